@@ -0,0 +1,70 @@
+package client
+
+import (
+	"context"
+	"github.com/jpdel518/go-ent/domain/model"
+	"net/http"
+	"net/url"
+	"strconv"
+)
+
+// numQuery asks for num of the items, or the default of the endpoint when num is zero
+func numQuery(num int) url.Values {
+	q := url.Values{}
+	if num > 0 {
+		q.Set("num", strconv.Itoa(num))
+	}
+	return q
+}
+
+func carPath(id int) string {
+	return "/cars/" + strconv.Itoa(id)
+}
+
+// ListCars fetches num of the cars, 10 by default
+func (c *Client) ListCars(ctx context.Context, num int) ([]*model.Car, error) {
+	var cars []*model.Car
+	if err := c.get(ctx, "/cars", numQuery(num), &cars); err != nil {
+		return nil, err
+	}
+	return cars, nil
+}
+
+func (c *Client) GetCar(ctx context.Context, id int) (*model.Car, error) {
+	car := &model.Car{}
+	if err := c.get(ctx, carPath(id), nil, car); err != nil {
+		return nil, err
+	}
+	return car, nil
+}
+
+func (c *Client) CreateCar(ctx context.Context, car *model.Car) (*model.Car, error) {
+	created := &model.Car{}
+	if err := c.json(ctx, &request{method: http.MethodPost, path: "/cars", withKey: true}, car, created); err != nil {
+		return nil, err
+	}
+	return created, nil
+}
+
+// UpdateCar replaces the fields of the car of car.ID
+func (c *Client) UpdateCar(ctx context.Context, car *model.Car) (*model.Car, error) {
+	updated := &model.Car{}
+	if err := c.json(ctx, &request{method: http.MethodPut, path: carPath(car.ID), idempotent: true}, car, updated); err != nil {
+		return nil, err
+	}
+	return updated, nil
+}
+
+func (c *Client) DeleteCar(ctx context.Context, id int) error {
+	return c.call(ctx, &request{method: http.MethodDelete, path: carPath(id), idempotent: true}, nil)
+}
+
+// TransferCar gives the car to the user, taking it from its owner, and returns the user
+func (c *Client) TransferCar(ctx context.Context, carID int, userID int) (*model.User, error) {
+	u := &model.User{}
+	r := &request{method: http.MethodPost, path: carPath(carID) + "/transfer", idempotent: true}
+	if err := c.json(ctx, r, &model.CarTransfer{UserID: userID}, u); err != nil {
+		return nil, err
+	}
+	return u, nil
+}
@@ -0,0 +1,363 @@
+// Package client is the Go client of the HTTP API.
+//
+//	c := client.New(client.Config{BaseURL: "http://localhost:8080", APIKey: key})
+//	u, err := c.GetUser(ctx, 1, model.CarDetailsAll)
+//
+// The methods unwrap the ApiRequestResponse envelope, and return the responses of other codes than 2000 as *Error.
+// The idempotent requests are retried with exponential backoff on the network errors and the 502, 503 and 504 of the proxies.
+// So are the creations, which carry an Idempotency-Key for the server to replay the first response to the retries.
+// The requests rejected by the rate limits are retried by any method after Retry-After.
+// GraphQL and /metrics have clients of their own and are not covered.
+package client
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	mathrand "math/rand"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	DefaultMaxRetries = 3
+	DefaultMinBackoff = 100 * time.Millisecond
+	DefaultMaxBackoff = 5 * time.Second
+
+	// idempotencyKeyHeader is handler.IdempotencyKeyHeader, which is not imported to keep the server out of the clients
+	idempotencyKeyHeader = "Idempotency-Key"
+	apiKeyHeader         = "X-API-Key"
+	userAgent            = "go-ent-client"
+
+	// codeOK is the code of the successful responses
+	codeOK = 2000
+)
+
+// Config of the client. Only BaseURL is required
+type Config struct {
+	// BaseURL is the scheme and the host of the API such as http://localhost:8080, with the path prefix of a proxy if any
+	BaseURL string
+	// APIKey is sent as X-API-Key, to which the tenant, the rate limits and the idempotency keys belong
+	APIKey string
+	// Token is sent as the bearer token, which is a JWT with the tenant claim
+	Token string
+	// HTTPClient sends the requests, http.DefaultClient by default
+	HTTPClient *http.Client
+	// MaxRetries is the number of the retries after the first attempt, DefaultMaxRetries when zero and none when negative
+	MaxRetries int
+	// MinBackoff is the wait before the first retry, doubled for each retry up to MaxBackoff.
+	// A Retry-After longer than MaxBackoff is not waited for and the error is returned instead
+	MinBackoff time.Duration
+	MaxBackoff time.Duration
+}
+
+// Client of the HTTP API, which is safe for concurrent use
+type Client struct {
+	baseURL    string
+	apiKey     string
+	token      string
+	httpClient *http.Client
+	maxRetries int
+	minBackoff time.Duration
+	maxBackoff time.Duration
+}
+
+// New creates a client with the defaults for the zero fields of the config
+func New(c Config) *Client {
+	client := &Client{
+		baseURL:    strings.TrimRight(c.BaseURL, "/"),
+		apiKey:     c.APIKey,
+		token:      c.Token,
+		httpClient: c.HTTPClient,
+		maxRetries: c.MaxRetries,
+		minBackoff: c.MinBackoff,
+		maxBackoff: c.MaxBackoff,
+	}
+	if client.httpClient == nil {
+		client.httpClient = http.DefaultClient
+	}
+	if client.maxRetries == 0 {
+		client.maxRetries = DefaultMaxRetries
+	} else if client.maxRetries < 0 {
+		client.maxRetries = 0
+	}
+	if client.minBackoff <= 0 {
+		client.minBackoff = DefaultMinBackoff
+	}
+	if client.maxBackoff <= 0 {
+		client.maxBackoff = DefaultMaxBackoff
+	}
+	if client.maxBackoff < client.minBackoff {
+		client.maxBackoff = client.minBackoff
+	}
+	return client
+}
+
+// Error is a response whose code is not 2000. Code is 0 for a response which is not an envelope, such as one of a proxy
+type Error struct {
+	StatusCode int
+	Code       int
+	Message    string
+	// RetryAfter is the wait asked for by the Retry-After header
+	RetryAfter time.Duration
+}
+
+func (e *Error) Error() string {
+	if e.Code == 0 {
+		return fmt.Sprintf("HTTP %d: %s", e.StatusCode, e.Message)
+	}
+	return fmt.Sprintf("error %d (HTTP %d): %s", e.Code, e.StatusCode, e.Message)
+}
+
+// Is matches the errors of the same code, as in errors.Is(err, client.ErrRateLimited)
+func (e *Error) Is(target error) bool {
+	t, ok := target.(*Error)
+	return ok && t.Code != 0 && t.Code == e.Code
+}
+
+// The errors of the codes which tell more than the failure of the endpoint
+var (
+	ErrNotReady                 = &Error{Code: 4700, Message: "not ready"}
+	ErrRateLimited              = &Error{Code: 4800, Message: "rate limit exceeded"}
+	ErrIdempotencyKeyMismatch   = &Error{Code: 4901, Message: "idempotency key reused for another request"}
+	ErrIdempotencyKeyInProgress = &Error{Code: 4902, Message: "idempotency key in progress"}
+)
+
+// jitter spreads the retries of the clients started together
+var (
+	jitter   = mathrand.New(mathrand.NewSource(time.Now().UnixNano()))
+	jitterMu sync.Mutex
+)
+
+// errNotReplayable stops the retries of a request whose body has been read and cannot be read again
+var errNotReplayable = errors.New("the body cannot be sent again")
+
+// envelope is handler.ApiRequestResponse with the data left for the callers
+type envelope struct {
+	Code int             `json:"code"`
+	Data json.RawMessage `json:"data"`
+}
+
+// request is sent by do once for each attempt
+type request struct {
+	method string
+	path   string
+	query  url.Values
+	header http.Header
+	// body makes the body of each attempt, nil for none
+	body        func() (io.Reader, error)
+	contentType string
+	// idempotent requests are retried on the network errors and the 502, 503 and 504 of the proxies
+	idempotent bool
+	// withKey sends an Idempotency-Key, the same one on the retries, which makes the request idempotent
+	withKey bool
+}
+
+func (c *Client) newRequest(ctx context.Context, r *request, key string) (*http.Request, error) {
+	target := c.baseURL + r.path
+	if len(r.query) > 0 {
+		target += "?" + r.query.Encode()
+	}
+	var body io.Reader
+	if r.body != nil {
+		b, err := r.body()
+		if err != nil {
+			return nil, err
+		}
+		// the transport closes the body, which is the file of the caller
+		body = io.NopCloser(b)
+	}
+	req, err := http.NewRequestWithContext(ctx, r.method, target, body)
+	if err != nil {
+		return nil, err
+	}
+	for k, v := range r.header {
+		req.Header[k] = v
+	}
+	req.Header.Set("User-Agent", userAgent)
+	if r.contentType != "" {
+		req.Header.Set("Content-Type", r.contentType)
+	}
+	if c.apiKey != "" {
+		req.Header.Set(apiKeyHeader, c.apiKey)
+	}
+	if c.token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
+	if key != "" {
+		req.Header.Set(idempotencyKeyHeader, key)
+	}
+	return req, nil
+}
+
+// do sends the request until it succeeds or is not to be retried. The caller closes the body of the response
+func (c *Client) do(ctx context.Context, r *request) (*http.Response, error) {
+	key := ""
+	if r.withKey {
+		var err error
+		if key, err = newIdempotencyKey(); err != nil {
+			return nil, err
+		}
+	}
+
+	var lastRes *http.Response
+	var lastErr error
+	for attempt := 0; ; attempt++ {
+		req, err := c.newRequest(ctx, r, key)
+		if errors.Is(err, errNotReplayable) && attempt > 0 {
+			return lastRes, lastErr
+		}
+		if err != nil {
+			closeBody(lastRes)
+			return nil, err
+		}
+		closeBody(lastRes)
+
+		res, err := c.httpClient.Do(req)
+		wait, retry := c.retryable(r, key, res, err)
+		if !retry || attempt >= c.maxRetries || ctx.Err() != nil {
+			return res, err
+		}
+		// the response is kept in case the body cannot be sent again
+		if res != nil {
+			lastRes, lastErr = bufferBody(res), nil
+		} else {
+			lastRes, lastErr = nil, err
+		}
+
+		if b := c.backoff(attempt); wait < b {
+			wait = b
+		}
+		t := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			t.Stop()
+			closeBody(lastRes)
+			return nil, ctx.Err()
+		case <-t.C:
+		}
+	}
+}
+
+// retryable tells whether the attempt is to be retried, and how long the server asked to wait for
+func (c *Client) retryable(r *request, key string, res *http.Response, err error) (time.Duration, bool) {
+	idempotent := r.idempotent || key != ""
+	if err != nil {
+		return 0, idempotent
+	}
+	wait := retryAfter(res)
+	if wait > c.maxBackoff {
+		return 0, false
+	}
+	switch res.StatusCode {
+	case http.StatusTooManyRequests:
+		// rejected before the handler
+		return wait, true
+	case http.StatusConflict:
+		// the first request of the key is still running
+		return wait, key != ""
+	case http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return wait, idempotent
+	}
+	return 0, false
+}
+
+// backoff is the wait before the retry after the attempt, doubled each time with a jitter of up to a half
+func (c *Client) backoff(attempt int) time.Duration {
+	d := c.maxBackoff
+	if attempt < 32 {
+		if b := c.minBackoff << uint(attempt); b > 0 && b < d {
+			d = b
+		}
+	}
+	jitterMu.Lock()
+	defer jitterMu.Unlock()
+	return d/2 + time.Duration(jitter.Int63n(int64(d/2)+1))
+}
+
+// retryAfter reads the delay seconds of Retry-After, which is all the server sends
+func retryAfter(res *http.Response) time.Duration {
+	s, err := strconv.Atoi(res.Header.Get("Retry-After"))
+	if err != nil || s < 0 {
+		return 0
+	}
+	return time.Duration(s) * time.Second
+}
+
+func newIdempotencyKey() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// bufferBody reads the small body of an error response and closes the connection
+func bufferBody(res *http.Response) *http.Response {
+	body, _ := io.ReadAll(io.LimitReader(res.Body, 1<<20))
+	_ = res.Body.Close()
+	res.Body = io.NopCloser(bytes.NewReader(body))
+	return res
+}
+
+func closeBody(res *http.Response) {
+	if res != nil {
+		_ = res.Body.Close()
+	}
+}
+
+// call sends the request and decodes the data of the envelope into out, unless out is nil
+func (c *Client) call(ctx context.Context, r *request, out interface{}) error {
+	res, err := c.do(ctx, r)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	return decode(res, out)
+}
+
+// decode unwraps the envelope of the response
+func decode(res *http.Response, out interface{}) error {
+	var e envelope
+	if err := json.NewDecoder(res.Body).Decode(&e); err != nil {
+		return &Error{StatusCode: res.StatusCode, Message: http.StatusText(res.StatusCode), RetryAfter: retryAfter(res)}
+	}
+	if e.Code != codeOK {
+		var message string
+		if err := json.Unmarshal(e.Data, &message); err != nil {
+			message = string(e.Data)
+		}
+		return &Error{StatusCode: res.StatusCode, Code: e.Code, Message: message, RetryAfter: retryAfter(res)}
+	}
+	if out == nil {
+		return nil
+	}
+	return json.Unmarshal(e.Data, out)
+}
+
+// get sends a GET, which is idempotent
+func (c *Client) get(ctx context.Context, path string, query url.Values, out interface{}) error {
+	return c.call(ctx, &request{method: http.MethodGet, path: path, query: query, idempotent: true}, out)
+}
+
+// json sends in as the JSON body. The body is in memory and can be sent again on the retries
+func (c *Client) json(ctx context.Context, r *request, in interface{}, out interface{}) error {
+	b, err := json.Marshal(in)
+	if err != nil {
+		return err
+	}
+	r.body = func() (io.Reader, error) {
+		return bytes.NewReader(b), nil
+	}
+	r.contentType = "application/json"
+	return c.call(ctx, r, out)
+}
@@ -0,0 +1,170 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"github.com/jpdel518/go-ent/domain/model"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// stub answers the attempts in turn with the statuses, and the last status to the rest
+type stub struct {
+	mu       sync.Mutex
+	statuses []int
+	bodies   []string
+	files    []string
+}
+
+func (s *stub) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if strings.HasPrefix(r.Header.Get("Content-Type"), "multipart/form-data") {
+		file, _, err := r.FormFile("avatar")
+		if err == nil {
+			b, _ := io.ReadAll(file)
+			s.files = append(s.files, string(b))
+		}
+	} else {
+		b, _ := io.ReadAll(r.Body)
+		s.bodies = append(s.bodies, string(b))
+	}
+	status := s.statuses[0]
+	if len(s.statuses) > 1 {
+		s.statuses = s.statuses[1:]
+	}
+	switch status {
+	case http.StatusOK:
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = io.WriteString(w, `{"code":2000,"data":{"id":1}}`)
+	case http.StatusInternalServerError:
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		_, _ = io.WriteString(w, `{"code":3000,"data":"database is down"}`)
+	default:
+		// a proxy
+		w.WriteHeader(status)
+		_, _ = io.WriteString(w, "<html>unavailable</html>")
+	}
+}
+
+func (s *stub) attempts() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.bodies) + len(s.files)
+}
+
+func newStub(t *testing.T, statuses ...int) (*stub, *Client) {
+	t.Helper()
+	s := &stub{statuses: statuses}
+	srv := httptest.NewServer(s)
+	t.Cleanup(srv.Close)
+	return s, New(Config{BaseURL: srv.URL, MinBackoff: time.Millisecond, MaxBackoff: 10 * time.Millisecond})
+}
+
+func TestRetryProxyErrors(t *testing.T) {
+	s, c := newStub(t, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusOK)
+	if _, err := c.GetCar(context.Background(), 1); err != nil {
+		t.Fatalf("GetCar: %v", err)
+	}
+	if s.attempts() != 3 {
+		t.Errorf("attempts = %d, want 3", s.attempts())
+	}
+
+	s, c = newStub(t, http.StatusServiceUnavailable)
+	_, err := c.GetCar(context.Background(), 1)
+	var apiErr *Error
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusServiceUnavailable || apiErr.Code != 0 {
+		t.Errorf("GetCar = %v, want HTTP 503", err)
+	}
+	if s.attempts() != 1+DefaultMaxRetries {
+		t.Errorf("attempts = %d, want %d", s.attempts(), 1+DefaultMaxRetries)
+	}
+}
+
+func TestNoRetryServerErrors(t *testing.T) {
+	s, c := newStub(t, http.StatusInternalServerError, http.StatusOK)
+	if _, err := c.GetCar(context.Background(), 1); !errors.Is(err, &Error{Code: 3000}) {
+		t.Errorf("GetCar = %v, want error 3000", err)
+	}
+	if s.attempts() != 1 {
+		t.Errorf("attempts = %d, want 1", s.attempts())
+	}
+}
+
+func TestRetryDisabled(t *testing.T) {
+	s := &stub{statuses: []int{http.StatusServiceUnavailable, http.StatusOK}}
+	srv := httptest.NewServer(s)
+	defer srv.Close()
+	c := New(Config{BaseURL: srv.URL, MaxRetries: -1})
+	if _, err := c.GetCar(context.Background(), 1); err == nil {
+		t.Error("GetCar succeeded without the retry")
+	}
+	if s.attempts() != 1 {
+		t.Errorf("attempts = %d, want 1", s.attempts())
+	}
+}
+
+func TestRetryReplaysBody(t *testing.T) {
+	s, c := newStub(t, http.StatusServiceUnavailable, http.StatusOK)
+	if _, err := c.ImportUsers(context.Background(), "csv", strings.NewReader("first_name\nTaro\n")); err != nil {
+		t.Fatalf("ImportUsers: %v", err)
+	}
+	if len(s.bodies) != 2 || s.bodies[0] != s.bodies[1] {
+		t.Errorf("bodies = %q, want the same body twice", s.bodies)
+	}
+
+	s, c = newStub(t, http.StatusServiceUnavailable, http.StatusOK)
+	u := &model.User{FirstName: "Taro", LastName: "Yamada", Email: "taro@example.com"}
+	if _, err := c.CreateUserWithAvatar(context.Background(), u, &File{Name: "taro.png", Body: strings.NewReader("png")}); err != nil {
+		t.Fatalf("CreateUserWithAvatar: %v", err)
+	}
+	if len(s.files) != 2 || s.files[0] != "png" || s.files[1] != "png" {
+		t.Errorf("files = %q, want the avatar twice", s.files)
+	}
+}
+
+func TestNoRetryUnreplayableBody(t *testing.T) {
+	s, c := newStub(t, http.StatusServiceUnavailable, http.StatusOK)
+	// a reader which cannot seek is read only once
+	body := io.MultiReader(strings.NewReader("first_name\nTaro\n"))
+	_, err := c.ImportUsers(context.Background(), "csv", body)
+	var apiErr *Error
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusServiceUnavailable {
+		t.Errorf("ImportUsers = %v, want HTTP 503", err)
+	}
+	if s.attempts() != 1 {
+		t.Errorf("attempts = %d, want 1", s.attempts())
+	}
+}
+
+func TestRetryCancelled(t *testing.T) {
+	s := &stub{statuses: []int{http.StatusServiceUnavailable}}
+	srv := httptest.NewServer(s)
+	defer srv.Close()
+	c := New(Config{BaseURL: srv.URL, MinBackoff: time.Hour, MaxBackoff: time.Hour})
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if _, err := c.GetCar(ctx, 1); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("GetCar = %v, want the deadline during the backoff", err)
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	c := New(Config{MinBackoff: 100 * time.Millisecond, MaxBackoff: time.Second})
+	for attempt, want := range []time.Duration{100 * time.Millisecond, 200 * time.Millisecond, 400 * time.Millisecond, 800 * time.Millisecond, time.Second, time.Second} {
+		for i := 0; i < 20; i++ {
+			if d := c.backoff(attempt); d < want/2 || d > want {
+				t.Fatalf("backoff(%d) = %v, want between %v and %v", attempt, d, want/2, want)
+			}
+		}
+	}
+	if d := c.backoff(100); d < 500*time.Millisecond || d > time.Second {
+		t.Errorf("backoff(100) = %v, want the maximum", d)
+	}
+}
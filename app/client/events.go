@@ -0,0 +1,126 @@
+package client
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"github.com/jpdel518/go-ent/domain/model"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// StreamEventsOptions filter the changes of the stream
+type StreamEventsOptions struct {
+	// Types are the entities such as model.ChangeEntityUser. Empty is all of them
+	Types []string
+	// IDs are the ids of the entities. Empty is all of them
+	IDs []int
+	// LastEventID resumes the stream after the change of the id
+	LastEventID uint64
+}
+
+// Event of the stream. Reset is true instead of Change when some changes were lost, and what the client shows has to be reloaded
+type Event struct {
+	Reset  bool
+	Change *model.Change
+}
+
+// EventStream reads the Server-Sent Events of /events/stream. It is not safe for concurrent use
+type EventStream struct {
+	body   io.ReadCloser
+	r      *bufio.Reader
+	lastID uint64
+}
+
+// StreamEvents subscribes to the changes of the users, the cars and the groups until the stream is closed or ctx is done
+func (c *Client) StreamEvents(ctx context.Context, opts StreamEventsOptions) (*EventStream, error) {
+	q := url.Values{}
+	if len(opts.Types) > 0 {
+		q.Set("type", strings.Join(opts.Types, ","))
+	}
+	if len(opts.IDs) > 0 {
+		ids := make([]string, 0, len(opts.IDs))
+		for _, id := range opts.IDs {
+			ids = append(ids, strconv.Itoa(id))
+		}
+		q.Set("id", strings.Join(ids, ","))
+	}
+	header := http.Header{}
+	if opts.LastEventID > 0 {
+		header.Set("Last-Event-ID", strconv.FormatUint(opts.LastEventID, 10))
+	}
+
+	res, err := c.do(ctx, &request{method: http.MethodGet, path: "/events/stream", query: q, header: header, idempotent: true})
+	if err != nil {
+		return nil, err
+	}
+	if res.StatusCode != http.StatusOK || !strings.HasPrefix(res.Header.Get("Content-Type"), "text/event-stream") {
+		defer res.Body.Close()
+		if err := decode(res, nil); err != nil {
+			return nil, err
+		}
+		return nil, &Error{StatusCode: res.StatusCode, Message: "not an event stream"}
+	}
+	return &EventStream{body: res.Body, r: bufio.NewReader(res.Body), lastID: opts.LastEventID}, nil
+}
+
+// Next blocks until the next event. It returns io.EOF when the server ends the stream,
+// which StreamEvents with LastEventID resumes. Closing the stream or cancelling the context of StreamEvents unblocks it
+func (s *EventStream) Next() (*Event, error) {
+	var id, event, data string
+	for {
+		line, err := s.r.ReadString('\n')
+		if err != nil {
+			// an event cut in the middle is dropped
+			return nil, err
+		}
+		line = strings.TrimRight(line, "\r\n")
+		if line == "" {
+			// the retry and the heartbeats have no event
+			if event == "" && data == "" {
+				continue
+			}
+			if event == "reset" {
+				return &Event{Reset: true}, nil
+			}
+			change := &model.Change{}
+			if err := json.Unmarshal([]byte(data), change); err != nil {
+				return nil, err
+			}
+			if id != "" {
+				if s.lastID, err = strconv.ParseUint(id, 10, 64); err != nil {
+					return nil, err
+				}
+			}
+			return &Event{Change: change}, nil
+		}
+		if strings.HasPrefix(line, ":") {
+			continue
+		}
+		name, value, _ := strings.Cut(line, ":")
+		value = strings.TrimPrefix(value, " ")
+		switch name {
+		case "id":
+			id = value
+		case "event":
+			event = value
+		case "data":
+			if data != "" {
+				data += "\n"
+			}
+			data += value
+		}
+	}
+}
+
+// LastEventID is the id of the last change read, to resume the stream with
+func (s *EventStream) LastEventID() uint64 {
+	return s.lastID
+}
+
+func (s *EventStream) Close() error {
+	return s.body.Close()
+}
@@ -0,0 +1,50 @@
+package client
+
+import (
+	"context"
+	"github.com/jpdel518/go-ent/domain/model"
+	"net/http"
+	"strconv"
+)
+
+func groupPath(id int) string {
+	return "/groups/" + strconv.Itoa(id)
+}
+
+// ListGroups fetches num of the groups, 10 by default
+func (c *Client) ListGroups(ctx context.Context, num int) ([]*model.Group, error) {
+	var groups []*model.Group
+	if err := c.get(ctx, "/groups", numQuery(num), &groups); err != nil {
+		return nil, err
+	}
+	return groups, nil
+}
+
+func (c *Client) GetGroup(ctx context.Context, id int) (*model.Group, error) {
+	g := &model.Group{}
+	if err := c.get(ctx, groupPath(id), nil, g); err != nil {
+		return nil, err
+	}
+	return g, nil
+}
+
+func (c *Client) CreateGroup(ctx context.Context, g *model.Group) (*model.Group, error) {
+	created := &model.Group{}
+	if err := c.json(ctx, &request{method: http.MethodPost, path: "/groups", withKey: true}, g, created); err != nil {
+		return nil, err
+	}
+	return created, nil
+}
+
+// UpdateGroup replaces the name and the members of the group of g.ID
+func (c *Client) UpdateGroup(ctx context.Context, g *model.Group) (*model.Group, error) {
+	updated := &model.Group{}
+	if err := c.json(ctx, &request{method: http.MethodPut, path: groupPath(g.ID), idempotent: true}, g, updated); err != nil {
+		return nil, err
+	}
+	return updated, nil
+}
+
+func (c *Client) DeleteGroup(ctx context.Context, id int) error {
+	return c.call(ctx, &request{method: http.MethodDelete, path: groupPath(id), idempotent: true}, nil)
+}
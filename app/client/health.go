@@ -0,0 +1,35 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/jpdel518/go-ent/domain/model"
+	"net/http"
+)
+
+// Live checks that the server is serving requests
+func (c *Client) Live(ctx context.Context) error {
+	return c.get(ctx, "/healthz", nil, nil)
+}
+
+// Ready checks the dependencies of the server. The report comes with ErrNotReady when a critical one fails
+func (c *Client) Ready(ctx context.Context) (*model.HealthReport, error) {
+	// the 503 of not ready is the answer rather than a failure to retry
+	res, err := c.do(ctx, &request{method: http.MethodGet, path: "/readyz"})
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+	report := &model.HealthReport{}
+	err = decode(res, report)
+	if e, ok := err.(*Error); ok && e.Code == ErrNotReady.Code {
+		if uerr := json.Unmarshal([]byte(e.Message), report); uerr != nil {
+			return nil, err
+		}
+		return report, err
+	}
+	if err != nil {
+		return nil, err
+	}
+	return report, nil
+}
@@ -0,0 +1,55 @@
+package client
+
+import (
+	"context"
+	"github.com/jpdel518/go-ent/domain/model"
+	"net/http"
+	"net/url"
+	"strconv"
+)
+
+func jobPath(id int) string {
+	return "/jobs/" + strconv.Itoa(id)
+}
+
+func (c *Client) GetJob(ctx context.Context, id int) (*model.Job, error) {
+	j := &model.Job{}
+	if err := c.get(ctx, jobPath(id), nil, j); err != nil {
+		return nil, err
+	}
+	return j, nil
+}
+
+// CancelJob stops the job, which keeps what it has done
+func (c *Client) CancelJob(ctx context.Context, id int) (*model.Job, error) {
+	j := &model.Job{}
+	if err := c.call(ctx, &request{method: http.MethodPost, path: jobPath(id) + "/cancel", idempotent: true}, j); err != nil {
+		return nil, err
+	}
+	return j, nil
+}
+
+// ImportUsersJob imports the users of the file in the background. The format is csv or ndjson, by the extension of the name when empty
+func (c *Client) ImportUsersJob(ctx context.Context, format string, file *File) (*model.Job, error) {
+	fields := []field{{name: "type", value: model.JobTypeUserImport}}
+	if format != "" {
+		fields = append(fields, field{name: "format", value: format})
+	}
+	body, contentType := multipartBody(append(fields, field{name: "file", file: file}))
+	j := &model.Job{}
+	r := &request{method: http.MethodPost, path: "/jobs", body: body, contentType: contentType, withKey: true}
+	if err := c.call(ctx, r, j); err != nil {
+		return nil, err
+	}
+	return j, nil
+}
+
+// ReprocessAvatars starts a job which makes the avatars of all the users again
+func (c *Client) ReprocessAvatars(ctx context.Context) (*model.Job, error) {
+	q := url.Values{"type": {model.JobTypeAvatarReprocess}}
+	j := &model.Job{}
+	if err := c.call(ctx, &request{method: http.MethodPost, path: "/jobs", query: q, withKey: true}, j); err != nil {
+		return nil, err
+	}
+	return j, nil
+}
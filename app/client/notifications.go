@@ -0,0 +1,32 @@
+package client
+
+import (
+	"context"
+	"github.com/jpdel518/go-ent/domain/model"
+	"net/http"
+	"net/url"
+	"strconv"
+)
+
+func notificationsQuery(userID int) url.Values {
+	return url.Values{"id": {strconv.Itoa(userID)}}
+}
+
+// GetNotificationPreferences fetches which mails the user is sent and in which language
+func (c *Client) GetNotificationPreferences(ctx context.Context, userID int) (*model.NotificationPreferences, error) {
+	p := &model.NotificationPreferences{}
+	if err := c.get(ctx, "/user/notifications", notificationsQuery(userID), p); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// UpdateNotificationPreferences replaces the preferences of the user of p.UserID
+func (c *Client) UpdateNotificationPreferences(ctx context.Context, p *model.NotificationPreferences) (*model.NotificationPreferences, error) {
+	updated := &model.NotificationPreferences{}
+	req := &request{method: http.MethodPut, path: "/user/notifications", query: notificationsQuery(p.UserID), idempotent: true}
+	if err := c.json(ctx, req, p, updated); err != nil {
+		return nil, err
+	}
+	return updated, nil
+}
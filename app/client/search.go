@@ -0,0 +1,25 @@
+package client
+
+import (
+	"context"
+	"github.com/jpdel518/go-ent/domain/model"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// Search ranks the users and the cars matching q.Text
+func (c *Client) Search(ctx context.Context, q *model.SearchQuery) (*model.SearchResult, error) {
+	query := url.Values{"q": {q.Text}}
+	if len(q.Types) > 0 {
+		query.Set("type", strings.Join(q.Types, ","))
+	}
+	if q.Limit > 0 {
+		query.Set("limit", strconv.Itoa(q.Limit))
+	}
+	result := &model.SearchResult{}
+	if err := c.get(ctx, "/search", query, result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
@@ -0,0 +1,113 @@
+package client
+
+import (
+	"io"
+	"mime/multipart"
+)
+
+// File is an upload. Its body is sent again on the retries when it implements io.Seeker such as *os.File,
+// and otherwise the request is not retried once sent
+type File struct {
+	// Name is the file name, whose extension tells the format of an import
+	Name string
+	Body io.Reader
+}
+
+// field is a field or, when file is set, a file of a multipart body
+type field struct {
+	name  string
+	value string
+	file  *File
+}
+
+// replay gives the reader for each attempt from where it was at first, or errNotReplayable when it cannot seek
+type replay struct {
+	r        io.Reader
+	offset   int64
+	started  bool
+	seekable bool
+}
+
+func (rp *replay) reader() (io.Reader, error) {
+	s, ok := rp.r.(io.Seeker)
+	if !rp.started {
+		rp.started = true
+		// a pipe is a Seeker which fails
+		if ok {
+			if offset, err := s.Seek(0, io.SeekCurrent); err == nil {
+				rp.offset, rp.seekable = offset, true
+			}
+		}
+		return rp.r, nil
+	}
+	if !rp.seekable {
+		return nil, errNotReplayable
+	}
+	if _, err := s.Seek(rp.offset, io.SeekStart); err != nil {
+		return nil, err
+	}
+	return rp.r, nil
+}
+
+// multipartBody streams the fields as the body of each attempt, reading the files while sending.
+// It returns the content type with the boundary shared by the attempts
+func multipartBody(fields []field) (func() (io.Reader, error), string) {
+	boundary := multipart.NewWriter(io.Discard).Boundary()
+	replays := make(map[int]*replay)
+	for i, f := range fields {
+		if f.file != nil {
+			replays[i] = &replay{r: f.file.Body}
+		}
+	}
+
+	var pr *io.PipeReader
+	var done chan struct{}
+	body := func() (io.Reader, error) {
+		// the writer of the previous attempt may still be reading the files
+		if pr != nil {
+			_ = pr.CloseWithError(errNotReplayable)
+			<-done
+		}
+		files := make(map[int]io.Reader)
+		for i, rp := range replays {
+			r, err := rp.reader()
+			if err != nil {
+				return nil, err
+			}
+			files[i] = r
+		}
+
+		var pw *io.PipeWriter
+		pr, pw = io.Pipe()
+		done = make(chan struct{})
+		mw := multipart.NewWriter(pw)
+		if err := mw.SetBoundary(boundary); err != nil {
+			return nil, err
+		}
+		go func(done chan struct{}) {
+			defer close(done)
+			_ = pw.CloseWithError(writeFields(mw, fields, files))
+		}(done)
+		return pr, nil
+	}
+	return body, "multipart/form-data; boundary=" + boundary
+}
+
+func writeFields(mw *multipart.Writer, fields []field, files map[int]io.Reader) error {
+	for i, f := range fields {
+		if f.file == nil {
+			if err := mw.WriteField(f.name, f.value); err != nil {
+				return err
+			}
+			continue
+		}
+		w, err := mw.CreateFormFile(f.name, f.file.Name)
+		if err != nil {
+			return err
+		}
+		if _, err := io.Copy(w, files[i]); err != nil {
+			return err
+		}
+	}
+	return mw.Close()
+}
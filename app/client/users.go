@@ -0,0 +1,241 @@
+package client
+
+import (
+	"context"
+	"github.com/jpdel518/go-ent/domain/model"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// DefaultPageSize is the number of the users fetched at once by UserIterator
+const DefaultPageSize = 100
+
+// ListUsersOptions select the users of a page
+type ListUsersOptions struct {
+	// Num is the number of the users, 10 by default
+	Num int
+	// After is the id of the last user of the previous page
+	After int
+	// Cars selects the details of the cars, all of them by default
+	Cars model.CarDetails
+}
+
+func (o ListUsersOptions) query() url.Values {
+	q := url.Values{}
+	if o.Num > 0 {
+		q.Set("num", strconv.Itoa(o.Num))
+	}
+	if o.After > 0 {
+		q.Set("after", strconv.Itoa(o.After))
+	}
+	if o.Cars != "" {
+		q.Set("cars", string(o.Cars))
+	}
+	return q
+}
+
+// ListUsers fetches a page of the users in the order of the ids
+func (c *Client) ListUsers(ctx context.Context, opts ListUsersOptions) ([]*model.User, error) {
+	var users []*model.User
+	if err := c.get(ctx, "/user/fetch", opts.query(), &users); err != nil {
+		return nil, err
+	}
+	return users, nil
+}
+
+// UserIterator walks through the users page by page.
+//
+//	it := c.Users(client.ListUsersOptions{})
+//	for it.Next(ctx) {
+//		u := it.User()
+//	}
+//	if err := it.Err(); err != nil {
+type UserIterator struct {
+	c    *Client
+	opts ListUsersOptions
+	page []*model.User
+	user *model.User
+	done bool
+	err  error
+}
+
+// Users iterates the users after opts.After, fetching opts.Num of them at once, DefaultPageSize by default
+func (c *Client) Users(opts ListUsersOptions) *UserIterator {
+	if opts.Num <= 0 {
+		opts.Num = DefaultPageSize
+	}
+	return &UserIterator{c: c, opts: opts}
+}
+
+// Next moves to the next user, fetching the next page when needed. It returns false at the end or on an error
+func (it *UserIterator) Next(ctx context.Context) bool {
+	if it.err != nil {
+		return false
+	}
+	if len(it.page) == 0 {
+		if it.done {
+			return false
+		}
+		it.page, it.err = it.c.ListUsers(ctx, it.opts)
+		if it.err != nil {
+			return false
+		}
+		// a short page is the last one
+		it.done = len(it.page) < it.opts.Num
+		if len(it.page) == 0 {
+			return false
+		}
+		it.opts.After = it.page[len(it.page)-1].ID
+	}
+	it.user, it.page = it.page[0], it.page[1:]
+	return true
+}
+
+// User is the current user
+func (it *UserIterator) User() *model.User {
+	return it.user
+}
+
+// Err is the error which stopped the iteration
+func (it *UserIterator) Err() error {
+	return it.err
+}
+
+func (c *Client) GetUser(ctx context.Context, id int, cars model.CarDetails) (*model.User, error) {
+	q := url.Values{}
+	if cars != "" {
+		q.Set("cars", string(cars))
+	}
+	u := &model.User{}
+	if err := c.get(ctx, "/user/get-by-id/"+strconv.Itoa(id), q, u); err != nil {
+		return nil, err
+	}
+	return u, nil
+}
+
+// CreateUser creates the user without an avatar
+func (c *Client) CreateUser(ctx context.Context, u *model.User) (*model.User, error) {
+	created := &model.User{}
+	if err := c.json(ctx, &request{method: http.MethodPost, path: "/user/create", withKey: true}, u, created); err != nil {
+		return nil, err
+	}
+	return created, nil
+}
+
+// CreateUserWithAvatar creates the user with the avatar as a multipart form
+func (c *Client) CreateUserWithAvatar(ctx context.Context, u *model.User, avatar *File) (*model.User, error) {
+	body, contentType := multipartBody(append(userFields(u, "car_ids"), field{name: "avatar", file: avatar}))
+	created := &model.User{}
+	r := &request{method: http.MethodPost, path: "/user/create", body: body, contentType: contentType, withKey: true}
+	if err := c.call(ctx, r, created); err != nil {
+		return nil, err
+	}
+	return created, nil
+}
+
+// UpdateUser replaces the fields and the cars of the user of u.ID, keeping the avatar
+func (c *Client) UpdateUser(ctx context.Context, u *model.User) (*model.User, error) {
+	updated := &model.User{}
+	if err := c.json(ctx, &request{method: http.MethodPut, path: "/user/update", idempotent: true}, u, updated); err != nil {
+		return nil, err
+	}
+	return updated, nil
+}
+
+// UpdateUserWithAvatar replaces the fields, the cars and the avatar of the user of u.ID
+func (c *Client) UpdateUserWithAvatar(ctx context.Context, u *model.User, avatar *File) (*model.User, error) {
+	fields := append([]field{{name: "id", value: strconv.Itoa(u.ID)}}, userFields(u, "cars")...)
+	body, contentType := multipartBody(append(fields, field{name: "avatar", file: avatar}))
+	updated := &model.User{}
+	r := &request{method: http.MethodPut, path: "/user/update", body: body, contentType: contentType, idempotent: true}
+	if err := c.call(ctx, r, updated); err != nil {
+		return nil, err
+	}
+	return updated, nil
+}
+
+// UploadAvatar replaces the avatar of the user, keeping the other fields.
+// The user is read first, so a change made in between is overwritten
+func (c *Client) UploadAvatar(ctx context.Context, id int, avatar *File) (*model.User, error) {
+	u, err := c.GetUser(ctx, id, model.CarDetailsNone)
+	if err != nil {
+		return nil, err
+	}
+	return c.UpdateUserWithAvatar(ctx, u, avatar)
+}
+
+// userFields are the fields of the multipart forms, where the cars are named carsField
+func userFields(u *model.User, carsField string) []field {
+	ids := make([]string, 0, len(u.CarIDs))
+	for _, id := range u.CarIDs {
+		ids = append(ids, strconv.Itoa(id))
+	}
+	return []field{
+		{name: "first_name", value: u.FirstName},
+		{name: "last_name", value: u.LastName},
+		{name: "email", value: u.Email},
+		{name: "age", value: strconv.Itoa(u.Age)},
+		{name: carsField, value: "[" + strings.Join(ids, ",") + "]"},
+	}
+}
+
+func (c *Client) DeleteUser(ctx context.Context, id int) error {
+	q := url.Values{"id": {strconv.Itoa(id)}}
+	return c.call(ctx, &request{method: http.MethodDelete, path: "/user/delete", query: q, idempotent: true}, nil)
+}
+
+// VerifyEmail verifies the e-mail of the token of the link which the user has been mailed
+func (c *Client) VerifyEmail(ctx context.Context, token string) (*model.User, error) {
+	u := &model.User{}
+	if err := c.get(ctx, "/user/verify", url.Values{"token": {token}}, u); err != nil {
+		return nil, err
+	}
+	return u, nil
+}
+
+// ResendVerification mails the link to verify the e-mail to the user again. It is not retried, as each call sends a mail
+func (c *Client) ResendVerification(ctx context.Context, id int) error {
+	q := url.Values{"id": {strconv.Itoa(id)}}
+	return c.call(ctx, &request{method: http.MethodPost, path: "/user/verify/resend", query: q}, nil)
+}
+
+// ImportUsers creates or updates the users of the file by their emails while the request lasts.
+// The format is csv or ndjson. Larger files should be imported by ImportUsersJob
+func (c *Client) ImportUsers(ctx context.Context, format string, r io.Reader) (*model.ImportReport, error) {
+	rp := &replay{r: r}
+	req := &request{
+		method:  http.MethodPost,
+		path:    "/users/import",
+		query:   url.Values{"format": {format}},
+		body:    rp.reader,
+		withKey: true,
+	}
+	report := &model.ImportReport{}
+	if err := c.call(ctx, req, report); err != nil {
+		return nil, err
+	}
+	return report, nil
+}
+
+// ExportUsers writes num of the users to w in the format, csv or ndjson. Zero num is all the users.
+// The error of a response cut in the middle is that of reading it
+func (c *Client) ExportUsers(ctx context.Context, format string, num int, w io.Writer) error {
+	q := url.Values{"format": {format}}
+	if num > 0 {
+		q.Set("num", strconv.Itoa(num))
+	}
+	res, err := c.do(ctx, &request{method: http.MethodGet, path: "/users/export", query: q, idempotent: true})
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	// the body is an envelope only on errors
+	if res.StatusCode != http.StatusOK {
+		return decode(res, nil)
+	}
+	_, err = io.Copy(w, res.Body)
+	return err
+}
@@ -0,0 +1,60 @@
+package client
+
+import (
+	"context"
+	"github.com/jpdel518/go-ent/domain/model"
+	"net/http"
+	"strconv"
+)
+
+func webhookPath(id int) string {
+	return "/webhooks/" + strconv.Itoa(id)
+}
+
+// ListWebhooks fetches num of the subscriptions, 10 by default
+func (c *Client) ListWebhooks(ctx context.Context, num int) ([]*model.WebhookSubscription, error) {
+	var subscriptions []*model.WebhookSubscription
+	if err := c.get(ctx, "/webhooks", numQuery(num), &subscriptions); err != nil {
+		return nil, err
+	}
+	return subscriptions, nil
+}
+
+func (c *Client) GetWebhook(ctx context.Context, id int) (*model.WebhookSubscription, error) {
+	s := &model.WebhookSubscription{}
+	if err := c.get(ctx, webhookPath(id), nil, s); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// CreateWebhook subscribes to the events, with a secret generated by the server when s.Secret is empty
+func (c *Client) CreateWebhook(ctx context.Context, s *model.WebhookSubscription) (*model.WebhookSubscription, error) {
+	created := &model.WebhookSubscription{}
+	if err := c.json(ctx, &request{method: http.MethodPost, path: "/webhooks", withKey: true}, s, created); err != nil {
+		return nil, err
+	}
+	return created, nil
+}
+
+// UpdateWebhook replaces the subscription of s.ID. An active one is enabled again
+func (c *Client) UpdateWebhook(ctx context.Context, s *model.WebhookSubscription) (*model.WebhookSubscription, error) {
+	updated := &model.WebhookSubscription{}
+	if err := c.json(ctx, &request{method: http.MethodPut, path: webhookPath(s.ID), idempotent: true}, s, updated); err != nil {
+		return nil, err
+	}
+	return updated, nil
+}
+
+func (c *Client) DeleteWebhook(ctx context.Context, id int) error {
+	return c.call(ctx, &request{method: http.MethodDelete, path: webhookPath(id), idempotent: true}, nil)
+}
+
+// ListWebhookDeliveries fetches num of the latest deliveries of the subscription, 50 by default
+func (c *Client) ListWebhookDeliveries(ctx context.Context, id int, num int) ([]*model.WebhookDelivery, error) {
+	var deliveries []*model.WebhookDelivery
+	if err := c.get(ctx, webhookPath(id)+"/deliveries", numQuery(num), &deliveries); err != nil {
+		return nil, err
+	}
+	return deliveries, nil
+}
@@ -0,0 +1,332 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"github.com/jpdel518/go-ent/client"
+	"github.com/jpdel518/go-ent/config"
+	"github.com/jpdel518/go-ent/domain/model"
+	"github.com/jpdel518/go-ent/presentation/handler"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// newClient serves the harness over HTTP and returns a client of it with short backoffs
+func newClient(t *testing.T, h http.Handler) *client.Client {
+	t.Helper()
+	srv := httptest.NewServer(h)
+	t.Cleanup(srv.Close)
+	return client.New(client.Config{BaseURL: srv.URL, MinBackoff: time.Millisecond, MaxBackoff: 2 * time.Second})
+}
+
+func userIDs(users []*model.User) []int {
+	ids := make([]int, 0, len(users))
+	for _, u := range users {
+		ids = append(ids, u.ID)
+	}
+	return ids
+}
+
+// TestClient goes through every endpoint of the client on the whole application
+func TestClient(t *testing.T) {
+	h := newHarness(t)
+	c := newClient(t, h.handler)
+	ctx := context.Background()
+
+	// cars
+	registeredAt := time.Date(2020, 4, 1, 9, 0, 0, 0, time.UTC)
+	leaf, err := c.CreateCar(ctx, &model.Car{Name: "Leaf", Model: "Nissan", RegisteredAt: registeredAt})
+	if err != nil {
+		t.Fatalf("CreateCar: %v", err)
+	}
+	if _, err := c.CreateCar(ctx, &model.Car{Name: "Prius", Model: "Toyota", RegisteredAt: registeredAt}); err != nil {
+		t.Fatalf("CreateCar: %v", err)
+	}
+	leaf.Model = "Nissan EV"
+	if _, err := c.UpdateCar(ctx, leaf); err != nil {
+		t.Fatalf("UpdateCar: %v", err)
+	}
+	if got, err := c.GetCar(ctx, leaf.ID); err != nil || got.Model != "Nissan EV" {
+		t.Fatalf("GetCar = %+v, %v", got, err)
+	}
+	if cars, err := c.ListCars(ctx, 10); err != nil || len(cars) != 2 {
+		t.Fatalf("ListCars = %d cars, %v", len(cars), err)
+	}
+
+	// users
+	taro, err := c.CreateUser(ctx, &model.User{FirstName: "Taro", LastName: "Yamada", Email: "taro@example.com", Age: 20, CarIDs: []int{leaf.ID}})
+	if err != nil {
+		t.Fatalf("CreateUser: %v", err)
+	}
+	hanako, err := c.CreateUserWithAvatar(ctx, &model.User{FirstName: "Hanako", LastName: "Sato", Email: "hanako@example.com", Age: 30},
+		&client.File{Name: "hanako.png", Body: strings.NewReader("png")})
+	if err != nil {
+		t.Fatalf("CreateUserWithAvatar: %v", err)
+	}
+	taro.Age = 21
+	if _, err := c.UpdateUser(ctx, taro); err != nil {
+		t.Fatalf("UpdateUser: %v", err)
+	}
+	if _, err := c.UploadAvatar(ctx, taro.ID, &client.File{Name: "taro.png", Body: strings.NewReader("png")}); err != nil {
+		t.Fatalf("UploadAvatar: %v", err)
+	}
+	got, err := c.GetUser(ctx, taro.ID, model.CarDetailsAll)
+	if err != nil {
+		t.Fatalf("GetUser: %v", err)
+	}
+	if got.Age != 21 || got.Avatar == "" || len(got.Cars) != 1 || got.Cars[0].Model != "Nissan EV" {
+		t.Errorf("GetUser = %+v, want the update, the avatar and the car", got)
+	}
+
+	report, err := c.ImportUsers(ctx, "ndjson", strings.NewReader(
+		`{"first_name":"Jiro","last_name":"Suzuki","email":"jiro@example.com"}`+"\n"+
+			`{"first_name":"Saburo","last_name":"Ito","email":"saburo@example.com"}`+"\n"+
+			`{"first_name":"Shiro","last_name":"Kato","email":"shiro@example.com"}`+"\n"))
+	if err != nil || report.Created != 3 {
+		t.Fatalf("ImportUsers = %+v, %v", report, err)
+	}
+	// the pages of two users end with a short one
+	it := c.Users(client.ListUsersOptions{Num: 2})
+	var users []*model.User
+	for it.Next(ctx) {
+		users = append(users, it.User())
+	}
+	if err := it.Err(); err != nil {
+		t.Fatalf("Users: %v", err)
+	}
+	if ids := userIDs(users); !reflect.DeepEqual(ids, []int{1, 2, 3, 4, 5}) {
+		t.Errorf("Users = %v, want 1 to 5", ids)
+	}
+	if page, err := c.ListUsers(ctx, client.ListUsersOptions{Num: 2, After: 2}); err != nil || !reflect.DeepEqual(userIDs(page), []int{3, 4}) {
+		t.Errorf("ListUsers after 2 = %v, %v", userIDs(page), err)
+	}
+	var export bytes.Buffer
+	if err := c.ExportUsers(ctx, "csv", 0, &export); err != nil || !strings.Contains(export.String(), "shiro@example.com") {
+		t.Errorf("ExportUsers = %q, %v", export.String(), err)
+	}
+
+	transferred, err := c.TransferCar(ctx, leaf.ID, hanako.ID)
+	if err != nil || !reflect.DeepEqual(transferred.CarIDs, []int{leaf.ID}) {
+		t.Errorf("TransferCar = %+v, %v", transferred, err)
+	}
+
+	// the link of the mail verifies the e-mail
+	if err := c.ResendVerification(ctx, hanako.ID); err != nil {
+		t.Fatalf("ResendVerification: %v", err)
+	}
+	link, err := url.ParseRequestURI(h.verificationLink(t, "hanako@example.com"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if verified, err := c.VerifyEmail(ctx, link.Query().Get("token")); err != nil || verified.VerifiedAt == nil {
+		t.Errorf("VerifyEmail = %+v, %v", verified, err)
+	}
+	var verifiedErr *client.Error
+	if err := c.ResendVerification(ctx, hanako.ID); !errors.As(err, &verifiedErr) || verifiedErr.Code != 6312 {
+		t.Errorf("ResendVerification of a verified user = %v, want code 6312", err)
+	}
+
+	// groups
+	group, err := c.CreateGroup(ctx, &model.Group{Name: "admins", UserIDs: []int{taro.ID}})
+	if err != nil {
+		t.Fatalf("CreateGroup: %v", err)
+	}
+	group.UserIDs = []int{taro.ID, hanako.ID}
+	if _, err := c.UpdateGroup(ctx, group); err != nil {
+		t.Fatalf("UpdateGroup: %v", err)
+	}
+	if got, err := c.GetGroup(ctx, group.ID); err != nil || len(got.UserIDs) != 2 {
+		t.Errorf("GetGroup = %+v, %v", got, err)
+	}
+	if groups, err := c.ListGroups(ctx, 0); err != nil || len(groups) != 1 {
+		t.Errorf("ListGroups = %d groups, %v", len(groups), err)
+	}
+	if err := c.DeleteGroup(ctx, group.ID); err != nil {
+		t.Errorf("DeleteGroup: %v", err)
+	}
+
+	// search
+	result, err := c.Search(ctx, &model.SearchQuery{Text: "hana", Types: []string{model.SearchTypeUser}, Limit: 5})
+	if err != nil || result.Total != 1 || result.Hits[0].ID != hanako.ID {
+		t.Errorf("Search = %+v, %v", result, err)
+	}
+
+	// jobs
+	job, err := c.ImportUsersJob(ctx, "", &client.File{Name: "users.csv", Body: strings.NewReader("first_name,last_name,email\nGoro,Abe,goro@example.com\n")})
+	if err != nil || job.Type != model.JobTypeUserImport {
+		t.Fatalf("ImportUsersJob = %+v, %v", job, err)
+	}
+	if _, err := c.ReprocessAvatars(ctx); err != nil {
+		t.Errorf("ReprocessAvatars: %v", err)
+	}
+	if got, err := c.GetJob(ctx, job.ID); err != nil || got.ID != job.ID {
+		t.Errorf("GetJob = %+v, %v", got, err)
+	}
+	if got, err := c.CancelJob(ctx, job.ID); err != nil || got.Status != model.JobStatusCanceled {
+		t.Errorf("CancelJob = %+v, %v", got, err)
+	}
+
+	// webhooks
+	subscription, err := c.CreateWebhook(ctx, &model.WebhookSubscription{URL: "https://example.com/hook", EventTypes: []string{"user.created"}})
+	if err != nil {
+		t.Fatalf("CreateWebhook: %v", err)
+	}
+	subscription.URL = "https://example.com/hook2"
+	subscription.Active = true
+	if _, err := c.UpdateWebhook(ctx, subscription); err != nil {
+		t.Errorf("UpdateWebhook: %v", err)
+	}
+	if got, err := c.GetWebhook(ctx, subscription.ID); err != nil || got.URL != "https://example.com/hook2" {
+		t.Errorf("GetWebhook = %+v, %v", got, err)
+	}
+	if subscriptions, err := c.ListWebhooks(ctx, 10); err != nil || len(subscriptions) != 1 {
+		t.Errorf("ListWebhooks = %d subscriptions, %v", len(subscriptions), err)
+	}
+	if _, err := c.ListWebhookDeliveries(ctx, subscription.ID, 0); err != nil {
+		t.Errorf("ListWebhookDeliveries: %v", err)
+	}
+	if err := c.DeleteWebhook(ctx, subscription.ID); err != nil {
+		t.Errorf("DeleteWebhook: %v", err)
+	}
+
+	// health
+	if err := c.Live(ctx); err != nil {
+		t.Errorf("Live: %v", err)
+	}
+	if report, err := c.Ready(ctx); err != nil || report.Status != "ok" {
+		t.Errorf("Ready = %+v, %v", report, err)
+	}
+
+	// deletions, and the errors of what is gone
+	if err := c.DeleteUser(ctx, taro.ID); err != nil {
+		t.Errorf("DeleteUser: %v", err)
+	}
+	if err := c.DeleteCar(ctx, leaf.ID); err != nil {
+		t.Errorf("DeleteCar: %v", err)
+	}
+	_, err = c.GetUser(ctx, taro.ID, model.CarDetailsNone)
+	var apiErr *client.Error
+	if !errors.As(err, &apiErr) || apiErr.Code != 3100 || apiErr.StatusCode != http.StatusInternalServerError {
+		t.Errorf("GetUser of a deleted user = %v, want error 3100", err)
+	}
+	if err := c.DeleteCar(ctx, leaf.ID); !errors.As(err, &apiErr) || apiErr.Code != 5500 {
+		t.Errorf("DeleteCar of a deleted car = %v, want error 5500", err)
+	}
+}
+
+func TestClientEvents(t *testing.T) {
+	h := newHarness(t)
+	c := newClient(t, h.handler)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	stream, err := c.StreamEvents(ctx, client.StreamEventsOptions{Types: []string{model.ChangeEntityUser}})
+	if err != nil {
+		t.Fatalf("StreamEvents: %v", err)
+	}
+	defer stream.Close()
+	if _, err := c.CreateUser(ctx, &model.User{FirstName: "Taro", LastName: "Yamada", Email: "taro@example.com"}); err != nil {
+		t.Fatalf("CreateUser: %v", err)
+	}
+	event, err := stream.Next()
+	if err != nil {
+		t.Fatalf("Next: %v", err)
+	}
+	if event.Reset || event.Change.Entity != model.ChangeEntityUser || event.Change.Op != model.ChangeOpCreate || event.Change.EntityID != 1 {
+		t.Errorf("Next = %+v, want the creation of user 1", event)
+	}
+	if stream.LastEventID() != event.Change.ID {
+		t.Errorf("LastEventID = %d, want %d", stream.LastEventID(), event.Change.ID)
+	}
+
+	if _, err := c.StreamEvents(ctx, client.StreamEventsOptions{Types: []string{"truck"}}); !errors.Is(err, &client.Error{Code: 4600}) {
+		t.Errorf("StreamEvents of an unknown type = %v, want error 4600", err)
+	}
+}
+
+// TestClientRetries drops the first response of a creation, whose retry gets it replayed by the server
+func TestClientRetries(t *testing.T) {
+	h := newHarness(t)
+	var mu sync.Mutex
+	var keys []string
+	c := newClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		keys = append(keys, r.Header.Get(handler.IdempotencyKeyHeader))
+		first := len(keys) == 1
+		mu.Unlock()
+		if !first {
+			h.handler.ServeHTTP(w, r)
+			return
+		}
+		h.handler.ServeHTTP(httptest.NewRecorder(), r)
+		conn, _, err := w.(http.Hijacker).Hijack()
+		if err != nil {
+			t.Errorf("Hijack: %v", err)
+			return
+		}
+		_ = conn.Close()
+	}))
+	ctx := context.Background()
+
+	u, err := c.CreateUser(ctx, &model.User{FirstName: "Taro", LastName: "Yamada", Email: "taro@example.com"})
+	if err != nil {
+		t.Fatalf("CreateUser: %v", err)
+	}
+	if len(keys) != 2 || keys[0] == "" || keys[0] != keys[1] {
+		t.Errorf("Idempotency-Key of the attempts = %q, want the same key twice", keys)
+	}
+	if users, err := c.ListUsers(ctx, client.ListUsersOptions{}); err != nil || len(users) != 1 || users[0].ID != u.ID {
+		t.Errorf("ListUsers = %v, %v, want only user %d", userIDs(users), err, u.ID)
+	}
+}
+
+func TestClientRateLimit(t *testing.T) {
+	h := newHarness(t, func(cfg *config.Config) {
+		cfg.RateLimit.Routes = map[string]model.RateLimit{"/search": {Limit: 1, Period: time.Second}}
+	})
+	srv := httptest.NewServer(h.handler)
+	defer srv.Close()
+	ctx := context.Background()
+	q := &model.SearchQuery{Text: "taro"}
+
+	// the wait asked for is longer than the backoff allows
+	impatient := client.New(client.Config{BaseURL: srv.URL, MinBackoff: time.Millisecond, MaxBackoff: 10 * time.Millisecond})
+	if _, err := impatient.Search(ctx, q); err != nil {
+		t.Fatalf("Search: %v", err)
+	}
+	_, err := impatient.Search(ctx, q)
+	var apiErr *client.Error
+	if !errors.Is(err, client.ErrRateLimited) || !errors.As(err, &apiErr) || apiErr.RetryAfter != time.Second {
+		t.Fatalf("Search over the limit = %v, want ErrRateLimited after a second", err)
+	}
+
+	patient := client.New(client.Config{BaseURL: srv.URL, MinBackoff: time.Millisecond, MaxBackoff: 2 * time.Second})
+	if _, err := patient.Search(ctx, q); err != nil {
+		t.Errorf("Search waiting for Retry-After: %v", err)
+	}
+}
+
+func TestClientDatabaseDown(t *testing.T) {
+	h := newHarness(t)
+	c := newClient(t, h.handler)
+	if err := h.components.client.Close(); err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+
+	report, err := c.Ready(ctx)
+	if !errors.Is(err, client.ErrNotReady) || report == nil || report.Ready() {
+		t.Errorf("Ready = %+v, %v, want the report with ErrNotReady", report, err)
+	}
+	// the errors of the server are not retried, and the tenant of the request is not found without the database
+	if _, err := c.ListUsers(ctx, client.ListUsersOptions{}); !errors.Is(err, &client.Error{Code: 6204}) {
+		t.Errorf("ListUsers = %v, want error 6204", err)
+	}
+}
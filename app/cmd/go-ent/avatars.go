@@ -0,0 +1,58 @@
+package main
+
+import (
+	"context"
+	"github.com/jpdel518/go-ent/client"
+	"github.com/jpdel518/go-ent/domain/model"
+	"os"
+	"path/filepath"
+	"strconv"
+)
+
+var avatarCommands = map[string]subcommand{
+	"get":    {usage: "<user id>", run: getAvatar},
+	"upload": {usage: "<user id> <file>", run: uploadAvatar},
+}
+
+func printAvatar(c *cli, u *model.User) error {
+	t := table{header: []string{"USER", "AVATAR"}, rows: [][]string{{strconv.Itoa(u.ID), u.Avatar}}}
+	return c.print(map[string]interface{}{"user_id": u.ID, "avatar": u.Avatar}, t)
+}
+
+func getAvatar(ctx context.Context, c *cli, args []string) error {
+	args, err := parse(c.flags("avatars get", "<user id>"), args, 1)
+	if err != nil {
+		return err
+	}
+	id, err := parseID("user", args[0])
+	if err != nil {
+		return err
+	}
+	u, err := c.api.GetUser(ctx, id, model.CarDetailsNone)
+	if err != nil {
+		return err
+	}
+	return printAvatar(c, u)
+}
+
+// uploadAvatar replaces the avatar of the user, keeping the other fields
+func uploadAvatar(ctx context.Context, c *cli, args []string) error {
+	args, err := parse(c.flags("avatars upload", "<user id> <file>"), args, 2)
+	if err != nil {
+		return err
+	}
+	id, err := parseID("user", args[0])
+	if err != nil {
+		return err
+	}
+	avatar, err := os.Open(args[1])
+	if err != nil {
+		return err
+	}
+	defer avatar.Close()
+	u, err := c.api.UploadAvatar(ctx, id, &client.File{Name: filepath.Base(args[1]), Body: avatar})
+	if err != nil {
+		return err
+	}
+	return printAvatar(c, u)
+}
@@ -0,0 +1,163 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"github.com/jpdel518/go-ent/domain/model"
+	"strconv"
+	"time"
+)
+
+var carCommands = map[string]subcommand{
+	"list":     {usage: "", run: listCars},
+	"get":      {usage: "<id>", run: getCar},
+	"create":   {usage: "", run: createCar},
+	"update":   {usage: "<id>", run: updateCar},
+	"delete":   {usage: "<id>", run: deleteCar},
+	"transfer": {usage: "<id> <user id>", run: transferCar},
+}
+
+func printCars(c *cli, v interface{}, cars ...*model.Car) error {
+	t := table{header: []string{"ID", "NAME", "MODEL", "REGISTERED AT"}}
+	for _, car := range cars {
+		t.rows = append(t.rows, []string{strconv.Itoa(car.ID), car.Name, car.Model, formatTime(&car.RegisteredAt)})
+	}
+	return c.print(v, t)
+}
+
+// parseDate reads RFC 3339 or a date such as 2020-04-01
+func parseDate(s string) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t, nil
+	}
+	t, err := time.ParseInLocation("2006-01-02", s, time.Local)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%q is neither RFC 3339 nor a date such as 2020-04-01", s)
+	}
+	return t, nil
+}
+
+func listCars(ctx context.Context, c *cli, args []string) error {
+	fs := c.flags("cars list", "")
+	num := fs.Int("num", 10, "the number of the cars")
+	if _, err := parse(fs, args, 0); err != nil {
+		return err
+	}
+	cars, err := c.api.ListCars(ctx, *num)
+	if err != nil {
+		return err
+	}
+	return printCars(c, cars, cars...)
+}
+
+func getCar(ctx context.Context, c *cli, args []string) error {
+	args, err := parse(c.flags("cars get", "<id>"), args, 1)
+	if err != nil {
+		return err
+	}
+	id, err := parseID("car", args[0])
+	if err != nil {
+		return err
+	}
+	car, err := c.api.GetCar(ctx, id)
+	if err != nil {
+		return err
+	}
+	return printCars(c, car, car)
+}
+
+func createCar(ctx context.Context, c *cli, args []string) error {
+	fs := c.flags("cars create", "")
+	name := fs.String("name", "", "the name")
+	carModel := fs.String("model", "", "the model")
+	registeredAt := fs.String("registered-at", "", "the registration date, today by default")
+	if _, err := parse(fs, args, 0); err != nil {
+		return err
+	}
+	car := &model.Car{Name: *name, Model: *carModel, RegisteredAt: time.Now()}
+	if *registeredAt != "" {
+		t, err := parseDate(*registeredAt)
+		if err != nil {
+			return err
+		}
+		car.RegisteredAt = t
+	}
+	created, err := c.api.CreateCar(ctx, car)
+	if err != nil {
+		return err
+	}
+	return printCars(c, created, created)
+}
+
+// updateCar changes the fields given on the current car, as the API replaces the whole car
+func updateCar(ctx context.Context, c *cli, args []string) error {
+	fs := c.flags("cars update", "<id>")
+	name := fs.String("name", "", "the name")
+	carModel := fs.String("model", "", "the model")
+	registeredAt := fs.String("registered-at", "", "the registration date")
+	args, err := parse(fs, args, 1)
+	if err != nil {
+		return err
+	}
+	id, err := parseID("car", args[0])
+	if err != nil {
+		return err
+	}
+	car, err := c.api.GetCar(ctx, id)
+	if err != nil {
+		return err
+	}
+	if visited(fs, "name") {
+		car.Name = *name
+	}
+	if visited(fs, "model") {
+		car.Model = *carModel
+	}
+	if visited(fs, "registered-at") {
+		if car.RegisteredAt, err = parseDate(*registeredAt); err != nil {
+			return err
+		}
+	}
+	updated, err := c.api.UpdateCar(ctx, car)
+	if err != nil {
+		return err
+	}
+	return printCars(c, updated, updated)
+}
+
+func deleteCar(ctx context.Context, c *cli, args []string) error {
+	args, err := parse(c.flags("cars delete", "<id>"), args, 1)
+	if err != nil {
+		return err
+	}
+	id, err := parseID("car", args[0])
+	if err != nil {
+		return err
+	}
+	if err := c.api.DeleteCar(ctx, id); err != nil {
+		return err
+	}
+	fmt.Fprintf(c.stderr, "car %d deleted\n", id)
+	return nil
+}
+
+// transferCar gives the car to the user and prints the user
+func transferCar(ctx context.Context, c *cli, args []string) error {
+	args, err := parse(c.flags("cars transfer", "<id> <user id>"), args, 2)
+	if err != nil {
+		return err
+	}
+	id, err := parseID("car", args[0])
+	if err != nil {
+		return err
+	}
+	userID, err := parseID("user", args[1])
+	if err != nil {
+		return err
+	}
+	u, err := c.api.TransferCar(ctx, id, userID)
+	if err != nil {
+		return err
+	}
+	return printUsers(c, u, u)
+}
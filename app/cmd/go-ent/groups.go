@@ -0,0 +1,119 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"github.com/jpdel518/go-ent/domain/model"
+	"strconv"
+)
+
+var groupCommands = map[string]subcommand{
+	"list":   {usage: "", run: listGroups},
+	"get":    {usage: "<id>", run: getGroup},
+	"create": {usage: "", run: createGroup},
+	"update": {usage: "<id>", run: updateGroup},
+	"delete": {usage: "<id>", run: deleteGroup},
+}
+
+func printGroups(c *cli, v interface{}, groups ...*model.Group) error {
+	t := table{header: []string{"ID", "NAME", "USERS"}}
+	for _, g := range groups {
+		t.rows = append(t.rows, []string{strconv.Itoa(g.ID), g.Name, ids(g.UserIDs)})
+	}
+	return c.print(v, t)
+}
+
+func listGroups(ctx context.Context, c *cli, args []string) error {
+	fs := c.flags("groups list", "")
+	num := fs.Int("num", 10, "the number of the groups")
+	if _, err := parse(fs, args, 0); err != nil {
+		return err
+	}
+	groups, err := c.api.ListGroups(ctx, *num)
+	if err != nil {
+		return err
+	}
+	return printGroups(c, groups, groups...)
+}
+
+func getGroup(ctx context.Context, c *cli, args []string) error {
+	args, err := parse(c.flags("groups get", "<id>"), args, 1)
+	if err != nil {
+		return err
+	}
+	id, err := parseID("group", args[0])
+	if err != nil {
+		return err
+	}
+	g, err := c.api.GetGroup(ctx, id)
+	if err != nil {
+		return err
+	}
+	return printGroups(c, g, g)
+}
+
+func createGroup(ctx context.Context, c *cli, args []string) error {
+	fs := c.flags("groups create", "")
+	name := fs.String("name", "", "the name of letters and underscores")
+	users := fs.String("users", "", "the ids of the users joined by commas")
+	if _, err := parse(fs, args, 0); err != nil {
+		return err
+	}
+	userIDs, err := parseIDs(*users)
+	if err != nil {
+		return err
+	}
+	created, err := c.api.CreateGroup(ctx, &model.Group{Name: *name, UserIDs: userIDs})
+	if err != nil {
+		return err
+	}
+	return printGroups(c, created, created)
+}
+
+// updateGroup changes the fields given on the current group, as the API replaces the whole group
+func updateGroup(ctx context.Context, c *cli, args []string) error {
+	fs := c.flags("groups update", "<id>")
+	name := fs.String("name", "", "the name of letters and underscores")
+	users := fs.String("users", "", "the ids of the users joined by commas, which replace the members")
+	args, err := parse(fs, args, 1)
+	if err != nil {
+		return err
+	}
+	id, err := parseID("group", args[0])
+	if err != nil {
+		return err
+	}
+	g, err := c.api.GetGroup(ctx, id)
+	if err != nil {
+		return err
+	}
+	if visited(fs, "name") {
+		g.Name = *name
+	}
+	if visited(fs, "users") {
+		if g.UserIDs, err = parseIDs(*users); err != nil {
+			return err
+		}
+	}
+	updated, err := c.api.UpdateGroup(ctx, g)
+	if err != nil {
+		return err
+	}
+	return printGroups(c, updated, updated)
+}
+
+func deleteGroup(ctx context.Context, c *cli, args []string) error {
+	args, err := parse(c.flags("groups delete", "<id>"), args, 1)
+	if err != nil {
+		return err
+	}
+	id, err := parseID("group", args[0])
+	if err != nil {
+		return err
+	}
+	if err := c.api.DeleteGroup(ctx, id); err != nil {
+		return err
+	}
+	fmt.Fprintf(c.stderr, "group %d deleted\n", id)
+	return nil
+}
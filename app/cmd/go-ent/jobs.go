@@ -0,0 +1,91 @@
+package main
+
+import (
+	"context"
+	"github.com/jpdel518/go-ent/client"
+	"github.com/jpdel518/go-ent/domain/model"
+	"os"
+	"path/filepath"
+	"strconv"
+)
+
+var jobCommands = map[string]subcommand{
+	"get":               {usage: "<id>", run: getJob},
+	"cancel":            {usage: "<id>", run: cancelJob},
+	"import":            {usage: "<file>", run: importJob},
+	"reprocess-avatars": {usage: "", run: reprocessAvatarsJob},
+}
+
+func printJob(c *cli, j *model.Job) error {
+	t := table{
+		header: []string{"ID", "TYPE", "STATUS", "PROGRESS", "CREATED AT", "FINISHED AT", "ERROR"},
+		rows: [][]string{{
+			strconv.Itoa(j.ID), j.Type, string(j.Status), strconv.Itoa(j.Progress) + "%",
+			formatTime(&j.CreatedAt), formatTime(j.FinishedAt), j.Error,
+		}},
+	}
+	return c.print(j, t)
+}
+
+func getJob(ctx context.Context, c *cli, args []string) error {
+	args, err := parse(c.flags("jobs get", "<id>"), args, 1)
+	if err != nil {
+		return err
+	}
+	id, err := parseID("job", args[0])
+	if err != nil {
+		return err
+	}
+	j, err := c.api.GetJob(ctx, id)
+	if err != nil {
+		return err
+	}
+	return printJob(c, j)
+}
+
+func cancelJob(ctx context.Context, c *cli, args []string) error {
+	args, err := parse(c.flags("jobs cancel", "<id>"), args, 1)
+	if err != nil {
+		return err
+	}
+	id, err := parseID("job", args[0])
+	if err != nil {
+		return err
+	}
+	j, err := c.api.CancelJob(ctx, id)
+	if err != nil {
+		return err
+	}
+	return printJob(c, j)
+}
+
+// importJob imports the users in the background, which suits the files too large for "users import"
+func importJob(ctx context.Context, c *cli, args []string) error {
+	fs := c.flags("jobs import", "<file>")
+	format := fs.String("format", "", "csv or ndjson, by the file extension by default")
+	args, err := parse(fs, args, 1)
+	if err != nil {
+		return err
+	}
+	f, err := os.Open(args[0])
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	j, err := c.api.ImportUsersJob(ctx, *format, &client.File{Name: filepath.Base(args[0]), Body: f})
+	if err != nil {
+		return err
+	}
+	return printJob(c, j)
+}
+
+func reprocessAvatarsJob(ctx context.Context, c *cli, args []string) error {
+	if _, err := parse(c.flags("jobs reprocess-avatars", ""), args, 0); err != nil {
+		return err
+	}
+	j, err := c.api.ReprocessAvatars(ctx)
+	if err != nil {
+		return err
+	}
+	return printJob(c, j)
+}
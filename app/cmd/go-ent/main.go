@@ -0,0 +1,214 @@
+// Command go-ent is the admin client of the HTTP API.
+//
+//	go-ent [-profile name] [-url url] [-api-key key] [-token token] [-output table|json|yaml] <command> <subcommand> [flags] [args]
+//
+// The commands are users, cars, groups, avatars, jobs and profile. "go-ent <command>" lists the subcommands
+// and "go-ent <command> <subcommand> -h" the flags of one.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"github.com/jpdel518/go-ent/client"
+	"io"
+	"net/http"
+	"os"
+	"os/signal"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+)
+
+// defaultURL is the API of the development environment
+const defaultURL = "http://localhost:8080"
+
+// subcommand of a command such as "users list"
+type subcommand struct {
+	// usage is the arguments after the flags
+	usage string
+	run   func(ctx context.Context, c *cli, args []string) error
+}
+
+var commands = map[string]map[string]subcommand{
+	"users":   userCommands,
+	"cars":    carCommands,
+	"groups":  groupCommands,
+	"avatars": avatarCommands,
+	"jobs":    jobCommands,
+	"profile": profileCommands,
+}
+
+// cli is the state shared by the subcommands
+type cli struct {
+	api      *client.Client
+	output   string
+	stdout   io.Writer
+	stderr   io.Writer
+	profiles *profiles
+	// profilesPath is where the profiles are saved
+	profilesPath string
+}
+
+func main() {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	err := run(ctx, os.Args[1:], os.Stdout, os.Stderr)
+	if errors.Is(err, flag.ErrHelp) {
+		os.Exit(2)
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "go-ent:", err)
+		os.Exit(1)
+	}
+}
+
+// run parses the global flags, resolves the profile and runs the subcommand
+func run(ctx context.Context, args []string, stdout io.Writer, stderr io.Writer) error {
+	fs := flag.NewFlagSet("go-ent", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	profileName := fs.String("profile", os.Getenv("GOENT_PROFILE"), "the profile of the URL and the credentials, env GOENT_PROFILE")
+	url := fs.String("url", os.Getenv("GOENT_URL"), "the base URL of the API, env GOENT_URL, default "+defaultURL)
+	apiKey := fs.String("api-key", os.Getenv("GOENT_API_KEY"), "the API key of the tenant, env GOENT_API_KEY")
+	token := fs.String("token", os.Getenv("GOENT_TOKEN"), "the bearer token, env GOENT_TOKEN")
+	output := fs.String("output", "", "table, json or yaml, default table")
+	timeout := fs.Duration("timeout", time.Minute, "the timeout of each request")
+	fs.Usage = func() {
+		fmt.Fprintln(stderr, "usage: go-ent [flags] <command> <subcommand> [flags] [args]")
+		fmt.Fprintln(stderr, "\ncommands:")
+		names := make([]string, 0, len(commands))
+		for name := range commands {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		fmt.Fprintln(stderr, "  "+strings.Join(names, ", "))
+		fmt.Fprintln(stderr, "\nflags:")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	profilesPath, err := profilesFile()
+	if err != nil {
+		return err
+	}
+	ps, err := loadProfiles(profilesPath)
+	if err != nil {
+		return err
+	}
+	// the flags and the environment take precedence over the profile
+	p := ps.profile(*profileName)
+	if *url == "" {
+		*url = p.URL
+	}
+	if *url == "" {
+		*url = defaultURL
+	}
+	if *apiKey == "" {
+		*apiKey = p.APIKey
+	}
+	if *token == "" {
+		*token = p.Token
+	}
+	if *output == "" {
+		*output = p.Output
+	}
+	if *output == "" {
+		*output = outputTable
+	}
+	if !validOutput(*output) {
+		return fmt.Errorf("output %q is not table, json or yaml", *output)
+	}
+
+	c := &cli{
+		api:          client.New(client.Config{BaseURL: *url, APIKey: *apiKey, Token: *token, HTTPClient: &http.Client{Timeout: *timeout}}),
+		output:       *output,
+		stdout:       stdout,
+		stderr:       stderr,
+		profiles:     ps,
+		profilesPath: profilesPath,
+	}
+	return c.dispatch(ctx, fs.Args())
+}
+
+func (c *cli) dispatch(ctx context.Context, args []string) error {
+	if len(args) == 0 {
+		fmt.Fprintln(c.stderr, "usage: go-ent [flags] <command> <subcommand> [flags] [args], go-ent -h for the commands")
+		return flag.ErrHelp
+	}
+	subcommands, ok := commands[args[0]]
+	if !ok {
+		return fmt.Errorf("unknown command %q", args[0])
+	}
+	if len(args) == 1 || args[1] == "-h" || args[1] == "help" {
+		c.usage(args[0], subcommands)
+		return flag.ErrHelp
+	}
+	sub, ok := subcommands[args[1]]
+	if !ok {
+		c.usage(args[0], subcommands)
+		return fmt.Errorf("unknown subcommand %q of %s", args[1], args[0])
+	}
+	return sub.run(ctx, c, args[2:])
+}
+
+// usage lists the subcommands of the command
+func (c *cli) usage(command string, subcommands map[string]subcommand) {
+	names := make([]string, 0, len(subcommands))
+	for name := range subcommands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	fmt.Fprintf(c.stderr, "usage of go-ent %s:\n", command)
+	w := tabwriter.NewWriter(c.stderr, 0, 4, 2, ' ', 0)
+	for _, name := range names {
+		fmt.Fprintf(w, "  %s %s\t%s\n", command, name, subcommands[name].usage)
+	}
+	_ = w.Flush()
+}
+
+// flags makes the flag set of a subcommand, which prints its usage on -h
+func (c *cli) flags(name string, usage string) *flag.FlagSet {
+	fs := flag.NewFlagSet("go-ent "+name, flag.ContinueOnError)
+	fs.SetOutput(c.stderr)
+	fs.Usage = func() {
+		fmt.Fprintf(c.stderr, "usage: go-ent %s [flags] %s\n", name, usage)
+		fs.PrintDefaults()
+	}
+	return fs
+}
+
+// parse parses the flags of the subcommand, which may come after the arguments as in "users update 1 -age 20",
+// and checks the number of the arguments
+func parse(fs *flag.FlagSet, args []string, n int) ([]string, error) {
+	var positional []string
+	for {
+		if err := fs.Parse(args); err != nil {
+			return nil, err
+		}
+		if fs.NArg() == 0 {
+			break
+		}
+		positional = append(positional, fs.Arg(0))
+		args = fs.Args()[1:]
+	}
+	if len(positional) != n {
+		fs.Usage()
+		return nil, fmt.Errorf("%s takes %d arguments, got %d", fs.Name(), n, len(positional))
+	}
+	return positional, nil
+}
+
+// visited tells whether the flag was given, so that the updates change only those fields
+func visited(fs *flag.FlagSet, name string) bool {
+	found := false
+	fs.Visit(func(f *flag.Flag) {
+		if f.Name == name {
+			found = true
+		}
+	})
+	return found
+}
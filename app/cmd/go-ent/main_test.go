@@ -0,0 +1,154 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// apiServer lists one car, recording the path prefix and the credentials of the last request
+type apiServer struct {
+	*httptest.Server
+	mu     sync.Mutex
+	prefix string
+	apiKey string
+	token  string
+}
+
+func newAPIServer(t *testing.T) *apiServer {
+	t.Helper()
+	s := &apiServer{}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		s.prefix = strings.TrimSuffix(r.URL.Path, "/cars")
+		s.apiKey = r.Header.Get("X-API-Key")
+		s.token = strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+		s.mu.Unlock()
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"code":2000,"data":[{"id":1,"name":"Leaf","model":"Nissan","registered_at":"2020-04-01T09:00:00Z"}]}`))
+	}))
+	t.Cleanup(s.Close)
+	return s
+}
+
+// last is the path prefix, the API key and the token of the last request
+func (s *apiServer) last() [3]string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return [3]string{s.prefix, s.apiKey, s.token}
+}
+
+// isolate saves the profiles in a file of the test and clears the variables of the command
+func isolate(t *testing.T) {
+	t.Helper()
+	t.Setenv("GOENT_PROFILES", filepath.Join(t.TempDir(), "profiles.yaml"))
+	for _, env := range []string{"GOENT_PROFILE", "GOENT_URL", "GOENT_API_KEY", "GOENT_TOKEN"} {
+		t.Setenv(env, "")
+	}
+}
+
+// runCLI runs the command and returns what it printed to the standard output
+func runCLI(t *testing.T, args ...string) string {
+	t.Helper()
+	var stdout, stderr bytes.Buffer
+	if err := run(context.Background(), args, &stdout, &stderr); err != nil {
+		t.Fatalf("go-ent %s: %v\n%s", strings.Join(args, " "), err, stderr.String())
+	}
+	return stdout.String()
+}
+
+func TestProfilePrecedence(t *testing.T) {
+	isolate(t)
+	srv := newAPIServer(t)
+	// the first profile is the default until another one is used
+	runCLI(t, "profile", "set", "staging", "-url", srv.URL+"/staging", "-api-key", "staging-key", "-token", "staging-token", "-output", "json")
+	runCLI(t, "profile", "set", "local", "-url", srv.URL+"/local", "-api-key", "local-key")
+	runCLI(t, "profile", "use", "local")
+
+	tests := []struct {
+		name string
+		env  map[string]string
+		args []string
+		want [3]string
+	}{
+		{name: "default profile", want: [3]string{"/local", "local-key", ""}},
+		{name: "named profile", args: []string{"-profile", "staging"}, want: [3]string{"/staging", "staging-key", "staging-token"}},
+		{name: "profile of the env", env: map[string]string{"GOENT_PROFILE": "staging"}, want: [3]string{"/staging", "staging-key", "staging-token"}},
+		{
+			name: "env over the profile",
+			env:  map[string]string{"GOENT_PROFILE": "staging", "GOENT_URL": srv.URL + "/env", "GOENT_API_KEY": "env-key"},
+			want: [3]string{"/env", "env-key", "staging-token"},
+		},
+		{
+			name: "flags over the env",
+			env:  map[string]string{"GOENT_URL": srv.URL + "/env", "GOENT_API_KEY": "env-key", "GOENT_TOKEN": "env-token"},
+			args: []string{"-url", srv.URL + "/flag", "-api-key", "flag-key", "-token", "flag-token"},
+			want: [3]string{"/flag", "flag-key", "flag-token"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for key, value := range tt.env {
+				t.Setenv(key, value)
+			}
+			runCLI(t, append(tt.args, "cars", "list")...)
+			if got := srv.last(); got != tt.want {
+				t.Errorf("prefix, API key and token = %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	// the output of the profile is used without -output
+	if out := runCLI(t, "-profile", "staging", "cars", "list"); !strings.HasPrefix(out, "[") {
+		t.Errorf("output of the staging profile = %q, want JSON", out)
+	}
+	// the credentials are not printed
+	out := runCLI(t, "profile", "list")
+	if strings.Contains(out, "staging-key") || strings.Contains(out, "staging-token") {
+		t.Errorf("profile list = %q, want the credentials redacted", out)
+	}
+}
+
+func TestOutput(t *testing.T) {
+	isolate(t)
+	srv := newAPIServer(t)
+	registeredAt := time.Date(2020, 4, 1, 9, 0, 0, 0, time.UTC)
+
+	tests := map[string]string{
+		outputTable: "ID  NAME  MODEL   REGISTERED AT\n" +
+			"1   Leaf  Nissan  " + formatTime(&registeredAt) + "\n",
+		outputJSON: `[
+  {
+    "id": 1,
+    "name": "Leaf",
+    "model": "Nissan",
+    "registered_at": "2020-04-01T09:00:00Z"
+  }
+]
+`,
+		// the keys are the ones of the API
+		outputYAML: `- id: 1
+  model: Nissan
+  name: Leaf
+  registered_at: "2020-04-01T09:00:00Z"
+`,
+	}
+	for output, want := range tests {
+		t.Run(output, func(t *testing.T) {
+			if got := runCLI(t, "-url", srv.URL, "-output", output, "cars", "list"); got != want {
+				t.Errorf("output =\n%s\nwant\n%s", got, want)
+			}
+		})
+	}
+
+	var stderr bytes.Buffer
+	if err := run(context.Background(), []string{"-url", srv.URL, "-output", "xml", "cars", "list"}, &bytes.Buffer{}, &stderr); err == nil {
+		t.Error("unknown output is accepted")
+	}
+}
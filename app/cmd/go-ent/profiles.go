@@ -0,0 +1,182 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"gopkg.in/yaml.v3"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+// profile names an API and its credentials
+type profile struct {
+	URL    string `yaml:"url,omitempty"`
+	APIKey string `yaml:"api_key,omitempty"`
+	Token  string `yaml:"token,omitempty"`
+	Output string `yaml:"output,omitempty"`
+}
+
+// profiles are saved in the YAML file of profilesFile
+type profiles struct {
+	// Default is the profile used without -profile
+	Default  string              `yaml:"default,omitempty"`
+	Profiles map[string]*profile `yaml:"profiles"`
+}
+
+// profilesFile is $GOENT_PROFILES, or go-ent/profiles.yaml in the user config directory
+func profilesFile() (string, error) {
+	if path := os.Getenv("GOENT_PROFILES"); path != "" {
+		return path, nil
+	}
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", fmt.Errorf("failed finding the profiles, set GOENT_PROFILES: %w", err)
+	}
+	return filepath.Join(dir, "go-ent", "profiles.yaml"), nil
+}
+
+// loadProfiles reads the file, which may not exist yet
+func loadProfiles(path string) (*profiles, error) {
+	ps := &profiles{Profiles: make(map[string]*profile)}
+	b, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return ps, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := yaml.Unmarshal(b, ps); err != nil {
+		return nil, fmt.Errorf("profiles %s: %w", path, err)
+	}
+	if ps.Profiles == nil {
+		ps.Profiles = make(map[string]*profile)
+	}
+	return ps, nil
+}
+
+// save writes the file readable by the user only, as it has the API keys and the tokens
+func (ps *profiles) save(path string) error {
+	b, err := yaml.Marshal(ps)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return err
+	}
+	return os.WriteFile(path, b, 0o600)
+}
+
+// profile is the named one, or the default one when the name is empty. An unknown one is empty
+func (ps *profiles) profile(name string) profile {
+	if name == "" {
+		name = ps.Default
+	}
+	if p, ok := ps.Profiles[name]; ok {
+		return *p
+	}
+	return profile{}
+}
+
+var profileCommands = map[string]subcommand{
+	"list":   {usage: "", run: listProfiles},
+	"set":    {usage: "<name>", run: setProfile},
+	"use":    {usage: "<name>", run: useProfile},
+	"delete": {usage: "<name>", run: deleteProfile},
+}
+
+func listProfiles(ctx context.Context, c *cli, args []string) error {
+	if _, err := parse(c.flags("profile list", ""), args, 0); err != nil {
+		return err
+	}
+	names := make([]string, 0, len(c.profiles.Profiles))
+	for name := range c.profiles.Profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	t := table{header: []string{"NAME", "DEFAULT", "URL", "API KEY", "TOKEN", "OUTPUT"}}
+	// the credentials are never printed
+	redacted := make(map[string]profile, len(names))
+	for _, name := range names {
+		p := *c.profiles.Profiles[name]
+		if p.APIKey != "" {
+			p.APIKey = "[REDACTED]"
+		}
+		if p.Token != "" {
+			p.Token = "[REDACTED]"
+		}
+		redacted[name] = p
+		def := ""
+		if name == c.profiles.Default {
+			def = "*"
+		}
+		t.rows = append(t.rows, []string{name, def, p.URL, p.APIKey, p.Token, p.Output})
+	}
+	return c.print(redacted, t)
+}
+
+func setProfile(ctx context.Context, c *cli, args []string) error {
+	fs := c.flags("profile set", "<name>")
+	url := fs.String("url", "", "the base URL of the API")
+	apiKey := fs.String("api-key", "", "the API key of the tenant")
+	token := fs.String("token", "", "the bearer token")
+	output := fs.String("output", "", "table, json or yaml")
+	args, err := parse(fs, args, 1)
+	if err != nil {
+		return err
+	}
+	if *output != "" && !validOutput(*output) {
+		return fmt.Errorf("output %q is not table, json or yaml", *output)
+	}
+	p, ok := c.profiles.Profiles[args[0]]
+	if !ok {
+		p = &profile{}
+		c.profiles.Profiles[args[0]] = p
+	}
+	// only the flags given change the profile
+	if visited(fs, "url") {
+		p.URL = *url
+	}
+	if visited(fs, "api-key") {
+		p.APIKey = *apiKey
+	}
+	if visited(fs, "token") {
+		p.Token = *token
+	}
+	if visited(fs, "output") {
+		p.Output = *output
+	}
+	if c.profiles.Default == "" {
+		c.profiles.Default = args[0]
+	}
+	return c.profiles.save(c.profilesPath)
+}
+
+func useProfile(ctx context.Context, c *cli, args []string) error {
+	args, err := parse(c.flags("profile use", "<name>"), args, 1)
+	if err != nil {
+		return err
+	}
+	if _, ok := c.profiles.Profiles[args[0]]; !ok {
+		return fmt.Errorf("profile %q does not exist", args[0])
+	}
+	c.profiles.Default = args[0]
+	return c.profiles.save(c.profilesPath)
+}
+
+func deleteProfile(ctx context.Context, c *cli, args []string) error {
+	args, err := parse(c.flags("profile delete", "<name>"), args, 1)
+	if err != nil {
+		return err
+	}
+	if _, ok := c.profiles.Profiles[args[0]]; !ok {
+		return fmt.Errorf("profile %q does not exist", args[0])
+	}
+	delete(c.profiles.Profiles, args[0])
+	if c.profiles.Default == args[0] {
+		c.profiles.Default = ""
+	}
+	return c.profiles.save(c.profilesPath)
+}
@@ -0,0 +1,253 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"github.com/jpdel518/go-ent/client"
+	"github.com/jpdel518/go-ent/domain/model"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+var userCommands = map[string]subcommand{
+	"list":   {usage: "", run: listUsers},
+	"get":    {usage: "<id>", run: getUser},
+	"create": {usage: "", run: createUser},
+	"update": {usage: "<id>", run: updateUser},
+	"delete": {usage: "<id>", run: deleteUser},
+	"import": {usage: "<file>", run: importUsers},
+	"export": {usage: "", run: exportUsers},
+}
+
+func printUsers(c *cli, v interface{}, users ...*model.User) error {
+	t := table{header: []string{"ID", "FIRST NAME", "LAST NAME", "EMAIL", "AGE", "CARS", "AVATAR"}}
+	for _, u := range users {
+		t.rows = append(t.rows, []string{strconv.Itoa(u.ID), u.FirstName, u.LastName, u.Email, strconv.Itoa(u.Age), ids(u.CarIDs), u.Avatar})
+	}
+	return c.print(v, t)
+}
+
+func listUsers(ctx context.Context, c *cli, args []string) error {
+	fs := c.flags("users list", "")
+	num := fs.Int("num", 10, "the number of the users, or of each page with -all")
+	after := fs.Int("after", 0, "the id of the last user of the previous page")
+	all := fs.Bool("all", false, "list all the users after -after page by page")
+	cars := fs.String("cars", "", "partial to leave out the cars which cannot be fetched, or none for the ids of the cars only")
+	if _, err := parse(fs, args, 0); err != nil {
+		return err
+	}
+	opts := client.ListUsersOptions{Num: *num, After: *after, Cars: model.CarDetails(*cars)}
+	if !*all {
+		users, err := c.api.ListUsers(ctx, opts)
+		if err != nil {
+			return err
+		}
+		return printUsers(c, users, users...)
+	}
+	users := make([]*model.User, 0)
+	it := c.api.Users(opts)
+	for it.Next(ctx) {
+		users = append(users, it.User())
+	}
+	if err := it.Err(); err != nil {
+		return err
+	}
+	return printUsers(c, users, users...)
+}
+
+func getUser(ctx context.Context, c *cli, args []string) error {
+	fs := c.flags("users get", "<id>")
+	cars := fs.String("cars", "", "partial to leave out the cars which cannot be fetched, or none for the ids of the cars only")
+	args, err := parse(fs, args, 1)
+	if err != nil {
+		return err
+	}
+	id, err := parseID("user", args[0])
+	if err != nil {
+		return err
+	}
+	u, err := c.api.GetUser(ctx, id, model.CarDetails(*cars))
+	if err != nil {
+		return err
+	}
+	return printUsers(c, u, u)
+}
+
+// openAvatar opens the avatar file, or returns nil when none is given
+func openAvatar(path string) (*client.File, func(), error) {
+	if path == "" {
+		return nil, func() {}, nil
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	return &client.File{Name: filepath.Base(path), Body: f}, func() { _ = f.Close() }, nil
+}
+
+func createUser(ctx context.Context, c *cli, args []string) error {
+	fs := c.flags("users create", "")
+	firstName := fs.String("first-name", "", "the first name")
+	lastName := fs.String("last-name", "", "the last name")
+	email := fs.String("email", "", "the e-mail address")
+	age := fs.Int("age", 0, "the age")
+	cars := fs.String("cars", "", "the ids of the cars joined by commas")
+	avatarPath := fs.String("avatar", "", "the image file of the avatar")
+	if _, err := parse(fs, args, 0); err != nil {
+		return err
+	}
+	carIDs, err := parseIDs(*cars)
+	if err != nil {
+		return err
+	}
+	u := &model.User{FirstName: *firstName, LastName: *lastName, Email: *email, Age: *age, CarIDs: carIDs}
+	avatar, closeAvatar, err := openAvatar(*avatarPath)
+	if err != nil {
+		return err
+	}
+	defer closeAvatar()
+
+	var created *model.User
+	if avatar == nil {
+		created, err = c.api.CreateUser(ctx, u)
+	} else {
+		created, err = c.api.CreateUserWithAvatar(ctx, u, avatar)
+	}
+	if err != nil {
+		return err
+	}
+	return printUsers(c, created, created)
+}
+
+// updateUser changes the fields given on the current user, as the API replaces the whole user
+func updateUser(ctx context.Context, c *cli, args []string) error {
+	fs := c.flags("users update", "<id>")
+	firstName := fs.String("first-name", "", "the first name")
+	lastName := fs.String("last-name", "", "the last name")
+	email := fs.String("email", "", "the e-mail address")
+	age := fs.Int("age", 0, "the age")
+	cars := fs.String("cars", "", "the ids of the cars joined by commas, which replace the cars")
+	avatarPath := fs.String("avatar", "", "the image file of the avatar")
+	args, err := parse(fs, args, 1)
+	if err != nil {
+		return err
+	}
+	id, err := parseID("user", args[0])
+	if err != nil {
+		return err
+	}
+	avatar, closeAvatar, err := openAvatar(*avatarPath)
+	if err != nil {
+		return err
+	}
+	defer closeAvatar()
+	u, err := c.api.GetUser(ctx, id, model.CarDetailsNone)
+	if err != nil {
+		return err
+	}
+	if visited(fs, "first-name") {
+		u.FirstName = *firstName
+	}
+	if visited(fs, "last-name") {
+		u.LastName = *lastName
+	}
+	if visited(fs, "email") {
+		u.Email = *email
+	}
+	if visited(fs, "age") {
+		u.Age = *age
+	}
+	if visited(fs, "cars") {
+		if u.CarIDs, err = parseIDs(*cars); err != nil {
+			return err
+		}
+	}
+
+	var updated *model.User
+	if avatar == nil {
+		updated, err = c.api.UpdateUser(ctx, u)
+	} else {
+		updated, err = c.api.UpdateUserWithAvatar(ctx, u, avatar)
+	}
+	if err != nil {
+		return err
+	}
+	return printUsers(c, updated, updated)
+}
+
+func deleteUser(ctx context.Context, c *cli, args []string) error {
+	args, err := parse(c.flags("users delete", "<id>"), args, 1)
+	if err != nil {
+		return err
+	}
+	id, err := parseID("user", args[0])
+	if err != nil {
+		return err
+	}
+	if err := c.api.DeleteUser(ctx, id); err != nil {
+		return err
+	}
+	fmt.Fprintf(c.stderr, "user %d deleted\n", id)
+	return nil
+}
+
+func importUsers(ctx context.Context, c *cli, args []string) error {
+	fs := c.flags("users import", "<file>")
+	format := fs.String("format", "", "csv or ndjson, by the file extension by default")
+	args, err := parse(fs, args, 1)
+	if err != nil {
+		return err
+	}
+	if *format == "" {
+		*format = strings.TrimPrefix(filepath.Ext(args[0]), ".")
+	}
+	f, err := os.Open(args[0])
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	report, err := c.api.ImportUsers(ctx, *format, f)
+	if err != nil {
+		return err
+	}
+	return printImportReport(c, report)
+}
+
+func printImportReport(c *cli, report *model.ImportReport) error {
+	t := table{header: []string{"LINE", "STATUS", "ID", "EMAIL", "REASON"}}
+	for _, row := range report.Rows {
+		t.rows = append(t.rows, []string{strconv.Itoa(row.Line), string(row.Status), strconv.Itoa(row.ID), row.Email, row.Reason})
+	}
+	if err := c.print(report, t); err != nil {
+		return err
+	}
+	if c.output == outputTable {
+		fmt.Fprintf(c.stdout, "\n%d created, %d updated, %d failed\n", report.Created, report.Updated, report.Failed)
+	}
+	return nil
+}
+
+// exportUsers writes the export as it is sent, regardless of -output
+func exportUsers(ctx context.Context, c *cli, args []string) error {
+	fs := c.flags("users export", "")
+	format := fs.String("format", "csv", "csv or ndjson")
+	num := fs.Int("num", 0, "the number of the users, all by default")
+	file := fs.String("file", "", "the file written instead of the standard output")
+	if _, err := parse(fs, args, 0); err != nil {
+		return err
+	}
+	if *file == "" {
+		return c.api.ExportUsers(ctx, *format, *num, c.stdout)
+	}
+	f, err := os.Create(*file)
+	if err != nil {
+		return err
+	}
+	if err := c.api.ExportUsers(ctx, *format, *num, f); err != nil {
+		_ = f.Close()
+		return err
+	}
+	return f.Close()
+}
@@ -0,0 +1,111 @@
+package main
+
+import (
+	entsql "entgo.io/ent/dialect/sql"
+	"github.com/jpdel518/go-ent/config"
+	"github.com/jpdel518/go-ent/domain/event"
+	"github.com/jpdel518/go-ent/domain/repository"
+	"github.com/jpdel518/go-ent/ent"
+	"github.com/jpdel518/go-ent/graph"
+	"github.com/jpdel518/go-ent/infrastructure/cache"
+	"github.com/jpdel518/go-ent/infrastructure/format"
+	"github.com/jpdel518/go-ent/infrastructure/ratelimit"
+	"github.com/jpdel518/go-ent/infrastructure/rdb"
+	"github.com/jpdel518/go-ent/infrastructure/rdb/mysql"
+	"github.com/jpdel518/go-ent/infrastructure/search"
+	"github.com/jpdel518/go-ent/infrastructure/sink"
+	"github.com/jpdel518/go-ent/infrastructure/stream"
+	"github.com/jpdel518/go-ent/infrastructure/webhook"
+	"github.com/jpdel518/go-ent/presentation/handler"
+	"github.com/jpdel518/go-ent/presentation/rpc"
+	"github.com/jpdel518/go-ent/usecase"
+	"google.golang.org/grpc"
+	"net/http"
+)
+
+// storage is the file storage of the application, which is S3 in the servers
+type storage struct {
+	userFiles repository.UserFileRepository
+	jobFiles  repository.JobFileRepository
+	checker   repository.HealthChecker
+}
+
+// components are the dependency graph of the servers and the workers
+type components struct {
+	client             *ent.Client
+	changeFeed         repository.ChangeFeed
+	searchIndex        *search.Index
+	jobUsecase         usecase.JobUsecase
+	webhookDispatcher  usecase.WebhookDispatcher
+	notifier           usecase.NotificationDispatcher
+	eventRelay         usecase.EventRelay
+	idempotencyUsecase usecase.IdempotencyUsecase
+	grpcServer         *grpc.Server
+	httpServer         *http.Server
+}
+
+// newComponents wires the application on the database, the storage, the mail sender and the templates of the notifications.
+// Nothing is started, and closing the client closes the driver
+func newComponents(cfg *config.Config, driver *entsql.Driver, st storage, mailSender repository.MailSender, renderer repository.NotificationRenderer) *components {
+	client := mysql.NewClient(rdb.NewTracingDriver(rdb.NewMetricsDriver(driver)), !cfg.Production())
+	client.Use(rdb.MetricsHook())
+	changeFeed := stream.NewChangeBroker(cfg.Events.ReplaySize, cfg.Events.QueueSize)
+	client.Use(rdb.ChangeHook(changeFeed))
+	readThrough := cache.NewReadThrough(cache.NewLRUCache(cfg.Cache.Size), cfg.RequestTimeout)
+	client.Use(rdb.CacheHook(readThrough))
+	searchIndex := search.NewIndex()
+	client.Use(rdb.SearchHook(client, searchIndex))
+	userRepository := cache.NewUserRepository(rdb.NewUserRepository(client), readThrough, cfg.Cache.UserTTL)
+	carRepository := cache.NewCarRepository(rdb.NewCarRepository(client), readThrough, cfg.Cache.CarTTL)
+	userUsecase := usecase.NewUserUsecase(userRepository, carRepository, st.userFiles, cfg.Verification, cfg.RequestTimeout)
+	jobRepository := rdb.NewJobRepository(client)
+	jobUsecase := usecase.NewJobUsecase(jobRepository, st.jobFiles, userRepository, carRepository, st.userFiles, format.NewUserDecoder, cfg.Jobs.Workers, cfg.RequestTimeout)
+	webhookRepository := rdb.NewWebhookRepository(client)
+	webhookUsecase := usecase.NewWebhookUsecase(webhookRepository, cfg.RequestTimeout)
+	webhookDispatcher := usecase.NewWebhookDispatcher(webhookRepository, webhook.NewSender(&http.Client{Timeout: cfg.Webhooks.SendTimeout}), cfg.Webhooks.Interval, cfg.RequestTimeout)
+	groupRepository := rdb.NewGroupRepository(client)
+	notificationRepository := rdb.NewNotificationRepository(client)
+	notifier := usecase.NewNotificationDispatcher(notificationRepository, userRepository, carRepository, groupRepository, renderer, mailSender, cfg.Verification, cfg.Notifications.Interval, cfg.RequestTimeout)
+	sinks := []event.Sink{sink.NewLogSink(), webhookDispatcher, notifier}
+	if cfg.Events.WebhookURL != "" {
+		sinks = append(sinks, sink.NewWebhookSink(cfg.Events.WebhookURL, cfg.Events.WebhookTimeout))
+	}
+	eventRelay := usecase.NewEventRelay(rdb.NewOutboxRepository(client), sinks, cfg.Events.RelayInterval, cfg.RequestTimeout)
+	carUsecase := usecase.NewCarUsecase(carRepository, cfg.RequestTimeout)
+	groupUsecase := usecase.NewGroupUsecase(groupRepository, cfg.RequestTimeout)
+	// the database is critical for every request, the storage for the files unless the config says otherwise
+	healthCheckers := []repository.HealthChecker{rdb.NewDatabaseChecker(driver)}
+	if cfg.Production() {
+		// only the versioned migrations record the revision
+		healthCheckers = append(healthCheckers, rdb.NewMigrationChecker(driver, cfg.Database.MigrationDir))
+	}
+	optionalCheckers := []repository.HealthChecker{st.checker}
+	if cfg.Health.CriticalStorage {
+		healthCheckers, optionalCheckers = append(healthCheckers, st.checker), nil
+	}
+	healthUsecase := usecase.NewHealthUsecase(healthCheckers, optionalCheckers, cfg.Health.Timeout)
+	rateLimitUsecase := usecase.NewRateLimitUsecase(ratelimit.NewMemoryStore(), cfg.RateLimit.Default, cfg.RateLimit.Routes, cfg.RequestTimeout)
+	// the buckets of the addresses are apart from those of the clients, which are identified by the addresses as well
+	addressRateLimitUsecase := usecase.NewRateLimitUsecase(ratelimit.NewMemoryStore(), cfg.RateLimit.Address, nil, cfg.RequestTimeout)
+	idempotencyUsecase := usecase.NewIdempotencyUsecase(rdb.NewIdempotencyRepository(client), cfg.Idempotency.TTL, cfg.Idempotency.PurgeInterval, cfg.RequestTimeout)
+	searchUsecase := usecase.NewSearchUsecase(searchIndex, cfg.RequestTimeout)
+	tenantRepository := cache.NewTenantRepository(rdb.NewTenantRepository(client), readThrough, cfg.Cache.TenantTTL)
+	tenantUsecase := usecase.NewTenantUsecase(tenantRepository, cfg.Tenancy, cfg.RequestTimeout)
+	grpcServer := rpc.NewServer(userUsecase, carUsecase, groupUsecase, tenantUsecase, rateLimitUsecase, addressRateLimitUsecase, cfg.RateLimit.ClientIPHeader)
+	httpServer := handler.NewServer(cfg.HTTP, handler.NewHandler(userUsecase, jobUsecase, webhookUsecase, usecase.NewChangeUsecase(changeFeed), healthUsecase, rateLimitUsecase, addressRateLimitUsecase, cfg.RateLimit.ClientIPHeader, idempotencyUsecase, searchUsecase, carUsecase, groupUsecase, usecase.NewNotificationUsecase(notificationRepository, cfg.RequestTimeout), tenantUsecase, graph.NewSchema(client, userUsecase), cfg.GraphQL))
+	// end the event streams so that they do not hold the shutdown
+	httpServer.RegisterOnShutdown(changeFeed.Close)
+
+	return &components{
+		client:             client,
+		changeFeed:         changeFeed,
+		searchIndex:        searchIndex,
+		jobUsecase:         jobUsecase,
+		webhookDispatcher:  webhookDispatcher,
+		notifier:           notifier,
+		eventRelay:         eventRelay,
+		idempotencyUsecase: idempotencyUsecase,
+		grpcServer:         grpcServer,
+		httpServer:         httpServer,
+	}
+}
@@ -0,0 +1,230 @@
+package config
+
+import (
+	"fmt"
+	"github.com/jpdel518/go-ent/domain/model"
+	"github.com/jpdel518/go-ent/infrastructure/file/s3"
+	"github.com/jpdel518/go-ent/infrastructure/mail"
+	"github.com/jpdel518/go-ent/infrastructure/rdb/mysql"
+	"github.com/jpdel518/go-ent/logging"
+	"github.com/jpdel518/go-ent/presentation/handler"
+	"github.com/jpdel518/go-ent/tracing"
+	"github.com/jpdel518/go-ent/usecase"
+	"strings"
+	"time"
+)
+
+// Config of the application.
+// Each field is named by its YAML key, which is the flag as well ("-database.host"), and by the env tag.
+// The fields tagged as secret are redacted when printed and read from the file of the "_FILE" variable as well,
+// and the fields tagged as required must not be empty after loading
+type Config struct {
+	// Env is development, staging or production
+	Env string `yaml:"env" env:"ENV" required:"true"`
+	// RequestTimeout bounds each operation of the usecases
+	RequestTimeout time.Duration `yaml:"request_timeout" env:"REQUEST_TIMEOUT" required:"true"`
+	// ShutdownTimeout bounds the graceful shutdown
+	ShutdownTimeout time.Duration              `yaml:"shutdown_timeout" env:"SHUTDOWN_TIMEOUT" required:"true"`
+	HTTP            handler.ServerConfig       `yaml:"http"`
+	RateLimit       handler.RateLimitConfig    `yaml:"rate_limit"`
+	GraphQL         handler.GraphQLConfig      `yaml:"graphql"`
+	GRPC            GRPCConfig                 `yaml:"grpc"`
+	Database        mysql.Config               `yaml:"database"`
+	Storage         s3.Config                  `yaml:"storage"`
+	Cache           CacheConfig                `yaml:"cache"`
+	Log             logging.Config             `yaml:"log"`
+	Tracing         tracing.Config             `yaml:"tracing"`
+	Health          HealthConfig               `yaml:"health"`
+	Idempotency     IdempotencyConfig          `yaml:"idempotency"`
+	Jobs            JobsConfig                 `yaml:"jobs"`
+	Webhooks        WebhooksConfig             `yaml:"webhooks"`
+	Events          EventsConfig               `yaml:"events"`
+	Tenancy         usecase.TenancyConfig      `yaml:"tenancy"`
+	Mail            mail.Config                `yaml:"mail"`
+	Verification    usecase.VerificationConfig `yaml:"verification"`
+	Notifications   NotificationsConfig        `yaml:"notifications"`
+}
+
+// GRPCConfig of the gRPC server
+type GRPCConfig struct {
+	Addr string `yaml:"addr" env:"GRPC_ADDR" required:"true"`
+}
+
+// CacheConfig of the read-through cache of the users and the cars
+type CacheConfig struct {
+	// Size is the number of the entries kept in memory
+	Size    int           `yaml:"size" env:"CACHE_SIZE" required:"true"`
+	UserTTL time.Duration `yaml:"user_ttl" env:"CACHE_USER_TTL" required:"true"`
+	CarTTL  time.Duration `yaml:"car_ttl" env:"CACHE_CAR_TTL" required:"true"`
+	// TenantTTL is how long a tenant is found without the database, the tenants are not invalidated
+	TenantTTL time.Duration `yaml:"tenant_ttl" env:"CACHE_TENANT_TTL" required:"true"`
+}
+
+// HealthConfig of the readiness checks
+type HealthConfig struct {
+	// Timeout bounds each dependency check
+	Timeout time.Duration `yaml:"timeout" env:"HEALTH_TIMEOUT" required:"true"`
+	// CriticalStorage makes the service unavailable rather than degraded while the storage fails,
+	// as the avatars and the imports need it. Turn it off when the storage is not used
+	CriticalStorage bool `yaml:"critical_storage" env:"HEALTH_CRITICAL_STORAGE"`
+}
+
+// IdempotencyConfig of the Idempotency-Key header
+type IdempotencyConfig struct {
+	// TTL is how long the response to a key is replayed
+	TTL time.Duration `yaml:"ttl" env:"IDEMPOTENCY_TTL" required:"true"`
+	// PurgeInterval is how often the expired keys are deleted
+	PurgeInterval time.Duration `yaml:"purge_interval" env:"IDEMPOTENCY_PURGE_INTERVAL" required:"true"`
+}
+
+// JobsConfig of the background jobs
+type JobsConfig struct {
+	// Workers is the number of the jobs which run at once
+	Workers int `yaml:"workers" env:"JOB_WORKERS" required:"true"`
+}
+
+// WebhooksConfig of the webhook deliveries
+type WebhooksConfig struct {
+	// Interval is how often the due deliveries are polled
+	Interval time.Duration `yaml:"interval" env:"WEBHOOK_INTERVAL" required:"true"`
+	// SendTimeout bounds each request to the endpoints
+	SendTimeout time.Duration `yaml:"send_timeout" env:"WEBHOOK_SEND_TIMEOUT" required:"true"`
+}
+
+// NotificationsConfig of the mails which notify the users of the events concerning them
+type NotificationsConfig struct {
+	// Interval is how often the due notifications are polled
+	Interval time.Duration `yaml:"interval" env:"NOTIFICATION_INTERVAL" required:"true"`
+	// DefaultLanguage is the language of the users who have not chosen one, ja or en
+	DefaultLanguage string `yaml:"default_language" env:"NOTIFICATION_DEFAULT_LANGUAGE" required:"true"`
+}
+
+// Validate checks that the default language has templates
+func (c NotificationsConfig) Validate() error {
+	for _, l := range model.Languages {
+		if c.DefaultLanguage == l {
+			return nil
+		}
+	}
+	return fmt.Errorf("default_language %q is not one of %s", c.DefaultLanguage, strings.Join(model.Languages, ", "))
+}
+
+// EventsConfig of the domain events and the change feed
+type EventsConfig struct {
+	// RelayInterval is how often the outbox is relayed to the sinks
+	RelayInterval time.Duration `yaml:"relay_interval" env:"EVENT_RELAY_INTERVAL" required:"true"`
+	// WebhookURL receives every event when it is not empty
+	WebhookURL     string        `yaml:"webhook_url" env:"EVENT_WEBHOOK_URL"`
+	WebhookTimeout time.Duration `yaml:"webhook_timeout" env:"EVENT_WEBHOOK_TIMEOUT"`
+	// ReplaySize is the number of the recent changes replayed to the reconnecting subscribers
+	ReplaySize int `yaml:"replay_size" env:"CHANGE_REPLAY_SIZE" required:"true"`
+	// QueueSize is the number of the changes buffered for each subscriber
+	QueueSize int `yaml:"queue_size" env:"CHANGE_QUEUE_SIZE" required:"true"`
+}
+
+// Default is the config before loading the file, the environment and the flags
+func Default() Config {
+	return Config{
+		Env:             "development",
+		RequestTimeout:  30 * time.Second,
+		ShutdownTimeout: 30 * time.Second,
+		HTTP: handler.ServerConfig{
+			Addr:              ":8080",
+			ReadHeaderTimeout: 10 * time.Second,
+			ReadTimeout:       5 * time.Minute,
+			IdleTimeout:       2 * time.Minute,
+		},
+		RateLimit: handler.RateLimitConfig{
+			Default: model.RateLimit{Limit: 300, Period: time.Minute},
+			Address: model.RateLimit{Limit: 1200, Period: time.Minute},
+			// creating users writes to MySQL and S3, updating uploads the avatars
+			Routes: map[string]model.RateLimit{
+				"/user/create":  {Limit: 10, Period: time.Minute},
+				"/user/update":  {Limit: 30, Period: time.Minute},
+				"/users/import": {Limit: 5, Period: time.Minute},
+				// each one sends a mail
+				"/user/verify/resend": {Limit: 5, Period: time.Minute},
+			},
+		},
+		GraphQL: handler.GraphQLConfig{
+			ComplexityLimit: 200,
+		},
+		GRPC: GRPCConfig{
+			Addr: ":9090",
+		},
+		Database: mysql.Config{
+			Driver:          "mysql",
+			Host:            "mysql",
+			Port:            3306,
+			MigrationDir:    "ent/migrate/migrations",
+			MaxOpenConns:    25,
+			MaxIdleConns:    25,
+			ConnMaxLifetime: 5 * time.Minute,
+		},
+		Cache: CacheConfig{
+			Size:    10000,
+			UserTTL: time.Minute,
+			// the cars rarely change
+			CarTTL:    10 * time.Minute,
+			TenantTTL: 5 * time.Minute,
+		},
+		Log: logging.Config{
+			Level:      "info",
+			MaxSizeMB:  100,
+			MaxBackups: 3,
+			MaxAgeDays: 28,
+			Compress:   true,
+		},
+		Tracing: tracing.Config{
+			Exporter:    "none",
+			ServiceName: tracing.ServiceName,
+		},
+		Health: HealthConfig{
+			Timeout:         2 * time.Second,
+			CriticalStorage: true,
+		},
+		Idempotency: IdempotencyConfig{
+			TTL:           24 * time.Hour,
+			PurgeInterval: time.Hour,
+		},
+		Jobs: JobsConfig{
+			Workers: 4,
+		},
+		Webhooks: WebhooksConfig{
+			Interval:    5 * time.Second,
+			SendTimeout: 10 * time.Second,
+		},
+		Events: EventsConfig{
+			RelayInterval:  time.Second,
+			WebhookTimeout: 10 * time.Second,
+			ReplaySize:     1024,
+			QueueSize:      64,
+		},
+		// the requests without credentials belong to the tenant which the migration creates
+		Tenancy: usecase.TenancyConfig{
+			Default: "default",
+		},
+		// the mails of development are written to files, the servers send them over SMTP
+		Mail: mail.Config{
+			Transport: "file",
+			From:      "go-ent <no-reply@example.com>",
+			Dir:       "mails",
+			SMTP: mail.SMTPConfig{
+				Port: 587,
+			},
+		},
+		Verification: usecase.VerificationConfig{
+			TTL: 48 * time.Hour,
+			URL: "http://localhost:8080/user/verify",
+		},
+		Notifications: NotificationsConfig{
+			Interval:        5 * time.Second,
+			DefaultLanguage: model.LanguageJapanese,
+		},
+	}
+}
+
+// Production tells whether the schema is managed by the versioned migrations, in staging and production
+func (c *Config) Production() bool {
+	return c.Env == "staging" || c.Env == "production"
+}
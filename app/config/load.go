@@ -0,0 +1,327 @@
+package config
+
+import (
+	"encoding"
+	"errors"
+	"flag"
+	"fmt"
+	"gopkg.in/yaml.v3"
+	"io"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// redacted replaces the secrets which are set when the config is printed
+const redacted = "[REDACTED]"
+
+// ErrPrinted is returned by Load after -print-config printed the config, like flag.ErrHelp after -h
+var ErrPrinted = errors.New("config printed")
+
+// field is a leaf of the config
+type field struct {
+	// key is the dotted path of the YAML keys, which names the flag
+	key      string
+	env      string
+	secret   bool
+	required bool
+	value    reflect.Value
+}
+
+// section is a struct of the config which may validate itself
+type section struct {
+	key   string
+	value reflect.Value
+}
+
+type validator interface {
+	Validate() error
+}
+
+var textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+
+// textual tells whether the values of the type are read from text, as a struct which is a single value such as model.RateLimit
+func textual(t reflect.Type) bool {
+	return reflect.PtrTo(t).Implements(textUnmarshalerType)
+}
+
+// Load reads the config from the defaults, the YAML file, the environment and the flags in this order, and validates it.
+// The file is named by -config or CONFIG_FILE. The first argument after the flags names the migration generated in the development.
+// -print-config writes the effective config with the secrets redacted to the standard output and returns ErrPrinted
+func Load(args []string) (*Config, error) {
+	c := Default()
+	leaves, _ := walk(reflect.ValueOf(&c).Elem(), "")
+
+	fs := flag.NewFlagSet("go-ent", flag.ContinueOnError)
+	file := fs.String("config", os.Getenv("CONFIG_FILE"), "the YAML config file, env CONFIG_FILE")
+	printConfig := fs.Bool("print-config", false, "print the effective config with the secrets redacted and exit")
+	// the flags are applied after the file and the environment, in the order given
+	type flagValue struct {
+		field field
+		value string
+	}
+	var flags []flagValue
+	for _, f := range leaves {
+		f := f
+		usage := "env " + f.env
+		if f.env == "" {
+			usage = "no env"
+		}
+		if def := format(f.value); def != "" && !f.secret {
+			usage += ", default " + def
+		}
+		fs.Func(f.key, usage, func(s string) error {
+			flags = append(flags, flagValue{field: f, value: s})
+			return nil
+		})
+	}
+	if err := fs.Parse(args); err != nil {
+		return nil, err
+	}
+
+	if *file != "" {
+		if err := loadFile(&c, *file); err != nil {
+			return nil, err
+		}
+	}
+	for _, f := range leaves {
+		if err := loadEnv(f); err != nil {
+			return nil, err
+		}
+	}
+	for _, v := range flags {
+		if err := set(v.field.value, v.value); err != nil {
+			return nil, fmt.Errorf("flag -%s: %w", v.field.key, err)
+		}
+	}
+	if name := fs.Arg(0); name != "" {
+		c.Database.MigrationName = name
+	}
+
+	if *printConfig {
+		fmt.Fprint(os.Stdout, c.String())
+	}
+	if err := c.Validate(); err != nil {
+		return nil, err
+	}
+	if *printConfig {
+		return nil, ErrPrinted
+	}
+	return &c, nil
+}
+
+// Validate checks that the required keys are set, the numbers are not negative and the sections are valid.
+// The error lists every problem
+func (c *Config) Validate() error {
+	var problems []string
+	leaves, sections := walk(reflect.ValueOf(c).Elem(), "")
+	for _, f := range leaves {
+		if f.required && f.value.IsZero() {
+			problems = append(problems, fmt.Sprintf("%s is required (%s)", f.key, sources(f)))
+			continue
+		}
+		if (f.value.Kind() == reflect.Int || f.value.Kind() == reflect.Int64) && f.value.Int() < 0 {
+			problems = append(problems, fmt.Sprintf("%s must not be negative", f.key))
+		}
+	}
+	switch c.Env {
+	case "development", "staging", "production":
+	default:
+		problems = append(problems, fmt.Sprintf("env %q is not development, staging or production", c.Env))
+	}
+	for _, s := range sections {
+		if v, ok := s.value.Interface().(validator); ok {
+			if err := v.Validate(); err != nil {
+				problems = append(problems, fmt.Sprintf("%s: %v", s.key, err))
+			}
+		}
+	}
+	if len(problems) > 0 {
+		return fmt.Errorf("invalid config: %s", strings.Join(problems, "; "))
+	}
+	return nil
+}
+
+// Redacted copies the config with the secrets which are set replaced
+func (c *Config) Redacted() *Config {
+	r := *c
+	leaves, _ := walk(reflect.ValueOf(&r).Elem(), "")
+	for _, f := range leaves {
+		if f.secret && !f.value.IsZero() {
+			f.value.SetString(redacted)
+		}
+	}
+	return &r
+}
+
+// String is the redacted config in YAML
+func (c *Config) String() string {
+	b, err := yaml.Marshal(c.Redacted())
+	if err != nil {
+		return err.Error()
+	}
+	return string(b)
+}
+
+// walk lists the leaves and the sections of the struct by the YAML keys
+func walk(v reflect.Value, prefix string) ([]field, []section) {
+	var leaves []field
+	var sections []section
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		name, _, _ := strings.Cut(sf.Tag.Get("yaml"), ",")
+		if name == "" || name == "-" {
+			continue
+		}
+		key := prefix + name
+		if sf.Type.Kind() == reflect.Struct && !textual(sf.Type) {
+			sections = append(sections, section{key: key, value: v.Field(i)})
+			l, s := walk(v.Field(i), key+".")
+			leaves = append(leaves, l...)
+			sections = append(sections, s...)
+			continue
+		}
+		leaves = append(leaves, field{
+			key:      key,
+			env:      sf.Tag.Get("env"),
+			secret:   sf.Tag.Get("secret") == "true",
+			required: sf.Tag.Get("required") == "true",
+			value:    v.Field(i),
+		})
+	}
+	return leaves, sections
+}
+
+func loadFile(c *Config, path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("failed opening config file: %w", err)
+	}
+	defer f.Close()
+
+	d := yaml.NewDecoder(f)
+	// a misspelled key is an error rather than ignored
+	d.KnownFields(true)
+	if err := d.Decode(c); err != nil && !errors.Is(err, io.EOF) {
+		return fmt.Errorf("config file %s: %w", path, err)
+	}
+	return nil
+}
+
+// loadEnv sets the field from its variable. The secrets are read from the file named by the variable with "_FILE" as well.
+// The empty variables are ignored as the blanks of .env.example
+func loadEnv(f field) error {
+	if f.env == "" {
+		return nil
+	}
+	v := os.Getenv(f.env)
+	if f.secret {
+		if path := os.Getenv(f.env + "_FILE"); path != "" {
+			if v != "" {
+				return fmt.Errorf("both %s and %s_FILE are set", f.env, f.env)
+			}
+			b, err := os.ReadFile(path)
+			if err != nil {
+				return fmt.Errorf("%s_FILE: %w", f.env, err)
+			}
+			v = strings.TrimRight(string(b), "\r\n")
+		}
+	}
+	if v == "" {
+		return nil
+	}
+	if err := set(f.value, v); err != nil {
+		return fmt.Errorf("%s: %w", f.env, err)
+	}
+	return nil
+}
+
+// set parses s into the field
+func set(v reflect.Value, s string) error {
+	if textual(v.Type()) {
+		return v.Addr().Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(s))
+	}
+	if v.Type() == reflect.TypeOf(time.Duration(0)) {
+		d, err := time.ParseDuration(s)
+		if err != nil {
+			return fmt.Errorf("%q is not a duration such as 30s", s)
+		}
+		v.SetInt(int64(d))
+		return nil
+	}
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(s)
+	case reflect.Int:
+		n, err := strconv.Atoi(s)
+		if err != nil {
+			return fmt.Errorf("%q is not a number", s)
+		}
+		v.SetInt(int64(n))
+	case reflect.Bool:
+		b, err := strconv.ParseBool(s)
+		if err != nil {
+			return fmt.Errorf("%q is not a boolean", s)
+		}
+		v.SetBool(b)
+	case reflect.Map:
+		// "key=value,key=value" replaces the map
+		m := reflect.MakeMap(v.Type())
+		for _, pair := range strings.Split(s, ",") {
+			key, value, ok := strings.Cut(strings.TrimSpace(pair), "=")
+			if !ok {
+				return fmt.Errorf("%q is not key=value", pair)
+			}
+			elem := reflect.New(v.Type().Elem()).Elem()
+			if err := set(elem, strings.TrimSpace(value)); err != nil {
+				return fmt.Errorf("%s: %w", key, err)
+			}
+			m.SetMapIndex(reflect.ValueOf(strings.TrimSpace(key)), elem)
+		}
+		v.Set(m)
+	default:
+		return fmt.Errorf("unsupported type %s", v.Type())
+	}
+	return nil
+}
+
+// format prints the value as set parses it
+func format(v reflect.Value) string {
+	if m, ok := v.Interface().(encoding.TextMarshaler); ok {
+		b, _ := m.MarshalText()
+		return string(b)
+	}
+	if v.Type() == reflect.TypeOf(time.Duration(0)) {
+		return time.Duration(v.Int()).String()
+	}
+	switch v.Kind() {
+	case reflect.Map:
+		pairs := make([]string, 0, v.Len())
+		iter := v.MapRange()
+		for iter.Next() {
+			pairs = append(pairs, fmt.Sprintf("%v=%s", iter.Key(), format(iter.Value())))
+		}
+		return strings.Join(pairs, ",")
+	case reflect.Bool:
+		return strconv.FormatBool(v.Bool())
+	}
+	if v.IsZero() {
+		return ""
+	}
+	return fmt.Sprint(v.Interface())
+}
+
+// sources names where the field is set
+func sources(f field) string {
+	s := "set " + f.key + " in the file"
+	if f.env != "" {
+		s += ", " + f.env
+		if f.secret {
+			s += ", " + f.env + "_FILE"
+		}
+	}
+	return s + " or -" + f.key
+}
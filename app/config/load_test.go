@@ -0,0 +1,179 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// isolate clears the variables of the config, so that the environment of the test does not leak into Load
+func isolate(t *testing.T) {
+	t.Helper()
+	c := Default()
+	leaves, _ := walk(reflect.ValueOf(&c).Elem(), "")
+	t.Setenv("CONFIG_FILE", "")
+	for _, f := range leaves {
+		if f.env != "" {
+			t.Setenv(f.env, "")
+			t.Setenv(f.env+"_FILE", "")
+		}
+	}
+}
+
+// writeFile writes the content to a file of the test and returns its path
+func writeFile(t *testing.T, name string, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+// required sets the keys which have no default
+const required = `
+database:
+  name: sample_db
+  user: sample_user
+storage:
+  region: ap-northeast-1
+  bucket: sample-bucket
+verification:
+  secret: verification-secret
+`
+
+func TestLoadPrecedence(t *testing.T) {
+	isolate(t)
+	file := writeFile(t, "config.yaml", required+`
+request_timeout: 10s
+http:
+  addr: :8081
+grpc:
+  addr: :9091
+`)
+	t.Setenv("HTTP_ADDR", ":8082")
+	t.Setenv("GRPC_ADDR", ":9092")
+
+	c, err := Load([]string{"-config", file, "-grpc.addr", ":9093", "add_users"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		key  string
+		got  interface{}
+		want interface{}
+	}{
+		{key: "default", got: c.ShutdownTimeout.String(), want: "30s"},
+		{key: "file over the default", got: c.RequestTimeout.String(), want: "10s"},
+		{key: "env over the file", got: c.HTTP.Addr, want: ":8082"},
+		{key: "flag over the env", got: c.GRPC.Addr, want: ":9093"},
+		{key: "argument", got: c.Database.MigrationName, want: "add_users"},
+	}
+	for _, tt := range tests {
+		if tt.got != tt.want {
+			t.Errorf("%s = %v, want %v", tt.key, tt.got, tt.want)
+		}
+	}
+
+	// the flags are applied in the order given
+	c, err = Load([]string{"-config", file, "-grpc.addr", ":9093", "-grpc.addr", ":9094"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.GRPC.Addr != ":9094" {
+		t.Errorf("grpc.addr = %s, want the last flag", c.GRPC.Addr)
+	}
+
+	// a misspelled key of the file is an error
+	misspelled := writeFile(t, "misspelled.yaml", required+"request_timout: 10s\n")
+	if _, err := Load([]string{"-config", misspelled}); err == nil {
+		t.Error("misspelled key is loaded")
+	}
+}
+
+func TestLoadSecretFile(t *testing.T) {
+	isolate(t)
+	file := writeFile(t, "config.yaml", required)
+	t.Setenv("RDB_PASSWORD_FILE", writeFile(t, "rdb_password", "s3cret:@/\n"))
+
+	c, err := Load([]string{"-config", file})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// the trailing newline of the file is not a part of the secret
+	if c.Database.Password != "s3cret:@/" {
+		t.Errorf("database.password = %q, want the content of the file", c.Database.Password)
+	}
+
+	// the secret set twice is ambiguous
+	t.Setenv("RDB_PASSWORD", "other")
+	if _, err := Load([]string{"-config", file}); err == nil || !strings.Contains(err.Error(), "RDB_PASSWORD_FILE") {
+		t.Errorf("Load = %v, want an error of both variables", err)
+	}
+
+	t.Setenv("RDB_PASSWORD", "")
+	t.Setenv("RDB_PASSWORD_FILE", filepath.Join(t.TempDir(), "missing"))
+	if _, err := Load([]string{"-config", file}); err == nil {
+		t.Error("missing secret file is loaded")
+	}
+}
+
+func TestLoadRequired(t *testing.T) {
+	isolate(t)
+
+	_, err := Load(nil)
+	if err == nil {
+		t.Fatal("config without the required keys is loaded")
+	}
+	// every problem is listed with where to set it
+	for _, want := range []string{
+		"database.name is required (set database.name in the file, RDB_NAME or -database.name)",
+		"database.user is required",
+		"storage.bucket is required",
+		"verification.secret is required (set verification.secret in the file, VERIFICATION_SECRET, VERIFICATION_SECRET_FILE or -verification.secret)",
+	} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("Load = %v, want %q", err, want)
+		}
+	}
+	if strings.Contains(err.Error(), "database.host") {
+		t.Errorf("Load = %v, the key with a default is reported", err)
+	}
+
+	file := writeFile(t, "config.yaml", required)
+	t.Setenv("ENV", "testing")
+	if _, err := Load([]string{"-config", file, "-jobs.workers", "-1"}); err == nil ||
+		!strings.Contains(err.Error(), `env "testing"`) || !strings.Contains(err.Error(), "jobs.workers must not be negative") {
+		t.Errorf("Load = %v, want the env and the negative number reported", err)
+	}
+}
+
+func TestRedacted(t *testing.T) {
+	isolate(t)
+	file := writeFile(t, "config.yaml", required)
+	t.Setenv("RDB_PASSWORD", "rdb-password")
+
+	c, err := Load([]string{"-config", file})
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := c.String()
+	for _, secret := range []string{"rdb-password", "verification-secret"} {
+		if strings.Contains(s, secret) {
+			t.Errorf("printed config contains %q", secret)
+		}
+	}
+	if !strings.Contains(s, "password: '[REDACTED]'") || !strings.Contains(s, "name: sample_db") {
+		t.Errorf("printed config = %s, want the secrets redacted and the others kept", s)
+	}
+	// the secrets which are not set stay empty, so that a missing one is seen
+	if c.Redacted().Storage.AccessKey != "" {
+		t.Error("empty secret is redacted")
+	}
+	// the loaded config keeps the secrets
+	if c.Database.Password != "rdb-password" || c.Verification.Secret != "verification-secret" {
+		t.Error("redacting changes the config")
+	}
+}
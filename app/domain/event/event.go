@@ -0,0 +1,84 @@
+package event
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+)
+
+// event types
+const (
+	UserCreated    = "user.created"
+	UserUpdated    = "user.updated"
+	UserDeleted    = "user.deleted"
+	CarTransferred = "car.transferred"
+	GroupUserAdded = "group.user_added"
+	// UserVerificationRequested asks for the link which verifies the e-mail of the user to be mailed
+	UserVerificationRequested = "user.verification_requested"
+)
+
+// aggregate types
+const (
+	AggregateUser  = "user"
+	AggregateCar   = "car"
+	AggregateGroup = "group"
+)
+
+// Event is a domain event published to other services
+type Event struct {
+	ID            int             `json:"id"`
+	TenantID      int             `json:"-"`
+	Type          string          `json:"type"`
+	AggregateType string          `json:"aggregate_type"`
+	AggregateID   int             `json:"aggregate_id"`
+	Payload       json.RawMessage `json:"payload"`
+	OccurredAt    time.Time       `json:"occurred_at"`
+	// delivery state kept by the outbox
+	Attempts    int       `json:"-"`
+	AvailableAt time.Time `json:"-"`
+}
+
+// UserDeletedPayload is the payload of UserDeleted
+type UserDeletedPayload struct {
+	ID int `json:"id"`
+}
+
+// UserVerificationRequestedPayload is the payload of UserVerificationRequested.
+// The link is signed when the mail is queued, so that no token is published with the event
+type UserVerificationRequestedPayload struct {
+	Email string `json:"email"`
+}
+
+// CarTransferredPayload is the payload of CarTransferred. A zero user id means no owner
+type CarTransferredPayload struct {
+	CarID      int `json:"car_id"`
+	FromUserID int `json:"from_user_id,omitempty"`
+	ToUserID   int `json:"to_user_id,omitempty"`
+}
+
+// GroupUserAddedPayload is the payload of GroupUserAdded
+type GroupUserAddedPayload struct {
+	GroupID int `json:"group_id"`
+	UserID  int `json:"user_id"`
+}
+
+// New creates an event with the payload encoded as JSON
+func New(eventType string, aggregateType string, aggregateID int, payload interface{}) (*Event, error) {
+	b, err := json.Marshal(payload)
+	if err != nil {
+		return nil, err
+	}
+	return &Event{
+		Type:          eventType,
+		AggregateType: aggregateType,
+		AggregateID:   aggregateID,
+		Payload:       b,
+		OccurredAt:    time.Now(),
+	}, nil
+}
+
+// Sink delivers events to the outside of the service
+type Sink interface {
+	Name() string
+	Publish(ctx context.Context, e *Event) error
+}
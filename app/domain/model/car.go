@@ -5,11 +5,9 @@ import (
 	"time"
 )
 
-const dateLayout = "2006-01-02T15:04:05"
-
 type Car struct {
 	ID           int       `json:"id"`
-	Name         string    `json:"name""`
+	Name         string    `json:"name"`
 	Model        string    `json:"model"`
 	RegisteredAt time.Time `json:"registered_at"`
 }
@@ -18,6 +16,17 @@ func (c Car) Validate() error {
 	return validation.ValidateStruct(&c,
 		validation.Field(&c.Name, validation.Required, validation.Length(1, 20)),
 		validation.Field(&c.Model, validation.Required, validation.Length(1, 20)),
-		validation.Field(&c.RegisteredAt, validation.Required, validation.Date(dateLayout)),
+		validation.Field(&c.RegisteredAt, validation.Required),
+	)
+}
+
+// CarTransfer gives a car to the user, taking it from its owner
+type CarTransfer struct {
+	UserID int `json:"user_id"`
+}
+
+func (t CarTransfer) Validate() error {
+	return validation.ValidateStruct(&t,
+		validation.Field(&t.UserID, validation.Required, validation.Min(1)),
 	)
 }
@@ -0,0 +1,64 @@
+package model
+
+import "time"
+
+const (
+	ChangeEntityUser  = "user"
+	ChangeEntityCar   = "car"
+	ChangeEntityGroup = "group"
+)
+
+type ChangeOp string
+
+const (
+	ChangeOpCreate ChangeOp = "create"
+	ChangeOpUpdate ChangeOp = "update"
+	ChangeOpDelete ChangeOp = "delete"
+)
+
+// Change notifies that an entity was written. The ID increases one by one in the process
+type Change struct {
+	ID       uint64    `json:"id"`
+	Entity   string    `json:"entity"`
+	EntityID int       `json:"entity_id"`
+	Op       ChangeOp  `json:"op"`
+	At       time.Time `json:"at"`
+	// TenantID is the tenant of the entity, which only its subscribers receive
+	TenantID int `json:"-"`
+}
+
+// ChangeFilter selects changes of the tenant. An empty list matches everything
+type ChangeFilter struct {
+	TenantID int
+	Entities []string
+	IDs      []int
+}
+
+func (f ChangeFilter) Match(c *Change) bool {
+	return c.TenantID == f.TenantID &&
+		matchAny(len(f.Entities), func(i int) bool { return f.Entities[i] == c.Entity }) &&
+		matchAny(len(f.IDs), func(i int) bool { return f.IDs[i] == c.EntityID })
+}
+
+func matchAny(n int, match func(i int) bool) bool {
+	if n == 0 {
+		return true
+	}
+	for i := 0; i < n; i++ {
+		if match(i) {
+			return true
+		}
+	}
+	return false
+}
+
+// ChangeSubscription receives changes until it is closed.
+// C is closed when the subscriber cannot keep up, and then it should subscribe again with the last ID it received.
+type ChangeSubscription struct {
+	// Replay holds the changes after the requested ID
+	Replay []*Change
+	// Missed is true when some changes after the requested ID are no longer kept
+	Missed bool
+	C      <-chan *Change
+	Close  func()
+}
@@ -0,0 +1,18 @@
+package model
+
+import (
+	validation "github.com/go-ozzo/ozzo-validation"
+	"regexp"
+)
+
+type Group struct {
+	ID      int    `json:"id"`
+	Name    string `json:"name"`
+	UserIDs []int  `json:"user_ids"`
+}
+
+func (g Group) Validate() error {
+	return validation.ValidateStruct(&g,
+		validation.Field(&g.Name, validation.Required, validation.Match(regexp.MustCompile("^[a-zA-Z_]+$"))),
+	)
+}
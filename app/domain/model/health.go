@@ -0,0 +1,34 @@
+package model
+
+const (
+	HealthStatusOK          = "ok"
+	HealthStatusDegraded    = "degraded"
+	HealthStatusUnavailable = "unavailable"
+)
+
+// HealthCheckTimedOut and HealthCheckFailed are the errors of the checks which are reported.
+// The errors of the dependencies are only logged, as they may tell the hosts and the users of them
+const (
+	HealthCheckTimedOut = "timed out"
+	HealthCheckFailed   = "failed"
+)
+
+// HealthCheck is the result of checking one dependency
+type HealthCheck struct {
+	Name     string `json:"name"`
+	Status   string `json:"status"`
+	Critical bool   `json:"critical"`
+	// Error is HealthCheckTimedOut or HealthCheckFailed when the check fails
+	Error      string `json:"error,omitempty"`
+	DurationMs int64  `json:"duration_ms"`
+}
+
+// HealthReport sums up the checks. It is unavailable when a critical check fails and degraded when an optional one fails
+type HealthReport struct {
+	Status string         `json:"status"`
+	Checks []*HealthCheck `json:"checks"`
+}
+
+func (r *HealthReport) Ready() bool {
+	return r.Status != HealthStatusUnavailable
+}
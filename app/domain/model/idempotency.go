@@ -0,0 +1,32 @@
+package model
+
+import "time"
+
+type IdempotencyKeyStatus string
+
+const (
+	// IdempotencyKeyPending is the status while the first request with the key is served
+	IdempotencyKeyPending   IdempotencyKeyStatus = "pending"
+	IdempotencyKeyCompleted IdempotencyKeyStatus = "completed"
+)
+
+// IdempotencyKey is a key given by a client to a request, and the response stored to replay for the retries with the key
+type IdempotencyKey struct {
+	ID    int
+	Key   string
+	Scope string
+	// Route is the method and the route pattern of the request
+	Route string
+	// Fingerprint is the hash of the route and the body of the request
+	Fingerprint         string
+	Status              IdempotencyKeyStatus
+	ResponseCode        int
+	ResponseContentType string
+	ResponseBody        []byte
+	ExpiresAt           time.Time
+	CreatedAt           time.Time
+}
+
+func (k *IdempotencyKey) Expired(now time.Time) bool {
+	return !now.Before(k.ExpiresAt)
+}
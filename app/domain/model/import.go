@@ -0,0 +1,68 @@
+package model
+
+import "fmt"
+
+type ImportStatus string
+
+const (
+	ImportStatusCreated ImportStatus = "created"
+	ImportStatusUpdated ImportStatus = "updated"
+	ImportStatusFailed  ImportStatus = "failed"
+)
+
+// ImportRow is the result of importing a single row
+type ImportRow struct {
+	Line   int          `json:"line"`
+	Status ImportStatus `json:"status"`
+	ID     int          `json:"id,omitempty"`
+	Email  string       `json:"email,omitempty"`
+	Reason string       `json:"reason,omitempty"`
+}
+
+// ImportReport is the per-row report of a bulk import
+type ImportReport struct {
+	Created int          `json:"created"`
+	Updated int          `json:"updated"`
+	Failed  int          `json:"failed"`
+	Rows    []*ImportRow `json:"rows"`
+}
+
+// Add appends a row result and counts it up
+func (r *ImportReport) Add(row *ImportRow) {
+	switch row.Status {
+	case ImportStatusCreated:
+		r.Created++
+	case ImportStatusUpdated:
+		r.Updated++
+	case ImportStatusFailed:
+		r.Failed++
+	}
+	r.Rows = append(r.Rows, row)
+}
+
+// RowError is returned by import decoders when a single row cannot be parsed.
+// Decoding can go on with the next row after it.
+type RowError struct {
+	Line int
+	Err  error
+}
+
+func (e *RowError) Error() string {
+	return fmt.Sprintf("line %d: %v", e.Line, e.Err)
+}
+
+func (e *RowError) Unwrap() error {
+	return e.Err
+}
+
+// UserDecoder reads users from an import source row by row.
+// Decode returns io.EOF after the last row and a *RowError when a row cannot be parsed.
+type UserDecoder interface {
+	Decode() (u *User, line int, err error)
+}
+
+// UserEncoder writes users to an export destination
+type UserEncoder interface {
+	Encode(u *User) error
+	Flush() error
+}
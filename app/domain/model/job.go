@@ -0,0 +1,38 @@
+package model
+
+import "time"
+
+type JobStatus string
+
+const (
+	JobStatusQueued    JobStatus = "queued"
+	JobStatusRunning   JobStatus = "running"
+	JobStatusSucceeded JobStatus = "succeeded"
+	JobStatusFailed    JobStatus = "failed"
+	JobStatusCanceled  JobStatus = "canceled"
+)
+
+const (
+	JobTypeUserImport      = "user_import"
+	JobTypeAvatarReprocess = "avatar_reprocess"
+)
+
+type Job struct {
+	ID          int                    `json:"id"`
+	TenantID    int                    `json:"-"`
+	Type        string                 `json:"type"`
+	Status      JobStatus              `json:"status"`
+	Progress    int                    `json:"progress"`
+	Params      map[string]string      `json:"params,omitempty"`
+	Result      map[string]interface{} `json:"result,omitempty"`
+	Error       string                 `json:"error,omitempty"`
+	ErrorReport string                 `json:"error_report,omitempty"`
+	CreatedAt   time.Time              `json:"created_at"`
+	StartedAt   *time.Time             `json:"started_at,omitempty"`
+	FinishedAt  *time.Time             `json:"finished_at,omitempty"`
+}
+
+// Finished reports whether the job will not run anymore
+func (j Job) Finished() bool {
+	return j.Status == JobStatusSucceeded || j.Status == JobStatusFailed || j.Status == JobStatusCanceled
+}
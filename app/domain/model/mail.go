@@ -0,0 +1,9 @@
+package model
+
+// Mail is a message to a single recipient with a plain text body, and an HTML alternative when HTML is not empty
+type Mail struct {
+	To      string
+	Subject string
+	Text    string
+	HTML    string
+}
@@ -0,0 +1,94 @@
+package model
+
+import (
+	validation "github.com/go-ozzo/ozzo-validation"
+	"time"
+)
+
+// the languages of the notifications
+const (
+	LanguageJapanese = "ja"
+	LanguageEnglish  = "en"
+)
+
+// Languages are the languages which the notifications have templates of
+var Languages = []string{LanguageJapanese, LanguageEnglish}
+
+type NotificationKind string
+
+const (
+	// NotificationWelcome is sent to a new user
+	NotificationWelcome NotificationKind = "welcome"
+	// NotificationCarAssigned is sent to the new owner of a car
+	NotificationCarAssigned NotificationKind = "car_assigned"
+	// NotificationGroupAdded is sent to a new member of a group
+	NotificationGroupAdded NotificationKind = "group_added"
+	// NotificationVerification mails the link which verifies the e-mail of a user
+	NotificationVerification NotificationKind = "verification"
+)
+
+// NotificationKinds are all the kinds of the notifications
+var NotificationKinds = []NotificationKind{NotificationWelcome, NotificationCarAssigned, NotificationGroupAdded, NotificationVerification}
+
+type NotificationStatus string
+
+const (
+	NotificationPending NotificationStatus = "pending"
+	NotificationSent    NotificationStatus = "sent"
+	NotificationFailed  NotificationStatus = "failed"
+)
+
+// Notification is a mail to a user about an event, which is queued and sent in the background
+type Notification struct {
+	ID            int                `json:"id"`
+	UserID        int                `json:"user_id"`
+	EventID       int                `json:"event_id"`
+	Kind          NotificationKind   `json:"kind"`
+	Mail          Mail               `json:"-"`
+	Status        NotificationStatus `json:"status"`
+	Attempts      int                `json:"attempts"`
+	LastError     string             `json:"last_error,omitempty"`
+	NextAttemptAt time.Time          `json:"next_attempt_at"`
+	SentAt        *time.Time         `json:"sent_at,omitempty"`
+	CreatedAt     time.Time          `json:"created_at"`
+}
+
+// NotificationPreferences of a user. The welcome and the verification mails are always sent
+type NotificationPreferences struct {
+	UserID int `json:"user_id"`
+	// Language of the mails, the default one of the service when empty
+	Language    string `json:"language"`
+	CarAssigned bool   `json:"car_assigned"`
+	GroupAdded  bool   `json:"group_added"`
+}
+
+func (p NotificationPreferences) Validate() error {
+	languages := make([]interface{}, 0, len(Languages))
+	for _, l := range Languages {
+		languages = append(languages, l)
+	}
+	return validation.ValidateStruct(&p,
+		validation.Field(&p.Language, validation.In(languages...)),
+	)
+}
+
+// Wants reports whether the user wants the notifications of the kind
+func (p NotificationPreferences) Wants(kind NotificationKind) bool {
+	switch kind {
+	case NotificationCarAssigned:
+		return p.CarAssigned
+	case NotificationGroupAdded:
+		return p.GroupAdded
+	}
+	return true
+}
+
+// NotificationData is what the templates of the notifications are rendered with.
+// Car and Group are set for the kinds about them, and Link and ExpiresAt for the verification
+type NotificationData struct {
+	User      *User
+	Car       *Car
+	Group     *Group
+	Link      string
+	ExpiresAt time.Time
+}
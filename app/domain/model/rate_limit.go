@@ -0,0 +1,84 @@
+package model
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// RateLimit is a token bucket which holds Limit tokens and refills them evenly over Period.
+// Each request takes a token. The zero RateLimit does not limit
+type RateLimit struct {
+	Limit  int
+	Period time.Duration
+}
+
+// ParseRateLimit reads "limit/period" such as "10/1m". The empty string is the zero RateLimit
+func ParseRateLimit(s string) (RateLimit, error) {
+	var l RateLimit
+	if s == "" {
+		return l, nil
+	}
+	limit, period, ok := strings.Cut(s, "/")
+	if !ok {
+		return l, fmt.Errorf("rate limit %q is not limit/period such as 10/1m", s)
+	}
+	n, err := strconv.Atoi(limit)
+	if err != nil || n < 0 {
+		return l, fmt.Errorf("rate limit %q: %q is not a number", s, limit)
+	}
+	d, err := time.ParseDuration(period)
+	if err != nil || d <= 0 {
+		return l, fmt.Errorf("rate limit %q: %q is not a duration", s, period)
+	}
+	return RateLimit{Limit: n, Period: d}, nil
+}
+
+func (l RateLimit) Unlimited() bool {
+	return l.Limit == 0
+}
+
+// Interval is the time to refill one token
+func (l RateLimit) Interval() time.Duration {
+	return l.Period / time.Duration(l.Limit)
+}
+
+func (l RateLimit) String() string {
+	if l.Unlimited() {
+		return ""
+	}
+	// 1m rather than 1m0s
+	period := l.Period.String()
+	if strings.HasSuffix(period, "m0s") {
+		period = strings.TrimSuffix(period, "0s")
+	}
+	if strings.HasSuffix(period, "h0m") {
+		period = strings.TrimSuffix(period, "0m")
+	}
+	return strconv.Itoa(l.Limit) + "/" + period
+}
+
+func (l RateLimit) MarshalText() ([]byte, error) {
+	return []byte(l.String()), nil
+}
+
+func (l *RateLimit) UnmarshalText(b []byte) error {
+	parsed, err := ParseRateLimit(string(b))
+	if err != nil {
+		return err
+	}
+	*l = parsed
+	return nil
+}
+
+// RateLimitDecision tells whether a request may go on and how much of the limit is left
+type RateLimitDecision struct {
+	Allowed   bool
+	Limit     RateLimit
+	Remaining int
+	// Reset is the time until the bucket is full again
+	Reset time.Duration
+	// RetryAfter is the time until the next token when the request is not allowed
+	RetryAfter time.Duration
+}
@@ -0,0 +1,45 @@
+package model
+
+import (
+	validation "github.com/go-ozzo/ozzo-validation"
+)
+
+const (
+	SearchTypeUser = "user"
+	SearchTypeCar  = "car"
+)
+
+// SearchQuery looks for the users by name and e-mail and the cars by name and model.
+// The words of the text match the words of the fields exactly, by prefix or with a typo or two
+type SearchQuery struct {
+	Text string
+	// Types limits the hits to the types. Empty is all the types
+	Types []string
+	Limit int
+}
+
+func (q SearchQuery) Validate() error {
+	return validation.ValidateStruct(&q,
+		validation.Field(&q.Text, validation.Required, validation.Length(1, 100)),
+		validation.Field(&q.Types, validation.Each(validation.In(SearchTypeUser, SearchTypeCar))),
+		validation.Field(&q.Limit, validation.Min(1), validation.Max(100)),
+	)
+}
+
+// SearchHit is a user or a car matching the query. Higher scores match better
+type SearchHit struct {
+	Type  string  `json:"type"`
+	ID    int     `json:"id"`
+	Score float64 `json:"score"`
+	User  *User   `json:"user,omitempty"`
+	Car   *Car    `json:"car,omitempty"`
+	// Highlights are the matching fields by name, with the matching words in <em> and the rest HTML-escaped
+	Highlights map[string]string `json:"highlights"`
+}
+
+type SearchResult struct {
+	Query string `json:"query"`
+	// Total is the number of the hits before the limit
+	Total int          `json:"total"`
+	Hits  []*SearchHit `json:"hits"`
+}
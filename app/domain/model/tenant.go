@@ -0,0 +1,37 @@
+package model
+
+import (
+	validation "github.com/go-ozzo/ozzo-validation"
+	"regexp"
+)
+
+// Tenant is a customer organisation. Its users, cars and groups are not seen by the others
+type Tenant struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+	// Slug is the subdomain of the tenant and the tenant claim of its tokens
+	Slug string `json:"slug"`
+}
+
+func (t Tenant) Validate() error {
+	return validation.ValidateStruct(&t,
+		validation.Field(&t.Name, validation.Required),
+		validation.Field(&t.Slug, validation.Required, validation.Length(1, 63), validation.Match(regexp.MustCompile("^[a-z0-9]+(-[a-z0-9]+)*$"))),
+	)
+}
+
+// TenantAccess is the tenant of a request and who made it
+type TenantAccess struct {
+	Tenant *Tenant
+	// Principal is "key:" and the hash of the API key, or "user:" and the subject of the token. It is empty for the anonymous requests
+	Principal string
+}
+
+// TenantCredentials of a request, any of which tells its tenant
+type TenantCredentials struct {
+	APIKey string
+	// Token is a JWT with the slug of the tenant in the tenant claim
+	Token string
+	// Host is the host of the request, whose subdomain is the slug of the tenant
+	Host string
+}
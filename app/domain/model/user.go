@@ -3,6 +3,8 @@ package model
 import (
 	validation "github.com/go-ozzo/ozzo-validation"
 	"github.com/go-ozzo/ozzo-validation/is"
+	"strings"
+	"time"
 )
 
 type User struct {
@@ -14,6 +16,13 @@ type User struct {
 	CarIDs    []int  `json:"car_ids"`
 	Cars      []Car  `json:"cars"`
 	Avatar    string `json:"avatar"`
+	// VerifiedAt is when the e-mail was verified, nil until then
+	VerifiedAt *time.Time `json:"verified_at,omitempty"`
+}
+
+// NormalizeEmail trims the e-mail and makes it lower case, in which the e-mails are stored and compared
+func NormalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
 }
 
 func (u User) Validate() error {
@@ -24,3 +33,20 @@ func (u User) Validate() error {
 		validation.Field(&u.Age, validation.Min(0)),
 	)
 }
+
+// CarDetails tells whether the users are returned with the details of their cars or with the ids only.
+// The empty CarDetails is CarDetailsAll, as the users were always returned with the details
+type CarDetails string
+
+const (
+	// CarDetailsAll fails when any of the cars cannot be fetched
+	CarDetailsAll CarDetails = "all"
+	// CarDetailsPartial leaves out the cars which cannot be fetched
+	CarDetailsPartial CarDetails = "partial"
+	// CarDetailsNone returns the ids of the cars only, with no cars
+	CarDetailsNone CarDetails = "none"
+)
+
+func (d CarDetails) Validate() error {
+	return validation.Validate(string(d), validation.In(string(CarDetailsAll), string(CarDetailsPartial), string(CarDetailsNone)))
+}
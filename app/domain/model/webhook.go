@@ -0,0 +1,62 @@
+package model
+
+import (
+	"encoding/json"
+	validation "github.com/go-ozzo/ozzo-validation"
+	"github.com/go-ozzo/ozzo-validation/is"
+	"time"
+)
+
+type WebhookSubscription struct {
+	ID           int        `json:"id"`
+	URL          string     `json:"url"`
+	Secret       string     `json:"secret,omitempty"`
+	EventTypes   []string   `json:"event_types"`
+	Active       bool       `json:"active"`
+	FailureCount int        `json:"failure_count"`
+	DisabledAt   *time.Time `json:"disabled_at,omitempty"`
+	CreatedAt    time.Time  `json:"created_at"`
+}
+
+func (w WebhookSubscription) Validate() error {
+	return validation.ValidateStruct(&w,
+		validation.Field(&w.URL, validation.Required, is.URL, validation.Length(1, 255)),
+		validation.Field(&w.Secret, validation.Length(16, 255)),
+	)
+}
+
+// Subscribes reports whether the subscription wants events of the type
+func (w WebhookSubscription) Subscribes(eventType string) bool {
+	if len(w.EventTypes) == 0 {
+		return true
+	}
+	for _, t := range w.EventTypes {
+		if t == eventType || t == "*" {
+			return true
+		}
+	}
+	return false
+}
+
+type WebhookDeliveryStatus string
+
+const (
+	WebhookDeliveryPending   WebhookDeliveryStatus = "pending"
+	WebhookDeliverySucceeded WebhookDeliveryStatus = "succeeded"
+	WebhookDeliveryFailed    WebhookDeliveryStatus = "failed"
+)
+
+type WebhookDelivery struct {
+	ID             int                   `json:"id"`
+	SubscriptionID int                   `json:"subscription_id"`
+	EventID        int                   `json:"event_id"`
+	EventType      string                `json:"event_type"`
+	Payload        json.RawMessage       `json:"payload"`
+	Status         WebhookDeliveryStatus `json:"status"`
+	Attempts       int                   `json:"attempts"`
+	ResponseCode   int                   `json:"response_code,omitempty"`
+	LastError      string                `json:"last_error,omitempty"`
+	NextAttemptAt  time.Time             `json:"next_attempt_at"`
+	DeliveredAt    *time.Time            `json:"delivered_at,omitempty"`
+	CreatedAt      time.Time             `json:"created_at"`
+}
@@ -0,0 +1,15 @@
+package repository
+
+import (
+	"context"
+	"time"
+)
+
+// Cache keeps encoded values by key for a while.
+// A cache shared by the instances makes the entries and the invalidations hold across them
+type Cache interface {
+	// Get returns false when the key is missing or expired
+	Get(ctx context.Context, key string) ([]byte, bool, error)
+	Set(ctx context.Context, key string, value []byte, ttl time.Duration) error
+	Delete(ctx context.Context, keys ...string) error
+}
@@ -0,0 +1,13 @@
+package repository
+
+import "github.com/jpdel518/go-ent/domain/model"
+
+// ChangeFeed fans out entity changes to the live subscribers
+type ChangeFeed interface {
+	// Publish numbers the change and hands it to the subscribers without waiting for them
+	Publish(c *model.Change)
+	// Subscribe starts receiving the changes which match the filter. lastID 0 skips the replay
+	Subscribe(filter model.ChangeFilter, lastID uint64) *model.ChangeSubscription
+	// Close ends all the subscriptions. Later subscriptions end at once
+	Close()
+}
@@ -0,0 +1,10 @@
+package repository
+
+import "errors"
+
+var (
+	// ErrNotFound is matched by errors.Is when the user or the car does not exist
+	ErrNotFound = errors.New("not found")
+	// ErrDuplicateEmail is matched by errors.Is when another user has the e-mail
+	ErrDuplicateEmail = errors.New("e-mail is already registered")
+)
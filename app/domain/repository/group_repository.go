@@ -0,0 +1,15 @@
+package repository
+
+import (
+	"context"
+	"github.com/jpdel518/go-ent/domain/model"
+)
+
+type GroupRepository interface {
+	Fetch(ctx context.Context, num int) ([]*model.Group, error)
+	GetByID(ctx context.Context, id int) (*model.Group, error)
+	Create(ctx context.Context, g *model.Group) error
+	// Update replaces the name and the members of the group
+	Update(ctx context.Context, g *model.Group) error
+	Delete(ctx context.Context, id int) error
+}
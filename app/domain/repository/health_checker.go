@@ -0,0 +1,10 @@
+package repository
+
+import "context"
+
+// HealthChecker checks that a dependency is reachable
+type HealthChecker interface {
+	Name() string
+	// Check returns nil when the dependency works. It must give up when ctx is done
+	Check(ctx context.Context) error
+}
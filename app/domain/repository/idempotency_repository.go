@@ -0,0 +1,18 @@
+package repository
+
+import (
+	"context"
+	"github.com/jpdel518/go-ent/domain/model"
+	"time"
+)
+
+type IdempotencyRepository interface {
+	// Reserve creates the pending key and returns it with true. When the scope already has the key which has not expired,
+	// the existing one is returned with false instead
+	Reserve(ctx context.Context, k *model.IdempotencyKey) (*model.IdempotencyKey, bool, error)
+	// Complete stores the response of the key
+	Complete(ctx context.Context, id int, code int, contentType string, body []byte) error
+	Delete(ctx context.Context, id int) error
+	// DeleteExpired deletes the keys which expired before now and returns the number of them
+	DeleteExpired(ctx context.Context, now time.Time) (int, error)
+}
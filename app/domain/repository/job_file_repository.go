@@ -0,0 +1,11 @@
+package repository
+
+import (
+	"context"
+	"io"
+)
+
+type JobFileRepository interface {
+	Upload(ctx context.Context, key string, r io.Reader) (string, error)
+	Download(ctx context.Context, key string) (io.ReadCloser, error)
+}
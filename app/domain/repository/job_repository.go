@@ -0,0 +1,20 @@
+package repository
+
+import (
+	"context"
+	"github.com/jpdel518/go-ent/domain/model"
+)
+
+type JobRepository interface {
+	GetByID(ctx context.Context, id int) (*model.Job, error)
+	Create(ctx context.Context, j *model.Job) (*model.Job, error)
+	// Claim marks the oldest queued job as running and returns it. It returns nil when no job is queued.
+	Claim(ctx context.Context) (*model.Job, error)
+	UpdateProgress(ctx context.Context, id int, progress int) error
+	// Finish stores the outcome of a running job. It returns false when the job is not running anymore.
+	Finish(ctx context.Context, j *model.Job) (bool, error)
+	// Cancel marks a queued or running job as canceled. It returns false when the job has already finished.
+	Cancel(ctx context.Context, id int) (bool, error)
+	// Requeue puts running jobs back to the queue and returns the number of them
+	Requeue(ctx context.Context, ids ...int) (int, error)
+}
@@ -0,0 +1,11 @@
+package repository
+
+import (
+	"context"
+	"github.com/jpdel518/go-ent/domain/model"
+)
+
+// MailSender delivers mails from the address of the service
+type MailSender interface {
+	Send(ctx context.Context, m *model.Mail) error
+}
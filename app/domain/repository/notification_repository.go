@@ -0,0 +1,26 @@
+package repository
+
+import (
+	"context"
+	"github.com/jpdel518/go-ent/domain/model"
+	"time"
+)
+
+type NotificationRepository interface {
+	// GetPreferences returns the preferences of the user, ErrNotFound when there is no such user
+	GetPreferences(ctx context.Context, userID int) (*model.NotificationPreferences, error)
+	UpdatePreferences(ctx context.Context, p *model.NotificationPreferences) error
+
+	// Create queues the notification. A notification of the same event, user and kind is kept as it is
+	Create(ctx context.Context, n *model.Notification) error
+	// FetchDue returns pending notifications whose next attempt time has come
+	FetchDue(ctx context.Context, num int) ([]*model.Notification, error)
+	MarkSent(ctx context.Context, id int) error
+	// MarkAttemptFailed records a failed attempt. The notification gives up when retryAt is nil
+	MarkAttemptFailed(ctx context.Context, id int, cause error, retryAt *time.Time) error
+}
+
+// NotificationRenderer renders the mail of a notification from the templates of the language
+type NotificationRenderer interface {
+	Render(kind model.NotificationKind, language string, data *model.NotificationData) (*model.Mail, error)
+}
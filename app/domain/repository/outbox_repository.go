@@ -0,0 +1,17 @@
+package repository
+
+import (
+	"context"
+	"github.com/jpdel518/go-ent/domain/event"
+	"time"
+)
+
+type OutboxRepository interface {
+	// FetchPending returns the undelivered events which are due in the order they occurred.
+	// The events behind an event of their aggregate which waits for a retry are left out
+	FetchPending(ctx context.Context, num int) ([]*event.Event, error)
+	MarkDelivered(ctx context.Context, id int) error
+	// MarkFailed records the failure and postpones the next delivery until retryAt.
+	// The event is given up as a dead letter when retryAt is nil
+	MarkFailed(ctx context.Context, id int, cause error, retryAt *time.Time) error
+}
@@ -0,0 +1,12 @@
+package repository
+
+import (
+	"context"
+	"github.com/jpdel518/go-ent/domain/model"
+)
+
+// RateLimitStore keeps the token buckets. A store shared by the instances makes the limits hold across them
+type RateLimitStore interface {
+	// Take takes a token from the bucket of the key, which is created full, and tells whether there was one
+	Take(ctx context.Context, key string, limit model.RateLimit) (*model.RateLimitDecision, error)
+}
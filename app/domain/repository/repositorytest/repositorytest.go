@@ -0,0 +1,616 @@
+// Package repositorytest is the contract that every implementation of the repositories must pass,
+// so that the usecases behave the same on the database and on the in-memory implementations
+package repositorytest
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"github.com/jpdel518/go-ent/domain/model"
+	"github.com/jpdel518/go-ent/domain/repository"
+	"github.com/jpdel518/go-ent/tenant"
+	"mime/multipart"
+	"sort"
+	"testing"
+	"time"
+)
+
+// Repositories are the user and the car repositories of one empty store
+type Repositories struct {
+	Users repository.UserRepository
+	Cars  repository.CarRepository
+	// Context is passed to the repositories, such as the one of the tenant of the store. It is context.Background() when nil
+	Context context.Context
+}
+
+func (r Repositories) ctx() context.Context {
+	if r.Context == nil {
+		return context.Background()
+	}
+	return r.Context
+}
+
+// TestRepositories runs the contract of the user and the car repositories.
+// newRepositories is called for each case and must return repositories without any user or car
+func TestRepositories(t *testing.T, newRepositories func(t *testing.T) Repositories) {
+	for _, c := range []struct {
+		name string
+		run  func(t *testing.T, r Repositories)
+	}{
+		{"UserNotFound", testUserNotFound},
+		{"CreateAndGetUser", testCreateAndGetUser},
+		{"UniqueEmail", testUniqueEmail},
+		{"NormalizedEmail", testNormalizedEmail},
+		{"VerifyEmail", testVerifyEmail},
+		{"UpdateUser", testUpdateUser},
+		{"Avatar", testAvatar},
+		{"DeleteUser", testDeleteUser},
+		{"FetchUsers", testFetchUsers},
+		{"ReplaceCars", testReplaceCars},
+		{"TransferCars", testTransferCars},
+		{"MissingCar", testMissingCar},
+		{"UpsertUsers", testUpsertUsers},
+		{"CarNotFound", testCarNotFound},
+		{"Cars", testCars},
+	} {
+		c := c
+		t.Run(c.name, func(t *testing.T) {
+			c.run(t, newRepositories(t))
+		})
+	}
+}
+
+// TestUserFileRepository runs the contract of the avatar storage.
+// newRepository is called for each case and must return a storage without any avatar
+func TestUserFileRepository(t *testing.T, newRepository func(t *testing.T) repository.UserFileRepository) {
+	ctx := tenant.NewContext(context.Background(), 1)
+
+	t.Run("Avatar", func(t *testing.T) {
+		r := newRepository(t)
+		created, err := r.Create(ctx, 1, newFile("avatar"), &multipart.FileHeader{Filename: "a.png", Size: 6})
+		if err != nil {
+			t.Fatalf("Create: %v", err)
+		}
+		if got := r.GetURLByUser(ctx, 1); got != created {
+			t.Errorf("GetURLByUser = %q, want %q", got, created)
+		}
+
+		updated, err := r.Update(ctx, 1, newFile("new avatar"), &multipart.FileHeader{Filename: "b.png", Size: 10})
+		if err != nil {
+			t.Fatalf("Update: %v", err)
+		}
+		if updated == created {
+			t.Errorf("Update returned the old URL %q", updated)
+		}
+		if got := r.GetURLByUser(ctx, 1); got != updated {
+			t.Errorf("GetURLByUser after Update = %q, want %q", got, updated)
+		}
+	})
+
+	t.Run("DeleteAvatar", func(t *testing.T) {
+		r := newRepository(t)
+		if _, err := r.Create(ctx, 1, newFile("avatar"), &multipart.FileHeader{Filename: "a.png", Size: 6}); err != nil {
+			t.Fatalf("Create: %v", err)
+		}
+		if err := r.Delete(ctx, 1); err != nil {
+			t.Fatalf("Delete: %v", err)
+		}
+		if err := r.Delete(ctx, 1); !errors.Is(err, repository.ErrNotFound) {
+			t.Errorf("Delete of the deleted avatar = %v, want ErrNotFound", err)
+		}
+	})
+
+	t.Run("Tenants", func(t *testing.T) {
+		r := newRepository(t)
+		if _, err := r.Create(ctx, 1, newFile("avatar"), &multipart.FileHeader{Filename: "a.png", Size: 6}); err != nil {
+			t.Fatalf("Create: %v", err)
+		}
+		// the user 1 of another tenant has no avatar
+		other := tenant.NewContext(context.Background(), 2)
+		if got := r.GetURLByUser(other, 1); got != "" {
+			t.Errorf("GetURLByUser of another tenant = %q, want none", got)
+		}
+		if err := r.Delete(other, 1); !errors.Is(err, repository.ErrNotFound) {
+			t.Errorf("Delete of another tenant = %v, want ErrNotFound", err)
+		}
+		if _, err := r.Create(context.Background(), 1, newFile("avatar"), &multipart.FileHeader{Filename: "a.png", Size: 6}); !errors.Is(err, tenant.ErrMissing) {
+			t.Errorf("Create without a tenant = %v, want tenant.ErrMissing", err)
+		}
+	})
+}
+
+func testUserNotFound(t *testing.T, r Repositories) {
+	ctx := r.ctx()
+	if _, err := r.Users.GetByID(ctx, 1); !errors.Is(err, repository.ErrNotFound) {
+		t.Errorf("GetByID = %v, want ErrNotFound", err)
+	}
+	if _, err := r.Users.Update(ctx, &model.User{ID: 1, FirstName: "Taro", LastName: "Yamada", Email: "taro@example.com"}); !errors.Is(err, repository.ErrNotFound) {
+		t.Errorf("Update = %v, want ErrNotFound", err)
+	}
+	if err := r.Users.Delete(ctx, 1); !errors.Is(err, repository.ErrNotFound) {
+		t.Errorf("Delete = %v, want ErrNotFound", err)
+	}
+	if err := r.Users.UpdateAvatar(ctx, 1, "avatar.png"); !errors.Is(err, repository.ErrNotFound) {
+		t.Errorf("UpdateAvatar = %v, want ErrNotFound", err)
+	}
+}
+
+func testCreateAndGetUser(t *testing.T, r Repositories) {
+	ctx := r.ctx()
+	created := createUser(t, r, "taro@example.com")
+	if created.ID == 0 {
+		t.Fatal("Create did not set the id")
+	}
+
+	got, err := r.Users.GetByID(ctx, created.ID)
+	if err != nil {
+		t.Fatalf("GetByID: %v", err)
+	}
+	if got.ID != created.ID || got.FirstName != "Taro" || got.LastName != "Yamada" || got.Email != "taro@example.com" || got.Age != 20 {
+		t.Errorf("GetByID = %+v, want the created user", got)
+	}
+	if len(got.CarIDs) != 0 || len(got.Cars) != 0 {
+		t.Errorf("GetByID cars = %v, want none", got.CarIDs)
+	}
+	if n, err := r.Users.Count(ctx); err != nil || n != 1 {
+		t.Errorf("Count = %d, %v, want 1", n, err)
+	}
+}
+
+func testUniqueEmail(t *testing.T, r Repositories) {
+	ctx := r.ctx()
+	taro := createUser(t, r, "taro@example.com")
+	hanako := createUser(t, r, "hanako@example.com")
+
+	_, err := r.Users.Create(ctx, &model.User{FirstName: "Jiro", LastName: "Yamada", Email: "taro@example.com"})
+	if !errors.Is(err, repository.ErrDuplicateEmail) {
+		t.Errorf("Create with a registered e-mail = %v, want ErrDuplicateEmail", err)
+	}
+	if n, _ := r.Users.Count(ctx); n != 2 {
+		t.Errorf("Count = %d, want 2", n)
+	}
+
+	hanako.Email = taro.Email
+	if _, err := r.Users.Update(ctx, hanako); !errors.Is(err, repository.ErrDuplicateEmail) {
+		t.Errorf("Update to a registered e-mail = %v, want ErrDuplicateEmail", err)
+	}
+	got, err := r.Users.GetByID(ctx, hanako.ID)
+	if err != nil {
+		t.Fatalf("GetByID: %v", err)
+	}
+	if got.Email != "hanako@example.com" {
+		t.Errorf("e-mail after the failed Update = %q, want hanako@example.com", got.Email)
+	}
+
+	// keeping the own e-mail is not a conflict
+	taro.FirstName = "Taro2"
+	if _, err := r.Users.Update(ctx, taro); err != nil {
+		t.Errorf("Update with the own e-mail: %v", err)
+	}
+}
+
+func testNormalizedEmail(t *testing.T, r Repositories) {
+	ctx := r.ctx()
+	taro := createUser(t, r, " Taro@Example.com ")
+	if taro.Email != "taro@example.com" {
+		t.Errorf("created e-mail = %q, want taro@example.com", taro.Email)
+	}
+	if got := getUser(t, r, taro.ID); got.Email != "taro@example.com" {
+		t.Errorf("stored e-mail = %q, want taro@example.com", got.Email)
+	}
+
+	// the e-mails are unique regardless of the case
+	_, err := r.Users.Create(ctx, &model.User{FirstName: "Jiro", LastName: "Yamada", Email: "TARO@example.com"})
+	if !errors.Is(err, repository.ErrDuplicateEmail) {
+		t.Errorf("Create with the e-mail in upper case = %v, want ErrDuplicateEmail", err)
+	}
+	hanako := createUser(t, r, "hanako@example.com")
+	hanako.Email = "Taro@example.COM"
+	if _, err := r.Users.Update(ctx, hanako); !errors.Is(err, repository.ErrDuplicateEmail) {
+		t.Errorf("Update to the e-mail in upper case = %v, want ErrDuplicateEmail", err)
+	}
+
+	// and an import finds the user by it
+	created, err := r.Users.Upsert(ctx, []*model.User{{FirstName: "Taro2", LastName: "Yamada", Email: "TARO@EXAMPLE.COM"}})
+	if err != nil {
+		t.Fatalf("Upsert: %v", err)
+	}
+	if created[0] {
+		t.Error("Upsert created a user for the e-mail in upper case")
+	}
+	if got := getUser(t, r, taro.ID); got.FirstName != "Taro2" {
+		t.Errorf("upserted user = %+v, want Taro2", got)
+	}
+	if n, _ := r.Users.Count(ctx); n != 2 {
+		t.Errorf("Count = %d, want 2", n)
+	}
+}
+
+func testVerifyEmail(t *testing.T, r Repositories) {
+	ctx := r.ctx()
+	u := createUser(t, r, "taro@example.com")
+	if got := getUser(t, r, u.ID); got.VerifiedAt != nil {
+		t.Errorf("VerifiedAt of a new user = %v, want nil", got.VerifiedAt)
+	}
+
+	if err := r.Users.Verify(ctx, u.ID, "jiro@example.com", registeredAt); !errors.Is(err, repository.ErrNotFound) {
+		t.Errorf("Verify of another e-mail = %v, want ErrNotFound", err)
+	}
+	if err := r.Users.Verify(ctx, u.ID+100, "taro@example.com", registeredAt); !errors.Is(err, repository.ErrNotFound) {
+		t.Errorf("Verify of another user = %v, want ErrNotFound", err)
+	}
+	if err := r.Users.Verify(ctx, u.ID, "Taro@example.com", registeredAt); err != nil {
+		t.Fatalf("Verify: %v", err)
+	}
+	if got := getUser(t, r, u.ID); got.VerifiedAt == nil || !got.VerifiedAt.Equal(registeredAt) {
+		t.Errorf("VerifiedAt = %v, want %v", got.VerifiedAt, registeredAt)
+	}
+
+	// the e-mail stays verified while it is kept, in any case
+	u.FirstName, u.Email = "Taro2", "TARO@example.com"
+	if _, err := r.Users.Update(ctx, u); err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+	if got := getUser(t, r, u.ID); got.VerifiedAt == nil {
+		t.Error("VerifiedAt after keeping the e-mail = nil, want it kept")
+	}
+	u.Email = "jiro@example.com"
+	if _, err := r.Users.Update(ctx, u); err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+	if got := getUser(t, r, u.ID); got.VerifiedAt != nil {
+		t.Errorf("VerifiedAt after changing the e-mail = %v, want nil", got.VerifiedAt)
+	}
+
+	if err := r.Users.RequestVerification(ctx, u.ID); err != nil {
+		t.Errorf("RequestVerification: %v", err)
+	}
+	if err := r.Users.RequestVerification(ctx, u.ID+100); !errors.Is(err, repository.ErrNotFound) {
+		t.Errorf("RequestVerification of another user = %v, want ErrNotFound", err)
+	}
+}
+
+func testUpdateUser(t *testing.T, r Repositories) {
+	ctx := r.ctx()
+	u := createUser(t, r, "taro@example.com")
+	u.FirstName, u.LastName, u.Email, u.Age = "Jiro", "Suzuki", "jiro@example.com", 30
+	if _, err := r.Users.Update(ctx, u); err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+
+	got, err := r.Users.GetByID(ctx, u.ID)
+	if err != nil {
+		t.Fatalf("GetByID: %v", err)
+	}
+	if got.FirstName != "Jiro" || got.LastName != "Suzuki" || got.Email != "jiro@example.com" || got.Age != 30 {
+		t.Errorf("GetByID = %+v, want the updated user", got)
+	}
+}
+
+func testAvatar(t *testing.T, r Repositories) {
+	ctx := r.ctx()
+	u := createUser(t, r, "taro@example.com")
+	if err := r.Users.UpdateAvatar(ctx, u.ID, "avatar.png"); err != nil {
+		t.Fatalf("UpdateAvatar: %v", err)
+	}
+
+	// Update keeps the avatar when no new one is given
+	u.Avatar = ""
+	if _, err := r.Users.Update(ctx, u); err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+	if got := getUser(t, r, u.ID); got.Avatar != "avatar.png" {
+		t.Errorf("avatar after Update = %q, want avatar.png", got.Avatar)
+	}
+
+	if err := r.Users.UpdateAvatar(ctx, u.ID, ""); err != nil {
+		t.Fatalf("UpdateAvatar: %v", err)
+	}
+	if got := getUser(t, r, u.ID); got.Avatar != "" {
+		t.Errorf("avatar after clearing = %q, want none", got.Avatar)
+	}
+}
+
+func testDeleteUser(t *testing.T, r Repositories) {
+	ctx := r.ctx()
+	c := createCar(t, r, "Leaf")
+	u := createUser(t, r, "taro@example.com", c.ID)
+	if err := r.Users.Delete(ctx, u.ID); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if _, err := r.Users.GetByID(ctx, u.ID); !errors.Is(err, repository.ErrNotFound) {
+		t.Errorf("GetByID of the deleted user = %v, want ErrNotFound", err)
+	}
+
+	// the car stays without the owner and can be given to another user
+	if _, err := r.Cars.GetByID(ctx, c.ID); err != nil {
+		t.Errorf("GetByID of the car of the deleted user: %v", err)
+	}
+	other := createUser(t, r, "hanako@example.com", c.ID)
+	assertCars(t, r, other.ID, c.ID)
+}
+
+func testFetchUsers(t *testing.T, r Repositories) {
+	ctx := r.ctx()
+	c := createCar(t, r, "Leaf")
+	var ids []int
+	for i := 0; i < 5; i++ {
+		var carIDs []int
+		if i == 0 {
+			carIDs = []int{c.ID}
+		}
+		ids = append(ids, createUser(t, r, fmt.Sprintf("user%d@example.com", i), carIDs...).ID)
+	}
+
+	users, err := r.Users.Fetch(ctx, 3)
+	if err != nil {
+		t.Fatalf("Fetch: %v", err)
+	}
+	assertIDs(t, "Fetch", users, ids[:3])
+	if len(users) > 0 && !equalInts(users[0].CarIDs, []int{c.ID}) {
+		t.Errorf("Fetch cars = %v, want [%d]", users[0].CarIDs, c.ID)
+	}
+
+	users, err = r.Users.FetchAfter(ctx, ids[1], 2)
+	if err != nil {
+		t.Fatalf("FetchAfter: %v", err)
+	}
+	assertIDs(t, "FetchAfter", users, ids[2:4])
+
+	users, err = r.Users.FetchAfter(ctx, ids[4], 2)
+	if err != nil {
+		t.Fatalf("FetchAfter: %v", err)
+	}
+	assertIDs(t, "FetchAfter the last", users, nil)
+}
+
+func testReplaceCars(t *testing.T, r Repositories) {
+	ctx := r.ctx()
+	leaf, prius, note := createCar(t, r, "Leaf"), createCar(t, r, "Prius"), createCar(t, r, "Note")
+	u := createUser(t, r, "taro@example.com", leaf.ID, prius.ID)
+	assertCars(t, r, u.ID, leaf.ID, prius.ID)
+
+	// the cars are replaced rather than added
+	u.CarIDs = []int{prius.ID, note.ID}
+	if _, err := r.Users.Update(ctx, u); err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+	assertCars(t, r, u.ID, prius.ID, note.ID)
+
+	// no cars release them all
+	u.CarIDs = []int{}
+	if _, err := r.Users.Update(ctx, u); err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+	assertCars(t, r, u.ID)
+	if _, err := r.Cars.GetByID(ctx, leaf.ID); err != nil {
+		t.Errorf("GetByID of the released car: %v", err)
+	}
+}
+
+func testTransferCars(t *testing.T, r Repositories) {
+	ctx := r.ctx()
+	leaf, prius := createCar(t, r, "Leaf"), createCar(t, r, "Prius")
+	taro := createUser(t, r, "taro@example.com", leaf.ID, prius.ID)
+
+	// a car has one owner, so giving it to another user takes it from the owner
+	hanako := createUser(t, r, "hanako@example.com", leaf.ID)
+	assertCars(t, r, taro.ID, prius.ID)
+	assertCars(t, r, hanako.ID, leaf.ID)
+
+	taro.CarIDs = []int{leaf.ID, prius.ID}
+	if _, err := r.Users.Update(ctx, taro); err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+	assertCars(t, r, taro.ID, leaf.ID, prius.ID)
+	assertCars(t, r, hanako.ID)
+}
+
+func testMissingCar(t *testing.T, r Repositories) {
+	ctx := r.ctx()
+	leaf := createCar(t, r, "Leaf")
+
+	_, err := r.Users.Create(ctx, &model.User{FirstName: "Taro", LastName: "Yamada", Email: "taro@example.com", CarIDs: []int{leaf.ID, leaf.ID + 100}})
+	if !errors.Is(err, repository.ErrNotFound) {
+		t.Errorf("Create with a missing car = %v, want ErrNotFound", err)
+	}
+	if n, _ := r.Users.Count(ctx); n != 0 {
+		t.Errorf("Count after the failed Create = %d, want 0", n)
+	}
+
+	// nothing changes when the update fails
+	u := createUser(t, r, "hanako@example.com", leaf.ID)
+	u.FirstName = "Changed"
+	u.CarIDs = []int{leaf.ID + 100}
+	if _, err := r.Users.Update(ctx, u); !errors.Is(err, repository.ErrNotFound) {
+		t.Errorf("Update with a missing car = %v, want ErrNotFound", err)
+	}
+	if got := getUser(t, r, u.ID); got.FirstName != "Taro" {
+		t.Errorf("first name after the failed Update = %q, want Taro", got.FirstName)
+	}
+	assertCars(t, r, u.ID, leaf.ID)
+}
+
+func testUpsertUsers(t *testing.T, r Repositories) {
+	ctx := r.ctx()
+	leaf, prius := createCar(t, r, "Leaf"), createCar(t, r, "Prius")
+	taro := createUser(t, r, "taro@example.com", leaf.ID)
+	hanako := createUser(t, r, "hanako@example.com", prius.ID)
+
+	us := []*model.User{
+		// the cars are kept when CarIDs is nil
+		{FirstName: "Taro2", LastName: "Yamada", Email: "taro@example.com", Age: 21},
+		{FirstName: "Jiro", LastName: "Suzuki", Email: "jiro@example.com", Age: 30, CarIDs: []int{leaf.ID}},
+		// and released when it is empty
+		{FirstName: "Hanako", LastName: "Sato", Email: "hanako@example.com", CarIDs: []int{}},
+	}
+	created, err := r.Users.Upsert(ctx, us)
+	if err != nil {
+		t.Fatalf("Upsert: %v", err)
+	}
+	if fmt.Sprint(created) != "[false true false]" {
+		t.Errorf("Upsert created = %v, want [false true false]", created)
+	}
+	if us[0].ID != taro.ID || us[1].ID == 0 || us[2].ID != hanako.ID {
+		t.Errorf("Upsert ids = %d %d %d, want %d, new, %d", us[0].ID, us[1].ID, us[2].ID, taro.ID, hanako.ID)
+	}
+	if got := getUser(t, r, taro.ID); got.FirstName != "Taro2" || got.Age != 21 {
+		t.Errorf("upserted user = %+v, want Taro2 aged 21", got)
+	}
+	assertCars(t, r, taro.ID)
+	assertCars(t, r, us[1].ID, leaf.ID)
+	assertCars(t, r, hanako.ID)
+	if n, _ := r.Users.Count(ctx); n != 3 {
+		t.Errorf("Count = %d, want 3", n)
+	}
+}
+
+func testCarNotFound(t *testing.T, r Repositories) {
+	ctx := r.ctx()
+	if _, err := r.Cars.GetByID(ctx, 1); !errors.Is(err, repository.ErrNotFound) {
+		t.Errorf("GetByID = %v, want ErrNotFound", err)
+	}
+	if err := r.Cars.Update(ctx, &model.Car{ID: 1, Name: "Leaf", Model: "Nissan", RegisteredAt: registeredAt}); !errors.Is(err, repository.ErrNotFound) {
+		t.Errorf("Update = %v, want ErrNotFound", err)
+	}
+	if err := r.Cars.Delete(ctx, 1); !errors.Is(err, repository.ErrNotFound) {
+		t.Errorf("Delete = %v, want ErrNotFound", err)
+	}
+}
+
+func testCars(t *testing.T, r Repositories) {
+	ctx := r.ctx()
+	leaf, prius, note := createCar(t, r, "Leaf"), createCar(t, r, "Prius"), createCar(t, r, "Note")
+
+	got, err := r.Cars.GetByID(ctx, leaf.ID)
+	if err != nil {
+		t.Fatalf("GetByID: %v", err)
+	}
+	if got.Name != "Leaf" || got.Model != "Model" || !got.RegisteredAt.Equal(registeredAt) {
+		t.Errorf("GetByID = %+v, want the created car", got)
+	}
+
+	leaf.Model = "Nissan"
+	if err := r.Cars.Update(ctx, leaf); err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+	if got, err := r.Cars.GetByID(ctx, leaf.ID); err != nil || got.Model != "Nissan" {
+		t.Errorf("GetByID after Update = %+v, %v, want the model Nissan", got, err)
+	}
+
+	cars, err := r.Cars.FetchByIDs(ctx, []int{note.ID, leaf.ID, note.ID + 100})
+	if err != nil {
+		t.Fatalf("FetchByIDs: %v", err)
+	}
+	ids := make([]int, 0, len(cars))
+	for _, c := range cars {
+		ids = append(ids, c.ID)
+	}
+	sort.Ints(ids)
+	if !equalInts(ids, []int{leaf.ID, note.ID}) {
+		t.Errorf("FetchByIDs = %v, want %v without the missing car", ids, []int{leaf.ID, note.ID})
+	}
+
+	if cars, err := r.Cars.Fetch(ctx, 2); err != nil || len(cars) != 2 {
+		t.Errorf("Fetch(2) = %d cars, %v, want 2", len(cars), err)
+	}
+
+	// deleting the car of a user takes it from the user
+	u := createUser(t, r, "taro@example.com", leaf.ID, prius.ID)
+	if err := r.Cars.Delete(ctx, leaf.ID); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if _, err := r.Cars.GetByID(ctx, leaf.ID); !errors.Is(err, repository.ErrNotFound) {
+		t.Errorf("GetByID of the deleted car = %v, want ErrNotFound", err)
+	}
+	assertCars(t, r, u.ID, prius.ID)
+}
+
+// registeredAt is in UTC without the sub-seconds, which MySQL drops
+var registeredAt = time.Date(2020, 4, 1, 9, 0, 0, 0, time.UTC)
+
+func createUser(t *testing.T, r Repositories, email string, carIDs ...int) *model.User {
+	t.Helper()
+	if carIDs == nil {
+		carIDs = []int{}
+	}
+	u, err := r.Users.Create(r.ctx(), &model.User{FirstName: "Taro", LastName: "Yamada", Email: email, Age: 20, CarIDs: carIDs})
+	if err != nil {
+		t.Fatalf("Create user %s: %v", email, err)
+	}
+	return u
+}
+
+func getUser(t *testing.T, r Repositories, id int) *model.User {
+	t.Helper()
+	u, err := r.Users.GetByID(r.ctx(), id)
+	if err != nil {
+		t.Fatalf("GetByID user %d: %v", id, err)
+	}
+	return u
+}
+
+func createCar(t *testing.T, r Repositories, name string) *model.Car {
+	t.Helper()
+	c := &model.Car{Name: name, Model: "Model", RegisteredAt: registeredAt}
+	if err := r.Cars.Create(r.ctx(), c); err != nil {
+		t.Fatalf("Create car %s: %v", name, err)
+	}
+	if c.ID == 0 {
+		t.Fatalf("Create car %s did not set the id", name)
+	}
+	return c
+}
+
+// assertCars checks the cars of the user in any order
+func assertCars(t *testing.T, r Repositories, userID int, want ...int) {
+	t.Helper()
+	u := getUser(t, r, userID)
+	got := append([]int{}, u.CarIDs...)
+	sort.Ints(got)
+	sort.Ints(want)
+	if !equalInts(got, want) {
+		t.Errorf("cars of user %d = %v, want %v", userID, got, want)
+	}
+	// the details of the cars are left to the usecase
+	if len(u.Cars) != 0 {
+		t.Errorf("user %d has the details of %d cars, want the ids only", userID, len(u.Cars))
+	}
+}
+
+func assertIDs(t *testing.T, name string, users []*model.User, want []int) {
+	t.Helper()
+	got := make([]int, 0, len(users))
+	for _, u := range users {
+		got = append(got, u.ID)
+	}
+	if !equalInts(got, want) {
+		t.Errorf("%s = %v, want %v", name, got, want)
+	}
+}
+
+func equalInts(a []int, b []int) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// file is a multipart.File in memory
+type file struct {
+	*bytes.Reader
+}
+
+func (file) Close() error {
+	return nil
+}
+
+func newFile(s string) multipart.File {
+	return file{bytes.NewReader([]byte(s))}
+}
@@ -0,0 +1,12 @@
+package repository
+
+import (
+	"context"
+	"github.com/jpdel518/go-ent/domain/model"
+)
+
+// SearchIndex finds the users and the cars by their words
+type SearchIndex interface {
+	// Search returns all the hits of the query from the best one
+	Search(ctx context.Context, q *model.SearchQuery) ([]*model.SearchHit, error)
+}
@@ -0,0 +1,13 @@
+package repository
+
+import (
+	"context"
+	"github.com/jpdel518/go-ent/domain/model"
+)
+
+type TenantRepository interface {
+	GetBySlug(ctx context.Context, slug string) (*model.Tenant, error)
+	GetByAPIKey(ctx context.Context, key string) (*model.Tenant, error)
+	// Create registers the tenant with the API key, or without one when it is empty
+	Create(ctx context.Context, t *model.Tenant, apiKey string) error
+}
@@ -3,12 +3,23 @@ package repository
 import (
 	"context"
 	"github.com/jpdel518/go-ent/domain/model"
+	"time"
 )
 
 type UserRepository interface {
 	Fetch(ctx context.Context, num int) (res []*model.User, err error)
+	FetchAfter(ctx context.Context, afterID int, num int) (res []*model.User, err error)
 	GetByID(ctx context.Context, id int) (*model.User, error)
 	Create(ctx context.Context, u *model.User) (*model.User, error)
 	Update(ctx context.Context, u *model.User) (*model.User, error)
 	Delete(ctx context.Context, id int) error
+	Count(ctx context.Context) (int, error)
+	UpdateAvatar(ctx context.Context, id int, avatar string) error
+	Upsert(ctx context.Context, us []*model.User) (created []bool, err error)
+	// Verify marks the e-mail of the user as verified at the time, unless the user has another e-mail by now.
+	// ErrNotFound is returned when there is no user of the id with the e-mail
+	Verify(ctx context.Context, id int, email string, at time.Time) error
+	// RequestVerification asks for the link which verifies the current e-mail of the user to be mailed.
+	// ErrNotFound is returned when there is no user of the id
+	RequestVerification(ctx context.Context, id int) error
 }
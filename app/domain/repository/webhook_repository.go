@@ -0,0 +1,36 @@
+package repository
+
+import (
+	"context"
+	"github.com/jpdel518/go-ent/domain/event"
+	"github.com/jpdel518/go-ent/domain/model"
+	"time"
+)
+
+type WebhookRepository interface {
+	Fetch(ctx context.Context, num int) ([]*model.WebhookSubscription, error)
+	GetByID(ctx context.Context, id int) (*model.WebhookSubscription, error)
+	Create(ctx context.Context, w *model.WebhookSubscription) (*model.WebhookSubscription, error)
+	// Update replaces the subscription. Deactivating it fails its pending deliveries
+	Update(ctx context.Context, w *model.WebhookSubscription) (*model.WebhookSubscription, error)
+	Delete(ctx context.Context, id int) error
+	FetchActive(ctx context.Context) ([]*model.WebhookSubscription, error)
+
+	// CreateDeliveries queues the event for the subscriptions. Deliveries which already exist are kept as they are
+	CreateDeliveries(ctx context.Context, e *event.Event, subscriptionIDs []int) error
+	// FetchDueDeliveries returns pending deliveries of the active subscriptions whose next attempt time has come
+	FetchDueDeliveries(ctx context.Context, num int) ([]*model.WebhookDelivery, error)
+	FetchDeliveries(ctx context.Context, subscriptionID int, num int) ([]*model.WebhookDelivery, error)
+	MarkDelivered(ctx context.Context, id int, code int) error
+	// MarkAttemptFailed records a failed attempt. The delivery gives up when retryAt is nil
+	MarkAttemptFailed(ctx context.Context, id int, code int, cause error, retryAt *time.Time) error
+	// RecordResult resets or counts up the failures in a row of the subscription.
+	// The subscription is disabled when the failures reach the threshold and true is returned.
+	RecordResult(ctx context.Context, subscriptionID int, succeeded bool, threshold int) (bool, error)
+}
+
+// WebhookSender sends a signed event to a webhook endpoint
+type WebhookSender interface {
+	// Send returns the response status code, which is 0 when no response is received
+	Send(ctx context.Context, url string, secret string, d *model.WebhookDelivery) (int, error)
+}
@@ -0,0 +1,1028 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"entgo.io/ent/dialect"
+	entsql "entgo.io/ent/dialect/sql"
+	"errors"
+	"flag"
+	"fmt"
+	"github.com/jpdel518/go-ent/config"
+	"github.com/jpdel518/go-ent/domain/model"
+	"github.com/jpdel518/go-ent/domain/repository"
+	entnotification "github.com/jpdel518/go-ent/ent/notification"
+	"github.com/jpdel518/go-ent/ent/outboxevent"
+	"github.com/jpdel518/go-ent/infrastructure/mail"
+	"github.com/jpdel518/go-ent/infrastructure/memory"
+	"github.com/jpdel518/go-ent/infrastructure/notification"
+	"github.com/jpdel518/go-ent/infrastructure/rdb"
+	"github.com/jpdel518/go-ent/infrastructure/rdb/seed"
+	"github.com/jpdel518/go-ent/metrics"
+	"github.com/jpdel518/go-ent/presentation/handler"
+	"github.com/jpdel518/go-ent/tenant"
+	"github.com/jpdel518/go-ent/tracing"
+	"github.com/jpdel518/go-ent/usecase"
+	_ "github.com/mattn/go-sqlite3"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"golang.org/x/exp/slog"
+	"io"
+	"log"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// go test -run TestE2E -update rewrites the golden files with the current responses
+var update = flag.Bool("update", false, "rewrite the golden files of the end-to-end tests")
+
+func TestMain(m *testing.M) {
+	flag.Parse()
+	// the statements of the debug client and the request logs are noise here
+	log.SetOutput(io.Discard)
+	slog.SetDefault(slog.New(slog.NewTextHandler(io.Discard)))
+	// the tracers of the packages are bound to the first provider, so it is set once for every test
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	otel.SetTracerProvider(tracing.NewProvider(spans, ""))
+	os.Exit(m.Run())
+}
+
+// spans records the spans of every request, which TestE2ETracing finds by the trace id
+var spans = tracetest.NewSpanRecorder()
+
+// databases names a new in-memory database for each harness
+var databases atomic.Uint64
+
+// harness is the whole application of main on SQLite and the storage in memory, without the workers and the servers running
+type harness struct {
+	components *components
+	handler    http.Handler
+	files      *gatedFiles
+	checker    *storageChecker
+	mailbox    *mail.Mailbox
+	// tenant is the default tenant, which the requests without credentials belong to
+	tenant int
+}
+
+// newHarness builds the application with the default config without rate limits, changed by configure
+func newHarness(t *testing.T, configure ...func(cfg *config.Config)) *harness {
+	t.Helper()
+	cfg := config.Default()
+	cfg.RateLimit.Default = model.RateLimit{}
+	cfg.RateLimit.Routes = nil
+	cfg.RateLimit.Address = model.RateLimit{}
+	cfg.Verification.Secret = "e2e-verification-secret"
+	for _, c := range configure {
+		c(&cfg)
+	}
+
+	driver, err := entsql.Open(dialect.SQLite, fmt.Sprintf("file:e2e%d?mode=memory&cache=shared&_fk=1", databases.Add(1)))
+	if err != nil {
+		t.Fatalf("failed opening sqlite: %v", err)
+	}
+	renderer, err := notification.NewRenderer(cfg.Notifications.DefaultLanguage)
+	if err != nil {
+		t.Fatalf("failed parsing notification templates: %v", err)
+	}
+	store := memory.NewStore()
+	h := &harness{
+		files:   &gatedFiles{UserFileRepository: memory.NewUserFileRepository(store)},
+		checker: &storageChecker{},
+		mailbox: mail.NewMailbox(),
+	}
+	h.components = newComponents(&cfg, driver, storage{
+		userFiles: h.files,
+		jobFiles:  memory.NewJobFileRepository(store),
+		checker:   h.checker,
+	}, h.mailbox, renderer)
+	t.Cleanup(func() {
+		_ = h.components.client.Close()
+	})
+	if err := h.components.client.Schema.Create(context.Background()); err != nil {
+		t.Fatalf("failed creating schema resources: %v", err)
+	}
+	id, ok := seed.TenantSeed(h.components.client)
+	if !ok {
+		t.Fatal("failed seeding the default tenant")
+	}
+	h.tenant = id
+	h.handler = h.components.httpServer.Handler
+	return h
+}
+
+// seedCar registers a car of the default tenant, which has no route of its own
+func (h *harness) seedCar(t *testing.T, name string, carModel string) {
+	t.Helper()
+	h.seedTenantCar(t, h.tenant, name, carModel)
+}
+
+// seedTenantCar registers a car of the tenant
+func (h *harness) seedTenantCar(t *testing.T, tenantID int, name string, carModel string) {
+	t.Helper()
+	registeredAt := time.Date(2020, 4, 1, 9, 0, 0, 0, time.UTC)
+	ctx := tenant.NewContext(context.Background(), tenantID)
+	if _, err := h.components.client.Car.Create().SetName(name).SetModel(carModel).SetRegisteredAt(registeredAt).Save(ctx); err != nil {
+		t.Fatalf("failed seeding car: %v", err)
+	}
+}
+
+// seedTenant registers a tenant with the API key
+func (h *harness) seedTenant(t *testing.T, slug string, apiKey string) int {
+	t.Helper()
+	tn := &model.Tenant{Name: slug, Slug: slug}
+	if err := rdb.NewTenantRepository(h.components.client).Create(context.Background(), tn, apiKey); err != nil {
+		t.Fatalf("failed seeding tenant: %v", err)
+	}
+	return tn.ID
+}
+
+// serve sends the request through the whole handler and compares the response with testdata/e2e/<name>.golden.
+// The golden file has the status, the Content-Type and the other headers named, and the body with the times masked
+func (h *harness) serve(t *testing.T, name string, r *http.Request, headers ...string) *httptest.ResponseRecorder {
+	t.Helper()
+	w := httptest.NewRecorder()
+	h.handler.ServeHTTP(w, r)
+	compareGolden(t, name, r, w.Result(), w.Body.Bytes(), headers)
+	return w
+}
+
+func compareGolden(t *testing.T, name string, r *http.Request, res *http.Response, body []byte, headers []string) {
+	t.Helper()
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s %s\n", r.Method, mask([]byte(r.URL.RequestURI())))
+	fmt.Fprintf(&b, "HTTP %d\n", res.StatusCode)
+	for _, k := range append([]string{"Content-Type"}, headers...) {
+		if v := res.Header.Get(k); v != "" {
+			fmt.Fprintf(&b, "%s: %s\n", k, v)
+		}
+	}
+	b.WriteString("\n")
+	var indented bytes.Buffer
+	if strings.HasPrefix(res.Header.Get("Content-Type"), "application/json") && json.Indent(&indented, body, "", "  ") == nil {
+		body = indented.Bytes()
+	}
+	b.Write(mask(body))
+	got := strings.TrimRight(b.String(), "\n") + "\n"
+
+	path := filepath.Join("testdata", "e2e", name+".golden")
+	if *update {
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(got), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("%v: run go test -run %s -update to create it", err, t.Name())
+	}
+	if got != string(want) {
+		t.Errorf("response of %s differs from %s\n--- got\n%s--- want\n%s", name, path, got, want)
+	}
+}
+
+var (
+	timePattern      = regexp.MustCompile(`\d{4}-\d{2}-\d{2}T\d{2}:\d{2}:\d{2}(\.\d+)?(Z|[+-]\d{2}:\d{2})`)
+	durationPattern  = regexp.MustCompile(`"duration_ms": \d+`)
+	sourceKeyPattern = regexp.MustCompile(`source/\d+-`)
+	tokenPattern     = regexp.MustCompile(`token=[\w.%-]+`)
+)
+
+// mask replaces the parts which change from run to run
+func mask(body []byte) []byte {
+	body = timePattern.ReplaceAll(body, []byte("<time>"))
+	body = durationPattern.ReplaceAll(body, []byte(`"duration_ms": 0`))
+	body = sourceKeyPattern.ReplaceAll(body, []byte("source/<time>-"))
+	return tokenPattern.ReplaceAll(body, []byte("token=<token>"))
+}
+
+func jsonRequest(method string, target string, body string) *http.Request {
+	r := httptest.NewRequest(method, target, strings.NewReader(body))
+	r.Header.Set("Content-Type", "application/json")
+	return r
+}
+
+// formFile is a file of a multipart body
+type formFile struct {
+	field    string
+	filename string
+	content  string
+}
+
+// multipartBody encodes the fields, given as name and value pairs, and the files
+func multipartBody(t *testing.T, fields []string, files ...formFile) ([]byte, string) {
+	t.Helper()
+	var b bytes.Buffer
+	mw := multipart.NewWriter(&b)
+	for i := 0; i+1 < len(fields); i += 2 {
+		if err := mw.WriteField(fields[i], fields[i+1]); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, f := range files {
+		w, err := mw.CreateFormFile(f.field, f.filename)
+		if err != nil {
+			t.Fatal(err)
+		}
+		_, _ = io.WriteString(w, f.content)
+	}
+	if err := mw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return b.Bytes(), mw.FormDataContentType()
+}
+
+func multipartRequest(t *testing.T, method string, target string, fields []string, files ...formFile) *http.Request {
+	t.Helper()
+	body, contentType := multipartBody(t, fields, files...)
+	r := httptest.NewRequest(method, target, bytes.NewReader(body))
+	r.Header.Set("Content-Type", contentType)
+	return r
+}
+
+// failingBody fails to be read after the prefix
+func failingBody(prefix string) io.Reader {
+	return io.MultiReader(strings.NewReader(prefix), errReader{})
+}
+
+type errReader struct{}
+
+func (errReader) Read([]byte) (int, error) {
+	return 0, errors.New("connection reset by peer")
+}
+
+// gatedFiles holds the avatar uploads while the gate is closed
+type gatedFiles struct {
+	repository.UserFileRepository
+	mu      sync.Mutex
+	gate    chan struct{}
+	waiting chan struct{}
+}
+
+// close makes the uploads wait until the returned function is called, and returns a channel notified when an upload waits
+func (f *gatedFiles) close() (<-chan struct{}, func()) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.gate = make(chan struct{})
+	f.waiting = make(chan struct{}, 1)
+	gate := f.gate
+	return f.waiting, func() { close(gate) }
+}
+
+func (f *gatedFiles) Create(ctx context.Context, id int, file multipart.File, fh *multipart.FileHeader) (string, error) {
+	f.mu.Lock()
+	gate, waiting := f.gate, f.waiting
+	f.mu.Unlock()
+	if gate != nil {
+		waiting <- struct{}{}
+		<-gate
+	}
+	return f.UserFileRepository.Create(ctx, id, file, fh)
+}
+
+// storageChecker reports err as the state of the storage
+type storageChecker struct {
+	err error
+}
+
+func (c *storageChecker) Name() string {
+	return "storage"
+}
+
+func (c *storageChecker) Check(ctx context.Context) error {
+	return c.err
+}
+
+// nonFlusher hides http.Flusher of the recorder
+type nonFlusher struct {
+	w http.ResponseWriter
+}
+
+func (n nonFlusher) Header() http.Header {
+	return n.w.Header()
+}
+
+func (n nonFlusher) Write(b []byte) (int, error) {
+	return n.w.Write(b)
+}
+
+func (n nonFlusher) WriteHeader(code int) {
+	n.w.WriteHeader(code)
+}
+
+func TestE2EUsers(t *testing.T) {
+	h := newHarness(t)
+	h.seedCar(t, "Leaf", "Nissan")
+	h.seedCar(t, "Prius", "Toyota")
+	h.seedCar(t, "Note", "Nissan")
+
+	h.serve(t, "root", httptest.NewRequest(http.MethodGet, "/", nil))
+	h.serve(t, "users/create_json", jsonRequest(http.MethodPost, "/user/create",
+		`{"first_name":"Taro","last_name":"Yamada","email":"taro@example.com","age":20,"car_ids":[1]}`))
+	h.serve(t, "users/create_multipart", multipartRequest(t, http.MethodPost, "/user/create",
+		[]string{"first_name", "Hanako", "last_name", "Sato", "email", "hanako@example.com", "age", "30", "car_ids", "[2, 3]"},
+		formFile{field: "avatar", filename: "hanako.png", content: "png"}))
+	h.serve(t, "users/fetch", httptest.NewRequest(http.MethodGet, "/user/fetch?num=10&cars=all", nil))
+	h.serve(t, "users/fetch_after", httptest.NewRequest(http.MethodGet, "/user/fetch?num=1&after=1", nil))
+	h.serve(t, "users/get_by_id", httptest.NewRequest(http.MethodGet, "/user/get-by-id/2?cars=partial", nil))
+	h.serve(t, "users/get_by_id_car_ids", httptest.NewRequest(http.MethodGet, "/user/get-by-id/2?cars=none", nil))
+	h.serve(t, "users/update_json", jsonRequest(http.MethodPut, "/user/update",
+		`{"id":1,"first_name":"Taro","last_name":"Tanaka","email":"taro@example.com","age":21,"car_ids":[1,3]}`))
+	h.serve(t, "users/update_multipart", multipartRequest(t, http.MethodPut, "/user/update",
+		[]string{"id", "2", "first_name", "Hanako", "last_name", "Sato", "email", "hanako@example.com", "age", "31", "cars", "[2]"},
+		formFile{field: "avatar", filename: "hanako2.png", content: "new png"}))
+	h.serve(t, "users/get_by_id_updated", httptest.NewRequest(http.MethodGet, "/user/get-by-id/1", nil))
+
+	csv := httptest.NewRequest(http.MethodPost, "/users/import", strings.NewReader(
+		"first_name,last_name,email,age,car_ids\nJiro,Suzuki,jiro@example.com,40,\nTaro,Yamada,taro@example.com,22,1\nBad,Row,not-an-email,1,\n"))
+	csv.Header.Set("Content-Type", "text/csv")
+	h.serve(t, "users/import_csv", csv)
+	h.serve(t, "users/import_multipart", multipartRequest(t, http.MethodPost, "/users/import", nil,
+		formFile{field: "file", filename: "users.ndjson", content: `{"first_name":"Saburo","last_name":"Ito","email":"saburo@example.com","age":50}` + "\n"}))
+	h.serve(t, "users/export_csv", httptest.NewRequest(http.MethodGet, "/users/export", nil), "Content-Disposition")
+	h.serve(t, "users/export_ndjson", httptest.NewRequest(http.MethodGet, "/users/export?format=ndjson&num=2", nil), "Content-Disposition")
+
+	h.serve(t, "users/delete", httptest.NewRequest(http.MethodDelete, "/user/delete?id=3", nil))
+	h.serve(t, "users/search", httptest.NewRequest(http.MethodGet, "/search?q=nisan&limit=5", nil))
+	h.serve(t, "users/search_type", httptest.NewRequest(http.MethodGet, "/search?q=hana&type=user", nil))
+	h.serve(t, "users/graphql", jsonRequest(http.MethodPost, "/graphql",
+		`{"query":"{ users(first: 10) { totalCount edges { node { id firstName email cars { name } } } } }"}`))
+}
+
+func TestE2EUserErrors(t *testing.T) {
+	h := newHarness(t)
+	h.seedCar(t, "Leaf", "Nissan")
+	h.serve(t, "user_errors/create_user", jsonRequest(http.MethodPost, "/user/create",
+		`{"first_name":"Taro","last_name":"Yamada","email":"taro@example.com","car_ids":[1]}`))
+
+	h.serve(t, "user_errors/create_method", httptest.NewRequest(http.MethodGet, "/user/create", nil))
+	h.serve(t, "user_errors/3100_not_found", httptest.NewRequest(http.MethodGet, "/user/get-by-id/99", nil))
+	h.serve(t, "user_errors/3001_unknown_car_details", httptest.NewRequest(http.MethodGet, "/user/fetch?cars=some", nil))
+	h.serve(t, "user_errors/3002_bad_after", httptest.NewRequest(http.MethodGet, "/user/fetch?after=abc", nil))
+	h.serve(t, "user_errors/3101_bad_id", httptest.NewRequest(http.MethodGet, "/user/get-by-id/abc", nil))
+	h.serve(t, "user_errors/3102_unknown_car_details", httptest.NewRequest(http.MethodGet, "/user/get-by-id/1?cars=some", nil))
+	h.serve(t, "user_errors/3200_duplicate_email", jsonRequest(http.MethodPost, "/user/create",
+		`{"first_name":"Jiro","last_name":"Yamada","email":"taro@example.com"}`))
+	h.serve(t, "user_errors/3201_bad_car_ids", multipartRequest(t, http.MethodPost, "/user/create",
+		[]string{"first_name", "Jiro", "last_name", "Yamada", "email", "jiro@example.com", "car_ids", "[1, x]"}))
+
+	unreadable := httptest.NewRequest(http.MethodPost, "/user/create", failingBody(`{"first_name":`))
+	unreadable.Header.Set("Content-Type", "application/json")
+	h.serve(t, "user_errors/3202_unreadable_body", unreadable)
+	h.serve(t, "user_errors/3203_bad_json", jsonRequest(http.MethodPost, "/user/create", `{"first_name":`))
+	h.serve(t, "user_errors/3204_invalid_user", jsonRequest(http.MethodPost, "/user/create",
+		`{"first_name":"","last_name":"Yamada","email":"not-an-email"}`))
+	h.serve(t, "user_errors/3204_update_bad_json", jsonRequest(http.MethodPut, "/user/update", `{"id":`))
+	h.serve(t, "user_errors/3205_missing_avatar", multipartRequest(t, http.MethodPost, "/user/create",
+		[]string{"first_name", "Jiro", "last_name", "Yamada", "email", "jiro@example.com"}))
+
+	h.serve(t, "user_errors/3300_not_found", jsonRequest(http.MethodPut, "/user/update",
+		`{"id":99,"first_name":"Jiro","last_name":"Yamada","email":"jiro@example.com"}`))
+	h.serve(t, "user_errors/3301_bad_id", multipartRequest(t, http.MethodPut, "/user/update", []string{"id", "abc"}))
+	h.serve(t, "user_errors/3302_bad_cars", multipartRequest(t, http.MethodPut, "/user/update", []string{"id", "1", "cars", "[x]"}))
+	h.serve(t, "user_errors/3303_missing_avatar", multipartRequest(t, http.MethodPut, "/user/update", []string{"id", "1", "cars", "[1]"}))
+	h.serve(t, "user_errors/3305_invalid_user", jsonRequest(http.MethodPut, "/user/update",
+		`{"id":1,"first_name":"Taro","last_name":"","email":"taro@example.com"}`))
+
+	h.serve(t, "user_errors/3400_not_found", httptest.NewRequest(http.MethodDelete, "/user/delete?id=99", nil))
+	h.serve(t, "user_errors/3401_bad_id", httptest.NewRequest(http.MethodDelete, "/user/delete?id=abc", nil))
+
+	broken := httptest.NewRequest(http.MethodPost, "/users/import", failingBody("first_name,last_name,email\n"))
+	broken.Header.Set("Content-Type", "text/csv")
+	h.serve(t, "user_errors/3500_unreadable_rows", broken)
+	h.serve(t, "user_errors/3501_unknown_format", httptest.NewRequest(http.MethodPost, "/users/import?format=xml", strings.NewReader("<users/>")))
+	h.serve(t, "user_errors/3502_missing_file", multipartRequest(t, http.MethodPost, "/users/import", []string{"format", "csv"}))
+
+	h.serve(t, "user_errors/3601_unknown_format", httptest.NewRequest(http.MethodGet, "/users/export?format=xml", nil))
+	h.serve(t, "user_errors/3602_bad_num", httptest.NewRequest(http.MethodGet, "/users/export?num=abc", nil))
+
+	// each alias counts, so 200 of them with the parents are over the limit
+	var fields strings.Builder
+	for i := 0; i < 200; i++ {
+		fmt.Fprintf(&fields, " f%d: email", i)
+	}
+	h.serve(t, "user_errors/graphql_too_complex", jsonRequest(http.MethodPost, "/graphql",
+		`{"query":"{ users { edges { node {`+fields.String()+` } } } }"}`))
+}
+
+func TestE2ECars(t *testing.T) {
+	h := newHarness(t)
+	h.serve(t, "cars/create_user", jsonRequest(http.MethodPost, "/user/create",
+		`{"first_name":"Taro","last_name":"Yamada","email":"taro@example.com","car_ids":[]}`))
+	h.serve(t, "cars/create_second_user", jsonRequest(http.MethodPost, "/user/create",
+		`{"first_name":"Hanako","last_name":"Sato","email":"hanako@example.com","car_ids":[]}`))
+
+	h.serve(t, "cars/create", jsonRequest(http.MethodPost, "/cars",
+		`{"name":"Leaf","model":"Nissan","registered_at":"2020-04-01T09:00:00Z"}`))
+	h.serve(t, "cars/create_second", jsonRequest(http.MethodPost, "/cars",
+		`{"name":"Prius","model":"Toyota","registered_at":"2021-04-01T09:00:00Z"}`))
+	h.serve(t, "cars/fetch", httptest.NewRequest(http.MethodGet, "/cars?num=10", nil))
+	h.serve(t, "cars/get_by_id", httptest.NewRequest(http.MethodGet, "/cars/1", nil))
+	h.serve(t, "cars/update", jsonRequest(http.MethodPut, "/cars/1",
+		`{"name":"Leaf","model":"Nissan e+","registered_at":"2020-04-01T09:00:00Z"}`))
+	h.serve(t, "cars/transfer", jsonRequest(http.MethodPost, "/cars/1/transfer", `{"user_id":1}`))
+	// the car is taken from its owner
+	h.serve(t, "cars/transfer_again", jsonRequest(http.MethodPost, "/cars/1/transfer", `{"user_id":2}`))
+	h.serve(t, "cars/transferred_from", httptest.NewRequest(http.MethodGet, "/user/get-by-id/1", nil))
+
+	h.serve(t, "car_errors/5200_not_found", httptest.NewRequest(http.MethodGet, "/cars/99", nil))
+	h.serve(t, "car_errors/5201_bad_id", httptest.NewRequest(http.MethodGet, "/cars/abc", nil))
+	h.serve(t, "car_errors/5300_invalid", jsonRequest(http.MethodPost, "/cars", `{"name":"","model":"Nissan"}`))
+	h.serve(t, "car_errors/5301_unreadable_body", httptest.NewRequest(http.MethodPost, "/cars", failingBody(`{"name":`)))
+	h.serve(t, "car_errors/5302_bad_json", jsonRequest(http.MethodPost, "/cars", `{"name":`))
+	h.serve(t, "car_errors/5400_not_found", jsonRequest(http.MethodPut, "/cars/99",
+		`{"name":"Leaf","model":"Nissan","registered_at":"2020-04-01T09:00:00Z"}`))
+	h.serve(t, "car_errors/5401_bad_id", jsonRequest(http.MethodPut, "/cars/abc", `{}`))
+	h.serve(t, "car_errors/5402_unreadable_body", httptest.NewRequest(http.MethodPut, "/cars/1", failingBody(`{"name":`)))
+	h.serve(t, "car_errors/5403_bad_json", jsonRequest(http.MethodPut, "/cars/1", `{"name":`))
+	h.serve(t, "car_errors/5600_user_not_found", jsonRequest(http.MethodPost, "/cars/1/transfer", `{"user_id":99}`))
+	h.serve(t, "car_errors/5601_bad_id", jsonRequest(http.MethodPost, "/cars/abc/transfer", `{"user_id":1}`))
+	h.serve(t, "car_errors/5602_unreadable_body", httptest.NewRequest(http.MethodPost, "/cars/1/transfer", failingBody(`{"user_id":`)))
+	h.serve(t, "car_errors/5603_missing_user", jsonRequest(http.MethodPost, "/cars/1/transfer", `{}`))
+
+	h.serve(t, "cars/delete", httptest.NewRequest(http.MethodDelete, "/cars/2", nil))
+	h.serve(t, "car_errors/5500_not_found", httptest.NewRequest(http.MethodDelete, "/cars/2", nil))
+	h.serve(t, "car_errors/5501_bad_id", httptest.NewRequest(http.MethodDelete, "/cars/abc", nil))
+}
+
+func TestE2EGroups(t *testing.T) {
+	h := newHarness(t)
+	h.serve(t, "groups/create_user", jsonRequest(http.MethodPost, "/user/create",
+		`{"first_name":"Taro","last_name":"Yamada","email":"taro@example.com","car_ids":[]}`))
+
+	h.serve(t, "groups/create", jsonRequest(http.MethodPost, "/groups", `{"name":"drivers","user_ids":[1]}`))
+	h.serve(t, "groups/fetch", httptest.NewRequest(http.MethodGet, "/groups?num=10", nil))
+	h.serve(t, "groups/get_by_id", httptest.NewRequest(http.MethodGet, "/groups/1", nil))
+	h.serve(t, "groups/update", jsonRequest(http.MethodPut, "/groups/1", `{"name":"owners","user_ids":[]}`))
+
+	h.serve(t, "group_errors/5800_not_found", httptest.NewRequest(http.MethodGet, "/groups/99", nil))
+	h.serve(t, "group_errors/5801_bad_id", httptest.NewRequest(http.MethodGet, "/groups/abc", nil))
+	h.serve(t, "group_errors/5900_invalid", jsonRequest(http.MethodPost, "/groups", `{"name":"no spaces"}`))
+	h.serve(t, "group_errors/5901_unreadable_body", httptest.NewRequest(http.MethodPost, "/groups", failingBody(`{"name":`)))
+	h.serve(t, "group_errors/5902_bad_json", jsonRequest(http.MethodPost, "/groups", `{"name":`))
+	h.serve(t, "group_errors/6000_not_found", jsonRequest(http.MethodPut, "/groups/99", `{"name":"owners"}`))
+	h.serve(t, "group_errors/6001_bad_id", jsonRequest(http.MethodPut, "/groups/abc", `{}`))
+	h.serve(t, "group_errors/6002_unreadable_body", httptest.NewRequest(http.MethodPut, "/groups/1", failingBody(`{"name":`)))
+	h.serve(t, "group_errors/6003_bad_json", jsonRequest(http.MethodPut, "/groups/1", `{"name":`))
+
+	h.serve(t, "groups/delete", httptest.NewRequest(http.MethodDelete, "/groups/1", nil))
+	h.serve(t, "group_errors/6100_not_found", httptest.NewRequest(http.MethodDelete, "/groups/1", nil))
+	h.serve(t, "group_errors/6101_bad_id", httptest.NewRequest(http.MethodDelete, "/groups/abc", nil))
+}
+
+func TestE2EJobs(t *testing.T) {
+	h := newHarness(t)
+
+	h.serve(t, "jobs/enqueue_import", multipartRequest(t, http.MethodPost, "/jobs", []string{"type", "user_import"},
+		formFile{field: "file", filename: "users.csv", content: "first_name,last_name,email\nTaro,Yamada,taro@example.com\n"}))
+	h.serve(t, "jobs/enqueue_reprocess", httptest.NewRequest(http.MethodPost, "/jobs?type=avatar_reprocess", nil))
+	h.serve(t, "jobs/get_by_id", httptest.NewRequest(http.MethodGet, "/jobs/1", nil))
+	h.serve(t, "jobs/cancel", httptest.NewRequest(http.MethodPost, "/jobs/2/cancel", nil))
+
+	h.serve(t, "job_errors/3700_unknown_type", httptest.NewRequest(http.MethodPost, "/jobs?type=unknown", nil))
+	malformed := httptest.NewRequest(http.MethodPost, "/jobs?type=user_import", strings.NewReader("--x\r\nbroken"))
+	malformed.Header.Set("Content-Type", "multipart/form-data; boundary=x")
+	h.serve(t, "job_errors/3701_malformed_multipart", malformed)
+	h.serve(t, "job_errors/3702_unsupported_format", httptest.NewRequest(http.MethodPost, "/jobs?type=user_import&format=xml", nil))
+	h.serve(t, "job_errors/3800_not_found", httptest.NewRequest(http.MethodGet, "/jobs/99", nil))
+	h.serve(t, "job_errors/3801_bad_id", httptest.NewRequest(http.MethodGet, "/jobs/abc", nil))
+	h.serve(t, "job_errors/3900_finished", httptest.NewRequest(http.MethodPost, "/jobs/2/cancel", nil))
+	h.serve(t, "job_errors/3901_bad_id", httptest.NewRequest(http.MethodPost, "/jobs/abc/cancel", nil))
+}
+
+func TestE2EWebhooks(t *testing.T) {
+	h := newHarness(t)
+
+	h.serve(t, "webhooks/create", jsonRequest(http.MethodPost, "/webhooks",
+		`{"url":"https://example.com/hook","secret":"0123456789abcdef","event_types":["user.created"]}`))
+	h.serve(t, "webhooks/fetch", httptest.NewRequest(http.MethodGet, "/webhooks?num=10", nil))
+	h.serve(t, "webhooks/get_by_id", httptest.NewRequest(http.MethodGet, "/webhooks/1", nil))
+	h.serve(t, "webhooks/update", jsonRequest(http.MethodPut, "/webhooks/1",
+		`{"url":"https://example.com/hook2","secret":"0123456789abcdef","event_types":["*"],"active":false}`))
+	h.serve(t, "webhooks/deliveries", httptest.NewRequest(http.MethodGet, "/webhooks/1/deliveries", nil))
+
+	h.serve(t, "webhook_errors/4100_not_found", httptest.NewRequest(http.MethodGet, "/webhooks/99", nil))
+	h.serve(t, "webhook_errors/4101_bad_id", httptest.NewRequest(http.MethodGet, "/webhooks/abc", nil))
+	h.serve(t, "webhook_errors/4200_invalid", jsonRequest(http.MethodPost, "/webhooks", `{"url":"not a url"}`))
+	h.serve(t, "webhook_errors/4201_unreadable_body", httptest.NewRequest(http.MethodPost, "/webhooks", failingBody(`{"url":`)))
+	h.serve(t, "webhook_errors/4202_bad_json", jsonRequest(http.MethodPost, "/webhooks", `{"url":`))
+	h.serve(t, "webhook_errors/4300_not_found", jsonRequest(http.MethodPut, "/webhooks/99", `{"url":"https://example.com/hook"}`))
+	h.serve(t, "webhook_errors/4301_bad_id", jsonRequest(http.MethodPut, "/webhooks/abc", `{"url":"https://example.com/hook"}`))
+	h.serve(t, "webhook_errors/4302_unreadable_body", httptest.NewRequest(http.MethodPut, "/webhooks/1", failingBody(`{"url":`)))
+	h.serve(t, "webhook_errors/4303_bad_json", jsonRequest(http.MethodPut, "/webhooks/1", `{"url":`))
+	h.serve(t, "webhook_errors/4500_not_found", httptest.NewRequest(http.MethodGet, "/webhooks/99/deliveries", nil))
+	h.serve(t, "webhook_errors/4501_bad_id", httptest.NewRequest(http.MethodGet, "/webhooks/abc/deliveries", nil))
+
+	h.serve(t, "webhooks/delete", httptest.NewRequest(http.MethodDelete, "/webhooks/1", nil))
+	h.serve(t, "webhook_errors/4400_not_found", httptest.NewRequest(http.MethodDelete, "/webhooks/1", nil))
+	h.serve(t, "webhook_errors/4401_bad_id", httptest.NewRequest(http.MethodDelete, "/webhooks/abc", nil))
+}
+
+func TestE2EEvents(t *testing.T) {
+	h := newHarness(t)
+	h.serve(t, "events/create_first_user", jsonRequest(http.MethodPost, "/user/create",
+		`{"first_name":"Taro","last_name":"Yamada","email":"taro@example.com","age":20,"car_ids":[]}`))
+	h.serve(t, "events/create_second_user", jsonRequest(http.MethodPost, "/user/create",
+		`{"first_name":"Hanako","last_name":"Sato","email":"hanako@example.com","age":30,"car_ids":[]}`))
+
+	// the stream replays the changes after Last-Event-ID and ends with the request
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	stream := httptest.NewRequest(http.MethodGet, "/events/stream?type=user", nil).WithContext(ctx)
+	stream.Header.Set("Last-Event-ID", "1")
+	h.serve(t, "events/stream", stream, "Cache-Control")
+
+	h.serve(t, "event_errors/4600_unknown_type", httptest.NewRequest(http.MethodGet, "/events/stream?type=truck", nil))
+	badID := httptest.NewRequest(http.MethodGet, "/events/stream", nil)
+	badID.Header.Set("Last-Event-ID", "abc")
+	h.serve(t, "event_errors/4601_bad_last_event_id", badID)
+
+	// every writer of the server flushes, so this is only reached without the middlewares
+	r := httptest.NewRequest(http.MethodGet, "/events/stream", nil)
+	w := httptest.NewRecorder()
+	handler.NewEventHandler(usecase.NewChangeUsecase(h.components.changeFeed)).Stream(nonFlusher{w}, r)
+	compareGolden(t, "event_errors/4602_streaming_not_supported", r, w.Result(), w.Body.Bytes(), nil)
+
+	// the middlewares always scope the request to a tenant, so this is only reached without them as well
+	r = httptest.NewRequest(http.MethodGet, "/events/stream", nil)
+	w = httptest.NewRecorder()
+	handler.NewEventHandler(usecase.NewChangeUsecase(h.components.changeFeed)).Stream(w, r)
+	compareGolden(t, "event_errors/4603_missing_tenant", r, w.Result(), w.Body.Bytes(), nil)
+}
+
+func TestE2EHealth(t *testing.T) {
+	h := newHarness(t)
+	h.serve(t, "health/healthz", httptest.NewRequest(http.MethodGet, "/healthz", nil), "Cache-Control")
+	h.serve(t, "health/readyz", httptest.NewRequest(http.MethodGet, "/readyz", nil), "Cache-Control")
+	// the error of the dependency is not shown
+	h.checker.err = errors.New("bucket sample-bucket of ap-northeast-1 is unreachable")
+	h.serve(t, "health/4700_storage_unavailable", httptest.NewRequest(http.MethodGet, "/readyz", nil), "Cache-Control")
+	h.serve(t, "health/graphql_playground", httptest.NewRequest(http.MethodGet, "/graphql/playground", nil))
+
+	w := httptest.NewRecorder()
+	h.handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	if w.Code != http.StatusOK || !strings.Contains(w.Body.String(), "goent_http_request_duration_seconds") {
+		t.Errorf("/metrics = %d without the request metrics", w.Code)
+	}
+
+	// the storage may only degrade the service
+	h = newHarness(t, func(cfg *config.Config) {
+		cfg.Health.CriticalStorage = false
+	})
+	h.checker.err = errors.New("bucket sample-bucket of ap-northeast-1 is unreachable")
+	h.serve(t, "health/readyz_degraded", httptest.NewRequest(http.MethodGet, "/readyz", nil), "Cache-Control")
+}
+
+func TestE2EMetrics(t *testing.T) {
+	h := newHarness(t)
+	for _, path := range []string{"/user/get-by-id/1", "/user/get-by-id/2", "/jobs/42", "/cars/7", "/no/such/route/9"} {
+		h.handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, path, nil))
+	}
+
+	families, err := metrics.Gatherer().Gather()
+	if err != nil {
+		t.Fatal(err)
+	}
+	routes := make(map[string]bool)
+	for _, f := range families {
+		if f.GetName() != "goent_http_request_duration_seconds" {
+			continue
+		}
+		for _, m := range f.GetMetric() {
+			for _, l := range m.GetLabel() {
+				if l.GetName() == "route" {
+					routes[l.GetValue()] = true
+				}
+			}
+		}
+	}
+	// the requests are labeled by the patterns of their routes, not by their paths
+	for _, want := range []string{"/user/get-by-id/", "/jobs/", "/cars/", "/"} {
+		if !routes[want] {
+			t.Errorf("routes = %v, want %s", routes, want)
+		}
+	}
+	for route := range routes {
+		if strings.ContainsAny(route, "0123456789") {
+			t.Errorf("route %s is a raw path", route)
+		}
+	}
+}
+
+func TestE2EIdempotency(t *testing.T) {
+	h := newHarness(t)
+	create := func(key string, body string) *http.Request {
+		r := jsonRequest(http.MethodPost, "/user/create", body)
+		r.Header.Set(handler.IdempotencyKeyHeader, key)
+		return r
+	}
+	taro := `{"first_name":"Taro","last_name":"Yamada","email":"taro@example.com","car_ids":[]}`
+
+	h.serve(t, "idempotency/first", create("key-1", taro), handler.IdempotentReplayedHeader)
+	h.serve(t, "idempotency/replayed", create("key-1", taro), handler.IdempotentReplayedHeader)
+	h.serve(t, "idempotency/4901_mismatch", create("key-1", `{"first_name":"Jiro","last_name":"Yamada","email":"jiro@example.com"}`))
+	h.serve(t, "idempotency/4903_key_too_long", create(strings.Repeat("k", 256), taro))
+	unreadable := httptest.NewRequest(http.MethodPost, "/user/create", failingBody(`{"first_name":`))
+	unreadable.Header.Set(handler.IdempotencyKeyHeader, "key-2")
+	h.serve(t, "idempotency/4904_unreadable_body", unreadable)
+
+	// a retry during the first request is rejected until it completes
+	body, contentType := multipartBody(t, []string{"first_name", "Hanako", "last_name", "Sato", "email", "hanako@example.com"},
+		formFile{field: "avatar", filename: "hanako.png", content: "png"})
+	upload := func() *http.Request {
+		r := httptest.NewRequest(http.MethodPost, "/user/create", bytes.NewReader(body))
+		r.Header.Set("Content-Type", contentType)
+		r.Header.Set(handler.IdempotencyKeyHeader, "key-3")
+		return r
+	}
+	waiting, open := h.files.close()
+	first := make(chan *httptest.ResponseRecorder)
+	go func() {
+		w := httptest.NewRecorder()
+		h.handler.ServeHTTP(w, upload())
+		first <- w
+	}()
+	<-waiting
+	h.serve(t, "idempotency/4902_in_progress", upload(), "Retry-After")
+	open()
+	if w := <-first; w.Code != http.StatusOK {
+		t.Errorf("first request = %d, want 200", w.Code)
+	}
+	h.serve(t, "idempotency/replayed_upload", upload(), handler.IdempotentReplayedHeader)
+
+	// a retry encoded again has another boundary and the fields in another order, but the same form
+	reencoded := multipartRequest(t, http.MethodPost, "/user/create", []string{"email", "hanako@example.com", "last_name", "Sato", "first_name", "Hanako"},
+		formFile{field: "avatar", filename: "hanako.png", content: "png"})
+	reencoded.Header.Set(handler.IdempotencyKeyHeader, "key-3")
+	h.serve(t, "idempotency/replayed_reencoded_upload", reencoded, handler.IdempotentReplayedHeader)
+	// another file is another request
+	otherFile := multipartRequest(t, http.MethodPost, "/user/create", []string{"first_name", "Hanako", "last_name", "Sato", "email", "hanako@example.com"},
+		formFile{field: "avatar", filename: "hanako.png", content: "jpg"})
+	otherFile.Header.Set(handler.IdempotencyKeyHeader, "key-3")
+	h.serve(t, "idempotency/4901_other_file", otherFile)
+}
+
+func TestE2ERateLimit(t *testing.T) {
+	h := newHarness(t, func(cfg *config.Config) {
+		cfg.RateLimit.Routes = map[string]model.RateLimit{"/search": {Limit: 1, Period: time.Minute}}
+	})
+	h.serve(t, "rate_limit/allowed", httptest.NewRequest(http.MethodGet, "/search?q=taro", nil), "RateLimit-Limit", "RateLimit-Policy")
+	h.serve(t, "rate_limit/4800_exceeded", httptest.NewRequest(http.MethodGet, "/search?q=taro", nil), "RateLimit-Limit", "RateLimit-Policy")
+	// the other clients have their own buckets
+	other := httptest.NewRequest(http.MethodGet, "/search?q=taro", nil)
+	other.RemoteAddr = "192.0.2.2:1234"
+	h.serve(t, "rate_limit/other_client", other, "RateLimit-Limit", "RateLimit-Policy")
+	// a verified API key has its own bucket, and a made-up one does not get a new bucket
+	h.seedTenant(t, "acme", "acme-key")
+	h.serve(t, "rate_limit/api_key", as(httptest.NewRequest(http.MethodGet, "/search?q=taro", nil), "acme-key"), "RateLimit-Limit", "RateLimit-Policy")
+	h.serve(t, "rate_limit/6201_made_up_api_key", as(httptest.NewRequest(http.MethodGet, "/search?q=taro", nil), "made-up-key"), "RateLimit-Limit", "RateLimit-Policy")
+
+	// the made-up API keys are limited by the address before they are looked up
+	h = newHarness(t, func(cfg *config.Config) {
+		cfg.RateLimit.Address = model.RateLimit{Limit: 20, Period: time.Minute}
+	})
+	for i := 0; i < 20; i++ {
+		w := httptest.NewRecorder()
+		h.handler.ServeHTTP(w, as(httptest.NewRequest(http.MethodGet, "/search?q=taro", nil), fmt.Sprintf("made-up-key-%d", i)))
+		if w.Code != http.StatusUnauthorized {
+			t.Fatalf("request %d with a made-up API key = %d, want 401", i, w.Code)
+		}
+	}
+	h.serve(t, "rate_limit/4800_made_up_api_keys", as(httptest.NewRequest(http.MethodGet, "/search?q=taro", nil), "made-up-key-20"), "RateLimit-Limit", "RateLimit-Policy")
+	// another address is not limited
+	other = as(httptest.NewRequest(http.MethodGet, "/search?q=taro", nil), "made-up-key-21")
+	other.RemoteAddr = "192.0.2.2:1234"
+	h.serve(t, "rate_limit/6201_made_up_api_key_other_address", other, "RateLimit-Limit", "RateLimit-Policy")
+}
+
+func TestE2ESearchErrors(t *testing.T) {
+	h := newHarness(t)
+	h.serve(t, "search_errors/5000_bad_limit", httptest.NewRequest(http.MethodGet, "/search?q=taro&limit=abc", nil))
+	h.serve(t, "search_errors/5001_empty_query", httptest.NewRequest(http.MethodGet, "/search?q=&type=truck", nil))
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	h.serve(t, "search_errors/5002_canceled", httptest.NewRequest(http.MethodGet, "/search?q=taro", nil).WithContext(ctx))
+}
+
+// TestE2EDatabaseDown covers the errors of the routes which fail only with the database.
+// The default tenant is found in the cache by then
+func TestE2EDatabaseDown(t *testing.T) {
+	h := newHarness(t)
+	h.handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/cars", nil))
+	h.handler.ServeHTTP(httptest.NewRecorder(), jsonRequest(http.MethodPost, "/user/create",
+		`{"first_name":"Hanako","last_name":"Sato","email":"hanako@example.com","car_ids":[]}`))
+	link := h.verificationLink(t, "hanako@example.com")
+	if err := h.components.client.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	h.serve(t, "database_down/3000_fetch", httptest.NewRequest(http.MethodGet, "/user/fetch", nil))
+	h.serve(t, "database_down/3600_export", httptest.NewRequest(http.MethodGet, "/users/export", nil))
+	h.serve(t, "database_down/4000_webhooks", httptest.NewRequest(http.MethodGet, "/webhooks", nil))
+	h.serve(t, "database_down/5100_cars", httptest.NewRequest(http.MethodGet, "/cars", nil))
+	h.serve(t, "database_down/5700_groups", httptest.NewRequest(http.MethodGet, "/groups", nil))
+	h.serve(t, "database_down/4700_readyz", httptest.NewRequest(http.MethodGet, "/readyz", nil))
+	r := jsonRequest(http.MethodPost, "/user/create", `{"first_name":"Taro","last_name":"Yamada","email":"taro@example.com"}`)
+	r.Header.Set(handler.IdempotencyKeyHeader, "key-1")
+	h.serve(t, "database_down/4900_idempotency_store", r)
+	// the tenants which are not cached are not found
+	r = httptest.NewRequest(http.MethodGet, "/user/fetch", nil)
+	r.Header.Set(handler.APIKeyHeader, "uncached-key")
+	h.serve(t, "database_down/6204_tenant", r)
+	// the link is valid, but the user read by the notifier from the cache cannot be verified
+	h.serve(t, "database_down/6300_verify", httptest.NewRequest(http.MethodGet, link, nil))
+}
+
+// TestE2ETracing follows a failed request from the handler through the usecase to the database
+func TestE2ETracing(t *testing.T) {
+	h := newHarness(t)
+	h.handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/cars", nil))
+	if err := h.components.client.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	const traceID = "4bf92f3577b34da6a3ce929d0e0e4736"
+	r := httptest.NewRequest(http.MethodGet, "/user/fetch", nil)
+	r.Header.Set("traceparent", "00-"+traceID+"-00f067aa0ba902b7-01")
+	w := httptest.NewRecorder()
+	h.handler.ServeHTTP(w, r)
+	if w.Code != http.StatusInternalServerError {
+		t.Fatalf("status = %d, want 500", w.Code)
+	}
+
+	named := make(map[string]sdktrace.ReadOnlySpan)
+	for _, s := range spans.Ended() {
+		if s.SpanContext().TraceID().String() == traceID {
+			named[s.Name()] = s
+		}
+	}
+	// each span is the child of the one before, and all of them failed
+	var parent sdktrace.ReadOnlySpan
+	for _, name := range []string{"GET /user/fetch", "UserUsecase.Fetch", "db.query"} {
+		s, ok := named[name]
+		if !ok {
+			t.Fatalf("span %s is not recorded, got %v", name, named)
+		}
+		if parent != nil && s.Parent().SpanID() != parent.SpanContext().SpanID() {
+			t.Errorf("span %s is the child of %s, want %s", name, s.Parent().SpanID(), parent.Name())
+		}
+		if s.Status().Code != codes.Error {
+			t.Errorf("status of span %s = %v, want Error", name, s.Status())
+		}
+		parent = s
+	}
+	for _, name := range []string{"UserUsecase.Fetch", "db.query"} {
+		events := named[name].Events()
+		if len(events) == 0 || events[0].Name != "exception" {
+			t.Errorf("events of span %s = %v, want the error recorded", name, events)
+		}
+	}
+}
+
+// signToken signs the HS256 token with the tenant claim
+func signToken(secret string, tenantSlug string) string {
+	header := base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"HS256","typ":"JWT"}`))
+	claims := base64.RawURLEncoding.EncodeToString([]byte(`{"tenant":"` + tenantSlug + `"}`))
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(header + "." + claims))
+	return header + "." + claims + "." + base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// as sets the API key of the request
+func as(r *http.Request, apiKey string) *http.Request {
+	r.Header.Set(handler.APIKeyHeader, apiKey)
+	return r
+}
+
+// TestE2ETenants covers the isolation of the tenants, which are resolved by the API key, the token and the subdomain
+func TestE2ETenants(t *testing.T) {
+	h := newHarness(t, func(cfg *config.Config) {
+		cfg.Tenancy.BaseDomain = "example.com"
+		cfg.Tenancy.JWTSecret = "0123456789abcdef"
+	})
+	acme := h.seedTenant(t, "acme", "acme-key")
+	globex := h.seedTenant(t, "globex", "globex-key")
+	h.seedTenantCar(t, acme, "Leaf", "Nissan")
+	h.seedTenantCar(t, globex, "Prius", "Toyota")
+
+	h.serve(t, "tenants/create_acme", as(multipartRequest(t, http.MethodPost, "/user/create",
+		[]string{"first_name", "Taro", "last_name", "Yamada", "email", "taro@example.com", "car_ids", "[1]"},
+		formFile{field: "avatar", filename: "taro.png", content: "png"}), "acme-key"))
+	// the e-mails are unique within a tenant
+	h.serve(t, "tenants/create_globex", as(jsonRequest(http.MethodPost, "/user/create",
+		`{"first_name":"Taro","last_name":"Suzuki","email":"taro@example.com","car_ids":[2]}`), "globex-key"))
+
+	// the data of the other tenants are not seen
+	h.serve(t, "tenants/fetch_acme", as(httptest.NewRequest(http.MethodGet, "/user/fetch?cars=all", nil), "acme-key"))
+	h.serve(t, "tenants/fetch_default", httptest.NewRequest(http.MethodGet, "/user/fetch", nil))
+	h.serve(t, "tenants/3100_other_user", as(httptest.NewRequest(http.MethodGet, "/user/get-by-id/1", nil), "globex-key"))
+	h.serve(t, "tenants/5100_other_cars", as(httptest.NewRequest(http.MethodGet, "/cars", nil), "globex-key"))
+	h.serve(t, "tenants/search_globex", as(httptest.NewRequest(http.MethodGet, "/search?q=taro", nil), "globex-key"))
+	// nor written
+	h.serve(t, "tenants/3200_other_car", as(jsonRequest(http.MethodPost, "/user/create",
+		`{"first_name":"Jiro","last_name":"Suzuki","email":"jiro@example.com","car_ids":[1]}`), "globex-key"))
+	h.serve(t, "tenants/3300_other_user", as(jsonRequest(http.MethodPut, "/user/update",
+		`{"id":1,"first_name":"Taro","last_name":"Suzuki","email":"taro@example.com","car_ids":[]}`), "globex-key"))
+	h.serve(t, "tenants/3400_other_user", as(httptest.NewRequest(http.MethodDelete, "/user/delete?id=1", nil), "globex-key"))
+	h.serve(t, "tenants/get_acme_after", as(httptest.NewRequest(http.MethodGet, "/user/get-by-id/1", nil), "acme-key"))
+
+	// the changes after the first one are replayed to their tenant only
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	stream := as(httptest.NewRequest(http.MethodGet, "/events/stream?type=user", nil).WithContext(ctx), "globex-key")
+	stream.Header.Set("Last-Event-ID", "1")
+	h.serve(t, "tenants/stream_globex", stream, "Cache-Control")
+	stream = as(httptest.NewRequest(http.MethodGet, "/events/stream?type=user", nil).WithContext(ctx), "acme-key")
+	stream.Header.Set("Last-Event-ID", "1")
+	h.serve(t, "tenants/stream_acme", stream, "Cache-Control")
+
+	subdomain := httptest.NewRequest(http.MethodGet, "/user/fetch", nil)
+	subdomain.Host = "acme.example.com"
+	h.serve(t, "tenants/subdomain", subdomain)
+	token := httptest.NewRequest(http.MethodGet, "/user/fetch", nil)
+	token.Header.Set("Authorization", "Bearer "+signToken("0123456789abcdef", "globex"))
+	h.serve(t, "tenants/token", token)
+
+	unknown := httptest.NewRequest(http.MethodGet, "/user/fetch", nil)
+	unknown.Host = "initech.example.com"
+	h.serve(t, "tenant_errors/6200_unknown_subdomain", unknown)
+	h.serve(t, "tenant_errors/6201_invalid_api_key", as(httptest.NewRequest(http.MethodGet, "/user/fetch", nil), "wrong-key"))
+	forged := httptest.NewRequest(http.MethodGet, "/user/fetch", nil)
+	forged.Header.Set("Authorization", "Bearer "+signToken("another secret", "globex"))
+	h.serve(t, "tenant_errors/6202_invalid_token", forged)
+	mismatch := as(httptest.NewRequest(http.MethodGet, "/user/fetch", nil), "acme-key")
+	mismatch.Host = "globex.example.com"
+	h.serve(t, "tenant_errors/6203_mismatch", mismatch)
+	// the probes belong to no tenant
+	probe := as(httptest.NewRequest(http.MethodGet, "/healthz", nil), "wrong-key")
+	h.serve(t, "tenants/healthz", probe)
+}
+
+// deliver runs the relay until the events are published and then the notifier until the mails are sent.
+// The workers run one after the other, because SQLite locks the tables against the writes of both
+func (h *harness) deliver(t *testing.T) {
+	t.Helper()
+	ctx := tenant.AllTenants(context.Background())
+	h.components.eventRelay.Start()
+	waitFor(t, func() bool {
+		n, err := h.components.client.OutboxEvent.Query().Where(outboxevent.DeliveredAtIsNil(), outboxevent.DeadAtIsNil()).Count(ctx)
+		return err == nil && n == 0
+	})
+	h.components.eventRelay.Stop()
+	h.components.notifier.Start()
+	waitFor(t, func() bool {
+		n, err := h.components.client.Notification.Query().Where(entnotification.StatusEQ(entnotification.StatusPending)).Count(ctx)
+		return err == nil && n == 0
+	})
+	h.components.notifier.Stop()
+}
+
+// verificationLink delivers the queued mails and returns the path of the link in the last one, which must have been sent to the address
+func (h *harness) verificationLink(t *testing.T, to string) string {
+	t.Helper()
+	h.deliver(t)
+	mails := h.mailbox.Mails()
+	if len(mails) == 0 {
+		t.Fatal("no mail was sent")
+	}
+	last := mails[len(mails)-1]
+	if last.To != to {
+		t.Fatalf("last mail was sent to %s, want %s", last.To, to)
+	}
+	link := regexp.MustCompile(`http\S+`).FindString(last.Text)
+	u, err := url.Parse(link)
+	if err != nil || link == "" {
+		t.Fatalf("no link in the mail %q: %v", last.Text, err)
+	}
+	return u.RequestURI()
+}
+
+// TestE2EVerification covers the e-mails stored in lower case and the links which verify them
+func TestE2EVerification(t *testing.T) {
+	h := newHarness(t)
+	h.seedTenant(t, "acme", "acme-key")
+
+	h.serve(t, "verification/create", jsonRequest(http.MethodPost, "/user/create",
+		`{"first_name":"Taro","last_name":"Yamada","email":"Taro@Example.COM","car_ids":[]}`))
+	link := h.verificationLink(t, "taro@example.com")
+	// the e-mails are unique in any case
+	h.serve(t, "verification/3200_duplicate_email", jsonRequest(http.MethodPost, "/user/create",
+		`{"first_name":"Jiro","last_name":"Yamada","email":"TARO@example.com","car_ids":[]}`))
+
+	h.serve(t, "verification/resend", httptest.NewRequest(http.MethodPost, "/user/verify/resend?id=1", nil))
+	// the welcome and the two verifications
+	h.deliver(t)
+	if n := len(h.mailbox.Mails()); n != 3 {
+		t.Errorf("mails after resending = %d, want 3", n)
+	}
+	// the link works without the credentials of the tenant, and more than once
+	h.serve(t, "verification/verify", as(httptest.NewRequest(http.MethodGet, link, nil), "acme-key"))
+	h.serve(t, "verification/verify_again", httptest.NewRequest(http.MethodGet, link, nil))
+	h.serve(t, "verification/6312_already_verified", httptest.NewRequest(http.MethodPost, "/user/verify/resend?id=1", nil))
+	// the user of acme with the same id is another one
+	h.serve(t, "verification/6310_other_tenant", as(httptest.NewRequest(http.MethodPost, "/user/verify/resend?id=1", nil), "acme-key"))
+
+	// a new e-mail has to be verified again, and the old link does not verify it
+	h.serve(t, "verification/update_email", jsonRequest(http.MethodPut, "/user/update",
+		`{"id":1,"first_name":"Taro","last_name":"Yamada","email":"taro@example.org","car_ids":[]}`))
+	newLink := h.verificationLink(t, "taro@example.org")
+	h.serve(t, "verification/get_unverified", httptest.NewRequest(http.MethodGet, "/user/get-by-id/1", nil))
+	h.serve(t, "verification/6301_changed_email", httptest.NewRequest(http.MethodGet, link, nil))
+	h.serve(t, "verification/verify_new_email", httptest.NewRequest(http.MethodGet, newLink, nil))
+	if n := len(h.mailbox.Mails()); n != 4 {
+		t.Errorf("mails = %d, want 4", n)
+	}
+
+	// the claims of another user do not match the signature
+	h.serve(t, "verification/6301_forged", httptest.NewRequest(http.MethodGet, strings.Replace(newLink, "token=eyJ", "token=eyK", 1), nil))
+	h.serve(t, "verification/6301_missing_token", httptest.NewRequest(http.MethodGet, "/user/verify", nil))
+	h.serve(t, "verification/6311_invalid_id", httptest.NewRequest(http.MethodPost, "/user/verify/resend?id=taro", nil))
+}
+
+// waitFor polls the condition until it holds, for 5 seconds at most
+func waitFor(t *testing.T, condition func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for !condition() {
+		if time.Now().After(deadline) {
+			t.Fatal("timed out waiting for the workers")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+// TestE2ENotifications runs the relay and the notifier, which mail the users in their languages unless they opted out.
+// The links which verify the e-mails are mailed in the same way
+func TestE2ENotifications(t *testing.T) {
+	h := newHarness(t, func(cfg *config.Config) {
+		cfg.Events.RelayInterval = 10 * time.Millisecond
+		cfg.Notifications.Interval = 10 * time.Millisecond
+	})
+	h.seedCar(t, "Prius", "Toyota")
+
+	h.serve(t, "notifications/create_taro", jsonRequest(http.MethodPost, "/user/create",
+		`{"first_name":"Taro","last_name":"Yamada","email":"taro@example.com","car_ids":[]}`))
+	h.serve(t, "notifications/get_preferences", httptest.NewRequest(http.MethodGet, "/user/notifications?id=1", nil))
+	h.serve(t, "notifications/update_preferences", jsonRequest(http.MethodPut, "/user/notifications?id=1",
+		`{"language":"en","car_assigned":true,"group_added":false}`))
+	h.serve(t, "notifications/create_hanako", jsonRequest(http.MethodPost, "/user/create",
+		`{"first_name":"Hanako","last_name":"Sato","email":"hanako@example.com","car_ids":[]}`))
+	h.serve(t, "notifications/assign_car", jsonRequest(http.MethodPut, "/user/update",
+		`{"id":1,"first_name":"Taro","last_name":"Yamada","email":"taro@example.com","car_ids":[1]}`))
+	h.serve(t, "notifications/create_group", jsonRequest(http.MethodPost, "/groups", `{"name":"drivers","user_ids":[1,2]}`))
+
+	h.serve(t, "notification_errors/6400_not_found", httptest.NewRequest(http.MethodGet, "/user/notifications?id=99", nil))
+	h.serve(t, "notification_errors/6401_bad_id", httptest.NewRequest(http.MethodGet, "/user/notifications?id=abc", nil))
+	h.serve(t, "notification_errors/6410_unknown_language", jsonRequest(http.MethodPut, "/user/notifications?id=1", `{"language":"fr"}`))
+	h.serve(t, "notification_errors/6410_not_found", jsonRequest(http.MethodPut, "/user/notifications?id=99", `{"language":"en"}`))
+	h.serve(t, "notification_errors/6411_bad_id", jsonRequest(http.MethodPut, "/user/notifications?id=abc", `{}`))
+	h.serve(t, "notification_errors/6412_unreadable_body", httptest.NewRequest(http.MethodPut, "/user/notifications?id=1", failingBody(`{"language":`)))
+	h.serve(t, "notification_errors/6413_bad_json", jsonRequest(http.MethodPut, "/user/notifications?id=1", `{"language":`))
+
+	want := map[string]bool{
+		"taro@example.com Welcome, Taro":                  true,
+		"taro@example.com A car has been assigned to you": true,
+		"hanako@example.com ようこそ、Sato Hanakoさん":           true,
+		"hanako@example.com グループ drivers に追加されました":        true,
+		"taro@example.com Verify your e-mail address":     true,
+		"hanako@example.com メールアドレスの確認":                   true,
+	}
+	h.deliver(t)
+
+	mails := h.mailbox.Mails()
+	if len(mails) != len(want) {
+		t.Errorf("notifications = %d, want %d", len(mails), len(want))
+	}
+	for _, m := range mails {
+		key := m.To + " " + m.Subject
+		if !want[key] {
+			t.Errorf("unexpected notification %q", key)
+		}
+		delete(want, key)
+		if m.HTML == "" {
+			t.Errorf("notification %q has no HTML", key)
+		}
+	}
+	for key := range want {
+		t.Errorf("missing notification %q", key)
+	}
+}
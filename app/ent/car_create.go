@@ -11,6 +11,7 @@ import (
 	"entgo.io/ent/dialect/sql/sqlgraph"
 	"entgo.io/ent/schema/field"
 	"github.com/jpdel518/go-ent/ent/car"
+	"github.com/jpdel518/go-ent/ent/tenant"
 	"github.com/jpdel518/go-ent/ent/user"
 )
 
@@ -49,6 +50,12 @@ func (cc *CarCreate) SetNillableUpdatedAt(t *time.Time) *CarCreate {
 	return cc
 }
 
+// SetTenantID sets the "tenant_id" field.
+func (cc *CarCreate) SetTenantID(i int) *CarCreate {
+	cc.mutation.SetTenantID(i)
+	return cc
+}
+
 // SetName sets the "name" field.
 func (cc *CarCreate) SetName(s string) *CarCreate {
 	cc.mutation.SetName(s)
@@ -67,6 +74,11 @@ func (cc *CarCreate) SetRegisteredAt(t time.Time) *CarCreate {
 	return cc
 }
 
+// SetTenant sets the "tenant" edge to the Tenant entity.
+func (cc *CarCreate) SetTenant(t *Tenant) *CarCreate {
+	return cc.SetTenantID(t.ID)
+}
+
 // SetOwnerID sets the "owner" edge to the User entity by ID.
 func (cc *CarCreate) SetOwnerID(id int) *CarCreate {
 	cc.mutation.SetOwnerID(id)
@@ -93,7 +105,9 @@ func (cc *CarCreate) Mutation() *CarMutation {
 
 // Save creates the Car in the database.
 func (cc *CarCreate) Save(ctx context.Context) (*Car, error) {
-	cc.defaults()
+	if err := cc.defaults(); err != nil {
+		return nil, err
+	}
 	return withHooks[*Car, CarMutation](ctx, cc.sqlSave, cc.mutation, cc.hooks)
 }
 
@@ -120,15 +134,22 @@ func (cc *CarCreate) ExecX(ctx context.Context) {
 }
 
 // defaults sets the default values of the builder before save.
-func (cc *CarCreate) defaults() {
+func (cc *CarCreate) defaults() error {
 	if _, ok := cc.mutation.CreatedAt(); !ok {
+		if car.DefaultCreatedAt == nil {
+			return fmt.Errorf("ent: uninitialized car.DefaultCreatedAt (forgotten import ent/runtime?)")
+		}
 		v := car.DefaultCreatedAt()
 		cc.mutation.SetCreatedAt(v)
 	}
 	if _, ok := cc.mutation.UpdatedAt(); !ok {
+		if car.DefaultUpdatedAt == nil {
+			return fmt.Errorf("ent: uninitialized car.DefaultUpdatedAt (forgotten import ent/runtime?)")
+		}
 		v := car.DefaultUpdatedAt()
 		cc.mutation.SetUpdatedAt(v)
 	}
+	return nil
 }
 
 // check runs all checks and user-defined validators on the builder.
@@ -139,6 +160,9 @@ func (cc *CarCreate) check() error {
 	if _, ok := cc.mutation.UpdatedAt(); !ok {
 		return &ValidationError{Name: "updated_at", err: errors.New(`ent: missing required field "Car.updated_at"`)}
 	}
+	if _, ok := cc.mutation.TenantID(); !ok {
+		return &ValidationError{Name: "tenant_id", err: errors.New(`ent: missing required field "Car.tenant_id"`)}
+	}
 	if _, ok := cc.mutation.Name(); !ok {
 		return &ValidationError{Name: "name", err: errors.New(`ent: missing required field "Car.name"`)}
 	}
@@ -148,6 +172,9 @@ func (cc *CarCreate) check() error {
 	if _, ok := cc.mutation.RegisteredAt(); !ok {
 		return &ValidationError{Name: "registered_at", err: errors.New(`ent: missing required field "Car.registered_at"`)}
 	}
+	if _, ok := cc.mutation.TenantID(); !ok {
+		return &ValidationError{Name: "tenant", err: errors.New(`ent: missing required edge "Car.tenant"`)}
+	}
 	return nil
 }
 
@@ -194,6 +221,23 @@ func (cc *CarCreate) createSpec() (*Car, *sqlgraph.CreateSpec) {
 		_spec.SetField(car.FieldRegisteredAt, field.TypeTime, value)
 		_node.RegisteredAt = value
 	}
+	if nodes := cc.mutation.TenantIDs(); len(nodes) > 0 {
+		edge := &sqlgraph.EdgeSpec{
+			Rel:     sqlgraph.M2O,
+			Inverse: false,
+			Table:   car.TenantTable,
+			Columns: []string{car.TenantColumn},
+			Bidi:    false,
+			Target: &sqlgraph.EdgeTarget{
+				IDSpec: sqlgraph.NewFieldSpec(tenant.FieldID, field.TypeInt),
+			},
+		}
+		for _, k := range nodes {
+			edge.Target.Nodes = append(edge.Target.Nodes, k)
+		}
+		_node.TenantID = nodes[0]
+		_spec.Edges = append(_spec.Edges, edge)
+	}
 	if nodes := cc.mutation.OwnerIDs(); len(nodes) > 0 {
 		edge := &sqlgraph.EdgeSpec{
 			Rel:     sqlgraph.M2O,
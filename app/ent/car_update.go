@@ -85,7 +85,9 @@ func (cu *CarUpdate) ClearOwner() *CarUpdate {
 
 // Save executes the query and returns the number of nodes affected by the update operation.
 func (cu *CarUpdate) Save(ctx context.Context) (int, error) {
-	cu.defaults()
+	if err := cu.defaults(); err != nil {
+		return 0, err
+	}
 	return withHooks[int, CarMutation](ctx, cu.sqlSave, cu.mutation, cu.hooks)
 }
 
@@ -112,14 +114,29 @@ func (cu *CarUpdate) ExecX(ctx context.Context) {
 }
 
 // defaults sets the default values of the builder before save.
-func (cu *CarUpdate) defaults() {
+func (cu *CarUpdate) defaults() error {
 	if _, ok := cu.mutation.UpdatedAt(); !ok {
+		if car.UpdateDefaultUpdatedAt == nil {
+			return fmt.Errorf("ent: uninitialized car.UpdateDefaultUpdatedAt (forgotten import ent/runtime?)")
+		}
 		v := car.UpdateDefaultUpdatedAt()
 		cu.mutation.SetUpdatedAt(v)
 	}
+	return nil
+}
+
+// check runs all checks and user-defined validators on the builder.
+func (cu *CarUpdate) check() error {
+	if _, ok := cu.mutation.TenantID(); cu.mutation.TenantCleared() && !ok {
+		return errors.New(`ent: clearing a required unique edge "Car.tenant"`)
+	}
+	return nil
 }
 
 func (cu *CarUpdate) sqlSave(ctx context.Context) (n int, err error) {
+	if err := cu.check(); err != nil {
+		return n, err
+	}
 	_spec := sqlgraph.NewUpdateSpec(car.Table, car.Columns, sqlgraph.NewFieldSpec(car.FieldID, field.TypeInt))
 	if ps := cu.mutation.predicates; len(ps) > 0 {
 		_spec.Predicate = func(selector *sql.Selector) {
@@ -258,7 +275,9 @@ func (cuo *CarUpdateOne) Select(field string, fields ...string) *CarUpdateOne {
 
 // Save executes the query and returns the updated Car entity.
 func (cuo *CarUpdateOne) Save(ctx context.Context) (*Car, error) {
-	cuo.defaults()
+	if err := cuo.defaults(); err != nil {
+		return nil, err
+	}
 	return withHooks[*Car, CarMutation](ctx, cuo.sqlSave, cuo.mutation, cuo.hooks)
 }
 
@@ -285,14 +304,29 @@ func (cuo *CarUpdateOne) ExecX(ctx context.Context) {
 }
 
 // defaults sets the default values of the builder before save.
-func (cuo *CarUpdateOne) defaults() {
+func (cuo *CarUpdateOne) defaults() error {
 	if _, ok := cuo.mutation.UpdatedAt(); !ok {
+		if car.UpdateDefaultUpdatedAt == nil {
+			return fmt.Errorf("ent: uninitialized car.UpdateDefaultUpdatedAt (forgotten import ent/runtime?)")
+		}
 		v := car.UpdateDefaultUpdatedAt()
 		cuo.mutation.SetUpdatedAt(v)
 	}
+	return nil
+}
+
+// check runs all checks and user-defined validators on the builder.
+func (cuo *CarUpdateOne) check() error {
+	if _, ok := cuo.mutation.TenantID(); cuo.mutation.TenantCleared() && !ok {
+		return errors.New(`ent: clearing a required unique edge "Car.tenant"`)
+	}
+	return nil
 }
 
 func (cuo *CarUpdateOne) sqlSave(ctx context.Context) (_node *Car, err error) {
+	if err := cuo.check(); err != nil {
+		return _node, err
+	}
 	_spec := sqlgraph.NewUpdateSpec(car.Table, car.Columns, sqlgraph.NewFieldSpec(car.FieldID, field.TypeInt))
 	id, ok := cuo.mutation.ID()
 	if !ok {
@@ -13,7 +13,14 @@ import (
 	"entgo.io/ent/dialect/sql/sqlgraph"
 	"github.com/jpdel518/go-ent/ent/car"
 	"github.com/jpdel518/go-ent/ent/group"
+	"github.com/jpdel518/go-ent/ent/idempotencykey"
+	"github.com/jpdel518/go-ent/ent/job"
+	"github.com/jpdel518/go-ent/ent/notification"
+	"github.com/jpdel518/go-ent/ent/outboxevent"
+	"github.com/jpdel518/go-ent/ent/tenant"
 	"github.com/jpdel518/go-ent/ent/user"
+	"github.com/jpdel518/go-ent/ent/webhookdelivery"
+	"github.com/jpdel518/go-ent/ent/webhooksubscription"
 )
 
 // ent aliases to avoid import conflicts in user's code.
@@ -67,9 +74,16 @@ type OrderFunc func(*sql.Selector)
 // columnChecker returns a function indicates if the column exists in the given column.
 func columnChecker(table string) func(string) error {
 	checks := map[string]func(string) bool{
-		car.Table:   car.ValidColumn,
-		group.Table: group.ValidColumn,
-		user.Table:  user.ValidColumn,
+		car.Table:                 car.ValidColumn,
+		group.Table:               group.ValidColumn,
+		idempotencykey.Table:      idempotencykey.ValidColumn,
+		job.Table:                 job.ValidColumn,
+		notification.Table:        notification.ValidColumn,
+		outboxevent.Table:         outboxevent.ValidColumn,
+		tenant.Table:              tenant.ValidColumn,
+		user.Table:                user.ValidColumn,
+		webhookdelivery.Table:     webhookdelivery.ValidColumn,
+		webhooksubscription.Table: webhooksubscription.ValidColumn,
 	}
 	check, ok := checks[table]
 	if !ok {
@@ -0,0 +1,29 @@
+//go:build ignore
+
+package main
+
+import (
+	"entgo.io/contrib/entgql"
+	"entgo.io/ent/entc"
+	"entgo.io/ent/entc/gen"
+	"log"
+)
+
+func main() {
+	ex, err := entgql.NewExtension(
+		// GraphQL types of the schema are written to graph/ent.graphql and bound in gqlgen.yml
+		entgql.WithSchemaGenerator(),
+		entgql.WithSchemaPath("../graph/ent.graphql"),
+		entgql.WithConfigPath("../gqlgen.yml"),
+	)
+	if err != nil {
+		log.Fatalf("creating entgql extension: %v", err)
+	}
+	opts := []entc.Option{
+		entc.Extensions(ex),
+		entc.FeatureNames("intercept", "sql/versioned-migration"),
+	}
+	if err := entc.Generate("./schema", &gen.Config{}, opts...); err != nil {
+		log.Fatalf("running ent codegen: %v", err)
+	}
+}
@@ -84,7 +84,9 @@ func (gu *GroupUpdate) RemoveUsers(u ...*User) *GroupUpdate {
 
 // Save executes the query and returns the number of nodes affected by the update operation.
 func (gu *GroupUpdate) Save(ctx context.Context) (int, error) {
-	gu.defaults()
+	if err := gu.defaults(); err != nil {
+		return 0, err
+	}
 	return withHooks[int, GroupMutation](ctx, gu.sqlSave, gu.mutation, gu.hooks)
 }
 
@@ -111,11 +113,15 @@ func (gu *GroupUpdate) ExecX(ctx context.Context) {
 }
 
 // defaults sets the default values of the builder before save.
-func (gu *GroupUpdate) defaults() {
+func (gu *GroupUpdate) defaults() error {
 	if _, ok := gu.mutation.UpdatedAt(); !ok {
+		if group.UpdateDefaultUpdatedAt == nil {
+			return fmt.Errorf("ent: uninitialized group.UpdateDefaultUpdatedAt (forgotten import ent/runtime?)")
+		}
 		v := group.UpdateDefaultUpdatedAt()
 		gu.mutation.SetUpdatedAt(v)
 	}
+	return nil
 }
 
 // check runs all checks and user-defined validators on the builder.
@@ -125,6 +131,9 @@ func (gu *GroupUpdate) check() error {
 			return &ValidationError{Name: "name", err: fmt.Errorf(`ent: validator failed for field "Group.name": %w`, err)}
 		}
 	}
+	if _, ok := gu.mutation.TenantID(); gu.mutation.TenantCleared() && !ok {
+		return errors.New(`ent: clearing a required unique edge "Group.tenant"`)
+	}
 	return nil
 }
 
@@ -279,7 +288,9 @@ func (guo *GroupUpdateOne) Select(field string, fields ...string) *GroupUpdateOn
 
 // Save executes the query and returns the updated Group entity.
 func (guo *GroupUpdateOne) Save(ctx context.Context) (*Group, error) {
-	guo.defaults()
+	if err := guo.defaults(); err != nil {
+		return nil, err
+	}
 	return withHooks[*Group, GroupMutation](ctx, guo.sqlSave, guo.mutation, guo.hooks)
 }
 
@@ -306,11 +317,15 @@ func (guo *GroupUpdateOne) ExecX(ctx context.Context) {
 }
 
 // defaults sets the default values of the builder before save.
-func (guo *GroupUpdateOne) defaults() {
+func (guo *GroupUpdateOne) defaults() error {
 	if _, ok := guo.mutation.UpdatedAt(); !ok {
+		if group.UpdateDefaultUpdatedAt == nil {
+			return fmt.Errorf("ent: uninitialized group.UpdateDefaultUpdatedAt (forgotten import ent/runtime?)")
+		}
 		v := group.UpdateDefaultUpdatedAt()
 		guo.mutation.SetUpdatedAt(v)
 	}
+	return nil
 }
 
 // check runs all checks and user-defined validators on the builder.
@@ -320,6 +335,9 @@ func (guo *GroupUpdateOne) check() error {
 			return &ValidationError{Name: "name", err: fmt.Errorf(`ent: validator failed for field "Group.name": %w`, err)}
 		}
 	}
+	if _, ok := guo.mutation.TenantID(); guo.mutation.TenantCleared() && !ok {
+		return errors.New(`ent: clearing a required unique edge "Group.tenant"`)
+	}
 	return nil
 }
 
@@ -0,0 +1,568 @@
+// Code generated by ent, DO NOT EDIT.
+
+package ent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"entgo.io/ent/dialect/sql"
+	"entgo.io/ent/dialect/sql/sqlgraph"
+	"entgo.io/ent/schema/field"
+	"github.com/jpdel518/go-ent/ent/idempotencykey"
+	"github.com/jpdel518/go-ent/ent/predicate"
+)
+
+// IdempotencyKeyUpdate is the builder for updating IdempotencyKey entities.
+type IdempotencyKeyUpdate struct {
+	config
+	hooks    []Hook
+	mutation *IdempotencyKeyMutation
+}
+
+// Where appends a list predicates to the IdempotencyKeyUpdate builder.
+func (iku *IdempotencyKeyUpdate) Where(ps ...predicate.IdempotencyKey) *IdempotencyKeyUpdate {
+	iku.mutation.Where(ps...)
+	return iku
+}
+
+// SetUpdatedAt sets the "updated_at" field.
+func (iku *IdempotencyKeyUpdate) SetUpdatedAt(t time.Time) *IdempotencyKeyUpdate {
+	iku.mutation.SetUpdatedAt(t)
+	return iku
+}
+
+// SetKey sets the "key" field.
+func (iku *IdempotencyKeyUpdate) SetKey(s string) *IdempotencyKeyUpdate {
+	iku.mutation.SetKey(s)
+	return iku
+}
+
+// SetScope sets the "scope" field.
+func (iku *IdempotencyKeyUpdate) SetScope(s string) *IdempotencyKeyUpdate {
+	iku.mutation.SetScope(s)
+	return iku
+}
+
+// SetNillableScope sets the "scope" field if the given value is not nil.
+func (iku *IdempotencyKeyUpdate) SetNillableScope(s *string) *IdempotencyKeyUpdate {
+	if s != nil {
+		iku.SetScope(*s)
+	}
+	return iku
+}
+
+// SetRoute sets the "route" field.
+func (iku *IdempotencyKeyUpdate) SetRoute(s string) *IdempotencyKeyUpdate {
+	iku.mutation.SetRoute(s)
+	return iku
+}
+
+// SetFingerprint sets the "fingerprint" field.
+func (iku *IdempotencyKeyUpdate) SetFingerprint(s string) *IdempotencyKeyUpdate {
+	iku.mutation.SetFingerprint(s)
+	return iku
+}
+
+// SetStatus sets the "status" field.
+func (iku *IdempotencyKeyUpdate) SetStatus(i idempotencykey.Status) *IdempotencyKeyUpdate {
+	iku.mutation.SetStatus(i)
+	return iku
+}
+
+// SetNillableStatus sets the "status" field if the given value is not nil.
+func (iku *IdempotencyKeyUpdate) SetNillableStatus(i *idempotencykey.Status) *IdempotencyKeyUpdate {
+	if i != nil {
+		iku.SetStatus(*i)
+	}
+	return iku
+}
+
+// SetResponseCode sets the "response_code" field.
+func (iku *IdempotencyKeyUpdate) SetResponseCode(i int) *IdempotencyKeyUpdate {
+	iku.mutation.ResetResponseCode()
+	iku.mutation.SetResponseCode(i)
+	return iku
+}
+
+// SetNillableResponseCode sets the "response_code" field if the given value is not nil.
+func (iku *IdempotencyKeyUpdate) SetNillableResponseCode(i *int) *IdempotencyKeyUpdate {
+	if i != nil {
+		iku.SetResponseCode(*i)
+	}
+	return iku
+}
+
+// AddResponseCode adds i to the "response_code" field.
+func (iku *IdempotencyKeyUpdate) AddResponseCode(i int) *IdempotencyKeyUpdate {
+	iku.mutation.AddResponseCode(i)
+	return iku
+}
+
+// ClearResponseCode clears the value of the "response_code" field.
+func (iku *IdempotencyKeyUpdate) ClearResponseCode() *IdempotencyKeyUpdate {
+	iku.mutation.ClearResponseCode()
+	return iku
+}
+
+// SetResponseContentType sets the "response_content_type" field.
+func (iku *IdempotencyKeyUpdate) SetResponseContentType(s string) *IdempotencyKeyUpdate {
+	iku.mutation.SetResponseContentType(s)
+	return iku
+}
+
+// SetNillableResponseContentType sets the "response_content_type" field if the given value is not nil.
+func (iku *IdempotencyKeyUpdate) SetNillableResponseContentType(s *string) *IdempotencyKeyUpdate {
+	if s != nil {
+		iku.SetResponseContentType(*s)
+	}
+	return iku
+}
+
+// ClearResponseContentType clears the value of the "response_content_type" field.
+func (iku *IdempotencyKeyUpdate) ClearResponseContentType() *IdempotencyKeyUpdate {
+	iku.mutation.ClearResponseContentType()
+	return iku
+}
+
+// SetResponseBody sets the "response_body" field.
+func (iku *IdempotencyKeyUpdate) SetResponseBody(b []byte) *IdempotencyKeyUpdate {
+	iku.mutation.SetResponseBody(b)
+	return iku
+}
+
+// ClearResponseBody clears the value of the "response_body" field.
+func (iku *IdempotencyKeyUpdate) ClearResponseBody() *IdempotencyKeyUpdate {
+	iku.mutation.ClearResponseBody()
+	return iku
+}
+
+// SetExpiresAt sets the "expires_at" field.
+func (iku *IdempotencyKeyUpdate) SetExpiresAt(t time.Time) *IdempotencyKeyUpdate {
+	iku.mutation.SetExpiresAt(t)
+	return iku
+}
+
+// Mutation returns the IdempotencyKeyMutation object of the builder.
+func (iku *IdempotencyKeyUpdate) Mutation() *IdempotencyKeyMutation {
+	return iku.mutation
+}
+
+// Save executes the query and returns the number of nodes affected by the update operation.
+func (iku *IdempotencyKeyUpdate) Save(ctx context.Context) (int, error) {
+	iku.defaults()
+	return withHooks[int, IdempotencyKeyMutation](ctx, iku.sqlSave, iku.mutation, iku.hooks)
+}
+
+// SaveX is like Save, but panics if an error occurs.
+func (iku *IdempotencyKeyUpdate) SaveX(ctx context.Context) int {
+	affected, err := iku.Save(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return affected
+}
+
+// Exec executes the query.
+func (iku *IdempotencyKeyUpdate) Exec(ctx context.Context) error {
+	_, err := iku.Save(ctx)
+	return err
+}
+
+// ExecX is like Exec, but panics if an error occurs.
+func (iku *IdempotencyKeyUpdate) ExecX(ctx context.Context) {
+	if err := iku.Exec(ctx); err != nil {
+		panic(err)
+	}
+}
+
+// defaults sets the default values of the builder before save.
+func (iku *IdempotencyKeyUpdate) defaults() {
+	if _, ok := iku.mutation.UpdatedAt(); !ok {
+		v := idempotencykey.UpdateDefaultUpdatedAt()
+		iku.mutation.SetUpdatedAt(v)
+	}
+}
+
+// check runs all checks and user-defined validators on the builder.
+func (iku *IdempotencyKeyUpdate) check() error {
+	if v, ok := iku.mutation.Key(); ok {
+		if err := idempotencykey.KeyValidator(v); err != nil {
+			return &ValidationError{Name: "key", err: fmt.Errorf(`ent: validator failed for field "IdempotencyKey.key": %w`, err)}
+		}
+	}
+	if v, ok := iku.mutation.Route(); ok {
+		if err := idempotencykey.RouteValidator(v); err != nil {
+			return &ValidationError{Name: "route", err: fmt.Errorf(`ent: validator failed for field "IdempotencyKey.route": %w`, err)}
+		}
+	}
+	if v, ok := iku.mutation.Fingerprint(); ok {
+		if err := idempotencykey.FingerprintValidator(v); err != nil {
+			return &ValidationError{Name: "fingerprint", err: fmt.Errorf(`ent: validator failed for field "IdempotencyKey.fingerprint": %w`, err)}
+		}
+	}
+	if v, ok := iku.mutation.Status(); ok {
+		if err := idempotencykey.StatusValidator(v); err != nil {
+			return &ValidationError{Name: "status", err: fmt.Errorf(`ent: validator failed for field "IdempotencyKey.status": %w`, err)}
+		}
+	}
+	return nil
+}
+
+func (iku *IdempotencyKeyUpdate) sqlSave(ctx context.Context) (n int, err error) {
+	if err := iku.check(); err != nil {
+		return n, err
+	}
+	_spec := sqlgraph.NewUpdateSpec(idempotencykey.Table, idempotencykey.Columns, sqlgraph.NewFieldSpec(idempotencykey.FieldID, field.TypeInt))
+	if ps := iku.mutation.predicates; len(ps) > 0 {
+		_spec.Predicate = func(selector *sql.Selector) {
+			for i := range ps {
+				ps[i](selector)
+			}
+		}
+	}
+	if value, ok := iku.mutation.UpdatedAt(); ok {
+		_spec.SetField(idempotencykey.FieldUpdatedAt, field.TypeTime, value)
+	}
+	if value, ok := iku.mutation.Key(); ok {
+		_spec.SetField(idempotencykey.FieldKey, field.TypeString, value)
+	}
+	if value, ok := iku.mutation.Scope(); ok {
+		_spec.SetField(idempotencykey.FieldScope, field.TypeString, value)
+	}
+	if value, ok := iku.mutation.Route(); ok {
+		_spec.SetField(idempotencykey.FieldRoute, field.TypeString, value)
+	}
+	if value, ok := iku.mutation.Fingerprint(); ok {
+		_spec.SetField(idempotencykey.FieldFingerprint, field.TypeString, value)
+	}
+	if value, ok := iku.mutation.Status(); ok {
+		_spec.SetField(idempotencykey.FieldStatus, field.TypeEnum, value)
+	}
+	if value, ok := iku.mutation.ResponseCode(); ok {
+		_spec.SetField(idempotencykey.FieldResponseCode, field.TypeInt, value)
+	}
+	if value, ok := iku.mutation.AddedResponseCode(); ok {
+		_spec.AddField(idempotencykey.FieldResponseCode, field.TypeInt, value)
+	}
+	if iku.mutation.ResponseCodeCleared() {
+		_spec.ClearField(idempotencykey.FieldResponseCode, field.TypeInt)
+	}
+	if value, ok := iku.mutation.ResponseContentType(); ok {
+		_spec.SetField(idempotencykey.FieldResponseContentType, field.TypeString, value)
+	}
+	if iku.mutation.ResponseContentTypeCleared() {
+		_spec.ClearField(idempotencykey.FieldResponseContentType, field.TypeString)
+	}
+	if value, ok := iku.mutation.ResponseBody(); ok {
+		_spec.SetField(idempotencykey.FieldResponseBody, field.TypeBytes, value)
+	}
+	if iku.mutation.ResponseBodyCleared() {
+		_spec.ClearField(idempotencykey.FieldResponseBody, field.TypeBytes)
+	}
+	if value, ok := iku.mutation.ExpiresAt(); ok {
+		_spec.SetField(idempotencykey.FieldExpiresAt, field.TypeTime, value)
+	}
+	if n, err = sqlgraph.UpdateNodes(ctx, iku.driver, _spec); err != nil {
+		if _, ok := err.(*sqlgraph.NotFoundError); ok {
+			err = &NotFoundError{idempotencykey.Label}
+		} else if sqlgraph.IsConstraintError(err) {
+			err = &ConstraintError{msg: err.Error(), wrap: err}
+		}
+		return 0, err
+	}
+	iku.mutation.done = true
+	return n, nil
+}
+
+// IdempotencyKeyUpdateOne is the builder for updating a single IdempotencyKey entity.
+type IdempotencyKeyUpdateOne struct {
+	config
+	fields   []string
+	hooks    []Hook
+	mutation *IdempotencyKeyMutation
+}
+
+// SetUpdatedAt sets the "updated_at" field.
+func (ikuo *IdempotencyKeyUpdateOne) SetUpdatedAt(t time.Time) *IdempotencyKeyUpdateOne {
+	ikuo.mutation.SetUpdatedAt(t)
+	return ikuo
+}
+
+// SetKey sets the "key" field.
+func (ikuo *IdempotencyKeyUpdateOne) SetKey(s string) *IdempotencyKeyUpdateOne {
+	ikuo.mutation.SetKey(s)
+	return ikuo
+}
+
+// SetScope sets the "scope" field.
+func (ikuo *IdempotencyKeyUpdateOne) SetScope(s string) *IdempotencyKeyUpdateOne {
+	ikuo.mutation.SetScope(s)
+	return ikuo
+}
+
+// SetNillableScope sets the "scope" field if the given value is not nil.
+func (ikuo *IdempotencyKeyUpdateOne) SetNillableScope(s *string) *IdempotencyKeyUpdateOne {
+	if s != nil {
+		ikuo.SetScope(*s)
+	}
+	return ikuo
+}
+
+// SetRoute sets the "route" field.
+func (ikuo *IdempotencyKeyUpdateOne) SetRoute(s string) *IdempotencyKeyUpdateOne {
+	ikuo.mutation.SetRoute(s)
+	return ikuo
+}
+
+// SetFingerprint sets the "fingerprint" field.
+func (ikuo *IdempotencyKeyUpdateOne) SetFingerprint(s string) *IdempotencyKeyUpdateOne {
+	ikuo.mutation.SetFingerprint(s)
+	return ikuo
+}
+
+// SetStatus sets the "status" field.
+func (ikuo *IdempotencyKeyUpdateOne) SetStatus(i idempotencykey.Status) *IdempotencyKeyUpdateOne {
+	ikuo.mutation.SetStatus(i)
+	return ikuo
+}
+
+// SetNillableStatus sets the "status" field if the given value is not nil.
+func (ikuo *IdempotencyKeyUpdateOne) SetNillableStatus(i *idempotencykey.Status) *IdempotencyKeyUpdateOne {
+	if i != nil {
+		ikuo.SetStatus(*i)
+	}
+	return ikuo
+}
+
+// SetResponseCode sets the "response_code" field.
+func (ikuo *IdempotencyKeyUpdateOne) SetResponseCode(i int) *IdempotencyKeyUpdateOne {
+	ikuo.mutation.ResetResponseCode()
+	ikuo.mutation.SetResponseCode(i)
+	return ikuo
+}
+
+// SetNillableResponseCode sets the "response_code" field if the given value is not nil.
+func (ikuo *IdempotencyKeyUpdateOne) SetNillableResponseCode(i *int) *IdempotencyKeyUpdateOne {
+	if i != nil {
+		ikuo.SetResponseCode(*i)
+	}
+	return ikuo
+}
+
+// AddResponseCode adds i to the "response_code" field.
+func (ikuo *IdempotencyKeyUpdateOne) AddResponseCode(i int) *IdempotencyKeyUpdateOne {
+	ikuo.mutation.AddResponseCode(i)
+	return ikuo
+}
+
+// ClearResponseCode clears the value of the "response_code" field.
+func (ikuo *IdempotencyKeyUpdateOne) ClearResponseCode() *IdempotencyKeyUpdateOne {
+	ikuo.mutation.ClearResponseCode()
+	return ikuo
+}
+
+// SetResponseContentType sets the "response_content_type" field.
+func (ikuo *IdempotencyKeyUpdateOne) SetResponseContentType(s string) *IdempotencyKeyUpdateOne {
+	ikuo.mutation.SetResponseContentType(s)
+	return ikuo
+}
+
+// SetNillableResponseContentType sets the "response_content_type" field if the given value is not nil.
+func (ikuo *IdempotencyKeyUpdateOne) SetNillableResponseContentType(s *string) *IdempotencyKeyUpdateOne {
+	if s != nil {
+		ikuo.SetResponseContentType(*s)
+	}
+	return ikuo
+}
+
+// ClearResponseContentType clears the value of the "response_content_type" field.
+func (ikuo *IdempotencyKeyUpdateOne) ClearResponseContentType() *IdempotencyKeyUpdateOne {
+	ikuo.mutation.ClearResponseContentType()
+	return ikuo
+}
+
+// SetResponseBody sets the "response_body" field.
+func (ikuo *IdempotencyKeyUpdateOne) SetResponseBody(b []byte) *IdempotencyKeyUpdateOne {
+	ikuo.mutation.SetResponseBody(b)
+	return ikuo
+}
+
+// ClearResponseBody clears the value of the "response_body" field.
+func (ikuo *IdempotencyKeyUpdateOne) ClearResponseBody() *IdempotencyKeyUpdateOne {
+	ikuo.mutation.ClearResponseBody()
+	return ikuo
+}
+
+// SetExpiresAt sets the "expires_at" field.
+func (ikuo *IdempotencyKeyUpdateOne) SetExpiresAt(t time.Time) *IdempotencyKeyUpdateOne {
+	ikuo.mutation.SetExpiresAt(t)
+	return ikuo
+}
+
+// Mutation returns the IdempotencyKeyMutation object of the builder.
+func (ikuo *IdempotencyKeyUpdateOne) Mutation() *IdempotencyKeyMutation {
+	return ikuo.mutation
+}
+
+// Where appends a list predicates to the IdempotencyKeyUpdate builder.
+func (ikuo *IdempotencyKeyUpdateOne) Where(ps ...predicate.IdempotencyKey) *IdempotencyKeyUpdateOne {
+	ikuo.mutation.Where(ps...)
+	return ikuo
+}
+
+// Select allows selecting one or more fields (columns) of the returned entity.
+// The default is selecting all fields defined in the entity schema.
+func (ikuo *IdempotencyKeyUpdateOne) Select(field string, fields ...string) *IdempotencyKeyUpdateOne {
+	ikuo.fields = append([]string{field}, fields...)
+	return ikuo
+}
+
+// Save executes the query and returns the updated IdempotencyKey entity.
+func (ikuo *IdempotencyKeyUpdateOne) Save(ctx context.Context) (*IdempotencyKey, error) {
+	ikuo.defaults()
+	return withHooks[*IdempotencyKey, IdempotencyKeyMutation](ctx, ikuo.sqlSave, ikuo.mutation, ikuo.hooks)
+}
+
+// SaveX is like Save, but panics if an error occurs.
+func (ikuo *IdempotencyKeyUpdateOne) SaveX(ctx context.Context) *IdempotencyKey {
+	node, err := ikuo.Save(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return node
+}
+
+// Exec executes the query on the entity.
+func (ikuo *IdempotencyKeyUpdateOne) Exec(ctx context.Context) error {
+	_, err := ikuo.Save(ctx)
+	return err
+}
+
+// ExecX is like Exec, but panics if an error occurs.
+func (ikuo *IdempotencyKeyUpdateOne) ExecX(ctx context.Context) {
+	if err := ikuo.Exec(ctx); err != nil {
+		panic(err)
+	}
+}
+
+// defaults sets the default values of the builder before save.
+func (ikuo *IdempotencyKeyUpdateOne) defaults() {
+	if _, ok := ikuo.mutation.UpdatedAt(); !ok {
+		v := idempotencykey.UpdateDefaultUpdatedAt()
+		ikuo.mutation.SetUpdatedAt(v)
+	}
+}
+
+// check runs all checks and user-defined validators on the builder.
+func (ikuo *IdempotencyKeyUpdateOne) check() error {
+	if v, ok := ikuo.mutation.Key(); ok {
+		if err := idempotencykey.KeyValidator(v); err != nil {
+			return &ValidationError{Name: "key", err: fmt.Errorf(`ent: validator failed for field "IdempotencyKey.key": %w`, err)}
+		}
+	}
+	if v, ok := ikuo.mutation.Route(); ok {
+		if err := idempotencykey.RouteValidator(v); err != nil {
+			return &ValidationError{Name: "route", err: fmt.Errorf(`ent: validator failed for field "IdempotencyKey.route": %w`, err)}
+		}
+	}
+	if v, ok := ikuo.mutation.Fingerprint(); ok {
+		if err := idempotencykey.FingerprintValidator(v); err != nil {
+			return &ValidationError{Name: "fingerprint", err: fmt.Errorf(`ent: validator failed for field "IdempotencyKey.fingerprint": %w`, err)}
+		}
+	}
+	if v, ok := ikuo.mutation.Status(); ok {
+		if err := idempotencykey.StatusValidator(v); err != nil {
+			return &ValidationError{Name: "status", err: fmt.Errorf(`ent: validator failed for field "IdempotencyKey.status": %w`, err)}
+		}
+	}
+	return nil
+}
+
+func (ikuo *IdempotencyKeyUpdateOne) sqlSave(ctx context.Context) (_node *IdempotencyKey, err error) {
+	if err := ikuo.check(); err != nil {
+		return _node, err
+	}
+	_spec := sqlgraph.NewUpdateSpec(idempotencykey.Table, idempotencykey.Columns, sqlgraph.NewFieldSpec(idempotencykey.FieldID, field.TypeInt))
+	id, ok := ikuo.mutation.ID()
+	if !ok {
+		return nil, &ValidationError{Name: "id", err: errors.New(`ent: missing "IdempotencyKey.id" for update`)}
+	}
+	_spec.Node.ID.Value = id
+	if fields := ikuo.fields; len(fields) > 0 {
+		_spec.Node.Columns = make([]string, 0, len(fields))
+		_spec.Node.Columns = append(_spec.Node.Columns, idempotencykey.FieldID)
+		for _, f := range fields {
+			if !idempotencykey.ValidColumn(f) {
+				return nil, &ValidationError{Name: f, err: fmt.Errorf("ent: invalid field %q for query", f)}
+			}
+			if f != idempotencykey.FieldID {
+				_spec.Node.Columns = append(_spec.Node.Columns, f)
+			}
+		}
+	}
+	if ps := ikuo.mutation.predicates; len(ps) > 0 {
+		_spec.Predicate = func(selector *sql.Selector) {
+			for i := range ps {
+				ps[i](selector)
+			}
+		}
+	}
+	if value, ok := ikuo.mutation.UpdatedAt(); ok {
+		_spec.SetField(idempotencykey.FieldUpdatedAt, field.TypeTime, value)
+	}
+	if value, ok := ikuo.mutation.Key(); ok {
+		_spec.SetField(idempotencykey.FieldKey, field.TypeString, value)
+	}
+	if value, ok := ikuo.mutation.Scope(); ok {
+		_spec.SetField(idempotencykey.FieldScope, field.TypeString, value)
+	}
+	if value, ok := ikuo.mutation.Route(); ok {
+		_spec.SetField(idempotencykey.FieldRoute, field.TypeString, value)
+	}
+	if value, ok := ikuo.mutation.Fingerprint(); ok {
+		_spec.SetField(idempotencykey.FieldFingerprint, field.TypeString, value)
+	}
+	if value, ok := ikuo.mutation.Status(); ok {
+		_spec.SetField(idempotencykey.FieldStatus, field.TypeEnum, value)
+	}
+	if value, ok := ikuo.mutation.ResponseCode(); ok {
+		_spec.SetField(idempotencykey.FieldResponseCode, field.TypeInt, value)
+	}
+	if value, ok := ikuo.mutation.AddedResponseCode(); ok {
+		_spec.AddField(idempotencykey.FieldResponseCode, field.TypeInt, value)
+	}
+	if ikuo.mutation.ResponseCodeCleared() {
+		_spec.ClearField(idempotencykey.FieldResponseCode, field.TypeInt)
+	}
+	if value, ok := ikuo.mutation.ResponseContentType(); ok {
+		_spec.SetField(idempotencykey.FieldResponseContentType, field.TypeString, value)
+	}
+	if ikuo.mutation.ResponseContentTypeCleared() {
+		_spec.ClearField(idempotencykey.FieldResponseContentType, field.TypeString)
+	}
+	if value, ok := ikuo.mutation.ResponseBody(); ok {
+		_spec.SetField(idempotencykey.FieldResponseBody, field.TypeBytes, value)
+	}
+	if ikuo.mutation.ResponseBodyCleared() {
+		_spec.ClearField(idempotencykey.FieldResponseBody, field.TypeBytes)
+	}
+	if value, ok := ikuo.mutation.ExpiresAt(); ok {
+		_spec.SetField(idempotencykey.FieldExpiresAt, field.TypeTime, value)
+	}
+	_node = &IdempotencyKey{config: ikuo.config}
+	_spec.Assign = _node.assignValues
+	_spec.ScanValues = _node.scanValues
+	if err = sqlgraph.UpdateNode(ctx, ikuo.driver, _spec); err != nil {
+		if _, ok := err.(*sqlgraph.NotFoundError); ok {
+			err = &NotFoundError{idempotencykey.Label}
+		} else if sqlgraph.IsConstraintError(err) {
+			err = &ConstraintError{msg: err.Error(), wrap: err}
+		}
+		return nil, err
+	}
+	ikuo.mutation.done = true
+	return _node, nil
+}
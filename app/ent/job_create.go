@@ -0,0 +1,437 @@
+// Code generated by ent, DO NOT EDIT.
+
+package ent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"entgo.io/ent/dialect/sql/sqlgraph"
+	"entgo.io/ent/schema/field"
+	"github.com/jpdel518/go-ent/ent/job"
+	"github.com/jpdel518/go-ent/ent/tenant"
+)
+
+// JobCreate is the builder for creating a Job entity.
+type JobCreate struct {
+	config
+	mutation *JobMutation
+	hooks    []Hook
+}
+
+// SetCreatedAt sets the "created_at" field.
+func (jc *JobCreate) SetCreatedAt(t time.Time) *JobCreate {
+	jc.mutation.SetCreatedAt(t)
+	return jc
+}
+
+// SetNillableCreatedAt sets the "created_at" field if the given value is not nil.
+func (jc *JobCreate) SetNillableCreatedAt(t *time.Time) *JobCreate {
+	if t != nil {
+		jc.SetCreatedAt(*t)
+	}
+	return jc
+}
+
+// SetUpdatedAt sets the "updated_at" field.
+func (jc *JobCreate) SetUpdatedAt(t time.Time) *JobCreate {
+	jc.mutation.SetUpdatedAt(t)
+	return jc
+}
+
+// SetNillableUpdatedAt sets the "updated_at" field if the given value is not nil.
+func (jc *JobCreate) SetNillableUpdatedAt(t *time.Time) *JobCreate {
+	if t != nil {
+		jc.SetUpdatedAt(*t)
+	}
+	return jc
+}
+
+// SetTenantID sets the "tenant_id" field.
+func (jc *JobCreate) SetTenantID(i int) *JobCreate {
+	jc.mutation.SetTenantID(i)
+	return jc
+}
+
+// SetType sets the "type" field.
+func (jc *JobCreate) SetType(s string) *JobCreate {
+	jc.mutation.SetType(s)
+	return jc
+}
+
+// SetStatus sets the "status" field.
+func (jc *JobCreate) SetStatus(j job.Status) *JobCreate {
+	jc.mutation.SetStatus(j)
+	return jc
+}
+
+// SetNillableStatus sets the "status" field if the given value is not nil.
+func (jc *JobCreate) SetNillableStatus(j *job.Status) *JobCreate {
+	if j != nil {
+		jc.SetStatus(*j)
+	}
+	return jc
+}
+
+// SetProgress sets the "progress" field.
+func (jc *JobCreate) SetProgress(i int) *JobCreate {
+	jc.mutation.SetProgress(i)
+	return jc
+}
+
+// SetNillableProgress sets the "progress" field if the given value is not nil.
+func (jc *JobCreate) SetNillableProgress(i *int) *JobCreate {
+	if i != nil {
+		jc.SetProgress(*i)
+	}
+	return jc
+}
+
+// SetParams sets the "params" field.
+func (jc *JobCreate) SetParams(m map[string]string) *JobCreate {
+	jc.mutation.SetParams(m)
+	return jc
+}
+
+// SetResult sets the "result" field.
+func (jc *JobCreate) SetResult(m map[string]interface{}) *JobCreate {
+	jc.mutation.SetResult(m)
+	return jc
+}
+
+// SetErrorMessage sets the "error_message" field.
+func (jc *JobCreate) SetErrorMessage(s string) *JobCreate {
+	jc.mutation.SetErrorMessage(s)
+	return jc
+}
+
+// SetNillableErrorMessage sets the "error_message" field if the given value is not nil.
+func (jc *JobCreate) SetNillableErrorMessage(s *string) *JobCreate {
+	if s != nil {
+		jc.SetErrorMessage(*s)
+	}
+	return jc
+}
+
+// SetErrorReport sets the "error_report" field.
+func (jc *JobCreate) SetErrorReport(s string) *JobCreate {
+	jc.mutation.SetErrorReport(s)
+	return jc
+}
+
+// SetNillableErrorReport sets the "error_report" field if the given value is not nil.
+func (jc *JobCreate) SetNillableErrorReport(s *string) *JobCreate {
+	if s != nil {
+		jc.SetErrorReport(*s)
+	}
+	return jc
+}
+
+// SetStartedAt sets the "started_at" field.
+func (jc *JobCreate) SetStartedAt(t time.Time) *JobCreate {
+	jc.mutation.SetStartedAt(t)
+	return jc
+}
+
+// SetNillableStartedAt sets the "started_at" field if the given value is not nil.
+func (jc *JobCreate) SetNillableStartedAt(t *time.Time) *JobCreate {
+	if t != nil {
+		jc.SetStartedAt(*t)
+	}
+	return jc
+}
+
+// SetFinishedAt sets the "finished_at" field.
+func (jc *JobCreate) SetFinishedAt(t time.Time) *JobCreate {
+	jc.mutation.SetFinishedAt(t)
+	return jc
+}
+
+// SetNillableFinishedAt sets the "finished_at" field if the given value is not nil.
+func (jc *JobCreate) SetNillableFinishedAt(t *time.Time) *JobCreate {
+	if t != nil {
+		jc.SetFinishedAt(*t)
+	}
+	return jc
+}
+
+// SetTenant sets the "tenant" edge to the Tenant entity.
+func (jc *JobCreate) SetTenant(t *Tenant) *JobCreate {
+	return jc.SetTenantID(t.ID)
+}
+
+// Mutation returns the JobMutation object of the builder.
+func (jc *JobCreate) Mutation() *JobMutation {
+	return jc.mutation
+}
+
+// Save creates the Job in the database.
+func (jc *JobCreate) Save(ctx context.Context) (*Job, error) {
+	if err := jc.defaults(); err != nil {
+		return nil, err
+	}
+	return withHooks[*Job, JobMutation](ctx, jc.sqlSave, jc.mutation, jc.hooks)
+}
+
+// SaveX calls Save and panics if Save returns an error.
+func (jc *JobCreate) SaveX(ctx context.Context) *Job {
+	v, err := jc.Save(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return v
+}
+
+// Exec executes the query.
+func (jc *JobCreate) Exec(ctx context.Context) error {
+	_, err := jc.Save(ctx)
+	return err
+}
+
+// ExecX is like Exec, but panics if an error occurs.
+func (jc *JobCreate) ExecX(ctx context.Context) {
+	if err := jc.Exec(ctx); err != nil {
+		panic(err)
+	}
+}
+
+// defaults sets the default values of the builder before save.
+func (jc *JobCreate) defaults() error {
+	if _, ok := jc.mutation.CreatedAt(); !ok {
+		if job.DefaultCreatedAt == nil {
+			return fmt.Errorf("ent: uninitialized job.DefaultCreatedAt (forgotten import ent/runtime?)")
+		}
+		v := job.DefaultCreatedAt()
+		jc.mutation.SetCreatedAt(v)
+	}
+	if _, ok := jc.mutation.UpdatedAt(); !ok {
+		if job.DefaultUpdatedAt == nil {
+			return fmt.Errorf("ent: uninitialized job.DefaultUpdatedAt (forgotten import ent/runtime?)")
+		}
+		v := job.DefaultUpdatedAt()
+		jc.mutation.SetUpdatedAt(v)
+	}
+	if _, ok := jc.mutation.Status(); !ok {
+		v := job.DefaultStatus
+		jc.mutation.SetStatus(v)
+	}
+	if _, ok := jc.mutation.Progress(); !ok {
+		v := job.DefaultProgress
+		jc.mutation.SetProgress(v)
+	}
+	return nil
+}
+
+// check runs all checks and user-defined validators on the builder.
+func (jc *JobCreate) check() error {
+	if _, ok := jc.mutation.CreatedAt(); !ok {
+		return &ValidationError{Name: "created_at", err: errors.New(`ent: missing required field "Job.created_at"`)}
+	}
+	if _, ok := jc.mutation.UpdatedAt(); !ok {
+		return &ValidationError{Name: "updated_at", err: errors.New(`ent: missing required field "Job.updated_at"`)}
+	}
+	if _, ok := jc.mutation.TenantID(); !ok {
+		return &ValidationError{Name: "tenant_id", err: errors.New(`ent: missing required field "Job.tenant_id"`)}
+	}
+	if _, ok := jc.mutation.GetType(); !ok {
+		return &ValidationError{Name: "type", err: errors.New(`ent: missing required field "Job.type"`)}
+	}
+	if v, ok := jc.mutation.GetType(); ok {
+		if err := job.TypeValidator(v); err != nil {
+			return &ValidationError{Name: "type", err: fmt.Errorf(`ent: validator failed for field "Job.type": %w`, err)}
+		}
+	}
+	if _, ok := jc.mutation.Status(); !ok {
+		return &ValidationError{Name: "status", err: errors.New(`ent: missing required field "Job.status"`)}
+	}
+	if v, ok := jc.mutation.Status(); ok {
+		if err := job.StatusValidator(v); err != nil {
+			return &ValidationError{Name: "status", err: fmt.Errorf(`ent: validator failed for field "Job.status": %w`, err)}
+		}
+	}
+	if _, ok := jc.mutation.Progress(); !ok {
+		return &ValidationError{Name: "progress", err: errors.New(`ent: missing required field "Job.progress"`)}
+	}
+	if v, ok := jc.mutation.Progress(); ok {
+		if err := job.ProgressValidator(v); err != nil {
+			return &ValidationError{Name: "progress", err: fmt.Errorf(`ent: validator failed for field "Job.progress": %w`, err)}
+		}
+	}
+	if _, ok := jc.mutation.TenantID(); !ok {
+		return &ValidationError{Name: "tenant", err: errors.New(`ent: missing required edge "Job.tenant"`)}
+	}
+	return nil
+}
+
+func (jc *JobCreate) sqlSave(ctx context.Context) (*Job, error) {
+	if err := jc.check(); err != nil {
+		return nil, err
+	}
+	_node, _spec := jc.createSpec()
+	if err := sqlgraph.CreateNode(ctx, jc.driver, _spec); err != nil {
+		if sqlgraph.IsConstraintError(err) {
+			err = &ConstraintError{msg: err.Error(), wrap: err}
+		}
+		return nil, err
+	}
+	id := _spec.ID.Value.(int64)
+	_node.ID = int(id)
+	jc.mutation.id = &_node.ID
+	jc.mutation.done = true
+	return _node, nil
+}
+
+func (jc *JobCreate) createSpec() (*Job, *sqlgraph.CreateSpec) {
+	var (
+		_node = &Job{config: jc.config}
+		_spec = sqlgraph.NewCreateSpec(job.Table, sqlgraph.NewFieldSpec(job.FieldID, field.TypeInt))
+	)
+	if value, ok := jc.mutation.CreatedAt(); ok {
+		_spec.SetField(job.FieldCreatedAt, field.TypeTime, value)
+		_node.CreatedAt = value
+	}
+	if value, ok := jc.mutation.UpdatedAt(); ok {
+		_spec.SetField(job.FieldUpdatedAt, field.TypeTime, value)
+		_node.UpdatedAt = value
+	}
+	if value, ok := jc.mutation.GetType(); ok {
+		_spec.SetField(job.FieldType, field.TypeString, value)
+		_node.Type = value
+	}
+	if value, ok := jc.mutation.Status(); ok {
+		_spec.SetField(job.FieldStatus, field.TypeEnum, value)
+		_node.Status = value
+	}
+	if value, ok := jc.mutation.Progress(); ok {
+		_spec.SetField(job.FieldProgress, field.TypeInt, value)
+		_node.Progress = value
+	}
+	if value, ok := jc.mutation.Params(); ok {
+		_spec.SetField(job.FieldParams, field.TypeJSON, value)
+		_node.Params = value
+	}
+	if value, ok := jc.mutation.Result(); ok {
+		_spec.SetField(job.FieldResult, field.TypeJSON, value)
+		_node.Result = value
+	}
+	if value, ok := jc.mutation.ErrorMessage(); ok {
+		_spec.SetField(job.FieldErrorMessage, field.TypeString, value)
+		_node.ErrorMessage = value
+	}
+	if value, ok := jc.mutation.ErrorReport(); ok {
+		_spec.SetField(job.FieldErrorReport, field.TypeString, value)
+		_node.ErrorReport = value
+	}
+	if value, ok := jc.mutation.StartedAt(); ok {
+		_spec.SetField(job.FieldStartedAt, field.TypeTime, value)
+		_node.StartedAt = &value
+	}
+	if value, ok := jc.mutation.FinishedAt(); ok {
+		_spec.SetField(job.FieldFinishedAt, field.TypeTime, value)
+		_node.FinishedAt = &value
+	}
+	if nodes := jc.mutation.TenantIDs(); len(nodes) > 0 {
+		edge := &sqlgraph.EdgeSpec{
+			Rel:     sqlgraph.M2O,
+			Inverse: false,
+			Table:   job.TenantTable,
+			Columns: []string{job.TenantColumn},
+			Bidi:    false,
+			Target: &sqlgraph.EdgeTarget{
+				IDSpec: sqlgraph.NewFieldSpec(tenant.FieldID, field.TypeInt),
+			},
+		}
+		for _, k := range nodes {
+			edge.Target.Nodes = append(edge.Target.Nodes, k)
+		}
+		_node.TenantID = nodes[0]
+		_spec.Edges = append(_spec.Edges, edge)
+	}
+	return _node, _spec
+}
+
+// JobCreateBulk is the builder for creating many Job entities in bulk.
+type JobCreateBulk struct {
+	config
+	builders []*JobCreate
+}
+
+// Save creates the Job entities in the database.
+func (jcb *JobCreateBulk) Save(ctx context.Context) ([]*Job, error) {
+	specs := make([]*sqlgraph.CreateSpec, len(jcb.builders))
+	nodes := make([]*Job, len(jcb.builders))
+	mutators := make([]Mutator, len(jcb.builders))
+	for i := range jcb.builders {
+		func(i int, root context.Context) {
+			builder := jcb.builders[i]
+			builder.defaults()
+			var mut Mutator = MutateFunc(func(ctx context.Context, m Mutation) (Value, error) {
+				mutation, ok := m.(*JobMutation)
+				if !ok {
+					return nil, fmt.Errorf("unexpected mutation type %T", m)
+				}
+				if err := builder.check(); err != nil {
+					return nil, err
+				}
+				builder.mutation = mutation
+				nodes[i], specs[i] = builder.createSpec()
+				var err error
+				if i < len(mutators)-1 {
+					_, err = mutators[i+1].Mutate(root, jcb.builders[i+1].mutation)
+				} else {
+					spec := &sqlgraph.BatchCreateSpec{Nodes: specs}
+					// Invoke the actual operation on the latest mutation in the chain.
+					if err = sqlgraph.BatchCreate(ctx, jcb.driver, spec); err != nil {
+						if sqlgraph.IsConstraintError(err) {
+							err = &ConstraintError{msg: err.Error(), wrap: err}
+						}
+					}
+				}
+				if err != nil {
+					return nil, err
+				}
+				mutation.id = &nodes[i].ID
+				if specs[i].ID.Value != nil {
+					id := specs[i].ID.Value.(int64)
+					nodes[i].ID = int(id)
+				}
+				mutation.done = true
+				return nodes[i], nil
+			})
+			for i := len(builder.hooks) - 1; i >= 0; i-- {
+				mut = builder.hooks[i](mut)
+			}
+			mutators[i] = mut
+		}(i, ctx)
+	}
+	if len(mutators) > 0 {
+		if _, err := mutators[0].Mutate(ctx, jcb.builders[0].mutation); err != nil {
+			return nil, err
+		}
+	}
+	return nodes, nil
+}
+
+// SaveX is like Save, but panics if an error occurs.
+func (jcb *JobCreateBulk) SaveX(ctx context.Context) []*Job {
+	v, err := jcb.Save(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return v
+}
+
+// Exec executes the query.
+func (jcb *JobCreateBulk) Exec(ctx context.Context) error {
+	_, err := jcb.Save(ctx)
+	return err
+}
+
+// ExecX is like Exec, but panics if an error occurs.
+func (jcb *JobCreateBulk) ExecX(ctx context.Context) {
+	if err := jcb.Exec(ctx); err != nil {
+		panic(err)
+	}
+}
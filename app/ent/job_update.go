@@ -0,0 +1,662 @@
+// Code generated by ent, DO NOT EDIT.
+
+package ent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"entgo.io/ent/dialect/sql"
+	"entgo.io/ent/dialect/sql/sqlgraph"
+	"entgo.io/ent/schema/field"
+	"github.com/jpdel518/go-ent/ent/job"
+	"github.com/jpdel518/go-ent/ent/predicate"
+)
+
+// JobUpdate is the builder for updating Job entities.
+type JobUpdate struct {
+	config
+	hooks    []Hook
+	mutation *JobMutation
+}
+
+// Where appends a list predicates to the JobUpdate builder.
+func (ju *JobUpdate) Where(ps ...predicate.Job) *JobUpdate {
+	ju.mutation.Where(ps...)
+	return ju
+}
+
+// SetUpdatedAt sets the "updated_at" field.
+func (ju *JobUpdate) SetUpdatedAt(t time.Time) *JobUpdate {
+	ju.mutation.SetUpdatedAt(t)
+	return ju
+}
+
+// SetType sets the "type" field.
+func (ju *JobUpdate) SetType(s string) *JobUpdate {
+	ju.mutation.SetType(s)
+	return ju
+}
+
+// SetStatus sets the "status" field.
+func (ju *JobUpdate) SetStatus(j job.Status) *JobUpdate {
+	ju.mutation.SetStatus(j)
+	return ju
+}
+
+// SetNillableStatus sets the "status" field if the given value is not nil.
+func (ju *JobUpdate) SetNillableStatus(j *job.Status) *JobUpdate {
+	if j != nil {
+		ju.SetStatus(*j)
+	}
+	return ju
+}
+
+// SetProgress sets the "progress" field.
+func (ju *JobUpdate) SetProgress(i int) *JobUpdate {
+	ju.mutation.ResetProgress()
+	ju.mutation.SetProgress(i)
+	return ju
+}
+
+// SetNillableProgress sets the "progress" field if the given value is not nil.
+func (ju *JobUpdate) SetNillableProgress(i *int) *JobUpdate {
+	if i != nil {
+		ju.SetProgress(*i)
+	}
+	return ju
+}
+
+// AddProgress adds i to the "progress" field.
+func (ju *JobUpdate) AddProgress(i int) *JobUpdate {
+	ju.mutation.AddProgress(i)
+	return ju
+}
+
+// SetParams sets the "params" field.
+func (ju *JobUpdate) SetParams(m map[string]string) *JobUpdate {
+	ju.mutation.SetParams(m)
+	return ju
+}
+
+// ClearParams clears the value of the "params" field.
+func (ju *JobUpdate) ClearParams() *JobUpdate {
+	ju.mutation.ClearParams()
+	return ju
+}
+
+// SetResult sets the "result" field.
+func (ju *JobUpdate) SetResult(m map[string]interface{}) *JobUpdate {
+	ju.mutation.SetResult(m)
+	return ju
+}
+
+// ClearResult clears the value of the "result" field.
+func (ju *JobUpdate) ClearResult() *JobUpdate {
+	ju.mutation.ClearResult()
+	return ju
+}
+
+// SetErrorMessage sets the "error_message" field.
+func (ju *JobUpdate) SetErrorMessage(s string) *JobUpdate {
+	ju.mutation.SetErrorMessage(s)
+	return ju
+}
+
+// SetNillableErrorMessage sets the "error_message" field if the given value is not nil.
+func (ju *JobUpdate) SetNillableErrorMessage(s *string) *JobUpdate {
+	if s != nil {
+		ju.SetErrorMessage(*s)
+	}
+	return ju
+}
+
+// ClearErrorMessage clears the value of the "error_message" field.
+func (ju *JobUpdate) ClearErrorMessage() *JobUpdate {
+	ju.mutation.ClearErrorMessage()
+	return ju
+}
+
+// SetErrorReport sets the "error_report" field.
+func (ju *JobUpdate) SetErrorReport(s string) *JobUpdate {
+	ju.mutation.SetErrorReport(s)
+	return ju
+}
+
+// SetNillableErrorReport sets the "error_report" field if the given value is not nil.
+func (ju *JobUpdate) SetNillableErrorReport(s *string) *JobUpdate {
+	if s != nil {
+		ju.SetErrorReport(*s)
+	}
+	return ju
+}
+
+// ClearErrorReport clears the value of the "error_report" field.
+func (ju *JobUpdate) ClearErrorReport() *JobUpdate {
+	ju.mutation.ClearErrorReport()
+	return ju
+}
+
+// SetStartedAt sets the "started_at" field.
+func (ju *JobUpdate) SetStartedAt(t time.Time) *JobUpdate {
+	ju.mutation.SetStartedAt(t)
+	return ju
+}
+
+// SetNillableStartedAt sets the "started_at" field if the given value is not nil.
+func (ju *JobUpdate) SetNillableStartedAt(t *time.Time) *JobUpdate {
+	if t != nil {
+		ju.SetStartedAt(*t)
+	}
+	return ju
+}
+
+// ClearStartedAt clears the value of the "started_at" field.
+func (ju *JobUpdate) ClearStartedAt() *JobUpdate {
+	ju.mutation.ClearStartedAt()
+	return ju
+}
+
+// SetFinishedAt sets the "finished_at" field.
+func (ju *JobUpdate) SetFinishedAt(t time.Time) *JobUpdate {
+	ju.mutation.SetFinishedAt(t)
+	return ju
+}
+
+// SetNillableFinishedAt sets the "finished_at" field if the given value is not nil.
+func (ju *JobUpdate) SetNillableFinishedAt(t *time.Time) *JobUpdate {
+	if t != nil {
+		ju.SetFinishedAt(*t)
+	}
+	return ju
+}
+
+// ClearFinishedAt clears the value of the "finished_at" field.
+func (ju *JobUpdate) ClearFinishedAt() *JobUpdate {
+	ju.mutation.ClearFinishedAt()
+	return ju
+}
+
+// Mutation returns the JobMutation object of the builder.
+func (ju *JobUpdate) Mutation() *JobMutation {
+	return ju.mutation
+}
+
+// Save executes the query and returns the number of nodes affected by the update operation.
+func (ju *JobUpdate) Save(ctx context.Context) (int, error) {
+	if err := ju.defaults(); err != nil {
+		return 0, err
+	}
+	return withHooks[int, JobMutation](ctx, ju.sqlSave, ju.mutation, ju.hooks)
+}
+
+// SaveX is like Save, but panics if an error occurs.
+func (ju *JobUpdate) SaveX(ctx context.Context) int {
+	affected, err := ju.Save(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return affected
+}
+
+// Exec executes the query.
+func (ju *JobUpdate) Exec(ctx context.Context) error {
+	_, err := ju.Save(ctx)
+	return err
+}
+
+// ExecX is like Exec, but panics if an error occurs.
+func (ju *JobUpdate) ExecX(ctx context.Context) {
+	if err := ju.Exec(ctx); err != nil {
+		panic(err)
+	}
+}
+
+// defaults sets the default values of the builder before save.
+func (ju *JobUpdate) defaults() error {
+	if _, ok := ju.mutation.UpdatedAt(); !ok {
+		if job.UpdateDefaultUpdatedAt == nil {
+			return fmt.Errorf("ent: uninitialized job.UpdateDefaultUpdatedAt (forgotten import ent/runtime?)")
+		}
+		v := job.UpdateDefaultUpdatedAt()
+		ju.mutation.SetUpdatedAt(v)
+	}
+	return nil
+}
+
+// check runs all checks and user-defined validators on the builder.
+func (ju *JobUpdate) check() error {
+	if v, ok := ju.mutation.GetType(); ok {
+		if err := job.TypeValidator(v); err != nil {
+			return &ValidationError{Name: "type", err: fmt.Errorf(`ent: validator failed for field "Job.type": %w`, err)}
+		}
+	}
+	if v, ok := ju.mutation.Status(); ok {
+		if err := job.StatusValidator(v); err != nil {
+			return &ValidationError{Name: "status", err: fmt.Errorf(`ent: validator failed for field "Job.status": %w`, err)}
+		}
+	}
+	if v, ok := ju.mutation.Progress(); ok {
+		if err := job.ProgressValidator(v); err != nil {
+			return &ValidationError{Name: "progress", err: fmt.Errorf(`ent: validator failed for field "Job.progress": %w`, err)}
+		}
+	}
+	if _, ok := ju.mutation.TenantID(); ju.mutation.TenantCleared() && !ok {
+		return errors.New(`ent: clearing a required unique edge "Job.tenant"`)
+	}
+	return nil
+}
+
+func (ju *JobUpdate) sqlSave(ctx context.Context) (n int, err error) {
+	if err := ju.check(); err != nil {
+		return n, err
+	}
+	_spec := sqlgraph.NewUpdateSpec(job.Table, job.Columns, sqlgraph.NewFieldSpec(job.FieldID, field.TypeInt))
+	if ps := ju.mutation.predicates; len(ps) > 0 {
+		_spec.Predicate = func(selector *sql.Selector) {
+			for i := range ps {
+				ps[i](selector)
+			}
+		}
+	}
+	if value, ok := ju.mutation.UpdatedAt(); ok {
+		_spec.SetField(job.FieldUpdatedAt, field.TypeTime, value)
+	}
+	if value, ok := ju.mutation.GetType(); ok {
+		_spec.SetField(job.FieldType, field.TypeString, value)
+	}
+	if value, ok := ju.mutation.Status(); ok {
+		_spec.SetField(job.FieldStatus, field.TypeEnum, value)
+	}
+	if value, ok := ju.mutation.Progress(); ok {
+		_spec.SetField(job.FieldProgress, field.TypeInt, value)
+	}
+	if value, ok := ju.mutation.AddedProgress(); ok {
+		_spec.AddField(job.FieldProgress, field.TypeInt, value)
+	}
+	if value, ok := ju.mutation.Params(); ok {
+		_spec.SetField(job.FieldParams, field.TypeJSON, value)
+	}
+	if ju.mutation.ParamsCleared() {
+		_spec.ClearField(job.FieldParams, field.TypeJSON)
+	}
+	if value, ok := ju.mutation.Result(); ok {
+		_spec.SetField(job.FieldResult, field.TypeJSON, value)
+	}
+	if ju.mutation.ResultCleared() {
+		_spec.ClearField(job.FieldResult, field.TypeJSON)
+	}
+	if value, ok := ju.mutation.ErrorMessage(); ok {
+		_spec.SetField(job.FieldErrorMessage, field.TypeString, value)
+	}
+	if ju.mutation.ErrorMessageCleared() {
+		_spec.ClearField(job.FieldErrorMessage, field.TypeString)
+	}
+	if value, ok := ju.mutation.ErrorReport(); ok {
+		_spec.SetField(job.FieldErrorReport, field.TypeString, value)
+	}
+	if ju.mutation.ErrorReportCleared() {
+		_spec.ClearField(job.FieldErrorReport, field.TypeString)
+	}
+	if value, ok := ju.mutation.StartedAt(); ok {
+		_spec.SetField(job.FieldStartedAt, field.TypeTime, value)
+	}
+	if ju.mutation.StartedAtCleared() {
+		_spec.ClearField(job.FieldStartedAt, field.TypeTime)
+	}
+	if value, ok := ju.mutation.FinishedAt(); ok {
+		_spec.SetField(job.FieldFinishedAt, field.TypeTime, value)
+	}
+	if ju.mutation.FinishedAtCleared() {
+		_spec.ClearField(job.FieldFinishedAt, field.TypeTime)
+	}
+	if n, err = sqlgraph.UpdateNodes(ctx, ju.driver, _spec); err != nil {
+		if _, ok := err.(*sqlgraph.NotFoundError); ok {
+			err = &NotFoundError{job.Label}
+		} else if sqlgraph.IsConstraintError(err) {
+			err = &ConstraintError{msg: err.Error(), wrap: err}
+		}
+		return 0, err
+	}
+	ju.mutation.done = true
+	return n, nil
+}
+
+// JobUpdateOne is the builder for updating a single Job entity.
+type JobUpdateOne struct {
+	config
+	fields   []string
+	hooks    []Hook
+	mutation *JobMutation
+}
+
+// SetUpdatedAt sets the "updated_at" field.
+func (juo *JobUpdateOne) SetUpdatedAt(t time.Time) *JobUpdateOne {
+	juo.mutation.SetUpdatedAt(t)
+	return juo
+}
+
+// SetType sets the "type" field.
+func (juo *JobUpdateOne) SetType(s string) *JobUpdateOne {
+	juo.mutation.SetType(s)
+	return juo
+}
+
+// SetStatus sets the "status" field.
+func (juo *JobUpdateOne) SetStatus(j job.Status) *JobUpdateOne {
+	juo.mutation.SetStatus(j)
+	return juo
+}
+
+// SetNillableStatus sets the "status" field if the given value is not nil.
+func (juo *JobUpdateOne) SetNillableStatus(j *job.Status) *JobUpdateOne {
+	if j != nil {
+		juo.SetStatus(*j)
+	}
+	return juo
+}
+
+// SetProgress sets the "progress" field.
+func (juo *JobUpdateOne) SetProgress(i int) *JobUpdateOne {
+	juo.mutation.ResetProgress()
+	juo.mutation.SetProgress(i)
+	return juo
+}
+
+// SetNillableProgress sets the "progress" field if the given value is not nil.
+func (juo *JobUpdateOne) SetNillableProgress(i *int) *JobUpdateOne {
+	if i != nil {
+		juo.SetProgress(*i)
+	}
+	return juo
+}
+
+// AddProgress adds i to the "progress" field.
+func (juo *JobUpdateOne) AddProgress(i int) *JobUpdateOne {
+	juo.mutation.AddProgress(i)
+	return juo
+}
+
+// SetParams sets the "params" field.
+func (juo *JobUpdateOne) SetParams(m map[string]string) *JobUpdateOne {
+	juo.mutation.SetParams(m)
+	return juo
+}
+
+// ClearParams clears the value of the "params" field.
+func (juo *JobUpdateOne) ClearParams() *JobUpdateOne {
+	juo.mutation.ClearParams()
+	return juo
+}
+
+// SetResult sets the "result" field.
+func (juo *JobUpdateOne) SetResult(m map[string]interface{}) *JobUpdateOne {
+	juo.mutation.SetResult(m)
+	return juo
+}
+
+// ClearResult clears the value of the "result" field.
+func (juo *JobUpdateOne) ClearResult() *JobUpdateOne {
+	juo.mutation.ClearResult()
+	return juo
+}
+
+// SetErrorMessage sets the "error_message" field.
+func (juo *JobUpdateOne) SetErrorMessage(s string) *JobUpdateOne {
+	juo.mutation.SetErrorMessage(s)
+	return juo
+}
+
+// SetNillableErrorMessage sets the "error_message" field if the given value is not nil.
+func (juo *JobUpdateOne) SetNillableErrorMessage(s *string) *JobUpdateOne {
+	if s != nil {
+		juo.SetErrorMessage(*s)
+	}
+	return juo
+}
+
+// ClearErrorMessage clears the value of the "error_message" field.
+func (juo *JobUpdateOne) ClearErrorMessage() *JobUpdateOne {
+	juo.mutation.ClearErrorMessage()
+	return juo
+}
+
+// SetErrorReport sets the "error_report" field.
+func (juo *JobUpdateOne) SetErrorReport(s string) *JobUpdateOne {
+	juo.mutation.SetErrorReport(s)
+	return juo
+}
+
+// SetNillableErrorReport sets the "error_report" field if the given value is not nil.
+func (juo *JobUpdateOne) SetNillableErrorReport(s *string) *JobUpdateOne {
+	if s != nil {
+		juo.SetErrorReport(*s)
+	}
+	return juo
+}
+
+// ClearErrorReport clears the value of the "error_report" field.
+func (juo *JobUpdateOne) ClearErrorReport() *JobUpdateOne {
+	juo.mutation.ClearErrorReport()
+	return juo
+}
+
+// SetStartedAt sets the "started_at" field.
+func (juo *JobUpdateOne) SetStartedAt(t time.Time) *JobUpdateOne {
+	juo.mutation.SetStartedAt(t)
+	return juo
+}
+
+// SetNillableStartedAt sets the "started_at" field if the given value is not nil.
+func (juo *JobUpdateOne) SetNillableStartedAt(t *time.Time) *JobUpdateOne {
+	if t != nil {
+		juo.SetStartedAt(*t)
+	}
+	return juo
+}
+
+// ClearStartedAt clears the value of the "started_at" field.
+func (juo *JobUpdateOne) ClearStartedAt() *JobUpdateOne {
+	juo.mutation.ClearStartedAt()
+	return juo
+}
+
+// SetFinishedAt sets the "finished_at" field.
+func (juo *JobUpdateOne) SetFinishedAt(t time.Time) *JobUpdateOne {
+	juo.mutation.SetFinishedAt(t)
+	return juo
+}
+
+// SetNillableFinishedAt sets the "finished_at" field if the given value is not nil.
+func (juo *JobUpdateOne) SetNillableFinishedAt(t *time.Time) *JobUpdateOne {
+	if t != nil {
+		juo.SetFinishedAt(*t)
+	}
+	return juo
+}
+
+// ClearFinishedAt clears the value of the "finished_at" field.
+func (juo *JobUpdateOne) ClearFinishedAt() *JobUpdateOne {
+	juo.mutation.ClearFinishedAt()
+	return juo
+}
+
+// Mutation returns the JobMutation object of the builder.
+func (juo *JobUpdateOne) Mutation() *JobMutation {
+	return juo.mutation
+}
+
+// Where appends a list predicates to the JobUpdate builder.
+func (juo *JobUpdateOne) Where(ps ...predicate.Job) *JobUpdateOne {
+	juo.mutation.Where(ps...)
+	return juo
+}
+
+// Select allows selecting one or more fields (columns) of the returned entity.
+// The default is selecting all fields defined in the entity schema.
+func (juo *JobUpdateOne) Select(field string, fields ...string) *JobUpdateOne {
+	juo.fields = append([]string{field}, fields...)
+	return juo
+}
+
+// Save executes the query and returns the updated Job entity.
+func (juo *JobUpdateOne) Save(ctx context.Context) (*Job, error) {
+	if err := juo.defaults(); err != nil {
+		return nil, err
+	}
+	return withHooks[*Job, JobMutation](ctx, juo.sqlSave, juo.mutation, juo.hooks)
+}
+
+// SaveX is like Save, but panics if an error occurs.
+func (juo *JobUpdateOne) SaveX(ctx context.Context) *Job {
+	node, err := juo.Save(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return node
+}
+
+// Exec executes the query on the entity.
+func (juo *JobUpdateOne) Exec(ctx context.Context) error {
+	_, err := juo.Save(ctx)
+	return err
+}
+
+// ExecX is like Exec, but panics if an error occurs.
+func (juo *JobUpdateOne) ExecX(ctx context.Context) {
+	if err := juo.Exec(ctx); err != nil {
+		panic(err)
+	}
+}
+
+// defaults sets the default values of the builder before save.
+func (juo *JobUpdateOne) defaults() error {
+	if _, ok := juo.mutation.UpdatedAt(); !ok {
+		if job.UpdateDefaultUpdatedAt == nil {
+			return fmt.Errorf("ent: uninitialized job.UpdateDefaultUpdatedAt (forgotten import ent/runtime?)")
+		}
+		v := job.UpdateDefaultUpdatedAt()
+		juo.mutation.SetUpdatedAt(v)
+	}
+	return nil
+}
+
+// check runs all checks and user-defined validators on the builder.
+func (juo *JobUpdateOne) check() error {
+	if v, ok := juo.mutation.GetType(); ok {
+		if err := job.TypeValidator(v); err != nil {
+			return &ValidationError{Name: "type", err: fmt.Errorf(`ent: validator failed for field "Job.type": %w`, err)}
+		}
+	}
+	if v, ok := juo.mutation.Status(); ok {
+		if err := job.StatusValidator(v); err != nil {
+			return &ValidationError{Name: "status", err: fmt.Errorf(`ent: validator failed for field "Job.status": %w`, err)}
+		}
+	}
+	if v, ok := juo.mutation.Progress(); ok {
+		if err := job.ProgressValidator(v); err != nil {
+			return &ValidationError{Name: "progress", err: fmt.Errorf(`ent: validator failed for field "Job.progress": %w`, err)}
+		}
+	}
+	if _, ok := juo.mutation.TenantID(); juo.mutation.TenantCleared() && !ok {
+		return errors.New(`ent: clearing a required unique edge "Job.tenant"`)
+	}
+	return nil
+}
+
+func (juo *JobUpdateOne) sqlSave(ctx context.Context) (_node *Job, err error) {
+	if err := juo.check(); err != nil {
+		return _node, err
+	}
+	_spec := sqlgraph.NewUpdateSpec(job.Table, job.Columns, sqlgraph.NewFieldSpec(job.FieldID, field.TypeInt))
+	id, ok := juo.mutation.ID()
+	if !ok {
+		return nil, &ValidationError{Name: "id", err: errors.New(`ent: missing "Job.id" for update`)}
+	}
+	_spec.Node.ID.Value = id
+	if fields := juo.fields; len(fields) > 0 {
+		_spec.Node.Columns = make([]string, 0, len(fields))
+		_spec.Node.Columns = append(_spec.Node.Columns, job.FieldID)
+		for _, f := range fields {
+			if !job.ValidColumn(f) {
+				return nil, &ValidationError{Name: f, err: fmt.Errorf("ent: invalid field %q for query", f)}
+			}
+			if f != job.FieldID {
+				_spec.Node.Columns = append(_spec.Node.Columns, f)
+			}
+		}
+	}
+	if ps := juo.mutation.predicates; len(ps) > 0 {
+		_spec.Predicate = func(selector *sql.Selector) {
+			for i := range ps {
+				ps[i](selector)
+			}
+		}
+	}
+	if value, ok := juo.mutation.UpdatedAt(); ok {
+		_spec.SetField(job.FieldUpdatedAt, field.TypeTime, value)
+	}
+	if value, ok := juo.mutation.GetType(); ok {
+		_spec.SetField(job.FieldType, field.TypeString, value)
+	}
+	if value, ok := juo.mutation.Status(); ok {
+		_spec.SetField(job.FieldStatus, field.TypeEnum, value)
+	}
+	if value, ok := juo.mutation.Progress(); ok {
+		_spec.SetField(job.FieldProgress, field.TypeInt, value)
+	}
+	if value, ok := juo.mutation.AddedProgress(); ok {
+		_spec.AddField(job.FieldProgress, field.TypeInt, value)
+	}
+	if value, ok := juo.mutation.Params(); ok {
+		_spec.SetField(job.FieldParams, field.TypeJSON, value)
+	}
+	if juo.mutation.ParamsCleared() {
+		_spec.ClearField(job.FieldParams, field.TypeJSON)
+	}
+	if value, ok := juo.mutation.Result(); ok {
+		_spec.SetField(job.FieldResult, field.TypeJSON, value)
+	}
+	if juo.mutation.ResultCleared() {
+		_spec.ClearField(job.FieldResult, field.TypeJSON)
+	}
+	if value, ok := juo.mutation.ErrorMessage(); ok {
+		_spec.SetField(job.FieldErrorMessage, field.TypeString, value)
+	}
+	if juo.mutation.ErrorMessageCleared() {
+		_spec.ClearField(job.FieldErrorMessage, field.TypeString)
+	}
+	if value, ok := juo.mutation.ErrorReport(); ok {
+		_spec.SetField(job.FieldErrorReport, field.TypeString, value)
+	}
+	if juo.mutation.ErrorReportCleared() {
+		_spec.ClearField(job.FieldErrorReport, field.TypeString)
+	}
+	if value, ok := juo.mutation.StartedAt(); ok {
+		_spec.SetField(job.FieldStartedAt, field.TypeTime, value)
+	}
+	if juo.mutation.StartedAtCleared() {
+		_spec.ClearField(job.FieldStartedAt, field.TypeTime)
+	}
+	if value, ok := juo.mutation.FinishedAt(); ok {
+		_spec.SetField(job.FieldFinishedAt, field.TypeTime, value)
+	}
+	if juo.mutation.FinishedAtCleared() {
+		_spec.ClearField(job.FieldFinishedAt, field.TypeTime)
+	}
+	_node = &Job{config: juo.config}
+	_spec.Assign = _node.assignValues
+	_spec.ScanValues = _node.scanValues
+	if err = sqlgraph.UpdateNode(ctx, juo.driver, _spec); err != nil {
+		if _, ok := err.(*sqlgraph.NotFoundError); ok {
+			err = &NotFoundError{job.Label}
+		} else if sqlgraph.IsConstraintError(err) {
+			err = &ConstraintError{msg: err.Error(), wrap: err}
+		}
+		return nil, err
+	}
+	juo.mutation.done = true
+	return _node, nil
+}
@@ -0,0 +1,4 @@
+-- Create "jobs" table
+CREATE TABLE `jobs` (`id` bigint NOT NULL AUTO_INCREMENT, `created_at` timestamp NOT NULL, `updated_at` timestamp NOT NULL, `type` varchar(255) NOT NULL, `status` enum('queued','running','succeeded','failed','canceled') NOT NULL DEFAULT 'queued', `progress` bigint NOT NULL DEFAULT 0, `params` json NULL, `result` json NULL, `error_message` longtext NULL, `error_report` varchar(255) NULL, `started_at` timestamp NULL, `finished_at` timestamp NULL, PRIMARY KEY (`id`), INDEX `job_status` (`status`)) CHARSET utf8mb4 COLLATE utf8mb4_bin;
+-- Modify "users" table
+ALTER TABLE `users` ADD COLUMN `avatar` varchar(255) NULL;
@@ -0,0 +1,2 @@
+-- Create "outbox_events" table
+CREATE TABLE `outbox_events` (`id` bigint NOT NULL AUTO_INCREMENT, `event_type` varchar(255) NOT NULL, `aggregate_type` varchar(255) NOT NULL, `aggregate_id` bigint NOT NULL, `payload` json NOT NULL, `occurred_at` timestamp NOT NULL, `attempts` bigint NOT NULL DEFAULT 0, `last_error` longtext NULL, `available_at` timestamp NOT NULL, `delivered_at` timestamp NULL, PRIMARY KEY (`id`), INDEX `outboxevent_delivered_at` (`delivered_at`), INDEX `outboxevent_aggregate_type_aggregate_id` (`aggregate_type`, `aggregate_id`)) CHARSET utf8mb4 COLLATE utf8mb4_bin;
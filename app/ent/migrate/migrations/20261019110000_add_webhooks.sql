@@ -0,0 +1,4 @@
+-- Create "webhook_subscriptions" table
+CREATE TABLE `webhook_subscriptions` (`id` bigint NOT NULL AUTO_INCREMENT, `created_at` timestamp NOT NULL, `updated_at` timestamp NOT NULL, `url` varchar(255) NOT NULL, `secret` varchar(255) NOT NULL, `event_types` json NULL, `active` bool NOT NULL DEFAULT 1, `failure_count` bigint NOT NULL DEFAULT 0, `disabled_at` timestamp NULL, PRIMARY KEY (`id`)) CHARSET utf8mb4 COLLATE utf8mb4_bin;
+-- Create "webhook_deliveries" table
+CREATE TABLE `webhook_deliveries` (`id` bigint NOT NULL AUTO_INCREMENT, `created_at` timestamp NOT NULL, `updated_at` timestamp NOT NULL, `event_id` bigint NOT NULL, `event_type` varchar(255) NOT NULL, `payload` json NOT NULL, `status` enum('pending','succeeded','failed') NOT NULL DEFAULT 'pending', `attempts` bigint NOT NULL DEFAULT 0, `response_code` bigint NULL, `last_error` longtext NULL, `next_attempt_at` timestamp NOT NULL, `delivered_at` timestamp NULL, `webhook_subscription_deliveries` bigint NOT NULL, PRIMARY KEY (`id`), INDEX `webhookdelivery_status_next_attempt_at` (`status`, `next_attempt_at`), UNIQUE INDEX `webhookdelivery_event_id_webhook_subscription_deliveries` (`event_id`, `webhook_subscription_deliveries`), CONSTRAINT `webhook_deliveries_webhook_subscriptions_deliveries` FOREIGN KEY (`webhook_subscription_deliveries`) REFERENCES `webhook_subscriptions` (`id`) ON DELETE CASCADE) CHARSET utf8mb4 COLLATE utf8mb4_bin;
@@ -0,0 +1,2 @@
+-- Create "idempotency_keys" table
+CREATE TABLE `idempotency_keys` (`id` bigint NOT NULL AUTO_INCREMENT, `created_at` timestamp NOT NULL, `updated_at` timestamp NOT NULL, `key` varchar(255) NOT NULL, `scope` varchar(255) NOT NULL DEFAULT '', `route` varchar(255) NOT NULL, `fingerprint` varchar(255) NOT NULL, `status` enum('pending','completed') NOT NULL DEFAULT 'pending', `response_code` bigint NULL, `response_content_type` varchar(255) NULL, `response_body` mediumblob NULL, `expires_at` timestamp NOT NULL, PRIMARY KEY (`id`), UNIQUE INDEX `idempotencykey_scope_key` (`scope`, `key`), INDEX `idempotencykey_expires_at` (`expires_at`)) CHARSET utf8mb4 COLLATE utf8mb4_bin;
@@ -0,0 +1,22 @@
+-- Create "tenants" table
+CREATE TABLE `tenants` (`id` bigint NOT NULL AUTO_INCREMENT, `created_at` timestamp NOT NULL, `updated_at` timestamp NOT NULL, `name` varchar(255) NOT NULL, `slug` varchar(63) NOT NULL, `api_key_hash` varchar(255) NULL, PRIMARY KEY (`id`), UNIQUE INDEX `slug` (`slug`), UNIQUE INDEX `api_key_hash` (`api_key_hash`)) CHARSET utf8mb4 COLLATE utf8mb4_bin;
+-- The existing data belongs to the default tenant
+INSERT INTO `tenants` (`id`, `created_at`, `updated_at`, `name`, `slug`) VALUES (1, NOW(), NOW(), 'Default', 'default');
+-- Modify "users" table
+ALTER TABLE `users` ADD COLUMN `tenant_id` bigint NOT NULL DEFAULT 1, DROP INDEX `email`, ADD UNIQUE INDEX `user_tenant_id_email` (`tenant_id`, `email`), ADD CONSTRAINT `users_tenants_tenant` FOREIGN KEY (`tenant_id`) REFERENCES `tenants` (`id`) ON UPDATE NO ACTION ON DELETE NO ACTION;
+ALTER TABLE `users` ALTER COLUMN `tenant_id` DROP DEFAULT;
+-- Modify "cars" table
+ALTER TABLE `cars` ADD COLUMN `tenant_id` bigint NOT NULL DEFAULT 1, ADD CONSTRAINT `cars_tenants_tenant` FOREIGN KEY (`tenant_id`) REFERENCES `tenants` (`id`) ON UPDATE NO ACTION ON DELETE NO ACTION;
+ALTER TABLE `cars` ALTER COLUMN `tenant_id` DROP DEFAULT;
+-- Modify "groups" table
+ALTER TABLE `groups` ADD COLUMN `tenant_id` bigint NOT NULL DEFAULT 1, ADD CONSTRAINT `groups_tenants_tenant` FOREIGN KEY (`tenant_id`) REFERENCES `tenants` (`id`) ON UPDATE NO ACTION ON DELETE NO ACTION;
+ALTER TABLE `groups` ALTER COLUMN `tenant_id` DROP DEFAULT;
+-- Modify "jobs" table
+ALTER TABLE `jobs` ADD COLUMN `tenant_id` bigint NOT NULL DEFAULT 1, ADD CONSTRAINT `jobs_tenants_tenant` FOREIGN KEY (`tenant_id`) REFERENCES `tenants` (`id`) ON UPDATE NO ACTION ON DELETE NO ACTION;
+ALTER TABLE `jobs` ALTER COLUMN `tenant_id` DROP DEFAULT;
+-- Modify "outbox_events" table
+ALTER TABLE `outbox_events` ADD COLUMN `tenant_id` bigint NOT NULL DEFAULT 1, ADD CONSTRAINT `outbox_events_tenants_tenant` FOREIGN KEY (`tenant_id`) REFERENCES `tenants` (`id`) ON UPDATE NO ACTION ON DELETE NO ACTION;
+ALTER TABLE `outbox_events` ALTER COLUMN `tenant_id` DROP DEFAULT;
+-- Modify "webhook_subscriptions" table
+ALTER TABLE `webhook_subscriptions` ADD COLUMN `tenant_id` bigint NOT NULL DEFAULT 1, ADD CONSTRAINT `webhook_subscriptions_tenants_tenant` FOREIGN KEY (`tenant_id`) REFERENCES `tenants` (`id`) ON UPDATE NO ACTION ON DELETE NO ACTION;
+ALTER TABLE `webhook_subscriptions` ALTER COLUMN `tenant_id` DROP DEFAULT;
@@ -0,0 +1,5 @@
+-- The e-mails are stored trimmed and in lower case. The update fails on the unique index
+-- when a tenant has e-mails which differ only in case, which have to be merged by hand first
+UPDATE `users` SET `email` = LOWER(TRIM(`email`));
+-- Modify "users" table
+ALTER TABLE `users` ADD COLUMN `verified_at` timestamp NULL;
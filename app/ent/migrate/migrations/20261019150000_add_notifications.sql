@@ -0,0 +1,4 @@
+-- Modify "users" table
+ALTER TABLE `users` ADD COLUMN `language` varchar(255) NULL, ADD COLUMN `notify_car_assigned` bool NOT NULL DEFAULT 1, ADD COLUMN `notify_group_added` bool NOT NULL DEFAULT 1;
+-- Create "notifications" table
+CREATE TABLE `notifications` (`id` bigint NOT NULL AUTO_INCREMENT, `created_at` timestamp NOT NULL, `updated_at` timestamp NOT NULL, `user_id` bigint NOT NULL, `event_id` bigint NOT NULL, `kind` varchar(255) NOT NULL, `to` varchar(255) NOT NULL, `subject` varchar(255) NOT NULL, `text` longtext NOT NULL, `html` longtext NULL, `status` enum('pending','sent','failed') NOT NULL DEFAULT 'pending', `attempts` bigint NOT NULL DEFAULT 0, `last_error` longtext NULL, `next_attempt_at` timestamp NOT NULL, `sent_at` timestamp NULL, `tenant_id` bigint NOT NULL, PRIMARY KEY (`id`), INDEX `notification_status_next_attempt_at` (`status`, `next_attempt_at`), UNIQUE INDEX `notification_event_id_user_id_kind` (`event_id`, `user_id`, `kind`), CONSTRAINT `notifications_tenants_tenant` FOREIGN KEY (`tenant_id`) REFERENCES `tenants` (`id`) ON UPDATE NO ACTION ON DELETE NO ACTION) CHARSET utf8mb4 COLLATE utf8mb4_bin;
@@ -0,0 +1,492 @@
+// Code generated by ent, DO NOT EDIT.
+
+package ent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"entgo.io/ent/dialect/sql/sqlgraph"
+	"entgo.io/ent/schema/field"
+	"github.com/jpdel518/go-ent/ent/notification"
+	"github.com/jpdel518/go-ent/ent/tenant"
+)
+
+// NotificationCreate is the builder for creating a Notification entity.
+type NotificationCreate struct {
+	config
+	mutation *NotificationMutation
+	hooks    []Hook
+}
+
+// SetCreatedAt sets the "created_at" field.
+func (nc *NotificationCreate) SetCreatedAt(t time.Time) *NotificationCreate {
+	nc.mutation.SetCreatedAt(t)
+	return nc
+}
+
+// SetNillableCreatedAt sets the "created_at" field if the given value is not nil.
+func (nc *NotificationCreate) SetNillableCreatedAt(t *time.Time) *NotificationCreate {
+	if t != nil {
+		nc.SetCreatedAt(*t)
+	}
+	return nc
+}
+
+// SetUpdatedAt sets the "updated_at" field.
+func (nc *NotificationCreate) SetUpdatedAt(t time.Time) *NotificationCreate {
+	nc.mutation.SetUpdatedAt(t)
+	return nc
+}
+
+// SetNillableUpdatedAt sets the "updated_at" field if the given value is not nil.
+func (nc *NotificationCreate) SetNillableUpdatedAt(t *time.Time) *NotificationCreate {
+	if t != nil {
+		nc.SetUpdatedAt(*t)
+	}
+	return nc
+}
+
+// SetTenantID sets the "tenant_id" field.
+func (nc *NotificationCreate) SetTenantID(i int) *NotificationCreate {
+	nc.mutation.SetTenantID(i)
+	return nc
+}
+
+// SetUserID sets the "user_id" field.
+func (nc *NotificationCreate) SetUserID(i int) *NotificationCreate {
+	nc.mutation.SetUserID(i)
+	return nc
+}
+
+// SetEventID sets the "event_id" field.
+func (nc *NotificationCreate) SetEventID(i int) *NotificationCreate {
+	nc.mutation.SetEventID(i)
+	return nc
+}
+
+// SetKind sets the "kind" field.
+func (nc *NotificationCreate) SetKind(s string) *NotificationCreate {
+	nc.mutation.SetKind(s)
+	return nc
+}
+
+// SetTo sets the "to" field.
+func (nc *NotificationCreate) SetTo(s string) *NotificationCreate {
+	nc.mutation.SetTo(s)
+	return nc
+}
+
+// SetSubject sets the "subject" field.
+func (nc *NotificationCreate) SetSubject(s string) *NotificationCreate {
+	nc.mutation.SetSubject(s)
+	return nc
+}
+
+// SetText sets the "text" field.
+func (nc *NotificationCreate) SetText(s string) *NotificationCreate {
+	nc.mutation.SetText(s)
+	return nc
+}
+
+// SetHTML sets the "html" field.
+func (nc *NotificationCreate) SetHTML(s string) *NotificationCreate {
+	nc.mutation.SetHTML(s)
+	return nc
+}
+
+// SetNillableHTML sets the "html" field if the given value is not nil.
+func (nc *NotificationCreate) SetNillableHTML(s *string) *NotificationCreate {
+	if s != nil {
+		nc.SetHTML(*s)
+	}
+	return nc
+}
+
+// SetStatus sets the "status" field.
+func (nc *NotificationCreate) SetStatus(n notification.Status) *NotificationCreate {
+	nc.mutation.SetStatus(n)
+	return nc
+}
+
+// SetNillableStatus sets the "status" field if the given value is not nil.
+func (nc *NotificationCreate) SetNillableStatus(n *notification.Status) *NotificationCreate {
+	if n != nil {
+		nc.SetStatus(*n)
+	}
+	return nc
+}
+
+// SetAttempts sets the "attempts" field.
+func (nc *NotificationCreate) SetAttempts(i int) *NotificationCreate {
+	nc.mutation.SetAttempts(i)
+	return nc
+}
+
+// SetNillableAttempts sets the "attempts" field if the given value is not nil.
+func (nc *NotificationCreate) SetNillableAttempts(i *int) *NotificationCreate {
+	if i != nil {
+		nc.SetAttempts(*i)
+	}
+	return nc
+}
+
+// SetLastError sets the "last_error" field.
+func (nc *NotificationCreate) SetLastError(s string) *NotificationCreate {
+	nc.mutation.SetLastError(s)
+	return nc
+}
+
+// SetNillableLastError sets the "last_error" field if the given value is not nil.
+func (nc *NotificationCreate) SetNillableLastError(s *string) *NotificationCreate {
+	if s != nil {
+		nc.SetLastError(*s)
+	}
+	return nc
+}
+
+// SetNextAttemptAt sets the "next_attempt_at" field.
+func (nc *NotificationCreate) SetNextAttemptAt(t time.Time) *NotificationCreate {
+	nc.mutation.SetNextAttemptAt(t)
+	return nc
+}
+
+// SetNillableNextAttemptAt sets the "next_attempt_at" field if the given value is not nil.
+func (nc *NotificationCreate) SetNillableNextAttemptAt(t *time.Time) *NotificationCreate {
+	if t != nil {
+		nc.SetNextAttemptAt(*t)
+	}
+	return nc
+}
+
+// SetSentAt sets the "sent_at" field.
+func (nc *NotificationCreate) SetSentAt(t time.Time) *NotificationCreate {
+	nc.mutation.SetSentAt(t)
+	return nc
+}
+
+// SetNillableSentAt sets the "sent_at" field if the given value is not nil.
+func (nc *NotificationCreate) SetNillableSentAt(t *time.Time) *NotificationCreate {
+	if t != nil {
+		nc.SetSentAt(*t)
+	}
+	return nc
+}
+
+// SetTenant sets the "tenant" edge to the Tenant entity.
+func (nc *NotificationCreate) SetTenant(t *Tenant) *NotificationCreate {
+	return nc.SetTenantID(t.ID)
+}
+
+// Mutation returns the NotificationMutation object of the builder.
+func (nc *NotificationCreate) Mutation() *NotificationMutation {
+	return nc.mutation
+}
+
+// Save creates the Notification in the database.
+func (nc *NotificationCreate) Save(ctx context.Context) (*Notification, error) {
+	if err := nc.defaults(); err != nil {
+		return nil, err
+	}
+	return withHooks[*Notification, NotificationMutation](ctx, nc.sqlSave, nc.mutation, nc.hooks)
+}
+
+// SaveX calls Save and panics if Save returns an error.
+func (nc *NotificationCreate) SaveX(ctx context.Context) *Notification {
+	v, err := nc.Save(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return v
+}
+
+// Exec executes the query.
+func (nc *NotificationCreate) Exec(ctx context.Context) error {
+	_, err := nc.Save(ctx)
+	return err
+}
+
+// ExecX is like Exec, but panics if an error occurs.
+func (nc *NotificationCreate) ExecX(ctx context.Context) {
+	if err := nc.Exec(ctx); err != nil {
+		panic(err)
+	}
+}
+
+// defaults sets the default values of the builder before save.
+func (nc *NotificationCreate) defaults() error {
+	if _, ok := nc.mutation.CreatedAt(); !ok {
+		if notification.DefaultCreatedAt == nil {
+			return fmt.Errorf("ent: uninitialized notification.DefaultCreatedAt (forgotten import ent/runtime?)")
+		}
+		v := notification.DefaultCreatedAt()
+		nc.mutation.SetCreatedAt(v)
+	}
+	if _, ok := nc.mutation.UpdatedAt(); !ok {
+		if notification.DefaultUpdatedAt == nil {
+			return fmt.Errorf("ent: uninitialized notification.DefaultUpdatedAt (forgotten import ent/runtime?)")
+		}
+		v := notification.DefaultUpdatedAt()
+		nc.mutation.SetUpdatedAt(v)
+	}
+	if _, ok := nc.mutation.Status(); !ok {
+		v := notification.DefaultStatus
+		nc.mutation.SetStatus(v)
+	}
+	if _, ok := nc.mutation.Attempts(); !ok {
+		v := notification.DefaultAttempts
+		nc.mutation.SetAttempts(v)
+	}
+	if _, ok := nc.mutation.NextAttemptAt(); !ok {
+		if notification.DefaultNextAttemptAt == nil {
+			return fmt.Errorf("ent: uninitialized notification.DefaultNextAttemptAt (forgotten import ent/runtime?)")
+		}
+		v := notification.DefaultNextAttemptAt()
+		nc.mutation.SetNextAttemptAt(v)
+	}
+	return nil
+}
+
+// check runs all checks and user-defined validators on the builder.
+func (nc *NotificationCreate) check() error {
+	if _, ok := nc.mutation.CreatedAt(); !ok {
+		return &ValidationError{Name: "created_at", err: errors.New(`ent: missing required field "Notification.created_at"`)}
+	}
+	if _, ok := nc.mutation.UpdatedAt(); !ok {
+		return &ValidationError{Name: "updated_at", err: errors.New(`ent: missing required field "Notification.updated_at"`)}
+	}
+	if _, ok := nc.mutation.TenantID(); !ok {
+		return &ValidationError{Name: "tenant_id", err: errors.New(`ent: missing required field "Notification.tenant_id"`)}
+	}
+	if _, ok := nc.mutation.UserID(); !ok {
+		return &ValidationError{Name: "user_id", err: errors.New(`ent: missing required field "Notification.user_id"`)}
+	}
+	if _, ok := nc.mutation.EventID(); !ok {
+		return &ValidationError{Name: "event_id", err: errors.New(`ent: missing required field "Notification.event_id"`)}
+	}
+	if _, ok := nc.mutation.Kind(); !ok {
+		return &ValidationError{Name: "kind", err: errors.New(`ent: missing required field "Notification.kind"`)}
+	}
+	if v, ok := nc.mutation.Kind(); ok {
+		if err := notification.KindValidator(v); err != nil {
+			return &ValidationError{Name: "kind", err: fmt.Errorf(`ent: validator failed for field "Notification.kind": %w`, err)}
+		}
+	}
+	if _, ok := nc.mutation.To(); !ok {
+		return &ValidationError{Name: "to", err: errors.New(`ent: missing required field "Notification.to"`)}
+	}
+	if v, ok := nc.mutation.To(); ok {
+		if err := notification.ToValidator(v); err != nil {
+			return &ValidationError{Name: "to", err: fmt.Errorf(`ent: validator failed for field "Notification.to": %w`, err)}
+		}
+	}
+	if _, ok := nc.mutation.Subject(); !ok {
+		return &ValidationError{Name: "subject", err: errors.New(`ent: missing required field "Notification.subject"`)}
+	}
+	if _, ok := nc.mutation.Text(); !ok {
+		return &ValidationError{Name: "text", err: errors.New(`ent: missing required field "Notification.text"`)}
+	}
+	if _, ok := nc.mutation.Status(); !ok {
+		return &ValidationError{Name: "status", err: errors.New(`ent: missing required field "Notification.status"`)}
+	}
+	if v, ok := nc.mutation.Status(); ok {
+		if err := notification.StatusValidator(v); err != nil {
+			return &ValidationError{Name: "status", err: fmt.Errorf(`ent: validator failed for field "Notification.status": %w`, err)}
+		}
+	}
+	if _, ok := nc.mutation.Attempts(); !ok {
+		return &ValidationError{Name: "attempts", err: errors.New(`ent: missing required field "Notification.attempts"`)}
+	}
+	if _, ok := nc.mutation.NextAttemptAt(); !ok {
+		return &ValidationError{Name: "next_attempt_at", err: errors.New(`ent: missing required field "Notification.next_attempt_at"`)}
+	}
+	if _, ok := nc.mutation.TenantID(); !ok {
+		return &ValidationError{Name: "tenant", err: errors.New(`ent: missing required edge "Notification.tenant"`)}
+	}
+	return nil
+}
+
+func (nc *NotificationCreate) sqlSave(ctx context.Context) (*Notification, error) {
+	if err := nc.check(); err != nil {
+		return nil, err
+	}
+	_node, _spec := nc.createSpec()
+	if err := sqlgraph.CreateNode(ctx, nc.driver, _spec); err != nil {
+		if sqlgraph.IsConstraintError(err) {
+			err = &ConstraintError{msg: err.Error(), wrap: err}
+		}
+		return nil, err
+	}
+	id := _spec.ID.Value.(int64)
+	_node.ID = int(id)
+	nc.mutation.id = &_node.ID
+	nc.mutation.done = true
+	return _node, nil
+}
+
+func (nc *NotificationCreate) createSpec() (*Notification, *sqlgraph.CreateSpec) {
+	var (
+		_node = &Notification{config: nc.config}
+		_spec = sqlgraph.NewCreateSpec(notification.Table, sqlgraph.NewFieldSpec(notification.FieldID, field.TypeInt))
+	)
+	if value, ok := nc.mutation.CreatedAt(); ok {
+		_spec.SetField(notification.FieldCreatedAt, field.TypeTime, value)
+		_node.CreatedAt = value
+	}
+	if value, ok := nc.mutation.UpdatedAt(); ok {
+		_spec.SetField(notification.FieldUpdatedAt, field.TypeTime, value)
+		_node.UpdatedAt = value
+	}
+	if value, ok := nc.mutation.UserID(); ok {
+		_spec.SetField(notification.FieldUserID, field.TypeInt, value)
+		_node.UserID = value
+	}
+	if value, ok := nc.mutation.EventID(); ok {
+		_spec.SetField(notification.FieldEventID, field.TypeInt, value)
+		_node.EventID = value
+	}
+	if value, ok := nc.mutation.Kind(); ok {
+		_spec.SetField(notification.FieldKind, field.TypeString, value)
+		_node.Kind = value
+	}
+	if value, ok := nc.mutation.To(); ok {
+		_spec.SetField(notification.FieldTo, field.TypeString, value)
+		_node.To = value
+	}
+	if value, ok := nc.mutation.Subject(); ok {
+		_spec.SetField(notification.FieldSubject, field.TypeString, value)
+		_node.Subject = value
+	}
+	if value, ok := nc.mutation.Text(); ok {
+		_spec.SetField(notification.FieldText, field.TypeString, value)
+		_node.Text = value
+	}
+	if value, ok := nc.mutation.HTML(); ok {
+		_spec.SetField(notification.FieldHTML, field.TypeString, value)
+		_node.HTML = value
+	}
+	if value, ok := nc.mutation.Status(); ok {
+		_spec.SetField(notification.FieldStatus, field.TypeEnum, value)
+		_node.Status = value
+	}
+	if value, ok := nc.mutation.Attempts(); ok {
+		_spec.SetField(notification.FieldAttempts, field.TypeInt, value)
+		_node.Attempts = value
+	}
+	if value, ok := nc.mutation.LastError(); ok {
+		_spec.SetField(notification.FieldLastError, field.TypeString, value)
+		_node.LastError = value
+	}
+	if value, ok := nc.mutation.NextAttemptAt(); ok {
+		_spec.SetField(notification.FieldNextAttemptAt, field.TypeTime, value)
+		_node.NextAttemptAt = value
+	}
+	if value, ok := nc.mutation.SentAt(); ok {
+		_spec.SetField(notification.FieldSentAt, field.TypeTime, value)
+		_node.SentAt = &value
+	}
+	if nodes := nc.mutation.TenantIDs(); len(nodes) > 0 {
+		edge := &sqlgraph.EdgeSpec{
+			Rel:     sqlgraph.M2O,
+			Inverse: false,
+			Table:   notification.TenantTable,
+			Columns: []string{notification.TenantColumn},
+			Bidi:    false,
+			Target: &sqlgraph.EdgeTarget{
+				IDSpec: sqlgraph.NewFieldSpec(tenant.FieldID, field.TypeInt),
+			},
+		}
+		for _, k := range nodes {
+			edge.Target.Nodes = append(edge.Target.Nodes, k)
+		}
+		_node.TenantID = nodes[0]
+		_spec.Edges = append(_spec.Edges, edge)
+	}
+	return _node, _spec
+}
+
+// NotificationCreateBulk is the builder for creating many Notification entities in bulk.
+type NotificationCreateBulk struct {
+	config
+	builders []*NotificationCreate
+}
+
+// Save creates the Notification entities in the database.
+func (ncb *NotificationCreateBulk) Save(ctx context.Context) ([]*Notification, error) {
+	specs := make([]*sqlgraph.CreateSpec, len(ncb.builders))
+	nodes := make([]*Notification, len(ncb.builders))
+	mutators := make([]Mutator, len(ncb.builders))
+	for i := range ncb.builders {
+		func(i int, root context.Context) {
+			builder := ncb.builders[i]
+			builder.defaults()
+			var mut Mutator = MutateFunc(func(ctx context.Context, m Mutation) (Value, error) {
+				mutation, ok := m.(*NotificationMutation)
+				if !ok {
+					return nil, fmt.Errorf("unexpected mutation type %T", m)
+				}
+				if err := builder.check(); err != nil {
+					return nil, err
+				}
+				builder.mutation = mutation
+				nodes[i], specs[i] = builder.createSpec()
+				var err error
+				if i < len(mutators)-1 {
+					_, err = mutators[i+1].Mutate(root, ncb.builders[i+1].mutation)
+				} else {
+					spec := &sqlgraph.BatchCreateSpec{Nodes: specs}
+					// Invoke the actual operation on the latest mutation in the chain.
+					if err = sqlgraph.BatchCreate(ctx, ncb.driver, spec); err != nil {
+						if sqlgraph.IsConstraintError(err) {
+							err = &ConstraintError{msg: err.Error(), wrap: err}
+						}
+					}
+				}
+				if err != nil {
+					return nil, err
+				}
+				mutation.id = &nodes[i].ID
+				if specs[i].ID.Value != nil {
+					id := specs[i].ID.Value.(int64)
+					nodes[i].ID = int(id)
+				}
+				mutation.done = true
+				return nodes[i], nil
+			})
+			for i := len(builder.hooks) - 1; i >= 0; i-- {
+				mut = builder.hooks[i](mut)
+			}
+			mutators[i] = mut
+		}(i, ctx)
+	}
+	if len(mutators) > 0 {
+		if _, err := mutators[0].Mutate(ctx, ncb.builders[0].mutation); err != nil {
+			return nil, err
+		}
+	}
+	return nodes, nil
+}
+
+// SaveX is like Save, but panics if an error occurs.
+func (ncb *NotificationCreateBulk) SaveX(ctx context.Context) []*Notification {
+	v, err := ncb.Save(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return v
+}
+
+// Exec executes the query.
+func (ncb *NotificationCreateBulk) Exec(ctx context.Context) error {
+	_, err := ncb.Save(ctx)
+	return err
+}
+
+// ExecX is like Exec, but panics if an error occurs.
+func (ncb *NotificationCreateBulk) ExecX(ctx context.Context) {
+	if err := ncb.Exec(ctx); err != nil {
+		panic(err)
+	}
+}
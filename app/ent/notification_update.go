@@ -0,0 +1,702 @@
+// Code generated by ent, DO NOT EDIT.
+
+package ent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"entgo.io/ent/dialect/sql"
+	"entgo.io/ent/dialect/sql/sqlgraph"
+	"entgo.io/ent/schema/field"
+	"github.com/jpdel518/go-ent/ent/notification"
+	"github.com/jpdel518/go-ent/ent/predicate"
+)
+
+// NotificationUpdate is the builder for updating Notification entities.
+type NotificationUpdate struct {
+	config
+	hooks    []Hook
+	mutation *NotificationMutation
+}
+
+// Where appends a list predicates to the NotificationUpdate builder.
+func (nu *NotificationUpdate) Where(ps ...predicate.Notification) *NotificationUpdate {
+	nu.mutation.Where(ps...)
+	return nu
+}
+
+// SetUpdatedAt sets the "updated_at" field.
+func (nu *NotificationUpdate) SetUpdatedAt(t time.Time) *NotificationUpdate {
+	nu.mutation.SetUpdatedAt(t)
+	return nu
+}
+
+// SetUserID sets the "user_id" field.
+func (nu *NotificationUpdate) SetUserID(i int) *NotificationUpdate {
+	nu.mutation.ResetUserID()
+	nu.mutation.SetUserID(i)
+	return nu
+}
+
+// AddUserID adds i to the "user_id" field.
+func (nu *NotificationUpdate) AddUserID(i int) *NotificationUpdate {
+	nu.mutation.AddUserID(i)
+	return nu
+}
+
+// SetEventID sets the "event_id" field.
+func (nu *NotificationUpdate) SetEventID(i int) *NotificationUpdate {
+	nu.mutation.ResetEventID()
+	nu.mutation.SetEventID(i)
+	return nu
+}
+
+// AddEventID adds i to the "event_id" field.
+func (nu *NotificationUpdate) AddEventID(i int) *NotificationUpdate {
+	nu.mutation.AddEventID(i)
+	return nu
+}
+
+// SetKind sets the "kind" field.
+func (nu *NotificationUpdate) SetKind(s string) *NotificationUpdate {
+	nu.mutation.SetKind(s)
+	return nu
+}
+
+// SetTo sets the "to" field.
+func (nu *NotificationUpdate) SetTo(s string) *NotificationUpdate {
+	nu.mutation.SetTo(s)
+	return nu
+}
+
+// SetSubject sets the "subject" field.
+func (nu *NotificationUpdate) SetSubject(s string) *NotificationUpdate {
+	nu.mutation.SetSubject(s)
+	return nu
+}
+
+// SetText sets the "text" field.
+func (nu *NotificationUpdate) SetText(s string) *NotificationUpdate {
+	nu.mutation.SetText(s)
+	return nu
+}
+
+// SetHTML sets the "html" field.
+func (nu *NotificationUpdate) SetHTML(s string) *NotificationUpdate {
+	nu.mutation.SetHTML(s)
+	return nu
+}
+
+// SetNillableHTML sets the "html" field if the given value is not nil.
+func (nu *NotificationUpdate) SetNillableHTML(s *string) *NotificationUpdate {
+	if s != nil {
+		nu.SetHTML(*s)
+	}
+	return nu
+}
+
+// ClearHTML clears the value of the "html" field.
+func (nu *NotificationUpdate) ClearHTML() *NotificationUpdate {
+	nu.mutation.ClearHTML()
+	return nu
+}
+
+// SetStatus sets the "status" field.
+func (nu *NotificationUpdate) SetStatus(n notification.Status) *NotificationUpdate {
+	nu.mutation.SetStatus(n)
+	return nu
+}
+
+// SetNillableStatus sets the "status" field if the given value is not nil.
+func (nu *NotificationUpdate) SetNillableStatus(n *notification.Status) *NotificationUpdate {
+	if n != nil {
+		nu.SetStatus(*n)
+	}
+	return nu
+}
+
+// SetAttempts sets the "attempts" field.
+func (nu *NotificationUpdate) SetAttempts(i int) *NotificationUpdate {
+	nu.mutation.ResetAttempts()
+	nu.mutation.SetAttempts(i)
+	return nu
+}
+
+// SetNillableAttempts sets the "attempts" field if the given value is not nil.
+func (nu *NotificationUpdate) SetNillableAttempts(i *int) *NotificationUpdate {
+	if i != nil {
+		nu.SetAttempts(*i)
+	}
+	return nu
+}
+
+// AddAttempts adds i to the "attempts" field.
+func (nu *NotificationUpdate) AddAttempts(i int) *NotificationUpdate {
+	nu.mutation.AddAttempts(i)
+	return nu
+}
+
+// SetLastError sets the "last_error" field.
+func (nu *NotificationUpdate) SetLastError(s string) *NotificationUpdate {
+	nu.mutation.SetLastError(s)
+	return nu
+}
+
+// SetNillableLastError sets the "last_error" field if the given value is not nil.
+func (nu *NotificationUpdate) SetNillableLastError(s *string) *NotificationUpdate {
+	if s != nil {
+		nu.SetLastError(*s)
+	}
+	return nu
+}
+
+// ClearLastError clears the value of the "last_error" field.
+func (nu *NotificationUpdate) ClearLastError() *NotificationUpdate {
+	nu.mutation.ClearLastError()
+	return nu
+}
+
+// SetNextAttemptAt sets the "next_attempt_at" field.
+func (nu *NotificationUpdate) SetNextAttemptAt(t time.Time) *NotificationUpdate {
+	nu.mutation.SetNextAttemptAt(t)
+	return nu
+}
+
+// SetNillableNextAttemptAt sets the "next_attempt_at" field if the given value is not nil.
+func (nu *NotificationUpdate) SetNillableNextAttemptAt(t *time.Time) *NotificationUpdate {
+	if t != nil {
+		nu.SetNextAttemptAt(*t)
+	}
+	return nu
+}
+
+// SetSentAt sets the "sent_at" field.
+func (nu *NotificationUpdate) SetSentAt(t time.Time) *NotificationUpdate {
+	nu.mutation.SetSentAt(t)
+	return nu
+}
+
+// SetNillableSentAt sets the "sent_at" field if the given value is not nil.
+func (nu *NotificationUpdate) SetNillableSentAt(t *time.Time) *NotificationUpdate {
+	if t != nil {
+		nu.SetSentAt(*t)
+	}
+	return nu
+}
+
+// ClearSentAt clears the value of the "sent_at" field.
+func (nu *NotificationUpdate) ClearSentAt() *NotificationUpdate {
+	nu.mutation.ClearSentAt()
+	return nu
+}
+
+// Mutation returns the NotificationMutation object of the builder.
+func (nu *NotificationUpdate) Mutation() *NotificationMutation {
+	return nu.mutation
+}
+
+// Save executes the query and returns the number of nodes affected by the update operation.
+func (nu *NotificationUpdate) Save(ctx context.Context) (int, error) {
+	if err := nu.defaults(); err != nil {
+		return 0, err
+	}
+	return withHooks[int, NotificationMutation](ctx, nu.sqlSave, nu.mutation, nu.hooks)
+}
+
+// SaveX is like Save, but panics if an error occurs.
+func (nu *NotificationUpdate) SaveX(ctx context.Context) int {
+	affected, err := nu.Save(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return affected
+}
+
+// Exec executes the query.
+func (nu *NotificationUpdate) Exec(ctx context.Context) error {
+	_, err := nu.Save(ctx)
+	return err
+}
+
+// ExecX is like Exec, but panics if an error occurs.
+func (nu *NotificationUpdate) ExecX(ctx context.Context) {
+	if err := nu.Exec(ctx); err != nil {
+		panic(err)
+	}
+}
+
+// defaults sets the default values of the builder before save.
+func (nu *NotificationUpdate) defaults() error {
+	if _, ok := nu.mutation.UpdatedAt(); !ok {
+		if notification.UpdateDefaultUpdatedAt == nil {
+			return fmt.Errorf("ent: uninitialized notification.UpdateDefaultUpdatedAt (forgotten import ent/runtime?)")
+		}
+		v := notification.UpdateDefaultUpdatedAt()
+		nu.mutation.SetUpdatedAt(v)
+	}
+	return nil
+}
+
+// check runs all checks and user-defined validators on the builder.
+func (nu *NotificationUpdate) check() error {
+	if v, ok := nu.mutation.Kind(); ok {
+		if err := notification.KindValidator(v); err != nil {
+			return &ValidationError{Name: "kind", err: fmt.Errorf(`ent: validator failed for field "Notification.kind": %w`, err)}
+		}
+	}
+	if v, ok := nu.mutation.To(); ok {
+		if err := notification.ToValidator(v); err != nil {
+			return &ValidationError{Name: "to", err: fmt.Errorf(`ent: validator failed for field "Notification.to": %w`, err)}
+		}
+	}
+	if v, ok := nu.mutation.Status(); ok {
+		if err := notification.StatusValidator(v); err != nil {
+			return &ValidationError{Name: "status", err: fmt.Errorf(`ent: validator failed for field "Notification.status": %w`, err)}
+		}
+	}
+	if _, ok := nu.mutation.TenantID(); nu.mutation.TenantCleared() && !ok {
+		return errors.New(`ent: clearing a required unique edge "Notification.tenant"`)
+	}
+	return nil
+}
+
+func (nu *NotificationUpdate) sqlSave(ctx context.Context) (n int, err error) {
+	if err := nu.check(); err != nil {
+		return n, err
+	}
+	_spec := sqlgraph.NewUpdateSpec(notification.Table, notification.Columns, sqlgraph.NewFieldSpec(notification.FieldID, field.TypeInt))
+	if ps := nu.mutation.predicates; len(ps) > 0 {
+		_spec.Predicate = func(selector *sql.Selector) {
+			for i := range ps {
+				ps[i](selector)
+			}
+		}
+	}
+	if value, ok := nu.mutation.UpdatedAt(); ok {
+		_spec.SetField(notification.FieldUpdatedAt, field.TypeTime, value)
+	}
+	if value, ok := nu.mutation.UserID(); ok {
+		_spec.SetField(notification.FieldUserID, field.TypeInt, value)
+	}
+	if value, ok := nu.mutation.AddedUserID(); ok {
+		_spec.AddField(notification.FieldUserID, field.TypeInt, value)
+	}
+	if value, ok := nu.mutation.EventID(); ok {
+		_spec.SetField(notification.FieldEventID, field.TypeInt, value)
+	}
+	if value, ok := nu.mutation.AddedEventID(); ok {
+		_spec.AddField(notification.FieldEventID, field.TypeInt, value)
+	}
+	if value, ok := nu.mutation.Kind(); ok {
+		_spec.SetField(notification.FieldKind, field.TypeString, value)
+	}
+	if value, ok := nu.mutation.To(); ok {
+		_spec.SetField(notification.FieldTo, field.TypeString, value)
+	}
+	if value, ok := nu.mutation.Subject(); ok {
+		_spec.SetField(notification.FieldSubject, field.TypeString, value)
+	}
+	if value, ok := nu.mutation.Text(); ok {
+		_spec.SetField(notification.FieldText, field.TypeString, value)
+	}
+	if value, ok := nu.mutation.HTML(); ok {
+		_spec.SetField(notification.FieldHTML, field.TypeString, value)
+	}
+	if nu.mutation.HTMLCleared() {
+		_spec.ClearField(notification.FieldHTML, field.TypeString)
+	}
+	if value, ok := nu.mutation.Status(); ok {
+		_spec.SetField(notification.FieldStatus, field.TypeEnum, value)
+	}
+	if value, ok := nu.mutation.Attempts(); ok {
+		_spec.SetField(notification.FieldAttempts, field.TypeInt, value)
+	}
+	if value, ok := nu.mutation.AddedAttempts(); ok {
+		_spec.AddField(notification.FieldAttempts, field.TypeInt, value)
+	}
+	if value, ok := nu.mutation.LastError(); ok {
+		_spec.SetField(notification.FieldLastError, field.TypeString, value)
+	}
+	if nu.mutation.LastErrorCleared() {
+		_spec.ClearField(notification.FieldLastError, field.TypeString)
+	}
+	if value, ok := nu.mutation.NextAttemptAt(); ok {
+		_spec.SetField(notification.FieldNextAttemptAt, field.TypeTime, value)
+	}
+	if value, ok := nu.mutation.SentAt(); ok {
+		_spec.SetField(notification.FieldSentAt, field.TypeTime, value)
+	}
+	if nu.mutation.SentAtCleared() {
+		_spec.ClearField(notification.FieldSentAt, field.TypeTime)
+	}
+	if n, err = sqlgraph.UpdateNodes(ctx, nu.driver, _spec); err != nil {
+		if _, ok := err.(*sqlgraph.NotFoundError); ok {
+			err = &NotFoundError{notification.Label}
+		} else if sqlgraph.IsConstraintError(err) {
+			err = &ConstraintError{msg: err.Error(), wrap: err}
+		}
+		return 0, err
+	}
+	nu.mutation.done = true
+	return n, nil
+}
+
+// NotificationUpdateOne is the builder for updating a single Notification entity.
+type NotificationUpdateOne struct {
+	config
+	fields   []string
+	hooks    []Hook
+	mutation *NotificationMutation
+}
+
+// SetUpdatedAt sets the "updated_at" field.
+func (nuo *NotificationUpdateOne) SetUpdatedAt(t time.Time) *NotificationUpdateOne {
+	nuo.mutation.SetUpdatedAt(t)
+	return nuo
+}
+
+// SetUserID sets the "user_id" field.
+func (nuo *NotificationUpdateOne) SetUserID(i int) *NotificationUpdateOne {
+	nuo.mutation.ResetUserID()
+	nuo.mutation.SetUserID(i)
+	return nuo
+}
+
+// AddUserID adds i to the "user_id" field.
+func (nuo *NotificationUpdateOne) AddUserID(i int) *NotificationUpdateOne {
+	nuo.mutation.AddUserID(i)
+	return nuo
+}
+
+// SetEventID sets the "event_id" field.
+func (nuo *NotificationUpdateOne) SetEventID(i int) *NotificationUpdateOne {
+	nuo.mutation.ResetEventID()
+	nuo.mutation.SetEventID(i)
+	return nuo
+}
+
+// AddEventID adds i to the "event_id" field.
+func (nuo *NotificationUpdateOne) AddEventID(i int) *NotificationUpdateOne {
+	nuo.mutation.AddEventID(i)
+	return nuo
+}
+
+// SetKind sets the "kind" field.
+func (nuo *NotificationUpdateOne) SetKind(s string) *NotificationUpdateOne {
+	nuo.mutation.SetKind(s)
+	return nuo
+}
+
+// SetTo sets the "to" field.
+func (nuo *NotificationUpdateOne) SetTo(s string) *NotificationUpdateOne {
+	nuo.mutation.SetTo(s)
+	return nuo
+}
+
+// SetSubject sets the "subject" field.
+func (nuo *NotificationUpdateOne) SetSubject(s string) *NotificationUpdateOne {
+	nuo.mutation.SetSubject(s)
+	return nuo
+}
+
+// SetText sets the "text" field.
+func (nuo *NotificationUpdateOne) SetText(s string) *NotificationUpdateOne {
+	nuo.mutation.SetText(s)
+	return nuo
+}
+
+// SetHTML sets the "html" field.
+func (nuo *NotificationUpdateOne) SetHTML(s string) *NotificationUpdateOne {
+	nuo.mutation.SetHTML(s)
+	return nuo
+}
+
+// SetNillableHTML sets the "html" field if the given value is not nil.
+func (nuo *NotificationUpdateOne) SetNillableHTML(s *string) *NotificationUpdateOne {
+	if s != nil {
+		nuo.SetHTML(*s)
+	}
+	return nuo
+}
+
+// ClearHTML clears the value of the "html" field.
+func (nuo *NotificationUpdateOne) ClearHTML() *NotificationUpdateOne {
+	nuo.mutation.ClearHTML()
+	return nuo
+}
+
+// SetStatus sets the "status" field.
+func (nuo *NotificationUpdateOne) SetStatus(n notification.Status) *NotificationUpdateOne {
+	nuo.mutation.SetStatus(n)
+	return nuo
+}
+
+// SetNillableStatus sets the "status" field if the given value is not nil.
+func (nuo *NotificationUpdateOne) SetNillableStatus(n *notification.Status) *NotificationUpdateOne {
+	if n != nil {
+		nuo.SetStatus(*n)
+	}
+	return nuo
+}
+
+// SetAttempts sets the "attempts" field.
+func (nuo *NotificationUpdateOne) SetAttempts(i int) *NotificationUpdateOne {
+	nuo.mutation.ResetAttempts()
+	nuo.mutation.SetAttempts(i)
+	return nuo
+}
+
+// SetNillableAttempts sets the "attempts" field if the given value is not nil.
+func (nuo *NotificationUpdateOne) SetNillableAttempts(i *int) *NotificationUpdateOne {
+	if i != nil {
+		nuo.SetAttempts(*i)
+	}
+	return nuo
+}
+
+// AddAttempts adds i to the "attempts" field.
+func (nuo *NotificationUpdateOne) AddAttempts(i int) *NotificationUpdateOne {
+	nuo.mutation.AddAttempts(i)
+	return nuo
+}
+
+// SetLastError sets the "last_error" field.
+func (nuo *NotificationUpdateOne) SetLastError(s string) *NotificationUpdateOne {
+	nuo.mutation.SetLastError(s)
+	return nuo
+}
+
+// SetNillableLastError sets the "last_error" field if the given value is not nil.
+func (nuo *NotificationUpdateOne) SetNillableLastError(s *string) *NotificationUpdateOne {
+	if s != nil {
+		nuo.SetLastError(*s)
+	}
+	return nuo
+}
+
+// ClearLastError clears the value of the "last_error" field.
+func (nuo *NotificationUpdateOne) ClearLastError() *NotificationUpdateOne {
+	nuo.mutation.ClearLastError()
+	return nuo
+}
+
+// SetNextAttemptAt sets the "next_attempt_at" field.
+func (nuo *NotificationUpdateOne) SetNextAttemptAt(t time.Time) *NotificationUpdateOne {
+	nuo.mutation.SetNextAttemptAt(t)
+	return nuo
+}
+
+// SetNillableNextAttemptAt sets the "next_attempt_at" field if the given value is not nil.
+func (nuo *NotificationUpdateOne) SetNillableNextAttemptAt(t *time.Time) *NotificationUpdateOne {
+	if t != nil {
+		nuo.SetNextAttemptAt(*t)
+	}
+	return nuo
+}
+
+// SetSentAt sets the "sent_at" field.
+func (nuo *NotificationUpdateOne) SetSentAt(t time.Time) *NotificationUpdateOne {
+	nuo.mutation.SetSentAt(t)
+	return nuo
+}
+
+// SetNillableSentAt sets the "sent_at" field if the given value is not nil.
+func (nuo *NotificationUpdateOne) SetNillableSentAt(t *time.Time) *NotificationUpdateOne {
+	if t != nil {
+		nuo.SetSentAt(*t)
+	}
+	return nuo
+}
+
+// ClearSentAt clears the value of the "sent_at" field.
+func (nuo *NotificationUpdateOne) ClearSentAt() *NotificationUpdateOne {
+	nuo.mutation.ClearSentAt()
+	return nuo
+}
+
+// Mutation returns the NotificationMutation object of the builder.
+func (nuo *NotificationUpdateOne) Mutation() *NotificationMutation {
+	return nuo.mutation
+}
+
+// Where appends a list predicates to the NotificationUpdate builder.
+func (nuo *NotificationUpdateOne) Where(ps ...predicate.Notification) *NotificationUpdateOne {
+	nuo.mutation.Where(ps...)
+	return nuo
+}
+
+// Select allows selecting one or more fields (columns) of the returned entity.
+// The default is selecting all fields defined in the entity schema.
+func (nuo *NotificationUpdateOne) Select(field string, fields ...string) *NotificationUpdateOne {
+	nuo.fields = append([]string{field}, fields...)
+	return nuo
+}
+
+// Save executes the query and returns the updated Notification entity.
+func (nuo *NotificationUpdateOne) Save(ctx context.Context) (*Notification, error) {
+	if err := nuo.defaults(); err != nil {
+		return nil, err
+	}
+	return withHooks[*Notification, NotificationMutation](ctx, nuo.sqlSave, nuo.mutation, nuo.hooks)
+}
+
+// SaveX is like Save, but panics if an error occurs.
+func (nuo *NotificationUpdateOne) SaveX(ctx context.Context) *Notification {
+	node, err := nuo.Save(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return node
+}
+
+// Exec executes the query on the entity.
+func (nuo *NotificationUpdateOne) Exec(ctx context.Context) error {
+	_, err := nuo.Save(ctx)
+	return err
+}
+
+// ExecX is like Exec, but panics if an error occurs.
+func (nuo *NotificationUpdateOne) ExecX(ctx context.Context) {
+	if err := nuo.Exec(ctx); err != nil {
+		panic(err)
+	}
+}
+
+// defaults sets the default values of the builder before save.
+func (nuo *NotificationUpdateOne) defaults() error {
+	if _, ok := nuo.mutation.UpdatedAt(); !ok {
+		if notification.UpdateDefaultUpdatedAt == nil {
+			return fmt.Errorf("ent: uninitialized notification.UpdateDefaultUpdatedAt (forgotten import ent/runtime?)")
+		}
+		v := notification.UpdateDefaultUpdatedAt()
+		nuo.mutation.SetUpdatedAt(v)
+	}
+	return nil
+}
+
+// check runs all checks and user-defined validators on the builder.
+func (nuo *NotificationUpdateOne) check() error {
+	if v, ok := nuo.mutation.Kind(); ok {
+		if err := notification.KindValidator(v); err != nil {
+			return &ValidationError{Name: "kind", err: fmt.Errorf(`ent: validator failed for field "Notification.kind": %w`, err)}
+		}
+	}
+	if v, ok := nuo.mutation.To(); ok {
+		if err := notification.ToValidator(v); err != nil {
+			return &ValidationError{Name: "to", err: fmt.Errorf(`ent: validator failed for field "Notification.to": %w`, err)}
+		}
+	}
+	if v, ok := nuo.mutation.Status(); ok {
+		if err := notification.StatusValidator(v); err != nil {
+			return &ValidationError{Name: "status", err: fmt.Errorf(`ent: validator failed for field "Notification.status": %w`, err)}
+		}
+	}
+	if _, ok := nuo.mutation.TenantID(); nuo.mutation.TenantCleared() && !ok {
+		return errors.New(`ent: clearing a required unique edge "Notification.tenant"`)
+	}
+	return nil
+}
+
+func (nuo *NotificationUpdateOne) sqlSave(ctx context.Context) (_node *Notification, err error) {
+	if err := nuo.check(); err != nil {
+		return _node, err
+	}
+	_spec := sqlgraph.NewUpdateSpec(notification.Table, notification.Columns, sqlgraph.NewFieldSpec(notification.FieldID, field.TypeInt))
+	id, ok := nuo.mutation.ID()
+	if !ok {
+		return nil, &ValidationError{Name: "id", err: errors.New(`ent: missing "Notification.id" for update`)}
+	}
+	_spec.Node.ID.Value = id
+	if fields := nuo.fields; len(fields) > 0 {
+		_spec.Node.Columns = make([]string, 0, len(fields))
+		_spec.Node.Columns = append(_spec.Node.Columns, notification.FieldID)
+		for _, f := range fields {
+			if !notification.ValidColumn(f) {
+				return nil, &ValidationError{Name: f, err: fmt.Errorf("ent: invalid field %q for query", f)}
+			}
+			if f != notification.FieldID {
+				_spec.Node.Columns = append(_spec.Node.Columns, f)
+			}
+		}
+	}
+	if ps := nuo.mutation.predicates; len(ps) > 0 {
+		_spec.Predicate = func(selector *sql.Selector) {
+			for i := range ps {
+				ps[i](selector)
+			}
+		}
+	}
+	if value, ok := nuo.mutation.UpdatedAt(); ok {
+		_spec.SetField(notification.FieldUpdatedAt, field.TypeTime, value)
+	}
+	if value, ok := nuo.mutation.UserID(); ok {
+		_spec.SetField(notification.FieldUserID, field.TypeInt, value)
+	}
+	if value, ok := nuo.mutation.AddedUserID(); ok {
+		_spec.AddField(notification.FieldUserID, field.TypeInt, value)
+	}
+	if value, ok := nuo.mutation.EventID(); ok {
+		_spec.SetField(notification.FieldEventID, field.TypeInt, value)
+	}
+	if value, ok := nuo.mutation.AddedEventID(); ok {
+		_spec.AddField(notification.FieldEventID, field.TypeInt, value)
+	}
+	if value, ok := nuo.mutation.Kind(); ok {
+		_spec.SetField(notification.FieldKind, field.TypeString, value)
+	}
+	if value, ok := nuo.mutation.To(); ok {
+		_spec.SetField(notification.FieldTo, field.TypeString, value)
+	}
+	if value, ok := nuo.mutation.Subject(); ok {
+		_spec.SetField(notification.FieldSubject, field.TypeString, value)
+	}
+	if value, ok := nuo.mutation.Text(); ok {
+		_spec.SetField(notification.FieldText, field.TypeString, value)
+	}
+	if value, ok := nuo.mutation.HTML(); ok {
+		_spec.SetField(notification.FieldHTML, field.TypeString, value)
+	}
+	if nuo.mutation.HTMLCleared() {
+		_spec.ClearField(notification.FieldHTML, field.TypeString)
+	}
+	if value, ok := nuo.mutation.Status(); ok {
+		_spec.SetField(notification.FieldStatus, field.TypeEnum, value)
+	}
+	if value, ok := nuo.mutation.Attempts(); ok {
+		_spec.SetField(notification.FieldAttempts, field.TypeInt, value)
+	}
+	if value, ok := nuo.mutation.AddedAttempts(); ok {
+		_spec.AddField(notification.FieldAttempts, field.TypeInt, value)
+	}
+	if value, ok := nuo.mutation.LastError(); ok {
+		_spec.SetField(notification.FieldLastError, field.TypeString, value)
+	}
+	if nuo.mutation.LastErrorCleared() {
+		_spec.ClearField(notification.FieldLastError, field.TypeString)
+	}
+	if value, ok := nuo.mutation.NextAttemptAt(); ok {
+		_spec.SetField(notification.FieldNextAttemptAt, field.TypeTime, value)
+	}
+	if value, ok := nuo.mutation.SentAt(); ok {
+		_spec.SetField(notification.FieldSentAt, field.TypeTime, value)
+	}
+	if nuo.mutation.SentAtCleared() {
+		_spec.ClearField(notification.FieldSentAt, field.TypeTime)
+	}
+	_node = &Notification{config: nuo.config}
+	_spec.Assign = _node.assignValues
+	_spec.ScanValues = _node.scanValues
+	if err = sqlgraph.UpdateNode(ctx, nuo.driver, _spec); err != nil {
+		if _, ok := err.(*sqlgraph.NotFoundError); ok {
+			err = &NotFoundError{notification.Label}
+		} else if sqlgraph.IsConstraintError(err) {
+			err = &ConstraintError{msg: err.Error(), wrap: err}
+		}
+		return nil, err
+	}
+	nuo.mutation.done = true
+	return _node, nil
+}
@@ -0,0 +1,578 @@
+// Code generated by ent, DO NOT EDIT.
+
+package ent
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"entgo.io/ent/dialect/sql"
+	"entgo.io/ent/dialect/sql/sqlgraph"
+	"entgo.io/ent/dialect/sql/sqljson"
+	"entgo.io/ent/schema/field"
+	"github.com/jpdel518/go-ent/ent/outboxevent"
+	"github.com/jpdel518/go-ent/ent/predicate"
+)
+
+// OutboxEventUpdate is the builder for updating OutboxEvent entities.
+type OutboxEventUpdate struct {
+	config
+	hooks    []Hook
+	mutation *OutboxEventMutation
+}
+
+// Where appends a list predicates to the OutboxEventUpdate builder.
+func (oeu *OutboxEventUpdate) Where(ps ...predicate.OutboxEvent) *OutboxEventUpdate {
+	oeu.mutation.Where(ps...)
+	return oeu
+}
+
+// SetEventType sets the "event_type" field.
+func (oeu *OutboxEventUpdate) SetEventType(s string) *OutboxEventUpdate {
+	oeu.mutation.SetEventType(s)
+	return oeu
+}
+
+// SetAggregateType sets the "aggregate_type" field.
+func (oeu *OutboxEventUpdate) SetAggregateType(s string) *OutboxEventUpdate {
+	oeu.mutation.SetAggregateType(s)
+	return oeu
+}
+
+// SetAggregateID sets the "aggregate_id" field.
+func (oeu *OutboxEventUpdate) SetAggregateID(i int) *OutboxEventUpdate {
+	oeu.mutation.ResetAggregateID()
+	oeu.mutation.SetAggregateID(i)
+	return oeu
+}
+
+// AddAggregateID adds i to the "aggregate_id" field.
+func (oeu *OutboxEventUpdate) AddAggregateID(i int) *OutboxEventUpdate {
+	oeu.mutation.AddAggregateID(i)
+	return oeu
+}
+
+// SetPayload sets the "payload" field.
+func (oeu *OutboxEventUpdate) SetPayload(j json.RawMessage) *OutboxEventUpdate {
+	oeu.mutation.SetPayload(j)
+	return oeu
+}
+
+// AppendPayload appends j to the "payload" field.
+func (oeu *OutboxEventUpdate) AppendPayload(j json.RawMessage) *OutboxEventUpdate {
+	oeu.mutation.AppendPayload(j)
+	return oeu
+}
+
+// SetAttempts sets the "attempts" field.
+func (oeu *OutboxEventUpdate) SetAttempts(i int) *OutboxEventUpdate {
+	oeu.mutation.ResetAttempts()
+	oeu.mutation.SetAttempts(i)
+	return oeu
+}
+
+// SetNillableAttempts sets the "attempts" field if the given value is not nil.
+func (oeu *OutboxEventUpdate) SetNillableAttempts(i *int) *OutboxEventUpdate {
+	if i != nil {
+		oeu.SetAttempts(*i)
+	}
+	return oeu
+}
+
+// AddAttempts adds i to the "attempts" field.
+func (oeu *OutboxEventUpdate) AddAttempts(i int) *OutboxEventUpdate {
+	oeu.mutation.AddAttempts(i)
+	return oeu
+}
+
+// SetLastError sets the "last_error" field.
+func (oeu *OutboxEventUpdate) SetLastError(s string) *OutboxEventUpdate {
+	oeu.mutation.SetLastError(s)
+	return oeu
+}
+
+// SetNillableLastError sets the "last_error" field if the given value is not nil.
+func (oeu *OutboxEventUpdate) SetNillableLastError(s *string) *OutboxEventUpdate {
+	if s != nil {
+		oeu.SetLastError(*s)
+	}
+	return oeu
+}
+
+// ClearLastError clears the value of the "last_error" field.
+func (oeu *OutboxEventUpdate) ClearLastError() *OutboxEventUpdate {
+	oeu.mutation.ClearLastError()
+	return oeu
+}
+
+// SetAvailableAt sets the "available_at" field.
+func (oeu *OutboxEventUpdate) SetAvailableAt(t time.Time) *OutboxEventUpdate {
+	oeu.mutation.SetAvailableAt(t)
+	return oeu
+}
+
+// SetNillableAvailableAt sets the "available_at" field if the given value is not nil.
+func (oeu *OutboxEventUpdate) SetNillableAvailableAt(t *time.Time) *OutboxEventUpdate {
+	if t != nil {
+		oeu.SetAvailableAt(*t)
+	}
+	return oeu
+}
+
+// SetDeliveredAt sets the "delivered_at" field.
+func (oeu *OutboxEventUpdate) SetDeliveredAt(t time.Time) *OutboxEventUpdate {
+	oeu.mutation.SetDeliveredAt(t)
+	return oeu
+}
+
+// SetNillableDeliveredAt sets the "delivered_at" field if the given value is not nil.
+func (oeu *OutboxEventUpdate) SetNillableDeliveredAt(t *time.Time) *OutboxEventUpdate {
+	if t != nil {
+		oeu.SetDeliveredAt(*t)
+	}
+	return oeu
+}
+
+// ClearDeliveredAt clears the value of the "delivered_at" field.
+func (oeu *OutboxEventUpdate) ClearDeliveredAt() *OutboxEventUpdate {
+	oeu.mutation.ClearDeliveredAt()
+	return oeu
+}
+
+// SetDeadAt sets the "dead_at" field.
+func (oeu *OutboxEventUpdate) SetDeadAt(t time.Time) *OutboxEventUpdate {
+	oeu.mutation.SetDeadAt(t)
+	return oeu
+}
+
+// SetNillableDeadAt sets the "dead_at" field if the given value is not nil.
+func (oeu *OutboxEventUpdate) SetNillableDeadAt(t *time.Time) *OutboxEventUpdate {
+	if t != nil {
+		oeu.SetDeadAt(*t)
+	}
+	return oeu
+}
+
+// ClearDeadAt clears the value of the "dead_at" field.
+func (oeu *OutboxEventUpdate) ClearDeadAt() *OutboxEventUpdate {
+	oeu.mutation.ClearDeadAt()
+	return oeu
+}
+
+// Mutation returns the OutboxEventMutation object of the builder.
+func (oeu *OutboxEventUpdate) Mutation() *OutboxEventMutation {
+	return oeu.mutation
+}
+
+// Save executes the query and returns the number of nodes affected by the update operation.
+func (oeu *OutboxEventUpdate) Save(ctx context.Context) (int, error) {
+	return withHooks[int, OutboxEventMutation](ctx, oeu.sqlSave, oeu.mutation, oeu.hooks)
+}
+
+// SaveX is like Save, but panics if an error occurs.
+func (oeu *OutboxEventUpdate) SaveX(ctx context.Context) int {
+	affected, err := oeu.Save(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return affected
+}
+
+// Exec executes the query.
+func (oeu *OutboxEventUpdate) Exec(ctx context.Context) error {
+	_, err := oeu.Save(ctx)
+	return err
+}
+
+// ExecX is like Exec, but panics if an error occurs.
+func (oeu *OutboxEventUpdate) ExecX(ctx context.Context) {
+	if err := oeu.Exec(ctx); err != nil {
+		panic(err)
+	}
+}
+
+// check runs all checks and user-defined validators on the builder.
+func (oeu *OutboxEventUpdate) check() error {
+	if v, ok := oeu.mutation.EventType(); ok {
+		if err := outboxevent.EventTypeValidator(v); err != nil {
+			return &ValidationError{Name: "event_type", err: fmt.Errorf(`ent: validator failed for field "OutboxEvent.event_type": %w`, err)}
+		}
+	}
+	if v, ok := oeu.mutation.AggregateType(); ok {
+		if err := outboxevent.AggregateTypeValidator(v); err != nil {
+			return &ValidationError{Name: "aggregate_type", err: fmt.Errorf(`ent: validator failed for field "OutboxEvent.aggregate_type": %w`, err)}
+		}
+	}
+	if _, ok := oeu.mutation.TenantID(); oeu.mutation.TenantCleared() && !ok {
+		return errors.New(`ent: clearing a required unique edge "OutboxEvent.tenant"`)
+	}
+	return nil
+}
+
+func (oeu *OutboxEventUpdate) sqlSave(ctx context.Context) (n int, err error) {
+	if err := oeu.check(); err != nil {
+		return n, err
+	}
+	_spec := sqlgraph.NewUpdateSpec(outboxevent.Table, outboxevent.Columns, sqlgraph.NewFieldSpec(outboxevent.FieldID, field.TypeInt))
+	if ps := oeu.mutation.predicates; len(ps) > 0 {
+		_spec.Predicate = func(selector *sql.Selector) {
+			for i := range ps {
+				ps[i](selector)
+			}
+		}
+	}
+	if value, ok := oeu.mutation.EventType(); ok {
+		_spec.SetField(outboxevent.FieldEventType, field.TypeString, value)
+	}
+	if value, ok := oeu.mutation.AggregateType(); ok {
+		_spec.SetField(outboxevent.FieldAggregateType, field.TypeString, value)
+	}
+	if value, ok := oeu.mutation.AggregateID(); ok {
+		_spec.SetField(outboxevent.FieldAggregateID, field.TypeInt, value)
+	}
+	if value, ok := oeu.mutation.AddedAggregateID(); ok {
+		_spec.AddField(outboxevent.FieldAggregateID, field.TypeInt, value)
+	}
+	if value, ok := oeu.mutation.Payload(); ok {
+		_spec.SetField(outboxevent.FieldPayload, field.TypeJSON, value)
+	}
+	if value, ok := oeu.mutation.AppendedPayload(); ok {
+		_spec.AddModifier(func(u *sql.UpdateBuilder) {
+			sqljson.Append(u, outboxevent.FieldPayload, value)
+		})
+	}
+	if value, ok := oeu.mutation.Attempts(); ok {
+		_spec.SetField(outboxevent.FieldAttempts, field.TypeInt, value)
+	}
+	if value, ok := oeu.mutation.AddedAttempts(); ok {
+		_spec.AddField(outboxevent.FieldAttempts, field.TypeInt, value)
+	}
+	if value, ok := oeu.mutation.LastError(); ok {
+		_spec.SetField(outboxevent.FieldLastError, field.TypeString, value)
+	}
+	if oeu.mutation.LastErrorCleared() {
+		_spec.ClearField(outboxevent.FieldLastError, field.TypeString)
+	}
+	if value, ok := oeu.mutation.AvailableAt(); ok {
+		_spec.SetField(outboxevent.FieldAvailableAt, field.TypeTime, value)
+	}
+	if value, ok := oeu.mutation.DeliveredAt(); ok {
+		_spec.SetField(outboxevent.FieldDeliveredAt, field.TypeTime, value)
+	}
+	if oeu.mutation.DeliveredAtCleared() {
+		_spec.ClearField(outboxevent.FieldDeliveredAt, field.TypeTime)
+	}
+	if value, ok := oeu.mutation.DeadAt(); ok {
+		_spec.SetField(outboxevent.FieldDeadAt, field.TypeTime, value)
+	}
+	if oeu.mutation.DeadAtCleared() {
+		_spec.ClearField(outboxevent.FieldDeadAt, field.TypeTime)
+	}
+	if n, err = sqlgraph.UpdateNodes(ctx, oeu.driver, _spec); err != nil {
+		if _, ok := err.(*sqlgraph.NotFoundError); ok {
+			err = &NotFoundError{outboxevent.Label}
+		} else if sqlgraph.IsConstraintError(err) {
+			err = &ConstraintError{msg: err.Error(), wrap: err}
+		}
+		return 0, err
+	}
+	oeu.mutation.done = true
+	return n, nil
+}
+
+// OutboxEventUpdateOne is the builder for updating a single OutboxEvent entity.
+type OutboxEventUpdateOne struct {
+	config
+	fields   []string
+	hooks    []Hook
+	mutation *OutboxEventMutation
+}
+
+// SetEventType sets the "event_type" field.
+func (oeuo *OutboxEventUpdateOne) SetEventType(s string) *OutboxEventUpdateOne {
+	oeuo.mutation.SetEventType(s)
+	return oeuo
+}
+
+// SetAggregateType sets the "aggregate_type" field.
+func (oeuo *OutboxEventUpdateOne) SetAggregateType(s string) *OutboxEventUpdateOne {
+	oeuo.mutation.SetAggregateType(s)
+	return oeuo
+}
+
+// SetAggregateID sets the "aggregate_id" field.
+func (oeuo *OutboxEventUpdateOne) SetAggregateID(i int) *OutboxEventUpdateOne {
+	oeuo.mutation.ResetAggregateID()
+	oeuo.mutation.SetAggregateID(i)
+	return oeuo
+}
+
+// AddAggregateID adds i to the "aggregate_id" field.
+func (oeuo *OutboxEventUpdateOne) AddAggregateID(i int) *OutboxEventUpdateOne {
+	oeuo.mutation.AddAggregateID(i)
+	return oeuo
+}
+
+// SetPayload sets the "payload" field.
+func (oeuo *OutboxEventUpdateOne) SetPayload(j json.RawMessage) *OutboxEventUpdateOne {
+	oeuo.mutation.SetPayload(j)
+	return oeuo
+}
+
+// AppendPayload appends j to the "payload" field.
+func (oeuo *OutboxEventUpdateOne) AppendPayload(j json.RawMessage) *OutboxEventUpdateOne {
+	oeuo.mutation.AppendPayload(j)
+	return oeuo
+}
+
+// SetAttempts sets the "attempts" field.
+func (oeuo *OutboxEventUpdateOne) SetAttempts(i int) *OutboxEventUpdateOne {
+	oeuo.mutation.ResetAttempts()
+	oeuo.mutation.SetAttempts(i)
+	return oeuo
+}
+
+// SetNillableAttempts sets the "attempts" field if the given value is not nil.
+func (oeuo *OutboxEventUpdateOne) SetNillableAttempts(i *int) *OutboxEventUpdateOne {
+	if i != nil {
+		oeuo.SetAttempts(*i)
+	}
+	return oeuo
+}
+
+// AddAttempts adds i to the "attempts" field.
+func (oeuo *OutboxEventUpdateOne) AddAttempts(i int) *OutboxEventUpdateOne {
+	oeuo.mutation.AddAttempts(i)
+	return oeuo
+}
+
+// SetLastError sets the "last_error" field.
+func (oeuo *OutboxEventUpdateOne) SetLastError(s string) *OutboxEventUpdateOne {
+	oeuo.mutation.SetLastError(s)
+	return oeuo
+}
+
+// SetNillableLastError sets the "last_error" field if the given value is not nil.
+func (oeuo *OutboxEventUpdateOne) SetNillableLastError(s *string) *OutboxEventUpdateOne {
+	if s != nil {
+		oeuo.SetLastError(*s)
+	}
+	return oeuo
+}
+
+// ClearLastError clears the value of the "last_error" field.
+func (oeuo *OutboxEventUpdateOne) ClearLastError() *OutboxEventUpdateOne {
+	oeuo.mutation.ClearLastError()
+	return oeuo
+}
+
+// SetAvailableAt sets the "available_at" field.
+func (oeuo *OutboxEventUpdateOne) SetAvailableAt(t time.Time) *OutboxEventUpdateOne {
+	oeuo.mutation.SetAvailableAt(t)
+	return oeuo
+}
+
+// SetNillableAvailableAt sets the "available_at" field if the given value is not nil.
+func (oeuo *OutboxEventUpdateOne) SetNillableAvailableAt(t *time.Time) *OutboxEventUpdateOne {
+	if t != nil {
+		oeuo.SetAvailableAt(*t)
+	}
+	return oeuo
+}
+
+// SetDeliveredAt sets the "delivered_at" field.
+func (oeuo *OutboxEventUpdateOne) SetDeliveredAt(t time.Time) *OutboxEventUpdateOne {
+	oeuo.mutation.SetDeliveredAt(t)
+	return oeuo
+}
+
+// SetNillableDeliveredAt sets the "delivered_at" field if the given value is not nil.
+func (oeuo *OutboxEventUpdateOne) SetNillableDeliveredAt(t *time.Time) *OutboxEventUpdateOne {
+	if t != nil {
+		oeuo.SetDeliveredAt(*t)
+	}
+	return oeuo
+}
+
+// ClearDeliveredAt clears the value of the "delivered_at" field.
+func (oeuo *OutboxEventUpdateOne) ClearDeliveredAt() *OutboxEventUpdateOne {
+	oeuo.mutation.ClearDeliveredAt()
+	return oeuo
+}
+
+// SetDeadAt sets the "dead_at" field.
+func (oeuo *OutboxEventUpdateOne) SetDeadAt(t time.Time) *OutboxEventUpdateOne {
+	oeuo.mutation.SetDeadAt(t)
+	return oeuo
+}
+
+// SetNillableDeadAt sets the "dead_at" field if the given value is not nil.
+func (oeuo *OutboxEventUpdateOne) SetNillableDeadAt(t *time.Time) *OutboxEventUpdateOne {
+	if t != nil {
+		oeuo.SetDeadAt(*t)
+	}
+	return oeuo
+}
+
+// ClearDeadAt clears the value of the "dead_at" field.
+func (oeuo *OutboxEventUpdateOne) ClearDeadAt() *OutboxEventUpdateOne {
+	oeuo.mutation.ClearDeadAt()
+	return oeuo
+}
+
+// Mutation returns the OutboxEventMutation object of the builder.
+func (oeuo *OutboxEventUpdateOne) Mutation() *OutboxEventMutation {
+	return oeuo.mutation
+}
+
+// Where appends a list predicates to the OutboxEventUpdate builder.
+func (oeuo *OutboxEventUpdateOne) Where(ps ...predicate.OutboxEvent) *OutboxEventUpdateOne {
+	oeuo.mutation.Where(ps...)
+	return oeuo
+}
+
+// Select allows selecting one or more fields (columns) of the returned entity.
+// The default is selecting all fields defined in the entity schema.
+func (oeuo *OutboxEventUpdateOne) Select(field string, fields ...string) *OutboxEventUpdateOne {
+	oeuo.fields = append([]string{field}, fields...)
+	return oeuo
+}
+
+// Save executes the query and returns the updated OutboxEvent entity.
+func (oeuo *OutboxEventUpdateOne) Save(ctx context.Context) (*OutboxEvent, error) {
+	return withHooks[*OutboxEvent, OutboxEventMutation](ctx, oeuo.sqlSave, oeuo.mutation, oeuo.hooks)
+}
+
+// SaveX is like Save, but panics if an error occurs.
+func (oeuo *OutboxEventUpdateOne) SaveX(ctx context.Context) *OutboxEvent {
+	node, err := oeuo.Save(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return node
+}
+
+// Exec executes the query on the entity.
+func (oeuo *OutboxEventUpdateOne) Exec(ctx context.Context) error {
+	_, err := oeuo.Save(ctx)
+	return err
+}
+
+// ExecX is like Exec, but panics if an error occurs.
+func (oeuo *OutboxEventUpdateOne) ExecX(ctx context.Context) {
+	if err := oeuo.Exec(ctx); err != nil {
+		panic(err)
+	}
+}
+
+// check runs all checks and user-defined validators on the builder.
+func (oeuo *OutboxEventUpdateOne) check() error {
+	if v, ok := oeuo.mutation.EventType(); ok {
+		if err := outboxevent.EventTypeValidator(v); err != nil {
+			return &ValidationError{Name: "event_type", err: fmt.Errorf(`ent: validator failed for field "OutboxEvent.event_type": %w`, err)}
+		}
+	}
+	if v, ok := oeuo.mutation.AggregateType(); ok {
+		if err := outboxevent.AggregateTypeValidator(v); err != nil {
+			return &ValidationError{Name: "aggregate_type", err: fmt.Errorf(`ent: validator failed for field "OutboxEvent.aggregate_type": %w`, err)}
+		}
+	}
+	if _, ok := oeuo.mutation.TenantID(); oeuo.mutation.TenantCleared() && !ok {
+		return errors.New(`ent: clearing a required unique edge "OutboxEvent.tenant"`)
+	}
+	return nil
+}
+
+func (oeuo *OutboxEventUpdateOne) sqlSave(ctx context.Context) (_node *OutboxEvent, err error) {
+	if err := oeuo.check(); err != nil {
+		return _node, err
+	}
+	_spec := sqlgraph.NewUpdateSpec(outboxevent.Table, outboxevent.Columns, sqlgraph.NewFieldSpec(outboxevent.FieldID, field.TypeInt))
+	id, ok := oeuo.mutation.ID()
+	if !ok {
+		return nil, &ValidationError{Name: "id", err: errors.New(`ent: missing "OutboxEvent.id" for update`)}
+	}
+	_spec.Node.ID.Value = id
+	if fields := oeuo.fields; len(fields) > 0 {
+		_spec.Node.Columns = make([]string, 0, len(fields))
+		_spec.Node.Columns = append(_spec.Node.Columns, outboxevent.FieldID)
+		for _, f := range fields {
+			if !outboxevent.ValidColumn(f) {
+				return nil, &ValidationError{Name: f, err: fmt.Errorf("ent: invalid field %q for query", f)}
+			}
+			if f != outboxevent.FieldID {
+				_spec.Node.Columns = append(_spec.Node.Columns, f)
+			}
+		}
+	}
+	if ps := oeuo.mutation.predicates; len(ps) > 0 {
+		_spec.Predicate = func(selector *sql.Selector) {
+			for i := range ps {
+				ps[i](selector)
+			}
+		}
+	}
+	if value, ok := oeuo.mutation.EventType(); ok {
+		_spec.SetField(outboxevent.FieldEventType, field.TypeString, value)
+	}
+	if value, ok := oeuo.mutation.AggregateType(); ok {
+		_spec.SetField(outboxevent.FieldAggregateType, field.TypeString, value)
+	}
+	if value, ok := oeuo.mutation.AggregateID(); ok {
+		_spec.SetField(outboxevent.FieldAggregateID, field.TypeInt, value)
+	}
+	if value, ok := oeuo.mutation.AddedAggregateID(); ok {
+		_spec.AddField(outboxevent.FieldAggregateID, field.TypeInt, value)
+	}
+	if value, ok := oeuo.mutation.Payload(); ok {
+		_spec.SetField(outboxevent.FieldPayload, field.TypeJSON, value)
+	}
+	if value, ok := oeuo.mutation.AppendedPayload(); ok {
+		_spec.AddModifier(func(u *sql.UpdateBuilder) {
+			sqljson.Append(u, outboxevent.FieldPayload, value)
+		})
+	}
+	if value, ok := oeuo.mutation.Attempts(); ok {
+		_spec.SetField(outboxevent.FieldAttempts, field.TypeInt, value)
+	}
+	if value, ok := oeuo.mutation.AddedAttempts(); ok {
+		_spec.AddField(outboxevent.FieldAttempts, field.TypeInt, value)
+	}
+	if value, ok := oeuo.mutation.LastError(); ok {
+		_spec.SetField(outboxevent.FieldLastError, field.TypeString, value)
+	}
+	if oeuo.mutation.LastErrorCleared() {
+		_spec.ClearField(outboxevent.FieldLastError, field.TypeString)
+	}
+	if value, ok := oeuo.mutation.AvailableAt(); ok {
+		_spec.SetField(outboxevent.FieldAvailableAt, field.TypeTime, value)
+	}
+	if value, ok := oeuo.mutation.DeliveredAt(); ok {
+		_spec.SetField(outboxevent.FieldDeliveredAt, field.TypeTime, value)
+	}
+	if oeuo.mutation.DeliveredAtCleared() {
+		_spec.ClearField(outboxevent.FieldDeliveredAt, field.TypeTime)
+	}
+	if value, ok := oeuo.mutation.DeadAt(); ok {
+		_spec.SetField(outboxevent.FieldDeadAt, field.TypeTime, value)
+	}
+	if oeuo.mutation.DeadAtCleared() {
+		_spec.ClearField(outboxevent.FieldDeadAt, field.TypeTime)
+	}
+	_node = &OutboxEvent{config: oeuo.config}
+	_spec.Assign = _node.assignValues
+	_spec.ScanValues = _node.scanValues
+	if err = sqlgraph.UpdateNode(ctx, oeuo.driver, _spec); err != nil {
+		if _, ok := err.(*sqlgraph.NotFoundError); ok {
+			err = &NotFoundError{outboxevent.Label}
+		} else if sqlgraph.IsConstraintError(err) {
+			err = &ConstraintError{msg: err.Error(), wrap: err}
+		}
+		return nil, err
+	}
+	oeuo.mutation.done = true
+	return _node, nil
+}
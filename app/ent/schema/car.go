@@ -1,9 +1,15 @@
 package schema
 
 import (
+	"context"
+	"entgo.io/contrib/entgql"
 	"entgo.io/ent"
+	"entgo.io/ent/schema"
 	"entgo.io/ent/schema/edge"
 	"entgo.io/ent/schema/field"
+	gen "github.com/jpdel518/go-ent/ent"
+	"github.com/jpdel518/go-ent/ent/car"
+	"github.com/jpdel518/go-ent/ent/user"
 )
 
 // Car holds the schema definition for the Car entity.
@@ -14,9 +20,11 @@ type Car struct {
 // Fields of the Car.
 func (Car) Fields() []ent.Field {
 	return []ent.Field{
-		field.String("name"),
+		field.String("name").
+			Annotations(entgql.OrderField("NAME")),
 		field.String("model"),
-		field.Time("registered_at"),
+		field.Time("registered_at").
+			Annotations(entgql.OrderField("REGISTERED_AT")),
 	}
 }
 
@@ -34,8 +42,26 @@ func (Car) Edges() []ent.Edge {
 	}
 }
 
+// Hooks of the Car.
+func (Car) Hooks() []ent.Hook {
+	return []ent.Hook{
+		sameTenant(car.EdgeOwner, func(ctx context.Context, c *gen.Client, ids []int) (int, error) {
+			return c.User.Query().Where(user.IDIn(ids...)).Count(ctx)
+		}),
+	}
+}
+
+// Annotations of the Car.
+func (Car) Annotations() []schema.Annotation {
+	return []schema.Annotation{
+		entgql.QueryField(),
+		entgql.RelayConnection(),
+	}
+}
+
 func (Car) Mixin() []ent.Mixin {
 	return []ent.Mixin{
 		TimeMixin{},
+		TenantMixin{},
 	}
 }
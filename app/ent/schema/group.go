@@ -1,9 +1,15 @@
 package schema
 
 import (
+	"context"
+	"entgo.io/contrib/entgql"
 	"entgo.io/ent"
+	"entgo.io/ent/schema"
 	"entgo.io/ent/schema/edge"
 	"entgo.io/ent/schema/field"
+	gen "github.com/jpdel518/go-ent/ent"
+	"github.com/jpdel518/go-ent/ent/group"
+	"github.com/jpdel518/go-ent/ent/user"
 	"regexp"
 )
 
@@ -17,7 +23,8 @@ func (Group) Fields() []ent.Field {
 	return []ent.Field{
 		// regexp validation
 		field.String("name").
-			Match(regexp.MustCompile("^[a-zA-Z_]+$")),
+			Match(regexp.MustCompile("^[a-zA-Z_]+$")).
+			Annotations(entgql.OrderField("NAME")),
 	}
 }
 
@@ -28,8 +35,26 @@ func (Group) Edges() []ent.Edge {
 	}
 }
 
+// Hooks of the Group.
+func (Group) Hooks() []ent.Hook {
+	return []ent.Hook{
+		sameTenant(group.EdgeUsers, func(ctx context.Context, c *gen.Client, ids []int) (int, error) {
+			return c.User.Query().Where(user.IDIn(ids...)).Count(ctx)
+		}),
+	}
+}
+
+// Annotations of the Group.
+func (Group) Annotations() []schema.Annotation {
+	return []schema.Annotation{
+		entgql.QueryField(),
+		entgql.RelayConnection(),
+	}
+}
+
 func (Group) Mixin() []ent.Mixin {
 	return []ent.Mixin{
 		TimeMixin{},
+		TenantMixin{},
 	}
 }
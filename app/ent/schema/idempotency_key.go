@@ -0,0 +1,69 @@
+package schema
+
+import (
+	"entgo.io/contrib/entgql"
+	"entgo.io/ent"
+	"entgo.io/ent/dialect"
+	"entgo.io/ent/schema"
+	"entgo.io/ent/schema/field"
+	"entgo.io/ent/schema/index"
+)
+
+// IdempotencyKey holds the schema definition for the IdempotencyKey entity.
+// It keeps the response to a request with an Idempotency-Key header to replay it for the retries.
+type IdempotencyKey struct {
+	ent.Schema
+}
+
+// Fields of the IdempotencyKey.
+func (IdempotencyKey) Fields() []ent.Field {
+	return []ent.Field{
+		field.String("key").
+			NotEmpty().
+			MaxLen(255),
+		// the client which sent the key, empty when it is not identified
+		field.String("scope").
+			Default(""),
+		// method and route pattern of the request
+		field.String("route").
+			NotEmpty(),
+		// hash of the route and the body of the request
+		field.String("fingerprint").
+			NotEmpty(),
+		field.Enum("status").
+			Values("pending", "completed").
+			Default("pending"),
+		field.Int("response_code").
+			Optional(),
+		field.String("response_content_type").
+			Optional(),
+		field.Bytes("response_body").
+			Optional().
+			SchemaType(map[string]string{
+				dialect.MySQL: "mediumblob",
+			}),
+		field.Time("expires_at"),
+	}
+}
+
+// Indexes of the IdempotencyKey.
+func (IdempotencyKey) Indexes() []ent.Index {
+	return []ent.Index{
+		index.Fields("scope", "key").
+			Unique(),
+		index.Fields("expires_at"),
+	}
+}
+
+func (IdempotencyKey) Mixin() []ent.Mixin {
+	return []ent.Mixin{
+		TimeMixin{},
+	}
+}
+
+// Annotations of the IdempotencyKey. It is internal and not exposed over GraphQL.
+func (IdempotencyKey) Annotations() []schema.Annotation {
+	return []schema.Annotation{
+		entgql.Skip(entgql.SkipAll),
+	}
+}
@@ -0,0 +1,65 @@
+package schema
+
+import (
+	"entgo.io/contrib/entgql"
+	"entgo.io/ent"
+	"entgo.io/ent/schema"
+	"entgo.io/ent/schema/field"
+	"entgo.io/ent/schema/index"
+)
+
+// Job holds the schema definition for the Job entity.
+type Job struct {
+	ent.Schema
+}
+
+// Fields of the Job.
+func (Job) Fields() []ent.Field {
+	return []ent.Field{
+		field.String("type").
+			NotEmpty(),
+		field.Enum("status").
+			Values("queued", "running", "succeeded", "failed", "canceled").
+			Default("queued"),
+		// percentage of the processed work
+		field.Int("progress").
+			Default(0).
+			Range(0, 100),
+		field.JSON("params", map[string]string{}).
+			Optional(),
+		field.JSON("result", map[string]interface{}{}).
+			Optional(),
+		field.Text("error_message").
+			Optional(),
+		// location of the error report in the storage
+		field.String("error_report").
+			Optional(),
+		field.Time("started_at").
+			Optional().
+			Nillable(),
+		field.Time("finished_at").
+			Optional().
+			Nillable(),
+	}
+}
+
+// Indexes of the Job.
+func (Job) Indexes() []ent.Index {
+	return []ent.Index{
+		index.Fields("status"),
+	}
+}
+
+func (Job) Mixin() []ent.Mixin {
+	return []ent.Mixin{
+		TimeMixin{},
+		TenantMixin{},
+	}
+}
+
+// Annotations of the Job. It is internal and not exposed over GraphQL.
+func (Job) Annotations() []schema.Annotation {
+	return []schema.Annotation{
+		entgql.Skip(entgql.SkipAll),
+	}
+}
@@ -0,0 +1,70 @@
+package schema
+
+import (
+	"entgo.io/contrib/entgql"
+	"entgo.io/ent"
+	"entgo.io/ent/schema"
+	"entgo.io/ent/schema/field"
+	"entgo.io/ent/schema/index"
+	"time"
+)
+
+// Notification holds the schema definition for the Notification entity.
+// It is a mail to a user queued by the notifier, which is sent in the background and retried.
+type Notification struct {
+	ent.Schema
+}
+
+// Fields of the Notification.
+func (Notification) Fields() []ent.Field {
+	return []ent.Field{
+		// the user is not an edge, so that the notifications outlive them
+		field.Int("user_id"),
+		// the event which the notification is about
+		field.Int("event_id"),
+		field.String("kind").
+			NotEmpty(),
+		field.String("to").
+			NotEmpty(),
+		field.String("subject"),
+		field.Text("text"),
+		field.Text("html").
+			Optional(),
+		field.Enum("status").
+			Values("pending", "sent", "failed").
+			Default("pending"),
+		field.Int("attempts").
+			Default(0),
+		field.Text("last_error").
+			Optional(),
+		field.Time("next_attempt_at").
+			Default(time.Now),
+		field.Time("sent_at").
+			Optional().
+			Nillable(),
+	}
+}
+
+// Indexes of the Notification.
+func (Notification) Indexes() []ent.Index {
+	return []ent.Index{
+		index.Fields("status", "next_attempt_at"),
+		// a user is notified of an event only once even if the relay publishes it again
+		index.Fields("event_id", "user_id", "kind").
+			Unique(),
+	}
+}
+
+func (Notification) Mixin() []ent.Mixin {
+	return []ent.Mixin{
+		TimeMixin{},
+		TenantMixin{},
+	}
+}
+
+// Annotations of the Notification. It is internal and not exposed over GraphQL.
+func (Notification) Annotations() []schema.Annotation {
+	return []schema.Annotation{
+		entgql.Skip(entgql.SkipAll),
+	}
+}
@@ -0,0 +1,68 @@
+package schema
+
+import (
+	"encoding/json"
+	"entgo.io/contrib/entgql"
+	"entgo.io/ent"
+	"entgo.io/ent/schema"
+	"entgo.io/ent/schema/field"
+	"entgo.io/ent/schema/index"
+	"time"
+)
+
+// OutboxEvent holds the schema definition for the OutboxEvent entity.
+// Events are written in the same transaction as the change and delivered by the relay afterwards.
+type OutboxEvent struct {
+	ent.Schema
+}
+
+// Fields of the OutboxEvent.
+func (OutboxEvent) Fields() []ent.Field {
+	return []ent.Field{
+		field.String("event_type").
+			NotEmpty(),
+		field.String("aggregate_type").
+			NotEmpty(),
+		field.Int("aggregate_id"),
+		field.JSON("payload", json.RawMessage{}),
+		field.Time("occurred_at").
+			Immutable().
+			Default(time.Now),
+		field.Int("attempts").
+			Default(0),
+		field.Text("last_error").
+			Optional(),
+		// the relay does not deliver the event before this time
+		field.Time("available_at").
+			Default(time.Now),
+		field.Time("delivered_at").
+			Optional().
+			Nillable(),
+		// the relay gave up the event after too many attempts, and the later events of its aggregate go on without it
+		field.Time("dead_at").
+			Optional().
+			Nillable(),
+	}
+}
+
+// Indexes of the OutboxEvent.
+func (OutboxEvent) Indexes() []ent.Index {
+	return []ent.Index{
+		index.Fields("delivered_at"),
+		index.Fields("aggregate_type", "aggregate_id"),
+	}
+}
+
+// Mixin of the OutboxEvent. The events are relayed with the tenant of the change
+func (OutboxEvent) Mixin() []ent.Mixin {
+	return []ent.Mixin{
+		TenantMixin{},
+	}
+}
+
+// Annotations of the OutboxEvent. It is internal and not exposed over GraphQL.
+func (OutboxEvent) Annotations() []schema.Annotation {
+	return []schema.Annotation{
+		entgql.Skip(entgql.SkipAll),
+	}
+}
@@ -0,0 +1,47 @@
+package schema
+
+import (
+	"entgo.io/contrib/entgql"
+	"entgo.io/ent"
+	"entgo.io/ent/schema"
+	"entgo.io/ent/schema/field"
+	"regexp"
+)
+
+// Tenant holds the schema definition for the Tenant entity.
+// It is a customer organisation whose users, cars and groups are kept apart from those of the others.
+type Tenant struct {
+	ent.Schema
+}
+
+// Fields of the Tenant.
+func (Tenant) Fields() []ent.Field {
+	return []ent.Field{
+		field.String("name").
+			NotEmpty(),
+		// subdomain and JWT claim of the tenant
+		field.String("slug").
+			Unique().
+			MaxLen(63).
+			Match(regexp.MustCompile("^[a-z0-9]+(-[a-z0-9]+)*$")),
+		// SHA-256 of the API key in hex, the key itself is not stored
+		field.String("api_key_hash").
+			Optional().
+			Nillable().
+			Unique().
+			Sensitive(),
+	}
+}
+
+func (Tenant) Mixin() []ent.Mixin {
+	return []ent.Mixin{
+		TimeMixin{},
+	}
+}
+
+// Annotations of the Tenant. It is internal and not exposed over GraphQL.
+func (Tenant) Annotations() []schema.Annotation {
+	return []schema.Annotation{
+		entgql.Skip(entgql.SkipAll),
+	}
+}
@@ -0,0 +1,120 @@
+package schema
+
+import (
+	"context"
+	"entgo.io/contrib/entgql"
+	"entgo.io/ent"
+	"entgo.io/ent/dialect/sql"
+	"entgo.io/ent/schema/edge"
+	"entgo.io/ent/schema/field"
+	"entgo.io/ent/schema/mixin"
+	gen "github.com/jpdel518/go-ent/ent"
+	"github.com/jpdel518/go-ent/ent/intercept"
+	"github.com/jpdel518/go-ent/tenant"
+)
+
+// TenantMixin makes the entity belong to a tenant.
+// The queries only see the entities of the tenant of the context, the new entities get its tenant,
+// and the updates and the deletions only touch its entities. A context without a tenant fails them with tenant.ErrMissing
+type TenantMixin struct {
+	mixin.Schema
+}
+
+func (TenantMixin) Fields() []ent.Field {
+	return []ent.Field{
+		field.Int("tenant_id").
+			Immutable().
+			Annotations(entgql.Skip(entgql.SkipAll)),
+	}
+}
+
+func (TenantMixin) Edges() []ent.Edge {
+	return []ent.Edge{
+		edge.To("tenant", Tenant.Type).
+			Field("tenant_id").
+			Unique().
+			Required().
+			Immutable().
+			Annotations(entgql.Skip(entgql.SkipAll)),
+	}
+}
+
+func (TenantMixin) Interceptors() []ent.Interceptor {
+	return []ent.Interceptor{
+		intercept.TraverseFunc(func(ctx context.Context, q intercept.Query) error {
+			if tenant.IsAll(ctx) {
+				return nil
+			}
+			id, ok := tenant.FromContext(ctx)
+			if !ok {
+				return tenant.ErrMissing
+			}
+			q.WhereP(sql.FieldEQ("tenant_id", id))
+			return nil
+		}),
+	}
+}
+
+func (TenantMixin) Hooks() []ent.Hook {
+	return []ent.Hook{
+		func(next ent.Mutator) ent.Mutator {
+			return ent.MutateFunc(func(ctx context.Context, m ent.Mutation) (ent.Value, error) {
+				id, ok := tenant.FromContext(ctx)
+				switch {
+				case tenant.IsAll(ctx):
+					// the workers of every tenant create the entities of the tenant they set
+					if _, set := m.Field("tenant_id"); m.Op().Is(ent.OpCreate) && !set {
+						return nil, tenant.ErrMissing
+					}
+					return next.Mutate(ctx, m)
+				case !ok:
+					return nil, tenant.ErrMissing
+				case m.Op().Is(ent.OpCreate):
+					if v, set := m.Field("tenant_id"); set && v != id {
+						return nil, tenant.ErrCrossTenant
+					}
+					if err := m.SetField("tenant_id", id); err != nil {
+						return nil, err
+					}
+				default:
+					wm, ok := m.(interface{ WhereP(...func(*sql.Selector)) })
+					if !ok {
+						return nil, tenant.ErrMissing
+					}
+					wm.WhereP(sql.FieldEQ("tenant_id", id))
+				}
+				return next.Mutate(ctx, m)
+			})
+		},
+	}
+}
+
+// sameTenant fails the mutation with tenant.ErrCrossTenant when the entities it adds to the edge are not of the tenant of the context.
+// count returns how many of them the scoped client sees
+func sameTenant(edgeName string, count func(ctx context.Context, c *gen.Client, ids []int) (int, error)) ent.Hook {
+	return func(next ent.Mutator) ent.Mutator {
+		return ent.MutateFunc(func(ctx context.Context, m ent.Mutation) (ent.Value, error) {
+			cm, ok := m.(interface{ Client() *gen.Client })
+			added := m.AddedIDs(edgeName)
+			if !ok || len(added) == 0 || tenant.IsAll(ctx) {
+				return next.Mutate(ctx, m)
+			}
+			unique := make(map[int]bool, len(added))
+			ids := make([]int, 0, len(added))
+			for _, v := range added {
+				if id, ok := v.(int); ok && !unique[id] {
+					unique[id] = true
+					ids = append(ids, id)
+				}
+			}
+			n, err := count(ctx, cm.Client(), ids)
+			if err != nil {
+				return nil, err
+			}
+			if n != len(ids) {
+				return nil, tenant.ErrCrossTenant
+			}
+			return next.Mutate(ctx, m)
+		})
+	}
+}
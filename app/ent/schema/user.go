@@ -1,10 +1,20 @@
 package schema
 
 import (
+	"context"
+	"entgo.io/contrib/entgql"
 	"entgo.io/ent"
 	"entgo.io/ent/dialect"
+	"entgo.io/ent/schema"
 	"entgo.io/ent/schema/edge"
 	"entgo.io/ent/schema/field"
+	"entgo.io/ent/schema/index"
+	"github.com/jpdel518/go-ent/domain/model"
+	gen "github.com/jpdel518/go-ent/ent"
+	"github.com/jpdel518/go-ent/ent/car"
+	"github.com/jpdel518/go-ent/ent/group"
+	"github.com/jpdel518/go-ent/ent/hook"
+	"github.com/jpdel518/go-ent/ent/user"
 )
 
 // User holds the schema definition for the User entity.
@@ -27,12 +37,29 @@ func (User) Fields() []ent.Field {
 			}),
 		field.String("email").
 			NotEmpty().
-			Unique().
 			SchemaType(map[string]string{
 				dialect.MySQL: "varchar(50)",
-			}),
+			}).
+			Annotations(entgql.OrderField("EMAIL")),
 		field.Int("age").
+			Optional().
+			Annotations(entgql.OrderField("AGE")),
+		field.String("avatar").
 			Optional(),
+		// when the e-mail was verified, which is cleared when it changes
+		field.Time("verified_at").
+			Optional().
+			Nillable(),
+		// the notification preferences, which have their own route. The language is the default one when it is empty
+		field.String("language").
+			Optional().
+			Annotations(entgql.Skip(entgql.SkipAll)),
+		field.Bool("notify_car_assigned").
+			Default(true).
+			Annotations(entgql.Skip(entgql.SkipAll)),
+		field.Bool("notify_group_added").
+			Default(true).
+			Annotations(entgql.Skip(entgql.SkipAll)),
 	}
 }
 
@@ -48,8 +75,64 @@ func (User) Edges() []ent.Edge {
 	}
 }
 
+// Indexes of the User. The e-mail is unique in the tenant
+func (User) Indexes() []ent.Index {
+	return []ent.Index{
+		index.Fields("tenant_id", "email").
+			Unique(),
+	}
+}
+
+// Hooks of the User.
+func (User) Hooks() []ent.Hook {
+	return []ent.Hook{
+		hook.On(normalizeEmail, ent.OpCreate|ent.OpUpdate|ent.OpUpdateOne),
+		sameTenant(user.EdgeCars, func(ctx context.Context, c *gen.Client, ids []int) (int, error) {
+			return c.Car.Query().Where(car.IDIn(ids...)).Count(ctx)
+		}),
+		sameTenant(user.EdgeGroup, func(ctx context.Context, c *gen.Client, ids []int) (int, error) {
+			return c.Group.Query().Where(group.IDIn(ids...)).Count(ctx)
+		}),
+	}
+}
+
+// normalizeEmail stores the e-mails trimmed and in lower case, so that the unique index ignores the case.
+// Changing the e-mail makes it unverified again
+func normalizeEmail(next ent.Mutator) ent.Mutator {
+	return hook.UserFunc(func(ctx context.Context, m *gen.UserMutation) (ent.Value, error) {
+		email, ok := m.Email()
+		if !ok {
+			return next.Mutate(ctx, m)
+		}
+		email = model.NormalizeEmail(email)
+		m.SetEmail(email)
+		switch {
+		case m.Op().Is(ent.OpUpdateOne):
+			old, err := m.OldEmail(ctx)
+			if err != nil {
+				return nil, err
+			}
+			if old != email {
+				m.ClearVerifiedAt()
+			}
+		case m.Op().Is(ent.OpUpdate):
+			m.ClearVerifiedAt()
+		}
+		return next.Mutate(ctx, m)
+	})
+}
+
+// Annotations of the User.
+func (User) Annotations() []schema.Annotation {
+	return []schema.Annotation{
+		entgql.QueryField(),
+		entgql.RelayConnection(),
+	}
+}
+
 func (User) Mixin() []ent.Mixin {
 	return []ent.Mixin{
 		TimeMixin{},
+		TenantMixin{},
 	}
 }
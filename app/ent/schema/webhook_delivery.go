@@ -0,0 +1,76 @@
+package schema
+
+import (
+	"encoding/json"
+	"entgo.io/contrib/entgql"
+	"entgo.io/ent"
+	"entgo.io/ent/schema"
+	"entgo.io/ent/schema/edge"
+	"entgo.io/ent/schema/field"
+	"entgo.io/ent/schema/index"
+	"time"
+)
+
+// WebhookDelivery holds the schema definition for the WebhookDelivery entity.
+// It is the delivery log of an event to a subscription.
+type WebhookDelivery struct {
+	ent.Schema
+}
+
+// Fields of the WebhookDelivery.
+func (WebhookDelivery) Fields() []ent.Field {
+	return []ent.Field{
+		field.Int("event_id"),
+		field.String("event_type"),
+		field.JSON("payload", json.RawMessage{}),
+		field.Enum("status").
+			Values("pending", "succeeded", "failed").
+			Default("pending"),
+		field.Int("attempts").
+			Default(0),
+		// status code of the last response
+		field.Int("response_code").
+			Optional(),
+		field.Text("last_error").
+			Optional(),
+		field.Time("next_attempt_at").
+			Default(time.Now),
+		field.Time("delivered_at").
+			Optional().
+			Nillable(),
+	}
+}
+
+// Edges of the WebhookDelivery.
+func (WebhookDelivery) Edges() []ent.Edge {
+	return []ent.Edge{
+		edge.From("subscription", WebhookSubscription.Type).
+			Ref("deliveries").
+			Unique().
+			Required(),
+	}
+}
+
+// Indexes of the WebhookDelivery.
+func (WebhookDelivery) Indexes() []ent.Index {
+	return []ent.Index{
+		index.Fields("status", "next_attempt_at"),
+		// an event is delivered to a subscription only once even if the relay sends it again
+		index.Fields("event_id").
+			Edges("subscription").
+			Unique(),
+	}
+}
+
+func (WebhookDelivery) Mixin() []ent.Mixin {
+	return []ent.Mixin{
+		TimeMixin{},
+	}
+}
+
+// Annotations of the WebhookDelivery. It is internal and not exposed over GraphQL.
+func (WebhookDelivery) Annotations() []schema.Annotation {
+	return []schema.Annotation{
+		entgql.Skip(entgql.SkipAll),
+	}
+}
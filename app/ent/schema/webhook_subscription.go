@@ -0,0 +1,62 @@
+package schema
+
+import (
+	"entgo.io/contrib/entgql"
+	"entgo.io/ent"
+	"entgo.io/ent/dialect/entsql"
+	"entgo.io/ent/schema"
+	"entgo.io/ent/schema/edge"
+	"entgo.io/ent/schema/field"
+)
+
+// WebhookSubscription holds the schema definition for the WebhookSubscription entity.
+type WebhookSubscription struct {
+	ent.Schema
+}
+
+// Fields of the WebhookSubscription.
+func (WebhookSubscription) Fields() []ent.Field {
+	return []ent.Field{
+		field.String("url").
+			NotEmpty(),
+		// key of the HMAC-SHA256 signature
+		field.String("secret").
+			NotEmpty().
+			Sensitive(),
+		// all events are sent when it is empty
+		field.JSON("event_types", []string{}).
+			Optional(),
+		field.Bool("active").
+			Default(true),
+		// number of failed attempts in a row
+		field.Int("failure_count").
+			Default(0),
+		field.Time("disabled_at").
+			Optional().
+			Nillable(),
+	}
+}
+
+// Edges of the WebhookSubscription.
+func (WebhookSubscription) Edges() []ent.Edge {
+	return []ent.Edge{
+		edge.To("deliveries", WebhookDelivery.Type).
+			Annotations(entsql.Annotation{
+				OnDelete: entsql.Cascade,
+			}),
+	}
+}
+
+func (WebhookSubscription) Mixin() []ent.Mixin {
+	return []ent.Mixin{
+		TimeMixin{},
+		TenantMixin{},
+	}
+}
+
+// Annotations of the WebhookSubscription. It is internal and not exposed over GraphQL.
+func (WebhookSubscription) Annotations() []schema.Annotation {
+	return []schema.Annotation{
+		entgql.Skip(entgql.SkipAll),
+	}
+}
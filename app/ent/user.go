@@ -8,6 +8,7 @@ import (
 	"time"
 
 	"entgo.io/ent/dialect/sql"
+	"github.com/jpdel518/go-ent/ent/tenant"
 	"github.com/jpdel518/go-ent/ent/user"
 )
 
@@ -20,6 +21,8 @@ type User struct {
 	CreatedAt time.Time `json:"created_at,omitempty"`
 	// UpdatedAt holds the value of the "updated_at" field.
 	UpdatedAt time.Time `json:"updated_at,omitempty"`
+	// TenantID holds the value of the "tenant_id" field.
+	TenantID int `json:"tenant_id,omitempty"`
 	// FirstName holds the value of the "first_name" field.
 	FirstName string `json:"first_name,omitempty"`
 	// LastName holds the value of the "last_name" field.
@@ -28,6 +31,16 @@ type User struct {
 	Email string `json:"email,omitempty"`
 	// Age holds the value of the "age" field.
 	Age int `json:"age,omitempty"`
+	// Avatar holds the value of the "avatar" field.
+	Avatar string `json:"avatar,omitempty"`
+	// VerifiedAt holds the value of the "verified_at" field.
+	VerifiedAt *time.Time `json:"verified_at,omitempty"`
+	// Language holds the value of the "language" field.
+	Language string `json:"language,omitempty"`
+	// NotifyCarAssigned holds the value of the "notify_car_assigned" field.
+	NotifyCarAssigned bool `json:"notify_car_assigned,omitempty"`
+	// NotifyGroupAdded holds the value of the "notify_group_added" field.
+	NotifyGroupAdded bool `json:"notify_group_added,omitempty"`
 	// Edges holds the relations/edges for other nodes in the graph.
 	// The values are being populated by the UserQuery when eager-loading is set.
 	Edges UserEdges `json:"edges"`
@@ -35,19 +48,39 @@ type User struct {
 
 // UserEdges holds the relations/edges for other nodes in the graph.
 type UserEdges struct {
+	// Tenant holds the value of the tenant edge.
+	Tenant *Tenant `json:"tenant,omitempty"`
 	// Cars holds the value of the cars edge.
 	Cars []*Car `json:"cars,omitempty"`
 	// Group holds the value of the group edge.
 	Group []*Group `json:"group,omitempty"`
 	// loadedTypes holds the information for reporting if a
 	// type was loaded (or requested) in eager-loading or not.
-	loadedTypes [2]bool
+	loadedTypes [3]bool
+	// totalCount holds the count of the edges above.
+	totalCount [2]map[string]int
+
+	namedCars  map[string][]*Car
+	namedGroup map[string][]*Group
+}
+
+// TenantOrErr returns the Tenant value or an error if the edge
+// was not loaded in eager-loading, or loaded but was not found.
+func (e UserEdges) TenantOrErr() (*Tenant, error) {
+	if e.loadedTypes[0] {
+		if e.Tenant == nil {
+			// Edge was loaded but was not found.
+			return nil, &NotFoundError{label: tenant.Label}
+		}
+		return e.Tenant, nil
+	}
+	return nil, &NotLoadedError{edge: "tenant"}
 }
 
 // CarsOrErr returns the Cars value or an error if the edge
 // was not loaded in eager-loading.
 func (e UserEdges) CarsOrErr() ([]*Car, error) {
-	if e.loadedTypes[0] {
+	if e.loadedTypes[1] {
 		return e.Cars, nil
 	}
 	return nil, &NotLoadedError{edge: "cars"}
@@ -56,7 +89,7 @@ func (e UserEdges) CarsOrErr() ([]*Car, error) {
 // GroupOrErr returns the Group value or an error if the edge
 // was not loaded in eager-loading.
 func (e UserEdges) GroupOrErr() ([]*Group, error) {
-	if e.loadedTypes[1] {
+	if e.loadedTypes[2] {
 		return e.Group, nil
 	}
 	return nil, &NotLoadedError{edge: "group"}
@@ -67,11 +100,13 @@ func (*User) scanValues(columns []string) ([]any, error) {
 	values := make([]any, len(columns))
 	for i := range columns {
 		switch columns[i] {
-		case user.FieldID, user.FieldAge:
+		case user.FieldNotifyCarAssigned, user.FieldNotifyGroupAdded:
+			values[i] = new(sql.NullBool)
+		case user.FieldID, user.FieldTenantID, user.FieldAge:
 			values[i] = new(sql.NullInt64)
-		case user.FieldFirstName, user.FieldLastName, user.FieldEmail:
+		case user.FieldFirstName, user.FieldLastName, user.FieldEmail, user.FieldAvatar, user.FieldLanguage:
 			values[i] = new(sql.NullString)
-		case user.FieldCreatedAt, user.FieldUpdatedAt:
+		case user.FieldCreatedAt, user.FieldUpdatedAt, user.FieldVerifiedAt:
 			values[i] = new(sql.NullTime)
 		default:
 			return nil, fmt.Errorf("unexpected column %q for type User", columns[i])
@@ -106,6 +141,12 @@ func (u *User) assignValues(columns []string, values []any) error {
 			} else if value.Valid {
 				u.UpdatedAt = value.Time
 			}
+		case user.FieldTenantID:
+			if value, ok := values[i].(*sql.NullInt64); !ok {
+				return fmt.Errorf("unexpected type %T for field tenant_id", values[i])
+			} else if value.Valid {
+				u.TenantID = int(value.Int64)
+			}
 		case user.FieldFirstName:
 			if value, ok := values[i].(*sql.NullString); !ok {
 				return fmt.Errorf("unexpected type %T for field first_name", values[i])
@@ -130,11 +171,47 @@ func (u *User) assignValues(columns []string, values []any) error {
 			} else if value.Valid {
 				u.Age = int(value.Int64)
 			}
+		case user.FieldAvatar:
+			if value, ok := values[i].(*sql.NullString); !ok {
+				return fmt.Errorf("unexpected type %T for field avatar", values[i])
+			} else if value.Valid {
+				u.Avatar = value.String
+			}
+		case user.FieldVerifiedAt:
+			if value, ok := values[i].(*sql.NullTime); !ok {
+				return fmt.Errorf("unexpected type %T for field verified_at", values[i])
+			} else if value.Valid {
+				u.VerifiedAt = new(time.Time)
+				*u.VerifiedAt = value.Time
+			}
+		case user.FieldLanguage:
+			if value, ok := values[i].(*sql.NullString); !ok {
+				return fmt.Errorf("unexpected type %T for field language", values[i])
+			} else if value.Valid {
+				u.Language = value.String
+			}
+		case user.FieldNotifyCarAssigned:
+			if value, ok := values[i].(*sql.NullBool); !ok {
+				return fmt.Errorf("unexpected type %T for field notify_car_assigned", values[i])
+			} else if value.Valid {
+				u.NotifyCarAssigned = value.Bool
+			}
+		case user.FieldNotifyGroupAdded:
+			if value, ok := values[i].(*sql.NullBool); !ok {
+				return fmt.Errorf("unexpected type %T for field notify_group_added", values[i])
+			} else if value.Valid {
+				u.NotifyGroupAdded = value.Bool
+			}
 		}
 	}
 	return nil
 }
 
+// QueryTenant queries the "tenant" edge of the User entity.
+func (u *User) QueryTenant() *TenantQuery {
+	return NewUserClient(u.config).QueryTenant(u)
+}
+
 // QueryCars queries the "cars" edge of the User entity.
 func (u *User) QueryCars() *CarQuery {
 	return NewUserClient(u.config).QueryCars(u)
@@ -174,6 +251,9 @@ func (u *User) String() string {
 	builder.WriteString("updated_at=")
 	builder.WriteString(u.UpdatedAt.Format(time.ANSIC))
 	builder.WriteString(", ")
+	builder.WriteString("tenant_id=")
+	builder.WriteString(fmt.Sprintf("%v", u.TenantID))
+	builder.WriteString(", ")
 	builder.WriteString("first_name=")
 	builder.WriteString(u.FirstName)
 	builder.WriteString(", ")
@@ -185,9 +265,74 @@ func (u *User) String() string {
 	builder.WriteString(", ")
 	builder.WriteString("age=")
 	builder.WriteString(fmt.Sprintf("%v", u.Age))
+	builder.WriteString(", ")
+	builder.WriteString("avatar=")
+	builder.WriteString(u.Avatar)
+	builder.WriteString(", ")
+	if v := u.VerifiedAt; v != nil {
+		builder.WriteString("verified_at=")
+		builder.WriteString(v.Format(time.ANSIC))
+	}
+	builder.WriteString(", ")
+	builder.WriteString("language=")
+	builder.WriteString(u.Language)
+	builder.WriteString(", ")
+	builder.WriteString("notify_car_assigned=")
+	builder.WriteString(fmt.Sprintf("%v", u.NotifyCarAssigned))
+	builder.WriteString(", ")
+	builder.WriteString("notify_group_added=")
+	builder.WriteString(fmt.Sprintf("%v", u.NotifyGroupAdded))
 	builder.WriteByte(')')
 	return builder.String()
 }
 
+// NamedCars returns the Cars named value or an error if the edge was not
+// loaded in eager-loading with this name.
+func (u *User) NamedCars(name string) ([]*Car, error) {
+	if u.Edges.namedCars == nil {
+		return nil, &NotLoadedError{edge: name}
+	}
+	nodes, ok := u.Edges.namedCars[name]
+	if !ok {
+		return nil, &NotLoadedError{edge: name}
+	}
+	return nodes, nil
+}
+
+func (u *User) appendNamedCars(name string, edges ...*Car) {
+	if u.Edges.namedCars == nil {
+		u.Edges.namedCars = make(map[string][]*Car)
+	}
+	if len(edges) == 0 {
+		u.Edges.namedCars[name] = []*Car{}
+	} else {
+		u.Edges.namedCars[name] = append(u.Edges.namedCars[name], edges...)
+	}
+}
+
+// NamedGroup returns the Group named value or an error if the edge was not
+// loaded in eager-loading with this name.
+func (u *User) NamedGroup(name string) ([]*Group, error) {
+	if u.Edges.namedGroup == nil {
+		return nil, &NotLoadedError{edge: name}
+	}
+	nodes, ok := u.Edges.namedGroup[name]
+	if !ok {
+		return nil, &NotLoadedError{edge: name}
+	}
+	return nodes, nil
+}
+
+func (u *User) appendNamedGroup(name string, edges ...*Group) {
+	if u.Edges.namedGroup == nil {
+		u.Edges.namedGroup = make(map[string][]*Group)
+	}
+	if len(edges) == 0 {
+		u.Edges.namedGroup[name] = []*Group{}
+	} else {
+		u.Edges.namedGroup[name] = append(u.Edges.namedGroup[name], edges...)
+	}
+}
+
 // Users is a parsable slice of User.
 type Users []*User
@@ -4,6 +4,8 @@ package user
 
 import (
 	"time"
+
+	"entgo.io/ent"
 )
 
 const (
@@ -15,6 +17,8 @@ const (
 	FieldCreatedAt = "created_at"
 	// FieldUpdatedAt holds the string denoting the updated_at field in the database.
 	FieldUpdatedAt = "updated_at"
+	// FieldTenantID holds the string denoting the tenant_id field in the database.
+	FieldTenantID = "tenant_id"
 	// FieldFirstName holds the string denoting the first_name field in the database.
 	FieldFirstName = "first_name"
 	// FieldLastName holds the string denoting the last_name field in the database.
@@ -23,12 +27,31 @@ const (
 	FieldEmail = "email"
 	// FieldAge holds the string denoting the age field in the database.
 	FieldAge = "age"
+	// FieldAvatar holds the string denoting the avatar field in the database.
+	FieldAvatar = "avatar"
+	// FieldVerifiedAt holds the string denoting the verified_at field in the database.
+	FieldVerifiedAt = "verified_at"
+	// FieldLanguage holds the string denoting the language field in the database.
+	FieldLanguage = "language"
+	// FieldNotifyCarAssigned holds the string denoting the notify_car_assigned field in the database.
+	FieldNotifyCarAssigned = "notify_car_assigned"
+	// FieldNotifyGroupAdded holds the string denoting the notify_group_added field in the database.
+	FieldNotifyGroupAdded = "notify_group_added"
+	// EdgeTenant holds the string denoting the tenant edge name in mutations.
+	EdgeTenant = "tenant"
 	// EdgeCars holds the string denoting the cars edge name in mutations.
 	EdgeCars = "cars"
 	// EdgeGroup holds the string denoting the group edge name in mutations.
 	EdgeGroup = "group"
 	// Table holds the table name of the user in the database.
 	Table = "users"
+	// TenantTable is the table that holds the tenant relation/edge.
+	TenantTable = "users"
+	// TenantInverseTable is the table name for the Tenant entity.
+	// It exists in this package in order to avoid circular dependency with the "tenant" package.
+	TenantInverseTable = "tenants"
+	// TenantColumn is the table column denoting the tenant relation/edge.
+	TenantColumn = "tenant_id"
 	// CarsTable is the table that holds the cars relation/edge.
 	CarsTable = "cars"
 	// CarsInverseTable is the table name for the Car entity.
@@ -48,10 +71,16 @@ var Columns = []string{
 	FieldID,
 	FieldCreatedAt,
 	FieldUpdatedAt,
+	FieldTenantID,
 	FieldFirstName,
 	FieldLastName,
 	FieldEmail,
 	FieldAge,
+	FieldAvatar,
+	FieldVerifiedAt,
+	FieldLanguage,
+	FieldNotifyCarAssigned,
+	FieldNotifyGroupAdded,
 }
 
 var (
@@ -70,7 +99,14 @@ func ValidColumn(column string) bool {
 	return false
 }
 
+// Note that the variables below are initialized by the runtime
+// package on the initialization of the application. Therefore,
+// it should be imported in the main as follows:
+//
+//	import _ "github.com/jpdel518/go-ent/ent/runtime"
 var (
+	Hooks        [4]ent.Hook
+	Interceptors [1]ent.Interceptor
 	// DefaultCreatedAt holds the default value on creation for the "created_at" field.
 	DefaultCreatedAt func() time.Time
 	// DefaultUpdatedAt holds the default value on creation for the "updated_at" field.
@@ -83,4 +119,8 @@ var (
 	LastNameValidator func(string) error
 	// EmailValidator is a validator for the "email" field. It is called by the builders before save.
 	EmailValidator func(string) error
+	// DefaultNotifyCarAssigned holds the default value on creation for the "notify_car_assigned" field.
+	DefaultNotifyCarAssigned bool
+	// DefaultNotifyGroupAdded holds the default value on creation for the "notify_group_added" field.
+	DefaultNotifyGroupAdded bool
 )
@@ -12,6 +12,7 @@ import (
 	"entgo.io/ent/schema/field"
 	"github.com/jpdel518/go-ent/ent/car"
 	"github.com/jpdel518/go-ent/ent/group"
+	"github.com/jpdel518/go-ent/ent/tenant"
 	"github.com/jpdel518/go-ent/ent/user"
 )
 
@@ -50,6 +51,12 @@ func (uc *UserCreate) SetNillableUpdatedAt(t *time.Time) *UserCreate {
 	return uc
 }
 
+// SetTenantID sets the "tenant_id" field.
+func (uc *UserCreate) SetTenantID(i int) *UserCreate {
+	uc.mutation.SetTenantID(i)
+	return uc
+}
+
 // SetFirstName sets the "first_name" field.
 func (uc *UserCreate) SetFirstName(s string) *UserCreate {
 	uc.mutation.SetFirstName(s)
@@ -82,6 +89,81 @@ func (uc *UserCreate) SetNillableAge(i *int) *UserCreate {
 	return uc
 }
 
+// SetAvatar sets the "avatar" field.
+func (uc *UserCreate) SetAvatar(s string) *UserCreate {
+	uc.mutation.SetAvatar(s)
+	return uc
+}
+
+// SetNillableAvatar sets the "avatar" field if the given value is not nil.
+func (uc *UserCreate) SetNillableAvatar(s *string) *UserCreate {
+	if s != nil {
+		uc.SetAvatar(*s)
+	}
+	return uc
+}
+
+// SetVerifiedAt sets the "verified_at" field.
+func (uc *UserCreate) SetVerifiedAt(t time.Time) *UserCreate {
+	uc.mutation.SetVerifiedAt(t)
+	return uc
+}
+
+// SetNillableVerifiedAt sets the "verified_at" field if the given value is not nil.
+func (uc *UserCreate) SetNillableVerifiedAt(t *time.Time) *UserCreate {
+	if t != nil {
+		uc.SetVerifiedAt(*t)
+	}
+	return uc
+}
+
+// SetLanguage sets the "language" field.
+func (uc *UserCreate) SetLanguage(s string) *UserCreate {
+	uc.mutation.SetLanguage(s)
+	return uc
+}
+
+// SetNillableLanguage sets the "language" field if the given value is not nil.
+func (uc *UserCreate) SetNillableLanguage(s *string) *UserCreate {
+	if s != nil {
+		uc.SetLanguage(*s)
+	}
+	return uc
+}
+
+// SetNotifyCarAssigned sets the "notify_car_assigned" field.
+func (uc *UserCreate) SetNotifyCarAssigned(b bool) *UserCreate {
+	uc.mutation.SetNotifyCarAssigned(b)
+	return uc
+}
+
+// SetNillableNotifyCarAssigned sets the "notify_car_assigned" field if the given value is not nil.
+func (uc *UserCreate) SetNillableNotifyCarAssigned(b *bool) *UserCreate {
+	if b != nil {
+		uc.SetNotifyCarAssigned(*b)
+	}
+	return uc
+}
+
+// SetNotifyGroupAdded sets the "notify_group_added" field.
+func (uc *UserCreate) SetNotifyGroupAdded(b bool) *UserCreate {
+	uc.mutation.SetNotifyGroupAdded(b)
+	return uc
+}
+
+// SetNillableNotifyGroupAdded sets the "notify_group_added" field if the given value is not nil.
+func (uc *UserCreate) SetNillableNotifyGroupAdded(b *bool) *UserCreate {
+	if b != nil {
+		uc.SetNotifyGroupAdded(*b)
+	}
+	return uc
+}
+
+// SetTenant sets the "tenant" edge to the Tenant entity.
+func (uc *UserCreate) SetTenant(t *Tenant) *UserCreate {
+	return uc.SetTenantID(t.ID)
+}
+
 // AddCarIDs adds the "cars" edge to the Car entity by IDs.
 func (uc *UserCreate) AddCarIDs(ids ...int) *UserCreate {
 	uc.mutation.AddCarIDs(ids...)
@@ -119,7 +201,9 @@ func (uc *UserCreate) Mutation() *UserMutation {
 
 // Save creates the User in the database.
 func (uc *UserCreate) Save(ctx context.Context) (*User, error) {
-	uc.defaults()
+	if err := uc.defaults(); err != nil {
+		return nil, err
+	}
 	return withHooks[*User, UserMutation](ctx, uc.sqlSave, uc.mutation, uc.hooks)
 }
 
@@ -146,15 +230,30 @@ func (uc *UserCreate) ExecX(ctx context.Context) {
 }
 
 // defaults sets the default values of the builder before save.
-func (uc *UserCreate) defaults() {
+func (uc *UserCreate) defaults() error {
 	if _, ok := uc.mutation.CreatedAt(); !ok {
+		if user.DefaultCreatedAt == nil {
+			return fmt.Errorf("ent: uninitialized user.DefaultCreatedAt (forgotten import ent/runtime?)")
+		}
 		v := user.DefaultCreatedAt()
 		uc.mutation.SetCreatedAt(v)
 	}
 	if _, ok := uc.mutation.UpdatedAt(); !ok {
+		if user.DefaultUpdatedAt == nil {
+			return fmt.Errorf("ent: uninitialized user.DefaultUpdatedAt (forgotten import ent/runtime?)")
+		}
 		v := user.DefaultUpdatedAt()
 		uc.mutation.SetUpdatedAt(v)
 	}
+	if _, ok := uc.mutation.NotifyCarAssigned(); !ok {
+		v := user.DefaultNotifyCarAssigned
+		uc.mutation.SetNotifyCarAssigned(v)
+	}
+	if _, ok := uc.mutation.NotifyGroupAdded(); !ok {
+		v := user.DefaultNotifyGroupAdded
+		uc.mutation.SetNotifyGroupAdded(v)
+	}
+	return nil
 }
 
 // check runs all checks and user-defined validators on the builder.
@@ -165,6 +264,9 @@ func (uc *UserCreate) check() error {
 	if _, ok := uc.mutation.UpdatedAt(); !ok {
 		return &ValidationError{Name: "updated_at", err: errors.New(`ent: missing required field "User.updated_at"`)}
 	}
+	if _, ok := uc.mutation.TenantID(); !ok {
+		return &ValidationError{Name: "tenant_id", err: errors.New(`ent: missing required field "User.tenant_id"`)}
+	}
 	if _, ok := uc.mutation.FirstName(); !ok {
 		return &ValidationError{Name: "first_name", err: errors.New(`ent: missing required field "User.first_name"`)}
 	}
@@ -189,6 +291,15 @@ func (uc *UserCreate) check() error {
 			return &ValidationError{Name: "email", err: fmt.Errorf(`ent: validator failed for field "User.email": %w`, err)}
 		}
 	}
+	if _, ok := uc.mutation.NotifyCarAssigned(); !ok {
+		return &ValidationError{Name: "notify_car_assigned", err: errors.New(`ent: missing required field "User.notify_car_assigned"`)}
+	}
+	if _, ok := uc.mutation.NotifyGroupAdded(); !ok {
+		return &ValidationError{Name: "notify_group_added", err: errors.New(`ent: missing required field "User.notify_group_added"`)}
+	}
+	if _, ok := uc.mutation.TenantID(); !ok {
+		return &ValidationError{Name: "tenant", err: errors.New(`ent: missing required edge "User.tenant"`)}
+	}
 	return nil
 }
 
@@ -239,6 +350,43 @@ func (uc *UserCreate) createSpec() (*User, *sqlgraph.CreateSpec) {
 		_spec.SetField(user.FieldAge, field.TypeInt, value)
 		_node.Age = value
 	}
+	if value, ok := uc.mutation.Avatar(); ok {
+		_spec.SetField(user.FieldAvatar, field.TypeString, value)
+		_node.Avatar = value
+	}
+	if value, ok := uc.mutation.VerifiedAt(); ok {
+		_spec.SetField(user.FieldVerifiedAt, field.TypeTime, value)
+		_node.VerifiedAt = &value
+	}
+	if value, ok := uc.mutation.Language(); ok {
+		_spec.SetField(user.FieldLanguage, field.TypeString, value)
+		_node.Language = value
+	}
+	if value, ok := uc.mutation.NotifyCarAssigned(); ok {
+		_spec.SetField(user.FieldNotifyCarAssigned, field.TypeBool, value)
+		_node.NotifyCarAssigned = value
+	}
+	if value, ok := uc.mutation.NotifyGroupAdded(); ok {
+		_spec.SetField(user.FieldNotifyGroupAdded, field.TypeBool, value)
+		_node.NotifyGroupAdded = value
+	}
+	if nodes := uc.mutation.TenantIDs(); len(nodes) > 0 {
+		edge := &sqlgraph.EdgeSpec{
+			Rel:     sqlgraph.M2O,
+			Inverse: false,
+			Table:   user.TenantTable,
+			Columns: []string{user.TenantColumn},
+			Bidi:    false,
+			Target: &sqlgraph.EdgeTarget{
+				IDSpec: sqlgraph.NewFieldSpec(tenant.FieldID, field.TypeInt),
+			},
+		}
+		for _, k := range nodes {
+			edge.Target.Nodes = append(edge.Target.Nodes, k)
+		}
+		_node.TenantID = nodes[0]
+		_spec.Edges = append(_spec.Edges, edge)
+	}
 	if nodes := uc.mutation.CarsIDs(); len(nodes) > 0 {
 		edge := &sqlgraph.EdgeSpec{
 			Rel:     sqlgraph.O2M,
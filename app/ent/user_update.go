@@ -81,6 +81,94 @@ func (uu *UserUpdate) ClearAge() *UserUpdate {
 	return uu
 }
 
+// SetAvatar sets the "avatar" field.
+func (uu *UserUpdate) SetAvatar(s string) *UserUpdate {
+	uu.mutation.SetAvatar(s)
+	return uu
+}
+
+// SetNillableAvatar sets the "avatar" field if the given value is not nil.
+func (uu *UserUpdate) SetNillableAvatar(s *string) *UserUpdate {
+	if s != nil {
+		uu.SetAvatar(*s)
+	}
+	return uu
+}
+
+// ClearAvatar clears the value of the "avatar" field.
+func (uu *UserUpdate) ClearAvatar() *UserUpdate {
+	uu.mutation.ClearAvatar()
+	return uu
+}
+
+// SetVerifiedAt sets the "verified_at" field.
+func (uu *UserUpdate) SetVerifiedAt(t time.Time) *UserUpdate {
+	uu.mutation.SetVerifiedAt(t)
+	return uu
+}
+
+// SetNillableVerifiedAt sets the "verified_at" field if the given value is not nil.
+func (uu *UserUpdate) SetNillableVerifiedAt(t *time.Time) *UserUpdate {
+	if t != nil {
+		uu.SetVerifiedAt(*t)
+	}
+	return uu
+}
+
+// ClearVerifiedAt clears the value of the "verified_at" field.
+func (uu *UserUpdate) ClearVerifiedAt() *UserUpdate {
+	uu.mutation.ClearVerifiedAt()
+	return uu
+}
+
+// SetLanguage sets the "language" field.
+func (uu *UserUpdate) SetLanguage(s string) *UserUpdate {
+	uu.mutation.SetLanguage(s)
+	return uu
+}
+
+// SetNillableLanguage sets the "language" field if the given value is not nil.
+func (uu *UserUpdate) SetNillableLanguage(s *string) *UserUpdate {
+	if s != nil {
+		uu.SetLanguage(*s)
+	}
+	return uu
+}
+
+// ClearLanguage clears the value of the "language" field.
+func (uu *UserUpdate) ClearLanguage() *UserUpdate {
+	uu.mutation.ClearLanguage()
+	return uu
+}
+
+// SetNotifyCarAssigned sets the "notify_car_assigned" field.
+func (uu *UserUpdate) SetNotifyCarAssigned(b bool) *UserUpdate {
+	uu.mutation.SetNotifyCarAssigned(b)
+	return uu
+}
+
+// SetNillableNotifyCarAssigned sets the "notify_car_assigned" field if the given value is not nil.
+func (uu *UserUpdate) SetNillableNotifyCarAssigned(b *bool) *UserUpdate {
+	if b != nil {
+		uu.SetNotifyCarAssigned(*b)
+	}
+	return uu
+}
+
+// SetNotifyGroupAdded sets the "notify_group_added" field.
+func (uu *UserUpdate) SetNotifyGroupAdded(b bool) *UserUpdate {
+	uu.mutation.SetNotifyGroupAdded(b)
+	return uu
+}
+
+// SetNillableNotifyGroupAdded sets the "notify_group_added" field if the given value is not nil.
+func (uu *UserUpdate) SetNillableNotifyGroupAdded(b *bool) *UserUpdate {
+	if b != nil {
+		uu.SetNotifyGroupAdded(*b)
+	}
+	return uu
+}
+
 // AddCarIDs adds the "cars" edge to the Car entity by IDs.
 func (uu *UserUpdate) AddCarIDs(ids ...int) *UserUpdate {
 	uu.mutation.AddCarIDs(ids...)
@@ -160,7 +248,9 @@ func (uu *UserUpdate) RemoveGroup(g ...*Group) *UserUpdate {
 
 // Save executes the query and returns the number of nodes affected by the update operation.
 func (uu *UserUpdate) Save(ctx context.Context) (int, error) {
-	uu.defaults()
+	if err := uu.defaults(); err != nil {
+		return 0, err
+	}
 	return withHooks[int, UserMutation](ctx, uu.sqlSave, uu.mutation, uu.hooks)
 }
 
@@ -187,11 +277,15 @@ func (uu *UserUpdate) ExecX(ctx context.Context) {
 }
 
 // defaults sets the default values of the builder before save.
-func (uu *UserUpdate) defaults() {
+func (uu *UserUpdate) defaults() error {
 	if _, ok := uu.mutation.UpdatedAt(); !ok {
+		if user.UpdateDefaultUpdatedAt == nil {
+			return fmt.Errorf("ent: uninitialized user.UpdateDefaultUpdatedAt (forgotten import ent/runtime?)")
+		}
 		v := user.UpdateDefaultUpdatedAt()
 		uu.mutation.SetUpdatedAt(v)
 	}
+	return nil
 }
 
 // check runs all checks and user-defined validators on the builder.
@@ -211,6 +305,9 @@ func (uu *UserUpdate) check() error {
 			return &ValidationError{Name: "email", err: fmt.Errorf(`ent: validator failed for field "User.email": %w`, err)}
 		}
 	}
+	if _, ok := uu.mutation.TenantID(); uu.mutation.TenantCleared() && !ok {
+		return errors.New(`ent: clearing a required unique edge "User.tenant"`)
+	}
 	return nil
 }
 
@@ -247,6 +344,30 @@ func (uu *UserUpdate) sqlSave(ctx context.Context) (n int, err error) {
 	if uu.mutation.AgeCleared() {
 		_spec.ClearField(user.FieldAge, field.TypeInt)
 	}
+	if value, ok := uu.mutation.Avatar(); ok {
+		_spec.SetField(user.FieldAvatar, field.TypeString, value)
+	}
+	if uu.mutation.AvatarCleared() {
+		_spec.ClearField(user.FieldAvatar, field.TypeString)
+	}
+	if value, ok := uu.mutation.VerifiedAt(); ok {
+		_spec.SetField(user.FieldVerifiedAt, field.TypeTime, value)
+	}
+	if uu.mutation.VerifiedAtCleared() {
+		_spec.ClearField(user.FieldVerifiedAt, field.TypeTime)
+	}
+	if value, ok := uu.mutation.Language(); ok {
+		_spec.SetField(user.FieldLanguage, field.TypeString, value)
+	}
+	if uu.mutation.LanguageCleared() {
+		_spec.ClearField(user.FieldLanguage, field.TypeString)
+	}
+	if value, ok := uu.mutation.NotifyCarAssigned(); ok {
+		_spec.SetField(user.FieldNotifyCarAssigned, field.TypeBool, value)
+	}
+	if value, ok := uu.mutation.NotifyGroupAdded(); ok {
+		_spec.SetField(user.FieldNotifyGroupAdded, field.TypeBool, value)
+	}
 	if uu.mutation.CarsCleared() {
 		edge := &sqlgraph.EdgeSpec{
 			Rel:     sqlgraph.O2M,
@@ -408,6 +529,94 @@ func (uuo *UserUpdateOne) ClearAge() *UserUpdateOne {
 	return uuo
 }
 
+// SetAvatar sets the "avatar" field.
+func (uuo *UserUpdateOne) SetAvatar(s string) *UserUpdateOne {
+	uuo.mutation.SetAvatar(s)
+	return uuo
+}
+
+// SetNillableAvatar sets the "avatar" field if the given value is not nil.
+func (uuo *UserUpdateOne) SetNillableAvatar(s *string) *UserUpdateOne {
+	if s != nil {
+		uuo.SetAvatar(*s)
+	}
+	return uuo
+}
+
+// ClearAvatar clears the value of the "avatar" field.
+func (uuo *UserUpdateOne) ClearAvatar() *UserUpdateOne {
+	uuo.mutation.ClearAvatar()
+	return uuo
+}
+
+// SetVerifiedAt sets the "verified_at" field.
+func (uuo *UserUpdateOne) SetVerifiedAt(t time.Time) *UserUpdateOne {
+	uuo.mutation.SetVerifiedAt(t)
+	return uuo
+}
+
+// SetNillableVerifiedAt sets the "verified_at" field if the given value is not nil.
+func (uuo *UserUpdateOne) SetNillableVerifiedAt(t *time.Time) *UserUpdateOne {
+	if t != nil {
+		uuo.SetVerifiedAt(*t)
+	}
+	return uuo
+}
+
+// ClearVerifiedAt clears the value of the "verified_at" field.
+func (uuo *UserUpdateOne) ClearVerifiedAt() *UserUpdateOne {
+	uuo.mutation.ClearVerifiedAt()
+	return uuo
+}
+
+// SetLanguage sets the "language" field.
+func (uuo *UserUpdateOne) SetLanguage(s string) *UserUpdateOne {
+	uuo.mutation.SetLanguage(s)
+	return uuo
+}
+
+// SetNillableLanguage sets the "language" field if the given value is not nil.
+func (uuo *UserUpdateOne) SetNillableLanguage(s *string) *UserUpdateOne {
+	if s != nil {
+		uuo.SetLanguage(*s)
+	}
+	return uuo
+}
+
+// ClearLanguage clears the value of the "language" field.
+func (uuo *UserUpdateOne) ClearLanguage() *UserUpdateOne {
+	uuo.mutation.ClearLanguage()
+	return uuo
+}
+
+// SetNotifyCarAssigned sets the "notify_car_assigned" field.
+func (uuo *UserUpdateOne) SetNotifyCarAssigned(b bool) *UserUpdateOne {
+	uuo.mutation.SetNotifyCarAssigned(b)
+	return uuo
+}
+
+// SetNillableNotifyCarAssigned sets the "notify_car_assigned" field if the given value is not nil.
+func (uuo *UserUpdateOne) SetNillableNotifyCarAssigned(b *bool) *UserUpdateOne {
+	if b != nil {
+		uuo.SetNotifyCarAssigned(*b)
+	}
+	return uuo
+}
+
+// SetNotifyGroupAdded sets the "notify_group_added" field.
+func (uuo *UserUpdateOne) SetNotifyGroupAdded(b bool) *UserUpdateOne {
+	uuo.mutation.SetNotifyGroupAdded(b)
+	return uuo
+}
+
+// SetNillableNotifyGroupAdded sets the "notify_group_added" field if the given value is not nil.
+func (uuo *UserUpdateOne) SetNillableNotifyGroupAdded(b *bool) *UserUpdateOne {
+	if b != nil {
+		uuo.SetNotifyGroupAdded(*b)
+	}
+	return uuo
+}
+
 // AddCarIDs adds the "cars" edge to the Car entity by IDs.
 func (uuo *UserUpdateOne) AddCarIDs(ids ...int) *UserUpdateOne {
 	uuo.mutation.AddCarIDs(ids...)
@@ -500,7 +709,9 @@ func (uuo *UserUpdateOne) Select(field string, fields ...string) *UserUpdateOne
 
 // Save executes the query and returns the updated User entity.
 func (uuo *UserUpdateOne) Save(ctx context.Context) (*User, error) {
-	uuo.defaults()
+	if err := uuo.defaults(); err != nil {
+		return nil, err
+	}
 	return withHooks[*User, UserMutation](ctx, uuo.sqlSave, uuo.mutation, uuo.hooks)
 }
 
@@ -527,11 +738,15 @@ func (uuo *UserUpdateOne) ExecX(ctx context.Context) {
 }
 
 // defaults sets the default values of the builder before save.
-func (uuo *UserUpdateOne) defaults() {
+func (uuo *UserUpdateOne) defaults() error {
 	if _, ok := uuo.mutation.UpdatedAt(); !ok {
+		if user.UpdateDefaultUpdatedAt == nil {
+			return fmt.Errorf("ent: uninitialized user.UpdateDefaultUpdatedAt (forgotten import ent/runtime?)")
+		}
 		v := user.UpdateDefaultUpdatedAt()
 		uuo.mutation.SetUpdatedAt(v)
 	}
+	return nil
 }
 
 // check runs all checks and user-defined validators on the builder.
@@ -551,6 +766,9 @@ func (uuo *UserUpdateOne) check() error {
 			return &ValidationError{Name: "email", err: fmt.Errorf(`ent: validator failed for field "User.email": %w`, err)}
 		}
 	}
+	if _, ok := uuo.mutation.TenantID(); uuo.mutation.TenantCleared() && !ok {
+		return errors.New(`ent: clearing a required unique edge "User.tenant"`)
+	}
 	return nil
 }
 
@@ -604,6 +822,30 @@ func (uuo *UserUpdateOne) sqlSave(ctx context.Context) (_node *User, err error)
 	if uuo.mutation.AgeCleared() {
 		_spec.ClearField(user.FieldAge, field.TypeInt)
 	}
+	if value, ok := uuo.mutation.Avatar(); ok {
+		_spec.SetField(user.FieldAvatar, field.TypeString, value)
+	}
+	if uuo.mutation.AvatarCleared() {
+		_spec.ClearField(user.FieldAvatar, field.TypeString)
+	}
+	if value, ok := uuo.mutation.VerifiedAt(); ok {
+		_spec.SetField(user.FieldVerifiedAt, field.TypeTime, value)
+	}
+	if uuo.mutation.VerifiedAtCleared() {
+		_spec.ClearField(user.FieldVerifiedAt, field.TypeTime)
+	}
+	if value, ok := uuo.mutation.Language(); ok {
+		_spec.SetField(user.FieldLanguage, field.TypeString, value)
+	}
+	if uuo.mutation.LanguageCleared() {
+		_spec.ClearField(user.FieldLanguage, field.TypeString)
+	}
+	if value, ok := uuo.mutation.NotifyCarAssigned(); ok {
+		_spec.SetField(user.FieldNotifyCarAssigned, field.TypeBool, value)
+	}
+	if value, ok := uuo.mutation.NotifyGroupAdded(); ok {
+		_spec.SetField(user.FieldNotifyGroupAdded, field.TypeBool, value)
+	}
 	if uuo.mutation.CarsCleared() {
 		edge := &sqlgraph.EdgeSpec{
 			Rel:     sqlgraph.O2M,
@@ -0,0 +1,664 @@
+// Code generated by ent, DO NOT EDIT.
+
+package ent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"entgo.io/ent/dialect/sql"
+	"entgo.io/ent/dialect/sql/sqlgraph"
+	"entgo.io/ent/dialect/sql/sqljson"
+	"entgo.io/ent/schema/field"
+	"github.com/jpdel518/go-ent/ent/predicate"
+	"github.com/jpdel518/go-ent/ent/webhookdelivery"
+	"github.com/jpdel518/go-ent/ent/webhooksubscription"
+)
+
+// WebhookSubscriptionUpdate is the builder for updating WebhookSubscription entities.
+type WebhookSubscriptionUpdate struct {
+	config
+	hooks    []Hook
+	mutation *WebhookSubscriptionMutation
+}
+
+// Where appends a list predicates to the WebhookSubscriptionUpdate builder.
+func (wsu *WebhookSubscriptionUpdate) Where(ps ...predicate.WebhookSubscription) *WebhookSubscriptionUpdate {
+	wsu.mutation.Where(ps...)
+	return wsu
+}
+
+// SetUpdatedAt sets the "updated_at" field.
+func (wsu *WebhookSubscriptionUpdate) SetUpdatedAt(t time.Time) *WebhookSubscriptionUpdate {
+	wsu.mutation.SetUpdatedAt(t)
+	return wsu
+}
+
+// SetURL sets the "url" field.
+func (wsu *WebhookSubscriptionUpdate) SetURL(s string) *WebhookSubscriptionUpdate {
+	wsu.mutation.SetURL(s)
+	return wsu
+}
+
+// SetSecret sets the "secret" field.
+func (wsu *WebhookSubscriptionUpdate) SetSecret(s string) *WebhookSubscriptionUpdate {
+	wsu.mutation.SetSecret(s)
+	return wsu
+}
+
+// SetEventTypes sets the "event_types" field.
+func (wsu *WebhookSubscriptionUpdate) SetEventTypes(s []string) *WebhookSubscriptionUpdate {
+	wsu.mutation.SetEventTypes(s)
+	return wsu
+}
+
+// AppendEventTypes appends s to the "event_types" field.
+func (wsu *WebhookSubscriptionUpdate) AppendEventTypes(s []string) *WebhookSubscriptionUpdate {
+	wsu.mutation.AppendEventTypes(s)
+	return wsu
+}
+
+// ClearEventTypes clears the value of the "event_types" field.
+func (wsu *WebhookSubscriptionUpdate) ClearEventTypes() *WebhookSubscriptionUpdate {
+	wsu.mutation.ClearEventTypes()
+	return wsu
+}
+
+// SetActive sets the "active" field.
+func (wsu *WebhookSubscriptionUpdate) SetActive(b bool) *WebhookSubscriptionUpdate {
+	wsu.mutation.SetActive(b)
+	return wsu
+}
+
+// SetNillableActive sets the "active" field if the given value is not nil.
+func (wsu *WebhookSubscriptionUpdate) SetNillableActive(b *bool) *WebhookSubscriptionUpdate {
+	if b != nil {
+		wsu.SetActive(*b)
+	}
+	return wsu
+}
+
+// SetFailureCount sets the "failure_count" field.
+func (wsu *WebhookSubscriptionUpdate) SetFailureCount(i int) *WebhookSubscriptionUpdate {
+	wsu.mutation.ResetFailureCount()
+	wsu.mutation.SetFailureCount(i)
+	return wsu
+}
+
+// SetNillableFailureCount sets the "failure_count" field if the given value is not nil.
+func (wsu *WebhookSubscriptionUpdate) SetNillableFailureCount(i *int) *WebhookSubscriptionUpdate {
+	if i != nil {
+		wsu.SetFailureCount(*i)
+	}
+	return wsu
+}
+
+// AddFailureCount adds i to the "failure_count" field.
+func (wsu *WebhookSubscriptionUpdate) AddFailureCount(i int) *WebhookSubscriptionUpdate {
+	wsu.mutation.AddFailureCount(i)
+	return wsu
+}
+
+// SetDisabledAt sets the "disabled_at" field.
+func (wsu *WebhookSubscriptionUpdate) SetDisabledAt(t time.Time) *WebhookSubscriptionUpdate {
+	wsu.mutation.SetDisabledAt(t)
+	return wsu
+}
+
+// SetNillableDisabledAt sets the "disabled_at" field if the given value is not nil.
+func (wsu *WebhookSubscriptionUpdate) SetNillableDisabledAt(t *time.Time) *WebhookSubscriptionUpdate {
+	if t != nil {
+		wsu.SetDisabledAt(*t)
+	}
+	return wsu
+}
+
+// ClearDisabledAt clears the value of the "disabled_at" field.
+func (wsu *WebhookSubscriptionUpdate) ClearDisabledAt() *WebhookSubscriptionUpdate {
+	wsu.mutation.ClearDisabledAt()
+	return wsu
+}
+
+// AddDeliveryIDs adds the "deliveries" edge to the WebhookDelivery entity by IDs.
+func (wsu *WebhookSubscriptionUpdate) AddDeliveryIDs(ids ...int) *WebhookSubscriptionUpdate {
+	wsu.mutation.AddDeliveryIDs(ids...)
+	return wsu
+}
+
+// AddDeliveries adds the "deliveries" edges to the WebhookDelivery entity.
+func (wsu *WebhookSubscriptionUpdate) AddDeliveries(w ...*WebhookDelivery) *WebhookSubscriptionUpdate {
+	ids := make([]int, len(w))
+	for i := range w {
+		ids[i] = w[i].ID
+	}
+	return wsu.AddDeliveryIDs(ids...)
+}
+
+// Mutation returns the WebhookSubscriptionMutation object of the builder.
+func (wsu *WebhookSubscriptionUpdate) Mutation() *WebhookSubscriptionMutation {
+	return wsu.mutation
+}
+
+// ClearDeliveries clears all "deliveries" edges to the WebhookDelivery entity.
+func (wsu *WebhookSubscriptionUpdate) ClearDeliveries() *WebhookSubscriptionUpdate {
+	wsu.mutation.ClearDeliveries()
+	return wsu
+}
+
+// RemoveDeliveryIDs removes the "deliveries" edge to WebhookDelivery entities by IDs.
+func (wsu *WebhookSubscriptionUpdate) RemoveDeliveryIDs(ids ...int) *WebhookSubscriptionUpdate {
+	wsu.mutation.RemoveDeliveryIDs(ids...)
+	return wsu
+}
+
+// RemoveDeliveries removes "deliveries" edges to WebhookDelivery entities.
+func (wsu *WebhookSubscriptionUpdate) RemoveDeliveries(w ...*WebhookDelivery) *WebhookSubscriptionUpdate {
+	ids := make([]int, len(w))
+	for i := range w {
+		ids[i] = w[i].ID
+	}
+	return wsu.RemoveDeliveryIDs(ids...)
+}
+
+// Save executes the query and returns the number of nodes affected by the update operation.
+func (wsu *WebhookSubscriptionUpdate) Save(ctx context.Context) (int, error) {
+	if err := wsu.defaults(); err != nil {
+		return 0, err
+	}
+	return withHooks[int, WebhookSubscriptionMutation](ctx, wsu.sqlSave, wsu.mutation, wsu.hooks)
+}
+
+// SaveX is like Save, but panics if an error occurs.
+func (wsu *WebhookSubscriptionUpdate) SaveX(ctx context.Context) int {
+	affected, err := wsu.Save(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return affected
+}
+
+// Exec executes the query.
+func (wsu *WebhookSubscriptionUpdate) Exec(ctx context.Context) error {
+	_, err := wsu.Save(ctx)
+	return err
+}
+
+// ExecX is like Exec, but panics if an error occurs.
+func (wsu *WebhookSubscriptionUpdate) ExecX(ctx context.Context) {
+	if err := wsu.Exec(ctx); err != nil {
+		panic(err)
+	}
+}
+
+// defaults sets the default values of the builder before save.
+func (wsu *WebhookSubscriptionUpdate) defaults() error {
+	if _, ok := wsu.mutation.UpdatedAt(); !ok {
+		if webhooksubscription.UpdateDefaultUpdatedAt == nil {
+			return fmt.Errorf("ent: uninitialized webhooksubscription.UpdateDefaultUpdatedAt (forgotten import ent/runtime?)")
+		}
+		v := webhooksubscription.UpdateDefaultUpdatedAt()
+		wsu.mutation.SetUpdatedAt(v)
+	}
+	return nil
+}
+
+// check runs all checks and user-defined validators on the builder.
+func (wsu *WebhookSubscriptionUpdate) check() error {
+	if v, ok := wsu.mutation.URL(); ok {
+		if err := webhooksubscription.URLValidator(v); err != nil {
+			return &ValidationError{Name: "url", err: fmt.Errorf(`ent: validator failed for field "WebhookSubscription.url": %w`, err)}
+		}
+	}
+	if v, ok := wsu.mutation.Secret(); ok {
+		if err := webhooksubscription.SecretValidator(v); err != nil {
+			return &ValidationError{Name: "secret", err: fmt.Errorf(`ent: validator failed for field "WebhookSubscription.secret": %w`, err)}
+		}
+	}
+	if _, ok := wsu.mutation.TenantID(); wsu.mutation.TenantCleared() && !ok {
+		return errors.New(`ent: clearing a required unique edge "WebhookSubscription.tenant"`)
+	}
+	return nil
+}
+
+func (wsu *WebhookSubscriptionUpdate) sqlSave(ctx context.Context) (n int, err error) {
+	if err := wsu.check(); err != nil {
+		return n, err
+	}
+	_spec := sqlgraph.NewUpdateSpec(webhooksubscription.Table, webhooksubscription.Columns, sqlgraph.NewFieldSpec(webhooksubscription.FieldID, field.TypeInt))
+	if ps := wsu.mutation.predicates; len(ps) > 0 {
+		_spec.Predicate = func(selector *sql.Selector) {
+			for i := range ps {
+				ps[i](selector)
+			}
+		}
+	}
+	if value, ok := wsu.mutation.UpdatedAt(); ok {
+		_spec.SetField(webhooksubscription.FieldUpdatedAt, field.TypeTime, value)
+	}
+	if value, ok := wsu.mutation.URL(); ok {
+		_spec.SetField(webhooksubscription.FieldURL, field.TypeString, value)
+	}
+	if value, ok := wsu.mutation.Secret(); ok {
+		_spec.SetField(webhooksubscription.FieldSecret, field.TypeString, value)
+	}
+	if value, ok := wsu.mutation.EventTypes(); ok {
+		_spec.SetField(webhooksubscription.FieldEventTypes, field.TypeJSON, value)
+	}
+	if value, ok := wsu.mutation.AppendedEventTypes(); ok {
+		_spec.AddModifier(func(u *sql.UpdateBuilder) {
+			sqljson.Append(u, webhooksubscription.FieldEventTypes, value)
+		})
+	}
+	if wsu.mutation.EventTypesCleared() {
+		_spec.ClearField(webhooksubscription.FieldEventTypes, field.TypeJSON)
+	}
+	if value, ok := wsu.mutation.Active(); ok {
+		_spec.SetField(webhooksubscription.FieldActive, field.TypeBool, value)
+	}
+	if value, ok := wsu.mutation.FailureCount(); ok {
+		_spec.SetField(webhooksubscription.FieldFailureCount, field.TypeInt, value)
+	}
+	if value, ok := wsu.mutation.AddedFailureCount(); ok {
+		_spec.AddField(webhooksubscription.FieldFailureCount, field.TypeInt, value)
+	}
+	if value, ok := wsu.mutation.DisabledAt(); ok {
+		_spec.SetField(webhooksubscription.FieldDisabledAt, field.TypeTime, value)
+	}
+	if wsu.mutation.DisabledAtCleared() {
+		_spec.ClearField(webhooksubscription.FieldDisabledAt, field.TypeTime)
+	}
+	if wsu.mutation.DeliveriesCleared() {
+		edge := &sqlgraph.EdgeSpec{
+			Rel:     sqlgraph.O2M,
+			Inverse: false,
+			Table:   webhooksubscription.DeliveriesTable,
+			Columns: []string{webhooksubscription.DeliveriesColumn},
+			Bidi:    false,
+			Target: &sqlgraph.EdgeTarget{
+				IDSpec: sqlgraph.NewFieldSpec(webhookdelivery.FieldID, field.TypeInt),
+			},
+		}
+		_spec.Edges.Clear = append(_spec.Edges.Clear, edge)
+	}
+	if nodes := wsu.mutation.RemovedDeliveriesIDs(); len(nodes) > 0 && !wsu.mutation.DeliveriesCleared() {
+		edge := &sqlgraph.EdgeSpec{
+			Rel:     sqlgraph.O2M,
+			Inverse: false,
+			Table:   webhooksubscription.DeliveriesTable,
+			Columns: []string{webhooksubscription.DeliveriesColumn},
+			Bidi:    false,
+			Target: &sqlgraph.EdgeTarget{
+				IDSpec: sqlgraph.NewFieldSpec(webhookdelivery.FieldID, field.TypeInt),
+			},
+		}
+		for _, k := range nodes {
+			edge.Target.Nodes = append(edge.Target.Nodes, k)
+		}
+		_spec.Edges.Clear = append(_spec.Edges.Clear, edge)
+	}
+	if nodes := wsu.mutation.DeliveriesIDs(); len(nodes) > 0 {
+		edge := &sqlgraph.EdgeSpec{
+			Rel:     sqlgraph.O2M,
+			Inverse: false,
+			Table:   webhooksubscription.DeliveriesTable,
+			Columns: []string{webhooksubscription.DeliveriesColumn},
+			Bidi:    false,
+			Target: &sqlgraph.EdgeTarget{
+				IDSpec: sqlgraph.NewFieldSpec(webhookdelivery.FieldID, field.TypeInt),
+			},
+		}
+		for _, k := range nodes {
+			edge.Target.Nodes = append(edge.Target.Nodes, k)
+		}
+		_spec.Edges.Add = append(_spec.Edges.Add, edge)
+	}
+	if n, err = sqlgraph.UpdateNodes(ctx, wsu.driver, _spec); err != nil {
+		if _, ok := err.(*sqlgraph.NotFoundError); ok {
+			err = &NotFoundError{webhooksubscription.Label}
+		} else if sqlgraph.IsConstraintError(err) {
+			err = &ConstraintError{msg: err.Error(), wrap: err}
+		}
+		return 0, err
+	}
+	wsu.mutation.done = true
+	return n, nil
+}
+
+// WebhookSubscriptionUpdateOne is the builder for updating a single WebhookSubscription entity.
+type WebhookSubscriptionUpdateOne struct {
+	config
+	fields   []string
+	hooks    []Hook
+	mutation *WebhookSubscriptionMutation
+}
+
+// SetUpdatedAt sets the "updated_at" field.
+func (wsuo *WebhookSubscriptionUpdateOne) SetUpdatedAt(t time.Time) *WebhookSubscriptionUpdateOne {
+	wsuo.mutation.SetUpdatedAt(t)
+	return wsuo
+}
+
+// SetURL sets the "url" field.
+func (wsuo *WebhookSubscriptionUpdateOne) SetURL(s string) *WebhookSubscriptionUpdateOne {
+	wsuo.mutation.SetURL(s)
+	return wsuo
+}
+
+// SetSecret sets the "secret" field.
+func (wsuo *WebhookSubscriptionUpdateOne) SetSecret(s string) *WebhookSubscriptionUpdateOne {
+	wsuo.mutation.SetSecret(s)
+	return wsuo
+}
+
+// SetEventTypes sets the "event_types" field.
+func (wsuo *WebhookSubscriptionUpdateOne) SetEventTypes(s []string) *WebhookSubscriptionUpdateOne {
+	wsuo.mutation.SetEventTypes(s)
+	return wsuo
+}
+
+// AppendEventTypes appends s to the "event_types" field.
+func (wsuo *WebhookSubscriptionUpdateOne) AppendEventTypes(s []string) *WebhookSubscriptionUpdateOne {
+	wsuo.mutation.AppendEventTypes(s)
+	return wsuo
+}
+
+// ClearEventTypes clears the value of the "event_types" field.
+func (wsuo *WebhookSubscriptionUpdateOne) ClearEventTypes() *WebhookSubscriptionUpdateOne {
+	wsuo.mutation.ClearEventTypes()
+	return wsuo
+}
+
+// SetActive sets the "active" field.
+func (wsuo *WebhookSubscriptionUpdateOne) SetActive(b bool) *WebhookSubscriptionUpdateOne {
+	wsuo.mutation.SetActive(b)
+	return wsuo
+}
+
+// SetNillableActive sets the "active" field if the given value is not nil.
+func (wsuo *WebhookSubscriptionUpdateOne) SetNillableActive(b *bool) *WebhookSubscriptionUpdateOne {
+	if b != nil {
+		wsuo.SetActive(*b)
+	}
+	return wsuo
+}
+
+// SetFailureCount sets the "failure_count" field.
+func (wsuo *WebhookSubscriptionUpdateOne) SetFailureCount(i int) *WebhookSubscriptionUpdateOne {
+	wsuo.mutation.ResetFailureCount()
+	wsuo.mutation.SetFailureCount(i)
+	return wsuo
+}
+
+// SetNillableFailureCount sets the "failure_count" field if the given value is not nil.
+func (wsuo *WebhookSubscriptionUpdateOne) SetNillableFailureCount(i *int) *WebhookSubscriptionUpdateOne {
+	if i != nil {
+		wsuo.SetFailureCount(*i)
+	}
+	return wsuo
+}
+
+// AddFailureCount adds i to the "failure_count" field.
+func (wsuo *WebhookSubscriptionUpdateOne) AddFailureCount(i int) *WebhookSubscriptionUpdateOne {
+	wsuo.mutation.AddFailureCount(i)
+	return wsuo
+}
+
+// SetDisabledAt sets the "disabled_at" field.
+func (wsuo *WebhookSubscriptionUpdateOne) SetDisabledAt(t time.Time) *WebhookSubscriptionUpdateOne {
+	wsuo.mutation.SetDisabledAt(t)
+	return wsuo
+}
+
+// SetNillableDisabledAt sets the "disabled_at" field if the given value is not nil.
+func (wsuo *WebhookSubscriptionUpdateOne) SetNillableDisabledAt(t *time.Time) *WebhookSubscriptionUpdateOne {
+	if t != nil {
+		wsuo.SetDisabledAt(*t)
+	}
+	return wsuo
+}
+
+// ClearDisabledAt clears the value of the "disabled_at" field.
+func (wsuo *WebhookSubscriptionUpdateOne) ClearDisabledAt() *WebhookSubscriptionUpdateOne {
+	wsuo.mutation.ClearDisabledAt()
+	return wsuo
+}
+
+// AddDeliveryIDs adds the "deliveries" edge to the WebhookDelivery entity by IDs.
+func (wsuo *WebhookSubscriptionUpdateOne) AddDeliveryIDs(ids ...int) *WebhookSubscriptionUpdateOne {
+	wsuo.mutation.AddDeliveryIDs(ids...)
+	return wsuo
+}
+
+// AddDeliveries adds the "deliveries" edges to the WebhookDelivery entity.
+func (wsuo *WebhookSubscriptionUpdateOne) AddDeliveries(w ...*WebhookDelivery) *WebhookSubscriptionUpdateOne {
+	ids := make([]int, len(w))
+	for i := range w {
+		ids[i] = w[i].ID
+	}
+	return wsuo.AddDeliveryIDs(ids...)
+}
+
+// Mutation returns the WebhookSubscriptionMutation object of the builder.
+func (wsuo *WebhookSubscriptionUpdateOne) Mutation() *WebhookSubscriptionMutation {
+	return wsuo.mutation
+}
+
+// ClearDeliveries clears all "deliveries" edges to the WebhookDelivery entity.
+func (wsuo *WebhookSubscriptionUpdateOne) ClearDeliveries() *WebhookSubscriptionUpdateOne {
+	wsuo.mutation.ClearDeliveries()
+	return wsuo
+}
+
+// RemoveDeliveryIDs removes the "deliveries" edge to WebhookDelivery entities by IDs.
+func (wsuo *WebhookSubscriptionUpdateOne) RemoveDeliveryIDs(ids ...int) *WebhookSubscriptionUpdateOne {
+	wsuo.mutation.RemoveDeliveryIDs(ids...)
+	return wsuo
+}
+
+// RemoveDeliveries removes "deliveries" edges to WebhookDelivery entities.
+func (wsuo *WebhookSubscriptionUpdateOne) RemoveDeliveries(w ...*WebhookDelivery) *WebhookSubscriptionUpdateOne {
+	ids := make([]int, len(w))
+	for i := range w {
+		ids[i] = w[i].ID
+	}
+	return wsuo.RemoveDeliveryIDs(ids...)
+}
+
+// Where appends a list predicates to the WebhookSubscriptionUpdate builder.
+func (wsuo *WebhookSubscriptionUpdateOne) Where(ps ...predicate.WebhookSubscription) *WebhookSubscriptionUpdateOne {
+	wsuo.mutation.Where(ps...)
+	return wsuo
+}
+
+// Select allows selecting one or more fields (columns) of the returned entity.
+// The default is selecting all fields defined in the entity schema.
+func (wsuo *WebhookSubscriptionUpdateOne) Select(field string, fields ...string) *WebhookSubscriptionUpdateOne {
+	wsuo.fields = append([]string{field}, fields...)
+	return wsuo
+}
+
+// Save executes the query and returns the updated WebhookSubscription entity.
+func (wsuo *WebhookSubscriptionUpdateOne) Save(ctx context.Context) (*WebhookSubscription, error) {
+	if err := wsuo.defaults(); err != nil {
+		return nil, err
+	}
+	return withHooks[*WebhookSubscription, WebhookSubscriptionMutation](ctx, wsuo.sqlSave, wsuo.mutation, wsuo.hooks)
+}
+
+// SaveX is like Save, but panics if an error occurs.
+func (wsuo *WebhookSubscriptionUpdateOne) SaveX(ctx context.Context) *WebhookSubscription {
+	node, err := wsuo.Save(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return node
+}
+
+// Exec executes the query on the entity.
+func (wsuo *WebhookSubscriptionUpdateOne) Exec(ctx context.Context) error {
+	_, err := wsuo.Save(ctx)
+	return err
+}
+
+// ExecX is like Exec, but panics if an error occurs.
+func (wsuo *WebhookSubscriptionUpdateOne) ExecX(ctx context.Context) {
+	if err := wsuo.Exec(ctx); err != nil {
+		panic(err)
+	}
+}
+
+// defaults sets the default values of the builder before save.
+func (wsuo *WebhookSubscriptionUpdateOne) defaults() error {
+	if _, ok := wsuo.mutation.UpdatedAt(); !ok {
+		if webhooksubscription.UpdateDefaultUpdatedAt == nil {
+			return fmt.Errorf("ent: uninitialized webhooksubscription.UpdateDefaultUpdatedAt (forgotten import ent/runtime?)")
+		}
+		v := webhooksubscription.UpdateDefaultUpdatedAt()
+		wsuo.mutation.SetUpdatedAt(v)
+	}
+	return nil
+}
+
+// check runs all checks and user-defined validators on the builder.
+func (wsuo *WebhookSubscriptionUpdateOne) check() error {
+	if v, ok := wsuo.mutation.URL(); ok {
+		if err := webhooksubscription.URLValidator(v); err != nil {
+			return &ValidationError{Name: "url", err: fmt.Errorf(`ent: validator failed for field "WebhookSubscription.url": %w`, err)}
+		}
+	}
+	if v, ok := wsuo.mutation.Secret(); ok {
+		if err := webhooksubscription.SecretValidator(v); err != nil {
+			return &ValidationError{Name: "secret", err: fmt.Errorf(`ent: validator failed for field "WebhookSubscription.secret": %w`, err)}
+		}
+	}
+	if _, ok := wsuo.mutation.TenantID(); wsuo.mutation.TenantCleared() && !ok {
+		return errors.New(`ent: clearing a required unique edge "WebhookSubscription.tenant"`)
+	}
+	return nil
+}
+
+func (wsuo *WebhookSubscriptionUpdateOne) sqlSave(ctx context.Context) (_node *WebhookSubscription, err error) {
+	if err := wsuo.check(); err != nil {
+		return _node, err
+	}
+	_spec := sqlgraph.NewUpdateSpec(webhooksubscription.Table, webhooksubscription.Columns, sqlgraph.NewFieldSpec(webhooksubscription.FieldID, field.TypeInt))
+	id, ok := wsuo.mutation.ID()
+	if !ok {
+		return nil, &ValidationError{Name: "id", err: errors.New(`ent: missing "WebhookSubscription.id" for update`)}
+	}
+	_spec.Node.ID.Value = id
+	if fields := wsuo.fields; len(fields) > 0 {
+		_spec.Node.Columns = make([]string, 0, len(fields))
+		_spec.Node.Columns = append(_spec.Node.Columns, webhooksubscription.FieldID)
+		for _, f := range fields {
+			if !webhooksubscription.ValidColumn(f) {
+				return nil, &ValidationError{Name: f, err: fmt.Errorf("ent: invalid field %q for query", f)}
+			}
+			if f != webhooksubscription.FieldID {
+				_spec.Node.Columns = append(_spec.Node.Columns, f)
+			}
+		}
+	}
+	if ps := wsuo.mutation.predicates; len(ps) > 0 {
+		_spec.Predicate = func(selector *sql.Selector) {
+			for i := range ps {
+				ps[i](selector)
+			}
+		}
+	}
+	if value, ok := wsuo.mutation.UpdatedAt(); ok {
+		_spec.SetField(webhooksubscription.FieldUpdatedAt, field.TypeTime, value)
+	}
+	if value, ok := wsuo.mutation.URL(); ok {
+		_spec.SetField(webhooksubscription.FieldURL, field.TypeString, value)
+	}
+	if value, ok := wsuo.mutation.Secret(); ok {
+		_spec.SetField(webhooksubscription.FieldSecret, field.TypeString, value)
+	}
+	if value, ok := wsuo.mutation.EventTypes(); ok {
+		_spec.SetField(webhooksubscription.FieldEventTypes, field.TypeJSON, value)
+	}
+	if value, ok := wsuo.mutation.AppendedEventTypes(); ok {
+		_spec.AddModifier(func(u *sql.UpdateBuilder) {
+			sqljson.Append(u, webhooksubscription.FieldEventTypes, value)
+		})
+	}
+	if wsuo.mutation.EventTypesCleared() {
+		_spec.ClearField(webhooksubscription.FieldEventTypes, field.TypeJSON)
+	}
+	if value, ok := wsuo.mutation.Active(); ok {
+		_spec.SetField(webhooksubscription.FieldActive, field.TypeBool, value)
+	}
+	if value, ok := wsuo.mutation.FailureCount(); ok {
+		_spec.SetField(webhooksubscription.FieldFailureCount, field.TypeInt, value)
+	}
+	if value, ok := wsuo.mutation.AddedFailureCount(); ok {
+		_spec.AddField(webhooksubscription.FieldFailureCount, field.TypeInt, value)
+	}
+	if value, ok := wsuo.mutation.DisabledAt(); ok {
+		_spec.SetField(webhooksubscription.FieldDisabledAt, field.TypeTime, value)
+	}
+	if wsuo.mutation.DisabledAtCleared() {
+		_spec.ClearField(webhooksubscription.FieldDisabledAt, field.TypeTime)
+	}
+	if wsuo.mutation.DeliveriesCleared() {
+		edge := &sqlgraph.EdgeSpec{
+			Rel:     sqlgraph.O2M,
+			Inverse: false,
+			Table:   webhooksubscription.DeliveriesTable,
+			Columns: []string{webhooksubscription.DeliveriesColumn},
+			Bidi:    false,
+			Target: &sqlgraph.EdgeTarget{
+				IDSpec: sqlgraph.NewFieldSpec(webhookdelivery.FieldID, field.TypeInt),
+			},
+		}
+		_spec.Edges.Clear = append(_spec.Edges.Clear, edge)
+	}
+	if nodes := wsuo.mutation.RemovedDeliveriesIDs(); len(nodes) > 0 && !wsuo.mutation.DeliveriesCleared() {
+		edge := &sqlgraph.EdgeSpec{
+			Rel:     sqlgraph.O2M,
+			Inverse: false,
+			Table:   webhooksubscription.DeliveriesTable,
+			Columns: []string{webhooksubscription.DeliveriesColumn},
+			Bidi:    false,
+			Target: &sqlgraph.EdgeTarget{
+				IDSpec: sqlgraph.NewFieldSpec(webhookdelivery.FieldID, field.TypeInt),
+			},
+		}
+		for _, k := range nodes {
+			edge.Target.Nodes = append(edge.Target.Nodes, k)
+		}
+		_spec.Edges.Clear = append(_spec.Edges.Clear, edge)
+	}
+	if nodes := wsuo.mutation.DeliveriesIDs(); len(nodes) > 0 {
+		edge := &sqlgraph.EdgeSpec{
+			Rel:     sqlgraph.O2M,
+			Inverse: false,
+			Table:   webhooksubscription.DeliveriesTable,
+			Columns: []string{webhooksubscription.DeliveriesColumn},
+			Bidi:    false,
+			Target: &sqlgraph.EdgeTarget{
+				IDSpec: sqlgraph.NewFieldSpec(webhookdelivery.FieldID, field.TypeInt),
+			},
+		}
+		for _, k := range nodes {
+			edge.Target.Nodes = append(edge.Target.Nodes, k)
+		}
+		_spec.Edges.Add = append(_spec.Edges.Add, edge)
+	}
+	_node = &WebhookSubscription{config: wsuo.config}
+	_spec.Assign = _node.assignValues
+	_spec.ScanValues = _node.scanValues
+	if err = sqlgraph.UpdateNode(ctx, wsuo.driver, _spec); err != nil {
+		if _, ok := err.(*sqlgraph.NotFoundError); ok {
+			err = &NotFoundError{webhooksubscription.Label}
+		} else if sqlgraph.IsConstraintError(err) {
+			err = &ConstraintError{msg: err.Error(), wrap: err}
+		}
+		return nil, err
+	}
+	wsuo.mutation.done = true
+	return _node, nil
+}
@@ -0,0 +1,247 @@
+package graph
+
+import (
+	"context"
+	"entgo.io/ent/dialect"
+	entsql "entgo.io/ent/dialect/sql"
+	"fmt"
+	"github.com/99designs/gqlgen/client"
+	"github.com/99designs/gqlgen/graphql/handler"
+	"github.com/jpdel518/go-ent/ent"
+	_ "github.com/jpdel518/go-ent/ent/runtime"
+	"github.com/jpdel518/go-ent/infrastructure/memory"
+	"github.com/jpdel518/go-ent/infrastructure/rdb"
+	"github.com/jpdel518/go-ent/infrastructure/rdb/seed"
+	"github.com/jpdel518/go-ent/tenant"
+	"github.com/jpdel518/go-ent/usecase"
+	_ "github.com/mattn/go-sqlite3"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// databases names a new in-memory database for each test
+var databases atomic.Uint64
+
+// countingDriver counts the queries sent to the database
+type countingDriver struct {
+	dialect.Driver
+	queries atomic.Int64
+}
+
+func (d *countingDriver) Query(ctx context.Context, query string, args, v any) error {
+	d.queries.Add(1)
+	return d.Driver.Query(ctx, query, args, v)
+}
+
+// graphServer serves the schema to the requests of the default tenant
+type graphServer struct {
+	ent    *ent.Client
+	driver *countingDriver
+	client *client.Client
+	// ctx belongs to the default tenant
+	ctx context.Context
+}
+
+func newGraphServer(t *testing.T) *graphServer {
+	t.Helper()
+	drv, err := entsql.Open(dialect.SQLite, fmt.Sprintf("file:graph%d?mode=memory&cache=shared&_fk=1", databases.Add(1)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	driver := &countingDriver{Driver: drv}
+	c := ent.NewClient(ent.Driver(driver))
+	t.Cleanup(func() {
+		_ = c.Close()
+	})
+	if err := c.Schema.Create(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	id, ok := seed.TenantSeed(c)
+	if !ok {
+		t.Fatal("failed seeding the default tenant")
+	}
+
+	verification := usecase.VerificationConfig{Secret: "graph-secret", TTL: time.Hour, URL: "http://localhost:8080/user/verify"}
+	userUsecase := usecase.NewUserUsecase(rdb.NewUserRepository(c), rdb.NewCarRepository(c), memory.NewUserFileRepository(memory.NewStore()), verification, 5*time.Second)
+	srv := handler.NewDefaultServer(NewSchema(c, userUsecase))
+	scoped := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		srv.ServeHTTP(w, r.WithContext(tenant.NewContext(r.Context(), id)))
+	})
+	return &graphServer{ent: c, driver: driver, client: client.New(scoped), ctx: tenant.NewContext(context.Background(), id)}
+}
+
+// seedUser registers a user with two cars and a group
+func (s *graphServer) seedUser(t *testing.T, i int) {
+	t.Helper()
+	registeredAt := time.Date(2020, 4, 1, 9, 0, 0, 0, time.UTC)
+	cars, err := s.ent.Car.CreateBulk(
+		s.ent.Car.Create().SetName(fmt.Sprintf("Leaf%d", i)).SetModel("Nissan").SetRegisteredAt(registeredAt),
+		s.ent.Car.Create().SetName(fmt.Sprintf("Prius%d", i)).SetModel("Toyota").SetRegisteredAt(registeredAt),
+	).Save(s.ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	u, err := s.ent.User.Create().
+		SetFirstName("Taro").
+		SetLastName("Yamada").
+		SetEmail(fmt.Sprintf("taro%d@example.com", i)).
+		SetAge(20).
+		AddCars(cars...).
+		Save(s.ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := s.ent.Group.Create().SetName("group").AddUsers(u).Exec(s.ctx); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// createResponse is the response of createUser and updateUser
+type createResponse struct {
+	User struct {
+		ID        string
+		FirstName string
+		Email     string
+		Cars      []struct{ Name string }
+	}
+}
+
+const createUser = `mutation($input: CreateUserInput!) {
+  user: createUser(input: $input) { id firstName email cars { name } }
+}`
+
+const updateUser = `mutation($id: ID!, $input: UpdateUserInput!) {
+  user: updateUser(id: $id, input: $input) { id firstName email cars { name } }
+}`
+
+func TestMutations(t *testing.T) {
+	s := newGraphServer(t)
+	s.seedUser(t, 1)
+
+	var created createResponse
+	s.client.MustPost(createUser, &created, client.Var("input", map[string]any{
+		"firstName": "Hanako", "lastName": "Sato", "email": "Hanako@Example.com", "age": 30, "carIDs": []string{"2"},
+	}))
+	// the usecase normalizes the e-mail and moves the car
+	if created.User.ID != "2" || created.User.Email != "hanako@example.com" || len(created.User.Cars) != 1 || created.User.Cars[0].Name != "Prius1" {
+		t.Errorf("createUser = %+v, want hanako with the Prius", created.User)
+	}
+
+	// the cars are kept when not given
+	var updated createResponse
+	s.client.MustPost(updateUser, &updated, client.Var("id", "2"), client.Var("input", map[string]any{
+		"firstName": "Hanako", "lastName": "Suzuki", "email": "hanako@example.com",
+	}))
+	if len(updated.User.Cars) != 1 || updated.User.Cars[0].Name != "Prius1" {
+		t.Errorf("updateUser without the cars = %+v, want the Prius kept", updated.User)
+	}
+	s.client.MustPost(updateUser, &updated, client.Var("id", "2"), client.Var("input", map[string]any{
+		"firstName": "Hanako", "lastName": "Suzuki", "email": "hanako@example.com", "carIDs": []string{},
+	}))
+	if len(updated.User.Cars) != 0 {
+		t.Errorf("updateUser with no cars = %+v, want the cars released", updated.User)
+	}
+
+	var deleted struct{ DeleteUser string }
+	s.client.MustPost(`mutation { deleteUser(id: 2) }`, &deleted)
+	if deleted.DeleteUser != "2" {
+		t.Errorf("deleteUser = %s, want 2", deleted.DeleteUser)
+	}
+	if n := s.ent.User.Query().CountX(s.ctx); n != 1 {
+		t.Errorf("users = %d, want 1 after deleting", n)
+	}
+}
+
+func TestMutationErrors(t *testing.T) {
+	s := newGraphServer(t)
+	s.seedUser(t, 1)
+
+	valid := map[string]any{"firstName": "Jiro", "lastName": "Yamada", "email": "jiro@example.com"}
+	with := func(key string, value any) map[string]any {
+		input := map[string]any{}
+		for k, v := range valid {
+			input[k] = v
+		}
+		input[key] = value
+		return input
+	}
+	tests := []struct {
+		name    string
+		query   string
+		options []client.Option
+		want    string
+	}{
+		{name: "invalid e-mail", query: createUser, options: []client.Option{client.Var("input", with("email", "not-an-email"))}, want: "email: must be a valid email address"},
+		{name: "blank name", query: createUser, options: []client.Option{client.Var("input", with("firstName", ""))}, want: "first_name: cannot be blank"},
+		{name: "duplicate e-mail", query: createUser, options: []client.Option{client.Var("input", with("email", "TARO1@example.com"))}, want: "constraint failed"},
+		{name: "unknown car", query: createUser, options: []client.Option{client.Var("input", with("carIDs", []string{"99"}))}, want: "car 99 does not exist"},
+		{name: "invalid update", query: updateUser, options: []client.Option{client.Var("id", "1"), client.Var("input", with("email", "not-an-email"))}, want: "email: must be a valid email address"},
+		{name: "unknown user", query: updateUser, options: []client.Option{client.Var("id", "99"), client.Var("input", with("carIDs", []string{}))}, want: "not found"},
+		{name: "delete unknown user", query: `mutation { deleteUser(id: 99) }`, want: "not found"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var res map[string]any
+			err := s.client.Post(tt.query, &res, tt.options...)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("error = %v, want %q", err, tt.want)
+			}
+		})
+	}
+	// nothing is written by the failed mutations
+	if n := s.ent.User.Query().CountX(s.ctx); n != 1 {
+		t.Errorf("users = %d, want 1", n)
+	}
+}
+
+func TestQueryBatchesEdges(t *testing.T) {
+	const query = `{
+  users(first: 100) {
+    totalCount
+    edges { node { id cars { name owner { id } } group { name users { id } } } }
+  }
+}`
+	// the number of the queries does not grow with the number of the users
+	queries := map[int]int64{}
+	for _, n := range []int{2, 6} {
+		s := newGraphServer(t)
+		for i := 0; i < n; i++ {
+			s.seedUser(t, i)
+		}
+		var res struct {
+			Users struct {
+				TotalCount int
+				Edges      []struct {
+					Node struct {
+						ID   string
+						Cars []struct {
+							Name  string
+							Owner struct{ ID string }
+						}
+						Group []struct {
+							Name  string
+							Users []struct{ ID string }
+						}
+					}
+				}
+			}
+		}
+		s.driver.queries.Store(0)
+		s.client.MustPost(query, &res)
+		queries[n] = s.driver.queries.Load()
+
+		if res.Users.TotalCount != n || len(res.Users.Edges) != n || len(res.Users.Edges[0].Node.Cars) != 2 {
+			t.Fatalf("users = %+v, want %d users with 2 cars", res.Users, n)
+		}
+	}
+	if queries[2] != queries[6] {
+		t.Errorf("queries = %d for 2 users and %d for 6, want the same", queries[2], queries[6])
+	}
+	// the count, the users and one query for each level of the edges
+	if queries[6] > 6 {
+		t.Errorf("queries = %d, want one for each level", queries[6])
+	}
+}
@@ -0,0 +1,20 @@
+// Code generated by github.com/99designs/gqlgen, DO NOT EDIT.
+
+package graph
+
+type CreateUserInput struct {
+	FirstName string `json:"firstName"`
+	LastName  string `json:"lastName"`
+	Email     string `json:"email"`
+	Age       *int   `json:"age,omitempty"`
+	CarIDs    []int  `json:"carIDs,omitempty"`
+}
+
+type UpdateUserInput struct {
+	FirstName string `json:"firstName"`
+	LastName  string `json:"lastName"`
+	Email     string `json:"email"`
+	Age       *int   `json:"age,omitempty"`
+	// The cars are replaced when given
+	CarIDs []int `json:"carIDs,omitempty"`
+}
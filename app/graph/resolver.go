@@ -0,0 +1,41 @@
+package graph
+
+import (
+	"context"
+	"errors"
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/jpdel518/go-ent/ent"
+	"github.com/jpdel518/go-ent/ent/user"
+	"github.com/jpdel518/go-ent/usecase"
+)
+
+//go:generate go run github.com/99designs/gqlgen
+
+// errNodeLookup is returned by node and nodes. IDs are unique per type only, so the type of an ID cannot be told
+var errNodeLookup = errors.New("node lookup is not supported, query users, cars or groups instead")
+
+// Resolver is the root resolver. Queries read through the ent client and mutations go through the usecases.
+// The edges selected in a query are loaded together with their parents (see ent/gql_collection.go),
+// so nested edges are fetched with one query per level instead of one per node.
+type Resolver struct {
+	client      *ent.Client
+	userUsecase usecase.UserUsecase
+}
+
+// NewSchema creates the executable schema
+func NewSchema(client *ent.Client, userUsecase usecase.UserUsecase) graphql.ExecutableSchema {
+	return NewExecutableSchema(Config{
+		Resolvers: &Resolver{client: client, userUsecase: userUsecase},
+	})
+}
+
+// user reads the user with the edges selected in the request
+func (r *Resolver) user(ctx context.Context, id int) (*ent.User, error) {
+	query, err := r.client.User.Query().
+		Where(user.ID(id)).
+		CollectFields(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return query.Only(ctx)
+}
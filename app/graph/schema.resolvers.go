@@ -0,0 +1,76 @@
+package graph
+
+// This file will be automatically regenerated based on the schema, any resolver implementations
+// will be copied through when generating and any unknown code will be moved to the end.
+// Code generated by github.com/99designs/gqlgen version v0.17.27
+
+import (
+	"context"
+
+	"github.com/jpdel518/go-ent/domain/model"
+	"github.com/jpdel518/go-ent/ent"
+	"github.com/jpdel518/go-ent/ent/car"
+	"github.com/jpdel518/go-ent/ent/user"
+)
+
+// CreateUser is the resolver for the createUser field.
+func (r *mutationResolver) CreateUser(ctx context.Context, input CreateUserInput) (*ent.User, error) {
+	u := &model.User{
+		FirstName: input.FirstName,
+		LastName:  input.LastName,
+		Email:     input.Email,
+		CarIDs:    input.CarIDs,
+	}
+	if input.Age != nil {
+		u.Age = *input.Age
+	}
+	if err := u.Validate(); err != nil {
+		return nil, err
+	}
+	if err := r.userUsecase.Create(ctx, u, nil, nil); err != nil {
+		return nil, err
+	}
+	return r.user(ctx, u.ID)
+}
+
+// UpdateUser is the resolver for the updateUser field.
+func (r *mutationResolver) UpdateUser(ctx context.Context, id int, input UpdateUserInput) (*ent.User, error) {
+	u := &model.User{
+		ID:        id,
+		FirstName: input.FirstName,
+		LastName:  input.LastName,
+		Email:     input.Email,
+		CarIDs:    input.CarIDs,
+	}
+	if input.Age != nil {
+		u.Age = *input.Age
+	}
+	// the usecase replaces the cars, so keep the current ones when they are not given
+	if u.CarIDs == nil {
+		carIDs, err := r.client.Car.Query().Where(car.HasOwnerWith(user.ID(id))).IDs(ctx)
+		if err != nil {
+			return nil, err
+		}
+		u.CarIDs = carIDs
+	}
+	if err := u.Validate(); err != nil {
+		return nil, err
+	}
+	if err := r.userUsecase.Update(ctx, u, nil, nil); err != nil {
+		return nil, err
+	}
+	return r.user(ctx, id)
+}
+
+// DeleteUser is the resolver for the deleteUser field.
+func (r *mutationResolver) DeleteUser(ctx context.Context, id int) (int, error) {
+	if err := r.userUsecase.Delete(ctx, id); err != nil {
+		return 0, err
+	}
+	return id, nil
+}
+
+// Mutation returns MutationResolver implementation.
+func (r *Resolver) Mutation() MutationResolver { return &mutationResolver{r} }
+
+type mutationResolver struct{ *Resolver }
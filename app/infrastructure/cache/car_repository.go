@@ -0,0 +1,37 @@
+package cache
+
+import (
+	"context"
+	"github.com/jpdel518/go-ent/domain/model"
+	"github.com/jpdel518/go-ent/domain/repository"
+	"github.com/jpdel518/go-ent/tenant"
+	"time"
+)
+
+// carRepository caches the cars by id. The other methods go to the decorated repository
+type carRepository struct {
+	repository.CarRepository
+	cache *ReadThrough
+	ttl   time.Duration
+}
+
+// NewCarRepository decorates the repository with the cache, whose entries are invalidated by rdb.CacheHook
+func NewCarRepository(next repository.CarRepository, c *ReadThrough, ttl time.Duration) repository.CarRepository {
+	return &carRepository{CarRepository: next, cache: c, ttl: ttl}
+}
+
+func (r *carRepository) GetByID(ctx context.Context, id int) (*model.Car, error) {
+	// the entities are cached by tenant, so that one is not read through another tenant
+	tenantID, ok := tenant.FromContext(ctx)
+	if !ok {
+		return r.CarRepository.GetByID(ctx, id)
+	}
+	var c model.Car
+	err := r.cache.Load(ctx, CarKey(tenantID, id), r.ttl, &c, func(ctx context.Context) (interface{}, error) {
+		return r.CarRepository.GetByID(ctx, id)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
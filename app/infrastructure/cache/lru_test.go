@@ -0,0 +1,67 @@
+package cache
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestLRUCache(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2026, 10, 19, 9, 0, 0, 0, time.UTC)
+	c := NewLRUCache(2).(*lruCache)
+	c.now = func() time.Time {
+		return now
+	}
+
+	get := func(key string) string {
+		t.Helper()
+		b, ok, err := c.Get(ctx, key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !ok {
+			return ""
+		}
+		return string(b)
+	}
+
+	_ = c.Set(ctx, "a", []byte("1"), time.Minute)
+	_ = c.Set(ctx, "b", []byte("2"), time.Minute)
+	if get("a") != "1" || get("b") != "2" {
+		t.Fatal("entries are not kept")
+	}
+
+	// the least recently used entry is dropped when it is full
+	get("a")
+	_ = c.Set(ctx, "c", []byte("3"), time.Minute)
+	if get("b") != "" {
+		t.Error("least recently used entry is kept")
+	}
+	if get("a") != "1" || get("c") != "3" {
+		t.Error("recently used entries are dropped")
+	}
+
+	// setting a key again replaces the value and the expiry
+	_ = c.Set(ctx, "a", []byte("4"), 2*time.Minute)
+	if get("a") != "4" {
+		t.Error("entry is not replaced")
+	}
+
+	// the entries expire after the ttl
+	now = now.Add(time.Minute)
+	if get("c") != "" {
+		t.Error("expired entry is returned")
+	}
+	if get("a") != "4" {
+		t.Error("entry expires before the ttl")
+	}
+	if _, ok := c.items["c"]; ok {
+		t.Error("expired entry is kept")
+	}
+
+	_ = c.Delete(ctx, "a", "unknown")
+	if get("a") != "" || c.order.Len() != 0 {
+		t.Error("deleted entry is kept")
+	}
+}
@@ -0,0 +1,108 @@
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/jpdel518/go-ent/domain/repository"
+	"github.com/jpdel518/go-ent/metrics"
+	"golang.org/x/exp/slog"
+	"golang.org/x/sync/singleflight"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// UserKey is the key of the cached user of the tenant
+func UserKey(tenantID int, id int) string {
+	return "user:" + strconv.Itoa(tenantID) + ":" + strconv.Itoa(id)
+}
+
+// CarKey is the key of the cached car of the tenant
+func CarKey(tenantID int, id int) string {
+	return "car:" + strconv.Itoa(tenantID) + ":" + strconv.Itoa(id)
+}
+
+// ReadThrough loads the values missing in the cache and stores them as JSON.
+// The concurrent misses of a key are collapsed into one load.
+// A value loaded while any entry was invalidated is not stored, as it may have been read before the write.
+// The cache failures are logged and the values are loaded instead.
+type ReadThrough struct {
+	cache repository.Cache
+	group singleflight.Group
+	// generation counts the invalidations
+	generation atomic.Uint64
+	// timeout bounds a load, which is shared by the callers and outlives the one who started it
+	timeout time.Duration
+}
+
+func NewReadThrough(c repository.Cache, timeout time.Duration) *ReadThrough {
+	return &ReadThrough{cache: c, timeout: timeout}
+}
+
+// Load decodes the cached value of the key into v, or the value returned by load which is cached for ttl
+func (r *ReadThrough) Load(ctx context.Context, key string, ttl time.Duration, v interface{}, load func(ctx context.Context) (interface{}, error)) error {
+	entity, _, _ := strings.Cut(key, ":")
+	b, ok, err := r.cache.Get(ctx, key)
+	if err != nil {
+		metrics.ObserveCacheLookup(entity, "error")
+		slog.WarnCtx(ctx, "failed reading cache", "key", key, "err", err)
+	} else if ok {
+		if err := json.Unmarshal(b, v); err == nil {
+			metrics.ObserveCacheLookup(entity, "hit")
+			return nil
+		}
+	} else {
+		metrics.ObserveCacheLookup(entity, "miss")
+	}
+
+	loaded, err, _ := r.group.Do(key, func() (interface{}, error) {
+		// the other callers wait for the load, so it is not canceled with the request of the first one
+		ctx, cancel := context.WithTimeout(detached{ctx}, r.timeout)
+		defer cancel()
+		generation := r.generation.Load()
+		value, err := load(ctx)
+		if err != nil {
+			return nil, err
+		}
+		b, err := json.Marshal(value)
+		if err != nil {
+			return nil, err
+		}
+		if r.generation.Load() == generation {
+			if err := r.cache.Set(ctx, key, b, ttl); err != nil {
+				slog.WarnCtx(ctx, "failed writing cache", "key", key, "err", err)
+			}
+		}
+		return b, nil
+	})
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(loaded.([]byte), v)
+}
+
+// Invalidate deletes the entries of the keys
+func (r *ReadThrough) Invalidate(ctx context.Context, keys ...string) {
+	r.generation.Add(1)
+	if err := r.cache.Delete(ctx, keys...); err != nil {
+		slog.ErrorCtx(ctx, "failed invalidating cache", "keys", keys, "err", err)
+	}
+}
+
+// detached keeps the values of the context, such as the tenant and the span, without its deadline and cancellation
+type detached struct {
+	context.Context
+}
+
+func (detached) Deadline() (time.Time, bool) {
+	return time.Time{}, false
+}
+
+func (detached) Done() <-chan struct{} {
+	return nil
+}
+
+func (detached) Err() error {
+	return nil
+}
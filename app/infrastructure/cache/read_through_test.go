@@ -0,0 +1,144 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+type value struct {
+	N int `json:"n"`
+}
+
+func TestReadThrough(t *testing.T) {
+	ctx := context.Background()
+	r := NewReadThrough(NewLRUCache(10), time.Second)
+	var loads atomic.Int64
+	load := func(n int) func(ctx context.Context) (interface{}, error) {
+		return func(ctx context.Context) (interface{}, error) {
+			loads.Add(1)
+			return &value{N: n}, nil
+		}
+	}
+
+	// the value is loaded once and read from the cache afterwards
+	var v value
+	for i := 0; i < 2; i++ {
+		if err := r.Load(ctx, "user:1:1", time.Minute, &v, load(1)); err != nil || v.N != 1 {
+			t.Fatalf("Load = %+v, %v", v, err)
+		}
+	}
+	if n := loads.Load(); n != 1 {
+		t.Errorf("loaded %d times, want 1", n)
+	}
+
+	// the invalidated value is loaded again
+	r.Invalidate(ctx, "user:1:1")
+	if err := r.Load(ctx, "user:1:1", time.Minute, &v, load(2)); err != nil || v.N != 2 {
+		t.Errorf("Load after Invalidate = %+v, %v, want 2", v, err)
+	}
+
+	// the errors are not cached
+	failed := errors.New("failed")
+	err := r.Load(ctx, "user:1:2", time.Minute, &v, func(ctx context.Context) (interface{}, error) {
+		return nil, failed
+	})
+	if !errors.Is(err, failed) {
+		t.Errorf("Load = %v, want the error of the load", err)
+	}
+	if err := r.Load(ctx, "user:1:2", time.Minute, &v, load(3)); err != nil || v.N != 3 {
+		t.Errorf("Load after the error = %+v, %v, want 3", v, err)
+	}
+
+	// a value loaded while an entry was invalidated is not cached, as it may have been read before the write
+	err = r.Load(ctx, "user:1:3", time.Minute, &v, func(ctx context.Context) (interface{}, error) {
+		r.Invalidate(ctx, "user:1:3")
+		return &value{N: 4}, nil
+	})
+	if err != nil || v.N != 4 {
+		t.Errorf("Load = %+v, %v, want 4", v, err)
+	}
+	if err := r.Load(ctx, "user:1:3", time.Minute, &v, load(5)); err != nil || v.N != 5 {
+		t.Errorf("Load after the invalidated load = %+v, %v, want 5", v, err)
+	}
+}
+
+func TestReadThroughSingleflight(t *testing.T) {
+	r := NewReadThrough(NewLRUCache(10), time.Second)
+	started := make(chan struct{})
+	release := make(chan struct{})
+	var loads atomic.Int64
+	load := func(ctx context.Context) (interface{}, error) {
+		loads.Add(1)
+		close(started)
+		select {
+		case <-release:
+			return &value{N: 1}, nil
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+
+	// the first caller gives up while the others wait for the load
+	first, cancel := context.WithCancel(context.Background())
+	firstDone := make(chan error, 1)
+	go func() {
+		var v value
+		firstDone <- r.Load(first, "car:1:1", time.Minute, &v, load)
+	}()
+	<-started
+
+	const waiters = 5
+	var wg sync.WaitGroup
+	results := make(chan value, waiters)
+	errs := make(chan error, waiters)
+	for i := 0; i < waiters; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			var v value
+			if err := r.Load(context.Background(), "car:1:1", time.Minute, &v, load); err != nil {
+				errs <- err
+				return
+			}
+			results <- v
+		}()
+	}
+	cancel()
+	// the waiters join the load before it ends
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+	wg.Wait()
+	close(errs)
+	close(results)
+
+	for err := range errs {
+		t.Errorf("Load of a waiter = %v, the load is canceled with the first caller", err)
+	}
+	for v := range results {
+		if v.N != 1 {
+			t.Errorf("Load of a waiter = %+v, want 1", v)
+		}
+	}
+	if err := <-firstDone; err != nil {
+		t.Errorf("Load of the first caller = %v", err)
+	}
+	if n := loads.Load(); n != 1 {
+		t.Errorf("loaded %d times, want 1", n)
+	}
+}
+
+func TestReadThroughTimeout(t *testing.T) {
+	r := NewReadThrough(NewLRUCache(10), 10*time.Millisecond)
+	var v value
+	err := r.Load(context.Background(), "car:1:1", time.Minute, &v, func(ctx context.Context) (interface{}, error) {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	})
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Load = %v, want the load bounded by the timeout", err)
+	}
+}
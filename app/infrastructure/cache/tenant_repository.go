@@ -0,0 +1,79 @@
+package cache
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"github.com/jpdel518/go-ent/domain/model"
+	"github.com/jpdel518/go-ent/domain/repository"
+	"time"
+)
+
+// tenantRepository caches the tenants by slug and by API key, which every request looks up.
+// The tenants are not changed once created, so the entries are left to expire.
+// The unknown slugs and keys are cached as well, so that the requests with made-up ones do not each reach the database.
+// Creating a tenant invalidates them
+type tenantRepository struct {
+	repository.TenantRepository
+	cache *ReadThrough
+	ttl   time.Duration
+}
+
+// NewTenantRepository decorates the repository with the cache
+func NewTenantRepository(next repository.TenantRepository, c *ReadThrough, ttl time.Duration) repository.TenantRepository {
+	return &tenantRepository{TenantRepository: next, cache: c, ttl: ttl}
+}
+
+func (r *tenantRepository) GetBySlug(ctx context.Context, slug string) (*model.Tenant, error) {
+	return r.load(ctx, slugKey(slug), func(ctx context.Context) (*model.Tenant, error) {
+		return r.TenantRepository.GetBySlug(ctx, slug)
+	})
+}
+
+func (r *tenantRepository) GetByAPIKey(ctx context.Context, apiKey string) (*model.Tenant, error) {
+	return r.load(ctx, apiKeyKey(apiKey), func(ctx context.Context) (*model.Tenant, error) {
+		return r.TenantRepository.GetByAPIKey(ctx, apiKey)
+	})
+}
+
+func (r *tenantRepository) Create(ctx context.Context, t *model.Tenant, apiKey string) error {
+	if err := r.TenantRepository.Create(ctx, t, apiKey); err != nil {
+		return err
+	}
+	keys := []string{slugKey(t.Slug)}
+	if apiKey != "" {
+		keys = append(keys, apiKeyKey(apiKey))
+	}
+	r.cache.Invalidate(ctx, keys...)
+	return nil
+}
+
+// load reads the tenant through the cache. A tenant which is not found is cached as the zero tenant
+func (r *tenantRepository) load(ctx context.Context, key string, find func(ctx context.Context) (*model.Tenant, error)) (*model.Tenant, error) {
+	var t model.Tenant
+	err := r.cache.Load(ctx, key, r.ttl, &t, func(ctx context.Context) (interface{}, error) {
+		t, err := find(ctx)
+		if errors.Is(err, repository.ErrNotFound) {
+			return &model.Tenant{}, nil
+		}
+		return t, err
+	})
+	if err != nil {
+		return nil, err
+	}
+	if t.ID == 0 {
+		return nil, repository.ErrNotFound
+	}
+	return &t, nil
+}
+
+func slugKey(slug string) string {
+	return "tenant:slug:" + slug
+}
+
+// apiKeyKey is the key of the API key, which itself is not kept in the cache
+func apiKeyKey(apiKey string) string {
+	sum := sha256.Sum256([]byte(apiKey))
+	return "tenant:key:" + hex.EncodeToString(sum[:])
+}
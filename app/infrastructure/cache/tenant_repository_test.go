@@ -0,0 +1,60 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"github.com/jpdel518/go-ent/domain/model"
+	"github.com/jpdel518/go-ent/domain/repository"
+	"testing"
+	"time"
+)
+
+// countingTenants finds the tenants of the API keys and counts the lookups
+type countingTenants struct {
+	repository.TenantRepository
+	keys    map[string]*model.Tenant
+	lookups int
+}
+
+func (r *countingTenants) GetByAPIKey(ctx context.Context, key string) (*model.Tenant, error) {
+	r.lookups++
+	if t, ok := r.keys[key]; ok {
+		return t, nil
+	}
+	return nil, repository.ErrNotFound
+}
+
+func (r *countingTenants) Create(ctx context.Context, t *model.Tenant, apiKey string) error {
+	t.ID = len(r.keys) + 1
+	r.keys[apiKey] = t
+	return nil
+}
+
+func TestTenantRepositoryCachesUnknownKeys(t *testing.T) {
+	ctx := context.Background()
+	next := &countingTenants{keys: map[string]*model.Tenant{}}
+	r := NewTenantRepository(next, NewReadThrough(NewLRUCache(10), time.Second), time.Minute)
+
+	// the unknown key is looked up once
+	for i := 0; i < 3; i++ {
+		if _, err := r.GetByAPIKey(ctx, "made-up-key"); !errors.Is(err, repository.ErrNotFound) {
+			t.Fatalf("GetByAPIKey = %v, want ErrNotFound", err)
+		}
+	}
+	if next.lookups != 1 {
+		t.Errorf("looked up %d times, want 1", next.lookups)
+	}
+
+	// creating the tenant of the key forgets that it was unknown
+	if err := r.Create(ctx, &model.Tenant{Name: "Acme", Slug: "acme"}, "made-up-key"); err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 2; i++ {
+		if got, err := r.GetByAPIKey(ctx, "made-up-key"); err != nil || got.Slug != "acme" {
+			t.Fatalf("GetByAPIKey = %+v, %v, want the tenant created", got, err)
+		}
+	}
+	if next.lookups != 2 {
+		t.Errorf("looked up %d times, want 2", next.lookups)
+	}
+}
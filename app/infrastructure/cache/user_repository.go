@@ -0,0 +1,37 @@
+package cache
+
+import (
+	"context"
+	"github.com/jpdel518/go-ent/domain/model"
+	"github.com/jpdel518/go-ent/domain/repository"
+	"github.com/jpdel518/go-ent/tenant"
+	"time"
+)
+
+// userRepository caches the users by id. The other methods go to the decorated repository
+type userRepository struct {
+	repository.UserRepository
+	cache *ReadThrough
+	ttl   time.Duration
+}
+
+// NewUserRepository decorates the repository with the cache, whose entries are invalidated by rdb.CacheHook
+func NewUserRepository(next repository.UserRepository, c *ReadThrough, ttl time.Duration) repository.UserRepository {
+	return &userRepository{UserRepository: next, cache: c, ttl: ttl}
+}
+
+func (r *userRepository) GetByID(ctx context.Context, id int) (*model.User, error) {
+	// the entities are cached by tenant, so that one is not read through another tenant
+	tenantID, ok := tenant.FromContext(ctx)
+	if !ok {
+		return r.UserRepository.GetByID(ctx, id)
+	}
+	var u model.User
+	err := r.cache.Load(ctx, UserKey(tenantID, id), r.ttl, &u, func(ctx context.Context) (interface{}, error) {
+		return r.UserRepository.GetByID(ctx, id)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &u, nil
+}
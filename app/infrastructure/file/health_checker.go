@@ -0,0 +1,24 @@
+package file
+
+import (
+	"context"
+	"github.com/jpdel518/go-ent/domain/repository"
+	"github.com/jpdel518/go-ent/infrastructure/file/s3"
+)
+
+type storageChecker struct {
+	session *s3.S3
+}
+
+// NewStorageChecker checks that the bucket of the file repositories is reachable
+func NewStorageChecker(session *s3.S3) repository.HealthChecker {
+	return &storageChecker{session: session}
+}
+
+func (c *storageChecker) Name() string {
+	return "storage"
+}
+
+func (c *storageChecker) Check(ctx context.Context) error {
+	return c.session.Ping(ctx)
+}
@@ -0,0 +1,44 @@
+package file
+
+import (
+	"context"
+	"github.com/jpdel518/go-ent/domain/repository"
+	"github.com/jpdel518/go-ent/infrastructure/file/s3"
+	"github.com/jpdel518/go-ent/tenant"
+	"io"
+)
+
+type jobFileRepository struct {
+	session *s3.S3
+}
+
+func NewJobFileRepository(session *s3.S3) repository.JobFileRepository {
+	return &jobFileRepository{
+		session: session,
+	}
+}
+
+// jobFileKey is the key of the file of the tenant, such as tenant/1/job/source/...
+func jobFileKey(ctx context.Context, key string) (string, error) {
+	tenantID, ok := tenant.FromContext(ctx)
+	if !ok {
+		return "", tenant.ErrMissing
+	}
+	return tenant.Prefix(tenantID) + "job/" + key, nil
+}
+
+func (jr jobFileRepository) Upload(ctx context.Context, key string, r io.Reader) (string, error) {
+	key, err := jobFileKey(ctx, key)
+	if err != nil {
+		return "", err
+	}
+	return jr.session.Upload(ctx, key, r)
+}
+
+func (jr jobFileRepository) Download(ctx context.Context, key string) (io.ReadCloser, error) {
+	key, err := jobFileKey(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	return jr.session.Download(ctx, key)
+}
@@ -0,0 +1,42 @@
+package s3
+
+import (
+	"github.com/jpdel518/go-ent/metrics"
+	"io"
+	"time"
+)
+
+const (
+	opUpload   = "upload"
+	opDownload = "download"
+	opDelete   = "delete"
+	opList     = "list"
+)
+
+// observe records the latency of an operation which began at start
+func observe(operation string, start time.Time, err error) {
+	metrics.ObserveStorageOperation(operation, err, time.Since(start))
+}
+
+// countingReader counts the bytes read through it
+type countingReader struct {
+	io.Reader
+	n int64
+}
+
+func (r *countingReader) Read(p []byte) (int, error) {
+	n, err := r.Reader.Read(p)
+	r.n += int64(n)
+	return n, err
+}
+
+// downloadBody counts the bytes of a downloaded object as they are read
+type downloadBody struct {
+	io.ReadCloser
+}
+
+func (b downloadBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	metrics.AddStorageBytes(opDownload, int64(n))
+	return n, err
+}
@@ -2,13 +2,15 @@ package s3
 
 import (
 	"bytes"
-	"fmt"
+	"context"
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/credentials"
 	"github.com/aws/aws-sdk-go/aws/session"
 	"github.com/aws/aws-sdk-go/service/s3"
 	"github.com/aws/aws-sdk-go/service/s3/s3manager"
-	"log"
+	"github.com/jpdel518/go-ent/metrics"
+	"golang.org/x/exp/slog"
+	"io"
 	"mime/multipart"
 	"os"
 	"sync"
@@ -25,31 +27,71 @@ type partUploadResult struct {
 	err           error
 }
 
-func NewS3Session() *S3 {
-	creds := credentials.NewStaticCredentials(os.Getenv("AWS_ACCESS_KEY"), os.Getenv("AWS_SECRET_ACCESS_KEY"), "")
-	s := session.Must(session.NewSession(&aws.Config{
-		Credentials: creds,
-		Region:      aws.String(os.Getenv("AWS_REGION")),
-	}))
-	bucket := os.Getenv("AWS_BUCKET_NAME")
+// Config of the S3 bucket
+type Config struct {
+	Region string `yaml:"region" env:"AWS_REGION" required:"true"`
+	Bucket string `yaml:"bucket" env:"AWS_BUCKET_NAME" required:"true"`
+	// AccessKey and SecretAccessKey are the static credentials. The default credential chain is used when they are empty
+	AccessKey       string `yaml:"access_key" env:"AWS_ACCESS_KEY" secret:"true"`
+	SecretAccessKey string `yaml:"secret_access_key" env:"AWS_SECRET_ACCESS_KEY" secret:"true"`
+	// Endpoint replaces the AWS endpoint with an S3 compatible storage such as MinIO
+	Endpoint string `yaml:"endpoint" env:"AWS_ENDPOINT"`
+}
+
+func NewS3Session(c Config) *S3 {
+	config := &aws.Config{
+		Region: aws.String(c.Region),
+	}
+	if c.AccessKey != "" || c.SecretAccessKey != "" {
+		config.Credentials = credentials.NewStaticCredentials(c.AccessKey, c.SecretAccessKey, "")
+	}
+	if c.Endpoint != "" {
+		config.Endpoint = aws.String(c.Endpoint)
+		config.S3ForcePathStyle = aws.Bool(true)
+	}
+	s := session.Must(session.NewSession(config))
+	traceHandlers(&s.Handlers)
 	return &S3{
 		s3session:      s,
-		baseBucketName: bucket,
+		baseBucketName: c.Bucket,
 	}
 }
 
+// Close closes the idle connections to S3
+func (s *S3) Close() error {
+	if c := s.s3session.Config.HTTPClient; c != nil {
+		c.CloseIdleConnections()
+	}
+	return nil
+}
+
+// Ping checks that the bucket exists and is accessible
+func (s *S3) Ping(ctx context.Context) error {
+	_, err := s3.New(s.s3session).HeadBucketWithContext(ctx, &s3.HeadBucketInput{
+		Bucket: aws.String(s.baseBucketName),
+	})
+	return err
+}
+
 // UploadFile ファイルをS3にアップロードする
-func (s *S3) UploadFile(folder string, key string, file *os.File) error {
-	_, err := s3.New(s.s3session).PutObject(&s3.PutObjectInput{
+func (s *S3) UploadFile(ctx context.Context, folder string, key string, file *os.File) error {
+	start := time.Now()
+	_, err := s3.New(s.s3session).PutObjectWithContext(ctx, &s3.PutObjectInput{
 		Bucket: aws.String(s.baseBucketName),
 		Key:    aws.String(folder + "/" + key),
 		Body:   file,
 	})
+	observe(opUpload, start, err)
+	if err == nil {
+		if info, err := file.Stat(); err == nil {
+			metrics.AddStorageBytes(opUpload, info.Size())
+		}
+	}
 	return err
 }
 
 // UploadMultipartFile multipart requestで受け取ったファイルをS3にアップロードする
-func (s *S3) UploadMultipartFile(folder string, fh *multipart.FileHeader, file multipart.File) (string, error) {
+func (s *S3) UploadMultipartFile(ctx context.Context, folder string, fh *multipart.FileHeader, file multipart.File) (string, error) {
 	// create a new uploader
 	uploader := s3manager.NewUploader(s.s3session, func(u *s3manager.Uploader) {
 		u.PartSize = 5 * 1024 * 1024 // 5MB
@@ -63,17 +105,59 @@ func (s *S3) UploadMultipartFile(folder string, fh *multipart.FileHeader, file m
 		Body:   file,
 	}
 	// Upload the file to S3.
-	upload, err := uploader.Upload(input)
+	start := time.Now()
+	upload, err := uploader.UploadWithContext(ctx, input)
+	observe(opUpload, start, err)
 	if err != nil {
-		log.Printf("failed to upload file, %v", err)
+		slog.ErrorCtx(ctx, "failed uploading file", "key", folder+"/"+fh.Filename, "err", err)
 		return "", err
 	}
+	metrics.AddStorageBytes(opUpload, fh.Size)
 
 	return upload.Location, nil
 }
 
+// Upload readerの内容をkeyにアップロードする
+func (s *S3) Upload(ctx context.Context, key string, body io.Reader) (string, error) {
+	uploader := s3manager.NewUploader(s.s3session, func(u *s3manager.Uploader) {
+		u.PartSize = 5 * 1024 * 1024 // 5MB
+		u.Concurrency = 5
+	})
+	r := &countingReader{Reader: body}
+	start := time.Now()
+	upload, err := uploader.UploadWithContext(ctx, &s3manager.UploadInput{
+		Bucket: aws.String(s.baseBucketName),
+		Key:    aws.String(key),
+		Body:   r,
+	})
+	observe(opUpload, start, err)
+	if err != nil {
+		slog.ErrorCtx(ctx, "failed uploading file", "key", key, "err", err)
+		return "", err
+	}
+	metrics.AddStorageBytes(opUpload, r.n)
+
+	return upload.Location, nil
+}
+
+// Download keyのファイルを取得する。読み終わったらCloseすること
+func (s *S3) Download(ctx context.Context, key string) (io.ReadCloser, error) {
+	start := time.Now()
+	object, err := s3.New(s.s3session).GetObjectWithContext(ctx, &s3.GetObjectInput{
+		Bucket: aws.String(s.baseBucketName),
+		Key:    aws.String(key),
+	})
+	// the latency is until the response headers as the body is read by the caller
+	observe(opDownload, start, err)
+	if err != nil {
+		slog.ErrorCtx(ctx, "failed downloading file", "key", key, "err", err)
+		return nil, err
+	}
+	return downloadBody{object.Body}, nil
+}
+
 // UploadBigFile 大容量ファイルをS3にアップロードする
-func (s *S3) UploadBigFile(folder string, fh *multipart.FileHeader, file multipart.File) (*string, error) {
+func (s *S3) UploadBigFile(ctx context.Context, folder string, fh *multipart.FileHeader, file multipart.File) (*string, error) {
 	// create a new uploader
 	uploader := s3manager.NewUploader(s.s3session, func(u *s3manager.Uploader) {
 		u.PartSize = 5 * 1024 * 1024 // 5MB
@@ -85,8 +169,9 @@ func (s *S3) UploadBigFile(folder string, fh *multipart.FileHeader, file multipa
 	expiryDate := time.Now().AddDate(0, 0, 30)
 	// multipart uploadの準備
 	// multipart uploadを紐付けるためのUploadIDを取得
+	began := time.Now()
 	s3client := s3.New(s.s3session)
-	createdResp, err := s3client.CreateMultipartUpload(&s3.CreateMultipartUploadInput{
+	createdResp, err := s3client.CreateMultipartUploadWithContext(ctx, &s3.CreateMultipartUploadInput{
 		Bucket:  aws.String(s.baseBucketName),
 		Key:     aws.String(folder + "/" + fh.Filename),
 		Expires: &expiryDate,
@@ -116,7 +201,7 @@ func (s *S3) UploadBigFile(folder string, fh *multipart.FileHeader, file multipa
 				panic(err)
 			}
 			// バッファからS3にmultipart upload
-			part, err := s3client.UploadPart(&s3.UploadPartInput{
+			part, err := s3client.UploadPartWithContext(ctx, &s3.UploadPartInput{
 				Body:       bytes.NewReader(buffer),
 				Bucket:     createdResp.Bucket,
 				Key:        createdResp.Key,
@@ -136,7 +221,7 @@ func (s *S3) UploadBigFile(folder string, fh *multipart.FileHeader, file multipa
 		}(partNum, start, currentSize)
 
 		remaining -= currentSize
-		fmt.Printf("Uplaodind of part %v started and remaning is %v \n", partNum, remaining)
+		slog.DebugCtx(ctx, "uploading part started", "part", partNum, "remaining", remaining)
 		partNum++
 	}
 
@@ -150,7 +235,7 @@ func (s *S3) UploadBigFile(folder string, fh *multipart.FileHeader, file multipa
 		// エラーが発生した場合はアップロードを中断
 		if partUploadResult.err != nil {
 			// エラーが発生する前までにアップロードした分割データを削除
-			_, err = s3client.AbortMultipartUpload(&s3.AbortMultipartUploadInput{
+			_, err = s3client.AbortMultipartUploadWithContext(ctx, &s3.AbortMultipartUploadInput{
 				Bucket:   createdResp.Bucket,
 				Key:      createdResp.Key,
 				UploadId: createdResp.UploadId,
@@ -159,18 +244,18 @@ func (s *S3) UploadBigFile(folder string, fh *multipart.FileHeader, file multipa
 			close(ch)
 			remaining = 0
 			if err != nil {
-				log.Printf("Failed to abort multipart upload, %v", err)
+				slog.ErrorCtx(ctx, "failed aborting multipart upload", "err", err)
 				return nil, err
 			}
 		}
 		// 成功した場合はアップロードが完了した分割データの情報をcompletedPartsに追加
-		log.Printf("Uploading completed part %v", *partUploadResult.completedPart.PartNumber)
+		slog.DebugCtx(ctx, "uploading part completed", "part", *partUploadResult.completedPart.PartNumber)
 		completedParts = append(completedParts, partUploadResult.completedPart)
 	}
 
 	// 全ての分割データのアップロードが完了したことをS3に通知
 	wg.Wait()
-	resp, err := s3client.CompleteMultipartUpload(&s3.CompleteMultipartUploadInput{
+	resp, err := s3client.CompleteMultipartUploadWithContext(ctx, &s3.CompleteMultipartUploadInput{
 		Bucket:   createdResp.Bucket,
 		Key:      createdResp.Key,
 		UploadId: createdResp.UploadId,
@@ -178,36 +263,44 @@ func (s *S3) UploadBigFile(folder string, fh *multipart.FileHeader, file multipa
 			Parts: completedParts,
 		},
 	})
+	observe(opUpload, began, err)
+	if err == nil {
+		metrics.AddStorageBytes(opUpload, fh.Size)
+	}
 
 	return resp.Location, err
 }
 
 // DeleteFile ファイルを削除する
-func (s *S3) DeleteFile(folder string, key string) error {
-	_, err := s3.New(s.s3session).DeleteObject(&s3.DeleteObjectInput{
+func (s *S3) DeleteFile(ctx context.Context, folder string, key string) error {
+	start := time.Now()
+	_, err := s3.New(s.s3session).DeleteObjectWithContext(ctx, &s3.DeleteObjectInput{
 		Bucket: aws.String(s.baseBucketName),
 		Key:    aws.String(folder + "/" + key),
 	})
+	observe(opDelete, start, err)
 	if err != nil {
-		log.Printf("failed to delete file, %v", err)
+		slog.ErrorCtx(ctx, "failed deleting file", "key", folder+"/"+key, "err", err)
 	}
 	return err
 }
 
 // GetFileURL バケット内にあるファイルのURLを取得する
-func (s *S3) GetFileURL(folder string) string {
-	objects, err := s3.New(s.s3session).ListObjects(&s3.ListObjectsInput{
+func (s *S3) GetFileURL(ctx context.Context, folder string) string {
+	start := time.Now()
+	objects, err := s3.New(s.s3session).ListObjectsWithContext(ctx, &s3.ListObjectsInput{
 		Bucket: aws.String(s.baseBucketName),
 		Prefix: aws.String(folder),
 	})
+	observe(opList, start, err)
 	if err != nil {
-		log.Printf("failed to get file url, %v", err)
+		slog.ErrorCtx(ctx, "failed listing files", "prefix", folder, "err", err)
 		return ""
 	}
 	// フォルダだけ（サイズ0）が取得される場合もある
 	var filename string
 	for _, object := range objects.Contents {
-		log.Printf("object key: %v", *object.Key)
+		slog.DebugCtx(ctx, "object found", "key", *object.Key)
 		if *object.Size > 0 {
 			filename = *object.Key
 			break
@@ -0,0 +1,49 @@
+package s3
+
+import (
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.17.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+var tracer = otel.Tracer("github.com/jpdel518/go-ent/infrastructure/file/s3")
+
+// traceHandlers adds the SDK handlers which start a span when a request is validated, that is before it is built,
+// and end it when the request completes after the retries. Each part of a multipart upload gets its own span.
+func traceHandlers(h *request.Handlers) {
+	h.Validate.PushFrontNamed(request.NamedHandler{Name: "goent.tracing.start", Fn: startSpan})
+	h.Complete.PushBackNamed(request.NamedHandler{Name: "goent.tracing.end", Fn: endSpan})
+}
+
+func startSpan(r *request.Request) {
+	ctx, _ := tracer.Start(r.Context(), r.ClientInfo.ServiceID+"."+r.Operation.Name,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			semconv.RPCSystemKey.String("aws-api"),
+			semconv.RPCService(r.ClientInfo.ServiceID),
+			semconv.RPCMethod(r.Operation.Name),
+			semconv.CloudRegion(aws.StringValue(r.Config.Region)),
+		),
+	)
+	r.SetContext(ctx)
+}
+
+func endSpan(r *request.Request) {
+	span := trace.SpanFromContext(r.Context())
+	if r.HTTPResponse != nil {
+		span.SetAttributes(semconv.HTTPStatusCode(r.HTTPResponse.StatusCode))
+	}
+	span.SetAttributes(attribute.Int("aws.retry_count", r.RetryCount))
+	if r.RequestID != "" {
+		span.SetAttributes(attribute.String("aws.request_id", r.RequestID))
+	}
+	if r.Error != nil {
+		span.RecordError(r.Error)
+		span.SetStatus(codes.Error, r.Error.Error())
+	}
+	span.End()
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"github.com/jpdel518/go-ent/domain/repository"
 	"github.com/jpdel518/go-ent/infrastructure/file/s3"
+	"github.com/jpdel518/go-ent/tenant"
 	"mime/multipart"
 	"strconv"
 	"strings"
@@ -19,20 +20,41 @@ func NewUserFileRepository(session *s3.S3) repository.UserFileRepository {
 	}
 }
 
+// avatarKey is the folder of the avatar of the user of the tenant, such as tenant/1/user/avatar/2
+func avatarKey(ctx context.Context, id int) (string, error) {
+	tenantID, ok := tenant.FromContext(ctx)
+	if !ok {
+		return "", tenant.ErrMissing
+	}
+	return tenant.Prefix(tenantID) + "user/avatar/" + strconv.Itoa(id), nil
+}
+
 func (ur userFileRepository) GetURLByUser(ctx context.Context, id int) string {
-	return ur.session.GetFileURL("user/avatar/" + strconv.Itoa(id))
+	key, err := avatarKey(ctx, id)
+	if err != nil {
+		return ""
+	}
+	return ur.session.GetFileURL(ctx, key)
 }
 
 func (ur userFileRepository) Create(ctx context.Context, id int, f multipart.File, fh *multipart.FileHeader) (string, error) {
-	return ur.session.UploadMultipartFile("user/avatar/"+strconv.Itoa(id), fh, f)
+	key, err := avatarKey(ctx, id)
+	if err != nil {
+		return "", err
+	}
+	return ur.session.UploadMultipartFile(ctx, key, fh, f)
 }
 
 func (ur userFileRepository) Update(ctx context.Context, id int, f multipart.File, fh *multipart.FileHeader) (string, error) {
+	key, err := avatarKey(ctx, id)
+	if err != nil {
+		return "", err
+	}
 	// Get existing avatar image file name
-	existingFile := ur.session.GetFileURL("user/avatar/" + strconv.Itoa(id))
+	existingFile := ur.session.GetFileURL(ctx, key)
 	url := strings.Split(existingFile, "/")
 	// Upload new avatar image file
-	file, err := ur.session.UploadMultipartFile("user/avatar/"+strconv.Itoa(id), fh, f)
+	file, err := ur.session.UploadMultipartFile(ctx, key, fh, f)
 	if err != nil {
 		return "", err
 	}
@@ -40,7 +62,7 @@ func (ur userFileRepository) Update(ctx context.Context, id int, f multipart.Fil
 	// If the file name is the same, it will not be deleted
 	filename := url[len(url)-1]
 	if fh.Filename != filename {
-		err = ur.session.DeleteFile("user/avatar/"+strconv.Itoa(id), filename)
+		err = ur.session.DeleteFile(ctx, key, filename)
 		if err != nil {
 			return file, err
 		}
@@ -49,7 +71,11 @@ func (ur userFileRepository) Update(ctx context.Context, id int, f multipart.Fil
 }
 
 func (ur userFileRepository) Delete(ctx context.Context, id int) error {
-	existsFile := ur.session.GetFileURL("user/avatar/" + strconv.Itoa(id))
-	filename := strings.SplitAfter(existsFile, "user/avatar/"+strconv.Itoa(id)+"/")
-	return ur.session.DeleteFile("user/avatar/"+strconv.Itoa(id), filename[1])
+	key, err := avatarKey(ctx, id)
+	if err != nil {
+		return err
+	}
+	existsFile := ur.session.GetFileURL(ctx, key)
+	filename := strings.SplitAfter(existsFile, key+"/")
+	return ur.session.DeleteFile(ctx, key, filename[1])
 }
@@ -0,0 +1,116 @@
+package format
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"github.com/jpdel518/go-ent/domain/model"
+	"io"
+	"strconv"
+	"strings"
+)
+
+var csvHeader = []string{"id", "first_name", "last_name", "email", "age", "car_ids"}
+
+type csvUserDecoder struct {
+	reader  *csv.Reader
+	columns map[string]int
+}
+
+func newCSVUserDecoder(r io.Reader) (*csvUserDecoder, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	// the first row must be a header
+	header, err := reader.Read()
+	if err == io.EOF {
+		return nil, errors.New("csv header is missing")
+	}
+	if err != nil {
+		return nil, err
+	}
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))] = i
+	}
+	for _, name := range []string{"first_name", "last_name", "email"} {
+		if _, ok := columns[name]; !ok {
+			return nil, fmt.Errorf("csv header must contain %q", name)
+		}
+	}
+	return &csvUserDecoder{reader: reader, columns: columns}, nil
+}
+
+func (d *csvUserDecoder) Decode() (*model.User, int, error) {
+	record, err := d.reader.Read()
+	if err == io.EOF {
+		return nil, 0, io.EOF
+	}
+	var parseErr *csv.ParseError
+	if errors.As(err, &parseErr) {
+		return nil, parseErr.StartLine, &model.RowError{Line: parseErr.StartLine, Err: parseErr.Err}
+	}
+	if err != nil {
+		return nil, 0, err
+	}
+	line, _ := d.reader.FieldPos(0)
+
+	user := &model.User{
+		FirstName: d.value(record, "first_name"),
+		LastName:  d.value(record, "last_name"),
+		Email:     d.value(record, "email"),
+	}
+	if age := d.value(record, "age"); age != "" {
+		user.Age, err = strconv.Atoi(age)
+		if err != nil {
+			return nil, line, &model.RowError{Line: line, Err: fmt.Errorf("invalid age %q", age)}
+		}
+	}
+	// an existing car_ids column replaces cars even when it is empty
+	if _, ok := d.columns["car_ids"]; ok {
+		user.CarIDs, err = ParseCarIDs(d.value(record, "car_ids"))
+		if err != nil {
+			return nil, line, &model.RowError{Line: line, Err: err}
+		}
+	}
+	return user, line, nil
+}
+
+func (d *csvUserDecoder) value(record []string, column string) string {
+	i, ok := d.columns[column]
+	if !ok || i >= len(record) {
+		return ""
+	}
+	return strings.TrimSpace(record[i])
+}
+
+type csvUserEncoder struct {
+	writer *csv.Writer
+}
+
+func newCSVUserEncoder(w io.Writer) *csvUserEncoder {
+	writer := csv.NewWriter(w)
+	_ = writer.Write(csvHeader) // errors are reported by Flush
+	return &csvUserEncoder{writer: writer}
+}
+
+func (e *csvUserEncoder) Encode(u *model.User) error {
+	carIDs := make([]string, 0, len(u.CarIDs))
+	for _, id := range u.CarIDs {
+		carIDs = append(carIDs, strconv.Itoa(id))
+	}
+	return e.writer.Write([]string{
+		strconv.Itoa(u.ID),
+		u.FirstName,
+		u.LastName,
+		u.Email,
+		strconv.Itoa(u.Age),
+		strings.Join(carIDs, ","),
+	})
+}
+
+func (e *csvUserEncoder) Flush() error {
+	e.writer.Flush()
+	return e.writer.Error()
+}
@@ -0,0 +1,81 @@
+package format
+
+import (
+	"fmt"
+	"github.com/jpdel518/go-ent/domain/model"
+	"io"
+	"strconv"
+	"strings"
+)
+
+const (
+	CSV    = "csv"
+	NDJSON = "ndjson"
+)
+
+// Supported reports whether users can be imported and exported in the format
+func Supported(format string) bool {
+	return format == CSV || format == NDJSON
+}
+
+// NewUserDecoder returns a decoder which reads users written in the given format
+func NewUserDecoder(format string, r io.Reader) (model.UserDecoder, error) {
+	switch format {
+	case CSV:
+		return newCSVUserDecoder(r)
+	case NDJSON:
+		return newNDJSONUserDecoder(r), nil
+	}
+	return nil, fmt.Errorf("unsupported format: %q", format)
+}
+
+// NewUserEncoder returns an encoder which writes users in the given format
+func NewUserEncoder(format string, w io.Writer) (model.UserEncoder, error) {
+	switch format {
+	case CSV:
+		return newCSVUserEncoder(w), nil
+	case NDJSON:
+		return newNDJSONUserEncoder(w), nil
+	}
+	return nil, fmt.Errorf("unsupported format: %q", format)
+}
+
+// ContentType returns the MIME type of the format
+func ContentType(format string) string {
+	switch format {
+	case CSV:
+		return "text/csv; charset=utf-8"
+	case NDJSON:
+		return "application/x-ndjson"
+	}
+	return "application/octet-stream"
+}
+
+// FromContentType detects the format from a Content-Type header value
+func FromContentType(contentType string) string {
+	mediaType := strings.TrimSpace(strings.Split(contentType, ";")[0])
+	switch mediaType {
+	case "text/csv", "application/csv":
+		return CSV
+	case "application/x-ndjson", "application/ndjson", "application/jsonl":
+		return NDJSON
+	}
+	return ""
+}
+
+// ParseCarIDs parses car ids such as "1,2", "[1, 2]" or "1;2"
+func ParseCarIDs(s string) ([]int, error) {
+	carIDs := make([]int, 0)
+	s = strings.Trim(strings.ReplaceAll(s, " ", ""), "[]")
+	if s == "" {
+		return carIDs, nil
+	}
+	for _, carString := range strings.FieldsFunc(s, func(r rune) bool { return r == ',' || r == ';' }) {
+		carID, err := strconv.Atoi(carString)
+		if err != nil {
+			return nil, fmt.Errorf("invalid car id %q", carString)
+		}
+		carIDs = append(carIDs, carID)
+	}
+	return carIDs, nil
+}
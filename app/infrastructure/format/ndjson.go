@@ -0,0 +1,63 @@
+package format
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"github.com/jpdel518/go-ent/domain/model"
+	"io"
+)
+
+// maxLineSize is the longest NDJSON line accepted by the decoder
+const maxLineSize = 1024 * 1024
+
+type ndjsonUserDecoder struct {
+	scanner *bufio.Scanner
+	line    int
+}
+
+func newNDJSONUserDecoder(r io.Reader) *ndjsonUserDecoder {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxLineSize)
+	return &ndjsonUserDecoder{scanner: scanner}
+}
+
+func (d *ndjsonUserDecoder) Decode() (*model.User, int, error) {
+	for d.scanner.Scan() {
+		d.line++
+		line := bytes.TrimSpace(d.scanner.Bytes())
+		// skip blank lines
+		if len(line) == 0 {
+			continue
+		}
+		user := &model.User{}
+		if err := json.Unmarshal(line, user); err != nil {
+			return nil, d.line, &model.RowError{Line: d.line, Err: err}
+		}
+		// ids are assigned by the import, not by the file
+		user.ID = 0
+		return user, d.line, nil
+	}
+	if err := d.scanner.Err(); err != nil {
+		return nil, d.line, err
+	}
+	return nil, d.line, io.EOF
+}
+
+type ndjsonUserEncoder struct {
+	writer  *bufio.Writer
+	encoder *json.Encoder
+}
+
+func newNDJSONUserEncoder(w io.Writer) *ndjsonUserEncoder {
+	writer := bufio.NewWriter(w)
+	return &ndjsonUserEncoder{writer: writer, encoder: json.NewEncoder(writer)}
+}
+
+func (e *ndjsonUserEncoder) Encode(u *model.User) error {
+	return e.encoder.Encode(u)
+}
+
+func (e *ndjsonUserEncoder) Flush() error {
+	return e.writer.Flush()
+}
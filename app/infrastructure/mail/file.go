@@ -0,0 +1,43 @@
+package mail
+
+import (
+	"context"
+	"fmt"
+	"github.com/jpdel518/go-ent/domain/model"
+	"github.com/jpdel518/go-ent/domain/repository"
+	netmail "net/mail"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"time"
+)
+
+type fileSender struct {
+	dir  string
+	from *netmail.Address
+	seq  atomic.Int64
+}
+
+// NewFileSender returns a sender which writes each mail to the directory as an .eml file instead of sending it,
+// so that the mails of local development can be opened with a mail client
+func NewFileSender(dir string, from *netmail.Address) (repository.MailSender, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	return &fileSender{dir: dir, from: from}, nil
+}
+
+func (s *fileSender) Send(ctx context.Context, m *model.Mail) error {
+	to, err := recipient(m)
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	msg, err := message(s.from, to, m, now)
+	if err != nil {
+		return err
+	}
+	// the names sort in the order of sending
+	name := fmt.Sprintf("%s-%06d.eml", now.UTC().Format("20060102T150405.000000000"), s.seq.Add(1))
+	return os.WriteFile(filepath.Join(s.dir, name), msg, 0o644)
+}
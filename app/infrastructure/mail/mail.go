@@ -0,0 +1,70 @@
+// Package mail sends the mails of the service over SMTP, or keeps them in files or in memory for local development and tests
+package mail
+
+import (
+	"fmt"
+	"github.com/jpdel518/go-ent/domain/repository"
+	netmail "net/mail"
+)
+
+// Config of the outgoing mails
+type Config struct {
+	// Transport is "smtp" which sends the mails, or "file" which writes them to Dir instead
+	Transport string `yaml:"transport" env:"MAIL_TRANSPORT" required:"true"`
+	// From is the address of the service, such as "go-ent <no-reply@example.com>"
+	From string     `yaml:"from" env:"MAIL_FROM" required:"true"`
+	Dir  string     `yaml:"dir" env:"MAIL_DIR"`
+	SMTP SMTPConfig `yaml:"smtp"`
+}
+
+// SMTPConfig of the mail server
+type SMTPConfig struct {
+	Host string `yaml:"host" env:"SMTP_HOST"`
+	Port int    `yaml:"port" env:"SMTP_PORT"`
+	// Username and Password authenticate with PLAIN, which needs TLS. There is no authentication when Username is empty
+	Username string `yaml:"username" env:"SMTP_USERNAME"`
+	Password string `yaml:"password" env:"SMTP_PASSWORD" secret:"true"`
+	// TLS connects with TLS from the start as on port 465. Otherwise STARTTLS is used when the server offers it
+	TLS bool `yaml:"tls" env:"SMTP_TLS"`
+}
+
+// Validate checks the transport and the addresses
+func (c Config) Validate() error {
+	if c.From != "" {
+		if _, err := netmail.ParseAddress(c.From); err != nil {
+			return fmt.Errorf("from %q: %w", c.From, err)
+		}
+	}
+	switch c.Transport {
+	case "smtp":
+		if c.SMTP.Host == "" || c.SMTP.Port == 0 {
+			return fmt.Errorf("smtp transport needs smtp.host and smtp.port")
+		}
+	case "file":
+		if c.Dir == "" {
+			return fmt.Errorf("file transport needs dir")
+		}
+	case "":
+	default:
+		return fmt.Errorf("unknown transport %q", c.Transport)
+	}
+	return nil
+}
+
+// NewSender returns the sender of the transport
+func NewSender(c Config) (repository.MailSender, error) {
+	if err := c.Validate(); err != nil {
+		return nil, err
+	}
+	from, err := netmail.ParseAddress(c.From)
+	if err != nil {
+		return nil, fmt.Errorf("from %q: %w", c.From, err)
+	}
+	switch c.Transport {
+	case "smtp":
+		return NewSMTPSender(c.SMTP, from), nil
+	case "file":
+		return NewFileSender(c.Dir, from)
+	}
+	return nil, fmt.Errorf("unknown transport %q", c.Transport)
+}
@@ -0,0 +1,34 @@
+package mail
+
+import (
+	"context"
+	"github.com/jpdel518/go-ent/domain/model"
+	"sync"
+)
+
+// Mailbox keeps the mails in memory instead of sending them, for the tests
+type Mailbox struct {
+	mu    sync.Mutex
+	mails []model.Mail
+}
+
+func NewMailbox() *Mailbox {
+	return &Mailbox{}
+}
+
+func (b *Mailbox) Send(ctx context.Context, m *model.Mail) error {
+	if _, err := recipient(m); err != nil {
+		return err
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.mails = append(b.mails, *m)
+	return nil
+}
+
+// Mails returns the mails sent so far in order
+func (b *Mailbox) Mails() []model.Mail {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return append([]model.Mail(nil), b.mails...)
+}
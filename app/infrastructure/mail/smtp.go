@@ -0,0 +1,94 @@
+package mail
+
+import (
+	"context"
+	"crypto/tls"
+	"github.com/jpdel518/go-ent/domain/model"
+	"github.com/jpdel518/go-ent/domain/repository"
+	"net"
+	netmail "net/mail"
+	"net/smtp"
+	"strconv"
+	"time"
+)
+
+type smtpSender struct {
+	config SMTPConfig
+	from   *netmail.Address
+}
+
+// NewSMTPSender returns a sender which delivers each mail to the server on a new connection
+func NewSMTPSender(c SMTPConfig, from *netmail.Address) repository.MailSender {
+	return &smtpSender{config: c, from: from}
+}
+
+func (s *smtpSender) Send(ctx context.Context, m *model.Mail) error {
+	to, err := recipient(m)
+	if err != nil {
+		return err
+	}
+	msg, err := message(s.from, to, m, time.Now())
+	if err != nil {
+		return err
+	}
+
+	addr := net.JoinHostPort(s.config.Host, strconv.Itoa(s.config.Port))
+	var conn net.Conn
+	if s.config.TLS {
+		d := &tls.Dialer{Config: &tls.Config{ServerName: s.config.Host}}
+		conn, err = d.DialContext(ctx, "tcp", addr)
+	} else {
+		var d net.Dialer
+		conn, err = d.DialContext(ctx, "tcp", addr)
+	}
+	if err != nil {
+		return err
+	}
+	// net/smtp knows no context, so the connection is closed when it is done
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		select {
+		case <-ctx.Done():
+			_ = conn.Close()
+		case <-done:
+		}
+	}()
+
+	c, err := smtp.NewClient(conn, s.config.Host)
+	if err != nil {
+		_ = conn.Close()
+		return err
+	}
+	defer c.Close()
+	if !s.config.TLS {
+		if ok, _ := c.Extension("STARTTLS"); ok {
+			if err := c.StartTLS(&tls.Config{ServerName: s.config.Host}); err != nil {
+				return err
+			}
+		}
+	}
+	// PLAIN refuses to send the password without TLS, except to localhost
+	if s.config.Username != "" {
+		if err := c.Auth(smtp.PlainAuth("", s.config.Username, s.config.Password, s.config.Host)); err != nil {
+			return err
+		}
+	}
+	if err := c.Mail(s.from.Address); err != nil {
+		return err
+	}
+	if err := c.Rcpt(to.Address); err != nil {
+		return err
+	}
+	w, err := c.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(msg); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+	return c.Quit()
+}
@@ -0,0 +1,108 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"github.com/jpdel518/go-ent/domain/model"
+	"github.com/jpdel518/go-ent/domain/repository"
+	"sort"
+)
+
+type carRepository struct {
+	store *Store
+}
+
+func NewCarRepository(s *Store) repository.CarRepository {
+	return &carRepository{store: s}
+}
+
+func (r *carRepository) Fetch(ctx context.Context, num int) ([]*model.Car, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	ids := make([]int, 0, len(r.store.cars))
+	for id := range r.store.cars {
+		ids = append(ids, id)
+	}
+	sort.Ints(ids)
+
+	res := make([]*model.Car, 0)
+	for _, id := range ids {
+		if len(res) == num {
+			break
+		}
+		c := *r.store.cars[id]
+		res = append(res, &c)
+	}
+	return res, nil
+}
+
+func (r *carRepository) GetByID(ctx context.Context, id int) (*model.Car, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	c, ok := r.store.cars[id]
+	if !ok {
+		return nil, fmt.Errorf("car %d: %w", id, repository.ErrNotFound)
+	}
+	res := *c
+	return &res, nil
+}
+
+// FetchByIDs returns the cars which exist, ordered by id
+func (r *carRepository) FetchByIDs(ctx context.Context, ids []int) ([]*model.Car, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	found := make([]int, 0, len(ids))
+	seen := make(map[int]bool, len(ids))
+	for _, id := range ids {
+		if _, ok := r.store.cars[id]; ok && !seen[id] {
+			seen[id] = true
+			found = append(found, id)
+		}
+	}
+	sort.Ints(found)
+
+	res := make([]*model.Car, 0, len(found))
+	for _, id := range found {
+		c := *r.store.cars[id]
+		res = append(res, &c)
+	}
+	return res, nil
+}
+
+func (r *carRepository) Create(ctx context.Context, u *model.Car) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	r.store.lastCar++
+	u.ID = r.store.lastCar
+	c := *u
+	r.store.cars[c.ID] = &c
+	return nil
+}
+
+func (r *carRepository) Update(ctx context.Context, u *model.Car) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if _, ok := r.store.cars[u.ID]; !ok {
+		return fmt.Errorf("car %d: %w", u.ID, repository.ErrNotFound)
+	}
+	c := *u
+	r.store.cars[c.ID] = &c
+	return nil
+}
+
+func (r *carRepository) Delete(ctx context.Context, id int) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if _, ok := r.store.cars[id]; !ok {
+		return fmt.Errorf("car %d: %w", id, repository.ErrNotFound)
+	}
+	delete(r.store.cars, id)
+	delete(r.store.owners, id)
+	return nil
+}
@@ -0,0 +1,60 @@
+package memory
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"github.com/jpdel518/go-ent/domain/repository"
+	"github.com/jpdel518/go-ent/tenant"
+	"io"
+)
+
+type jobFileRepository struct {
+	store *Store
+}
+
+func NewJobFileRepository(s *Store) repository.JobFileRepository {
+	return &jobFileRepository{store: s}
+}
+
+// jobFileKey is the key of the file of the tenant, as in S3
+func jobFileKey(ctx context.Context, key string) (string, error) {
+	tenantID, ok := tenant.FromContext(ctx)
+	if !ok {
+		return "", tenant.ErrMissing
+	}
+	return tenant.Prefix(tenantID) + "job/" + key, nil
+}
+
+func (r *jobFileRepository) Upload(ctx context.Context, key string, body io.Reader) (string, error) {
+	key, err := jobFileKey(ctx, key)
+	if err != nil {
+		return "", err
+	}
+	// read outside the lock
+	data, err := io.ReadAll(body)
+	if err != nil {
+		return "", err
+	}
+
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	r.store.files[key] = data
+	return "memory://" + key, nil
+}
+
+func (r *jobFileRepository) Download(ctx context.Context, key string) (io.ReadCloser, error) {
+	key, err := jobFileKey(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	data, ok := r.store.files[key]
+	if !ok {
+		return nil, fmt.Errorf("job file %s: %w", key, repository.ErrNotFound)
+	}
+	return io.NopCloser(bytes.NewReader(data)), nil
+}
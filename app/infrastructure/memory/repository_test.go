@@ -0,0 +1,68 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"github.com/jpdel518/go-ent/domain/model"
+	"github.com/jpdel518/go-ent/domain/repository"
+	"github.com/jpdel518/go-ent/domain/repository/repositorytest"
+	"sync"
+	"testing"
+)
+
+func TestRepositories(t *testing.T) {
+	repositorytest.TestRepositories(t, func(t *testing.T) repositorytest.Repositories {
+		s := NewStore()
+		return repositorytest.Repositories{Users: NewUserRepository(s), Cars: NewCarRepository(s)}
+	})
+}
+
+func TestUserFileRepository(t *testing.T) {
+	repositorytest.TestUserFileRepository(t, func(t *testing.T) repository.UserFileRepository {
+		return NewUserFileRepository(NewStore())
+	})
+}
+
+// TestConcurrentUsers is meant for -race
+func TestConcurrentUsers(t *testing.T) {
+	ctx := context.Background()
+	s := NewStore()
+	users, cars := NewUserRepository(s), NewCarRepository(s)
+	car := &model.Car{Name: "Leaf", Model: "Nissan"}
+	if err := cars.Create(ctx, car); err != nil {
+		t.Fatal(err)
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			// every user takes the car from the previous owner
+			u, err := users.Create(ctx, &model.User{FirstName: "Taro", LastName: "Yamada", Email: fmt.Sprintf("user%d@example.com", i), CarIDs: []int{car.ID}})
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			if _, err := users.GetByID(ctx, u.ID); err != nil {
+				t.Error(err)
+			}
+			if _, err := users.Fetch(ctx, 10); err != nil {
+				t.Error(err)
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	if n, _ := users.Count(ctx); n != 20 {
+		t.Errorf("Count = %d, want 20", n)
+	}
+	all, _ := users.Fetch(ctx, 20)
+	owners := 0
+	for _, u := range all {
+		owners += len(u.CarIDs)
+	}
+	if owners != 1 {
+		t.Errorf("the car has %d owners, want 1", owners)
+	}
+}
@@ -0,0 +1,97 @@
+package memory
+
+import (
+	"github.com/jpdel518/go-ent/domain/model"
+	"sort"
+	"sync"
+)
+
+// Store keeps the users, the cars and the files in the memory of the instance, for the tests of the usecases.
+// The repositories of one store share the data, so that a car belongs to at most one user as in the database.
+// Only the files are kept by tenant, the users and the cars are of one tenant whatever the context
+type Store struct {
+	mu sync.RWMutex
+	// users are kept without the cars, which are found by owners
+	users  map[int]*model.User
+	cars   map[int]*model.Car
+	owners map[int]int
+	// avatars are the avatars by the key of their folder
+	avatars map[string]avatar
+	// files are the files of the jobs by key
+	files    map[string][]byte
+	lastUser int
+	lastCar  int
+}
+
+type avatar struct {
+	filename string
+	data     []byte
+}
+
+// NewStore returns an empty store
+func NewStore() *Store {
+	return &Store{
+		users:   make(map[int]*model.User),
+		cars:    make(map[int]*model.Car),
+		owners:  make(map[int]int),
+		avatars: make(map[string]avatar),
+		files:   make(map[string][]byte),
+	}
+}
+
+// user copies the user with the ids of the cars in order
+func (s *Store) user(id int) *model.User {
+	u := *s.users[id]
+	u.CarIDs = make([]int, 0)
+	u.Cars = make([]model.Car, 0)
+	for carID, owner := range s.owners {
+		if owner == id {
+			u.CarIDs = append(u.CarIDs, carID)
+		}
+	}
+	sort.Ints(u.CarIDs)
+	return &u
+}
+
+// userIDs are the ids of the users in order
+func (s *Store) userIDs() []int {
+	ids := make([]int, 0, len(s.users))
+	for id := range s.users {
+		ids = append(ids, id)
+	}
+	sort.Ints(ids)
+	return ids
+}
+
+// emailTaken tells whether a user other than id has the e-mail
+func (s *Store) emailTaken(email string, id int) bool {
+	for _, u := range s.users {
+		if u.Email == email && u.ID != id {
+			return true
+		}
+	}
+	return false
+}
+
+// replaceCars gives the user the cars and nothing else, taking them from the other users.
+// The cars must have been checked by missingCar
+func (s *Store) replaceCars(userID int, carIDs []int) {
+	for carID, owner := range s.owners {
+		if owner == userID {
+			delete(s.owners, carID)
+		}
+	}
+	for _, carID := range carIDs {
+		s.owners[carID] = userID
+	}
+}
+
+// missingCar returns the first car which does not exist
+func (s *Store) missingCar(carIDs []int) (int, bool) {
+	for _, id := range carIDs {
+		if _, ok := s.cars[id]; !ok {
+			return id, true
+		}
+	}
+	return 0, false
+}
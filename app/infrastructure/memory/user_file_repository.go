@@ -0,0 +1,91 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"github.com/jpdel518/go-ent/domain/repository"
+	"github.com/jpdel518/go-ent/tenant"
+	"io"
+	"mime/multipart"
+	"strconv"
+)
+
+type userFileRepository struct {
+	store *Store
+}
+
+func NewUserFileRepository(s *Store) repository.UserFileRepository {
+	return &userFileRepository{store: s}
+}
+
+// avatarKey is the folder of the avatar of the user of the tenant, as in S3
+func avatarKey(ctx context.Context, id int) (string, error) {
+	tenantID, ok := tenant.FromContext(ctx)
+	if !ok {
+		return "", tenant.ErrMissing
+	}
+	return tenant.Prefix(tenantID) + "user/avatar/" + strconv.Itoa(id), nil
+}
+
+// url is the location of the avatar like the URL of S3
+func url(key string, filename string) string {
+	return "memory://" + key + "/" + filename
+}
+
+// GetURLByUser returns the URL of the avatar, or "" when the user has none
+func (r *userFileRepository) GetURLByUser(ctx context.Context, id int) string {
+	key, err := avatarKey(ctx, id)
+	if err != nil {
+		return ""
+	}
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	a, ok := r.store.avatars[key]
+	if !ok {
+		return ""
+	}
+	return url(key, a.filename)
+}
+
+func (r *userFileRepository) Create(ctx context.Context, id int, f multipart.File, fh *multipart.FileHeader) (string, error) {
+	return r.put(ctx, id, f, fh)
+}
+
+// Update replaces the avatar of the user
+func (r *userFileRepository) Update(ctx context.Context, id int, f multipart.File, fh *multipart.FileHeader) (string, error) {
+	return r.put(ctx, id, f, fh)
+}
+
+func (r *userFileRepository) Delete(ctx context.Context, id int) error {
+	key, err := avatarKey(ctx, id)
+	if err != nil {
+		return err
+	}
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if _, ok := r.store.avatars[key]; !ok {
+		return fmt.Errorf("avatar of user %d: %w", id, repository.ErrNotFound)
+	}
+	delete(r.store.avatars, key)
+	return nil
+}
+
+func (r *userFileRepository) put(ctx context.Context, id int, f multipart.File, fh *multipart.FileHeader) (string, error) {
+	key, err := avatarKey(ctx, id)
+	if err != nil {
+		return "", err
+	}
+	// read outside the lock
+	data, err := io.ReadAll(f)
+	if err != nil {
+		return "", err
+	}
+
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	r.store.avatars[key] = avatar{filename: fh.Filename, data: data}
+	return url(key, fh.Filename), nil
+}
@@ -0,0 +1,214 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"github.com/jpdel518/go-ent/domain/model"
+	"github.com/jpdel518/go-ent/domain/repository"
+	"time"
+)
+
+type userRepository struct {
+	store *Store
+}
+
+func NewUserRepository(s *Store) repository.UserRepository {
+	return &userRepository{store: s}
+}
+
+func (r *userRepository) Fetch(ctx context.Context, num int) ([]*model.User, error) {
+	return r.FetchAfter(ctx, 0, num)
+}
+
+func (r *userRepository) FetchAfter(ctx context.Context, afterID int, num int) ([]*model.User, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	res := make([]*model.User, 0)
+	for _, id := range r.store.userIDs() {
+		if len(res) == num {
+			break
+		}
+		if id > afterID {
+			res = append(res, r.store.user(id))
+		}
+	}
+	return res, nil
+}
+
+func (r *userRepository) GetByID(ctx context.Context, id int) (*model.User, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	if _, ok := r.store.users[id]; !ok {
+		return nil, fmt.Errorf("user %d: %w", id, repository.ErrNotFound)
+	}
+	return r.store.user(id), nil
+}
+
+func (r *userRepository) Create(ctx context.Context, u *model.User) (*model.User, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	email := model.NormalizeEmail(u.Email)
+	if r.store.emailTaken(email, 0) {
+		return nil, fmt.Errorf("%s: %w", u.Email, repository.ErrDuplicateEmail)
+	}
+	if id, ok := r.store.missingCar(u.CarIDs); ok {
+		return nil, fmt.Errorf("car %d does not exist: %w", id, repository.ErrNotFound)
+	}
+
+	r.store.lastUser++
+	r.store.users[r.store.lastUser] = &model.User{
+		ID:        r.store.lastUser,
+		FirstName: u.FirstName,
+		LastName:  u.LastName,
+		Email:     email,
+		Age:       u.Age,
+	}
+	r.store.replaceCars(r.store.lastUser, u.CarIDs)
+	return r.store.user(r.store.lastUser), nil
+}
+
+func (r *userRepository) Update(ctx context.Context, u *model.User) (*model.User, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	current, ok := r.store.users[u.ID]
+	if !ok {
+		return nil, fmt.Errorf("user %d: %w", u.ID, repository.ErrNotFound)
+	}
+	email := model.NormalizeEmail(u.Email)
+	if r.store.emailTaken(email, u.ID) {
+		return nil, fmt.Errorf("%s: %w", u.Email, repository.ErrDuplicateEmail)
+	}
+	if id, ok := r.store.missingCar(u.CarIDs); ok {
+		return nil, fmt.Errorf("car %d does not exist: %w", id, repository.ErrNotFound)
+	}
+
+	current.FirstName = u.FirstName
+	current.LastName = u.LastName
+	// a new e-mail is not verified yet
+	if current.Email != email {
+		current.Email = email
+		current.VerifiedAt = nil
+	}
+	current.Age = u.Age
+	// keep the current avatar when no new one is given
+	if u.Avatar != "" {
+		current.Avatar = u.Avatar
+	}
+	r.store.replaceCars(u.ID, u.CarIDs)
+	return u, nil
+}
+
+func (r *userRepository) Delete(ctx context.Context, id int) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if _, ok := r.store.users[id]; !ok {
+		return fmt.Errorf("user %d: %w", id, repository.ErrNotFound)
+	}
+	// the cars lose their owner together with the user
+	r.store.replaceCars(id, nil)
+	delete(r.store.users, id)
+	return nil
+}
+
+func (r *userRepository) Count(ctx context.Context) (int, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	return len(r.store.users), nil
+}
+
+func (r *userRepository) UpdateAvatar(ctx context.Context, id int, avatar string) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	u, ok := r.store.users[id]
+	if !ok {
+		return fmt.Errorf("user %d: %w", id, repository.ErrNotFound)
+	}
+	u.Avatar = avatar
+	return nil
+}
+
+func (r *userRepository) Verify(ctx context.Context, id int, email string, at time.Time) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	u, ok := r.store.users[id]
+	if !ok || u.Email != model.NormalizeEmail(email) {
+		return fmt.Errorf("user %d with %s: %w", id, email, repository.ErrNotFound)
+	}
+	u.VerifiedAt = &at
+	return nil
+}
+
+// RequestVerification only checks the user, as the store has no outbox to mail the link from
+func (r *userRepository) RequestVerification(ctx context.Context, id int) error {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	if _, ok := r.store.users[id]; !ok {
+		return fmt.Errorf("user %d: %w", id, repository.ErrNotFound)
+	}
+	return nil
+}
+
+// Upsert creates the users whose email is not registered yet and updates the others, all or nothing.
+// Cars are only replaced when CarIDs is not nil.
+func (r *userRepository) Upsert(ctx context.Context, us []*model.User) ([]bool, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	// check everything before changing anything
+	ids := make(map[string]int, len(r.store.users))
+	for _, u := range r.store.users {
+		ids[u.Email] = u.ID
+	}
+	news := make(map[string]bool)
+	for _, u := range us {
+		u.Email = model.NormalizeEmail(u.Email)
+		if _, ok := ids[u.Email]; !ok {
+			if news[u.Email] {
+				return nil, fmt.Errorf("%s: %w", u.Email, repository.ErrDuplicateEmail)
+			}
+			news[u.Email] = true
+		}
+		if id, ok := r.store.missingCar(u.CarIDs); ok {
+			return nil, fmt.Errorf("car %d does not exist: %w", id, repository.ErrNotFound)
+		}
+	}
+
+	created := make([]bool, len(us))
+	for i, u := range us {
+		id, ok := ids[u.Email]
+		if ok {
+			current := r.store.users[id]
+			current.FirstName = u.FirstName
+			current.LastName = u.LastName
+			current.Age = u.Age
+		} else {
+			created[i] = true
+			r.store.lastUser++
+			id = r.store.lastUser
+			r.store.users[id] = &model.User{
+				ID:        id,
+				FirstName: u.FirstName,
+				LastName:  u.LastName,
+				Email:     u.Email,
+				Age:       u.Age,
+			}
+		}
+		u.ID = id
+	}
+	// replace cars in the order of the rows
+	for _, u := range us {
+		if u.CarIDs != nil {
+			r.store.replaceCars(u.ID, u.CarIDs)
+		}
+	}
+	return created, nil
+}
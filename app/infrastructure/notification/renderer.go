@@ -0,0 +1,92 @@
+// Package notification renders the mails of the notifications from the templates of each language.
+//
+// The templates are templates/{language}/{kind}.txt and templates/{language}/{kind}.html,
+// which are executed with model.NotificationData.
+// The text one is a text/template which defines the subject as "subject", and the HTML one is an html/template
+package notification
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	"github.com/jpdel518/go-ent/domain/model"
+	"github.com/jpdel518/go-ent/domain/repository"
+	htmltemplate "html/template"
+	"strings"
+	texttemplate "text/template"
+)
+
+//go:embed templates
+var templates embed.FS
+
+// kindTemplates are the templates of a kind in a language
+type kindTemplates struct {
+	text *texttemplate.Template
+	html *htmltemplate.Template
+}
+
+type renderer struct {
+	defaultLanguage string
+	// templates by the language and the kind
+	templates map[string]map[model.NotificationKind]*kindTemplates
+}
+
+// NewRenderer parses the templates of every language and kind, so that a broken one fails the start instead of a mail
+func NewRenderer(defaultLanguage string) (repository.NotificationRenderer, error) {
+	r := &renderer{
+		defaultLanguage: defaultLanguage,
+		templates:       make(map[string]map[model.NotificationKind]*kindTemplates, len(model.Languages)),
+	}
+	for _, language := range model.Languages {
+		r.templates[language] = make(map[model.NotificationKind]*kindTemplates, len(model.NotificationKinds))
+		for _, kind := range model.NotificationKinds {
+			name := fmt.Sprintf("templates/%s/%s", language, kind)
+			text, err := texttemplate.ParseFS(templates, name+".txt")
+			if err != nil {
+				return nil, err
+			}
+			if text.Lookup("subject") == nil {
+				return nil, fmt.Errorf("%s.txt: no subject is defined", name)
+			}
+			html, err := htmltemplate.ParseFS(templates, name+".html")
+			if err != nil {
+				return nil, err
+			}
+			r.templates[language][kind] = &kindTemplates{text: text, html: html}
+		}
+	}
+	if _, ok := r.templates[defaultLanguage]; !ok {
+		return nil, fmt.Errorf("no templates of the default language %q", defaultLanguage)
+	}
+	return r, nil
+}
+
+// Render renders the mail to the user of the data. An empty or unknown language falls back to the default one
+func (r *renderer) Render(kind model.NotificationKind, language string, data *model.NotificationData) (*model.Mail, error) {
+	byKind, ok := r.templates[language]
+	if !ok {
+		byKind = r.templates[r.defaultLanguage]
+	}
+	t, ok := byKind[kind]
+	if !ok {
+		return nil, fmt.Errorf("unknown notification kind %q", kind)
+	}
+
+	var subject, text, html bytes.Buffer
+	if err := t.text.ExecuteTemplate(&subject, "subject", data); err != nil {
+		return nil, err
+	}
+	if err := t.text.Execute(&text, data); err != nil {
+		return nil, err
+	}
+	if err := t.html.Execute(&html, data); err != nil {
+		return nil, err
+	}
+	return &model.Mail{
+		To: data.User.Email,
+		// a subject is a single line
+		Subject: strings.Join(strings.Fields(subject.String()), " "),
+		Text:    text.String(),
+		HTML:    html.String(),
+	}, nil
+}
@@ -0,0 +1,82 @@
+package notification
+
+import (
+	"github.com/jpdel518/go-ent/domain/model"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRender(t *testing.T) {
+	r, err := NewRenderer(model.LanguageJapanese)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data := &model.NotificationData{
+		User:      &model.User{ID: 1, FirstName: "Taro", LastName: "Yamada", Email: "taro@example.com"},
+		Car:       &model.Car{ID: 2, Name: "Prius", Model: "<XW60>"},
+		Group:     &model.Group{ID: 3, Name: "drivers"},
+		Link:      "http://localhost:8080/user/verify?token=abc.def",
+		ExpiresAt: time.Date(2020, 4, 2, 9, 0, 0, 0, time.UTC),
+	}
+
+	// every template renders in every language
+	for _, language := range model.Languages {
+		for _, kind := range model.NotificationKinds {
+			m, err := r.Render(kind, language, data)
+			if err != nil {
+				t.Errorf("%s %s: %v", language, kind, err)
+				continue
+			}
+			if m.To != "taro@example.com" || m.Subject == "" || m.Text == "" || m.HTML == "" {
+				t.Errorf("%s %s: got %+v", language, kind, m)
+			}
+			if strings.Contains(m.Subject, "\n") {
+				t.Errorf("%s %s: subject has a line break: %q", language, kind, m.Subject)
+			}
+		}
+	}
+
+	m, err := r.Render(model.NotificationCarAssigned, model.LanguageEnglish, data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(m.Text, "Model: <XW60>") {
+		t.Errorf("text is escaped: %q", m.Text)
+	}
+	if !strings.Contains(m.HTML, "&lt;XW60&gt;") {
+		t.Errorf("html is not escaped: %q", m.HTML)
+	}
+
+	m, err = r.Render(model.NotificationVerification, model.LanguageEnglish, data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(m.Text, data.Link+"\n") || !strings.Contains(m.Text, "Thu, 02 Apr 2020 09:00:00 UTC") {
+		t.Errorf("text has no link or expiry: %q", m.Text)
+	}
+
+	// an unknown language falls back to the default one
+	fallback, err := r.Render(model.NotificationGroupAdded, "fr", data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ja, err := r.Render(model.NotificationGroupAdded, model.LanguageJapanese, data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fallback.Subject != ja.Subject {
+		t.Errorf("got subject %q, want %q", fallback.Subject, ja.Subject)
+	}
+
+	// the data of the kind is required
+	if _, err := r.Render(model.NotificationCarAssigned, model.LanguageEnglish, &model.NotificationData{User: data.User}); err == nil {
+		t.Error("rendered without the car")
+	}
+}
+
+func TestNewRendererUnknownDefault(t *testing.T) {
+	if _, err := NewRenderer("fr"); err == nil {
+		t.Error("created with a default language without templates")
+	}
+}
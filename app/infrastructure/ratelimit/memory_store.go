@@ -0,0 +1,79 @@
+package ratelimit
+
+import (
+	"context"
+	"github.com/jpdel518/go-ent/domain/model"
+	"github.com/jpdel518/go-ent/domain/repository"
+	"math"
+	"sync"
+	"time"
+)
+
+// sweepInterval is how often the full buckets are dropped, as a full bucket is the same as no bucket
+const sweepInterval = time.Minute
+
+type bucket struct {
+	limit   model.RateLimit
+	tokens  float64
+	updated time.Time
+}
+
+// refill adds the tokens for the time since the last update
+func (b *bucket) refill(now time.Time) {
+	b.tokens = math.Min(float64(b.limit.Limit), b.tokens+float64(now.Sub(b.updated))/float64(b.limit.Interval()))
+	b.updated = now
+}
+
+type memoryStore struct {
+	mu        sync.Mutex
+	buckets   map[string]*bucket
+	now       func() time.Time
+	lastSweep time.Time
+}
+
+// NewMemoryStore returns a rate limit store in the memory of the instance
+func NewMemoryStore() repository.RateLimitStore {
+	return &memoryStore{
+		buckets:   make(map[string]*bucket),
+		now:       time.Now,
+		lastSweep: time.Now(),
+	}
+}
+
+func (s *memoryStore) Take(ctx context.Context, key string, limit model.RateLimit) (*model.RateLimitDecision, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	s.sweep(now)
+	b, ok := s.buckets[key]
+	if !ok || b.limit != limit {
+		b = &bucket{limit: limit, tokens: float64(limit.Limit), updated: now}
+		s.buckets[key] = b
+	}
+	b.refill(now)
+
+	decision := &model.RateLimitDecision{Limit: limit}
+	if b.tokens >= 1 {
+		b.tokens--
+		decision.Allowed = true
+	} else {
+		decision.RetryAfter = time.Duration((1 - b.tokens) * float64(limit.Interval()))
+	}
+	decision.Remaining = int(b.tokens)
+	decision.Reset = time.Duration((float64(limit.Limit) - b.tokens) * float64(limit.Interval()))
+	return decision, nil
+}
+
+func (s *memoryStore) sweep(now time.Time) {
+	if now.Sub(s.lastSweep) < sweepInterval {
+		return
+	}
+	s.lastSweep = now
+	for key, b := range s.buckets {
+		b.refill(now)
+		if b.tokens >= float64(b.limit.Limit) {
+			delete(s.buckets, key)
+		}
+	}
+}
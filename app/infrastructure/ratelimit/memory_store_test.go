@@ -0,0 +1,72 @@
+package ratelimit
+
+import (
+	"context"
+	"github.com/jpdel518/go-ent/domain/model"
+	"testing"
+	"time"
+)
+
+func TestMemoryStore(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2026, 10, 19, 9, 0, 0, 0, time.UTC)
+	s := NewMemoryStore().(*memoryStore)
+	s.now = func() time.Time {
+		return now
+	}
+	s.lastSweep = now
+	// a token every 20s
+	limit := model.RateLimit{Limit: 3, Period: time.Minute}
+
+	take := func(key string, l model.RateLimit) *model.RateLimitDecision {
+		t.Helper()
+		d, err := s.Take(ctx, key, l)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return d
+	}
+	expect := func(d *model.RateLimitDecision, allowed bool, remaining int, reset, retryAfter time.Duration) {
+		t.Helper()
+		if d.Allowed != allowed || d.Remaining != remaining || d.Reset != reset || d.RetryAfter != retryAfter {
+			t.Fatalf("decision = %+v, want allowed %v, remaining %d, reset %v, retry after %v", d, allowed, remaining, reset, retryAfter)
+		}
+	}
+
+	// a new client has a full bucket
+	expect(take("a", limit), true, 2, 20*time.Second, 0)
+	expect(take("a", limit), true, 1, 40*time.Second, 0)
+	expect(take("a", limit), true, 0, time.Minute, 0)
+	expect(take("a", limit), false, 0, time.Minute, 20*time.Second)
+
+	// the other clients have their own buckets
+	expect(take("b", limit), true, 2, 20*time.Second, 0)
+
+	// the tokens are refilled evenly
+	now = now.Add(10 * time.Second)
+	expect(take("a", limit), false, 0, 50*time.Second, 10*time.Second)
+	now = now.Add(10 * time.Second)
+	expect(take("a", limit), true, 0, time.Minute, 0)
+
+	// the bucket never holds more than the limit
+	now = now.Add(time.Hour)
+	expect(take("a", limit), true, 2, 20*time.Second, 0)
+
+	// a changed limit starts a new bucket
+	expect(take("a", model.RateLimit{Limit: 1, Period: time.Second}), true, 0, time.Second, 0)
+
+	// the full buckets are dropped by the sweep, and the others are kept
+	now = now.Add(sweepInterval)
+	take("c", model.RateLimit{Limit: 1, Period: time.Hour})
+	if _, ok := s.buckets["a"]; ok {
+		t.Error("full bucket of a is kept")
+	}
+	if _, ok := s.buckets["c"]; !ok {
+		t.Error("bucket of c is dropped")
+	}
+	now = now.Add(time.Second)
+	take("c", model.RateLimit{Limit: 1, Period: time.Hour})
+	if len(s.buckets) != 1 {
+		t.Errorf("%d buckets, want 1", len(s.buckets))
+	}
+}
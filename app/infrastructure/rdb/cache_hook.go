@@ -0,0 +1,99 @@
+package rdb
+
+import (
+	"context"
+	"github.com/jpdel518/go-ent/ent"
+	"github.com/jpdel518/go-ent/ent/car"
+	"github.com/jpdel518/go-ent/ent/user"
+	"github.com/jpdel518/go-ent/infrastructure/cache"
+)
+
+// CacheHook invalidates the cached users and cars written through the client.
+// A user is cached with the ids of the cars, so the owners of the cars before and after the change are invalidated as well.
+// The keys are read with the tenants of the entities, as the workers of every tenant write them too.
+// Changes made in a transaction are invalidated once it is committed, as the entries may be loaded again before that.
+func CacheHook(c *cache.ReadThrough) ent.Hook {
+	return func(next ent.Mutator) ent.Mutator {
+		return ent.MutateFunc(func(ctx context.Context, m ent.Mutation) (ent.Value, error) {
+			sm, ok := m.(searchMutation)
+			if !ok || (m.Type() != ent.TypeUser && m.Type() != ent.TypeCar) {
+				return next.Mutate(ctx, m)
+			}
+
+			// the ids have to be read before they are deleted, a new entity is not cached yet
+			var ids []int
+			if !m.Op().Is(ent.OpCreate) {
+				var err error
+				ids, err = sm.IDs(ctx)
+				if err != nil {
+					return nil, err
+				}
+			}
+			var userIDs, carIDs, cars []int
+			switch mm := m.(type) {
+			case *ent.UserMutation:
+				userIDs = ids
+				cars = append(mm.CarsIDs(), mm.RemovedCarsIDs()...)
+			case *ent.CarMutation:
+				carIDs, cars = ids, ids
+				// the new owner gains the cars
+				userIDs = mm.OwnerIDs()
+			}
+			keys, err := cacheKeysOf(ctx, sm.Client(), userIDs, carIDs, cars)
+			if err != nil {
+				return nil, err
+			}
+
+			v, err := next.Mutate(ctx, m)
+			if err != nil || len(keys) == 0 {
+				return v, err
+			}
+			tx, err := sm.Tx()
+			if err != nil {
+				c.Invalidate(ctx, keys...)
+				return v, nil
+			}
+			tx.OnCommit(func(next ent.Committer) ent.Committer {
+				return ent.CommitFunc(func(ctx context.Context, tx *ent.Tx) error {
+					if err := next.Commit(ctx, tx); err != nil {
+						return err
+					}
+					c.Invalidate(ctx, keys...)
+					return nil
+				})
+			})
+			return v, nil
+		})
+	}
+}
+
+// cacheKeysOf returns the keys of the users, the cars and the current owners of the cars in their tenants
+func cacheKeysOf(ctx context.Context, client *ent.Client, userIDs []int, carIDs []int, ownedCarIDs []int) ([]string, error) {
+	var keys []string
+	if len(ownedCarIDs) > 0 {
+		owners, err := client.Car.Query().Where(car.IDIn(ownedCarIDs...)).QueryOwner().IDs(ctx)
+		if err != nil {
+			return nil, err
+		}
+		userIDs = append(userIDs, owners...)
+	}
+	if len(userIDs) > 0 {
+		users, err := client.User.Query().Where(user.IDIn(userIDs...)).Select(user.FieldTenantID).All(ctx)
+		if err != nil {
+			return nil, err
+		}
+		for _, u := range users {
+			keys = append(keys, cache.UserKey(u.TenantID, u.ID))
+		}
+	}
+	if len(carIDs) > 0 {
+		cars, err := client.Car.Query().Where(car.IDIn(carIDs...)).Select(car.FieldTenantID).All(ctx)
+		if err != nil {
+			return nil, err
+		}
+		for _, c := range cars {
+			keys = append(keys, cache.CarKey(c.TenantID, c.ID))
+		}
+	}
+	return keys, nil
+}
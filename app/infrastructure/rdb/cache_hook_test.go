@@ -0,0 +1,76 @@
+package rdb
+
+import (
+	"context"
+	"github.com/jpdel518/go-ent/infrastructure/cache"
+	"github.com/jpdel518/go-ent/tenant"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestCacheHook(t *testing.T) {
+	ts := newTenants(t)
+	rt := cache.NewReadThrough(cache.NewLRUCache(100), time.Second)
+	ts.client.Use(CacheHook(rt))
+	users := cache.NewUserRepository(NewUserRepository(ts.client), rt, time.Hour)
+	cars := cache.NewCarRepository(NewCarRepository(ts.client), rt, time.Hour)
+	hanako := ts.client.User.Create().SetFirstName("Hanako").SetLastName("Sato").SetEmail("hanako@example.com").SaveX(ts.acme)
+
+	carIDs := func(ctx context.Context, id int, want ...int) {
+		t.Helper()
+		u, err := users.GetByID(ctx, id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(u.CarIDs) == 0 && len(want) == 0 {
+			return
+		}
+		if !reflect.DeepEqual(u.CarIDs, want) {
+			t.Errorf("cars of user %d = %v, want %v", id, u.CarIDs, want)
+		}
+	}
+	carIDs(ts.acme, ts.acmeUser.ID, ts.acmeCar.ID)
+	carIDs(ts.acme, hanako.ID)
+
+	// the car changes hands: the previous owner and the new one are invalidated
+	ts.client.Car.UpdateOneID(ts.acmeCar.ID).SetOwnerID(hanako.ID).ExecX(ts.acme)
+	carIDs(ts.acme, ts.acmeUser.ID)
+	carIDs(ts.acme, hanako.ID, ts.acmeCar.ID)
+
+	// the car is given back through the edges of the users
+	ts.client.User.UpdateOneID(hanako.ID).RemoveCars(ts.acmeCar).ExecX(ts.acme)
+	carIDs(ts.acme, hanako.ID)
+	ts.client.User.UpdateOneID(ts.acmeUser.ID).AddCars(ts.acmeCar).ExecX(ts.acme)
+	carIDs(ts.acme, ts.acmeUser.ID, ts.acmeCar.ID)
+	carIDs(ts.acme, hanako.ID)
+
+	// the same in a transaction, once it is committed
+	tx, err := ts.client.Tx(ts.acme)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tx.Car.UpdateOneID(ts.acmeCar.ID).SetOwnerID(hanako.ID).ExecX(ts.acme)
+	if err := tx.Commit(); err != nil {
+		t.Fatal(err)
+	}
+	carIDs(ts.acme, ts.acmeUser.ID)
+	carIDs(ts.acme, hanako.ID, ts.acmeCar.ID)
+
+	// the workers of every tenant invalidate the entries of the tenant of the entity
+	all := tenant.AllTenants(context.Background())
+	if u, err := users.GetByID(ts.globex, ts.globexUser.ID); err != nil || u.LastName != "Suzuki" {
+		t.Fatalf("GetByID = %+v, %v", u, err)
+	}
+	if c, err := cars.GetByID(ts.globex, ts.globexCar.ID); err != nil || c.Name != "Prius" {
+		t.Fatalf("GetByID = %+v, %v", c, err)
+	}
+	ts.client.User.UpdateOneID(ts.globexUser.ID).SetLastName("Tanaka").ExecX(all)
+	ts.client.Car.UpdateOneID(ts.globexCar.ID).SetName("Aqua").ExecX(all)
+	if u, err := users.GetByID(ts.globex, ts.globexUser.ID); err != nil || u.LastName != "Tanaka" {
+		t.Errorf("GetByID after the update of every tenant = %+v, %v, want Tanaka", u, err)
+	}
+	if c, err := cars.GetByID(ts.globex, ts.globexCar.ID); err != nil || c.Name != "Aqua" {
+		t.Errorf("GetByID after the update of every tenant = %+v, %v, want Aqua", c, err)
+	}
+}
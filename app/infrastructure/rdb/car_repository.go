@@ -2,11 +2,12 @@ package rdb
 
 import (
 	"context"
+	"fmt"
 	"github.com/jpdel518/go-ent/domain/model"
 	"github.com/jpdel518/go-ent/domain/repository"
 	"github.com/jpdel518/go-ent/ent"
 	"github.com/jpdel518/go-ent/ent/car"
-	"log"
+	"golang.org/x/exp/slog"
 )
 
 type carRepository struct {
@@ -23,7 +24,7 @@ func (r *carRepository) Fetch(ctx context.Context, num int) ([]*model.Car, error
 	// fetch cars
 	cars, err := r.client.Car.Query().Limit(num).All(ctx)
 	if err != nil {
-		log.Printf("failed fetching cars: %v", err)
+		slog.ErrorCtx(ctx, "failed fetching cars", "err", err)
 		return res, err
 	}
 
@@ -43,8 +44,8 @@ func (r *carRepository) GetByID(ctx context.Context, id int) (*model.Car, error)
 	// get car
 	c, err := r.client.Car.Get(ctx, id)
 	if err != nil {
-		log.Printf("failed getbyid car: %v", err)
-		return nil, err
+		slog.ErrorCtx(ctx, "failed getbyid car", "err", err)
+		return nil, toRepositoryError(err, nil)
 	}
 
 	// ent.Car -> model.Car
@@ -56,6 +57,28 @@ func (r *carRepository) GetByID(ctx context.Context, id int) (*model.Car, error)
 	}, nil
 }
 
+func (r *carRepository) FetchByIDs(ctx context.Context, ids []int) ([]*model.Car, error) {
+	res := make([]*model.Car, 0)
+
+	// fetch cars
+	cars, err := r.client.Car.Query().Where(car.IDIn(ids...)).All(ctx)
+	if err != nil {
+		slog.ErrorCtx(ctx, "failed fetching cars by ids", "err", err)
+		return res, err
+	}
+
+	// ent.Car -> model.Car
+	for _, c := range cars {
+		res = append(res, &model.Car{
+			ID:           c.ID,
+			Name:         c.Name,
+			Model:        c.Model,
+			RegisteredAt: c.RegisteredAt,
+		})
+	}
+	return res, nil
+}
+
 func (r *carRepository) Create(ctx context.Context, u *model.Car) error {
 	data, err := r.client.Car.Create().
 		SetName(u.Name).
@@ -64,11 +87,13 @@ func (r *carRepository) Create(ctx context.Context, u *model.Car) error {
 		Save(ctx)
 
 	if err != nil {
-		log.Printf("failed creating car: %v", err)
+		slog.ErrorCtx(ctx, "failed creating car", "err", err)
+		return err
 	}
-	log.Printf("car was created: %v", data)
+	slog.InfoCtx(ctx, "car was created", "id", data.ID)
+	u.ID = data.ID
 
-	return err
+	return nil
 }
 
 func (r *carRepository) Update(ctx context.Context, u *model.Car) error {
@@ -80,13 +105,17 @@ func (r *carRepository) Update(ctx context.Context, u *model.Car) error {
 		Save(ctx)
 
 	if err != nil {
-		log.Printf("failed updating car: %v", err)
+		slog.ErrorCtx(ctx, "failed updating car", "err", err)
+		return err
+	}
+	if data == 0 {
+		return &repositoryError{err: fmt.Errorf("car %d does not exist", u.ID), target: repository.ErrNotFound}
 	}
-	log.Printf("car was updated: %v", data)
+	slog.InfoCtx(ctx, "car was updated", "id", u.ID, "affected", data)
 
-	return err
+	return nil
 }
 
 func (r *carRepository) Delete(ctx context.Context, id int) error {
-	return r.client.Car.DeleteOneID(id).Exec(ctx)
+	return toRepositoryError(r.client.Car.DeleteOneID(id).Exec(ctx), nil)
 }
@@ -0,0 +1,103 @@
+package rdb
+
+import (
+	"context"
+	"github.com/jpdel518/go-ent/domain/model"
+	"github.com/jpdel518/go-ent/domain/repository"
+	"github.com/jpdel518/go-ent/ent"
+	"github.com/jpdel518/go-ent/tenant"
+	"sync"
+	"time"
+)
+
+// changeEntities maps the ent types to the entities which are published to the change feed
+var changeEntities = map[string]string{
+	ent.TypeUser:  model.ChangeEntityUser,
+	ent.TypeCar:   model.ChangeEntityCar,
+	ent.TypeGroup: model.ChangeEntityGroup,
+}
+
+// changeMutation is implemented by the generated mutations
+type changeMutation interface {
+	ent.Mutation
+	ID() (int, bool)
+	IDs(ctx context.Context) ([]int, error)
+	Tx() (*ent.Tx, error)
+}
+
+// ChangeHook publishes the users, cars and groups written through the client to the feed.
+// Changes made in a transaction are published once it is committed.
+func ChangeHook(feed repository.ChangeFeed) ent.Hook {
+	// changes of the transactions being committed
+	var pending sync.Map
+	return func(next ent.Mutator) ent.Mutator {
+		return ent.MutateFunc(func(ctx context.Context, m ent.Mutation) (ent.Value, error) {
+			entity, ok := changeEntities[m.Type()]
+			cm, isChange := m.(changeMutation)
+			if !ok || !isChange {
+				return next.Mutate(ctx, m)
+			}
+
+			// the ids have to be read before they are deleted
+			var ids []int
+			if !m.Op().Is(ent.OpCreate) {
+				var err error
+				ids, err = cm.IDs(ctx)
+				if err != nil {
+					return nil, err
+				}
+			}
+			v, err := next.Mutate(ctx, m)
+			if err != nil {
+				return v, err
+			}
+			if id, exists := cm.ID(); exists && m.Op().Is(ent.OpCreate) {
+				ids = []int{id}
+			}
+
+			op := model.ChangeOpUpdate
+			switch {
+			case m.Op().Is(ent.OpCreate):
+				op = model.ChangeOpCreate
+			case m.Op().Is(ent.OpDelete | ent.OpDeleteOne):
+				op = model.ChangeOpDelete
+			}
+			now := time.Now()
+			tenantID, _ := tenant.FromContext(ctx)
+			changes := make([]*model.Change, 0, len(ids))
+			for _, id := range ids {
+				changes = append(changes, &model.Change{Entity: entity, EntityID: id, Op: op, At: now, TenantID: tenantID})
+			}
+
+			tx, err := cm.Tx()
+			if err != nil {
+				publishChanges(feed, changes)
+				return v, nil
+			}
+			tx.OnCommit(func(next ent.Committer) ent.Committer {
+				return ent.CommitFunc(func(ctx context.Context, tx *ent.Tx) error {
+					// the commit hooks finish in reverse order, so the first one publishes all the changes of the transaction
+					list, loaded := pending.LoadOrStore(tx, &[]*model.Change{})
+					changesOfTx := list.(*[]*model.Change)
+					*changesOfTx = append(*changesOfTx, changes...)
+					if loaded {
+						return next.Commit(ctx, tx)
+					}
+					defer pending.Delete(tx)
+					if err := next.Commit(ctx, tx); err != nil {
+						return err
+					}
+					publishChanges(feed, *changesOfTx)
+					return nil
+				})
+			})
+			return v, nil
+		})
+	}
+}
+
+func publishChanges(feed repository.ChangeFeed, changes []*model.Change) {
+	for _, c := range changes {
+		feed.Publish(c)
+	}
+}
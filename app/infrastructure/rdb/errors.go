@@ -0,0 +1,38 @@
+package rdb
+
+import (
+	"github.com/jpdel518/go-ent/domain/repository"
+	"github.com/jpdel518/go-ent/ent"
+)
+
+// repositoryError matches the error of the repository package with errors.Is,
+// and unwraps to the error of ent so that ent.IsNotFound and ent.IsConstraintError still tell it
+type repositoryError struct {
+	err    error
+	target error
+}
+
+func (e *repositoryError) Error() string {
+	return e.err.Error()
+}
+
+func (e *repositoryError) Unwrap() error {
+	return e.err
+}
+
+func (e *repositoryError) Is(target error) bool {
+	return target == e.target
+}
+
+// toRepositoryError tells the not found errors of ent, and the constraint errors as conflict when it is not nil
+func toRepositoryError(err error, conflict error) error {
+	switch {
+	case err == nil:
+		return nil
+	case ent.IsNotFound(err):
+		return &repositoryError{err: err, target: repository.ErrNotFound}
+	case conflict != nil && ent.IsConstraintError(err):
+		return &repositoryError{err: err, target: conflict}
+	}
+	return err
+}
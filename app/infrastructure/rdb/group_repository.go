@@ -0,0 +1,141 @@
+package rdb
+
+import (
+	"context"
+	"github.com/jpdel518/go-ent/domain/event"
+	"github.com/jpdel518/go-ent/domain/model"
+	"github.com/jpdel518/go-ent/domain/repository"
+	"github.com/jpdel518/go-ent/ent"
+	"github.com/jpdel518/go-ent/ent/group"
+	"golang.org/x/exp/slog"
+)
+
+type groupRepository struct {
+	client *ent.Client
+}
+
+func NewGroupRepository(client *ent.Client) repository.GroupRepository {
+	return &groupRepository{client: client}
+}
+
+func (r *groupRepository) Fetch(ctx context.Context, num int) ([]*model.Group, error) {
+	res := make([]*model.Group, 0)
+
+	// fetch groups
+	groups, err := r.client.Group.Query().
+		Order(ent.Asc(group.FieldID)).
+		Limit(num).
+		WithUsers().
+		All(ctx)
+	if err != nil {
+		slog.ErrorCtx(ctx, "failed fetching groups", "err", err)
+		return res, err
+	}
+
+	for _, g := range groups {
+		res = append(res, toGroupModel(g))
+	}
+	return res, nil
+}
+
+func (r *groupRepository) GetByID(ctx context.Context, id int) (*model.Group, error) {
+	g, err := r.client.Group.Query().
+		Where(group.ID(id)).
+		WithUsers().
+		Only(ctx)
+	if err != nil {
+		slog.ErrorCtx(ctx, "failed getbyid group", "err", err)
+		return nil, toRepositoryError(err, nil)
+	}
+	return toGroupModel(g), nil
+}
+
+func (r *groupRepository) Create(ctx context.Context, g *model.Group) error {
+	var data *ent.Group
+	err := withTx(ctx, r.client, func(tx *ent.Tx) error {
+		var err error
+		data, err = tx.Group.Create().
+			SetName(g.Name).
+			AddUserIDs(g.UserIDs...).
+			Save(ctx)
+		if err != nil {
+			return err
+		}
+		return recordUsersAdded(ctx, tx, data.ID, nil, g.UserIDs)
+	})
+	if err != nil {
+		slog.ErrorCtx(ctx, "failed creating group", "err", err)
+		return err
+	}
+	slog.InfoCtx(ctx, "group was created", "id", data.ID)
+	g.ID = data.ID
+
+	return nil
+}
+
+func (r *groupRepository) Update(ctx context.Context, g *model.Group) error {
+	var data *ent.Group
+	err := withTx(ctx, r.client, func(tx *ent.Tx) error {
+		members, err := tx.Group.Query().Where(group.ID(g.ID)).QueryUsers().IDs(ctx)
+		if err != nil {
+			return err
+		}
+		data, err = tx.Group.UpdateOneID(g.ID).
+			SetName(g.Name).
+			ClearUsers().
+			AddUserIDs(g.UserIDs...).
+			Save(ctx)
+		if err != nil {
+			return err
+		}
+		return recordUsersAdded(ctx, tx, g.ID, members, g.UserIDs)
+	})
+	if err != nil {
+		slog.ErrorCtx(ctx, "failed updating group", "err", err)
+		return err
+	}
+	slog.InfoCtx(ctx, "group was updated", "id", data.ID)
+
+	return nil
+}
+
+func (r *groupRepository) Delete(ctx context.Context, id int) error {
+	return r.client.Group.DeleteOneID(id).Exec(ctx)
+}
+
+// recordUsersAdded records a GroupUserAdded event for every user who was not a member of the group
+func recordUsersAdded(ctx context.Context, tx *ent.Tx, groupID int, members []int, userIDs []int) error {
+	added := make(map[int]bool, len(members)+len(userIDs))
+	for _, id := range members {
+		added[id] = true
+	}
+	var events []*event.Event
+	for _, id := range userIDs {
+		if added[id] {
+			continue
+		}
+		added[id] = true
+		e, err := event.New(event.GroupUserAdded, event.AggregateGroup, groupID, event.GroupUserAddedPayload{
+			GroupID: groupID,
+			UserID:  id,
+		})
+		if err != nil {
+			return err
+		}
+		events = append(events, e)
+	}
+	return recordEvents(ctx, tx, events...)
+}
+
+// ent.Group -> model.Group
+func toGroupModel(g *ent.Group) *model.Group {
+	userIDs := make([]int, 0, len(g.Edges.Users))
+	for _, u := range g.Edges.Users {
+		userIDs = append(userIDs, u.ID)
+	}
+	return &model.Group{
+		ID:      g.ID,
+		Name:    g.Name,
+		UserIDs: userIDs,
+	}
+}
@@ -0,0 +1,84 @@
+package rdb
+
+import (
+	atlas "ariga.io/atlas/sql/migrate"
+	"context"
+	entsql "entgo.io/ent/dialect/sql"
+	"fmt"
+	"github.com/jpdel518/go-ent/domain/repository"
+)
+
+type databaseChecker struct {
+	drv *entsql.Driver
+}
+
+// NewDatabaseChecker pings the database through the ent driver
+func NewDatabaseChecker(drv *entsql.Driver) repository.HealthChecker {
+	return &databaseChecker{drv: drv}
+}
+
+func (c *databaseChecker) Name() string {
+	return "database"
+}
+
+func (c *databaseChecker) Check(ctx context.Context) error {
+	return c.drv.DB().PingContext(ctx)
+}
+
+type migrationChecker struct {
+	drv *entsql.Driver
+	dir string
+}
+
+// NewMigrationChecker checks that the database is at the latest revision in the migration directory.
+// The revision is the one recorded by `atlas migrate apply`, so it works only where the versioned migrations are used.
+func NewMigrationChecker(drv *entsql.Driver, dir string) repository.HealthChecker {
+	return &migrationChecker{drv: drv, dir: dir}
+}
+
+func (c *migrationChecker) Name() string {
+	return "migrations"
+}
+
+func (c *migrationChecker) Check(ctx context.Context) error {
+	expected, err := c.expectedVersion()
+	if err != nil {
+		return err
+	}
+
+	rows, err := c.drv.DB().QueryContext(ctx, "SELECT `version` FROM `atlas_schema_revisions` ORDER BY `version` DESC LIMIT 1")
+	if err != nil {
+		return fmt.Errorf("failed querying schema revision: %w", err)
+	}
+	defer rows.Close()
+	if !rows.Next() {
+		if err := rows.Err(); err != nil {
+			return fmt.Errorf("failed querying schema revision: %w", err)
+		}
+		return fmt.Errorf("no migration is applied, expected version %s", expected)
+	}
+	var version string
+	if err := rows.Scan(&version); err != nil {
+		return fmt.Errorf("failed querying schema revision: %w", err)
+	}
+	if version != expected {
+		return fmt.Errorf("schema is at version %s, expected version %s", version, expected)
+	}
+	return nil
+}
+
+// expectedVersion returns the version of the last migration file
+func (c *migrationChecker) expectedVersion() (string, error) {
+	dir, err := atlas.NewLocalDir(c.dir)
+	if err != nil {
+		return "", fmt.Errorf("failed opening migration directory: %w", err)
+	}
+	files, err := dir.Files()
+	if err != nil {
+		return "", fmt.Errorf("failed reading migration directory: %w", err)
+	}
+	if len(files) == 0 {
+		return "", fmt.Errorf("no migration file in %s", c.dir)
+	}
+	return files[len(files)-1].Version(), nil
+}
@@ -0,0 +1,99 @@
+package rdb
+
+import (
+	"context"
+	"github.com/jpdel518/go-ent/domain/model"
+	"github.com/jpdel518/go-ent/domain/repository"
+	"github.com/jpdel518/go-ent/ent"
+	"github.com/jpdel518/go-ent/ent/idempotencykey"
+	"golang.org/x/exp/slog"
+	"time"
+)
+
+// reserveAttempts bounds the retries of Reserve when another request takes or frees the key at the same time
+const reserveAttempts = 3
+
+type idempotencyRepository struct {
+	client *ent.Client
+}
+
+func NewIdempotencyRepository(client *ent.Client) repository.IdempotencyRepository {
+	return &idempotencyRepository{client: client}
+}
+
+func (r *idempotencyRepository) Reserve(ctx context.Context, k *model.IdempotencyKey) (*model.IdempotencyKey, bool, error) {
+	var err error
+	for i := 0; i < reserveAttempts; i++ {
+		var data *ent.IdempotencyKey
+		data, err = r.client.IdempotencyKey.Create().
+			SetKey(k.Key).
+			SetScope(k.Scope).
+			SetRoute(k.Route).
+			SetFingerprint(k.Fingerprint).
+			SetExpiresAt(k.ExpiresAt).
+			Save(ctx)
+		if err == nil {
+			return toIdempotencyKeyModel(data), true, nil
+		}
+		if !ent.IsConstraintError(err) {
+			break
+		}
+
+		// the unique index says the key is taken
+		data, err = r.client.IdempotencyKey.Query().
+			Where(idempotencykey.Scope(k.Scope), idempotencykey.Key(k.Key)).
+			Only(ctx)
+		if ent.IsNotFound(err) {
+			continue
+		}
+		if err != nil {
+			break
+		}
+		existing := toIdempotencyKeyModel(data)
+		if !existing.Expired(time.Now()) {
+			return existing, false, nil
+		}
+		// an expired key which is not purged yet is free
+		if err = r.client.IdempotencyKey.DeleteOneID(existing.ID).Exec(ctx); err != nil && !ent.IsNotFound(err) {
+			break
+		}
+	}
+	slog.ErrorCtx(ctx, "failed reserving idempotency key", "err", err)
+	return nil, false, err
+}
+
+func (r *idempotencyRepository) Complete(ctx context.Context, id int, code int, contentType string, body []byte) error {
+	return r.client.IdempotencyKey.UpdateOneID(id).
+		SetStatus(idempotencykey.StatusCompleted).
+		SetResponseCode(code).
+		SetResponseContentType(contentType).
+		SetResponseBody(body).
+		Exec(ctx)
+}
+
+func (r *idempotencyRepository) Delete(ctx context.Context, id int) error {
+	return r.client.IdempotencyKey.DeleteOneID(id).Exec(ctx)
+}
+
+func (r *idempotencyRepository) DeleteExpired(ctx context.Context, now time.Time) (int, error) {
+	return r.client.IdempotencyKey.Delete().
+		Where(idempotencykey.ExpiresAtLTE(now)).
+		Exec(ctx)
+}
+
+// ent.IdempotencyKey -> model.IdempotencyKey
+func toIdempotencyKeyModel(k *ent.IdempotencyKey) *model.IdempotencyKey {
+	return &model.IdempotencyKey{
+		ID:                  k.ID,
+		Key:                 k.Key,
+		Scope:               k.Scope,
+		Route:               k.Route,
+		Fingerprint:         k.Fingerprint,
+		Status:              model.IdempotencyKeyStatus(k.Status),
+		ResponseCode:        k.ResponseCode,
+		ResponseContentType: k.ResponseContentType,
+		ResponseBody:        k.ResponseBody,
+		ExpiresAt:           k.ExpiresAt,
+		CreatedAt:           k.CreatedAt,
+	}
+}
@@ -0,0 +1,143 @@
+package rdb
+
+import (
+	"context"
+	"github.com/jpdel518/go-ent/domain/model"
+	"github.com/jpdel518/go-ent/domain/repository"
+	"github.com/jpdel518/go-ent/ent"
+	"github.com/jpdel518/go-ent/ent/job"
+	"golang.org/x/exp/slog"
+	"time"
+)
+
+type jobRepository struct {
+	client *ent.Client
+}
+
+func NewJobRepository(client *ent.Client) repository.JobRepository {
+	return &jobRepository{client: client}
+}
+
+func (r *jobRepository) GetByID(ctx context.Context, id int) (*model.Job, error) {
+	j, err := r.client.Job.Get(ctx, id)
+	if err != nil {
+		slog.ErrorCtx(ctx, "failed getbyid job", "err", err)
+		return nil, err
+	}
+	return toJobModel(j), nil
+}
+
+func (r *jobRepository) Create(ctx context.Context, j *model.Job) (*model.Job, error) {
+	data, err := r.client.Job.Create().
+		SetType(j.Type).
+		SetParams(j.Params).
+		Save(ctx)
+	if err != nil {
+		slog.ErrorCtx(ctx, "failed creating job", "err", err)
+		return nil, err
+	}
+	slog.InfoCtx(ctx, "job was created", "id", data.ID)
+
+	return toJobModel(data), nil
+}
+
+func (r *jobRepository) Claim(ctx context.Context) (*model.Job, error) {
+	for {
+		// find the oldest queued job
+		id, err := r.client.Job.Query().
+			Where(job.StatusEQ(job.StatusQueued)).
+			Order(ent.Asc(job.FieldID)).
+			FirstID(ctx)
+		if ent.IsNotFound(err) {
+			return nil, nil
+		}
+		if err != nil {
+			slog.ErrorCtx(ctx, "failed finding queued job", "err", err)
+			return nil, err
+		}
+
+		// another worker may have claimed it in the meantime
+		n, err := r.client.Job.Update().
+			Where(job.ID(id), job.StatusEQ(job.StatusQueued)).
+			SetStatus(job.StatusRunning).
+			SetStartedAt(time.Now()).
+			Save(ctx)
+		if err != nil {
+			slog.ErrorCtx(ctx, "failed claiming job", "err", err)
+			return nil, err
+		}
+		if n == 1 {
+			return r.GetByID(ctx, id)
+		}
+	}
+}
+
+func (r *jobRepository) UpdateProgress(ctx context.Context, id int, progress int) error {
+	return r.client.Job.Update().
+		Where(job.ID(id), job.StatusEQ(job.StatusRunning)).
+		SetProgress(progress).
+		Exec(ctx)
+}
+
+func (r *jobRepository) Finish(ctx context.Context, j *model.Job) (bool, error) {
+	update := r.client.Job.Update().
+		Where(job.ID(j.ID), job.StatusEQ(job.StatusRunning)).
+		SetStatus(job.Status(j.Status)).
+		SetProgress(j.Progress).
+		SetErrorMessage(j.Error).
+		SetErrorReport(j.ErrorReport).
+		SetFinishedAt(time.Now())
+	if j.Result != nil {
+		update = update.SetResult(j.Result)
+	}
+	n, err := update.Save(ctx)
+	if err != nil {
+		slog.ErrorCtx(ctx, "failed finishing job", "err", err)
+		return false, err
+	}
+	return n == 1, nil
+}
+
+func (r *jobRepository) Cancel(ctx context.Context, id int) (bool, error) {
+	n, err := r.client.Job.Update().
+		Where(job.ID(id), job.StatusIn(job.StatusQueued, job.StatusRunning)).
+		SetStatus(job.StatusCanceled).
+		SetFinishedAt(time.Now()).
+		Save(ctx)
+	if err != nil {
+		slog.ErrorCtx(ctx, "failed canceling job", "err", err)
+		return false, err
+	}
+	return n == 1, nil
+}
+
+func (r *jobRepository) Requeue(ctx context.Context, ids ...int) (int, error) {
+	update := r.client.Job.Update().
+		Where(job.StatusEQ(job.StatusRunning))
+	if len(ids) > 0 {
+		update = update.Where(job.IDIn(ids...))
+	}
+	return update.
+		SetStatus(job.StatusQueued).
+		SetProgress(0).
+		ClearStartedAt().
+		Save(ctx)
+}
+
+// ent.Job -> model.Job
+func toJobModel(j *ent.Job) *model.Job {
+	return &model.Job{
+		ID:          j.ID,
+		TenantID:    j.TenantID,
+		Type:        j.Type,
+		Status:      model.JobStatus(j.Status),
+		Progress:    j.Progress,
+		Params:      j.Params,
+		Result:      j.Result,
+		Error:       j.ErrorMessage,
+		ErrorReport: j.ErrorReport,
+		CreatedAt:   j.CreatedAt,
+		StartedAt:   j.StartedAt,
+		FinishedAt:  j.FinishedAt,
+	}
+}
@@ -0,0 +1,106 @@
+package rdb
+
+import (
+	"context"
+	"database/sql"
+	"entgo.io/ent/dialect"
+	"fmt"
+	"github.com/jpdel518/go-ent/ent"
+	"github.com/jpdel518/go-ent/metrics"
+	"time"
+)
+
+// metricsDriver records the latency of the statements sent to the database
+type metricsDriver struct {
+	dialect.Driver
+}
+
+// NewMetricsDriver wraps the driver to record every query and exec, including the ones in transactions
+func NewMetricsDriver(drv dialect.Driver) dialect.Driver {
+	return &metricsDriver{Driver: drv}
+}
+
+func (d *metricsDriver) Exec(ctx context.Context, query string, args, v interface{}) error {
+	start := time.Now()
+	err := d.Driver.Exec(ctx, query, args, v)
+	metrics.ObserveDBOperation("exec", err, time.Since(start))
+	return err
+}
+
+func (d *metricsDriver) Query(ctx context.Context, query string, args, v interface{}) error {
+	start := time.Now()
+	err := d.Driver.Query(ctx, query, args, v)
+	metrics.ObserveDBOperation("query", err, time.Since(start))
+	return err
+}
+
+func (d *metricsDriver) Tx(ctx context.Context) (dialect.Tx, error) {
+	start := time.Now()
+	tx, err := d.Driver.Tx(ctx)
+	metrics.ObserveDBOperation("begin", err, time.Since(start))
+	if err != nil {
+		return nil, err
+	}
+	return &metricsTx{Tx: tx}, nil
+}
+
+// BeginTx is used by ent.Client.BeginTx
+func (d *metricsDriver) BeginTx(ctx context.Context, opts *sql.TxOptions) (dialect.Tx, error) {
+	drv, ok := d.Driver.(interface {
+		BeginTx(context.Context, *sql.TxOptions) (dialect.Tx, error)
+	})
+	if !ok {
+		return nil, fmt.Errorf("driver.BeginTx is not supported")
+	}
+	start := time.Now()
+	tx, err := drv.BeginTx(ctx, opts)
+	metrics.ObserveDBOperation("begin", err, time.Since(start))
+	if err != nil {
+		return nil, err
+	}
+	return &metricsTx{Tx: tx}, nil
+}
+
+type metricsTx struct {
+	dialect.Tx
+}
+
+func (tx *metricsTx) Exec(ctx context.Context, query string, args, v interface{}) error {
+	start := time.Now()
+	err := tx.Tx.Exec(ctx, query, args, v)
+	metrics.ObserveDBOperation("exec", err, time.Since(start))
+	return err
+}
+
+func (tx *metricsTx) Query(ctx context.Context, query string, args, v interface{}) error {
+	start := time.Now()
+	err := tx.Tx.Query(ctx, query, args, v)
+	metrics.ObserveDBOperation("query", err, time.Since(start))
+	return err
+}
+
+func (tx *metricsTx) Commit() error {
+	start := time.Now()
+	err := tx.Tx.Commit()
+	metrics.ObserveDBOperation("commit", err, time.Since(start))
+	return err
+}
+
+func (tx *metricsTx) Rollback() error {
+	start := time.Now()
+	err := tx.Tx.Rollback()
+	metrics.ObserveDBOperation("rollback", err, time.Since(start))
+	return err
+}
+
+// MetricsHook records the latency of the mutations of every ent type
+func MetricsHook() ent.Hook {
+	return func(next ent.Mutator) ent.Mutator {
+		return ent.MutateFunc(func(ctx context.Context, m ent.Mutation) (ent.Value, error) {
+			start := time.Now()
+			v, err := next.Mutate(ctx, m)
+			metrics.ObserveEntMutation(m.Type(), m.Op().String(), err, time.Since(start))
+			return v, err
+		})
+	}
+}
@@ -0,0 +1,28 @@
+package mysql
+
+import (
+	gomysql "github.com/go-sql-driver/mysql"
+	"net/url"
+	"testing"
+)
+
+func TestConfigEscapesCredentials(t *testing.T) {
+	c := Config{Host: "mysql", Port: 3306, Name: "sample_db", User: "sample user", Password: "p@ss:w/rd?&#"}
+
+	dsn, err := gomysql.ParseDSN(c.DSN())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if dsn.User != c.User || dsn.Passwd != c.Password || dsn.Addr != "mysql:3306" || dsn.DBName != c.Name || !dsn.ParseTime {
+		t.Errorf("DSN = %+v, want the config", dsn)
+	}
+
+	u, err := url.Parse(c.atlasURL())
+	if err != nil {
+		t.Fatal(err)
+	}
+	password, _ := u.User.Password()
+	if u.User.Username() != c.User || password != c.Password || u.Host != "mysql:3306" || u.Path != "/sample_db" {
+		t.Errorf("atlas URL = %s, want the config", u.Redacted())
+	}
+}
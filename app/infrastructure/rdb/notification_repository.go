@@ -0,0 +1,140 @@
+package rdb
+
+import (
+	"context"
+	"github.com/jpdel518/go-ent/domain/model"
+	"github.com/jpdel518/go-ent/domain/repository"
+	"github.com/jpdel518/go-ent/ent"
+	"github.com/jpdel518/go-ent/ent/notification"
+	"golang.org/x/exp/slog"
+	"time"
+)
+
+type notificationRepository struct {
+	client *ent.Client
+}
+
+func NewNotificationRepository(client *ent.Client) repository.NotificationRepository {
+	return &notificationRepository{client: client}
+}
+
+// GetPreferences reads the preferences from the columns of the user
+func (r *notificationRepository) GetPreferences(ctx context.Context, userID int) (*model.NotificationPreferences, error) {
+	u, err := r.client.User.Get(ctx, userID)
+	if err != nil {
+		slog.ErrorCtx(ctx, "failed getting notification preferences", "user_id", userID, "err", err)
+		return nil, toRepositoryError(err, nil)
+	}
+	return &model.NotificationPreferences{
+		UserID:      u.ID,
+		Language:    u.Language,
+		CarAssigned: u.NotifyCarAssigned,
+		GroupAdded:  u.NotifyGroupAdded,
+	}, nil
+}
+
+func (r *notificationRepository) UpdatePreferences(ctx context.Context, p *model.NotificationPreferences) error {
+	err := r.client.User.UpdateOneID(p.UserID).
+		SetLanguage(p.Language).
+		SetNotifyCarAssigned(p.CarAssigned).
+		SetNotifyGroupAdded(p.GroupAdded).
+		Exec(ctx)
+	if err != nil {
+		slog.ErrorCtx(ctx, "failed updating notification preferences", "user_id", p.UserID, "err", err)
+		return toRepositoryError(err, nil)
+	}
+	slog.InfoCtx(ctx, "notification preferences were updated", "user_id", p.UserID)
+	return nil
+}
+
+func (r *notificationRepository) Create(ctx context.Context, n *model.Notification) error {
+	// the relay may send the same event again
+	exists, err := r.client.Notification.Query().
+		Where(
+			notification.EventID(n.EventID),
+			notification.UserID(n.UserID),
+			notification.Kind(string(n.Kind)),
+		).
+		Exist(ctx)
+	if err != nil || exists {
+		return err
+	}
+	create := r.client.Notification.Create().
+		SetUserID(n.UserID).
+		SetEventID(n.EventID).
+		SetKind(string(n.Kind)).
+		SetTo(n.Mail.To).
+		SetSubject(n.Mail.Subject).
+		SetText(n.Mail.Text)
+	if n.Mail.HTML != "" {
+		create = create.SetHTML(n.Mail.HTML)
+	}
+	data, err := create.Save(ctx)
+	if ent.IsConstraintError(err) {
+		return nil
+	}
+	if err != nil {
+		slog.ErrorCtx(ctx, "failed creating notification", "err", err)
+		return err
+	}
+	n.ID = data.ID
+	return nil
+}
+
+func (r *notificationRepository) FetchDue(ctx context.Context, num int) ([]*model.Notification, error) {
+	res := make([]*model.Notification, 0)
+
+	notifications, err := r.client.Notification.Query().
+		Where(notification.StatusEQ(notification.StatusPending), notification.NextAttemptAtLTE(time.Now())).
+		Order(ent.Asc(notification.FieldID)).
+		Limit(num).
+		All(ctx)
+	if err != nil {
+		slog.ErrorCtx(ctx, "failed fetching notifications", "err", err)
+		return res, err
+	}
+
+	// ent.Notification -> model.Notification
+	for _, n := range notifications {
+		res = append(res, &model.Notification{
+			ID:      n.ID,
+			UserID:  n.UserID,
+			EventID: n.EventID,
+			Kind:    model.NotificationKind(n.Kind),
+			Mail: model.Mail{
+				To:      n.To,
+				Subject: n.Subject,
+				Text:    n.Text,
+				HTML:    n.HTML,
+			},
+			Status:        model.NotificationStatus(n.Status),
+			Attempts:      n.Attempts,
+			LastError:     n.LastError,
+			NextAttemptAt: n.NextAttemptAt,
+			SentAt:        n.SentAt,
+			CreatedAt:     n.CreatedAt,
+		})
+	}
+	return res, nil
+}
+
+func (r *notificationRepository) MarkSent(ctx context.Context, id int) error {
+	return r.client.Notification.UpdateOneID(id).
+		SetStatus(notification.StatusSent).
+		AddAttempts(1).
+		ClearLastError().
+		SetSentAt(time.Now()).
+		Exec(ctx)
+}
+
+func (r *notificationRepository) MarkAttemptFailed(ctx context.Context, id int, cause error, retryAt *time.Time) error {
+	update := r.client.Notification.UpdateOneID(id).
+		AddAttempts(1).
+		SetLastError(cause.Error())
+	if retryAt == nil {
+		update = update.SetStatus(notification.StatusFailed)
+	} else {
+		update = update.SetNextAttemptAt(*retryAt)
+	}
+	return update.Exec(ctx)
+}
@@ -0,0 +1,127 @@
+package rdb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/jpdel518/go-ent/domain/event"
+	"github.com/jpdel518/go-ent/domain/model"
+	"github.com/jpdel518/go-ent/domain/repository"
+	"github.com/jpdel518/go-ent/ent"
+	"github.com/jpdel518/go-ent/ent/outboxevent"
+	"github.com/jpdel518/go-ent/tenant"
+	"testing"
+	"time"
+)
+
+func TestNotificationRepository(t *testing.T) {
+	ts := newTenants(t)
+	notifications := NewNotificationRepository(ts.client)
+
+	// the users are notified of everything by default
+	p, err := notifications.GetPreferences(ts.acme, ts.acmeUser.ID)
+	if err != nil || *p != (model.NotificationPreferences{UserID: ts.acmeUser.ID, CarAssigned: true, GroupAdded: true}) {
+		t.Errorf("GetPreferences = %+v, %v, want the defaults", p, err)
+	}
+	updated := &model.NotificationPreferences{UserID: ts.acmeUser.ID, Language: model.LanguageEnglish, CarAssigned: true}
+	if err := notifications.UpdatePreferences(ts.acme, updated); err != nil {
+		t.Fatal(err)
+	}
+	if p, err := notifications.GetPreferences(ts.acme, ts.acmeUser.ID); err != nil || *p != *updated {
+		t.Errorf("GetPreferences after the update = %+v, %v, want %+v", p, err, updated)
+	}
+	if _, err := notifications.GetPreferences(ts.acme, ts.globexUser.ID); !errors.Is(err, repository.ErrNotFound) {
+		t.Errorf("GetPreferences of the user of globex = %v, want ErrNotFound", err)
+	}
+	other := &model.NotificationPreferences{UserID: ts.globexUser.ID}
+	if err := notifications.UpdatePreferences(ts.acme, other); !errors.Is(err, repository.ErrNotFound) {
+		t.Errorf("UpdatePreferences of the user of globex = %v, want ErrNotFound", err)
+	}
+
+	// the same event is queued once for the user and the kind
+	welcome := func() *model.Notification {
+		return &model.Notification{
+			UserID:  ts.acmeUser.ID,
+			EventID: 1,
+			Kind:    model.NotificationWelcome,
+			Mail:    model.Mail{To: "taro@example.com", Subject: "Welcome", Text: "Hello", HTML: "<p>Hello</p>"},
+		}
+	}
+	first := welcome()
+	if err := notifications.Create(ts.acme, first); err != nil || first.ID == 0 {
+		t.Fatalf("Create = %v, id %d", err, first.ID)
+	}
+	if err := notifications.Create(ts.acme, welcome()); err != nil {
+		t.Errorf("Create again = %v, want it kept as it is", err)
+	}
+	second := &model.Notification{UserID: ts.acmeUser.ID, EventID: 2, Kind: model.NotificationCarAssigned, Mail: model.Mail{To: "taro@example.com", Subject: "Car", Text: "Car"}}
+	if err := notifications.Create(ts.acme, second); err != nil {
+		t.Fatal(err)
+	}
+
+	all := tenant.AllTenants(context.Background())
+	due, err := notifications.FetchDue(all, 10)
+	if err != nil || len(due) != 2 || due[0].ID != first.ID || due[0].Mail != welcome().Mail {
+		t.Fatalf("FetchDue = %+v, %v, want both in order", due, err)
+	}
+	if err := notifications.MarkSent(all, first.ID); err != nil {
+		t.Fatal(err)
+	}
+	retryAt := time.Now().Add(time.Minute)
+	if err := notifications.MarkAttemptFailed(all, second.ID, errors.New("connection refused"), &retryAt); err != nil {
+		t.Fatal(err)
+	}
+	if due, err := notifications.FetchDue(all, 10); err != nil || len(due) != 0 {
+		t.Errorf("FetchDue after sending and failing = %+v, %v, want none", due, err)
+	}
+	n := ts.client.Notification.GetX(all, second.ID)
+	if n.Attempts != 1 || n.LastError != "connection refused" || n.Status != "pending" {
+		t.Errorf("failed notification = %+v, want pending to be retried", n)
+	}
+	if err := notifications.MarkAttemptFailed(all, second.ID, errors.New("connection refused"), nil); err != nil {
+		t.Fatal(err)
+	}
+	if n := ts.client.Notification.GetX(all, second.ID); n.Status != "failed" || n.Attempts != 2 {
+		t.Errorf("given up notification = %+v, want failed", n)
+	}
+}
+
+func TestGroupUserAddedEvents(t *testing.T) {
+	ts := newTenants(t)
+	groups := NewGroupRepository(ts.client)
+	other := ts.client.User.Create().SetFirstName("Hanako").SetLastName("Sato").SetEmail("hanako@example.com").SaveX(ts.acme)
+
+	g := &model.Group{Name: "drivers", UserIDs: []int{ts.acmeUser.ID}}
+	if err := groups.Create(ts.acme, g); err != nil {
+		t.Fatal(err)
+	}
+	// only the new member is added by the update
+	g.UserIDs = []int{ts.acmeUser.ID, other.ID}
+	if err := groups.Update(ts.acme, g); err != nil {
+		t.Fatal(err)
+	}
+
+	events, err := ts.client.OutboxEvent.Query().
+		Where(outboxevent.EventType(event.GroupUserAdded)).
+		Order(ent.Asc(outboxevent.FieldID)).
+		All(ts.acme)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{
+		fmt.Sprintf(`{"group_id":%d,"user_id":%d}`, g.ID, ts.acmeUser.ID),
+		fmt.Sprintf(`{"group_id":%d,"user_id":%d}`, g.ID, other.ID),
+	}
+	if len(events) != len(want) {
+		t.Fatalf("events = %d, want %d", len(events), len(want))
+	}
+	for i, e := range events {
+		if string(e.Payload) != want[i] || e.AggregateID != g.ID {
+			t.Errorf("event %d = %s of group %d, want %s", i, e.Payload, e.AggregateID, want[i])
+		}
+	}
+
+	if _, err := groups.GetByID(ts.globex, g.ID); !errors.Is(err, repository.ErrNotFound) {
+		t.Errorf("GetByID of the group of acme = %v, want ErrNotFound", err)
+	}
+}
@@ -0,0 +1,153 @@
+package rdb
+
+import (
+	"context"
+	"fmt"
+	"github.com/jpdel518/go-ent/domain/event"
+	"github.com/jpdel518/go-ent/domain/model"
+	"github.com/jpdel518/go-ent/domain/repository"
+	"github.com/jpdel518/go-ent/ent"
+	"github.com/jpdel518/go-ent/ent/car"
+	"github.com/jpdel518/go-ent/ent/user"
+)
+
+// withTx runs fn in a transaction and commits it when fn succeeds
+func withTx(ctx context.Context, client *ent.Client, fn func(tx *ent.Tx) error) error {
+	tx, err := client.Tx(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if v := recover(); v != nil {
+			_ = tx.Rollback()
+			panic(v)
+		}
+	}()
+	if err := fn(tx); err != nil {
+		if rerr := tx.Rollback(); rerr != nil {
+			err = fmt.Errorf("%w: rolling back transaction: %v", err, rerr)
+		}
+		return err
+	}
+	return tx.Commit()
+}
+
+// recordEvents writes events to the outbox in the transaction of the change
+func recordEvents(ctx context.Context, tx *ent.Tx, events ...*event.Event) error {
+	if len(events) == 0 {
+		return nil
+	}
+	builders := make([]*ent.OutboxEventCreate, 0, len(events))
+	for _, e := range events {
+		builders = append(builders, tx.OutboxEvent.Create().
+			SetEventType(e.Type).
+			SetAggregateType(e.AggregateType).
+			SetAggregateID(e.AggregateID).
+			SetPayload(e.Payload).
+			SetOccurredAt(e.OccurredAt))
+	}
+	return tx.OutboxEvent.CreateBulk(builders...).Exec(ctx)
+}
+
+// userEvent creates an event whose payload is the current state of the user
+func userEvent(ctx context.Context, tx *ent.Tx, eventType string, id int) (*event.Event, error) {
+	u, err := tx.User.Query().Where(user.ID(id)).WithCars().Only(ctx)
+	if err != nil {
+		return nil, err
+	}
+	carIDs := make([]int, 0, len(u.Edges.Cars))
+	for _, c := range u.Edges.Cars {
+		carIDs = append(carIDs, c.ID)
+	}
+	return event.New(eventType, event.AggregateUser, id, &model.User{
+		ID:        u.ID,
+		FirstName: u.FirstName,
+		LastName:  u.LastName,
+		Email:     u.Email,
+		Age:       u.Age,
+		CarIDs:    carIDs,
+		Avatar:    u.Avatar,
+	})
+}
+
+// verificationEvent asks for the link which verifies the e-mail of the user to be mailed
+func verificationEvent(id int, email string) (*event.Event, error) {
+	return event.New(event.UserVerificationRequested, event.AggregateUser, id, event.UserVerificationRequestedPayload{Email: email})
+}
+
+// replaceCars makes the user own exactly the given cars, taking them over from their previous owners.
+// It returns a CarTransferred event for every car whose owner changes.
+func replaceCars(ctx context.Context, tx *ent.Tx, userID int, carIDs []int) ([]*event.Event, error) {
+	var events []*event.Event
+	transferred := func(carID, from, to int) error {
+		e, err := event.New(event.CarTransferred, event.AggregateCar, carID, event.CarTransferredPayload{
+			CarID:      carID,
+			FromUserID: from,
+			ToUserID:   to,
+		})
+		if err == nil {
+			events = append(events, e)
+		}
+		return err
+	}
+
+	// detach the cars the user does not own anymore
+	wanted := make(map[int]bool, len(carIDs))
+	for _, id := range carIDs {
+		wanted[id] = true
+	}
+	owned, err := tx.Car.Query().Where(car.HasOwnerWith(user.ID(userID))).IDs(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var removed []int
+	for _, id := range owned {
+		if !wanted[id] {
+			removed = append(removed, id)
+			if err := transferred(id, userID, 0); err != nil {
+				return nil, err
+			}
+		}
+	}
+	if len(removed) > 0 {
+		if err := tx.Car.Update().Where(car.IDIn(removed...)).ClearOwner().Exec(ctx); err != nil {
+			return nil, err
+		}
+	}
+	if len(carIDs) == 0 {
+		return events, nil
+	}
+
+	// attach the new cars
+	cars, err := tx.Car.Query().Where(car.IDIn(carIDs...)).WithOwner().All(ctx)
+	if err != nil {
+		return nil, err
+	}
+	found := make(map[int]bool, len(cars))
+	var added []int
+	for _, c := range cars {
+		found[c.ID] = true
+		from := 0
+		if c.Edges.Owner != nil {
+			from = c.Edges.Owner.ID
+		}
+		if from == userID {
+			continue
+		}
+		added = append(added, c.ID)
+		if err := transferred(c.ID, from, userID); err != nil {
+			return nil, err
+		}
+	}
+	for _, id := range carIDs {
+		if !found[id] {
+			return nil, &repositoryError{err: fmt.Errorf("car %d does not exist", id), target: repository.ErrNotFound}
+		}
+	}
+	if len(added) > 0 {
+		if err := tx.Car.Update().Where(car.IDIn(added...)).SetOwnerID(userID).Exec(ctx); err != nil {
+			return nil, err
+		}
+	}
+	return events, nil
+}
@@ -0,0 +1,97 @@
+package rdb
+
+import (
+	"context"
+	"entgo.io/ent/dialect/sql"
+	"github.com/jpdel518/go-ent/domain/event"
+	"github.com/jpdel518/go-ent/domain/repository"
+	"github.com/jpdel518/go-ent/ent"
+	"github.com/jpdel518/go-ent/ent/outboxevent"
+	"github.com/jpdel518/go-ent/ent/predicate"
+	"golang.org/x/exp/slog"
+	"time"
+)
+
+type outboxRepository struct {
+	client *ent.Client
+}
+
+func NewOutboxRepository(client *ent.Client) repository.OutboxRepository {
+	return &outboxRepository{client: client}
+}
+
+func (r *outboxRepository) FetchPending(ctx context.Context, num int) ([]*event.Event, error) {
+	res := make([]*event.Event, 0)
+
+	now := time.Now()
+	events, err := r.client.OutboxEvent.Query().
+		Where(
+			outboxevent.DeliveredAtIsNil(),
+			outboxevent.DeadAtIsNil(),
+			outboxevent.AvailableAtLTE(now),
+			notBackingOff(now),
+		).
+		Order(ent.Asc(outboxevent.FieldID)).
+		Limit(num).
+		All(ctx)
+	if err != nil {
+		slog.ErrorCtx(ctx, "failed fetching pending events", "err", err)
+		return res, err
+	}
+
+	// ent.OutboxEvent -> event.Event
+	for _, e := range events {
+		res = append(res, &event.Event{
+			ID:            e.ID,
+			TenantID:      e.TenantID,
+			Type:          e.EventType,
+			AggregateType: e.AggregateType,
+			AggregateID:   e.AggregateID,
+			Payload:       e.Payload,
+			OccurredAt:    e.OccurredAt,
+			Attempts:      e.Attempts,
+			AvailableAt:   e.AvailableAt,
+		})
+	}
+	return res, nil
+}
+
+func (r *outboxRepository) MarkDelivered(ctx context.Context, id int) error {
+	return r.client.OutboxEvent.UpdateOneID(id).
+		SetDeliveredAt(time.Now()).
+		AddAttempts(1).
+		ClearLastError().
+		Exec(ctx)
+}
+
+func (r *outboxRepository) MarkFailed(ctx context.Context, id int, cause error, retryAt *time.Time) error {
+	update := r.client.OutboxEvent.UpdateOneID(id).
+		AddAttempts(1).
+		SetLastError(cause.Error())
+	if retryAt == nil {
+		update = update.SetDeadAt(time.Now())
+	} else {
+		update = update.SetAvailableAt(*retryAt)
+	}
+	return update.Exec(ctx)
+}
+
+// notBackingOff leaves out the events behind an earlier pending event of their aggregate which is waiting for a retry,
+// so that the aggregates in backoff do not fill the batches and hold up the others
+func notBackingOff(now time.Time) predicate.OutboxEvent {
+	return func(s *sql.Selector) {
+		earlier := sql.Table(outboxevent.Table).As("earlier")
+		s.Where(sql.NotExists(
+			sql.Select(earlier.C(outboxevent.FieldID)).
+				From(earlier).
+				Where(sql.And(
+					sql.ColumnsEQ(earlier.C(outboxevent.FieldAggregateType), s.C(outboxevent.FieldAggregateType)),
+					sql.ColumnsEQ(earlier.C(outboxevent.FieldAggregateID), s.C(outboxevent.FieldAggregateID)),
+					sql.ColumnsLT(earlier.C(outboxevent.FieldID), s.C(outboxevent.FieldID)),
+					sql.IsNull(earlier.C(outboxevent.FieldDeliveredAt)),
+					sql.IsNull(earlier.C(outboxevent.FieldDeadAt)),
+					sql.GT(earlier.C(outboxevent.FieldAvailableAt), now),
+				)),
+		))
+	}
+}
@@ -0,0 +1,26 @@
+package rdb
+
+import (
+	"context"
+	"fmt"
+	"github.com/jpdel518/go-ent/domain/repository/repositorytest"
+	"github.com/jpdel518/go-ent/ent/enttest"
+	"github.com/jpdel518/go-ent/tenant"
+	_ "github.com/mattn/go-sqlite3"
+	"sync/atomic"
+	"testing"
+)
+
+// databases names a new in-memory database for each case
+var databases atomic.Uint64
+
+func TestRepositories(t *testing.T) {
+	repositorytest.TestRepositories(t, func(t *testing.T) repositorytest.Repositories {
+		client := enttest.Open(t, "sqlite3", fmt.Sprintf("file:repositories%d?mode=memory&cache=shared&_fk=1", databases.Add(1)))
+		t.Cleanup(func() {
+			_ = client.Close()
+		})
+		t1 := client.Tenant.Create().SetName("Acme").SetSlug("acme").SaveX(context.Background())
+		return repositorytest.Repositories{Users: NewUserRepository(client), Cars: NewCarRepository(client), Context: tenant.NewContext(context.Background(), t1.ID)}
+	})
+}
@@ -0,0 +1,204 @@
+package rdb
+
+import (
+	"context"
+	"github.com/jpdel518/go-ent/domain/model"
+	"github.com/jpdel518/go-ent/ent"
+	"github.com/jpdel518/go-ent/ent/car"
+	"github.com/jpdel518/go-ent/ent/user"
+	"github.com/jpdel518/go-ent/infrastructure/search"
+	"github.com/jpdel518/go-ent/tenant"
+	"golang.org/x/exp/slog"
+)
+
+// searchLoadBatchSize is the number of the users or the cars read at once to fill the index
+const searchLoadBatchSize = 500
+
+// searchMutation is implemented by the generated mutations
+type searchMutation interface {
+	changeMutation
+	Client() *ent.Client
+}
+
+// LoadSearchIndex fills the index with all the users and the cars of every tenant
+func LoadSearchIndex(ctx context.Context, client *ent.Client, idx *search.Index) error {
+	ctx = tenant.AllTenants(ctx)
+	for after := 0; ; {
+		users, err := client.User.Query().
+			Where(user.IDGT(after)).
+			Order(ent.Asc(user.FieldID)).
+			Limit(searchLoadBatchSize).
+			WithCars().
+			All(ctx)
+		if err != nil {
+			return err
+		}
+		for _, u := range users {
+			idx.PutUser(u.TenantID, toSearchUser(u))
+			after = u.ID
+		}
+		if len(users) < searchLoadBatchSize {
+			break
+		}
+	}
+	for after := 0; ; {
+		cars, err := client.Car.Query().
+			Where(car.IDGT(after)).
+			Order(ent.Asc(car.FieldID)).
+			Limit(searchLoadBatchSize).
+			All(ctx)
+		if err != nil {
+			return err
+		}
+		for _, c := range cars {
+			idx.PutCar(c.TenantID, toSearchCar(c))
+			after = c.ID
+		}
+		if len(cars) < searchLoadBatchSize {
+			break
+		}
+	}
+	slog.InfoCtx(ctx, "search index was loaded", "documents", idx.Len())
+	return nil
+}
+
+// SearchHook keeps the index in sync with the users and the cars written through the client.
+// A user is indexed with the cars, so the owners of the cars are indexed again as well when the cars change hands or are renamed.
+// Changes made in a transaction are indexed once it is committed.
+func SearchHook(client *ent.Client, idx *search.Index) ent.Hook {
+	return func(next ent.Mutator) ent.Mutator {
+		return ent.MutateFunc(func(ctx context.Context, m ent.Mutation) (ent.Value, error) {
+			sm, ok := m.(searchMutation)
+			if !ok || (m.Type() != ent.TypeUser && m.Type() != ent.TypeCar) {
+				return next.Mutate(ctx, m)
+			}
+
+			// the ids have to be read before they are deleted
+			var ids []int
+			if !m.Op().Is(ent.OpCreate) {
+				var err error
+				ids, err = sm.IDs(ctx)
+				if err != nil {
+					return nil, err
+				}
+			}
+			// the owners before the change lose the cars
+			var cars []int
+			switch mm := m.(type) {
+			case *ent.UserMutation:
+				cars = append(mm.CarsIDs(), mm.RemovedCarsIDs()...)
+			case *ent.CarMutation:
+				cars = ids
+			}
+			var owners []int
+			if len(cars) > 0 {
+				var err error
+				owners, err = sm.Client().Car.Query().Where(car.IDIn(cars...)).QueryOwner().IDs(ctx)
+				if err != nil {
+					return nil, err
+				}
+			}
+
+			v, err := next.Mutate(ctx, m)
+			if err != nil {
+				return v, err
+			}
+			if id, exists := sm.ID(); exists && m.Op().Is(ent.OpCreate) {
+				ids = []int{id}
+			}
+			var userIDs, carIDs []int
+			if m.Type() == ent.TypeUser {
+				userIDs = append(ids, owners...)
+			} else {
+				userIDs, carIDs = owners, ids
+			}
+
+			tx, err := sm.Tx()
+			if err != nil {
+				reindex(ctx, client, idx, userIDs, carIDs)
+				return v, nil
+			}
+			tx.OnCommit(func(next ent.Committer) ent.Committer {
+				return ent.CommitFunc(func(ctx context.Context, tx *ent.Tx) error {
+					if err := next.Commit(ctx, tx); err != nil {
+						return err
+					}
+					reindex(ctx, client, idx, userIDs, carIDs)
+					return nil
+				})
+			})
+			return v, nil
+		})
+	}
+}
+
+// reindex reads the users and the cars again, with the current owners of the cars, and removes the deleted ones from the index
+func reindex(ctx context.Context, client *ent.Client, idx *search.Index, userIDs []int, carIDs []int) {
+	if len(carIDs) > 0 {
+		cars, err := client.Car.Query().Where(car.IDIn(carIDs...)).WithOwner().All(ctx)
+		if err != nil {
+			slog.ErrorCtx(ctx, "failed indexing cars", "err", err)
+			return
+		}
+		found := make(map[int]bool, len(cars))
+		for _, c := range cars {
+			found[c.ID] = true
+			idx.PutCar(c.TenantID, toSearchCar(c))
+			if c.Edges.Owner != nil {
+				userIDs = append(userIDs, c.Edges.Owner.ID)
+			}
+		}
+		for _, id := range carIDs {
+			if !found[id] {
+				idx.RemoveCar(id)
+			}
+		}
+	}
+	if len(userIDs) > 0 {
+		users, err := client.User.Query().Where(user.IDIn(userIDs...)).WithCars().All(ctx)
+		if err != nil {
+			slog.ErrorCtx(ctx, "failed indexing users", "err", err)
+			return
+		}
+		found := make(map[int]bool, len(users))
+		for _, u := range users {
+			found[u.ID] = true
+			idx.PutUser(u.TenantID, toSearchUser(u))
+		}
+		for _, id := range userIDs {
+			if !found[id] {
+				idx.RemoveUser(id)
+			}
+		}
+	}
+}
+
+// ent.User -> model.User with the cars
+func toSearchUser(u *ent.User) *model.User {
+	cars := make([]model.Car, 0, len(u.Edges.Cars))
+	carIDs := make([]int, 0, len(u.Edges.Cars))
+	for _, c := range u.Edges.Cars {
+		cars = append(cars, *toSearchCar(c))
+		carIDs = append(carIDs, c.ID)
+	}
+	return &model.User{
+		ID:        u.ID,
+		FirstName: u.FirstName,
+		LastName:  u.LastName,
+		Email:     u.Email,
+		Age:       u.Age,
+		CarIDs:    carIDs,
+		Cars:      cars,
+		Avatar:    u.Avatar,
+	}
+}
+
+// ent.Car -> model.Car
+func toSearchCar(c *ent.Car) *model.Car {
+	return &model.Car{
+		ID:           c.ID,
+		Name:         c.Name,
+		Model:        c.Model,
+		RegisteredAt: c.RegisteredAt,
+	}
+}
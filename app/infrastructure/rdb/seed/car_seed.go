@@ -3,23 +3,23 @@ package seed
 import (
 	"context"
 	"github.com/jpdel518/go-ent/ent"
-	"log"
+	"golang.org/x/exp/slog"
 	"time"
 )
 
-// CarSeed is a function to seed cars
-func CarSeed(client *ent.Client) {
+// CarSeed is a function to seed cars of the tenant of the context
+func CarSeed(ctx context.Context, client *ent.Client) {
 	var v []struct {
 		Count int
 	}
 	err := client.Car.Query().
 		Aggregate(ent.Count()).
-		Scan(context.Background(), &v)
+		Scan(ctx, &v)
 	if err != nil {
-		log.Printf("failed seeding when count cars: %v", err)
+		slog.Error("failed seeding when count cars", "err", err)
 		return
 	} else {
-		log.Printf("car count: %v and skip seeding", v)
+		slog.Info("cars counted before seeding", "count", v[0].Count)
 	}
 
 	if v[0].Count <= 0 {
@@ -27,9 +27,9 @@ func CarSeed(client *ent.Client) {
 			SetName("Toyota").
 			SetModel("Prius").
 			SetRegisteredAt(time.Now()).
-			Save(context.Background())
+			Save(ctx)
 		if err != nil {
-			log.Printf("failed seeding when creating car: %v", err)
+			slog.Error("failed seeding when creating car", "err", err)
 			return
 		}
 
@@ -37,9 +37,9 @@ func CarSeed(client *ent.Client) {
 			SetName("Honda").
 			SetModel("Civic").
 			SetRegisteredAt(time.Now()).
-			Save(context.Background())
+			Save(ctx)
 		if err != nil {
-			log.Printf("failed seeding when creating car: %v", err)
+			slog.Error("failed seeding when creating car", "err", err)
 			return
 		}
 
@@ -47,9 +47,9 @@ func CarSeed(client *ent.Client) {
 			SetName("Nissan").
 			SetModel("Leaf").
 			SetRegisteredAt(time.Now()).
-			Save(context.Background())
+			Save(ctx)
 		if err != nil {
-			log.Printf("failed seeding when creating car: %v", err)
+			slog.Error("failed seeding when creating car", "err", err)
 			return
 		}
 	}
@@ -0,0 +1,36 @@
+package seed
+
+import (
+	"context"
+	"github.com/jpdel518/go-ent/ent"
+	"github.com/jpdel518/go-ent/ent/tenant"
+	"golang.org/x/exp/slog"
+)
+
+// DefaultTenant is the slug of the tenant of the requests without credentials, which the migration creates as well
+const DefaultTenant = "default"
+
+// TenantSeed is a function to seed the default tenant. It returns the id of the tenant, or false when it failed
+func TenantSeed(client *ent.Client) (int, bool) {
+	ctx := context.Background()
+	id, err := client.Tenant.Query().
+		Where(tenant.Slug(DefaultTenant)).
+		OnlyID(ctx)
+	if err == nil {
+		return id, true
+	}
+	if !ent.IsNotFound(err) {
+		slog.Error("failed seeding when finding tenant", "err", err)
+		return 0, false
+	}
+
+	t, err := client.Tenant.Create().
+		SetName("Default").
+		SetSlug(DefaultTenant).
+		Save(ctx)
+	if err != nil {
+		slog.Error("failed seeding when creating tenant", "err", err)
+		return 0, false
+	}
+	return t.ID, true
+}
@@ -0,0 +1,69 @@
+package rdb
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"github.com/jpdel518/go-ent/domain/model"
+	"github.com/jpdel518/go-ent/domain/repository"
+	"github.com/jpdel518/go-ent/ent"
+	enttenant "github.com/jpdel518/go-ent/ent/tenant"
+	"golang.org/x/exp/slog"
+)
+
+type tenantRepository struct {
+	client *ent.Client
+}
+
+func NewTenantRepository(client *ent.Client) repository.TenantRepository {
+	return &tenantRepository{client: client}
+}
+
+func (r *tenantRepository) GetBySlug(ctx context.Context, slug string) (*model.Tenant, error) {
+	t, err := r.client.Tenant.Query().Where(enttenant.Slug(slug)).Only(ctx)
+	if err != nil {
+		return nil, toRepositoryError(err, nil)
+	}
+	return toTenantModel(t), nil
+}
+
+func (r *tenantRepository) GetByAPIKey(ctx context.Context, key string) (*model.Tenant, error) {
+	t, err := r.client.Tenant.Query().Where(enttenant.APIKeyHash(apiKeyHash(key))).Only(ctx)
+	if err != nil {
+		return nil, toRepositoryError(err, nil)
+	}
+	return toTenantModel(t), nil
+}
+
+func (r *tenantRepository) Create(ctx context.Context, t *model.Tenant, apiKey string) error {
+	create := r.client.Tenant.Create().
+		SetName(t.Name).
+		SetSlug(t.Slug)
+	if apiKey != "" {
+		create = create.SetAPIKeyHash(apiKeyHash(apiKey))
+	}
+	data, err := create.Save(ctx)
+	if err != nil {
+		slog.ErrorCtx(ctx, "failed creating tenant", "err", err)
+		return err
+	}
+	slog.InfoCtx(ctx, "tenant was created", "id", data.ID, "slug", data.Slug)
+	t.ID = data.ID
+
+	return nil
+}
+
+// apiKeyHash is what is stored of the API key
+func apiKeyHash(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
+
+// ent.Tenant -> model.Tenant
+func toTenantModel(t *ent.Tenant) *model.Tenant {
+	return &model.Tenant{
+		ID:   t.ID,
+		Name: t.Name,
+		Slug: t.Slug,
+	}
+}
@@ -0,0 +1,167 @@
+package rdb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/jpdel518/go-ent/domain/model"
+	"github.com/jpdel518/go-ent/domain/repository"
+	"github.com/jpdel518/go-ent/ent"
+	"github.com/jpdel518/go-ent/ent/enttest"
+	"github.com/jpdel518/go-ent/ent/user"
+	"github.com/jpdel518/go-ent/tenant"
+	"testing"
+	"time"
+)
+
+// tenants is a database with two tenants, each with a user who owns a car
+type tenants struct {
+	client     *ent.Client
+	acme       context.Context
+	globex     context.Context
+	acmeUser   *ent.User
+	globexUser *ent.User
+	acmeCar    *ent.Car
+	globexCar  *ent.Car
+}
+
+func newTenants(t *testing.T) *tenants {
+	t.Helper()
+	client := enttest.Open(t, "sqlite3", fmt.Sprintf("file:tenants%d?mode=memory&cache=shared&_fk=1", databases.Add(1)))
+	t.Cleanup(func() {
+		_ = client.Close()
+	})
+	ts := &tenants{client: client}
+	bg := context.Background()
+	ts.acme = tenant.NewContext(bg, client.Tenant.Create().SetName("Acme").SetSlug("acme").SaveX(bg).ID)
+	ts.globex = tenant.NewContext(bg, client.Tenant.Create().SetName("Globex").SetSlug("globex").SaveX(bg).ID)
+	ts.acmeCar = client.Car.Create().SetName("Leaf").SetModel("Nissan").SetRegisteredAt(time.Now()).SaveX(ts.acme)
+	ts.globexCar = client.Car.Create().SetName("Prius").SetModel("Toyota").SetRegisteredAt(time.Now()).SaveX(ts.globex)
+	ts.acmeUser = client.User.Create().SetFirstName("Taro").SetLastName("Yamada").SetEmail("taro@example.com").AddCars(ts.acmeCar).SaveX(ts.acme)
+	ts.globexUser = client.User.Create().SetFirstName("Taro").SetLastName("Suzuki").SetEmail("taro@example.com").AddCars(ts.globexCar).SaveX(ts.globex)
+	return ts
+}
+
+func TestTenantQueries(t *testing.T) {
+	ts := newTenants(t)
+
+	users, err := ts.client.User.Query().All(ts.acme)
+	if err != nil || len(users) != 1 || users[0].ID != ts.acmeUser.ID {
+		t.Errorf("users of acme = %v, %v, want only its user", users, err)
+	}
+	if _, err := ts.client.User.Get(ts.acme, ts.globexUser.ID); !ent.IsNotFound(err) {
+		t.Errorf("Get of the user of globex = %v, want not found", err)
+	}
+	if n, err := ts.client.Car.Query().Count(ts.globex); err != nil || n != 1 {
+		t.Errorf("cars of globex = %d, %v, want 1", n, err)
+	}
+	// the traversals are scoped as well, even from an entity of another tenant
+	if cars, err := ts.globexUser.QueryCars().All(ts.acme); err != nil || len(cars) != 0 {
+		t.Errorf("cars of the user of globex in acme = %v, %v, want none", cars, err)
+	}
+	if owner, err := ts.acmeCar.QueryOwner().Only(ts.acme); err != nil || owner.ID != ts.acmeUser.ID {
+		t.Errorf("owner of the car of acme = %v, %v, want its user", owner, err)
+	}
+	// the workers see every tenant
+	if n, err := ts.client.User.Query().Count(tenant.AllTenants(context.Background())); err != nil || n != 2 {
+		t.Errorf("users of all tenants = %d, %v, want 2", n, err)
+	}
+}
+
+func TestTenantMutations(t *testing.T) {
+	ts := newTenants(t)
+
+	if _, err := ts.client.User.UpdateOneID(ts.globexUser.ID).SetLastName("Tanaka").Save(ts.acme); !ent.IsNotFound(err) {
+		t.Errorf("update of the user of globex = %v, want not found", err)
+	}
+	if n, err := ts.client.User.Update().SetAge(30).Save(ts.acme); err != nil || n != 1 {
+		t.Errorf("update of every user = %d, %v, want only the user of acme", n, err)
+	}
+	if err := ts.client.Car.DeleteOneID(ts.globexCar.ID).Exec(ts.acme); !ent.IsNotFound(err) {
+		t.Errorf("delete of the car of globex = %v, want not found", err)
+	}
+	if n, err := ts.client.Car.Delete().Exec(ts.acme); err != nil || n != 1 {
+		t.Errorf("delete of every car = %d, %v, want only the car of acme", n, err)
+	}
+	if n := ts.client.Car.Query().CountX(ts.globex); n != 1 {
+		t.Errorf("cars of globex = %d, want 1", n)
+	}
+	// the e-mails are unique within a tenant
+	if _, err := ts.client.User.Create().SetFirstName("Jiro").SetLastName("Yamada").SetEmail("taro@example.com").Save(ts.acme); !ent.IsConstraintError(err) {
+		t.Errorf("create with the e-mail of acme = %v, want a constraint error", err)
+	}
+}
+
+func TestTenantEdges(t *testing.T) {
+	ts := newTenants(t)
+
+	for name, err := range map[string]error{
+		"user with the car of globex": ts.client.User.Create().SetFirstName("Jiro").SetLastName("Yamada").SetEmail("jiro@example.com").
+			AddCarIDs(ts.globexCar.ID).Exec(ts.acme),
+		"car to the user of globex": ts.client.Car.UpdateOneID(ts.acmeCar.ID).SetOwnerID(ts.globexUser.ID).Exec(ts.acme),
+		"group with the user of globex": ts.client.Group.Create().SetName("Drivers").
+			AddUserIDs(ts.acmeUser.ID, ts.globexUser.ID).Exec(ts.acme),
+		"user of another tenant": ts.client.User.Create().SetFirstName("Jiro").SetLastName("Yamada").SetEmail("jiro@example.com").
+			SetTenantID(ts.globexUser.TenantID).Exec(ts.acme),
+	} {
+		if !errors.Is(err, tenant.ErrCrossTenant) {
+			t.Errorf("%s = %v, want ErrCrossTenant", name, err)
+		}
+	}
+	if owner := ts.client.Car.GetX(ts.globex, ts.globexCar.ID).QueryOwner().OnlyX(ts.globex); owner.ID != ts.globexUser.ID {
+		t.Errorf("owner of the car of globex = %d, want %d", owner.ID, ts.globexUser.ID)
+	}
+}
+
+func TestTenantMissing(t *testing.T) {
+	ts := newTenants(t)
+	ctx := context.Background()
+
+	if _, err := ts.client.User.Query().All(ctx); !errors.Is(err, tenant.ErrMissing) {
+		t.Errorf("query without a tenant = %v, want ErrMissing", err)
+	}
+	if err := ts.client.Car.Create().SetName("Note").SetModel("Nissan").SetRegisteredAt(time.Now()).Exec(ctx); !errors.Is(err, tenant.ErrMissing) {
+		t.Errorf("create without a tenant = %v, want ErrMissing", err)
+	}
+	if _, err := ts.client.User.Delete().Where(user.Email("taro@example.com")).Exec(ctx); !errors.Is(err, tenant.ErrMissing) {
+		t.Errorf("delete without a tenant = %v, want ErrMissing", err)
+	}
+	// the workers create the entities of the tenant they set
+	all := tenant.AllTenants(ctx)
+	if err := ts.client.Car.Create().SetName("Note").SetModel("Nissan").SetRegisteredAt(time.Now()).Exec(all); !errors.Is(err, tenant.ErrMissing) {
+		t.Errorf("create of all tenants = %v, want ErrMissing", err)
+	}
+	if err := ts.client.Car.Create().SetName("Note").SetModel("Nissan").SetRegisteredAt(time.Now()).SetTenantID(ts.acmeCar.TenantID).Exec(all); err != nil {
+		t.Errorf("create of all tenants with the tenant = %v", err)
+	}
+}
+
+func TestTenantRepositories(t *testing.T) {
+	ts := newTenants(t)
+	users, cars := NewUserRepository(ts.client), NewCarRepository(ts.client)
+
+	if _, err := users.GetByID(ts.acme, ts.globexUser.ID); !errors.Is(err, repository.ErrNotFound) {
+		t.Errorf("GetByID of the user of globex = %v, want ErrNotFound", err)
+	}
+	if err := users.Delete(ts.acme, ts.globexUser.ID); !errors.Is(err, repository.ErrNotFound) {
+		t.Errorf("Delete of the user of globex = %v, want ErrNotFound", err)
+	}
+	if err := cars.Update(ts.acme, &model.Car{ID: ts.globexCar.ID, Name: "Aqua", Model: "Toyota"}); !errors.Is(err, repository.ErrNotFound) {
+		t.Errorf("Update of the car of globex = %v, want ErrNotFound", err)
+	}
+	if n, err := users.Count(ts.globex); err != nil || n != 1 {
+		t.Errorf("Count of globex = %d, %v, want 1", n, err)
+	}
+
+	tenants := NewTenantRepository(ts.client)
+	created := &model.Tenant{Name: "Initech", Slug: "initech"}
+	if err := tenants.Create(context.Background(), created, "initech-key"); err != nil {
+		t.Fatal(err)
+	}
+	if got, err := tenants.GetByAPIKey(context.Background(), "initech-key"); err != nil || got.ID != created.ID {
+		t.Errorf("GetByAPIKey = %+v, %v, want %+v", got, err, created)
+	}
+	if _, err := tenants.GetByAPIKey(context.Background(), "wrong-key"); !errors.Is(err, repository.ErrNotFound) {
+		t.Errorf("GetByAPIKey of a wrong key = %v, want ErrNotFound", err)
+	}
+}
@@ -0,0 +1,109 @@
+package rdb
+
+import (
+	"context"
+	"database/sql"
+	"entgo.io/ent/dialect"
+	"fmt"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.17.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+var tracer = otel.Tracer("github.com/jpdel518/go-ent/infrastructure/rdb")
+
+// tracingDriver starts a span for each statement with the SQL in db.statement. The arguments are not recorded
+type tracingDriver struct {
+	dialect.Driver
+}
+
+// NewTracingDriver wraps the driver to trace every query and exec, including the ones in transactions
+func NewTracingDriver(drv dialect.Driver) dialect.Driver {
+	return &tracingDriver{Driver: drv}
+}
+
+func (d *tracingDriver) Exec(ctx context.Context, query string, args, v interface{}) error {
+	ctx, span := startStatement(ctx, "exec", query)
+	defer span.End()
+	return endSpan(span, d.Driver.Exec(ctx, query, args, v))
+}
+
+func (d *tracingDriver) Query(ctx context.Context, query string, args, v interface{}) error {
+	ctx, span := startStatement(ctx, "query", query)
+	defer span.End()
+	return endSpan(span, d.Driver.Query(ctx, query, args, v))
+}
+
+func (d *tracingDriver) Tx(ctx context.Context) (dialect.Tx, error) {
+	tx, err := d.Driver.Tx(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &tracingTx{Tx: tx, ctx: ctx}, nil
+}
+
+// BeginTx is used by ent.Client.BeginTx
+func (d *tracingDriver) BeginTx(ctx context.Context, opts *sql.TxOptions) (dialect.Tx, error) {
+	drv, ok := d.Driver.(interface {
+		BeginTx(context.Context, *sql.TxOptions) (dialect.Tx, error)
+	})
+	if !ok {
+		return nil, fmt.Errorf("driver.BeginTx is not supported")
+	}
+	tx, err := drv.BeginTx(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+	return &tracingTx{Tx: tx, ctx: ctx}, nil
+}
+
+// tracingTx keeps the context of the transaction for the spans of commit and rollback, which take none
+type tracingTx struct {
+	dialect.Tx
+	ctx context.Context
+}
+
+func (tx *tracingTx) Exec(ctx context.Context, query string, args, v interface{}) error {
+	ctx, span := startStatement(ctx, "exec", query)
+	defer span.End()
+	return endSpan(span, tx.Tx.Exec(ctx, query, args, v))
+}
+
+func (tx *tracingTx) Query(ctx context.Context, query string, args, v interface{}) error {
+	ctx, span := startStatement(ctx, "query", query)
+	defer span.End()
+	return endSpan(span, tx.Tx.Query(ctx, query, args, v))
+}
+
+func (tx *tracingTx) Commit() error {
+	_, span := startStatement(tx.ctx, "commit", "COMMIT")
+	defer span.End()
+	return endSpan(span, tx.Tx.Commit())
+}
+
+func (tx *tracingTx) Rollback() error {
+	_, span := startStatement(tx.ctx, "rollback", "ROLLBACK")
+	defer span.End()
+	return endSpan(span, tx.Tx.Rollback())
+}
+
+func startStatement(ctx context.Context, operation string, query string) (context.Context, trace.Span) {
+	return tracer.Start(ctx, "db."+operation,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			semconv.DBSystemMySQL,
+			semconv.DBOperation(operation),
+			semconv.DBStatement(query),
+		),
+	)
+}
+
+// endSpan records the error on the span and returns it
+func endSpan(span trace.Span, err error) error {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	return err
+}
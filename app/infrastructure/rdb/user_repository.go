@@ -2,11 +2,15 @@ package rdb
 
 import (
 	"context"
+	"fmt"
+	"github.com/jpdel518/go-ent/domain/event"
 	"github.com/jpdel518/go-ent/domain/model"
 	"github.com/jpdel518/go-ent/domain/repository"
 	"github.com/jpdel518/go-ent/ent"
+	"github.com/jpdel518/go-ent/ent/car"
 	"github.com/jpdel518/go-ent/ent/user"
-	"log"
+	"golang.org/x/exp/slog"
+	"time"
 )
 
 type userRepository struct {
@@ -21,77 +25,90 @@ func (r *userRepository) Fetch(ctx context.Context, num int) ([]*model.User, err
 	res := make([]*model.User, 0)
 
 	// fetch users
-	users, err := r.client.User.Query().Limit(num).All(ctx)
+	users, err := r.client.User.Query().
+		Order(ent.Asc(user.FieldID)).
+		Limit(num).
+		WithCars(withCarIDs).
+		All(ctx)
 	if err != nil {
-		log.Printf("failed fetching users: %v", err)
+		slog.ErrorCtx(ctx, "failed fetching users", "err", err)
 		return res, err
 	}
 
 	// ent.User -> model.User
 	for _, u := range users {
-		cars := make([]model.Car, 0)
-		for _, c := range u.Edges.Cars {
-			cars = append(cars, model.Car{
-				ID:           c.ID,
-				Name:         c.Name,
-				Model:        c.Model,
-				RegisteredAt: c.RegisteredAt,
-			})
-		}
-		res = append(res, &model.User{
-			ID:        u.ID,
-			FirstName: u.FirstName,
-			LastName:  u.LastName,
-			Email:     u.Email,
-			Age:       u.Age,
-			Cars:      cars,
-		})
+		res = append(res, toModelUser(u))
 	}
 	return res, nil
 }
 
-func (r *userRepository) GetByID(ctx context.Context, id int) (*model.User, error) {
-	// get user
-	u, err := r.client.User.Get(ctx, id)
+func (r *userRepository) FetchAfter(ctx context.Context, afterID int, num int) ([]*model.User, error) {
+	res := make([]*model.User, 0)
+
+	// fetch users ordered by id
+	users, err := r.client.User.Query().
+		Where(user.IDGT(afterID)).
+		Order(ent.Asc(user.FieldID)).
+		Limit(num).
+		WithCars(withCarIDs).
+		All(ctx)
 	if err != nil {
-		log.Printf("failed getbyid user: %v", err)
-		return nil, err
+		slog.ErrorCtx(ctx, "failed fetching users", "after_id", afterID, "err", err)
+		return res, err
 	}
 
 	// ent.User -> model.User
-	cars := make([]model.Car, 0)
-	for _, c := range u.Edges.Cars {
-		cars = append(cars, model.Car{
-			ID:           c.ID,
-			Name:         c.Name,
-			Model:        c.Model,
-			RegisteredAt: c.RegisteredAt,
-		})
+	for _, u := range users {
+		res = append(res, toModelUser(u))
 	}
-	return &model.User{
-		ID:        u.ID,
-		FirstName: u.FirstName,
-		LastName:  u.LastName,
-		Email:     u.Email,
-		Age:       u.Age,
-		Cars:      cars,
-	}, nil
+	return res, nil
+}
+
+func (r *userRepository) GetByID(ctx context.Context, id int) (*model.User, error) {
+	// get user with the ids of the cars
+	u, err := r.client.User.Query().Where(user.ID(id)).WithCars(withCarIDs).Only(ctx)
+	if err != nil {
+		slog.ErrorCtx(ctx, "failed getbyid user", "err", err)
+		return nil, toRepositoryError(err, nil)
+	}
+
+	// ent.User -> model.User
+	return toModelUser(u), nil
 }
 
 func (r *userRepository) Create(ctx context.Context, u *model.User) (*model.User, error) {
-	data, err := r.client.User.Create().
-		SetFirstName(u.FirstName).
-		SetLastName(u.LastName).
-		SetEmail(u.Email).
-		SetAge(u.Age).
-		AddCarIDs(u.CarIDs...).
-		Save(ctx)
+	var data *ent.User
+	err := withTx(ctx, r.client, func(tx *ent.Tx) error {
+		var err error
+		data, err = tx.User.Create().
+			SetFirstName(u.FirstName).
+			SetLastName(u.LastName).
+			SetEmail(u.Email).
+			SetAge(u.Age).
+			Save(ctx)
+		if err != nil {
+			return err
+		}
+		events, err := replaceCars(ctx, tx, data.ID, u.CarIDs)
+		if err != nil {
+			return err
+		}
+		created, err := userEvent(ctx, tx, event.UserCreated, data.ID)
+		if err != nil {
+			return err
+		}
+		requested, err := verificationEvent(data.ID, data.Email)
+		if err != nil {
+			return err
+		}
+		return recordEvents(ctx, tx, append([]*event.Event{created, requested}, events...)...)
+	})
 
 	if err != nil {
-		log.Printf("failed creating user: %v", err)
-		return nil, err
+		slog.ErrorCtx(ctx, "failed creating user", "err", err)
+		return nil, toRepositoryError(err, repository.ErrDuplicateEmail)
 	}
-	log.Printf("user was created: %v", data)
+	slog.InfoCtx(ctx, "user was created", "id", data.ID)
 
 	// ent.User -> model.User
 	cars := make([]model.Car, 0)
@@ -109,6 +126,7 @@ func (r *userRepository) Create(ctx context.Context, u *model.User) (*model.User
 		LastName:  data.LastName,
 		Email:     data.Email,
 		Age:       data.Age,
+		CarIDs:    u.CarIDs,
 		Cars:      cars,
 	}
 
@@ -116,25 +134,243 @@ func (r *userRepository) Create(ctx context.Context, u *model.User) (*model.User
 }
 
 func (r *userRepository) Update(ctx context.Context, u *model.User) (*model.User, error) {
-	data, err := r.client.User.Update().
-		Where(user.ID(u.ID)).
-		SetFirstName(u.FirstName).
-		SetLastName(u.LastName).
-		SetEmail(u.Email).
-		SetAge(u.Age).
-		ClearCars().
-		AddCarIDs(u.CarIDs...).
-		Save(ctx)
+	err := withTx(ctx, r.client, func(tx *ent.Tx) error {
+		current, err := tx.User.Get(ctx, u.ID)
+		if err != nil {
+			return err
+		}
+		update := tx.User.UpdateOneID(u.ID).
+			SetFirstName(u.FirstName).
+			SetLastName(u.LastName).
+			SetEmail(u.Email).
+			SetAge(u.Age)
+		// keep the current avatar when no new one is given
+		if u.Avatar != "" {
+			update = update.SetAvatar(u.Avatar)
+		}
+		if err := update.Exec(ctx); err != nil {
+			return err
+		}
+		events, err := replaceCars(ctx, tx, u.ID, u.CarIDs)
+		if err != nil {
+			return err
+		}
+		updated, err := userEvent(ctx, tx, event.UserUpdated, u.ID)
+		if err != nil {
+			return err
+		}
+		events = append([]*event.Event{updated}, events...)
+		// a new e-mail has to be verified again
+		if email := model.NormalizeEmail(u.Email); email != current.Email {
+			requested, err := verificationEvent(u.ID, email)
+			if err != nil {
+				return err
+			}
+			events = append(events, requested)
+		}
+		return recordEvents(ctx, tx, events...)
+	})
 
 	if err != nil {
-		log.Printf("failed updating user: %v", err)
-		return nil, err
+		slog.ErrorCtx(ctx, "failed updating user", "err", err)
+		return nil, toRepositoryError(err, repository.ErrDuplicateEmail)
 	}
-	log.Printf("user was updated: %v", data)
+	slog.InfoCtx(ctx, "user was updated", "id", u.ID)
 
 	return u, err
 }
 
 func (r *userRepository) Delete(ctx context.Context, id int) error {
-	return r.client.User.DeleteOneID(id).Exec(ctx)
+	err := withTx(ctx, r.client, func(tx *ent.Tx) error {
+		// the cars lose their owner together with the user
+		events, err := replaceCars(ctx, tx, id, []int{})
+		if err != nil {
+			return err
+		}
+		if err := tx.User.DeleteOneID(id).Exec(ctx); err != nil {
+			return err
+		}
+		deleted, err := event.New(event.UserDeleted, event.AggregateUser, id, event.UserDeletedPayload{ID: id})
+		if err != nil {
+			return err
+		}
+		return recordEvents(ctx, tx, append(events, deleted)...)
+	})
+	return toRepositoryError(err, nil)
+}
+
+func (r *userRepository) Count(ctx context.Context) (int, error) {
+	return r.client.User.Query().Count(ctx)
+}
+
+func (r *userRepository) UpdateAvatar(ctx context.Context, id int, avatar string) error {
+	err := withTx(ctx, r.client, func(tx *ent.Tx) error {
+		update := tx.User.UpdateOneID(id)
+		if avatar == "" {
+			update = update.ClearAvatar()
+		} else {
+			update = update.SetAvatar(avatar)
+		}
+		if err := update.Exec(ctx); err != nil {
+			return err
+		}
+		updated, err := userEvent(ctx, tx, event.UserUpdated, id)
+		if err != nil {
+			return err
+		}
+		return recordEvents(ctx, tx, updated)
+	})
+	return toRepositoryError(err, nil)
+}
+
+func (r *userRepository) Verify(ctx context.Context, id int, email string, at time.Time) error {
+	err := withTx(ctx, r.client, func(tx *ent.Tx) error {
+		n, err := tx.User.Update().
+			Where(user.ID(id), user.Email(model.NormalizeEmail(email))).
+			SetVerifiedAt(at).
+			Save(ctx)
+		if err != nil {
+			return err
+		}
+		if n == 0 {
+			return fmt.Errorf("user %d with %s: %w", id, email, repository.ErrNotFound)
+		}
+		updated, err := userEvent(ctx, tx, event.UserUpdated, id)
+		if err != nil {
+			return err
+		}
+		return recordEvents(ctx, tx, updated)
+	})
+	return toRepositoryError(err, nil)
+}
+
+func (r *userRepository) RequestVerification(ctx context.Context, id int) error {
+	err := withTx(ctx, r.client, func(tx *ent.Tx) error {
+		u, err := tx.User.Get(ctx, id)
+		if err != nil {
+			return err
+		}
+		requested, err := verificationEvent(id, u.Email)
+		if err != nil {
+			return err
+		}
+		return recordEvents(ctx, tx, requested)
+	})
+	return toRepositoryError(err, nil)
+}
+
+// Upsert creates the users whose email is not registered yet and updates the others in one transaction.
+// Cars are only replaced when CarIDs is not nil.
+func (r *userRepository) Upsert(ctx context.Context, us []*model.User) ([]bool, error) {
+	var created []bool
+	err := withTx(ctx, r.client, func(tx *ent.Tx) error {
+		var err error
+		created, err = upsertUsers(ctx, tx, us)
+		return err
+	})
+	if err != nil {
+		slog.ErrorCtx(ctx, "failed upserting users", "err", err)
+		return nil, toRepositoryError(err, repository.ErrDuplicateEmail)
+	}
+	return created, nil
+}
+
+func upsertUsers(ctx context.Context, tx *ent.Tx, us []*model.User) ([]bool, error) {
+	// find users already registered, by the e-mails as they are stored
+	emails := make([]string, 0, len(us))
+	for _, u := range us {
+		u.Email = model.NormalizeEmail(u.Email)
+		emails = append(emails, u.Email)
+	}
+	existing, err := tx.User.Query().Where(user.EmailIn(emails...)).All(ctx)
+	if err != nil {
+		return nil, err
+	}
+	ids := make(map[string]int, len(existing))
+	for _, e := range existing {
+		ids[e.Email] = e.ID
+	}
+
+	created := make([]bool, len(us))
+	builders := make([]*ent.UserCreate, 0, len(us))
+	news := make([]*model.User, 0, len(us))
+	for i, u := range us {
+		id, ok := ids[u.Email]
+		if !ok {
+			created[i] = true
+			builders = append(builders, tx.User.Create().
+				SetFirstName(u.FirstName).
+				SetLastName(u.LastName).
+				SetEmail(u.Email).
+				SetAge(u.Age))
+			news = append(news, u)
+			continue
+		}
+
+		err := tx.User.UpdateOneID(id).
+			SetFirstName(u.FirstName).
+			SetLastName(u.LastName).
+			SetAge(u.Age).
+			Exec(ctx)
+		if err != nil {
+			return nil, err
+		}
+		u.ID = id
+	}
+
+	if len(builders) > 0 {
+		data, err := tx.User.CreateBulk(builders...).Save(ctx)
+		if err != nil {
+			return nil, err
+		}
+		for i, d := range data {
+			news[i].ID = d.ID
+		}
+	}
+
+	// replace cars and record the events in the order of the rows
+	var events []*event.Event
+	for i, u := range us {
+		if u.CarIDs != nil {
+			transfers, err := replaceCars(ctx, tx, u.ID, u.CarIDs)
+			if err != nil {
+				return nil, err
+			}
+			events = append(events, transfers...)
+		}
+		eventType := event.UserUpdated
+		if created[i] {
+			eventType = event.UserCreated
+		}
+		e, err := userEvent(ctx, tx, eventType, u.ID)
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, e)
+	}
+	return created, recordEvents(ctx, tx, events...)
+}
+
+// withCarIDs loads the ids of the cars only, whose details are fetched through the car repository
+func withCarIDs(q *ent.CarQuery) {
+	q.Select(car.FieldID).Order(ent.Asc(car.FieldID))
+}
+
+// ent.User -> model.User with the ids of the cars, whose details are left empty
+func toModelUser(u *ent.User) *model.User {
+	carIDs := make([]int, 0, len(u.Edges.Cars))
+	for _, c := range u.Edges.Cars {
+		carIDs = append(carIDs, c.ID)
+	}
+	return &model.User{
+		ID:         u.ID,
+		FirstName:  u.FirstName,
+		LastName:   u.LastName,
+		Email:      u.Email,
+		Age:        u.Age,
+		CarIDs:     carIDs,
+		Cars:       make([]model.Car, 0),
+		Avatar:     u.Avatar,
+		VerifiedAt: u.VerifiedAt,
+	}
 }
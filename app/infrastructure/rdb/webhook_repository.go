@@ -0,0 +1,290 @@
+package rdb
+
+import (
+	"context"
+	"github.com/jpdel518/go-ent/domain/event"
+	"github.com/jpdel518/go-ent/domain/model"
+	"github.com/jpdel518/go-ent/domain/repository"
+	"github.com/jpdel518/go-ent/ent"
+	"github.com/jpdel518/go-ent/ent/predicate"
+	"github.com/jpdel518/go-ent/ent/webhookdelivery"
+	"github.com/jpdel518/go-ent/ent/webhooksubscription"
+	"golang.org/x/exp/slog"
+	"time"
+)
+
+type webhookRepository struct {
+	client *ent.Client
+}
+
+func NewWebhookRepository(client *ent.Client) repository.WebhookRepository {
+	return &webhookRepository{client: client}
+}
+
+func (r *webhookRepository) Fetch(ctx context.Context, num int) ([]*model.WebhookSubscription, error) {
+	res := make([]*model.WebhookSubscription, 0)
+
+	subscriptions, err := r.client.WebhookSubscription.Query().
+		Order(ent.Asc(webhooksubscription.FieldID)).
+		Limit(num).
+		All(ctx)
+	if err != nil {
+		slog.ErrorCtx(ctx, "failed fetching webhook subscriptions", "err", err)
+		return res, err
+	}
+	for _, s := range subscriptions {
+		res = append(res, toWebhookSubscriptionModel(s))
+	}
+	return res, nil
+}
+
+func (r *webhookRepository) GetByID(ctx context.Context, id int) (*model.WebhookSubscription, error) {
+	s, err := r.client.WebhookSubscription.Get(ctx, id)
+	if err != nil {
+		slog.ErrorCtx(ctx, "failed getbyid webhook subscription", "err", err)
+		return nil, err
+	}
+	return toWebhookSubscriptionModel(s), nil
+}
+
+func (r *webhookRepository) Create(ctx context.Context, w *model.WebhookSubscription) (*model.WebhookSubscription, error) {
+	data, err := r.client.WebhookSubscription.Create().
+		SetURL(w.URL).
+		SetSecret(w.Secret).
+		SetEventTypes(w.EventTypes).
+		SetActive(w.Active).
+		Save(ctx)
+	if err != nil {
+		slog.ErrorCtx(ctx, "failed creating webhook subscription", "err", err)
+		return nil, err
+	}
+	slog.InfoCtx(ctx, "webhook subscription was created", "id", data.ID)
+
+	return toWebhookSubscriptionModel(data), nil
+}
+
+func (r *webhookRepository) Update(ctx context.Context, w *model.WebhookSubscription) (*model.WebhookSubscription, error) {
+	var data *ent.WebhookSubscription
+	err := withTx(ctx, r.client, func(tx *ent.Tx) error {
+		update := tx.WebhookSubscription.UpdateOneID(w.ID).
+			SetURL(w.URL).
+			SetEventTypes(w.EventTypes).
+			SetActive(w.Active)
+		// keep the current secret when no new one is given
+		if w.Secret != "" {
+			update = update.SetSecret(w.Secret)
+		}
+		// enabling the subscription again gives it a fresh start
+		if w.Active {
+			update = update.SetFailureCount(0).ClearDisabledAt()
+		}
+		var err error
+		data, err = update.Save(ctx)
+		if err != nil || w.Active {
+			return err
+		}
+		// a deactivated subscription gives up what is queued, which is not sent when it is enabled again
+		return failPendingDeliveries(ctx, tx, w.ID, "subscription was deactivated")
+	})
+	if err != nil {
+		slog.ErrorCtx(ctx, "failed updating webhook subscription", "err", err)
+		return nil, err
+	}
+	slog.InfoCtx(ctx, "webhook subscription was updated", "id", data.ID)
+
+	return toWebhookSubscriptionModel(data), nil
+}
+
+func (r *webhookRepository) Delete(ctx context.Context, id int) error {
+	return r.client.WebhookSubscription.DeleteOneID(id).Exec(ctx)
+}
+
+func (r *webhookRepository) FetchActive(ctx context.Context) ([]*model.WebhookSubscription, error) {
+	res := make([]*model.WebhookSubscription, 0)
+
+	subscriptions, err := r.client.WebhookSubscription.Query().
+		Where(webhooksubscription.Active(true)).
+		All(ctx)
+	if err != nil {
+		slog.ErrorCtx(ctx, "failed fetching active webhook subscriptions", "err", err)
+		return res, err
+	}
+	for _, s := range subscriptions {
+		res = append(res, toWebhookSubscriptionModel(s))
+	}
+	return res, nil
+}
+
+func (r *webhookRepository) CreateDeliveries(ctx context.Context, e *event.Event, subscriptionIDs []int) error {
+	if len(subscriptionIDs) == 0 {
+		return nil
+	}
+	return withTx(ctx, r.client, func(tx *ent.Tx) error {
+		// the relay may send the same event again
+		existing, err := tx.WebhookDelivery.Query().
+			Where(
+				webhookdelivery.EventID(e.ID),
+				webhookdelivery.HasSubscriptionWith(webhooksubscription.IDIn(subscriptionIDs...)),
+			).
+			QuerySubscription().
+			IDs(ctx)
+		if err != nil {
+			return err
+		}
+		delivered := make(map[int]bool, len(existing))
+		for _, id := range existing {
+			delivered[id] = true
+		}
+
+		builders := make([]*ent.WebhookDeliveryCreate, 0, len(subscriptionIDs))
+		for _, id := range subscriptionIDs {
+			if delivered[id] {
+				continue
+			}
+			builders = append(builders, tx.WebhookDelivery.Create().
+				SetSubscriptionID(id).
+				SetEventID(e.ID).
+				SetEventType(e.Type).
+				SetPayload(e.Payload))
+		}
+		if len(builders) == 0 {
+			return nil
+		}
+		return tx.WebhookDelivery.CreateBulk(builders...).Exec(ctx)
+	})
+}
+
+func (r *webhookRepository) FetchDueDeliveries(ctx context.Context, num int) ([]*model.WebhookDelivery, error) {
+	return r.fetchDeliveries(ctx, num,
+		webhookdelivery.StatusEQ(webhookdelivery.StatusPending),
+		webhookdelivery.NextAttemptAtLTE(time.Now()),
+		// the deliveries of the inactive subscriptions would fill the batches without being sent
+		webhookdelivery.HasSubscriptionWith(webhooksubscription.Active(true)),
+	)
+}
+
+func (r *webhookRepository) FetchDeliveries(ctx context.Context, subscriptionID int, num int) ([]*model.WebhookDelivery, error) {
+	return r.fetchDeliveries(ctx, num, webhookdelivery.HasSubscriptionWith(webhooksubscription.ID(subscriptionID)))
+}
+
+func (r *webhookRepository) fetchDeliveries(ctx context.Context, num int, ps ...predicate.WebhookDelivery) ([]*model.WebhookDelivery, error) {
+	res := make([]*model.WebhookDelivery, 0)
+
+	deliveries, err := r.client.WebhookDelivery.Query().
+		Where(ps...).
+		Order(ent.Asc(webhookdelivery.FieldID)).
+		Limit(num).
+		WithSubscription().
+		All(ctx)
+	if err != nil {
+		slog.ErrorCtx(ctx, "failed fetching webhook deliveries", "err", err)
+		return res, err
+	}
+
+	// ent.WebhookDelivery -> model.WebhookDelivery
+	for _, d := range deliveries {
+		res = append(res, &model.WebhookDelivery{
+			ID:             d.ID,
+			SubscriptionID: d.Edges.Subscription.ID,
+			EventID:        d.EventID,
+			EventType:      d.EventType,
+			Payload:        d.Payload,
+			Status:         model.WebhookDeliveryStatus(d.Status),
+			Attempts:       d.Attempts,
+			ResponseCode:   d.ResponseCode,
+			LastError:      d.LastError,
+			NextAttemptAt:  d.NextAttemptAt,
+			DeliveredAt:    d.DeliveredAt,
+			CreatedAt:      d.CreatedAt,
+		})
+	}
+	return res, nil
+}
+
+func (r *webhookRepository) MarkDelivered(ctx context.Context, id int, code int) error {
+	return r.client.WebhookDelivery.UpdateOneID(id).
+		SetStatus(webhookdelivery.StatusSucceeded).
+		AddAttempts(1).
+		SetResponseCode(code).
+		ClearLastError().
+		SetDeliveredAt(time.Now()).
+		Exec(ctx)
+}
+
+func (r *webhookRepository) MarkAttemptFailed(ctx context.Context, id int, code int, cause error, retryAt *time.Time) error {
+	update := r.client.WebhookDelivery.UpdateOneID(id).
+		AddAttempts(1).
+		SetLastError(cause.Error())
+	if code > 0 {
+		update = update.SetResponseCode(code)
+	}
+	if retryAt == nil {
+		update = update.SetStatus(webhookdelivery.StatusFailed)
+	} else {
+		update = update.SetNextAttemptAt(*retryAt)
+	}
+	return update.Exec(ctx)
+}
+
+func (r *webhookRepository) RecordResult(ctx context.Context, subscriptionID int, succeeded bool, threshold int) (bool, error) {
+	if succeeded {
+		return false, r.client.WebhookSubscription.UpdateOneID(subscriptionID).
+			SetFailureCount(0).
+			Exec(ctx)
+	}
+
+	disabled := false
+	err := withTx(ctx, r.client, func(tx *ent.Tx) error {
+		s, err := tx.WebhookSubscription.UpdateOneID(subscriptionID).
+			AddFailureCount(1).
+			Save(ctx)
+		if err != nil {
+			return err
+		}
+		if !s.Active || s.FailureCount < threshold {
+			return nil
+		}
+
+		// stop sending to the endpoint which keeps failing
+		disabled = true
+		err = tx.WebhookSubscription.UpdateOneID(subscriptionID).
+			SetActive(false).
+			SetDisabledAt(time.Now()).
+			Exec(ctx)
+		if err != nil {
+			return err
+		}
+		return failPendingDeliveries(ctx, tx, subscriptionID, "subscription was disabled")
+	})
+	return disabled, err
+}
+
+// failPendingDeliveries gives up the deliveries of the subscription which have not been sent yet
+func failPendingDeliveries(ctx context.Context, tx *ent.Tx, subscriptionID int, reason string) error {
+	return tx.WebhookDelivery.Update().
+		Where(
+			webhookdelivery.StatusEQ(webhookdelivery.StatusPending),
+			webhookdelivery.HasSubscriptionWith(webhooksubscription.ID(subscriptionID)),
+		).
+		SetStatus(webhookdelivery.StatusFailed).
+		SetLastError(reason).
+		Exec(ctx)
+}
+
+// ent.WebhookSubscription -> model.WebhookSubscription
+func toWebhookSubscriptionModel(s *ent.WebhookSubscription) *model.WebhookSubscription {
+	eventTypes := s.EventTypes
+	if eventTypes == nil {
+		eventTypes = make([]string, 0)
+	}
+	return &model.WebhookSubscription{
+		ID:           s.ID,
+		URL:          s.URL,
+		Secret:       s.Secret,
+		EventTypes:   eventTypes,
+		Active:       s.Active,
+		FailureCount: s.FailureCount,
+		DisabledAt:   s.DisabledAt,
+		CreatedAt:    s.CreatedAt,
+	}
+}
@@ -0,0 +1,258 @@
+package search
+
+import (
+	"context"
+	"github.com/jpdel518/go-ent/domain/model"
+	"github.com/jpdel518/go-ent/tenant"
+	"math"
+	"sort"
+	"strings"
+	"sync"
+)
+
+type docKey struct {
+	typ string
+	id  int
+}
+
+type field struct {
+	name   string
+	value  string
+	weight float64
+	words  []string
+}
+
+func newField(name string, value string, weight float64) field {
+	return field{name: name, value: value, weight: weight, words: uniqueWords(value)}
+}
+
+type document struct {
+	key      docKey
+	tenantID int
+	fields   []field
+	user     *model.User
+	car      *model.Car
+}
+
+// shard holds the documents of one tenant
+type shard struct {
+	docs map[docKey]*document
+	// postings lists the documents by their words
+	postings map[string]map[docKey]struct{}
+}
+
+// Index is an inverted index of the users and the cars of every tenant in memory.
+// Each tenant has its own postings, so a search only reads the words of its tenant.
+// The names weigh more than the e-mail, and the cars of a user weigh the least, so that "leaf" finds the Leaf first and then who drives it
+type Index struct {
+	mu     sync.RWMutex
+	shards map[int]*shard
+	// tenants finds the tenant of a document to remove, the ids being unique across the tenants
+	tenants map[docKey]int
+}
+
+func NewIndex() *Index {
+	return &Index{
+		shards:  make(map[int]*shard),
+		tenants: make(map[docKey]int),
+	}
+}
+
+// PutUser adds or replaces the user of the tenant with the cars
+func (idx *Index) PutUser(tenantID int, u *model.User) {
+	cars := make([]string, 0, len(u.Cars)*2)
+	for _, c := range u.Cars {
+		cars = append(cars, c.Name, c.Model)
+	}
+	idx.put(&document{
+		key:      docKey{typ: model.SearchTypeUser, id: u.ID},
+		tenantID: tenantID,
+		user:     u,
+		fields: []field{
+			newField("first_name", u.FirstName, 3),
+			newField("last_name", u.LastName, 3),
+			newField("email", u.Email, 2),
+			newField("cars", strings.Join(cars, " "), 1),
+		},
+	})
+}
+
+// PutCar adds or replaces the car of the tenant
+func (idx *Index) PutCar(tenantID int, c *model.Car) {
+	idx.put(&document{
+		key:      docKey{typ: model.SearchTypeCar, id: c.ID},
+		tenantID: tenantID,
+		car:      c,
+		fields: []field{
+			newField("name", c.Name, 3),
+			newField("model", c.Model, 3),
+		},
+	})
+}
+
+func (idx *Index) RemoveUser(id int) {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+	idx.remove(docKey{typ: model.SearchTypeUser, id: id})
+}
+
+func (idx *Index) RemoveCar(id int) {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+	idx.remove(docKey{typ: model.SearchTypeCar, id: id})
+}
+
+// Len returns the number of the indexed users and cars
+func (idx *Index) Len() int {
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+	return len(idx.tenants)
+}
+
+func (idx *Index) put(d *document) {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+
+	idx.remove(d.key)
+	s, ok := idx.shards[d.tenantID]
+	if !ok {
+		s = &shard{
+			docs:     make(map[docKey]*document),
+			postings: make(map[string]map[docKey]struct{}),
+		}
+		idx.shards[d.tenantID] = s
+	}
+	idx.tenants[d.key] = d.tenantID
+	s.docs[d.key] = d
+	for _, f := range d.fields {
+		for _, w := range f.words {
+			docs, ok := s.postings[w]
+			if !ok {
+				docs = make(map[docKey]struct{})
+				s.postings[w] = docs
+			}
+			docs[d.key] = struct{}{}
+		}
+	}
+}
+
+func (idx *Index) remove(key docKey) {
+	tenantID, ok := idx.tenants[key]
+	if !ok {
+		return
+	}
+	delete(idx.tenants, key)
+	s := idx.shards[tenantID]
+	d := s.docs[key]
+	delete(s.docs, key)
+	for _, f := range d.fields {
+		for _, w := range f.words {
+			delete(s.postings[w], key)
+			if len(s.postings[w]) == 0 {
+				delete(s.postings, w)
+			}
+		}
+	}
+	if len(s.docs) == 0 {
+		delete(idx.shards, tenantID)
+	}
+}
+
+// Search scores each document by the best field for each query word, which is the weight of the field times the weight of the match,
+// and by the share of the query words it matches. Only the documents of the tenant of the context are searched
+func (idx *Index) Search(ctx context.Context, q *model.SearchQuery) ([]*model.SearchHit, error) {
+	tenantID, ok := tenant.FromContext(ctx)
+	if !ok {
+		return nil, tenant.ErrMissing
+	}
+	hits := make([]*model.SearchHit, 0)
+	queries := uniqueWords(q.Text)
+	if len(queries) == 0 {
+		return hits, nil
+	}
+	types := map[string]bool{model.SearchTypeUser: len(q.Types) == 0, model.SearchTypeCar: len(q.Types) == 0}
+	for _, t := range q.Types {
+		types[t] = true
+	}
+
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+	// the request may have gone while waiting for the writers
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	s, ok := idx.shards[tenantID]
+	if !ok {
+		return hits, nil
+	}
+
+	// the weights of the indexed words which match each query word
+	matches := make([]map[string]float64, len(queries))
+	candidates := make(map[docKey]struct{})
+	for i, query := range queries {
+		matches[i] = make(map[string]float64)
+		for w, docs := range s.postings {
+			m := match(query, w)
+			if m == 0 {
+				continue
+			}
+			matches[i][w] = m
+			for key := range docs {
+				if types[key.typ] {
+					candidates[key] = struct{}{}
+				}
+			}
+		}
+	}
+
+	for key := range candidates {
+		d := s.docs[key]
+		score := 0.0
+		matched := 0
+		for i := range queries {
+			best := 0.0
+			for _, f := range d.fields {
+				for _, w := range f.words {
+					best = math.Max(best, matches[i][w]*f.weight)
+				}
+			}
+			if best > 0 {
+				matched++
+				score += best
+			}
+		}
+		score *= float64(matched) / float64(len(queries))
+
+		hit := &model.SearchHit{
+			Type:       key.typ,
+			ID:         key.id,
+			Score:      math.Round(score*1000) / 1000,
+			Highlights: make(map[string]string),
+		}
+		for _, f := range d.fields {
+			if h, ok := highlight(f.value, queries); ok {
+				hit.Highlights[f.name] = h
+			}
+		}
+		if d.user != nil {
+			u := *d.user
+			hit.User = &u
+		}
+		if d.car != nil {
+			c := *d.car
+			hit.Car = &c
+		}
+		hits = append(hits, hit)
+	}
+
+	sort.Slice(hits, func(i, j int) bool {
+		if hits[i].Score != hits[j].Score {
+			return hits[i].Score > hits[j].Score
+		}
+		if hits[i].Type != hits[j].Type {
+			return hits[i].Type < hits[j].Type
+		}
+		return hits[i].ID < hits[j].ID
+	})
+	return hits, nil
+}
@@ -0,0 +1,158 @@
+package search
+
+import (
+	"context"
+	"errors"
+	"github.com/jpdel518/go-ent/domain/model"
+	"github.com/jpdel518/go-ent/tenant"
+	"reflect"
+	"testing"
+)
+
+// search returns the type, the id and the score of the hits in the tenant
+func search(t *testing.T, idx *Index, tenantID int, q model.SearchQuery) []model.SearchHit {
+	t.Helper()
+	hits, err := idx.Search(tenant.NewContext(context.Background(), tenantID), &q)
+	if err != nil {
+		t.Fatal(err)
+	}
+	res := make([]model.SearchHit, 0, len(hits))
+	for _, h := range hits {
+		res = append(res, model.SearchHit{Type: h.Type, ID: h.ID, Score: h.Score})
+	}
+	return res
+}
+
+func newTestIndex() *Index {
+	idx := NewIndex()
+	leaf := model.Car{ID: 1, Name: "Leaf", Model: "Nissan"}
+	idx.PutCar(1, &leaf)
+	idx.PutCar(1, &model.Car{ID: 2, Name: "Prius", Model: "Toyota"})
+	idx.PutUser(1, &model.User{ID: 1, FirstName: "Taro", LastName: "Yamada", Email: "taro@example.com", Cars: []model.Car{leaf}})
+	idx.PutUser(1, &model.User{ID: 2, FirstName: "Hanako", LastName: "Leafson", Email: "hanako@example.com"})
+	return idx
+}
+
+func TestSearch(t *testing.T) {
+	idx := newTestIndex()
+	tests := []struct {
+		name string
+		q    model.SearchQuery
+		want []model.SearchHit
+	}{
+		{
+			// the name of the car, then the last name beginning with it, then the driver of the car
+			name: "score order",
+			q:    model.SearchQuery{Text: "leaf"},
+			want: []model.SearchHit{
+				{Type: model.SearchTypeCar, ID: 1, Score: 3},
+				{Type: model.SearchTypeUser, ID: 2, Score: 2.1},
+				{Type: model.SearchTypeUser, ID: 1, Score: 1},
+			},
+		},
+		{
+			name: "prefix",
+			q:    model.SearchQuery{Text: "toyo"},
+			want: []model.SearchHit{{Type: model.SearchTypeCar, ID: 2, Score: 2.1}},
+		},
+		{
+			name: "fuzzy",
+			q:    model.SearchQuery{Text: "nisan"},
+			want: []model.SearchHit{
+				{Type: model.SearchTypeCar, ID: 1, Score: 1.2},
+				{Type: model.SearchTypeUser, ID: 1, Score: 0.4},
+			},
+		},
+		{
+			// a half of the words match
+			name: "some of the words",
+			q:    model.SearchQuery{Text: "taro prius"},
+			want: []model.SearchHit{
+				{Type: model.SearchTypeCar, ID: 2, Score: 1.5},
+				{Type: model.SearchTypeUser, ID: 1, Score: 1.5},
+			},
+		},
+		{
+			name: "all the words",
+			q:    model.SearchQuery{Text: "taro yamada"},
+			want: []model.SearchHit{{Type: model.SearchTypeUser, ID: 1, Score: 6}},
+		},
+		{
+			name: "type",
+			q:    model.SearchQuery{Text: "leaf", Types: []string{model.SearchTypeUser}},
+			want: []model.SearchHit{
+				{Type: model.SearchTypeUser, ID: 2, Score: 2.1},
+				{Type: model.SearchTypeUser, ID: 1, Score: 1},
+			},
+		},
+		{name: "no match", q: model.SearchQuery{Text: "civic"}, want: []model.SearchHit{}},
+		{name: "no words", q: model.SearchQuery{Text: "@@"}, want: []model.SearchHit{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := search(t, idx, 1, tt.q); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Search(%q) = %v, want %v", tt.q.Text, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSearchHighlights(t *testing.T) {
+	idx := NewIndex()
+	idx.PutUser(1, &model.User{ID: 1, FirstName: "<b>Taro</b>", LastName: "Yamada & Co", Email: "taro@example.com"})
+
+	hits, err := idx.Search(tenant.NewContext(context.Background(), 1), &model.SearchQuery{Text: "taro yama"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]string{
+		"first_name": "&lt;b&gt;<em>Taro</em>&lt;/b&gt;",
+		"last_name":  "<em>Yama</em>da &amp; Co",
+		"email":      "<em>taro</em>@example.com",
+	}
+	if len(hits) != 1 || !reflect.DeepEqual(hits[0].Highlights, want) {
+		t.Fatalf("hits = %v, want the highlights %v", hits, want)
+	}
+	// the indexed user is a copy
+	hits[0].User.FirstName = "Jiro"
+	if hits, _ := idx.Search(tenant.NewContext(context.Background(), 1), &model.SearchQuery{Text: "jiro"}); len(hits) != 0 {
+		t.Errorf("changing a hit changed the index: %v", hits)
+	}
+}
+
+func TestSearchTenants(t *testing.T) {
+	idx := newTestIndex()
+	idx.PutCar(2, &model.Car{ID: 3, Name: "Leaf", Model: "Nissan"})
+
+	if got, want := search(t, idx, 2, model.SearchQuery{Text: "leaf"}), []model.SearchHit{{Type: model.SearchTypeCar, ID: 3, Score: 3}}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Search of tenant 2 = %v, want only its car", got)
+	}
+	if got := search(t, idx, 3, model.SearchQuery{Text: "leaf"}); len(got) != 0 {
+		t.Errorf("Search of a tenant without documents = %v, want none", got)
+	}
+	if _, err := idx.Search(context.Background(), &model.SearchQuery{Text: "leaf"}); !errors.Is(err, tenant.ErrMissing) {
+		t.Errorf("Search without a tenant = %v, want %v", err, tenant.ErrMissing)
+	}
+
+	// the words of each tenant are kept apart, and go with its last document
+	if _, ok := idx.shards[1].postings["leaf"]; !ok || len(idx.shards[2].postings["leaf"]) != 1 {
+		t.Errorf("postings of leaf = %v and %v, want one in each tenant", idx.shards[1].postings["leaf"], idx.shards[2].postings["leaf"])
+	}
+	idx.RemoveCar(3)
+	if _, ok := idx.shards[2]; ok {
+		t.Error("tenant without documents is kept")
+	}
+	if idx.Len() != 4 {
+		t.Errorf("Len = %d, want 4", idx.Len())
+	}
+
+	// a user put again replaces the words of the previous one
+	idx.PutUser(1, &model.User{ID: 2, FirstName: "Hanako", LastName: "Sato", Email: "hanako@example.com"})
+	if got := search(t, idx, 1, model.SearchQuery{Text: "leafson"}); len(got) != 0 {
+		t.Errorf("Search of the replaced name = %v, want none", got)
+	}
+	idx.RemoveUser(2)
+	if got := search(t, idx, 1, model.SearchQuery{Text: "hanako"}); len(got) != 0 {
+		t.Errorf("Search of the removed user = %v, want none", got)
+	}
+}
@@ -0,0 +1,156 @@
+package search
+
+import (
+	"html"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// the weights of the ways a query word matches a word of a field
+const (
+	exactMatch  = 1.0
+	prefixMatch = 0.7
+	fuzzyMatch  = 0.4
+)
+
+// minPrefixLength is the length of the shortest query word which matches the words it begins
+const minPrefixLength = 2
+
+// words splits the text into the lower case runs of letters and digits, so "john.doe@example.com" is john, doe, example and com
+func words(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+// uniqueWords returns the words of the text without the repeated ones
+func uniqueWords(text string) []string {
+	seen := make(map[string]bool)
+	res := make([]string, 0)
+	for _, w := range words(text) {
+		if !seen[w] {
+			seen[w] = true
+			res = append(res, w)
+		}
+	}
+	return res
+}
+
+// match returns the weight of the way the query word matches the word, or 0
+func match(query string, word string) float64 {
+	if query == word {
+		return exactMatch
+	}
+	n := utf8.RuneCountInString(query)
+	if n >= minPrefixLength && strings.HasPrefix(word, query) {
+		return prefixMatch
+	}
+	if typos := allowedTypos(n); typos > 0 && distance([]rune(query), []rune(word), typos) <= typos {
+		return fuzzyMatch
+	}
+	return 0
+}
+
+// allowedTypos is the edit distance allowed for a query word of the length
+func allowedTypos(n int) int {
+	switch {
+	case n >= 8:
+		return 2
+	case n >= 4:
+		return 1
+	}
+	return 0
+}
+
+// distance is the Levenshtein distance of a and b, or max+1 once it is known to be over max
+func distance(a []rune, b []rune, max int) int {
+	if d := len(a) - len(b); d > max || -d > max {
+		return max + 1
+	}
+	prev := make([]int, len(b)+1)
+	cur := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		cur[0] = i
+		rowMin := cur[0]
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = minInt(minInt(prev[j]+1, cur[j-1]+1), prev[j-1]+cost)
+			rowMin = minInt(rowMin, cur[j])
+		}
+		if rowMin > max {
+			return max + 1
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(b)]
+}
+
+func minInt(a int, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+// highlight wraps the words of the text which match the query words in <em>, only the beginning of the words matched by prefix.
+// The text is HTML-escaped. It returns false when no word matches
+func highlight(text string, queries []string) (string, bool) {
+	var b strings.Builder
+	matched := false
+	runes := []rune(text)
+	for i := 0; i < len(runes); {
+		if !isWordRune(runes[i]) {
+			j := i
+			for j < len(runes) && !isWordRune(runes[j]) {
+				j++
+			}
+			b.WriteString(html.EscapeString(string(runes[i:j])))
+			i = j
+			continue
+		}
+		j := i
+		for j < len(runes) && isWordRune(runes[j]) {
+			j++
+		}
+		word := runes[i:j]
+		n := highlightLength(strings.ToLower(string(word)), len(word), queries)
+		if n > 0 {
+			matched = true
+			b.WriteString("<em>" + html.EscapeString(string(word[:n])) + "</em>")
+		}
+		b.WriteString(html.EscapeString(string(word[n:])))
+		i = j
+	}
+	return b.String(), matched
+}
+
+// highlightLength is the number of the runes of the word to highlight for the best match of the query words
+func highlightLength(word string, length int, queries []string) int {
+	best := 0.0
+	n := 0
+	for _, q := range queries {
+		switch m := match(q, word); {
+		case m <= best:
+		case m == prefixMatch:
+			best, n = m, utf8.RuneCountInString(q)
+		default:
+			best, n = m, length
+		}
+	}
+	// lower casing may change the length
+	if n > length {
+		n = length
+	}
+	return n
+}
+
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r)
+}
@@ -0,0 +1,53 @@
+package search
+
+import "testing"
+
+func TestMatch(t *testing.T) {
+	tests := []struct {
+		query string
+		word  string
+		want  float64
+	}{
+		{query: "nissan", word: "nissan", want: exactMatch},
+		{query: "nis", word: "nissan", want: prefixMatch},
+		// one letter is too short to match by prefix
+		{query: "n", word: "nissan", want: 0},
+		{query: "nisan", word: "nissan", want: fuzzyMatch},
+		{query: "nisssan", word: "nissan", want: fuzzyMatch},
+		// three letters allow no typo, and four to seven one
+		{query: "lef", word: "leaf", want: 0},
+		{query: "nsan", word: "nissan", want: 0},
+		{query: "yamamoto", word: "yamaguto", want: fuzzyMatch},
+		{query: "yamamoto", word: "yamazaki", want: 0},
+		{query: "prius", word: "leaf", want: 0},
+	}
+	for _, tt := range tests {
+		if got := match(tt.query, tt.word); got != tt.want {
+			t.Errorf("match(%q, %q) = %v, want %v", tt.query, tt.word, got, tt.want)
+		}
+	}
+}
+
+func TestHighlight(t *testing.T) {
+	tests := []struct {
+		text    string
+		queries []string
+		want    string
+		ok      bool
+	}{
+		{text: "Yamada", queries: []string{"yamada"}, want: "<em>Yamada</em>", ok: true},
+		// only the beginning matched by prefix is highlighted
+		{text: "Yamada", queries: []string{"yama"}, want: "<em>Yama</em>da", ok: true},
+		{text: "Nissan Leaf", queries: []string{"nisan"}, want: "<em>Nissan</em> Leaf", ok: true},
+		{text: "taro@example.com", queries: []string{"taro", "exam"}, want: "<em>taro</em>@<em>exam</em>ple.com", ok: true},
+		// the text is escaped around and within the highlights
+		{text: "<b>Taro</b> & Co", queries: []string{"taro"}, want: "&lt;b&gt;<em>Taro</em>&lt;/b&gt; &amp; Co", ok: true},
+		{text: `"Leaf" <script>`, queries: []string{"prius"}, want: "&#34;Leaf&#34; &lt;script&gt;", ok: false},
+	}
+	for _, tt := range tests {
+		got, ok := highlight(tt.text, tt.queries)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("highlight(%q, %q) = %q, %v, want %q, %v", tt.text, tt.queries, got, ok, tt.want, tt.ok)
+		}
+	}
+}
@@ -0,0 +1,29 @@
+package sink
+
+import (
+	"context"
+	"github.com/jpdel518/go-ent/domain/event"
+	"golang.org/x/exp/slog"
+)
+
+type logSink struct{}
+
+// NewLogSink returns a sink which writes events to the log. The e-mail addresses in the payload are redacted by the logger
+func NewLogSink() event.Sink {
+	return &logSink{}
+}
+
+func (s *logSink) Name() string {
+	return "log"
+}
+
+func (s *logSink) Publish(ctx context.Context, e *event.Event) error {
+	slog.InfoCtx(ctx, "event",
+		"id", e.ID,
+		"type", e.Type,
+		"aggregate_type", e.AggregateType,
+		"aggregate_id", e.AggregateID,
+		"payload", string(e.Payload),
+	)
+	return nil
+}
@@ -0,0 +1,49 @@
+package sink
+
+import (
+	"context"
+	"github.com/jpdel518/go-ent/domain/event"
+	"sync"
+)
+
+// MemorySink keeps published events in memory. It is meant for tests
+type MemorySink struct {
+	mu     sync.Mutex
+	events []*event.Event
+	err    error
+}
+
+func NewMemorySink() *MemorySink {
+	return &MemorySink{}
+}
+
+func (s *MemorySink) Name() string {
+	return "memory"
+}
+
+func (s *MemorySink) Publish(ctx context.Context, e *event.Event) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.err != nil {
+		return s.err
+	}
+	s.events = append(s.events, e)
+	return nil
+}
+
+// Events returns the events published so far
+func (s *MemorySink) Events() []*event.Event {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([]*event.Event(nil), s.events...)
+}
+
+// FailWith makes Publish return err until it is called again with nil
+func (s *MemorySink) FailWith(err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.err = err
+}
@@ -0,0 +1,114 @@
+package stream
+
+import (
+	"github.com/jpdel518/go-ent/domain/model"
+	"github.com/jpdel518/go-ent/domain/repository"
+	"sync"
+)
+
+type subscriber struct {
+	filter model.ChangeFilter
+	ch     chan *model.Change
+}
+
+type changeBroker struct {
+	mu          sync.Mutex
+	lastID      uint64
+	buffer      []*model.Change
+	start       int
+	size        int
+	subscribers map[*subscriber]struct{}
+	queueSize   int
+	closed      bool
+}
+
+// NewChangeBroker returns an in-memory change feed which keeps the last replaySize changes for resuming.
+// Each subscriber can fall behind by queueSize changes before it is cut off.
+func NewChangeBroker(replaySize int, queueSize int) repository.ChangeFeed {
+	return &changeBroker{
+		buffer:      make([]*model.Change, replaySize),
+		subscribers: make(map[*subscriber]struct{}),
+		queueSize:   queueSize,
+	}
+}
+
+func (b *changeBroker) Publish(c *model.Change) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.lastID++
+	c.ID = b.lastID
+
+	// ring buffer
+	if len(b.buffer) > 0 {
+		if b.size < len(b.buffer) {
+			b.buffer[(b.start+b.size)%len(b.buffer)] = c
+			b.size++
+		} else {
+			b.buffer[b.start] = c
+			b.start = (b.start + 1) % len(b.buffer)
+		}
+	}
+
+	for s := range b.subscribers {
+		if !s.filter.Match(c) {
+			continue
+		}
+		select {
+		case s.ch <- c:
+		default:
+			// never make the writer wait for a slow subscriber
+			delete(b.subscribers, s)
+			close(s.ch)
+		}
+	}
+}
+
+func (b *changeBroker) Subscribe(filter model.ChangeFilter, lastID uint64) *model.ChangeSubscription {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	s := &subscriber{filter: filter, ch: make(chan *model.Change, b.queueSize)}
+	if b.closed {
+		close(s.ch)
+	} else {
+		b.subscribers[s] = struct{}{}
+	}
+
+	res := &model.ChangeSubscription{
+		C: s.ch,
+		Close: func() {
+			b.mu.Lock()
+			defer b.mu.Unlock()
+			if _, ok := b.subscribers[s]; ok {
+				delete(b.subscribers, s)
+				close(s.ch)
+			}
+		},
+	}
+	if lastID == 0 {
+		return res
+	}
+
+	// an ID ahead of the feed was issued before the process restarted
+	oldest := b.lastID - uint64(b.size) + 1
+	res.Missed = lastID > b.lastID || lastID+1 < oldest
+	for i := 0; i < b.size; i++ {
+		c := b.buffer[(b.start+i)%len(b.buffer)]
+		if c.ID > lastID && filter.Match(c) {
+			res.Replay = append(res.Replay, c)
+		}
+	}
+	return res
+}
+
+func (b *changeBroker) Close() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.closed = true
+	for s := range b.subscribers {
+		delete(b.subscribers, s)
+		close(s.ch)
+	}
+}
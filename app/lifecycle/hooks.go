@@ -0,0 +1,94 @@
+package lifecycle
+
+import (
+	"context"
+	"errors"
+	"google.golang.org/grpc"
+	"net"
+	"net/http"
+)
+
+// HTTPServer listens on srv.Addr at start and drains the requests in flight at stop
+func HTTPServer(l *Lifecycle, name string, srv *http.Server) Hook {
+	return Hook{
+		Name: name,
+		Start: func() error {
+			// listen here so that a busy port is a start failure
+			lis, err := net.Listen("tcp", srv.Addr)
+			if err != nil {
+				return err
+			}
+			go func() {
+				if err := srv.Serve(lis); err != nil && !errors.Is(err, http.ErrServerClosed) {
+					l.Fail(name, err)
+				}
+			}()
+			return nil
+		},
+		Stop: srv.Shutdown,
+	}
+}
+
+// GRPCServer listens on addr at start and waits for the running RPCs at stop
+func GRPCServer(l *Lifecycle, name string, srv *grpc.Server, addr string) Hook {
+	return Hook{
+		Name: name,
+		Start: func() error {
+			lis, err := net.Listen("tcp", addr)
+			if err != nil {
+				return err
+			}
+			go func() {
+				if err := srv.Serve(lis); err != nil {
+					l.Fail(name, err)
+				}
+			}()
+			return nil
+		},
+		Stop: func(ctx context.Context) error {
+			return wait(ctx, srv.GracefulStop, srv.Stop)
+		},
+	}
+}
+
+// Worker adapts a background worker whose Stop blocks until it has finished
+func Worker(name string, start func(), stop func()) Hook {
+	return Hook{
+		Name: name,
+		Start: func() error {
+			start()
+			return nil
+		},
+		Stop: func(ctx context.Context) error {
+			return wait(ctx, stop, nil)
+		},
+	}
+}
+
+// Closer closes a client such as the database pool at stop
+func Closer(name string, close func() error) Hook {
+	return Hook{
+		Name: name,
+		Stop: func(context.Context) error {
+			return close()
+		},
+	}
+}
+
+// wait runs stop and gives up when ctx is done, calling force if given
+func wait(ctx context.Context, stop func(), force func()) error {
+	done := make(chan struct{})
+	go func() {
+		stop()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		if force != nil {
+			force()
+		}
+		return ctx.Err()
+	}
+}
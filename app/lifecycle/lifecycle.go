@@ -0,0 +1,147 @@
+package lifecycle
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"golang.org/x/exp/slog"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// Hook is a component started and stopped by the lifecycle. Start and Stop are optional
+type Hook struct {
+	Name  string
+	Start func() error
+	// Stop should return when ctx is done even if the component has not stopped yet
+	Stop func(ctx context.Context) error
+}
+
+// StartError is returned by Run when a component failed to start or stopped by itself
+type StartError struct {
+	Name string
+	Err  error
+}
+
+func (e *StartError) Error() string {
+	return fmt.Sprintf("%s: %v", e.Name, e.Err)
+}
+
+func (e *StartError) Unwrap() error {
+	return e.Err
+}
+
+// ShutdownError is returned by Run when some components did not stop cleanly in time
+type ShutdownError struct {
+	Errs []error
+}
+
+func (e *ShutdownError) Error() string {
+	msgs := make([]string, 0, len(e.Errs))
+	for _, err := range e.Errs {
+		msgs = append(msgs, err.Error())
+	}
+	return "shutdown: " + strings.Join(msgs, "; ")
+}
+
+// Lifecycle starts the components in the order they are appended and stops them in reverse order
+type Lifecycle struct {
+	hooks           []Hook
+	shutdownTimeout time.Duration
+	failed          chan error
+}
+
+func New(shutdownTimeout time.Duration) *Lifecycle {
+	return &Lifecycle{
+		shutdownTimeout: shutdownTimeout,
+		failed:          make(chan error, 1),
+	}
+}
+
+func (l *Lifecycle) Append(h Hook) {
+	l.hooks = append(l.hooks, h)
+}
+
+// Fail makes Run shut down because a running component failed
+func (l *Lifecycle) Fail(name string, err error) {
+	select {
+	case l.failed <- &StartError{Name: name, Err: err}:
+	default:
+	}
+}
+
+// Run starts the components, waits for SIGINT, SIGTERM or a failure and then stops the components.
+func (l *Lifecycle) Run() error {
+	started := 0
+	for _, h := range l.hooks {
+		if h.Start != nil {
+			if err := h.Start(); err != nil {
+				slog.Error("failed starting", "hook", h.Name, "err", err)
+				l.stop(l.hooks[:started])
+				return &StartError{Name: h.Name, Err: err}
+			}
+		}
+		started++
+	}
+	slog.Info("application started")
+
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGINT, syscall.SIGTERM)
+	var cause error
+	select {
+	case sig := <-signals:
+		slog.Info("shutting down", "signal", sig.String())
+	case cause = <-l.failed:
+		slog.Error("shutting down", "cause", cause)
+	}
+	// a second signal kills the process at once
+	signal.Stop(signals)
+
+	if err := l.stop(l.hooks); err != nil {
+		if cause != nil {
+			slog.Error("failed shutting down", "err", err)
+			return cause
+		}
+		return err
+	}
+	slog.Info("application stopped")
+	return cause
+}
+
+// stop stops the hooks in reverse order sharing one deadline
+func (l *Lifecycle) stop(hooks []Hook) error {
+	ctx, cancel := context.WithTimeout(context.Background(), l.shutdownTimeout)
+	defer cancel()
+
+	var errs []error
+	for i := len(hooks) - 1; i >= 0; i-- {
+		h := hooks[i]
+		if h.Stop == nil {
+			continue
+		}
+		if err := h.Stop(ctx); err != nil {
+			slog.Error("failed stopping", "hook", h.Name, "err", err)
+			errs = append(errs, fmt.Errorf("%s: %w", h.Name, err))
+		}
+	}
+	if len(errs) > 0 {
+		return &ShutdownError{Errs: errs}
+	}
+	return nil
+}
+
+// ExitCode returns the process exit code for the result of Run.
+// 1 means the application failed to start or crashed, 2 means it did not shut down cleanly.
+func ExitCode(err error) int {
+	var shutdownErr *ShutdownError
+	switch {
+	case err == nil:
+		return 0
+	case errors.As(err, &shutdownErr):
+		return 2
+	}
+	return 1
+}
@@ -0,0 +1,199 @@
+package lifecycle
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"golang.org/x/exp/slog"
+	"io"
+	"os"
+	"os/signal"
+	"reflect"
+	"sync"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func TestMain(m *testing.M) {
+	slog.SetDefault(slog.New(slog.NewTextHandler(io.Discard)))
+	// listening to SIGTERM for the whole run keeps the signals of terminate from killing the process
+	signal.Notify(make(chan os.Signal, 1), syscall.SIGTERM)
+	os.Exit(m.Run())
+}
+
+// recorder records the order in which the hooks are started and stopped
+type recorder struct {
+	mu     sync.Mutex
+	events []string
+}
+
+func (r *recorder) record(event string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.events = append(r.events, event)
+}
+
+func (r *recorder) hook(name string, startErr error, stopErr error) Hook {
+	return Hook{
+		Name: name,
+		Start: func() error {
+			r.record("start " + name)
+			return startErr
+		},
+		Stop: func(ctx context.Context) error {
+			r.record("stop " + name)
+			return stopErr
+		},
+	}
+}
+
+// terminate sends SIGTERM to the process until done is closed, since Run may not be listening yet when the first one is sent
+func terminate(done <-chan struct{}) {
+	go func() {
+		ticker := time.NewTicker(10 * time.Millisecond)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				_ = syscall.Kill(os.Getpid(), syscall.SIGTERM)
+			}
+		}
+	}()
+}
+
+// run runs the lifecycle, terminating it once started
+func run(t *testing.T, l *Lifecycle) error {
+	t.Helper()
+	done := make(chan struct{})
+	defer close(done)
+	terminate(done)
+	return l.Run()
+}
+
+func TestRunStartFailure(t *testing.T) {
+	r := &recorder{}
+	l := New(time.Second)
+	startErr := errors.New("address already in use")
+	l.Append(r.hook("database", nil, nil))
+	l.Append(Hook{Name: "tracing"})
+	l.Append(r.hook("cache", nil, nil))
+	l.Append(r.hook("http server", startErr, nil))
+	l.Append(r.hook("worker", nil, nil))
+
+	err := l.Run()
+	var se *StartError
+	if !errors.As(err, &se) || se.Name != "http server" || !errors.Is(err, startErr) {
+		t.Fatalf("Run = %v, want the start error of the http server", err)
+	}
+	// the started components are stopped in reverse order, the failed and the later ones are not
+	want := []string{"start database", "start cache", "start http server", "stop cache", "stop database"}
+	if !reflect.DeepEqual(r.events, want) {
+		t.Errorf("events = %v, want %v", r.events, want)
+	}
+}
+
+func TestRunStopOrder(t *testing.T) {
+	r := &recorder{}
+	l := New(time.Second)
+	for _, name := range []string{"database", "worker", "http server"} {
+		l.Append(r.hook(name, nil, nil))
+	}
+
+	if err := run(t, l); err != nil {
+		t.Fatalf("Run = %v, want nil", err)
+	}
+	want := []string{"start database", "start worker", "start http server", "stop http server", "stop worker", "stop database"}
+	if !reflect.DeepEqual(r.events, want) {
+		t.Errorf("events = %v, want %v", r.events, want)
+	}
+}
+
+func TestRunFail(t *testing.T) {
+	r := &recorder{}
+	l := New(time.Second)
+	serveErr := errors.New("connection reset")
+	l.Append(r.hook("database", nil, nil))
+	l.Append(r.hook("grpc server", nil, errors.New("not stopped")))
+	l.Append(Hook{
+		Name: "http server",
+		Start: func() error {
+			// a server failing after it has started
+			l.Fail("http server", serveErr)
+			return nil
+		},
+	})
+
+	// the failure is the cause even when the shutdown fails too
+	err := l.Run()
+	var se *StartError
+	if !errors.As(err, &se) || se.Name != "http server" || !errors.Is(err, serveErr) {
+		t.Fatalf("Run = %v, want the failure of the http server", err)
+	}
+	want := []string{"start database", "start grpc server", "stop grpc server", "stop database"}
+	if !reflect.DeepEqual(r.events, want) {
+		t.Errorf("events = %v, want %v", r.events, want)
+	}
+}
+
+func TestRunShutdownDeadline(t *testing.T) {
+	const timeout = 100 * time.Millisecond
+	l := New(timeout)
+	var deadlines []time.Time
+	for _, name := range []string{"database", "grpc server", "http server"} {
+		l.Append(Hook{
+			Name: name,
+			Stop: func(ctx context.Context) error {
+				deadline, _ := ctx.Deadline()
+				deadlines = append(deadlines, deadline)
+				// a component which does not stop in time
+				<-ctx.Done()
+				return ctx.Err()
+			},
+		})
+	}
+
+	begin := time.Now()
+	err := run(t, l)
+	elapsed := time.Since(begin)
+
+	var se *ShutdownError
+	if !errors.As(err, &se) || len(se.Errs) != 3 {
+		t.Fatalf("Run = %v, want the errors of the 3 components", err)
+	}
+	if !errors.Is(se.Errs[0], context.DeadlineExceeded) {
+		t.Errorf("error = %v, want the deadline exceeded", se.Errs[0])
+	}
+	// the hooks share one deadline instead of getting one each
+	for _, deadline := range deadlines[1:] {
+		if !deadline.Equal(deadlines[0]) {
+			t.Errorf("deadlines = %v, want the same one", deadlines)
+		}
+	}
+	if elapsed >= 3*timeout {
+		t.Errorf("shutdown took %v, want about %v", elapsed, timeout)
+	}
+}
+
+func TestExitCode(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want int
+	}{
+		{name: "stopped cleanly", err: nil, want: 0},
+		{name: "failed starting", err: &StartError{Name: "http server", Err: errors.New("address already in use")}, want: 1},
+		{name: "other error", err: errors.New("unknown"), want: 1},
+		{name: "not stopped in time", err: &ShutdownError{Errs: []error{context.DeadlineExceeded}}, want: 2},
+		{name: "wrapped shutdown error", err: fmt.Errorf("run: %w", &ShutdownError{Errs: []error{context.DeadlineExceeded}}), want: 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ExitCode(tt.err); got != tt.want {
+				t.Errorf("ExitCode(%v) = %d, want %d", tt.err, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,102 @@
+package logging
+
+import (
+	"context"
+	"fmt"
+	"golang.org/x/exp/slog"
+	"runtime"
+	"sort"
+	"strings"
+)
+
+type packageLevel struct {
+	prefix string
+	level  slog.Level
+}
+
+// packageLevels decides the minimum level of a record by the package which logged it
+type packageLevels struct {
+	base slog.Level
+	// overrides are sorted by the length of the prefix so that the most specific one comes first
+	overrides []packageLevel
+	// min is the lowest of the levels, under which no record is written
+	min slog.Level
+}
+
+func newPackageLevels(base string, overrides map[string]string) (*packageLevels, error) {
+	l := &packageLevels{}
+	if err := l.base.UnmarshalText([]byte(base)); err != nil {
+		return nil, fmt.Errorf("log level %q: %w", base, err)
+	}
+	l.min = l.base
+	for prefix, level := range overrides {
+		var pl packageLevel
+		if err := pl.level.UnmarshalText([]byte(level)); err != nil {
+			return nil, fmt.Errorf("log level %q of %s: %w", level, prefix, err)
+		}
+		// the packages of this module may be given relative to it
+		pl.prefix = strings.Trim(prefix, "/")
+		if !strings.Contains(strings.SplitN(pl.prefix, "/", 2)[0], ".") {
+			pl.prefix = modulePath + pl.prefix
+		}
+		l.overrides = append(l.overrides, pl)
+		if pl.level < l.min {
+			l.min = pl.level
+		}
+	}
+	sort.Slice(l.overrides, func(i, j int) bool {
+		return len(l.overrides[i].prefix) > len(l.overrides[j].prefix)
+	})
+	return l, nil
+}
+
+// level returns the minimum level of the package of the function at pc
+func (l *packageLevels) level(pc uintptr) slog.Level {
+	if len(l.overrides) == 0 || pc == 0 {
+		return l.base
+	}
+	frame, _ := runtime.CallersFrames([]uintptr{pc}).Next()
+	pkg := packageOf(frame.Function)
+	for _, o := range l.overrides {
+		if pkg == o.prefix || strings.HasPrefix(pkg, o.prefix+"/") {
+			return o.level
+		}
+	}
+	return l.base
+}
+
+// packageOf returns the import path of a function name such as "github.com/a/b/pkg.(*T).Method".
+// The dots of the last element of the path are escaped in the names, such as "gopkg.in/natefinch/lumberjack%2ev2"
+func packageOf(function string) string {
+	slash := strings.LastIndex(function, "/")
+	dot := strings.Index(function[slash+1:], ".")
+	if dot < 0 {
+		return function
+	}
+	return strings.ReplaceAll(function[:slash+1+dot], "%2e", ".")
+}
+
+// levelHandler drops the records under the level of their package
+type levelHandler struct {
+	next   slog.Handler
+	levels *packageLevels
+}
+
+func (h *levelHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return level >= h.levels.min
+}
+
+func (h *levelHandler) Handle(ctx context.Context, r slog.Record) error {
+	if r.Level < h.levels.level(r.PC) {
+		return nil
+	}
+	return h.next.Handle(ctx, r)
+}
+
+func (h *levelHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &levelHandler{next: h.next.WithAttrs(attrs), levels: h.levels}
+}
+
+func (h *levelHandler) WithGroup(name string) slog.Handler {
+	return &levelHandler{next: h.next.WithGroup(name), levels: h.levels}
+}
@@ -0,0 +1,85 @@
+package logging
+
+import (
+	"golang.org/x/exp/slog"
+	"gopkg.in/natefinch/lumberjack.v2"
+	"io"
+	"log"
+	"os"
+)
+
+// modulePath is prepended to the package names of the level overrides which are relative to the module
+const modulePath = "github.com/jpdel518/go-ent/"
+
+// Config of the logger
+type Config struct {
+	// Level is the minimum level to write: debug, info, warn or error
+	Level string `yaml:"level" env:"LOG_LEVEL" required:"true"`
+	// Levels overrides Level for packages by the import path prefix, such as "infrastructure/rdb" or "entgo.io/ent".
+	// The environment sets it as "usecase=debug,infrastructure/rdb=warn"
+	Levels map[string]string `yaml:"levels" env:"LOG_LEVELS"`
+	// File is written besides the standard output when it is not empty. It is rotated by its size
+	File string `yaml:"file" env:"LOG_FILE"`
+	// MaxSizeMB is the size in megabytes at which the file is rotated
+	MaxSizeMB int `yaml:"max_size_mb" env:"LOG_MAX_SIZE_MB"`
+	// MaxBackups is the number of the rotated files to keep
+	MaxBackups int `yaml:"max_backups" env:"LOG_MAX_BACKUPS"`
+	// MaxAgeDays is the number of the days to keep the rotated files
+	MaxAgeDays int `yaml:"max_age_days" env:"LOG_MAX_AGE_DAYS"`
+	// Compress gzips the rotated files
+	Compress bool `yaml:"compress" env:"LOG_COMPRESS"`
+}
+
+// Validate checks the levels
+func (c Config) Validate() error {
+	_, err := newPackageLevels(c.Level, c.Levels)
+	return err
+}
+
+// Setup makes a JSON logger which follows the config the default of slog.
+// The log package writes through it as well, at the info level.
+// The returned closer closes the log file.
+func Setup(c Config) (io.Closer, error) {
+	h, closer, err := NewHandler(c, os.Stdout)
+	if err != nil {
+		return nil, err
+	}
+	// the caller of the log package is needed for the package levels
+	log.SetFlags(log.Lshortfile)
+	slog.SetDefault(slog.New(h))
+	return closer, nil
+}
+
+// NewHandler creates the handler which writes JSON lines to w and to the file of the config.
+// It adds the request scoped attributes of the context, redacts the personal data and filters the records by the package levels.
+func NewHandler(c Config, w io.Writer) (slog.Handler, io.Closer, error) {
+	levels, err := newPackageLevels(c.Level, c.Levels)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var closer io.Closer = nopCloser{}
+	if c.File != "" {
+		file := &lumberjack.Logger{
+			Filename:   c.File,
+			MaxSize:    c.MaxSizeMB,
+			MaxBackups: c.MaxBackups,
+			MaxAge:     c.MaxAgeDays,
+			Compress:   c.Compress,
+			LocalTime:  true,
+		}
+		w = io.MultiWriter(w, file)
+		closer = file
+	}
+
+	json := slog.HandlerOptions{
+		AddSource:   true,
+		Level:       levels.min,
+		ReplaceAttr: redact,
+	}.NewJSONHandler(w)
+	return &levelHandler{next: &contextHandler{next: json}, levels: levels}, closer, nil
+}
+
+type nopCloser struct{}
+
+func (nopCloser) Close() error { return nil }
@@ -0,0 +1,159 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"golang.org/x/exp/slog"
+	"strings"
+	"testing"
+)
+
+// newTestLogger returns a logger of the config whose records are decoded by the returned function
+func newTestLogger(t *testing.T, c Config) (*slog.Logger, func() []map[string]interface{}) {
+	t.Helper()
+	var buf bytes.Buffer
+	h, _, err := NewHandler(c, &buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return slog.New(h), func() []map[string]interface{} {
+		var records []map[string]interface{}
+		for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+			if line == "" {
+				continue
+			}
+			var r map[string]interface{}
+			if err := json.Unmarshal([]byte(line), &r); err != nil {
+				t.Fatalf("%q is not JSON: %v", line, err)
+			}
+			records = append(records, r)
+		}
+		buf.Reset()
+		return records
+	}
+}
+
+func TestRedaction(t *testing.T) {
+	logger, records := newTestLogger(t, Config{Level: "info"})
+
+	logger.Info("mail to taro@example.com was sent",
+		"email", "taro@example.com",
+		"First_Name", "Taro",
+		"query", "SELECT * FROM users WHERE email = 'hanako@example.co.jp'",
+		"err", errors.New("Duplicate entry 'jiro@example.com' for key 'users.email'"),
+		"user_id", 1,
+	)
+	r := records()[0]
+	want := map[string]interface{}{
+		"msg":        "mail to [REDACTED] was sent",
+		"email":      redacted,
+		"First_Name": redacted,
+		"query":      "SELECT * FROM users WHERE email = '[REDACTED]'",
+		"err":        "Duplicate entry '[REDACTED]' for key 'users.email'",
+		"user_id":    float64(1),
+	}
+	for key, value := range want {
+		if r[key] != value {
+			t.Errorf("%s = %v, want %v", key, r[key], value)
+		}
+	}
+}
+
+func TestRedactionOfSQLArgs(t *testing.T) {
+	logger, records := newTestLogger(t, Config{Level: "info"})
+
+	// the lines of the ent debug log, which come through the log package
+	logger.Info("driver.Tx(0f4d8e16): started")
+	logger.Info("Tx(0f4d8e16).Query: query=INSERT INTO `users` (`first_name`, `last_name`, `email`) VALUES (?, ?, ?) RETURNING `id` args=[Taro Yamada taro@example.com]")
+	logger.Info("driver.Query: query=SELECT * FROM `users` WHERE `first_name` = ? args=[Hanako\nSato]")
+	logger.Info("driver.Exec: query=DELETE FROM `users` args=[]")
+
+	want := []string{
+		"driver.Tx(0f4d8e16): started",
+		"Tx(0f4d8e16).Query: query=INSERT INTO `users` (`first_name`, `last_name`, `email`) VALUES (?, ?, ?) RETURNING `id` args=[[REDACTED]]",
+		"driver.Query: query=SELECT * FROM `users` WHERE `first_name` = ? args=[[REDACTED]]",
+		"driver.Exec: query=DELETE FROM `users` args=[[REDACTED]]",
+	}
+	got := records()
+	if len(got) != len(want) {
+		t.Fatalf("records = %d, want %d", len(got), len(want))
+	}
+	for i, r := range got {
+		if r["msg"] != want[i] {
+			t.Errorf("msg = %v, want %v", r["msg"], want[i])
+		}
+	}
+}
+
+func TestContextAttributes(t *testing.T) {
+	logger, records := newTestLogger(t, Config{Level: "info"})
+
+	logger.InfoCtx(context.Background(), "anonymous")
+	ctx := WithPrincipal(WithRequestID(context.Background(), "req-1"), "key:0123")
+	logger.InfoCtx(ctx, "by a client")
+
+	rs := records()
+	if _, ok := rs[0]["principal"]; ok {
+		t.Errorf("record without a principal = %v", rs[0])
+	}
+	if rs[1]["request_id"] != "req-1" || rs[1]["principal"] != "key:0123" {
+		t.Errorf("record = %v, want the request id and the principal", rs[1])
+	}
+}
+
+func TestPackageLevels(t *testing.T) {
+	tests := []struct {
+		name   string
+		config Config
+		want   []string
+	}{
+		{name: "base level", config: Config{Level: "info"}, want: []string{"INFO", "WARN"}},
+		{name: "package relative to the module", config: Config{Level: "info", Levels: map[string]string{"logging": "warn"}}, want: []string{"WARN"}},
+		{name: "lower than the base", config: Config{Level: "warn", Levels: map[string]string{"/logging/": "debug"}}, want: []string{"DEBUG", "INFO", "WARN"}},
+		{name: "most specific prefix", config: Config{Level: "info", Levels: map[string]string{"github.com/jpdel518": "error", "github.com/jpdel518/go-ent/logging": "debug"}}, want: []string{"DEBUG", "INFO", "WARN"}},
+		{name: "other packages", config: Config{Level: "info", Levels: map[string]string{"usecase": "debug", "entgo.io/ent": "error"}}, want: []string{"INFO", "WARN"}},
+		{name: "prefix of another name", config: Config{Level: "info", Levels: map[string]string{"log": "error"}}, want: []string{"INFO", "WARN"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logger, records := newTestLogger(t, tt.config)
+			logger.Debug("debug")
+			logger.Info("info")
+			logger.Warn("warn")
+
+			var got []string
+			for _, r := range records() {
+				got = append(got, r["level"].(string))
+			}
+			if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+				t.Errorf("levels = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	if err := (Config{Level: "info", Levels: map[string]string{"usecase": "loud"}}).Validate(); err == nil {
+		t.Error("unknown level of a package is valid")
+	}
+	if err := (Config{Level: "loud"}).Validate(); err == nil {
+		t.Error("unknown level is valid")
+	}
+}
+
+func TestPackageOf(t *testing.T) {
+	tests := map[string]string{
+		"github.com/jpdel518/go-ent/usecase.(*userUsecase).Fetch":       "github.com/jpdel518/go-ent/usecase",
+		"github.com/jpdel518/go-ent/usecase.(*userUsecase).Fetch.func1": "github.com/jpdel518/go-ent/usecase",
+		"github.com/jpdel518/go-ent/infrastructure/rdb.toModelUser":     "github.com/jpdel518/go-ent/infrastructure/rdb",
+		"gopkg.in/natefinch/lumberjack%2ev2.(*Logger).Write":            "gopkg.in/natefinch/lumberjack.v2",
+		"entgo.io/ent/dialect/sql.(*Selector).Query":                    "entgo.io/ent/dialect/sql",
+		"main.main":            "main",
+		"log.(*Logger).Output": "log",
+	}
+	for function, want := range tests {
+		if got := packageOf(function); got != want {
+			t.Errorf("packageOf(%q) = %q, want %q", function, got, want)
+		}
+	}
+}
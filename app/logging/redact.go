@@ -0,0 +1,53 @@
+package logging
+
+import (
+	"golang.org/x/exp/slog"
+	"regexp"
+	"strings"
+)
+
+const redacted = "[REDACTED]"
+
+// piiKeys are the keys whose values are personal data
+var piiKeys = map[string]bool{
+	"email":      true,
+	"e_mail":     true,
+	"first_name": true,
+	"last_name":  true,
+	"firstname":  true,
+	"lastname":   true,
+	"full_name":  true,
+}
+
+var emailPattern = regexp.MustCompile(`[A-Za-z0-9._%+\-]+@[A-Za-z0-9.\-]+\.[A-Za-z]{2,}`)
+
+// sqlArgsPattern matches the arguments which end the queries of the ent debug log, such as
+// "driver.Exec: query=INSERT INTO `users` ... args=[Taro Yamada taro@example.com 20]".
+// The names and the other personal data cannot be told apart from the rest of them, so all of them are hidden
+var sqlArgsPattern = regexp.MustCompile(`(?s)\bargs=\[.*\]$`)
+
+// redact hides the values of the personal data keys, the SQL arguments of the ent debug log,
+// and the e-mail addresses in the messages and the other strings such as the duplicate entry errors.
+// Structs are written as they are, so log their ids instead.
+func redact(groups []string, a slog.Attr) slog.Attr {
+	if piiKeys[strings.ToLower(a.Key)] {
+		return slog.String(a.Key, redacted)
+	}
+	switch a.Value.Kind() {
+	case slog.KindString:
+		if s := a.Value.String(); emailPattern.MatchString(s) || sqlArgsPattern.MatchString(s) {
+			return slog.String(a.Key, redactString(s))
+		}
+	case slog.KindAny:
+		// such as a duplicate entry error of the e-mail
+		if err, ok := a.Value.Any().(error); ok {
+			return slog.String(a.Key, redactString(err.Error()))
+		}
+	}
+	return a
+}
+
+func redactString(s string) string {
+	s = sqlArgsPattern.ReplaceAllString(s, "args=["+redacted+"]")
+	return emailPattern.ReplaceAllString(s, redacted)
+}
@@ -0,0 +1,32 @@
+package logging
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+)
+
+// RequestIDHeader carries the request id from the client or the proxy, and back in the response
+const RequestIDHeader = "X-Request-ID"
+
+// NewRequestID returns a random id of 32 hex characters
+func NewRequestID() string {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return ""
+	}
+	return hex.EncodeToString(b)
+}
+
+// ValidRequestID reports whether an id given by a client can be used as is.
+// It must be short and made of letters, digits, '-', '_' and '.' not to break the log lines.
+func ValidRequestID(id string) bool {
+	if id == "" || len(id) > 64 {
+		return false
+	}
+	for _, c := range id {
+		if !(c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '-' || c == '_' || c == '.') {
+			return false
+		}
+	}
+	return true
+}
@@ -1,30 +1,87 @@
 package main
 
 import (
+	"context"
+	"errors"
+	"flag"
+	"github.com/jpdel518/go-ent/config"
 	"github.com/jpdel518/go-ent/infrastructure/file"
 	"github.com/jpdel518/go-ent/infrastructure/file/s3"
+	"github.com/jpdel518/go-ent/infrastructure/mail"
+	"github.com/jpdel518/go-ent/infrastructure/notification"
 	"github.com/jpdel518/go-ent/infrastructure/rdb"
 	"github.com/jpdel518/go-ent/infrastructure/rdb/mysql"
-	"github.com/jpdel518/go-ent/presentation/handler"
-	"github.com/jpdel518/go-ent/usecase"
+	"github.com/jpdel518/go-ent/lifecycle"
+	"github.com/jpdel518/go-ent/logging"
+	"github.com/jpdel518/go-ent/metrics"
+	"github.com/jpdel518/go-ent/tracing"
 	"github.com/jpdel518/go-ent/utils"
+	"golang.org/x/exp/slog"
+	"log"
 	"os"
-	"time"
 )
 
-func init() {
+func main() {
 	utils.LoadEnv()
-	utils.LoggingSettings(os.Getenv("LOG_FILE"))
-	mysql.InitDatabase()
-}
+	cfg, err := config.Load(os.Args[1:])
+	if errors.Is(err, flag.ErrHelp) || errors.Is(err, config.ErrPrinted) {
+		return
+	}
+	if err != nil {
+		log.Fatalf("failed loading config: %v", err)
+	}
+	logFile, err := logging.Setup(cfg.Log)
+	if err != nil {
+		log.Fatalf("failed setting up logger: %v", err)
+	}
+	slog.Debug("config loaded", "config", cfg.String())
+	mysql.InitDatabase(cfg.Database, !cfg.Production())
+
+	shutdownTracing, err := tracing.Setup(context.Background(), cfg.Tracing)
+	if err != nil {
+		log.Fatalf("failed setting up tracing: %v", err)
+	}
 
-func main() {
 	// Dependency Injection
-	client := mysql.NewClient()
-	userRepository := rdb.NewUserRepository(client)
-	carRepository := rdb.NewCarRepository(client)
-	session := s3.NewS3Session()
-	userFileRepository := file.NewUserFileRepository(session)
-	userUsecase := usecase.NewUserUsecase(userRepository, carRepository, userFileRepository, 30*time.Second)
-	handler.NewHandler(userUsecase)
+	driver := mysql.NewDriver(cfg.Database)
+	metrics.RegisterDB(driver.DB(), cfg.Database.Name)
+	session := s3.NewS3Session(cfg.Storage)
+	mailSender, err := mail.NewSender(cfg.Mail)
+	if err != nil {
+		log.Fatalf("failed setting up mail: %v", err)
+	}
+	renderer, err := notification.NewRenderer(cfg.Notifications.DefaultLanguage)
+	if err != nil {
+		log.Fatalf("failed parsing notification templates: %v", err)
+	}
+	components := newComponents(cfg, driver, storage{
+		userFiles: file.NewUserFileRepository(session),
+		jobFiles:  file.NewJobFileRepository(session),
+		checker:   file.NewStorageChecker(session),
+	}, mailSender, renderer)
+
+	// Lifecycle: started in this order and stopped in reverse order
+	app := lifecycle.New(cfg.ShutdownTimeout)
+	app.Append(lifecycle.Closer("log file", logFile.Close))
+	// stopped last to export the spans of the shutdown
+	app.Append(lifecycle.Hook{Name: "tracing", Stop: shutdownTracing})
+	app.Append(lifecycle.Closer("database", components.client.Close))
+	app.Append(lifecycle.Closer("storage", session.Close))
+	// the index is filled before the servers serve /search
+	app.Append(lifecycle.Hook{Name: "search index", Start: func() error {
+		return rdb.LoadSearchIndex(context.Background(), components.client, components.searchIndex)
+	}})
+	app.Append(lifecycle.Worker("job workers", components.jobUsecase.Start, components.jobUsecase.Stop))
+	app.Append(lifecycle.Worker("webhook dispatcher", components.webhookDispatcher.Start, components.webhookDispatcher.Stop))
+	app.Append(lifecycle.Worker("notifier", components.notifier.Start, components.notifier.Stop))
+	app.Append(lifecycle.Worker("event relay", components.eventRelay.Start, components.eventRelay.Stop))
+	app.Append(lifecycle.Worker("idempotency key purger", components.idempotencyUsecase.Start, components.idempotencyUsecase.Stop))
+	app.Append(lifecycle.GRPCServer(app, "grpc server", components.grpcServer, cfg.GRPC.Addr))
+	app.Append(lifecycle.HTTPServer(app, "http server", components.httpServer))
+
+	err = app.Run()
+	if err != nil {
+		slog.Error("application failed", "err", err)
+	}
+	os.Exit(lifecycle.ExitCode(err))
 }
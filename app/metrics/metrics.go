@@ -0,0 +1,211 @@
+package metrics
+
+import (
+	"database/sql"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// The labels only take values out of fixed sets such as route patterns, ent types and job types,
+// so that the number of series stays bounded. Never label with raw paths, ids or keys.
+
+const namespace = "goent"
+
+const (
+	ResultSuccess = "success"
+	ResultError   = "error"
+)
+
+var registry = prometheus.NewRegistry()
+
+var (
+	httpRequestDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "http",
+		Name:      "request_duration_seconds",
+		Help:      "Latency of the HTTP requests by route pattern, method and status code.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"route", "method", "code"})
+	httpRequestsInFlight = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Subsystem: "http",
+		Name:      "requests_in_flight",
+		Help:      "Number of the HTTP requests being served, including the open event streams.",
+	})
+
+	dbOperationDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "db",
+		Name:      "operation_duration_seconds",
+		Help:      "Latency of the statements sent through the ent driver by operation (query, exec, begin, commit, rollback) and result.",
+		Buckets:   []float64{.0005, .001, .0025, .005, .01, .025, .05, .1, .25, .5, 1, 2.5},
+	}, []string{"operation", "result"})
+	entMutationDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "ent",
+		Name:      "mutation_duration_seconds",
+		Help:      "Latency of the ent mutations by type, op and result.",
+		Buckets:   []float64{.001, .0025, .005, .01, .025, .05, .1, .25, .5, 1, 2.5},
+	}, []string{"type", "op", "result"})
+
+	storageOperationDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "storage",
+		Name:      "operation_duration_seconds",
+		Help:      "Latency of the S3 operations by operation (upload, download, delete, list) and result.",
+		Buckets:   []float64{.01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10, 30, 60},
+	}, []string{"operation", "result"})
+	storageBytes = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "storage",
+		Name:      "bytes_total",
+		Help:      "Bytes sent to and received from S3 by operation.",
+	}, []string{"operation"})
+
+	cacheLookups = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "cache",
+		Name:      "lookups_total",
+		Help:      "Lookups of the read-through cache by entity and result (hit, miss, error).",
+	}, []string{"entity", "result"})
+
+	jobWorkers = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Subsystem: "job",
+		Name:      "workers",
+		Help:      "Number of the running job workers.",
+	})
+	jobWorkersBusy = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Subsystem: "job",
+		Name:      "workers_busy",
+		Help:      "Number of the job workers running a job.",
+	})
+	jobsRunning = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Subsystem: "job",
+		Name:      "running",
+		Help:      "Number of the jobs being run by type.",
+	}, []string{"type"})
+	jobDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "job",
+		Name:      "duration_seconds",
+		Help:      "Run time of the jobs by type and final status (succeeded, failed, canceled, requeued).",
+		Buckets:   []float64{.1, .5, 1, 5, 10, 30, 60, 300, 900, 1800, 3600},
+	}, []string{"type", "status"})
+)
+
+func init() {
+	registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		httpRequestDuration,
+		httpRequestsInFlight,
+		dbOperationDuration,
+		entMutationDuration,
+		storageOperationDuration,
+		storageBytes,
+		cacheLookups,
+		jobWorkers,
+		jobWorkersBusy,
+		jobsRunning,
+		jobDuration,
+	)
+}
+
+// Handler serves the metrics in the Prometheus exposition format
+func Handler() http.Handler {
+	return promhttp.HandlerFor(registry, promhttp.HandlerOpts{})
+}
+
+// Gatherer gathers the metrics served by Handler
+func Gatherer() prometheus.Gatherer {
+	return registry
+}
+
+// RegisterDB exports the connection pool stats of db such as open, idle and waiting connections
+func RegisterDB(db *sql.DB, name string) {
+	registry.MustRegister(collectors.NewDBStatsCollector(db, name))
+}
+
+// Result returns the result label of err
+func Result(err error) string {
+	if err != nil {
+		return ResultError
+	}
+	return ResultSuccess
+}
+
+// methods are the request methods which are labeled as is. The others are labeled as OTHER
+var methods = map[string]bool{
+	http.MethodGet:     true,
+	http.MethodHead:    true,
+	http.MethodPost:    true,
+	http.MethodPut:     true,
+	http.MethodPatch:   true,
+	http.MethodDelete:  true,
+	http.MethodOptions: true,
+}
+
+// HTTPRequestStarted counts a request in flight. Call the returned function when it is done
+func HTTPRequestStarted() func() {
+	httpRequestsInFlight.Inc()
+	return httpRequestsInFlight.Dec
+}
+
+// ObserveHTTPRequest records a request served by the handler of the route pattern
+func ObserveHTTPRequest(route string, method string, code int, d time.Duration) {
+	if !methods[method] {
+		method = "OTHER"
+	}
+	httpRequestDuration.WithLabelValues(route, method, strconv.Itoa(code)).Observe(d.Seconds())
+}
+
+// ObserveDBOperation records a statement sent to the database
+func ObserveDBOperation(operation string, err error, d time.Duration) {
+	dbOperationDuration.WithLabelValues(operation, Result(err)).Observe(d.Seconds())
+}
+
+// ObserveEntMutation records a mutation of the ent type
+func ObserveEntMutation(typ string, op string, err error, d time.Duration) {
+	entMutationDuration.WithLabelValues(typ, op, Result(err)).Observe(d.Seconds())
+}
+
+// ObserveStorageOperation records an S3 operation
+func ObserveStorageOperation(operation string, err error, d time.Duration) {
+	storageOperationDuration.WithLabelValues(operation, Result(err)).Observe(d.Seconds())
+}
+
+// AddStorageBytes counts the bytes transferred by an S3 operation
+func AddStorageBytes(operation string, n int64) {
+	if n > 0 {
+		storageBytes.WithLabelValues(operation).Add(float64(n))
+	}
+}
+
+// ObserveCacheLookup counts a lookup of the read-through cache
+func ObserveCacheLookup(entity string, result string) {
+	cacheLookups.WithLabelValues(entity, result).Inc()
+}
+
+// AddJobWorkers changes the number of the running job workers
+func AddJobWorkers(n int) {
+	jobWorkers.Add(float64(n))
+}
+
+// JobStarted counts a job being run. Call the returned function with the final status when it is done
+func JobStarted(jobType string) func(status string) {
+	start := time.Now()
+	jobWorkersBusy.Inc()
+	jobsRunning.WithLabelValues(jobType).Inc()
+	return func(status string) {
+		jobWorkersBusy.Dec()
+		jobsRunning.WithLabelValues(jobType).Dec()
+		jobDuration.WithLabelValues(jobType, status).Observe(time.Since(start).Seconds())
+	}
+}
@@ -0,0 +1,122 @@
+package metrics
+
+import (
+	"database/sql"
+	"errors"
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRegisterDB(t *testing.T) {
+	db, err := sql.Open("sqlite3", "file:metrics?mode=memory&cache=shared")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	db.SetMaxOpenConns(7)
+	if err := db.Ping(); err != nil {
+		t.Fatal(err)
+	}
+	RegisterDB(db, "sample_db")
+
+	want := `
+# HELP go_sql_max_open_connections Maximum number of open connections to the database.
+# TYPE go_sql_max_open_connections gauge
+go_sql_max_open_connections{db_name="sample_db"} 7
+# HELP go_sql_open_connections The number of established connections both in use and idle.
+# TYPE go_sql_open_connections gauge
+go_sql_open_connections{db_name="sample_db"} 1
+`
+	if err := testutil.GatherAndCompare(registry, strings.NewReader(want), "go_sql_max_open_connections", "go_sql_open_connections"); err != nil {
+		t.Error(err)
+	}
+	for _, name := range []string{"go_sql_idle_connections", "go_sql_in_use_connections", "go_sql_wait_count_total", "go_sql_wait_duration_seconds_total"} {
+		if n, err := testutil.GatherAndCount(registry, name); err != nil || n != 1 {
+			t.Errorf("%s = %d series, %v, want 1", name, n, err)
+		}
+	}
+}
+
+func TestStorage(t *testing.T) {
+	ObserveStorageOperation("list", nil, 30*time.Millisecond)
+	ObserveStorageOperation("list", errors.New("access denied"), 10*time.Millisecond)
+	ObserveStorageOperation("delete", nil, 20*time.Millisecond)
+	AddStorageBytes("download", 100)
+	AddStorageBytes("download", 28)
+	// nothing read makes no series
+	AddStorageBytes("delete", 0)
+
+	if n := testutil.CollectAndCount(storageOperationDuration); n != 3 {
+		t.Errorf("storage operation series = %d, want list by result and delete", n)
+	}
+	want := `
+# HELP goent_storage_bytes_total Bytes sent to and received from S3 by operation.
+# TYPE goent_storage_bytes_total counter
+goent_storage_bytes_total{operation="download"} 128
+`
+	if err := testutil.CollectAndCompare(storageBytes, strings.NewReader(want)); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestJobs(t *testing.T) {
+	AddJobWorkers(4)
+	defer AddJobWorkers(-4)
+	done := JobStarted("user_import")
+
+	running := `
+# HELP goent_job_running Number of the jobs being run by type.
+# TYPE goent_job_running gauge
+goent_job_running{type="user_import"} 1
+# HELP goent_job_workers Number of the running job workers.
+# TYPE goent_job_workers gauge
+goent_job_workers 4
+# HELP goent_job_workers_busy Number of the job workers running a job.
+# TYPE goent_job_workers_busy gauge
+goent_job_workers_busy 1
+`
+	if err := testutil.GatherAndCompare(registry, strings.NewReader(running), "goent_job_running", "goent_job_workers", "goent_job_workers_busy"); err != nil {
+		t.Error(err)
+	}
+
+	done("succeeded")
+	if got := testutil.ToFloat64(jobWorkersBusy); got != 0 {
+		t.Errorf("busy workers = %v, want 0 after the job", got)
+	}
+	if got := testutil.ToFloat64(jobsRunning.WithLabelValues("user_import")); got != 0 {
+		t.Errorf("running user imports = %v, want 0 after the job", got)
+	}
+	if n, err := testutil.GatherAndCount(registry, "goent_job_duration_seconds"); err != nil || n != 1 {
+		t.Errorf("job duration series = %d, %v, want the succeeded user import", n, err)
+	}
+}
+
+func TestObserveHTTPRequest(t *testing.T) {
+	ObserveHTTPRequest("/jobs/", "GET", 200, time.Millisecond)
+	ObserveHTTPRequest("/jobs/", "PROPFIND", 405, time.Millisecond)
+
+	families, err := registry.Gather()
+	if err != nil {
+		t.Fatal(err)
+	}
+	methods := make(map[string]bool)
+	for _, f := range families {
+		if f.GetName() != "goent_http_request_duration_seconds" {
+			continue
+		}
+		for _, m := range f.GetMetric() {
+			for _, l := range m.GetLabel() {
+				if l.GetName() == "method" {
+					methods[l.GetValue()] = true
+				}
+			}
+		}
+	}
+	// the unknown methods are not labeled as is
+	if !methods["GET"] || !methods["OTHER"] || methods["PROPFIND"] {
+		t.Errorf("methods = %v, want GET and OTHER", methods)
+	}
+}
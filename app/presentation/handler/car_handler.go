@@ -0,0 +1,277 @@
+package handler
+
+import (
+	"encoding/json"
+	"github.com/jpdel518/go-ent/domain/model"
+	"github.com/jpdel518/go-ent/usecase"
+	"golang.org/x/exp/slog"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+type CarHandler struct {
+	usecase     usecase.CarUsecase
+	userUsecase usecase.UserUsecase
+}
+
+func NewCarHandler(usecase usecase.CarUsecase, userUsecase usecase.UserUsecase) *CarHandler {
+	return &CarHandler{usecase: usecase, userUsecase: userUsecase}
+}
+
+// Cars handles /cars
+func (h *CarHandler) Cars(w http.ResponseWriter, r *http.Request) {
+	if r.Method == http.MethodPost {
+		h.Create(w, r)
+		return
+	}
+	h.Fetch(w, r)
+}
+
+// Car handles /cars/{id} and /cars/{id}/transfer
+func (h *CarHandler) Car(w http.ResponseWriter, r *http.Request) {
+	if strings.HasSuffix(r.URL.Path, "/transfer") {
+		h.Transfer(w, r)
+		return
+	}
+	switch r.Method {
+	case http.MethodPut:
+		h.Update(w, r)
+	case http.MethodDelete:
+		h.Delete(w, r)
+	default:
+		h.GetById(w, r)
+	}
+}
+
+func (h *CarHandler) Fetch(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+
+	// get query parameters
+	num, err := strconv.Atoi(r.URL.Query().Get("num"))
+	if err != nil {
+		num = 10
+	}
+
+	// fetch cars
+	cars, err := h.usecase.Fetch(r.Context(), num)
+	if err != nil {
+		slog.ErrorCtx(r.Context(), "request failed", "err", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		_, _ = w.Write(CreateResponseJson(&ApiRequestResponse{Code: 5100, Data: err.Error()}))
+		return
+	}
+
+	// response
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write(CreateResponseJson(&ApiRequestResponse{Code: 2000, Data: cars}))
+}
+
+func (h *CarHandler) GetById(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+
+	// get path parameters
+	id, err := carID(r.URL.Path)
+	if err != nil {
+		slog.ErrorCtx(r.Context(), "request failed", "err", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		_, _ = w.Write(CreateResponseJson(&ApiRequestResponse{Code: 5201, Data: err.Error()}))
+		return
+	}
+
+	// fetch car
+	car, err := h.usecase.GetByID(r.Context(), id)
+	if err != nil {
+		slog.ErrorCtx(r.Context(), "request failed", "err", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		_, _ = w.Write(CreateResponseJson(&ApiRequestResponse{Code: 5200, Data: err.Error()}))
+		return
+	}
+
+	// response
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write(CreateResponseJson(&ApiRequestResponse{Code: 2000, Data: car}))
+}
+
+func (h *CarHandler) Create(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+
+	// get parameters
+	car := &model.Car{}
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		slog.ErrorCtx(r.Context(), "request failed", "err", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		_, _ = w.Write(CreateResponseJson(&ApiRequestResponse{Code: 5301, Data: err.Error()}))
+		return
+	}
+	err = json.Unmarshal(body, car)
+	if err != nil {
+		slog.ErrorCtx(r.Context(), "request failed", "err", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		_, _ = w.Write(CreateResponseJson(&ApiRequestResponse{Code: 5302, Data: err.Error()}))
+		return
+	}
+
+	// create car
+	err = h.usecase.Create(r.Context(), car)
+	if err != nil {
+		slog.ErrorCtx(r.Context(), "request failed", "err", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		_, _ = w.Write(CreateResponseJson(&ApiRequestResponse{Code: 5300, Data: err.Error()}))
+		return
+	}
+
+	// response
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write(CreateResponseJson(&ApiRequestResponse{Code: 2000, Data: car}))
+}
+
+func (h *CarHandler) Update(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPut {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+
+	// get parameters
+	id, err := carID(r.URL.Path)
+	if err != nil {
+		slog.ErrorCtx(r.Context(), "request failed", "err", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		_, _ = w.Write(CreateResponseJson(&ApiRequestResponse{Code: 5401, Data: err.Error()}))
+		return
+	}
+	car := &model.Car{}
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		slog.ErrorCtx(r.Context(), "request failed", "err", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		_, _ = w.Write(CreateResponseJson(&ApiRequestResponse{Code: 5402, Data: err.Error()}))
+		return
+	}
+	err = json.Unmarshal(body, car)
+	if err != nil {
+		slog.ErrorCtx(r.Context(), "request failed", "err", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		_, _ = w.Write(CreateResponseJson(&ApiRequestResponse{Code: 5403, Data: err.Error()}))
+		return
+	}
+	car.ID = id
+
+	// update car
+	err = h.usecase.Update(r.Context(), car)
+	if err != nil {
+		slog.ErrorCtx(r.Context(), "request failed", "err", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		_, _ = w.Write(CreateResponseJson(&ApiRequestResponse{Code: 5400, Data: err.Error()}))
+		return
+	}
+
+	// response
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write(CreateResponseJson(&ApiRequestResponse{Code: 2000, Data: car}))
+}
+
+func (h *CarHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodDelete {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+
+	// get path parameters
+	id, err := carID(r.URL.Path)
+	if err != nil {
+		slog.ErrorCtx(r.Context(), "request failed", "err", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		_, _ = w.Write(CreateResponseJson(&ApiRequestResponse{Code: 5501, Data: err.Error()}))
+		return
+	}
+
+	// delete car
+	err = h.usecase.Delete(r.Context(), id)
+	if err != nil {
+		slog.ErrorCtx(r.Context(), "request failed", "err", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		_, _ = w.Write(CreateResponseJson(&ApiRequestResponse{Code: 5500, Data: err.Error()}))
+		return
+	}
+
+	// response
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write(CreateResponseJson(&ApiRequestResponse{Code: 2000, Data: "success"}))
+}
+
+// Transfer gives the car to the user of {"user_id": 1}, taking it from its owner
+func (h *CarHandler) Transfer(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+
+	// get parameters
+	id, err := carID(strings.TrimSuffix(r.URL.Path, "/transfer"))
+	if err != nil {
+		slog.ErrorCtx(r.Context(), "request failed", "err", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		_, _ = w.Write(CreateResponseJson(&ApiRequestResponse{Code: 5601, Data: err.Error()}))
+		return
+	}
+	transfer := &model.CarTransfer{}
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		slog.ErrorCtx(r.Context(), "request failed", "err", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		_, _ = w.Write(CreateResponseJson(&ApiRequestResponse{Code: 5602, Data: err.Error()}))
+		return
+	}
+	err = json.Unmarshal(body, transfer)
+	if err == nil {
+		err = transfer.Validate()
+	}
+	if err != nil {
+		slog.ErrorCtx(r.Context(), "request failed", "err", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		_, _ = w.Write(CreateResponseJson(&ApiRequestResponse{Code: 5603, Data: err.Error()}))
+		return
+	}
+
+	// transfer car
+	user, err := h.userUsecase.TransferCar(r.Context(), id, transfer.UserID)
+	if err != nil {
+		slog.ErrorCtx(r.Context(), "request failed", "err", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		_, _ = w.Write(CreateResponseJson(&ApiRequestResponse{Code: 5600, Data: err.Error()}))
+		return
+	}
+
+	// response
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write(CreateResponseJson(&ApiRequestResponse{Code: 2000, Data: user}))
+}
+
+// carID reads the id from /cars/{id}
+func carID(path string) (int, error) {
+	return strconv.Atoi(strings.TrimPrefix(path, "/cars/"))
+}
@@ -0,0 +1,159 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/jpdel518/go-ent/domain/model"
+	"github.com/jpdel518/go-ent/usecase"
+	"golang.org/x/exp/slog"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// sseHeartbeatInterval keeps proxies from closing idle streams
+	sseHeartbeatInterval = 15 * time.Second
+	// sseRetry is the reconnection delay suggested to the clients in milliseconds
+	sseRetry = 3000
+)
+
+type EventHandler struct {
+	usecase usecase.ChangeUsecase
+}
+
+func NewEventHandler(usecase usecase.ChangeUsecase) *EventHandler {
+	return &EventHandler{usecase}
+}
+
+// Stream sends the changes of users, cars and groups as Server-Sent Events.
+// ?type=user,car and ?id=1,2 filter the changes, and Last-Event-ID resumes the stream.
+func (h *EventHandler) Stream(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusInternalServerError)
+		_, _ = w.Write(CreateResponseJson(&ApiRequestResponse{Code: 4602, Data: "streaming is not supported"}))
+		return
+	}
+
+	// get parameters
+	filter, err := changeFilter(r)
+	if err != nil {
+		slog.ErrorCtx(r.Context(), "request failed", "err", err)
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusInternalServerError)
+		_, _ = w.Write(CreateResponseJson(&ApiRequestResponse{Code: 4600, Data: err.Error()}))
+		return
+	}
+	var lastID uint64
+	if v := r.Header.Get("Last-Event-ID"); v != "" {
+		lastID, err = strconv.ParseUint(v, 10, 64)
+		if err != nil {
+			slog.ErrorCtx(r.Context(), "request failed", "err", err)
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusInternalServerError)
+			_, _ = w.Write(CreateResponseJson(&ApiRequestResponse{Code: 4601, Data: err.Error()}))
+			return
+		}
+	}
+
+	subscription, err := h.usecase.Subscribe(r.Context(), filter, lastID)
+	if err != nil {
+		slog.ErrorCtx(r.Context(), "request failed", "err", err)
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusInternalServerError)
+		_, _ = w.Write(CreateResponseJson(&ApiRequestResponse{Code: 4603, Data: err.Error()}))
+		return
+	}
+	defer subscription.Close()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	// turn off the response buffering of nginx
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+
+	if _, err := fmt.Fprintf(w, "retry: %d\n\n", sseRetry); err != nil {
+		return
+	}
+	// the client has to reload what it shows because some changes are lost
+	if subscription.Missed {
+		if _, err := fmt.Fprint(w, "event: reset\ndata: {}\n\n"); err != nil {
+			return
+		}
+	}
+	for _, c := range subscription.Replay {
+		if err := writeChange(w, c); err != nil {
+			return
+		}
+	}
+	flusher.Flush()
+
+	heartbeat := time.NewTicker(sseHeartbeatInterval)
+	defer heartbeat.Stop()
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-heartbeat.C:
+			if _, err := fmt.Fprint(w, ": heartbeat\n\n"); err != nil {
+				return
+			}
+		case c, ok := <-subscription.C:
+			if !ok {
+				// fell too far behind or the server is shutting down. The client reconnects with Last-Event-ID
+				return
+			}
+			if err := writeChange(w, c); err != nil {
+				return
+			}
+		}
+		flusher.Flush()
+	}
+}
+
+func writeChange(w http.ResponseWriter, c *model.Change) error {
+	data, err := json.Marshal(c)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "id: %d\nevent: %s.%s\ndata: %s\n\n", c.ID, c.Entity, c.Op, data)
+	return err
+}
+
+// changeFilter reads the comma separated type and id query parameters
+func changeFilter(r *http.Request) (model.ChangeFilter, error) {
+	var filter model.ChangeFilter
+	for _, v := range r.URL.Query()["type"] {
+		for _, t := range strings.Split(v, ",") {
+			switch t = strings.TrimSpace(t); t {
+			case "":
+			case model.ChangeEntityUser, model.ChangeEntityCar, model.ChangeEntityGroup:
+				filter.Entities = append(filter.Entities, t)
+			default:
+				return filter, fmt.Errorf("unknown type: %s", t)
+			}
+		}
+	}
+	for _, v := range r.URL.Query()["id"] {
+		for _, s := range strings.Split(v, ",") {
+			if s = strings.TrimSpace(s); s == "" {
+				continue
+			}
+			id, err := strconv.Atoi(s)
+			if err != nil {
+				return filter, err
+			}
+			filter.IDs = append(filter.IDs, id)
+		}
+	}
+	return filter, nil
+}
@@ -0,0 +1,22 @@
+package handler
+
+import (
+	"github.com/99designs/gqlgen/graphql"
+	gqlhandler "github.com/99designs/gqlgen/graphql/handler"
+	"github.com/99designs/gqlgen/graphql/handler/extension"
+	"net/http"
+)
+
+// GraphQLConfig of the /graphql endpoint
+type GraphQLConfig struct {
+	// ComplexityLimit rejects the queries whose complexity is over it before they run.
+	// Each selected field counts 1, so it bounds how many fields and how deep the edges a query can select
+	ComplexityLimit int `yaml:"complexity_limit" env:"GRAPHQL_COMPLEXITY_LIMIT" required:"true"`
+}
+
+// newGraphQLServer serves the schema with the complexity limit
+func newGraphQLServer(schema graphql.ExecutableSchema, c GraphQLConfig) http.Handler {
+	srv := gqlhandler.NewDefaultServer(schema)
+	srv.Use(extension.FixedComplexityLimit(c.ComplexityLimit))
+	return srv
+}
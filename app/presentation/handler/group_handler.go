@@ -0,0 +1,222 @@
+package handler
+
+import (
+	"encoding/json"
+	"github.com/jpdel518/go-ent/domain/model"
+	"github.com/jpdel518/go-ent/usecase"
+	"golang.org/x/exp/slog"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+type GroupHandler struct {
+	usecase usecase.GroupUsecase
+}
+
+func NewGroupHandler(usecase usecase.GroupUsecase) *GroupHandler {
+	return &GroupHandler{usecase}
+}
+
+// Groups handles /groups
+func (h *GroupHandler) Groups(w http.ResponseWriter, r *http.Request) {
+	if r.Method == http.MethodPost {
+		h.Create(w, r)
+		return
+	}
+	h.Fetch(w, r)
+}
+
+// Group handles /groups/{id}
+func (h *GroupHandler) Group(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodPut:
+		h.Update(w, r)
+	case http.MethodDelete:
+		h.Delete(w, r)
+	default:
+		h.GetById(w, r)
+	}
+}
+
+func (h *GroupHandler) Fetch(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+
+	// get query parameters
+	num, err := strconv.Atoi(r.URL.Query().Get("num"))
+	if err != nil {
+		num = 10
+	}
+
+	// fetch groups
+	groups, err := h.usecase.Fetch(r.Context(), num)
+	if err != nil {
+		slog.ErrorCtx(r.Context(), "request failed", "err", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		_, _ = w.Write(CreateResponseJson(&ApiRequestResponse{Code: 5700, Data: err.Error()}))
+		return
+	}
+
+	// response
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write(CreateResponseJson(&ApiRequestResponse{Code: 2000, Data: groups}))
+}
+
+func (h *GroupHandler) GetById(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+
+	// get path parameters
+	id, err := groupID(r.URL.Path)
+	if err != nil {
+		slog.ErrorCtx(r.Context(), "request failed", "err", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		_, _ = w.Write(CreateResponseJson(&ApiRequestResponse{Code: 5801, Data: err.Error()}))
+		return
+	}
+
+	// fetch group
+	group, err := h.usecase.GetByID(r.Context(), id)
+	if err != nil {
+		slog.ErrorCtx(r.Context(), "request failed", "err", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		_, _ = w.Write(CreateResponseJson(&ApiRequestResponse{Code: 5800, Data: err.Error()}))
+		return
+	}
+
+	// response
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write(CreateResponseJson(&ApiRequestResponse{Code: 2000, Data: group}))
+}
+
+func (h *GroupHandler) Create(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+
+	// get parameters
+	group := &model.Group{}
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		slog.ErrorCtx(r.Context(), "request failed", "err", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		_, _ = w.Write(CreateResponseJson(&ApiRequestResponse{Code: 5901, Data: err.Error()}))
+		return
+	}
+	err = json.Unmarshal(body, group)
+	if err != nil {
+		slog.ErrorCtx(r.Context(), "request failed", "err", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		_, _ = w.Write(CreateResponseJson(&ApiRequestResponse{Code: 5902, Data: err.Error()}))
+		return
+	}
+
+	// create group
+	err = h.usecase.Create(r.Context(), group)
+	if err != nil {
+		slog.ErrorCtx(r.Context(), "request failed", "err", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		_, _ = w.Write(CreateResponseJson(&ApiRequestResponse{Code: 5900, Data: err.Error()}))
+		return
+	}
+
+	// response
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write(CreateResponseJson(&ApiRequestResponse{Code: 2000, Data: group}))
+}
+
+func (h *GroupHandler) Update(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPut {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+
+	// get parameters
+	id, err := groupID(r.URL.Path)
+	if err != nil {
+		slog.ErrorCtx(r.Context(), "request failed", "err", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		_, _ = w.Write(CreateResponseJson(&ApiRequestResponse{Code: 6001, Data: err.Error()}))
+		return
+	}
+	group := &model.Group{}
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		slog.ErrorCtx(r.Context(), "request failed", "err", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		_, _ = w.Write(CreateResponseJson(&ApiRequestResponse{Code: 6002, Data: err.Error()}))
+		return
+	}
+	err = json.Unmarshal(body, group)
+	if err != nil {
+		slog.ErrorCtx(r.Context(), "request failed", "err", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		_, _ = w.Write(CreateResponseJson(&ApiRequestResponse{Code: 6003, Data: err.Error()}))
+		return
+	}
+	group.ID = id
+
+	// update group
+	err = h.usecase.Update(r.Context(), group)
+	if err != nil {
+		slog.ErrorCtx(r.Context(), "request failed", "err", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		_, _ = w.Write(CreateResponseJson(&ApiRequestResponse{Code: 6000, Data: err.Error()}))
+		return
+	}
+
+	// response
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write(CreateResponseJson(&ApiRequestResponse{Code: 2000, Data: group}))
+}
+
+func (h *GroupHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodDelete {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+
+	// get path parameters
+	id, err := groupID(r.URL.Path)
+	if err != nil {
+		slog.ErrorCtx(r.Context(), "request failed", "err", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		_, _ = w.Write(CreateResponseJson(&ApiRequestResponse{Code: 6101, Data: err.Error()}))
+		return
+	}
+
+	// delete group
+	err = h.usecase.Delete(r.Context(), id)
+	if err != nil {
+		slog.ErrorCtx(r.Context(), "request failed", "err", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		_, _ = w.Write(CreateResponseJson(&ApiRequestResponse{Code: 6100, Data: err.Error()}))
+		return
+	}
+
+	// response
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write(CreateResponseJson(&ApiRequestResponse{Code: 2000, Data: "success"}))
+}
+
+// groupID reads the id from /groups/{id}
+func groupID(path string) (int, error) {
+	return strconv.Atoi(strings.TrimPrefix(path, "/groups/"))
+}
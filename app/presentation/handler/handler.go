@@ -2,24 +2,81 @@ package handler
 
 import (
 	"encoding/json"
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/99designs/gqlgen/graphql/playground"
+	"github.com/jpdel518/go-ent/metrics"
 	"github.com/jpdel518/go-ent/usecase"
 	"log"
 	"net/http"
+	"time"
 )
 
-func NewHandler(userUsecase usecase.UserUsecase) {
+func NewHandler(userUsecase usecase.UserUsecase, jobUsecase usecase.JobUsecase, webhookUsecase usecase.WebhookUsecase, changeUsecase usecase.ChangeUsecase, healthUsecase usecase.HealthUsecase, rateLimitUsecase usecase.RateLimitUsecase, addressRateLimitUsecase usecase.RateLimitUsecase, clientIPHeader string, idempotencyUsecase usecase.IdempotencyUsecase, searchUsecase usecase.SearchUsecase, carUsecase usecase.CarUsecase, groupUsecase usecase.GroupUsecase, notificationUsecase usecase.NotificationUsecase, tenantUsecase usecase.TenantUsecase, schema graphql.ExecutableSchema, graphQLConfig GraphQLConfig) http.Handler {
 	userHandler := NewUserHandler(userUsecase)
+	jobHandler := NewJobHandler(jobUsecase)
+	webhookHandler := NewWebhookHandler(webhookUsecase)
+	eventHandler := NewEventHandler(changeUsecase)
+	healthHandler := NewHealthHandler(healthUsecase)
+	searchHandler := NewSearchHandler(searchUsecase)
+	carHandler := NewCarHandler(carUsecase, userUsecase)
+	groupHandler := NewGroupHandler(groupUsecase)
+	notificationHandler := NewNotificationHandler(notificationUsecase)
 
-	http.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
 		_, _ = w.Write([]byte("Hello World"))
 	})
-	http.HandleFunc("/user/fetch", userHandler.Fetch)
-	http.HandleFunc("/user/get-by-id/", userHandler.GetById)
-	http.HandleFunc("/user/update", userHandler.Update)
-	http.HandleFunc("/user/create", userHandler.Create)
-	http.HandleFunc("/user/delete", userHandler.Delete)
+	mux.HandleFunc("/healthz", healthHandler.Live)
+	mux.HandleFunc("/readyz", healthHandler.Ready)
+	mux.HandleFunc("/user/fetch", userHandler.Fetch)
+	mux.HandleFunc("/user/get-by-id/", userHandler.GetById)
+	mux.HandleFunc("/user/update", idempotent(idempotencyUsecase, "/user/update", userHandler.Update))
+	mux.HandleFunc("/user/create", idempotent(idempotencyUsecase, "/user/create", userHandler.Create))
+	mux.HandleFunc("/user/delete", userHandler.Delete)
+	mux.HandleFunc("/user/verify", userHandler.Verify)
+	mux.HandleFunc("/user/verify/resend", userHandler.ResendVerification)
+	mux.HandleFunc("/user/notifications", notificationHandler.Preferences)
+	mux.HandleFunc("/users/import", idempotent(idempotencyUsecase, "/users/import", userHandler.Import))
+	mux.HandleFunc("/users/export", userHandler.Export)
+	mux.HandleFunc("/cars", idempotent(idempotencyUsecase, "/cars", carHandler.Cars))
+	mux.HandleFunc("/cars/", carHandler.Car)
+	mux.HandleFunc("/groups", idempotent(idempotencyUsecase, "/groups", groupHandler.Groups))
+	mux.HandleFunc("/groups/", groupHandler.Group)
+	mux.HandleFunc("/search", searchHandler.Search)
+	mux.HandleFunc("/jobs", idempotent(idempotencyUsecase, "/jobs", jobHandler.Enqueue))
+	mux.HandleFunc("/jobs/", jobHandler.Job)
+	mux.HandleFunc("/webhooks", idempotent(idempotencyUsecase, "/webhooks", webhookHandler.Webhooks))
+	mux.HandleFunc("/webhooks/", webhookHandler.Webhook)
+	mux.HandleFunc("/events/stream", eventHandler.Stream)
+	mux.Handle("/graphql", newGraphQLServer(schema, graphQLConfig))
+	mux.Handle("/graphql/playground", playground.Handler("GraphQL playground", "/graphql"))
+	mux.Handle("/metrics", metrics.Handler())
 
-	http.ListenAndServe(":8080", nil)
+	// the rate limits of the clients come after the tenant, so that only the verified API keys have their own buckets,
+	// and the limit of the addresses comes before it, so that the made-up credentials do not reach the database freely
+	limited := limitRate(mux, rateLimitUsecase, clientIPHeader, mux)
+	return instrument(mux, limitRate(mux, addressRateLimitUsecase, clientIPHeader, scopeTenant(mux, tenantUsecase, limited)))
+}
+
+// ServerConfig of the HTTP server
+type ServerConfig struct {
+	Addr              string        `yaml:"addr" env:"HTTP_ADDR" required:"true"`
+	ReadHeaderTimeout time.Duration `yaml:"read_header_timeout" env:"HTTP_READ_HEADER_TIMEOUT"`
+	// ReadTimeout should be large enough for uploading import files and avatars
+	ReadTimeout time.Duration `yaml:"read_timeout" env:"HTTP_READ_TIMEOUT"`
+	IdleTimeout time.Duration `yaml:"idle_timeout" env:"HTTP_IDLE_TIMEOUT"`
+}
+
+// NewServer creates the HTTP server.
+// There is no write timeout because /events/stream and /users/export stream for long, and each operation is bounded by the usecase timeout instead.
+func NewServer(c ServerConfig, h http.Handler) *http.Server {
+	return &http.Server{
+		Addr:              c.Addr,
+		Handler:           h,
+		ReadHeaderTimeout: c.ReadHeaderTimeout,
+		ReadTimeout:       c.ReadTimeout,
+		IdleTimeout:       c.IdleTimeout,
+	}
 }
 
 // ApiRequestResponse response json
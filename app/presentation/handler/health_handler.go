@@ -0,0 +1,50 @@
+package handler
+
+import (
+	"github.com/jpdel518/go-ent/usecase"
+	"golang.org/x/exp/slog"
+	"net/http"
+)
+
+type HealthHandler struct {
+	usecase usecase.HealthUsecase
+}
+
+func NewHealthHandler(usecase usecase.HealthUsecase) *HealthHandler {
+	return &HealthHandler{usecase}
+}
+
+// Live reports that the process is alive and serving requests. It checks no dependency
+func (h *HealthHandler) Live(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write(CreateResponseJson(&ApiRequestResponse{Code: 2000, Data: "ok"}))
+}
+
+// Ready reports whether the dependencies work, with 503 when a critical one fails
+func (h *HealthHandler) Ready(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+
+	report := h.usecase.Check(r.Context())
+	if !report.Ready() {
+		slog.WarnCtx(r.Context(), "readiness check failed", "checks", report.Checks)
+		w.WriteHeader(http.StatusServiceUnavailable)
+		_, _ = w.Write(CreateResponseJson(&ApiRequestResponse{Code: 4700, Data: report}))
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write(CreateResponseJson(&ApiRequestResponse{Code: 2000, Data: report}))
+}
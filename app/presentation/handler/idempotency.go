@@ -0,0 +1,253 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"github.com/jpdel518/go-ent/domain/model"
+	"github.com/jpdel518/go-ent/logging"
+	"github.com/jpdel518/go-ent/tenant"
+	"github.com/jpdel518/go-ent/usecase"
+	"golang.org/x/exp/slog"
+	"hash"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"os"
+	"sort"
+	"strconv"
+	"time"
+)
+
+const (
+	// IdempotencyKeyHeader makes the retries of a request with the same key get the response of the first one
+	IdempotencyKeyHeader = "Idempotency-Key"
+	// IdempotentReplayedHeader is set to true on the replayed responses
+	IdempotentReplayedHeader = "Idempotent-Replayed"
+
+	// maxIdempotencyKeyLength is the size of the column
+	maxIdempotencyKeyLength = 255
+	// spoolMemory is the size of the body kept in memory for fingerprinting, the rest goes to a temporary file
+	spoolMemory = 10 << 20
+	// maxStoredResponse bounds the response stored for replaying. The key is released for a larger response
+	maxStoredResponse = 1 << 20
+	// releaseTimeout bounds storing the response after the client has gone
+	releaseTimeout = 5 * time.Second
+)
+
+// idempotent serves the request with an Idempotency-Key header once per key and replays the stored response to the retries.
+// A retry with another body or route is rejected with 422 and one during the first request with 409.
+// A response with a 5xx status is not stored, so that the request can be retried.
+// The keys belong to the tenant and the API key or the user of the request, and are shared by the anonymous clients of the tenant.
+func idempotent(u usecase.IdempotencyUsecase, route string, next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		key := r.Header.Get(IdempotencyKeyHeader)
+		if key == "" || r.Method == http.MethodGet || r.Method == http.MethodHead {
+			next(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		if len(key) > maxIdempotencyKeyLength {
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write(CreateResponseJson(&ApiRequestResponse{Code: 4903, Data: IdempotencyKeyHeader + " is longer than " + strconv.Itoa(maxIdempotencyKeyLength)}))
+			return
+		}
+
+		fingerprint := sha256.New()
+		_, _ = io.WriteString(fingerprint, r.Method+" "+route+"\n")
+		body, cleanup, err := spoolBody(r)
+		if err == nil {
+			defer cleanup()
+			err = fingerprintBody(fingerprint, r.Header.Get("Content-Type"), body)
+		}
+		if err != nil {
+			slog.ErrorCtx(r.Context(), "request failed", "err", err)
+			w.WriteHeader(http.StatusInternalServerError)
+			_, _ = w.Write(CreateResponseJson(&ApiRequestResponse{Code: 4904, Data: err.Error()}))
+			return
+		}
+
+		k, err := u.Begin(r.Context(), idempotencyScope(r), key, r.Method+" "+route, hex.EncodeToString(fingerprint.Sum(nil)))
+		switch {
+		case errors.Is(err, usecase.ErrIdempotencyKeyMismatch):
+			w.WriteHeader(http.StatusUnprocessableEntity)
+			_, _ = w.Write(CreateResponseJson(&ApiRequestResponse{Code: 4901, Data: err.Error()}))
+			return
+		case errors.Is(err, usecase.ErrIdempotencyKeyInProgress):
+			w.Header().Set("Retry-After", "1")
+			w.WriteHeader(http.StatusConflict)
+			_, _ = w.Write(CreateResponseJson(&ApiRequestResponse{Code: 4902, Data: err.Error()}))
+			return
+		case err != nil:
+			slog.ErrorCtx(r.Context(), "request failed", "err", err)
+			w.WriteHeader(http.StatusInternalServerError)
+			_, _ = w.Write(CreateResponseJson(&ApiRequestResponse{Code: 4900, Data: err.Error()}))
+			return
+		}
+		if k.Status == model.IdempotencyKeyCompleted {
+			w.Header().Set("Content-Type", k.ResponseContentType)
+			w.Header().Set(IdempotentReplayedHeader, "true")
+			w.WriteHeader(k.ResponseCode)
+			_, _ = w.Write(k.ResponseBody)
+			return
+		}
+
+		rec := &responseRecorder{ResponseWriter: w, status: http.StatusOK}
+		completed := false
+		// the key is kept by the response even when the client has gone
+		ctx, cancel := context.WithTimeout(logging.WithRequestID(context.Background(), logging.RequestID(r.Context())), releaseTimeout)
+		defer cancel()
+		defer func() {
+			if !completed {
+				if err := u.Release(ctx, k); err != nil {
+					slog.ErrorCtx(ctx, "failed releasing idempotency key", "err", err)
+				}
+			}
+		}()
+
+		next(rec, r)
+
+		if rec.status >= http.StatusInternalServerError || rec.overflow {
+			return
+		}
+		if err := u.Complete(ctx, k, rec.status, w.Header().Get("Content-Type"), rec.body.Bytes()); err != nil {
+			slog.ErrorCtx(ctx, "failed storing idempotent response", "err", err)
+			return
+		}
+		completed = true
+	}
+}
+
+// idempotencyScope is the tenant and the API key or the user of the request. The anonymous clients of a tenant share it
+func idempotencyScope(r *http.Request) string {
+	scope := ""
+	if id, ok := tenant.FromContext(r.Context()); ok {
+		scope = "tenant:" + strconv.Itoa(id) + "/"
+	}
+	return scope + logging.Principal(r.Context())
+}
+
+// spoolBody replaces the body with a copy, which is returned to be read once more before the handler.
+// The copy is kept in memory up to spoolMemory and in a temporary file beyond, which cleanup removes
+func spoolBody(r *http.Request) (io.ReadSeeker, func(), error) {
+	var buf bytes.Buffer
+	n, err := io.CopyN(&buf, r.Body, spoolMemory+1)
+	if err != nil && err != io.EOF {
+		return nil, nil, err
+	}
+	if n <= spoolMemory {
+		body := bytes.NewReader(buf.Bytes())
+		r.Body = io.NopCloser(body)
+		return body, func() {}, nil
+	}
+
+	f, err := os.CreateTemp("", "go-ent-body-*")
+	if err != nil {
+		return nil, nil, err
+	}
+	cleanup := func() {
+		_ = f.Close()
+		_ = os.Remove(f.Name())
+	}
+	if _, err := buf.WriteTo(f); err != nil {
+		cleanup()
+		return nil, nil, err
+	}
+	if _, err := io.Copy(f, r.Body); err != nil {
+		cleanup()
+		return nil, nil, err
+	}
+	if _, err := f.Seek(0, io.SeekStart); err != nil {
+		cleanup()
+		return nil, nil, err
+	}
+	r.Body = f
+	return f, cleanup, nil
+}
+
+// fingerprintBody hashes the body and rewinds it for the handler.
+// A multipart form is hashed by its fields and the hashes of its files rather than as it is encoded,
+// so that a retry encoded again with another boundary is the same request. A malformed one is hashed as it is
+func fingerprintBody(h hash.Hash, contentType string, body io.ReadSeeker) error {
+	mediaType, params, err := mime.ParseMediaType(contentType)
+	if err != nil || mediaType != "multipart/form-data" || params["boundary"] == "" {
+		return hashBody(h, body)
+	}
+
+	type formPart struct {
+		name     string
+		filename string
+		sum      string
+	}
+	var parts []formPart
+	mr := multipart.NewReader(body, params["boundary"])
+	for {
+		p, err := mr.NextPart()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return hashBody(h, body)
+		}
+		sum := sha256.New()
+		if _, err := io.Copy(sum, p); err != nil {
+			return hashBody(h, body)
+		}
+		parts = append(parts, formPart{name: p.FormName(), filename: p.FileName(), sum: hex.EncodeToString(sum.Sum(nil))})
+	}
+	// the fields may be encoded in any order, the values of a field keep theirs
+	sort.SliceStable(parts, func(i, j int) bool {
+		return parts[i].name < parts[j].name
+	})
+	for _, p := range parts {
+		_, _ = fmt.Fprintf(h, "%q %q %s\n", p.name, p.filename, p.sum)
+	}
+	_, err = body.Seek(0, io.SeekStart)
+	return err
+}
+
+// hashBody hashes the body as it is from the start and rewinds it
+func hashBody(h hash.Hash, body io.ReadSeeker) error {
+	if _, err := body.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+	if _, err := io.Copy(h, body); err != nil {
+		return err
+	}
+	_, err := body.Seek(0, io.SeekStart)
+	return err
+}
+
+// responseRecorder keeps a copy of the response for storing it
+type responseRecorder struct {
+	http.ResponseWriter
+	status      int
+	wroteHeader bool
+	body        bytes.Buffer
+	overflow    bool
+}
+
+func (rec *responseRecorder) WriteHeader(code int) {
+	if !rec.wroteHeader {
+		rec.status = code
+		rec.wroteHeader = true
+	}
+	rec.ResponseWriter.WriteHeader(code)
+}
+
+func (rec *responseRecorder) Write(b []byte) (int, error) {
+	rec.wroteHeader = true
+	if !rec.overflow {
+		if rec.body.Len()+len(b) > maxStoredResponse {
+			rec.overflow = true
+			rec.body.Reset()
+		} else {
+			rec.body.Write(b)
+		}
+	}
+	return rec.ResponseWriter.Write(b)
+}
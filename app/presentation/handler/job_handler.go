@@ -0,0 +1,142 @@
+package handler
+
+import (
+	"github.com/jpdel518/go-ent/infrastructure/format"
+	"github.com/jpdel518/go-ent/usecase"
+	"golang.org/x/exp/slog"
+	"mime/multipart"
+	"net/http"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+type JobHandler struct {
+	usecase usecase.JobUsecase
+}
+
+func NewJobHandler(usecase usecase.JobUsecase) *JobHandler {
+	return &JobHandler{usecase}
+}
+
+func (h *JobHandler) Enqueue(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+
+	// get parameters
+	jobType := r.FormValue("type")
+	params := make(map[string]string)
+	var file multipart.File
+	var fileHeader *multipart.FileHeader
+	if strings.HasPrefix(r.Header.Get("Content-Type"), "multipart/form-data") {
+		var err error
+		file, fileHeader, err = r.FormFile("file")
+		if err != nil && err != http.ErrMissingFile {
+			slog.ErrorCtx(r.Context(), "request failed", "err", err)
+			w.WriteHeader(http.StatusInternalServerError)
+			_, _ = w.Write(CreateResponseJson(&ApiRequestResponse{Code: 3701, Data: err.Error()}))
+			return
+		}
+		if file != nil {
+			defer file.Close()
+		}
+	}
+	if f := r.FormValue("format"); f != "" {
+		params["format"] = f
+	} else if fileHeader != nil {
+		params["format"] = strings.TrimPrefix(filepath.Ext(fileHeader.Filename), ".")
+	}
+	if _, ok := params["format"]; ok && !format.Supported(params["format"]) {
+		w.WriteHeader(http.StatusInternalServerError)
+		_, _ = w.Write(CreateResponseJson(&ApiRequestResponse{Code: 3702, Data: "unsupported format: " + params["format"]}))
+		return
+	}
+
+	// enqueue job
+	job, err := h.usecase.Enqueue(r.Context(), jobType, params, file, fileHeader)
+	if err != nil {
+		slog.ErrorCtx(r.Context(), "request failed", "err", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		_, _ = w.Write(CreateResponseJson(&ApiRequestResponse{Code: 3700, Data: err.Error()}))
+		return
+	}
+
+	// response
+	w.WriteHeader(http.StatusAccepted)
+	_, _ = w.Write(CreateResponseJson(&ApiRequestResponse{Code: 2000, Data: job}))
+}
+
+// Job handles /jobs/{id} and /jobs/{id}/cancel
+func (h *JobHandler) Job(w http.ResponseWriter, r *http.Request) {
+	if strings.HasSuffix(r.URL.Path, "/cancel") {
+		h.Cancel(w, r)
+		return
+	}
+	h.GetById(w, r)
+}
+
+func (h *JobHandler) GetById(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+
+	// get path parameters
+	id, err := strconv.Atoi(filepath.Base(r.URL.Path))
+	if err != nil {
+		slog.ErrorCtx(r.Context(), "request failed", "err", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		_, _ = w.Write(CreateResponseJson(&ApiRequestResponse{Code: 3801, Data: err.Error()}))
+		return
+	}
+
+	// fetch job
+	job, err := h.usecase.GetByID(r.Context(), id)
+	if err != nil {
+		slog.ErrorCtx(r.Context(), "request failed", "err", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		_, _ = w.Write(CreateResponseJson(&ApiRequestResponse{Code: 3800, Data: err.Error()}))
+		return
+	}
+
+	// response
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write(CreateResponseJson(&ApiRequestResponse{Code: 2000, Data: job}))
+}
+
+func (h *JobHandler) Cancel(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+
+	// get path parameters
+	id, err := strconv.Atoi(filepath.Base(strings.TrimSuffix(r.URL.Path, "/cancel")))
+	if err != nil {
+		slog.ErrorCtx(r.Context(), "request failed", "err", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		_, _ = w.Write(CreateResponseJson(&ApiRequestResponse{Code: 3901, Data: err.Error()}))
+		return
+	}
+
+	// cancel job
+	job, err := h.usecase.Cancel(r.Context(), id)
+	if err != nil {
+		slog.ErrorCtx(r.Context(), "request failed", "err", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		_, _ = w.Write(CreateResponseJson(&ApiRequestResponse{Code: 3900, Data: err.Error()}))
+		return
+	}
+
+	// response
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write(CreateResponseJson(&ApiRequestResponse{Code: 2000, Data: job}))
+}
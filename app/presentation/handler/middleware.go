@@ -0,0 +1,97 @@
+package handler
+
+import (
+	"github.com/jpdel518/go-ent/logging"
+	"github.com/jpdel518/go-ent/metrics"
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	"golang.org/x/exp/slog"
+	"net/http"
+	"time"
+)
+
+// probeRoutes are polled by the orchestrator and the monitoring, so they are logged at the debug level
+var probeRoutes = map[string]bool{
+	"/healthz": true,
+	"/readyz":  true,
+	"/metrics": true,
+}
+
+// instrument gives each request a request id, traces it, and records and logs it by the pattern of the route which served it,
+// not by its path, so that ids in the path such as /jobs/1 do not make new series.
+// The span continues the trace of the traceparent header of the request.
+// next serves the request after the mux matched its route.
+func instrument(mux *http.ServeMux, next http.Handler) http.Handler {
+	observed := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		done := metrics.HTTPRequestStarted()
+		defer done()
+		rec := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+		start := time.Now()
+		next.ServeHTTP(rec, r)
+		d := time.Since(start)
+
+		pattern := route(mux, r)
+		metrics.ObserveHTTPRequest(pattern, r.Method, rec.status, d)
+		level := slog.LevelInfo
+		if probeRoutes[pattern] {
+			level = slog.LevelDebug
+		}
+		slog.Default().Log(r.Context(), level, "request served",
+			"method", r.Method,
+			"route", pattern,
+			"status", rec.status,
+			"duration_ms", d.Milliseconds(),
+		)
+	})
+	traced := otelhttp.NewHandler(observed, "http.server", otelhttp.WithSpanNameFormatter(func(_ string, r *http.Request) string {
+		return r.Method + " " + route(mux, r)
+	}))
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := r.Header.Get(logging.RequestIDHeader)
+		if !logging.ValidRequestID(id) {
+			id = logging.NewRequestID()
+		}
+		w.Header().Set(logging.RequestIDHeader, id)
+		traced.ServeHTTP(w, r.WithContext(logging.WithRequestID(r.Context(), id)))
+	})
+}
+
+// route returns the pattern which matches the request
+func route(mux *http.ServeMux, r *http.Request) string {
+	_, pattern := mux.Handler(r)
+	if pattern == "" {
+		return "unmatched"
+	}
+	return pattern
+}
+
+// statusRecorder keeps the status code written by the handler
+type statusRecorder struct {
+	http.ResponseWriter
+	status      int
+	wroteHeader bool
+}
+
+func (rec *statusRecorder) WriteHeader(code int) {
+	if !rec.wroteHeader {
+		rec.status = code
+		rec.wroteHeader = true
+	}
+	rec.ResponseWriter.WriteHeader(code)
+}
+
+func (rec *statusRecorder) Write(b []byte) (int, error) {
+	rec.wroteHeader = true
+	return rec.ResponseWriter.Write(b)
+}
+
+// Flush is needed by the event stream
+func (rec *statusRecorder) Flush() {
+	if f, ok := rec.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// Unwrap lets http.ResponseController reach the original writer
+func (rec *statusRecorder) Unwrap() http.ResponseWriter {
+	return rec.ResponseWriter
+}
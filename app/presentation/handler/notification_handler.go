@@ -0,0 +1,107 @@
+package handler
+
+import (
+	"encoding/json"
+	"github.com/jpdel518/go-ent/domain/model"
+	"github.com/jpdel518/go-ent/usecase"
+	"golang.org/x/exp/slog"
+	"io"
+	"net/http"
+	"strconv"
+)
+
+type NotificationHandler struct {
+	usecase usecase.NotificationUsecase
+}
+
+func NewNotificationHandler(usecase usecase.NotificationUsecase) *NotificationHandler {
+	return &NotificationHandler{usecase}
+}
+
+// Preferences handles /user/notifications?id={user id}
+func (h *NotificationHandler) Preferences(w http.ResponseWriter, r *http.Request) {
+	if r.Method == http.MethodPut {
+		h.UpdatePreferences(w, r)
+		return
+	}
+	h.GetPreferences(w, r)
+}
+
+func (h *NotificationHandler) GetPreferences(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+
+	// get parameters
+	id, err := strconv.Atoi(r.URL.Query().Get("id"))
+	if err != nil {
+		slog.ErrorCtx(r.Context(), "request failed", "err", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		_, _ = w.Write(CreateResponseJson(&ApiRequestResponse{Code: 6401, Data: err.Error()}))
+		return
+	}
+
+	// get preferences
+	preferences, err := h.usecase.GetPreferences(r.Context(), id)
+	if err != nil {
+		slog.ErrorCtx(r.Context(), "request failed", "err", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		_, _ = w.Write(CreateResponseJson(&ApiRequestResponse{Code: 6400, Data: err.Error()}))
+		return
+	}
+
+	// response
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write(CreateResponseJson(&ApiRequestResponse{Code: 2000, Data: preferences}))
+}
+
+// UpdatePreferences replaces the preferences with the JSON body, in which the fields left out are false or empty
+func (h *NotificationHandler) UpdatePreferences(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPut {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+
+	// get parameters
+	id, err := strconv.Atoi(r.URL.Query().Get("id"))
+	if err != nil {
+		slog.ErrorCtx(r.Context(), "request failed", "err", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		_, _ = w.Write(CreateResponseJson(&ApiRequestResponse{Code: 6411, Data: err.Error()}))
+		return
+	}
+	preferences := &model.NotificationPreferences{}
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		slog.ErrorCtx(r.Context(), "request failed", "err", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		_, _ = w.Write(CreateResponseJson(&ApiRequestResponse{Code: 6412, Data: err.Error()}))
+		return
+	}
+	err = json.Unmarshal(body, preferences)
+	if err != nil {
+		slog.ErrorCtx(r.Context(), "request failed", "err", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		_, _ = w.Write(CreateResponseJson(&ApiRequestResponse{Code: 6413, Data: err.Error()}))
+		return
+	}
+	preferences.UserID = id
+
+	// update preferences
+	preferences, err = h.usecase.UpdatePreferences(r.Context(), preferences)
+	if err != nil {
+		slog.ErrorCtx(r.Context(), "request failed", "err", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		_, _ = w.Write(CreateResponseJson(&ApiRequestResponse{Code: 6410, Data: err.Error()}))
+		return
+	}
+
+	// response
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write(CreateResponseJson(&ApiRequestResponse{Code: 2000, Data: preferences}))
+}
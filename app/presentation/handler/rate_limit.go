@@ -0,0 +1,93 @@
+package handler
+
+import (
+	"github.com/jpdel518/go-ent/domain/model"
+	"github.com/jpdel518/go-ent/logging"
+	"github.com/jpdel518/go-ent/usecase"
+	"golang.org/x/exp/slog"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// APIKeyHeader carries the API key of the tenant, which identifies the client for the rate limits once it is verified
+const APIKeyHeader = "X-API-Key"
+
+// RateLimitConfig of the rate limiting middleware
+type RateLimitConfig struct {
+	// Default limits each client on the routes without their own limit, such as "300/1m". The empty limit turns it off
+	Default model.RateLimit `yaml:"default" env:"RATE_LIMIT_DEFAULT"`
+	// Routes limits each client on the route patterns or the gRPC methods separately, such as "/user/create=10/1m"
+	// or "/goent.v1.UserService/ImportUsers=5/1m"
+	Routes map[string]model.RateLimit `yaml:"routes" env:"RATE_LIMIT_ROUTES"`
+	// Address limits each address on all the routes before the tenant is resolved, whatever the credentials.
+	// It bounds the lookups of the made-up API keys and tokens, so it is looser than the limits of the clients
+	Address model.RateLimit `yaml:"address" env:"RATE_LIMIT_ADDRESS"`
+	// ClientIPHeader is set to the address of the client by the reverse proxy, such as X-Real-IP.
+	// The remote address is used when it is empty, as anyone could set the header without a proxy
+	ClientIPHeader string `yaml:"client_ip_header" env:"RATE_LIMIT_CLIENT_IP_HEADER"`
+}
+
+// limitRate rejects the requests of a client over the limit of the route with 429.
+// The responses carry the RateLimit-* headers of the limit, and the rejected ones Retry-After as well.
+// The probes are not limited, and the requests go on when the store fails.
+func limitRate(mux *http.ServeMux, u usecase.RateLimitUsecase, clientIPHeader string, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		pattern := route(mux, r)
+		if probeRoutes[pattern] {
+			next.ServeHTTP(w, r)
+			return
+		}
+		decision, err := u.Allow(r.Context(), pattern, client(r, clientIPHeader))
+		if err != nil {
+			slog.WarnCtx(r.Context(), "rate limit check failed", "err", err)
+			next.ServeHTTP(w, r)
+			return
+		}
+		if decision == nil {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		h := w.Header()
+		h.Set("RateLimit-Limit", strconv.Itoa(decision.Limit.Limit))
+		h.Set("RateLimit-Remaining", strconv.Itoa(decision.Remaining))
+		h.Set("RateLimit-Reset", seconds(decision.Reset))
+		h.Set("RateLimit-Policy", strconv.Itoa(decision.Limit.Limit)+";w="+seconds(decision.Limit.Period))
+		if !decision.Allowed {
+			h.Set("Retry-After", seconds(decision.RetryAfter))
+			h.Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusTooManyRequests)
+			_, _ = w.Write(CreateResponseJson(&ApiRequestResponse{Code: 4800, Data: "rate limit exceeded"}))
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// client identifies the client by the principal, that is the verified API key or user, or by the address.
+// The API key of the header is not used as it is, since a client could send another one with each request
+func client(r *http.Request, clientIPHeader string) string {
+	if principal := logging.Principal(r.Context()); principal != "" {
+		return principal
+	}
+	if clientIPHeader != "" {
+		// the proxy appends the address it sees to X-Forwarded-For, the addresses before it are set by the client
+		addrs := strings.Split(r.Header.Get(clientIPHeader), ",")
+		if ip := strings.TrimSpace(addrs[len(addrs)-1]); ip != "" {
+			return "ip:" + ip
+		}
+	}
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+	return "ip:" + host
+}
+
+// seconds rounds up, so that a client waiting for it is not rejected again
+func seconds(d time.Duration) string {
+	return strconv.FormatInt(int64((d+time.Second-1)/time.Second), 10)
+}
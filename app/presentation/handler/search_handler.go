@@ -0,0 +1,64 @@
+package handler
+
+import (
+	"github.com/jpdel518/go-ent/domain/model"
+	"github.com/jpdel518/go-ent/usecase"
+	"golang.org/x/exp/slog"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+type SearchHandler struct {
+	usecase usecase.SearchUsecase
+}
+
+func NewSearchHandler(usecase usecase.SearchUsecase) *SearchHandler {
+	return &SearchHandler{usecase}
+}
+
+// Search ranks the users and the cars matching ?q=, limited to ?type=user,car and ?limit=
+func (h *SearchHandler) Search(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+
+	// get query parameters
+	query := r.URL.Query()
+	q := &model.SearchQuery{Text: strings.TrimSpace(query.Get("q"))}
+	if t := query.Get("type"); t != "" {
+		q.Types = strings.Split(t, ",")
+	}
+	if l := query.Get("limit"); l != "" {
+		limit, err := strconv.Atoi(l)
+		if err != nil {
+			slog.ErrorCtx(r.Context(), "request failed", "err", err)
+			w.WriteHeader(http.StatusInternalServerError)
+			_, _ = w.Write(CreateResponseJson(&ApiRequestResponse{Code: 5000, Data: err.Error()}))
+			return
+		}
+		q.Limit = limit
+	}
+	if err := q.Validate(); err != nil {
+		slog.ErrorCtx(r.Context(), "request failed", "err", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		_, _ = w.Write(CreateResponseJson(&ApiRequestResponse{Code: 5001, Data: err.Error()}))
+		return
+	}
+
+	// search
+	result, err := h.usecase.Search(r.Context(), q)
+	if err != nil {
+		slog.ErrorCtx(r.Context(), "request failed", "err", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		_, _ = w.Write(CreateResponseJson(&ApiRequestResponse{Code: 5002, Data: err.Error()}))
+		return
+	}
+
+	// response
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write(CreateResponseJson(&ApiRequestResponse{Code: 2000, Data: result}))
+}
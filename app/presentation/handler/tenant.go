@@ -0,0 +1,63 @@
+package handler
+
+import (
+	"errors"
+	"github.com/jpdel518/go-ent/domain/model"
+	"github.com/jpdel518/go-ent/logging"
+	"github.com/jpdel518/go-ent/tenant"
+	"github.com/jpdel518/go-ent/usecase"
+	"golang.org/x/exp/slog"
+	"net/http"
+	"strings"
+)
+
+// scopeTenant puts the tenant of the API key, the bearer token or the subdomain into the context of the request,
+// which the repositories scope the data by, and the principal which the logs, the rate limits and the idempotency keys are of. The requests without a tenant are rejected with 401, and those whose credentials disagree with 403.
+// The probes belong to no tenant, and the routes which name their tenant otherwise are not resolved
+func scopeTenant(mux *http.ServeMux, u usecase.TenantUsecase, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if pattern := route(mux, r); probeRoutes[pattern] || untenantedRoutes[pattern] {
+			next.ServeHTTP(w, r)
+			return
+		}
+		cred := tenantCredentials(r)
+		a, err := u.Resolve(r.Context(), cred)
+		if err != nil {
+			status, code := http.StatusInternalServerError, 6204
+			switch {
+			case errors.Is(err, usecase.ErrUnknownTenant):
+				status, code = http.StatusUnauthorized, 6200
+			case errors.Is(err, usecase.ErrInvalidAPIKey):
+				status, code = http.StatusUnauthorized, 6201
+			case errors.Is(err, usecase.ErrInvalidToken):
+				status, code = http.StatusUnauthorized, 6202
+			case errors.Is(err, usecase.ErrTenantMismatch):
+				status, code = http.StatusForbidden, 6203
+			default:
+				slog.ErrorCtx(r.Context(), "request failed", "err", err)
+			}
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(status)
+			_, _ = w.Write(CreateResponseJson(&ApiRequestResponse{Code: code, Data: err.Error()}))
+			return
+		}
+		ctx := tenant.NewContext(r.Context(), a.Tenant.ID)
+		if a.Principal != "" {
+			ctx = logging.WithPrincipal(ctx, a.Principal)
+		}
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// untenantedRoutes are opened from the links of the mails, whose tokens name the tenant
+var untenantedRoutes = map[string]bool{
+	"/user/verify": true,
+}
+
+func tenantCredentials(r *http.Request) model.TenantCredentials {
+	cred := model.TenantCredentials{APIKey: r.Header.Get(APIKeyHeader), Host: r.Host}
+	if auth := r.Header.Get("Authorization"); len(auth) > len("Bearer ") && strings.EqualFold(auth[:len("Bearer ")], "Bearer ") {
+		cred.Token = auth[len("Bearer "):]
+	}
+	return cred
+}
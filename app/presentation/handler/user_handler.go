@@ -2,10 +2,12 @@ package handler
 
 import (
 	"encoding/json"
+	"errors"
 	"github.com/jpdel518/go-ent/domain/model"
+	"github.com/jpdel518/go-ent/infrastructure/format"
 	"github.com/jpdel518/go-ent/usecase"
+	"golang.org/x/exp/slog"
 	"io"
-	"log"
 	"mime/multipart"
 	"net/http"
 	"path/filepath"
@@ -31,16 +33,35 @@ func (h *Handler) Fetch(w http.ResponseWriter, r *http.Request) {
 	// get query parameters
 	num, err := strconv.Atoi(r.URL.Query().Get("num"))
 	if err != nil {
-		log.Println(err)
+		slog.ErrorCtx(r.Context(), "request failed", "err", err)
 		num = 10
 		// http.Error(w, err.Error(), http.StatusInternalServerError)
 		// return
 	}
 
+	cars := model.CarDetails(r.URL.Query().Get("cars"))
+	if err := cars.Validate(); err != nil {
+		slog.ErrorCtx(r.Context(), "request failed", "err", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		_, _ = w.Write(CreateResponseJson(&ApiRequestResponse{Code: 3001, Data: err.Error()}))
+		return
+	}
+	// ?after= is the id of the last user of the previous page
+	afterID := 0
+	if after := r.URL.Query().Get("after"); after != "" {
+		afterID, err = strconv.Atoi(after)
+		if err != nil {
+			slog.ErrorCtx(r.Context(), "request failed", "err", err)
+			w.WriteHeader(http.StatusInternalServerError)
+			_, _ = w.Write(CreateResponseJson(&ApiRequestResponse{Code: 3002, Data: err.Error()}))
+			return
+		}
+	}
+
 	// fetch user data
-	users, err := h.usecase.Fetch(r.Context(), num)
+	users, err := h.usecase.Fetch(r.Context(), afterID, num, cars)
 	if err != nil {
-		log.Println(err)
+		slog.ErrorCtx(r.Context(), "request failed", "err", err)
 		w.WriteHeader(http.StatusInternalServerError)
 		_, _ = w.Write(CreateResponseJson(&ApiRequestResponse{Code: 3000, Data: err.Error()}))
 		return
@@ -63,15 +84,23 @@ func (h *Handler) GetById(w http.ResponseWriter, r *http.Request) {
 	sub := strings.TrimPrefix(r.URL.Path, "/user")
 	id, err := strconv.Atoi(filepath.Base(sub))
 	if err != nil {
-		log.Println(err)
+		slog.ErrorCtx(r.Context(), "request failed", "err", err)
 		w.WriteHeader(http.StatusInternalServerError)
 		_, _ = w.Write(CreateResponseJson(&ApiRequestResponse{Code: 3101, Data: err.Error()}))
+		return
+	}
+	cars := model.CarDetails(r.URL.Query().Get("cars"))
+	if err := cars.Validate(); err != nil {
+		slog.ErrorCtx(r.Context(), "request failed", "err", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		_, _ = w.Write(CreateResponseJson(&ApiRequestResponse{Code: 3102, Data: err.Error()}))
+		return
 	}
 
 	// fetch user data
-	user, err := h.usecase.GetByID(r.Context(), id)
+	user, err := h.usecase.GetByID(r.Context(), id, cars)
 	if err != nil {
-		log.Println(err)
+		slog.ErrorCtx(r.Context(), "request failed", "err", err)
 		w.WriteHeader(http.StatusInternalServerError)
 		_, _ = w.Write(CreateResponseJson(&ApiRequestResponse{Code: 3100, Data: err.Error()}))
 		return
@@ -105,14 +134,13 @@ func (h *Handler) Create(w http.ResponseWriter, r *http.Request) {
 		lastName := r.FormValue("last_name")
 		email := r.FormValue("email")
 		age, _ := strconv.Atoi(r.FormValue("age")) // optionalなのでエラーは無視
-		carFormValue := r.FormValue("car_ids")
 		var carIDs []int
-		if carFormValue != "" {
-			carStrings := strings.Split(strings.Trim(strings.ReplaceAll(carFormValue, " ", ""), "[]"), ",")
+		if carFormValue := strings.Trim(strings.ReplaceAll(r.FormValue("car_ids"), " ", ""), "[]"); carFormValue != "" {
+			carStrings := strings.Split(carFormValue, ",")
 			for _, carString := range carStrings {
 				car, err := strconv.Atoi(carString)
 				if err != nil {
-					log.Println(err)
+					slog.ErrorCtx(r.Context(), "request failed", "err", err)
 					w.WriteHeader(http.StatusInternalServerError)
 					_, _ = w.Write(CreateResponseJson(&ApiRequestResponse{Code: 3201, Data: err.Error()}))
 					return
@@ -123,7 +151,7 @@ func (h *Handler) Create(w http.ResponseWriter, r *http.Request) {
 		var err error
 		file, fileHeader, err = r.FormFile("avatar")
 		if err != nil {
-			log.Println(err)
+			slog.ErrorCtx(r.Context(), "request failed", "err", err)
 			w.WriteHeader(http.StatusInternalServerError)
 			_, _ = w.Write(CreateResponseJson(&ApiRequestResponse{Code: 3205, Data: err.Error()}))
 			return
@@ -141,14 +169,14 @@ func (h *Handler) Create(w http.ResponseWriter, r *http.Request) {
 		user = &model.User{}
 		body, err := io.ReadAll(r.Body)
 		if err != nil {
-			log.Println(err)
+			slog.ErrorCtx(r.Context(), "request failed", "err", err)
 			w.WriteHeader(http.StatusInternalServerError)
 			_, _ = w.Write(CreateResponseJson(&ApiRequestResponse{Code: 3202, Data: err.Error()}))
 			return
 		}
 		err = json.Unmarshal(body, user)
 		if err != nil {
-			log.Println(err)
+			slog.ErrorCtx(r.Context(), "request failed", "err", err)
 			w.WriteHeader(http.StatusInternalServerError)
 			_, _ = w.Write(CreateResponseJson(&ApiRequestResponse{Code: 3203, Data: err.Error()}))
 			return
@@ -158,7 +186,7 @@ func (h *Handler) Create(w http.ResponseWriter, r *http.Request) {
 	// validation
 	err := user.Validate()
 	if err != nil {
-		log.Println(err)
+		slog.ErrorCtx(r.Context(), "request failed", "err", err)
 		w.WriteHeader(http.StatusInternalServerError)
 		_, _ = w.Write(CreateResponseJson(&ApiRequestResponse{Code: 3204, Data: err.Error()}))
 		return
@@ -167,7 +195,7 @@ func (h *Handler) Create(w http.ResponseWriter, r *http.Request) {
 	// create user
 	err = h.usecase.Create(r.Context(), user, file, fileHeader)
 	if err != nil {
-		log.Println(err)
+		slog.ErrorCtx(r.Context(), "request failed", "err", err)
 		w.WriteHeader(http.StatusInternalServerError)
 		_, _ = w.Write(CreateResponseJson(&ApiRequestResponse{Code: 3200, Data: err.Error()}))
 		return
@@ -194,7 +222,7 @@ func (h *Handler) Update(w http.ResponseWriter, r *http.Request) {
 		// multipart/form-data or application/x-www-form-urlencoded
 		ID, err := strconv.Atoi(r.FormValue("id"))
 		if err != nil {
-			log.Println(err)
+			slog.ErrorCtx(r.Context(), "request failed", "err", err)
 			w.WriteHeader(http.StatusInternalServerError)
 			_, _ = w.Write(CreateResponseJson(&ApiRequestResponse{Code: 3301, Data: err.Error()}))
 			return
@@ -203,21 +231,23 @@ func (h *Handler) Update(w http.ResponseWriter, r *http.Request) {
 		lastName := r.FormValue("last_name")
 		email := r.FormValue("email")
 		age, _ := strconv.Atoi(r.FormValue("age")) // optionalなのでエラーは無視
-		carStrings := strings.Split(strings.Trim(strings.ReplaceAll(r.FormValue("cars"), " ", ""), "[]"), ",")
-		var carIDs []int
-		for _, carString := range carStrings {
-			car, err := strconv.Atoi(carString)
-			if err != nil {
-				log.Println(err)
-				w.WriteHeader(http.StatusInternalServerError)
-				_, _ = w.Write(CreateResponseJson(&ApiRequestResponse{Code: 3302, Data: err.Error()}))
-				return
+		// "[]" releases all the cars
+		carIDs := make([]int, 0)
+		if carFormValue := strings.Trim(strings.ReplaceAll(r.FormValue("cars"), " ", ""), "[]"); carFormValue != "" {
+			for _, carString := range strings.Split(carFormValue, ",") {
+				car, err := strconv.Atoi(carString)
+				if err != nil {
+					slog.ErrorCtx(r.Context(), "request failed", "err", err)
+					w.WriteHeader(http.StatusInternalServerError)
+					_, _ = w.Write(CreateResponseJson(&ApiRequestResponse{Code: 3302, Data: err.Error()}))
+					return
+				}
+				carIDs = append(carIDs, car)
 			}
-			carIDs = append(carIDs, car)
 		}
 		file, fileHeader, err = r.FormFile("avatar")
 		if err != nil {
-			log.Println(err)
+			slog.ErrorCtx(r.Context(), "request failed", "err", err)
 			w.WriteHeader(http.StatusInternalServerError)
 			_, _ = w.Write(CreateResponseJson(&ApiRequestResponse{Code: 3303, Data: err.Error()}))
 			return
@@ -236,7 +266,7 @@ func (h *Handler) Update(w http.ResponseWriter, r *http.Request) {
 		user = &model.User{}
 		err := json.NewDecoder(r.Body).Decode(user)
 		if err != nil {
-			log.Println(err)
+			slog.ErrorCtx(r.Context(), "request failed", "err", err)
 			w.WriteHeader(http.StatusInternalServerError)
 			_, _ = w.Write(CreateResponseJson(&ApiRequestResponse{Code: 3204, Data: err.Error()}))
 			return
@@ -246,7 +276,7 @@ func (h *Handler) Update(w http.ResponseWriter, r *http.Request) {
 	// validation
 	err := user.Validate()
 	if err != nil {
-		log.Println(err)
+		slog.ErrorCtx(r.Context(), "request failed", "err", err)
 		w.WriteHeader(http.StatusInternalServerError)
 		_, _ = w.Write(CreateResponseJson(&ApiRequestResponse{Code: 3305, Data: err.Error()}))
 		return
@@ -255,7 +285,7 @@ func (h *Handler) Update(w http.ResponseWriter, r *http.Request) {
 	// update user
 	err = h.usecase.Update(r.Context(), user, file, fileHeader)
 	if err != nil {
-		log.Println(err)
+		slog.ErrorCtx(r.Context(), "request failed", "err", err)
 		w.WriteHeader(http.StatusInternalServerError)
 		_, _ = w.Write(CreateResponseJson(&ApiRequestResponse{Code: 3300, Data: err.Error()}))
 		return
@@ -277,7 +307,7 @@ func (h *Handler) Delete(w http.ResponseWriter, r *http.Request) {
 	// get parameters
 	id, err := strconv.Atoi(r.URL.Query().Get("id"))
 	if err != nil {
-		log.Println(err)
+		slog.ErrorCtx(r.Context(), "request failed", "err", err)
 		w.WriteHeader(http.StatusInternalServerError)
 		_, _ = w.Write(CreateResponseJson(&ApiRequestResponse{Code: 3401, Data: err.Error()}))
 		return
@@ -286,7 +316,7 @@ func (h *Handler) Delete(w http.ResponseWriter, r *http.Request) {
 	// delete user
 	err = h.usecase.Delete(r.Context(), id)
 	if err != nil {
-		log.Println(err)
+		slog.ErrorCtx(r.Context(), "request failed", "err", err)
 		w.WriteHeader(http.StatusInternalServerError)
 		_, _ = w.Write(CreateResponseJson(&ApiRequestResponse{Code: 3400, Data: err.Error()}))
 		return
@@ -296,3 +326,183 @@ func (h *Handler) Delete(w http.ResponseWriter, r *http.Request) {
 	w.WriteHeader(http.StatusOK)
 	_, _ = w.Write(CreateResponseJson(&ApiRequestResponse{Code: 2000, Data: "success"}))
 }
+
+// Verify is opened from the link of the verification mail, whose token names the tenant
+func (h *Handler) Verify(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+
+	// verify e-mail
+	user, err := h.usecase.Verify(r.Context(), r.URL.Query().Get("token"))
+	if errors.Is(err, usecase.ErrInvalidVerification) {
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write(CreateResponseJson(&ApiRequestResponse{Code: 6301, Data: err.Error()}))
+		return
+	}
+	if err != nil {
+		slog.ErrorCtx(r.Context(), "request failed", "err", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		_, _ = w.Write(CreateResponseJson(&ApiRequestResponse{Code: 6300, Data: err.Error()}))
+		return
+	}
+
+	// response
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write(CreateResponseJson(&ApiRequestResponse{Code: 2000, Data: user}))
+}
+
+func (h *Handler) ResendVerification(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+
+	// get parameters
+	id, err := strconv.Atoi(r.URL.Query().Get("id"))
+	if err != nil {
+		slog.ErrorCtx(r.Context(), "request failed", "err", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		_, _ = w.Write(CreateResponseJson(&ApiRequestResponse{Code: 6311, Data: err.Error()}))
+		return
+	}
+
+	// resend mail
+	err = h.usecase.ResendVerification(r.Context(), id)
+	if errors.Is(err, usecase.ErrAlreadyVerified) {
+		w.WriteHeader(http.StatusConflict)
+		_, _ = w.Write(CreateResponseJson(&ApiRequestResponse{Code: 6312, Data: err.Error()}))
+		return
+	}
+	if err != nil {
+		slog.ErrorCtx(r.Context(), "request failed", "err", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		_, _ = w.Write(CreateResponseJson(&ApiRequestResponse{Code: 6310, Data: err.Error()}))
+		return
+	}
+
+	// response
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write(CreateResponseJson(&ApiRequestResponse{Code: 2000, Data: "success"}))
+}
+
+func (h *Handler) Import(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+
+	// get import source
+	// the format is taken from the query parameter, the Content-Type or the uploaded file name in this order
+	f := r.URL.Query().Get("format")
+	var body io.Reader = r.Body
+	if strings.HasPrefix(r.Header.Get("Content-Type"), "multipart/form-data") {
+		file, fileHeader, err := r.FormFile("file")
+		if err != nil {
+			slog.ErrorCtx(r.Context(), "request failed", "err", err)
+			w.WriteHeader(http.StatusInternalServerError)
+			_, _ = w.Write(CreateResponseJson(&ApiRequestResponse{Code: 3502, Data: err.Error()}))
+			return
+		}
+		defer file.Close()
+		body = file
+		if f == "" {
+			f = strings.TrimPrefix(filepath.Ext(fileHeader.Filename), ".")
+		}
+	} else if f == "" {
+		f = format.FromContentType(r.Header.Get("Content-Type"))
+	}
+
+	dec, err := format.NewUserDecoder(f, body)
+	if err != nil {
+		slog.ErrorCtx(r.Context(), "request failed", "err", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		_, _ = w.Write(CreateResponseJson(&ApiRequestResponse{Code: 3501, Data: err.Error()}))
+		return
+	}
+
+	// import users
+	report, err := h.usecase.Import(r.Context(), dec)
+	if err != nil {
+		slog.ErrorCtx(r.Context(), "request failed", "err", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		_, _ = w.Write(CreateResponseJson(&ApiRequestResponse{Code: 3500, Data: err.Error()}))
+		return
+	}
+
+	// response
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write(CreateResponseJson(&ApiRequestResponse{Code: 2000, Data: report}))
+}
+
+func (h *Handler) Export(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	// get query parameters
+	f := r.URL.Query().Get("format")
+	if f == "" {
+		f = format.CSV
+	}
+	num := 0 // all users
+	if r.URL.Query().Get("num") != "" {
+		var err error
+		num, err = strconv.Atoi(r.URL.Query().Get("num"))
+		if err != nil {
+			slog.ErrorCtx(r.Context(), "request failed", "err", err)
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusInternalServerError)
+			_, _ = w.Write(CreateResponseJson(&ApiRequestResponse{Code: 3602, Data: err.Error()}))
+			return
+		}
+	}
+
+	out := &flushWriter{w: w}
+	enc, err := format.NewUserEncoder(f, out)
+	if err != nil {
+		slog.ErrorCtx(r.Context(), "request failed", "err", err)
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusInternalServerError)
+		_, _ = w.Write(CreateResponseJson(&ApiRequestResponse{Code: 3601, Data: err.Error()}))
+		return
+	}
+
+	// stream users
+	w.Header().Set("Content-Type", format.ContentType(f))
+	w.Header().Set("Content-Disposition", `attachment; filename="users.`+f+`"`)
+	err = h.usecase.Export(r.Context(), num, enc)
+	if err != nil {
+		slog.ErrorCtx(r.Context(), "request failed", "err", err)
+		// the status can not be changed once the body is written
+		if !out.written {
+			w.Header().Set("Content-Type", "application/json")
+			w.Header().Del("Content-Disposition")
+			w.WriteHeader(http.StatusInternalServerError)
+			_, _ = w.Write(CreateResponseJson(&ApiRequestResponse{Code: 3600, Data: err.Error()}))
+		}
+	}
+}
+
+// flushWriter sends written data to the client immediately
+type flushWriter struct {
+	w       http.ResponseWriter
+	written bool
+}
+
+func (fw *flushWriter) Write(p []byte) (int, error) {
+	fw.written = true
+	n, err := fw.w.Write(p)
+	if f, ok := fw.w.(http.Flusher); ok {
+		f.Flush()
+	}
+	return n, err
+}
@@ -0,0 +1,262 @@
+package handler
+
+import (
+	"encoding/json"
+	"github.com/jpdel518/go-ent/domain/model"
+	"github.com/jpdel518/go-ent/usecase"
+	"golang.org/x/exp/slog"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+type WebhookHandler struct {
+	usecase usecase.WebhookUsecase
+}
+
+func NewWebhookHandler(usecase usecase.WebhookUsecase) *WebhookHandler {
+	return &WebhookHandler{usecase}
+}
+
+// Webhooks handles /webhooks
+func (h *WebhookHandler) Webhooks(w http.ResponseWriter, r *http.Request) {
+	if r.Method == http.MethodPost {
+		h.Create(w, r)
+		return
+	}
+	h.Fetch(w, r)
+}
+
+// Webhook handles /webhooks/{id} and /webhooks/{id}/deliveries
+func (h *WebhookHandler) Webhook(w http.ResponseWriter, r *http.Request) {
+	if strings.HasSuffix(r.URL.Path, "/deliveries") {
+		h.FetchDeliveries(w, r)
+		return
+	}
+	switch r.Method {
+	case http.MethodPut:
+		h.Update(w, r)
+	case http.MethodDelete:
+		h.Delete(w, r)
+	default:
+		h.GetById(w, r)
+	}
+}
+
+func (h *WebhookHandler) Fetch(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+
+	// get query parameters
+	num, err := strconv.Atoi(r.URL.Query().Get("num"))
+	if err != nil {
+		num = 10
+	}
+
+	// fetch subscriptions
+	subscriptions, err := h.usecase.Fetch(r.Context(), num)
+	if err != nil {
+		slog.ErrorCtx(r.Context(), "request failed", "err", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		_, _ = w.Write(CreateResponseJson(&ApiRequestResponse{Code: 4000, Data: err.Error()}))
+		return
+	}
+
+	// response
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write(CreateResponseJson(&ApiRequestResponse{Code: 2000, Data: subscriptions}))
+}
+
+func (h *WebhookHandler) GetById(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+
+	// get path parameters
+	id, err := webhookID(r.URL.Path)
+	if err != nil {
+		slog.ErrorCtx(r.Context(), "request failed", "err", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		_, _ = w.Write(CreateResponseJson(&ApiRequestResponse{Code: 4101, Data: err.Error()}))
+		return
+	}
+
+	// fetch subscription
+	subscription, err := h.usecase.GetByID(r.Context(), id)
+	if err != nil {
+		slog.ErrorCtx(r.Context(), "request failed", "err", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		_, _ = w.Write(CreateResponseJson(&ApiRequestResponse{Code: 4100, Data: err.Error()}))
+		return
+	}
+
+	// response
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write(CreateResponseJson(&ApiRequestResponse{Code: 2000, Data: subscription}))
+}
+
+func (h *WebhookHandler) Create(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+
+	// get parameters
+	subscription := &model.WebhookSubscription{}
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		slog.ErrorCtx(r.Context(), "request failed", "err", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		_, _ = w.Write(CreateResponseJson(&ApiRequestResponse{Code: 4201, Data: err.Error()}))
+		return
+	}
+	err = json.Unmarshal(body, subscription)
+	if err != nil {
+		slog.ErrorCtx(r.Context(), "request failed", "err", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		_, _ = w.Write(CreateResponseJson(&ApiRequestResponse{Code: 4202, Data: err.Error()}))
+		return
+	}
+
+	// create subscription
+	subscription, err = h.usecase.Create(r.Context(), subscription)
+	if err != nil {
+		slog.ErrorCtx(r.Context(), "request failed", "err", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		_, _ = w.Write(CreateResponseJson(&ApiRequestResponse{Code: 4200, Data: err.Error()}))
+		return
+	}
+
+	// response
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write(CreateResponseJson(&ApiRequestResponse{Code: 2000, Data: subscription}))
+}
+
+func (h *WebhookHandler) Update(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPut {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+
+	// get parameters
+	id, err := webhookID(r.URL.Path)
+	if err != nil {
+		slog.ErrorCtx(r.Context(), "request failed", "err", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		_, _ = w.Write(CreateResponseJson(&ApiRequestResponse{Code: 4301, Data: err.Error()}))
+		return
+	}
+	// a subscription is enabled unless "active": false is given
+	subscription := &model.WebhookSubscription{Active: true}
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		slog.ErrorCtx(r.Context(), "request failed", "err", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		_, _ = w.Write(CreateResponseJson(&ApiRequestResponse{Code: 4302, Data: err.Error()}))
+		return
+	}
+	err = json.Unmarshal(body, subscription)
+	if err != nil {
+		slog.ErrorCtx(r.Context(), "request failed", "err", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		_, _ = w.Write(CreateResponseJson(&ApiRequestResponse{Code: 4303, Data: err.Error()}))
+		return
+	}
+	subscription.ID = id
+
+	// update subscription
+	subscription, err = h.usecase.Update(r.Context(), subscription)
+	if err != nil {
+		slog.ErrorCtx(r.Context(), "request failed", "err", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		_, _ = w.Write(CreateResponseJson(&ApiRequestResponse{Code: 4300, Data: err.Error()}))
+		return
+	}
+
+	// response
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write(CreateResponseJson(&ApiRequestResponse{Code: 2000, Data: subscription}))
+}
+
+func (h *WebhookHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodDelete {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+
+	// get path parameters
+	id, err := webhookID(r.URL.Path)
+	if err != nil {
+		slog.ErrorCtx(r.Context(), "request failed", "err", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		_, _ = w.Write(CreateResponseJson(&ApiRequestResponse{Code: 4401, Data: err.Error()}))
+		return
+	}
+
+	// delete subscription
+	err = h.usecase.Delete(r.Context(), id)
+	if err != nil {
+		slog.ErrorCtx(r.Context(), "request failed", "err", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		_, _ = w.Write(CreateResponseJson(&ApiRequestResponse{Code: 4400, Data: err.Error()}))
+		return
+	}
+
+	// response
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write(CreateResponseJson(&ApiRequestResponse{Code: 2000, Data: "success"}))
+}
+
+func (h *WebhookHandler) FetchDeliveries(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+
+	// get parameters
+	id, err := webhookID(strings.TrimSuffix(r.URL.Path, "/deliveries"))
+	if err != nil {
+		slog.ErrorCtx(r.Context(), "request failed", "err", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		_, _ = w.Write(CreateResponseJson(&ApiRequestResponse{Code: 4501, Data: err.Error()}))
+		return
+	}
+	num, err := strconv.Atoi(r.URL.Query().Get("num"))
+	if err != nil {
+		num = 50
+	}
+
+	// fetch delivery log
+	deliveries, err := h.usecase.FetchDeliveries(r.Context(), id, num)
+	if err != nil {
+		slog.ErrorCtx(r.Context(), "request failed", "err", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		_, _ = w.Write(CreateResponseJson(&ApiRequestResponse{Code: 4500, Data: err.Error()}))
+		return
+	}
+
+	// response
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write(CreateResponseJson(&ApiRequestResponse{Code: 2000, Data: deliveries}))
+}
+
+// webhookID reads the id from /webhooks/{id}
+func webhookID(path string) (int, error) {
+	return strconv.Atoi(strings.TrimPrefix(path, "/webhooks/"))
+}
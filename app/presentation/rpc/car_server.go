@@ -0,0 +1,81 @@
+package rpc
+
+import (
+	"context"
+	"github.com/jpdel518/go-ent/domain/model"
+	goentv1 "github.com/jpdel518/go-ent/proto/goent/v1"
+	"github.com/jpdel518/go-ent/usecase"
+	"google.golang.org/protobuf/types/known/emptypb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+type carServer struct {
+	goentv1.UnimplementedCarServiceServer
+	usecase usecase.CarUsecase
+}
+
+func (s *carServer) ListCars(req *goentv1.ListCarsRequest, stream goentv1.CarService_ListCarsServer) error {
+	cars, err := s.usecase.Fetch(stream.Context(), listNum(req.GetNum()))
+	if err != nil {
+		return toStatus(err)
+	}
+	for _, c := range cars {
+		if err := stream.Send(toCarProto(c)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *carServer) GetCar(ctx context.Context, req *goentv1.GetCarRequest) (*goentv1.Car, error) {
+	c, err := s.usecase.GetByID(ctx, int(req.GetId()))
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return toCarProto(c), nil
+}
+
+func (s *carServer) CreateCar(ctx context.Context, req *goentv1.CreateCarRequest) (*goentv1.Car, error) {
+	c := &model.Car{
+		Name:  req.GetName(),
+		Model: req.GetModel(),
+	}
+	if req.GetRegisteredAt() != nil {
+		c.RegisteredAt = req.GetRegisteredAt().AsTime()
+	}
+	if err := s.usecase.Create(ctx, c); err != nil {
+		return nil, toStatus(err)
+	}
+	return toCarProto(c), nil
+}
+
+func (s *carServer) UpdateCar(ctx context.Context, req *goentv1.UpdateCarRequest) (*goentv1.Car, error) {
+	c := &model.Car{
+		ID:    int(req.GetId()),
+		Name:  req.GetName(),
+		Model: req.GetModel(),
+	}
+	if req.GetRegisteredAt() != nil {
+		c.RegisteredAt = req.GetRegisteredAt().AsTime()
+	}
+	if err := s.usecase.Update(ctx, c); err != nil {
+		return nil, toStatus(err)
+	}
+	return toCarProto(c), nil
+}
+
+func (s *carServer) DeleteCar(ctx context.Context, req *goentv1.DeleteCarRequest) (*emptypb.Empty, error) {
+	if err := s.usecase.Delete(ctx, int(req.GetId())); err != nil {
+		return nil, toStatus(err)
+	}
+	return &emptypb.Empty{}, nil
+}
+
+func toCarProto(c *model.Car) *goentv1.Car {
+	return &goentv1.Car{
+		Id:           int64(c.ID),
+		Name:         c.Name,
+		Model:        c.Model,
+		RegisteredAt: timestamppb.New(c.RegisteredAt),
+	}
+}
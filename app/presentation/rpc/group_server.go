@@ -0,0 +1,73 @@
+package rpc
+
+import (
+	"context"
+	"github.com/jpdel518/go-ent/domain/model"
+	goentv1 "github.com/jpdel518/go-ent/proto/goent/v1"
+	"github.com/jpdel518/go-ent/usecase"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+type groupServer struct {
+	goentv1.UnimplementedGroupServiceServer
+	usecase usecase.GroupUsecase
+}
+
+func (s *groupServer) ListGroups(req *goentv1.ListGroupsRequest, stream goentv1.GroupService_ListGroupsServer) error {
+	groups, err := s.usecase.Fetch(stream.Context(), listNum(req.GetNum()))
+	if err != nil {
+		return toStatus(err)
+	}
+	for _, g := range groups {
+		if err := stream.Send(toGroupProto(g)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *groupServer) GetGroup(ctx context.Context, req *goentv1.GetGroupRequest) (*goentv1.Group, error) {
+	g, err := s.usecase.GetByID(ctx, int(req.GetId()))
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return toGroupProto(g), nil
+}
+
+func (s *groupServer) CreateGroup(ctx context.Context, req *goentv1.CreateGroupRequest) (*goentv1.Group, error) {
+	g := &model.Group{
+		Name:    req.GetName(),
+		UserIDs: toIDs(req.GetUserIds()),
+	}
+	if err := s.usecase.Create(ctx, g); err != nil {
+		return nil, toStatus(err)
+	}
+	return toGroupProto(g), nil
+}
+
+func (s *groupServer) UpdateGroup(ctx context.Context, req *goentv1.UpdateGroupRequest) (*goentv1.Group, error) {
+	g := &model.Group{
+		ID:      int(req.GetId()),
+		Name:    req.GetName(),
+		UserIDs: toIDs(req.GetUserIds()),
+	}
+	if err := s.usecase.Update(ctx, g); err != nil {
+		return nil, toStatus(err)
+	}
+	return toGroupProto(g), nil
+}
+
+func (s *groupServer) DeleteGroup(ctx context.Context, req *goentv1.DeleteGroupRequest) (*emptypb.Empty, error) {
+	if err := s.usecase.Delete(ctx, int(req.GetId())); err != nil {
+		return nil, toStatus(err)
+	}
+	return &emptypb.Empty{}, nil
+}
+
+func toGroupProto(g *model.Group) *goentv1.Group {
+	return &goentv1.Group{
+		Id:      int64(g.ID),
+		Name:    g.Name,
+		UserIds: fromIDs(g.UserIDs),
+	}
+}
@@ -0,0 +1,81 @@
+package rpc
+
+import (
+	"context"
+	"github.com/jpdel518/go-ent/logging"
+	"github.com/jpdel518/go-ent/usecase"
+	"golang.org/x/exp/slog"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// limitRateUnary rejects the calls of a client over the limit of the method with ResourceExhausted, as the HTTP routes do.
+// The routes of the limits are the full methods such as "/goent.v1.UserService/ImportUsers"
+func limitRateUnary(u usecase.RateLimitUsecase, clientIPHeader string) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if err := limit(ctx, u, clientIPHeader, info.FullMethod); err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+func limitRateStream(u usecase.RateLimitUsecase, clientIPHeader string) grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if err := limit(ss.Context(), u, clientIPHeader, info.FullMethod); err != nil {
+			return err
+		}
+		return handler(srv, ss)
+	}
+}
+
+// limit takes a token of the client for the method. The reflection is not limited, and the calls go on when the store fails
+func limit(ctx context.Context, u usecase.RateLimitUsecase, clientIPHeader string, method string) error {
+	if strings.HasPrefix(method, reflectionPrefix) {
+		return nil
+	}
+	decision, err := u.Allow(ctx, method, client(ctx, clientIPHeader))
+	if err != nil {
+		slog.WarnCtx(ctx, "rate limit check failed", "err", err)
+		return nil
+	}
+	if decision == nil || decision.Allowed {
+		return nil
+	}
+	_ = grpc.SetTrailer(ctx, metadata.Pairs("retry-after", seconds(decision.RetryAfter)))
+	return status.Error(codes.ResourceExhausted, "rate limit exceeded")
+}
+
+// client identifies the client by the principal, or by the address which the proxy sets in the metadata or the peer
+func client(ctx context.Context, clientIPHeader string) string {
+	if principal := logging.Principal(ctx); principal != "" {
+		return principal
+	}
+	if md, ok := metadata.FromIncomingContext(ctx); ok && clientIPHeader != "" {
+		// the proxy appends the address it sees, the addresses before it are set by the client
+		addrs := strings.Split(first(md, strings.ToLower(clientIPHeader)), ",")
+		if ip := strings.TrimSpace(addrs[len(addrs)-1]); ip != "" {
+			return "ip:" + ip
+		}
+	}
+	if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+		host, _, err := net.SplitHostPort(p.Addr.String())
+		if err != nil {
+			host = p.Addr.String()
+		}
+		return "ip:" + host
+	}
+	return "ip:unknown"
+}
+
+// seconds rounds up, so that a client waiting for it is not rejected again
+func seconds(d time.Duration) string {
+	return strconv.FormatInt(int64((d+time.Second-1)/time.Second), 10)
+}
@@ -0,0 +1,68 @@
+package rpc
+
+import (
+	"context"
+	"github.com/jpdel518/go-ent/domain/model"
+	goentv1 "github.com/jpdel518/go-ent/proto/goent/v1"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"testing"
+	"time"
+)
+
+func TestRateLimit(t *testing.T) {
+	s := newRPCServer(t, options{client: model.RateLimit{Limit: 2, Period: time.Minute}})
+	ctx := context.Background()
+
+	for i := 0; i < 2; i++ {
+		if _, err := s.cars.GetCar(ctx, &goentv1.GetCarRequest{Id: 1}); status.Code(err) != codes.NotFound {
+			t.Fatalf("GetCar = %v, want NotFound", err)
+		}
+	}
+	var trailer metadata.MD
+	_, err := s.cars.GetCar(ctx, &goentv1.GetCarRequest{Id: 1}, grpc.Trailer(&trailer))
+	if status.Code(err) != codes.ResourceExhausted {
+		t.Fatalf("GetCar over the limit = %v, want ResourceExhausted", err)
+	}
+	if got := trailer.Get("retry-after"); len(got) != 1 || got[0] != "30" {
+		t.Errorf("retry-after = %v, want 30", got)
+	}
+
+	// the streams take from the same bucket
+	stream, err := s.cars.ListCars(ctx, &goentv1.ListCarsRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := stream.Recv(); status.Code(err) != codes.ResourceExhausted {
+		t.Errorf("ListCars over the limit = %v, want ResourceExhausted", err)
+	}
+
+	// a verified API key has its own bucket
+	if _, err := s.cars.GetCar(withAPIKey(ctx, "acme-key"), &goentv1.GetCarRequest{Id: 1}); status.Code(err) != codes.NotFound {
+		t.Errorf("GetCar with the API key = %v, want NotFound", err)
+	}
+}
+
+func TestRateLimitAddress(t *testing.T) {
+	s := newRPCServer(t, options{address: model.RateLimit{Limit: 3, Period: time.Minute}})
+	ctx := context.Background()
+
+	// the made-up API keys are limited before they are looked up
+	for _, key := range []string{"made-up-key-1", "made-up-key-2", "made-up-key-3"} {
+		if _, err := s.cars.GetCar(withAPIKey(ctx, key), &goentv1.GetCarRequest{Id: 1}); status.Code(err) != codes.Unauthenticated {
+			t.Fatalf("GetCar with a made-up API key = %v, want Unauthenticated", err)
+		}
+	}
+	if _, err := s.cars.GetCar(withAPIKey(ctx, "made-up-key-4"), &goentv1.GetCarRequest{Id: 1}); status.Code(err) != codes.ResourceExhausted {
+		t.Errorf("GetCar over the limit of the address = %v, want ResourceExhausted", err)
+	}
+	stream, err := s.users.ImportUsers(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := stream.CloseAndRecv(); status.Code(err) != codes.ResourceExhausted {
+		t.Errorf("ImportUsers over the limit of the address = %v, want ResourceExhausted", err)
+	}
+}
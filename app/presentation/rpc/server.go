@@ -0,0 +1,71 @@
+package rpc
+
+import (
+	"context"
+	"github.com/jpdel518/go-ent/logging"
+	goentv1 "github.com/jpdel518/go-ent/proto/goent/v1"
+	"github.com/jpdel518/go-ent/usecase"
+	"golang.org/x/exp/slog"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/reflection"
+	"google.golang.org/grpc/status"
+	"strings"
+)
+
+// NewServer creates the gRPC server of the user, car and group services with reflection enabled.
+// The calls are scoped to the tenant of their metadata, and limited by the address before it and by the client after it as the HTTP requests are
+func NewServer(userUsecase usecase.UserUsecase, carUsecase usecase.CarUsecase, groupUsecase usecase.GroupUsecase, tenantUsecase usecase.TenantUsecase, rateLimitUsecase usecase.RateLimitUsecase, addressRateLimitUsecase usecase.RateLimitUsecase, clientIPHeader string) *grpc.Server {
+	s := grpc.NewServer(
+		grpc.ChainUnaryInterceptor(logUnary, limitRateUnary(addressRateLimitUsecase, clientIPHeader), scopeTenantUnary(tenantUsecase), limitRateUnary(rateLimitUsecase, clientIPHeader)),
+		grpc.ChainStreamInterceptor(logStream, limitRateStream(addressRateLimitUsecase, clientIPHeader), scopeTenantStream(tenantUsecase), limitRateStream(rateLimitUsecase, clientIPHeader)),
+	)
+	goentv1.RegisterUserServiceServer(s, &userServer{usecase: userUsecase})
+	goentv1.RegisterCarServiceServer(s, &carServer{usecase: carUsecase})
+	goentv1.RegisterGroupServiceServer(s, &groupServer{usecase: groupUsecase})
+	reflection.Register(s)
+	return s
+}
+
+func logUnary(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	ctx = withRequestID(ctx)
+	res, err := handler(ctx, req)
+	if err != nil {
+		slog.WarnCtx(ctx, "rpc failed", "method", info.FullMethod, "code", status.Code(err).String(), "err", err)
+	}
+	return res, err
+}
+
+func logStream(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	ctx := withRequestID(ss.Context())
+	err := handler(srv, &serverStream{ServerStream: ss, ctx: ctx})
+	if err != nil {
+		slog.WarnCtx(ctx, "rpc failed", "method", info.FullMethod, "code", status.Code(err).String(), "err", err)
+	}
+	return err
+}
+
+// withRequestID puts the x-request-id of the metadata, or a new id, into the context for the logs
+func withRequestID(ctx context.Context) context.Context {
+	var id string
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if ids := md.Get(strings.ToLower(logging.RequestIDHeader)); len(ids) > 0 {
+			id = ids[0]
+		}
+	}
+	if !logging.ValidRequestID(id) {
+		id = logging.NewRequestID()
+	}
+	_ = grpc.SetHeader(ctx, metadata.Pairs(strings.ToLower(logging.RequestIDHeader), id))
+	return logging.WithRequestID(ctx, id)
+}
+
+// serverStream replaces the context of a stream
+type serverStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *serverStream) Context() context.Context {
+	return s.ctx
+}
@@ -0,0 +1,122 @@
+package rpc
+
+import (
+	"context"
+	"fmt"
+	"github.com/jpdel518/go-ent/domain/model"
+	"github.com/jpdel518/go-ent/ent"
+	"github.com/jpdel518/go-ent/ent/enttest"
+	"github.com/jpdel518/go-ent/infrastructure/memory"
+	"github.com/jpdel518/go-ent/infrastructure/ratelimit"
+	"github.com/jpdel518/go-ent/infrastructure/rdb"
+	goentv1 "github.com/jpdel518/go-ent/proto/goent/v1"
+	"github.com/jpdel518/go-ent/usecase"
+	_ "github.com/mattn/go-sqlite3"
+	"golang.org/x/exp/slog"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	"io"
+	"log"
+	"net"
+	"os"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestMain(m *testing.M) {
+	// the logs of the failed calls are noise here
+	log.SetOutput(io.Discard)
+	slog.SetDefault(slog.New(slog.NewTextHandler(io.Discard)))
+	os.Exit(m.Run())
+}
+
+// databases names a new in-memory database for each server
+var databases atomic.Uint64
+
+// rpcServer is the gRPC server on SQLite, served over an in-memory connection.
+// The requests without credentials belong to the default tenant, and those with the API key "acme-key" to acme
+type rpcServer struct {
+	client *ent.Client
+	conn   *grpc.ClientConn
+	users  goentv1.UserServiceClient
+	cars   goentv1.CarServiceClient
+	groups goentv1.GroupServiceClient
+}
+
+// options of the server. The limits of the clients and of the addresses are off when empty,
+// and the requests without credentials belong to the default tenant unless the tenancy is given
+type options struct {
+	client  model.RateLimit
+	address model.RateLimit
+	tenancy *usecase.TenancyConfig
+}
+
+func newRPCServer(t *testing.T, o options) *rpcServer {
+	t.Helper()
+	client := enttest.Open(t, "sqlite3", fmt.Sprintf("file:rpc%d?mode=memory&cache=shared&_fk=1", databases.Add(1)))
+	t.Cleanup(func() {
+		_ = client.Close()
+	})
+	tenants := rdb.NewTenantRepository(client)
+	for _, tn := range []struct{ slug, apiKey string }{{slug: "default"}, {slug: "acme", apiKey: "acme-key"}} {
+		if err := tenants.Create(context.Background(), &model.Tenant{Name: tn.slug, Slug: tn.slug}, tn.apiKey); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tenancy := usecase.TenancyConfig{Default: "default"}
+	if o.tenancy != nil {
+		tenancy = *o.tenancy
+	}
+	timeout := 5 * time.Second
+	verification := usecase.VerificationConfig{Secret: "rpc-secret", TTL: time.Hour, URL: "http://localhost:8080/user/verify"}
+	carRepository := rdb.NewCarRepository(client)
+	s := NewServer(
+		usecase.NewUserUsecase(rdb.NewUserRepository(client), carRepository, memory.NewUserFileRepository(memory.NewStore()), verification, timeout),
+		usecase.NewCarUsecase(carRepository, timeout),
+		usecase.NewGroupUsecase(rdb.NewGroupRepository(client), timeout),
+		usecase.NewTenantUsecase(tenants, tenancy, timeout),
+		usecase.NewRateLimitUsecase(ratelimit.NewMemoryStore(), o.client, nil, timeout),
+		usecase.NewRateLimitUsecase(ratelimit.NewMemoryStore(), o.address, nil, timeout),
+		"",
+	)
+	lis := bufconn.Listen(1 << 20)
+	go func() {
+		_ = s.Serve(lis)
+	}()
+	t.Cleanup(s.Stop)
+
+	conn, err := grpc.Dial("bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return lis.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		_ = conn.Close()
+	})
+	return &rpcServer{
+		client: client,
+		conn:   conn,
+		users:  goentv1.NewUserServiceClient(conn),
+		cars:   goentv1.NewCarServiceClient(conn),
+		groups: goentv1.NewGroupServiceClient(conn),
+	}
+}
+
+// leaf is the request to create a car
+func leaf() *goentv1.CreateCarRequest {
+	return &goentv1.CreateCarRequest{Name: "Leaf", Model: "Nissan", RegisteredAt: timestamppb.New(time.Date(2020, 4, 1, 9, 0, 0, 0, time.UTC))}
+}
+
+// withAPIKey sends the API key in the metadata
+func withAPIKey(ctx context.Context, apiKey string) context.Context {
+	return metadata.AppendToOutgoingContext(ctx, "x-api-key", apiKey)
+}
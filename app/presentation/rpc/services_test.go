@@ -0,0 +1,157 @@
+package rpc
+
+import (
+	"context"
+	"fmt"
+	"github.com/jpdel518/go-ent/domain/model"
+	goentv1 "github.com/jpdel518/go-ent/proto/goent/v1"
+	"github.com/jpdel518/go-ent/usecase"
+	"google.golang.org/grpc"
+	rpb "google.golang.org/grpc/reflection/grpc_reflection_v1alpha"
+	"io"
+	"testing"
+)
+
+// count receives the messages of the stream until it ends
+func count[T any](t *testing.T, recv func() (T, error)) int {
+	t.Helper()
+	n := 0
+	for {
+		if _, err := recv(); err == io.EOF {
+			return n
+		} else if err != nil {
+			t.Fatal(err)
+		}
+		n++
+	}
+}
+
+func TestList(t *testing.T) {
+	s := newRPCServer(t, options{})
+	ctx := context.Background()
+	for i := 0; i < 12; i++ {
+		if _, err := s.cars.CreateCar(ctx, leaf()); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := s.users.CreateUser(ctx, &goentv1.CreateUserRequest{FirstName: "Taro", LastName: "Yamada", Email: fmt.Sprintf("taro%d@example.com", i), Age: 30}); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := s.groups.CreateGroup(ctx, &goentv1.CreateGroupRequest{Name: "group_" + string(rune('a'+i))}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// num 0 lists 10 like the REST API
+	for _, num := range []int32{0, 11} {
+		want := listNum(num)
+		t.Run(fmt.Sprintf("num %d", num), func(t *testing.T) {
+			cars, err := s.cars.ListCars(ctx, &goentv1.ListCarsRequest{Num: num})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := count(t, cars.Recv); got != want {
+				t.Errorf("ListCars = %d cars, want %d", got, want)
+			}
+			users, err := s.users.ListUsers(ctx, &goentv1.ListUsersRequest{Num: num})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := count(t, users.Recv); got != want {
+				t.Errorf("ListUsers = %d users, want %d", got, want)
+			}
+			groups, err := s.groups.ListGroups(ctx, &goentv1.ListGroupsRequest{Num: num})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := count(t, groups.Recv); got != want {
+				t.Errorf("ListGroups = %d groups, want %d", got, want)
+			}
+		})
+	}
+
+	// the users are streamed in id order
+	users, err := s.users.ListUsers(ctx, &goentv1.ListUsersRequest{Num: 3})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for want := int64(1); want <= 3; want++ {
+		u, err := users.Recv()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if u.GetId() != want {
+			t.Errorf("user id = %d, want %d", u.GetId(), want)
+		}
+	}
+}
+
+func TestImportUsers(t *testing.T) {
+	s := newRPCServer(t, options{})
+	stream, err := s.users.ImportUsers(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, req := range []*goentv1.ImportUsersRequest{
+		{FirstName: "Taro", LastName: "Yamada", Email: "taro@example.com", Age: 30},
+		{FirstName: "Jiro", LastName: "Yamada", Email: "not-an-email", Age: 28},
+		{FirstName: "Hanako", LastName: "Suzuki", Email: "hanako@example.com", Age: 25},
+	} {
+		if err := stream.Send(req); err != nil {
+			t.Fatal(err)
+		}
+	}
+	res, err := stream.CloseAndRecv()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the invalid row fails alone
+	if res.GetCreated() != 2 || res.GetUpdated() != 0 || res.GetFailed() != 1 {
+		t.Errorf("created, updated, failed = %d, %d, %d, want 2, 0, 1", res.GetCreated(), res.GetUpdated(), res.GetFailed())
+	}
+	want := []model.ImportStatus{model.ImportStatusCreated, model.ImportStatusFailed, model.ImportStatusCreated}
+	if len(res.GetRows()) != len(want) {
+		t.Fatalf("rows = %v, want %d", res.GetRows(), len(want))
+	}
+	for i, row := range res.GetRows() {
+		if row.GetLine() != int32(i+1) || row.GetStatus() != string(want[i]) {
+			t.Errorf("row %d = line %d %s, want line %d %s", i, row.GetLine(), row.GetStatus(), i+1, want[i])
+		}
+	}
+	if failed := res.GetRows()[1]; failed.GetReason() == "" || failed.GetId() != 0 {
+		t.Errorf("failed row = %v, want the reason and no id", failed)
+	}
+
+	users, err := s.users.ListUsers(context.Background(), &goentv1.ListUsersRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := count(t, users.Recv); got != 2 {
+		t.Errorf("ListUsers = %d users, want the 2 imported", got)
+	}
+}
+
+func TestReflection(t *testing.T) {
+	// the reflection needs no tenant
+	s := newRPCServer(t, options{tenancy: &usecase.TenancyConfig{}})
+	stream, err := rpb.NewServerReflectionClient(s.conn).ServerReflectionInfo(context.Background(), grpc.WaitForReady(true))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := stream.Send(&rpb.ServerReflectionRequest{MessageRequest: &rpb.ServerReflectionRequest_ListServices{}}); err != nil {
+		t.Fatal(err)
+	}
+	res, err := stream.Recv()
+	if err != nil {
+		t.Fatal(err)
+	}
+	services := map[string]bool{}
+	for _, service := range res.GetListServicesResponse().GetService() {
+		services[service.GetName()] = true
+	}
+	for _, want := range []string{"goent.v1.UserService", "goent.v1.CarService", "goent.v1.GroupService"} {
+		if !services[want] {
+			t.Errorf("services = %v, want %s", services, want)
+		}
+	}
+}
@@ -0,0 +1,59 @@
+package rpc
+
+import (
+	"context"
+	"errors"
+	validation "github.com/go-ozzo/ozzo-validation"
+	"github.com/jpdel518/go-ent/domain/repository"
+	"github.com/jpdel518/go-ent/ent"
+	"github.com/jpdel518/go-ent/tenant"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// toStatus converts an error of the usecases to a gRPC status error
+func toStatus(err error) error {
+	if err == nil {
+		return nil
+	}
+	var verrs validation.Errors
+	switch {
+	case errors.As(err, &verrs), ent.IsValidationError(err):
+		return status.Error(codes.InvalidArgument, err.Error())
+	case errors.Is(err, tenant.ErrCrossTenant):
+		return status.Error(codes.InvalidArgument, err.Error())
+	case errors.Is(err, repository.ErrNotFound), ent.IsNotFound(err):
+		return status.Error(codes.NotFound, err.Error())
+	case errors.Is(err, repository.ErrDuplicateEmail), ent.IsConstraintError(err):
+		return status.Error(codes.AlreadyExists, err.Error())
+	case errors.Is(err, context.DeadlineExceeded):
+		return status.Error(codes.DeadlineExceeded, err.Error())
+	case errors.Is(err, context.Canceled):
+		return status.Error(codes.Canceled, err.Error())
+	}
+	return status.Error(codes.Internal, err.Error())
+}
+
+func toIDs(ids []int64) []int {
+	res := make([]int, 0, len(ids))
+	for _, id := range ids {
+		res = append(res, int(id))
+	}
+	return res
+}
+
+func fromIDs(ids []int) []int64 {
+	res := make([]int64, 0, len(ids))
+	for _, id := range ids {
+		res = append(res, int64(id))
+	}
+	return res
+}
+
+// listNum is the number of items returned by a list RPC when num is not given
+func listNum(num int32) int {
+	if num <= 0 {
+		return 10
+	}
+	return int(num)
+}
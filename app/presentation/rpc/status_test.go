@@ -0,0 +1,44 @@
+package rpc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	validation "github.com/go-ozzo/ozzo-validation"
+	"github.com/jpdel518/go-ent/domain/repository"
+	"github.com/jpdel518/go-ent/ent"
+	"github.com/jpdel518/go-ent/tenant"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"testing"
+)
+
+func TestToStatus(t *testing.T) {
+	tests := []struct {
+		err  error
+		want codes.Code
+	}{
+		{err: validation.Errors{"email": errors.New("must be a valid email address")}, want: codes.InvalidArgument},
+		{err: fmt.Errorf("failed creating group: %w", tenant.ErrCrossTenant), want: codes.InvalidArgument},
+		{err: fmt.Errorf("failed getting car 1: %w", repository.ErrNotFound), want: codes.NotFound},
+		{err: &ent.NotFoundError{}, want: codes.NotFound},
+		{err: repository.ErrDuplicateEmail, want: codes.AlreadyExists},
+		{err: &ent.ConstraintError{}, want: codes.AlreadyExists},
+		{err: fmt.Errorf("failed fetching users: %w", context.DeadlineExceeded), want: codes.DeadlineExceeded},
+		{err: context.Canceled, want: codes.Canceled},
+		{err: errors.New("sql: database is closed"), want: codes.Internal},
+	}
+	for _, tt := range tests {
+		err := toStatus(tt.err)
+		if got := status.Code(err); got != tt.want {
+			t.Errorf("toStatus(%v) = %v, want %v", tt.err, got, tt.want)
+		}
+		// the message tells the cause
+		if s, _ := status.FromError(err); s.Message() != tt.err.Error() {
+			t.Errorf("message of toStatus(%v) = %q", tt.err, s.Message())
+		}
+	}
+	if err := toStatus(nil); err != nil {
+		t.Errorf("toStatus(nil) = %v, want nil", err)
+	}
+}
@@ -0,0 +1,78 @@
+package rpc
+
+import (
+	"context"
+	"errors"
+	"github.com/jpdel518/go-ent/domain/model"
+	"github.com/jpdel518/go-ent/logging"
+	"github.com/jpdel518/go-ent/tenant"
+	"github.com/jpdel518/go-ent/usecase"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"strings"
+)
+
+// reflectionPrefix is the prefix of the methods of the reflection service, which belongs to no tenant
+const reflectionPrefix = "/grpc.reflection."
+
+func scopeTenantUnary(u usecase.TenantUsecase) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if strings.HasPrefix(info.FullMethod, reflectionPrefix) {
+			return handler(ctx, req)
+		}
+		ctx, err := withTenant(ctx, u)
+		if err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+func scopeTenantStream(u usecase.TenantUsecase) grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if strings.HasPrefix(info.FullMethod, reflectionPrefix) {
+			return handler(srv, ss)
+		}
+		ctx, err := withTenant(ss.Context(), u)
+		if err != nil {
+			return err
+		}
+		return handler(srv, &serverStream{ServerStream: ss, ctx: ctx})
+	}
+}
+
+// withTenant puts the tenant of the x-api-key, the bearer token of the authorization or the authority of the metadata into the context,
+// with the principal of the logs
+func withTenant(ctx context.Context, u usecase.TenantUsecase) (context.Context, error) {
+	var cred model.TenantCredentials
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		cred.APIKey = first(md, "x-api-key")
+		if auth := first(md, "authorization"); len(auth) > len("Bearer ") && strings.EqualFold(auth[:len("Bearer ")], "Bearer ") {
+			cred.Token = auth[len("Bearer "):]
+		}
+		cred.Host = first(md, ":authority")
+	}
+	a, err := u.Resolve(ctx, cred)
+	switch {
+	case errors.Is(err, usecase.ErrUnknownTenant), errors.Is(err, usecase.ErrInvalidAPIKey), errors.Is(err, usecase.ErrInvalidToken):
+		return nil, status.Error(codes.Unauthenticated, err.Error())
+	case errors.Is(err, usecase.ErrTenantMismatch):
+		return nil, status.Error(codes.PermissionDenied, err.Error())
+	case err != nil:
+		return nil, toStatus(err)
+	}
+	ctx = tenant.NewContext(ctx, a.Tenant.ID)
+	if a.Principal != "" {
+		ctx = logging.WithPrincipal(ctx, a.Principal)
+	}
+	return ctx, nil
+}
+
+func first(md metadata.MD, key string) string {
+	if v := md.Get(key); len(v) > 0 {
+		return v[0]
+	}
+	return ""
+}
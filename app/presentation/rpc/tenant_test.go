@@ -0,0 +1,85 @@
+package rpc
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	goentv1 "github.com/jpdel518/go-ent/proto/goent/v1"
+	"github.com/jpdel518/go-ent/usecase"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"io"
+	"testing"
+)
+
+// signToken signs the HS256 token with the tenant claim
+func signToken(secret string, tenantSlug string) string {
+	header := base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"HS256","typ":"JWT"}`))
+	claims := base64.RawURLEncoding.EncodeToString([]byte(`{"tenant":"` + tenantSlug + `"}`))
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(header + "." + claims))
+	return header + "." + claims + "." + base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+func TestScopeTenant(t *testing.T) {
+	const secret = "0123456789abcdef"
+	s := newRPCServer(t, options{tenancy: &usecase.TenancyConfig{JWTSecret: secret}})
+	ctx := context.Background()
+	acme := withAPIKey(ctx, "acme-key")
+	if _, err := s.cars.CreateCar(acme, leaf()); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name string
+		ctx  context.Context
+		want codes.Code
+	}{
+		{name: "no credentials without a default tenant", ctx: ctx, want: codes.Unauthenticated},
+		{name: "made-up API key", ctx: withAPIKey(ctx, "made-up-key"), want: codes.Unauthenticated},
+		{name: "forged token", ctx: metadata.AppendToOutgoingContext(ctx, "authorization", "Bearer "+signToken("another-secret", "acme")), want: codes.Unauthenticated},
+		{name: "credentials of two tenants", ctx: metadata.AppendToOutgoingContext(acme, "authorization", "Bearer "+signToken(secret, "default")), want: codes.PermissionDenied},
+		{name: "API key", ctx: acme, want: codes.OK},
+		{name: "token", ctx: metadata.AppendToOutgoingContext(ctx, "authorization", "Bearer "+signToken(secret, "acme")), want: codes.OK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// both the unary and the stream interceptors reject the call
+			if _, err := s.cars.GetCar(tt.ctx, &goentv1.GetCarRequest{Id: 1}); status.Code(err) != tt.want {
+				t.Errorf("GetCar = %v, want %v", err, tt.want)
+			}
+			stream, err := s.cars.ListCars(tt.ctx, &goentv1.ListCarsRequest{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			_, err = stream.Recv()
+			if tt.want == codes.OK {
+				if err != nil {
+					t.Errorf("ListCars = %v, want the car", err)
+				}
+				return
+			}
+			if status.Code(err) != tt.want {
+				t.Errorf("ListCars = %v, want %v", err, tt.want)
+			}
+		})
+	}
+
+	// the car of acme is not found in the default tenant
+	s = newRPCServer(t, options{})
+	if _, err := s.cars.CreateCar(withAPIKey(ctx, "acme-key"), leaf()); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.cars.GetCar(ctx, &goentv1.GetCarRequest{Id: 1}); status.Code(err) != codes.NotFound {
+		t.Errorf("GetCar of another tenant = %v, want NotFound", err)
+	}
+	stream, err := s.cars.ListCars(ctx, &goentv1.ListCarsRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := stream.Recv(); err != io.EOF {
+		t.Errorf("ListCars of the default tenant = %v, want no cars", err)
+	}
+}
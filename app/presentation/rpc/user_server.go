@@ -0,0 +1,151 @@
+package rpc
+
+import (
+	"context"
+	"github.com/jpdel518/go-ent/domain/model"
+	goentv1 "github.com/jpdel518/go-ent/proto/goent/v1"
+	"github.com/jpdel518/go-ent/usecase"
+	"google.golang.org/protobuf/types/known/emptypb"
+	"io"
+)
+
+type userServer struct {
+	goentv1.UnimplementedUserServiceServer
+	usecase usecase.UserUsecase
+}
+
+func (s *userServer) ListUsers(req *goentv1.ListUsersRequest, stream goentv1.UserService_ListUsersServer) error {
+	err := s.usecase.Export(stream.Context(), listNum(req.GetNum()), &userStreamEncoder{stream: stream})
+	return toStatus(err)
+}
+
+func (s *userServer) GetUser(ctx context.Context, req *goentv1.GetUserRequest) (*goentv1.User, error) {
+	u, err := s.usecase.GetByID(ctx, int(req.GetId()), model.CarDetailsAll)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return toUserProto(u), nil
+}
+
+func (s *userServer) CreateUser(ctx context.Context, req *goentv1.CreateUserRequest) (*goentv1.User, error) {
+	u := &model.User{
+		FirstName: req.GetFirstName(),
+		LastName:  req.GetLastName(),
+		Email:     req.GetEmail(),
+		Age:       int(req.GetAge()),
+		CarIDs:    toIDs(req.GetCarIds()),
+	}
+	if err := u.Validate(); err != nil {
+		return nil, toStatus(err)
+	}
+	if err := s.usecase.Create(ctx, u, nil, nil); err != nil {
+		return nil, toStatus(err)
+	}
+	return s.GetUser(ctx, &goentv1.GetUserRequest{Id: int64(u.ID)})
+}
+
+func (s *userServer) UpdateUser(ctx context.Context, req *goentv1.UpdateUserRequest) (*goentv1.User, error) {
+	u := &model.User{
+		ID:        int(req.GetId()),
+		FirstName: req.GetFirstName(),
+		LastName:  req.GetLastName(),
+		Email:     req.GetEmail(),
+		Age:       int(req.GetAge()),
+		CarIDs:    toIDs(req.GetCarIds()),
+	}
+	if err := u.Validate(); err != nil {
+		return nil, toStatus(err)
+	}
+	if err := s.usecase.Update(ctx, u, nil, nil); err != nil {
+		return nil, toStatus(err)
+	}
+	return s.GetUser(ctx, &goentv1.GetUserRequest{Id: req.GetId()})
+}
+
+func (s *userServer) DeleteUser(ctx context.Context, req *goentv1.DeleteUserRequest) (*emptypb.Empty, error) {
+	if err := s.usecase.Delete(ctx, int(req.GetId())); err != nil {
+		return nil, toStatus(err)
+	}
+	return &emptypb.Empty{}, nil
+}
+
+func (s *userServer) ImportUsers(stream goentv1.UserService_ImportUsersServer) error {
+	report, err := s.usecase.Import(stream.Context(), &userStreamDecoder{stream: stream})
+	if err != nil {
+		return toStatus(err)
+	}
+
+	res := &goentv1.ImportUsersResponse{
+		Created: int32(report.Created),
+		Updated: int32(report.Updated),
+		Failed:  int32(report.Failed),
+	}
+	for _, row := range report.Rows {
+		res.Rows = append(res.Rows, &goentv1.ImportRow{
+			Line:   int32(row.Line),
+			Status: string(row.Status),
+			Id:     int64(row.ID),
+			Email:  row.Email,
+			Reason: row.Reason,
+		})
+	}
+	return stream.SendAndClose(res)
+}
+
+// userStreamEncoder sends the exported users to the stream
+type userStreamEncoder struct {
+	stream goentv1.UserService_ListUsersServer
+}
+
+func (e *userStreamEncoder) Encode(u *model.User) error {
+	return e.stream.Send(toUserProto(u))
+}
+
+func (e *userStreamEncoder) Flush() error {
+	return nil
+}
+
+// userStreamDecoder reads the users to import from the stream
+type userStreamDecoder struct {
+	stream goentv1.UserService_ImportUsersServer
+	line   int
+}
+
+func (d *userStreamDecoder) Decode() (*model.User, int, error) {
+	req, err := d.stream.Recv()
+	if err == io.EOF {
+		return nil, d.line, io.EOF
+	}
+	if err != nil {
+		return nil, d.line, err
+	}
+	d.line++
+
+	u := &model.User{
+		FirstName: req.GetFirstName(),
+		LastName:  req.GetLastName(),
+		Email:     req.GetEmail(),
+		Age:       int(req.GetAge()),
+	}
+	// nil keeps the cars of an existing user
+	if !req.GetKeepCars() {
+		u.CarIDs = toIDs(req.GetCarIds())
+	}
+	return u, d.line, nil
+}
+
+func toUserProto(u *model.User) *goentv1.User {
+	res := &goentv1.User{
+		Id:        int64(u.ID),
+		FirstName: u.FirstName,
+		LastName:  u.LastName,
+		Email:     u.Email,
+		Age:       int32(u.Age),
+		CarIds:    fromIDs(u.CarIDs),
+		Avatar:    u.Avatar,
+	}
+	for i := range u.Cars {
+		res.Cars = append(res.Cars, toCarProto(&u.Cars[i]))
+	}
+	return res
+}
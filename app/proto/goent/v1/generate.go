@@ -0,0 +1,3 @@
+package goentv1
+
+//go:generate protoc -I ../.. --go_out=../.. --go_opt=paths=source_relative --go-grpc_out=../.. --go-grpc_opt=paths=source_relative goent/v1/car.proto goent/v1/group.proto goent/v1/user.proto
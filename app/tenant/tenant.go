@@ -0,0 +1,55 @@
+// Package tenant carries the tenant of a request in the context.
+// The ent hooks and interceptors scope the users, the cars and the groups to it, and the storage and the caches key by it.
+package tenant
+
+import (
+	"context"
+	"errors"
+	"strconv"
+)
+
+var (
+	// ErrMissing is returned when the data is read or written without a tenant in the context
+	ErrMissing = errors.New("no tenant in the context")
+	// ErrCrossTenant is returned when a write refers to an entity which the tenant does not have
+	ErrCrossTenant = errors.New("entity does not belong to the tenant")
+)
+
+type scopeKey struct{}
+
+// scope is the tenant of the context, or all of them for the background work
+type scope struct {
+	id  int
+	all bool
+}
+
+// NewContext returns the context scoped to the tenant
+func NewContext(ctx context.Context, id int) context.Context {
+	return context.WithValue(ctx, scopeKey{}, scope{id: id})
+}
+
+// AllTenants returns the context which is not scoped, for the workers which serve every tenant such as the job queue.
+// Creating an entity in it needs the tenant set explicitly
+func AllTenants(ctx context.Context) context.Context {
+	return context.WithValue(ctx, scopeKey{}, scope{all: true})
+}
+
+// FromContext returns the tenant of the context. It is false for the contexts without one and those of AllTenants
+func FromContext(ctx context.Context) (int, bool) {
+	s, ok := ctx.Value(scopeKey{}).(scope)
+	if !ok || s.all {
+		return 0, false
+	}
+	return s.id, true
+}
+
+// IsAll tells whether the context was made by AllTenants
+func IsAll(ctx context.Context) bool {
+	s, _ := ctx.Value(scopeKey{}).(scope)
+	return s.all
+}
+
+// Prefix is the prefix of the storage keys of the tenant, such as "tenant/1/"
+func Prefix(id int) string {
+	return "tenant/" + strconv.Itoa(id) + "/"
+}
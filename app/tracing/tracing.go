@@ -0,0 +1,78 @@
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.17.0"
+)
+
+// ServiceName is reported as service.name unless the config names the service
+const ServiceName = "go-ent"
+
+// Config of the tracing
+type Config struct {
+	// Exporter is "otlp" which sends the spans over OTLP/HTTP to OTEL_EXPORTER_OTLP_ENDPOINT,
+	// "stdout" which writes them to the standard output, or "none" which keeps tracing off
+	Exporter    string `yaml:"exporter" env:"OTEL_TRACES_EXPORTER"`
+	ServiceName string `yaml:"service_name" env:"OTEL_SERVICE_NAME"`
+}
+
+// Validate checks the exporter
+func (c Config) Validate() error {
+	switch c.Exporter {
+	case "", "none", "otlp", "stdout":
+		return nil
+	}
+	return fmt.Errorf("unknown exporter %q", c.Exporter)
+}
+
+// Setup installs the global tracer provider and the W3C trace context propagator.
+// The returned function flushes the spans in the queue and stops the exporter.
+func Setup(ctx context.Context, c Config) (func(ctx context.Context) error, error) {
+	// the incoming traceparent headers are honored even when no span is exported
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+
+	var exporter sdktrace.SpanExporter
+	var err error
+	switch c.Exporter {
+	case "", "none":
+		return func(ctx context.Context) error { return nil }, nil
+	case "otlp":
+		exporter, err = otlptracehttp.New(ctx)
+	case "stdout":
+		exporter, err = stdouttrace.New(stdouttrace.WithPrettyPrint())
+	default:
+		return nil, c.Validate()
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed creating trace exporter: %w", err)
+	}
+
+	provider := NewProvider(sdktrace.NewBatchSpanProcessor(exporter), c.ServiceName)
+	otel.SetTracerProvider(provider)
+	return provider.Shutdown, nil
+}
+
+// NewProvider creates a tracer provider of the service which hands the spans to the processor.
+// The parent based sampler follows the decision of the caller and samples the new traces.
+// ServiceName is used when the name is empty
+func NewProvider(processor sdktrace.SpanProcessor, name string) *sdktrace.TracerProvider {
+	if name == "" {
+		name = ServiceName
+	}
+	res, err := resource.Merge(resource.Default(), resource.NewWithAttributes(semconv.SchemaURL, semconv.ServiceName(name)))
+	if err != nil {
+		res = resource.Default()
+	}
+	return sdktrace.NewTracerProvider(
+		sdktrace.WithSpanProcessor(processor),
+		sdktrace.WithResource(res),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.AlwaysSample())),
+	)
+}
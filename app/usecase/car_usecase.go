@@ -0,0 +1,83 @@
+package usecase
+
+import (
+	"context"
+	"github.com/jpdel518/go-ent/domain/model"
+	"github.com/jpdel518/go-ent/domain/repository"
+	"time"
+)
+
+type CarUsecase interface {
+	Fetch(ctx context.Context, num int) ([]*model.Car, error)
+	GetByID(ctx context.Context, id int) (*model.Car, error)
+	Create(ctx context.Context, c *model.Car) error
+	Update(ctx context.Context, c *model.Car) error
+	Delete(ctx context.Context, id int) error
+}
+
+type carUsecase struct {
+	carRepo        repository.CarRepository
+	contextTimeout time.Duration
+}
+
+// NewCarUsecase will create new a carUsecase object
+func NewCarUsecase(c repository.CarRepository, timeout time.Duration) CarUsecase {
+	return &carUsecase{
+		carRepo:        c,
+		contextTimeout: timeout,
+	}
+}
+
+func (usecase *carUsecase) Fetch(c context.Context, num int) (_ []*model.Car, err error) {
+	c, span := tracer.Start(c, "CarUsecase.Fetch")
+	defer endSpan(span, &err)
+	ctx, cancel := context.WithTimeout(c, usecase.contextTimeout)
+	defer cancel()
+
+	return usecase.carRepo.Fetch(ctx, num)
+}
+
+func (usecase *carUsecase) GetByID(c context.Context, id int) (_ *model.Car, err error) {
+	c, span := tracer.Start(c, "CarUsecase.GetByID")
+	defer endSpan(span, &err)
+	ctx, cancel := context.WithTimeout(c, usecase.contextTimeout)
+	defer cancel()
+
+	return usecase.carRepo.GetByID(ctx, id)
+}
+
+func (usecase *carUsecase) Create(c context.Context, car *model.Car) (err error) {
+	c, span := tracer.Start(c, "CarUsecase.Create")
+	defer endSpan(span, &err)
+	ctx, cancel := context.WithTimeout(c, usecase.contextTimeout)
+	defer cancel()
+
+	if err := car.Validate(); err != nil {
+		return err
+	}
+	return usecase.carRepo.Create(ctx, car)
+}
+
+func (usecase *carUsecase) Update(c context.Context, car *model.Car) (err error) {
+	c, span := tracer.Start(c, "CarUsecase.Update")
+	defer endSpan(span, &err)
+	ctx, cancel := context.WithTimeout(c, usecase.contextTimeout)
+	defer cancel()
+
+	if err := car.Validate(); err != nil {
+		return err
+	}
+	if _, err := usecase.carRepo.GetByID(ctx, car.ID); err != nil {
+		return err
+	}
+	return usecase.carRepo.Update(ctx, car)
+}
+
+func (usecase *carUsecase) Delete(c context.Context, id int) (err error) {
+	c, span := tracer.Start(c, "CarUsecase.Delete")
+	defer endSpan(span, &err)
+	ctx, cancel := context.WithTimeout(c, usecase.contextTimeout)
+	defer cancel()
+
+	return usecase.carRepo.Delete(ctx, id)
+}
@@ -0,0 +1,31 @@
+package usecase
+
+import (
+	"context"
+	"github.com/jpdel518/go-ent/domain/model"
+	"github.com/jpdel518/go-ent/domain/repository"
+	"github.com/jpdel518/go-ent/tenant"
+)
+
+type ChangeUsecase interface {
+	// Subscribe starts a live stream of entity changes of the tenant of the context, resuming after lastID when it is not 0
+	Subscribe(ctx context.Context, filter model.ChangeFilter, lastID uint64) (*model.ChangeSubscription, error)
+}
+
+type changeUsecase struct {
+	changeFeed repository.ChangeFeed
+}
+
+// NewChangeUsecase will create new a changeUsecase object
+func NewChangeUsecase(f repository.ChangeFeed) ChangeUsecase {
+	return &changeUsecase{changeFeed: f}
+}
+
+func (usecase *changeUsecase) Subscribe(ctx context.Context, filter model.ChangeFilter, lastID uint64) (*model.ChangeSubscription, error) {
+	id, ok := tenant.FromContext(ctx)
+	if !ok {
+		return nil, tenant.ErrMissing
+	}
+	filter.TenantID = id
+	return usecase.changeFeed.Subscribe(filter, lastID), nil
+}
@@ -0,0 +1,98 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"github.com/jpdel518/go-ent/domain/event"
+	"github.com/jpdel518/go-ent/domain/repository"
+	"golang.org/x/exp/slog"
+	"strconv"
+	"time"
+)
+
+// relayBatchSize is the number of outbox events read at once
+const relayBatchSize = 100
+
+// relayRetries gives an event up as a dead letter after about half an hour
+var relayRetries = retryPolicy{maxAttempts: 15, first: time.Second, max: 5 * time.Minute}
+
+// EventRelay delivers the events written to the outbox to the sinks.
+// An event is delivered at least once and events of the same aggregate are delivered in order.
+// An event which keeps failing is given up after relayRetries, and the later events of its aggregate go on.
+type EventRelay interface {
+	Start()
+	Stop()
+}
+
+type eventRelay struct {
+	outboxRepo     repository.OutboxRepository
+	sinks          []event.Sink
+	contextTimeout time.Duration
+	*poller
+}
+
+// NewEventRelay will create new an eventRelay object
+func NewEventRelay(o repository.OutboxRepository, sinks []event.Sink, interval time.Duration, timeout time.Duration) EventRelay {
+	relay := &eventRelay{
+		outboxRepo:     o,
+		sinks:          sinks,
+		contextTimeout: timeout,
+	}
+	// the outbox of every tenant is relayed
+	relay.poller = newPoller("relaying events", interval, relayBatchSize, 1, relay.relay)
+	return relay
+}
+
+// relay delivers one batch of pending events and returns the number of delivered events
+func (relay *eventRelay) relay(c context.Context) (int, error) {
+	ctx, cancel := context.WithTimeout(c, relay.contextTimeout)
+	defer cancel()
+
+	events, err := relay.outboxRepo.FetchPending(ctx, relayBatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	// once an event of an aggregate is not delivered, the later ones have to wait for it
+	blocked := make(map[string]bool)
+	delivered := 0
+	now := time.Now()
+	for _, e := range events {
+		if ctx.Err() != nil {
+			return delivered, ctx.Err()
+		}
+		aggregate := e.AggregateType + "/" + strconv.Itoa(e.AggregateID)
+		if blocked[aggregate] {
+			continue
+		}
+
+		if err := relay.publish(ctx, e); err != nil {
+			retryAt := relayRetries.next(now, e.Attempts)
+			if retryAt != nil {
+				blocked[aggregate] = true
+				slog.WarnCtx(ctx, "failed delivering event", "event_id", e.ID, "attempt", e.Attempts+1, "err", err)
+			} else {
+				slog.ErrorCtx(ctx, "gave up delivering event", "event_id", e.ID, "event_type", e.Type, "attempts", e.Attempts+1, "err", err)
+			}
+			if err := relay.outboxRepo.MarkFailed(ctx, e.ID, err, retryAt); err != nil {
+				return delivered, err
+			}
+			continue
+		}
+		if err := relay.outboxRepo.MarkDelivered(ctx, e.ID); err != nil {
+			return delivered, err
+		}
+		delivered++
+	}
+	return delivered, nil
+}
+
+// publish sends the event to every sink. When one of them fails, the event is sent to all of them again later
+func (relay *eventRelay) publish(ctx context.Context, e *event.Event) error {
+	for _, sink := range relay.sinks {
+		if err := sink.Publish(ctx, e); err != nil {
+			return fmt.Errorf("%s sink: %w", sink.Name(), err)
+		}
+	}
+	return nil
+}
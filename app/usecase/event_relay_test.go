@@ -0,0 +1,116 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/jpdel518/go-ent/domain/event"
+	"github.com/jpdel518/go-ent/ent/enttest"
+	"github.com/jpdel518/go-ent/ent/outboxevent"
+	"github.com/jpdel518/go-ent/infrastructure/rdb"
+	"github.com/jpdel518/go-ent/infrastructure/sink"
+	"github.com/jpdel518/go-ent/tenant"
+	_ "github.com/mattn/go-sqlite3"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestEventRelay(t *testing.T) {
+	client := enttest.Open(t, "sqlite3", fmt.Sprintf("file:%s?mode=memory&cache=shared&_fk=1", t.Name()))
+	t.Cleanup(func() {
+		_ = client.Close()
+	})
+	bg := context.Background()
+	tenantID := client.Tenant.Create().SetName("Acme").SetSlug("acme").SaveX(bg).ID
+	ctx := tenant.NewContext(bg, tenantID)
+	// the relay runs for every tenant
+	all := tenant.AllTenants(bg)
+
+	s := sink.NewMemorySink()
+	relay := NewEventRelay(rdb.NewOutboxRepository(client), []event.Sink{s}, time.Second, 5*time.Second).(*eventRelay)
+
+	record := func(aggregateID int) int {
+		t.Helper()
+		return client.OutboxEvent.Create().
+			SetEventType(event.UserUpdated).
+			SetAggregateType(event.AggregateUser).
+			SetAggregateID(aggregateID).
+			SetPayload([]byte(`{}`)).
+			SaveX(ctx).ID
+	}
+	run := func(want int) {
+		t.Helper()
+		n, err := relay.relay(all)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if n != want {
+			t.Fatalf("delivered %d events, want %d", n, want)
+		}
+	}
+	published := func(want ...int) {
+		t.Helper()
+		var got []int
+		for _, e := range s.Events() {
+			got = append(got, e.ID)
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Fatalf("published %v, want %v", got, want)
+		}
+	}
+	// due makes the retry of the event due now
+	due := func(id int) {
+		t.Helper()
+		client.OutboxEvent.UpdateOneID(id).SetAvailableAt(time.Now().Add(-time.Second)).ExecX(ctx)
+	}
+
+	// a failed event waits for a retry and holds the later events of its aggregate
+	a1, a2 := record(1), record(1)
+	s.FailWith(errors.New("broker down"))
+	run(0)
+	failed := client.OutboxEvent.GetX(ctx, a1)
+	if failed.Attempts != 1 || failed.LastError != "memory sink: broker down" || !failed.AvailableAt.After(time.Now()) {
+		t.Fatalf("unexpected failed event: %+v", failed)
+	}
+	s.FailWith(nil)
+
+	// the aggregate in backoff does not fill the batch and the other aggregates go on
+	for i := 0; i < relayBatchSize; i++ {
+		record(1)
+	}
+	b1 := record(2)
+	run(1)
+	published(b1)
+
+	// after the backoff the aggregate is delivered in order
+	due(a1)
+	run(relayBatchSize)
+	run(2)
+	events := s.Events()
+	if events[1].ID != a1 || events[2].ID != a2 {
+		t.Fatalf("aggregate delivered out of order: %d, %d", events[1].ID, events[2].ID)
+	}
+	run(0)
+
+	// an event which keeps failing becomes a dead letter and the aggregate goes on without it
+	c1, c2 := record(3), record(3)
+	client.OutboxEvent.UpdateOneID(c1).SetAttempts(relayRetries.maxAttempts - 1).ExecX(ctx)
+	s.FailWith(errors.New("rejected"))
+	run(0)
+	dead := client.OutboxEvent.GetX(ctx, c1)
+	if dead.DeadAt == nil || dead.Attempts != relayRetries.maxAttempts {
+		t.Fatalf("event is not a dead letter: %+v", dead)
+	}
+	s.FailWith(nil)
+	due(c2)
+	run(1)
+	if last := s.Events()[len(s.Events())-1]; last.ID != c2 {
+		t.Fatalf("published %d, want %d", last.ID, c2)
+	}
+	// dead letters are not relayed again
+	run(0)
+	if n := client.OutboxEvent.Query().Where(outboxevent.DeadAtNotNil()).CountX(ctx); n != 1 {
+		t.Fatalf("%d dead letters, want 1", n)
+	}
+}
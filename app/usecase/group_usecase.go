@@ -0,0 +1,80 @@
+package usecase
+
+import (
+	"context"
+	"github.com/jpdel518/go-ent/domain/model"
+	"github.com/jpdel518/go-ent/domain/repository"
+	"time"
+)
+
+type GroupUsecase interface {
+	Fetch(ctx context.Context, num int) ([]*model.Group, error)
+	GetByID(ctx context.Context, id int) (*model.Group, error)
+	Create(ctx context.Context, g *model.Group) error
+	Update(ctx context.Context, g *model.Group) error
+	Delete(ctx context.Context, id int) error
+}
+
+type groupUsecase struct {
+	groupRepo      repository.GroupRepository
+	contextTimeout time.Duration
+}
+
+// NewGroupUsecase will create new a groupUsecase object
+func NewGroupUsecase(g repository.GroupRepository, timeout time.Duration) GroupUsecase {
+	return &groupUsecase{
+		groupRepo:      g,
+		contextTimeout: timeout,
+	}
+}
+
+func (usecase *groupUsecase) Fetch(c context.Context, num int) (_ []*model.Group, err error) {
+	c, span := tracer.Start(c, "GroupUsecase.Fetch")
+	defer endSpan(span, &err)
+	ctx, cancel := context.WithTimeout(c, usecase.contextTimeout)
+	defer cancel()
+
+	return usecase.groupRepo.Fetch(ctx, num)
+}
+
+func (usecase *groupUsecase) GetByID(c context.Context, id int) (_ *model.Group, err error) {
+	c, span := tracer.Start(c, "GroupUsecase.GetByID")
+	defer endSpan(span, &err)
+	ctx, cancel := context.WithTimeout(c, usecase.contextTimeout)
+	defer cancel()
+
+	return usecase.groupRepo.GetByID(ctx, id)
+}
+
+func (usecase *groupUsecase) Create(c context.Context, g *model.Group) (err error) {
+	c, span := tracer.Start(c, "GroupUsecase.Create")
+	defer endSpan(span, &err)
+	ctx, cancel := context.WithTimeout(c, usecase.contextTimeout)
+	defer cancel()
+
+	if err := g.Validate(); err != nil {
+		return err
+	}
+	return usecase.groupRepo.Create(ctx, g)
+}
+
+func (usecase *groupUsecase) Update(c context.Context, g *model.Group) (err error) {
+	c, span := tracer.Start(c, "GroupUsecase.Update")
+	defer endSpan(span, &err)
+	ctx, cancel := context.WithTimeout(c, usecase.contextTimeout)
+	defer cancel()
+
+	if err := g.Validate(); err != nil {
+		return err
+	}
+	return usecase.groupRepo.Update(ctx, g)
+}
+
+func (usecase *groupUsecase) Delete(c context.Context, id int) (err error) {
+	c, span := tracer.Start(c, "GroupUsecase.Delete")
+	defer endSpan(span, &err)
+	ctx, cancel := context.WithTimeout(c, usecase.contextTimeout)
+	defer cancel()
+
+	return usecase.groupRepo.Delete(ctx, id)
+}
@@ -0,0 +1,86 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"github.com/jpdel518/go-ent/domain/model"
+	"github.com/jpdel518/go-ent/domain/repository"
+	"golang.org/x/exp/slog"
+	"sync"
+	"time"
+)
+
+type HealthUsecase interface {
+	// Check runs all the checks at the same time, each bounded by the check timeout
+	Check(ctx context.Context) *model.HealthReport
+}
+
+type healthUsecase struct {
+	critical       []repository.HealthChecker
+	optional       []repository.HealthChecker
+	contextTimeout time.Duration
+}
+
+// NewHealthUsecase will create new a healthUsecase object.
+// A failing critical check makes the service unavailable, a failing optional check only degrades it.
+func NewHealthUsecase(critical []repository.HealthChecker, optional []repository.HealthChecker, timeout time.Duration) HealthUsecase {
+	return &healthUsecase{
+		critical:       critical,
+		optional:       optional,
+		contextTimeout: timeout,
+	}
+}
+
+func (usecase *healthUsecase) Check(c context.Context) *model.HealthReport {
+	checks := make([]*model.HealthCheck, len(usecase.critical)+len(usecase.optional))
+	var wg sync.WaitGroup
+	run := func(i int, checker repository.HealthChecker, critical bool) {
+		defer wg.Done()
+		checks[i] = usecase.check(c, checker, critical)
+	}
+	for i, checker := range usecase.critical {
+		wg.Add(1)
+		go run(i, checker, true)
+	}
+	for i, checker := range usecase.optional {
+		wg.Add(1)
+		go run(len(usecase.critical)+i, checker, false)
+	}
+	wg.Wait()
+
+	report := &model.HealthReport{Status: model.HealthStatusOK, Checks: checks}
+	for _, check := range checks {
+		if check.Status == model.HealthStatusOK {
+			continue
+		}
+		if check.Critical {
+			report.Status = model.HealthStatusUnavailable
+		} else if report.Status == model.HealthStatusOK {
+			report.Status = model.HealthStatusDegraded
+		}
+	}
+	return report
+}
+
+func (usecase *healthUsecase) check(c context.Context, checker repository.HealthChecker, critical bool) *model.HealthCheck {
+	ctx, cancel := context.WithTimeout(c, usecase.contextTimeout)
+	defer cancel()
+
+	start := time.Now()
+	err := checker.Check(ctx)
+	check := &model.HealthCheck{
+		Name:       checker.Name(),
+		Status:     model.HealthStatusOK,
+		Critical:   critical,
+		DurationMs: time.Since(start).Milliseconds(),
+	}
+	if err != nil {
+		slog.WarnCtx(ctx, "health check failed", "check", check.Name, "critical", critical, "err", err)
+		check.Status = model.HealthStatusUnavailable
+		check.Error = model.HealthCheckFailed
+		if errors.Is(err, context.DeadlineExceeded) {
+			check.Error = model.HealthCheckTimedOut
+		}
+	}
+	return check
+}
@@ -0,0 +1,139 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"github.com/jpdel518/go-ent/domain/model"
+	"github.com/jpdel518/go-ent/domain/repository"
+	"golang.org/x/exp/slog"
+	"sync"
+	"time"
+)
+
+var (
+	ErrIdempotencyKeyMismatch   = errors.New("idempotency key was used for a different request")
+	ErrIdempotencyKeyInProgress = errors.New("request with the idempotency key is in progress")
+)
+
+type IdempotencyUsecase interface {
+	// Begin reserves the key of the scope for the request.
+	// It returns the completed key to replay its response, or the pending key which the request has to complete or release.
+	// ErrIdempotencyKeyMismatch is returned when the key was used for another request and ErrIdempotencyKeyInProgress while it is served
+	Begin(ctx context.Context, scope string, key string, route string, fingerprint string) (*model.IdempotencyKey, error)
+	// Complete stores the response of the pending key to replay
+	Complete(ctx context.Context, k *model.IdempotencyKey, code int, contentType string, body []byte) error
+	// Release frees the pending key so that the request can be retried
+	Release(ctx context.Context, k *model.IdempotencyKey) error
+	// Start purges the expired keys in the background
+	Start()
+	Stop()
+}
+
+type idempotencyUsecase struct {
+	idempotencyRepo repository.IdempotencyRepository
+	ttl             time.Duration
+	purgeInterval   time.Duration
+	contextTimeout  time.Duration
+
+	stop context.CancelFunc
+	wg   sync.WaitGroup
+}
+
+// NewIdempotencyUsecase will create new an idempotencyUsecase object.
+// The keys expire after ttl from the first request and are purged every purgeInterval.
+func NewIdempotencyUsecase(i repository.IdempotencyRepository, ttl time.Duration, purgeInterval time.Duration, timeout time.Duration) IdempotencyUsecase {
+	return &idempotencyUsecase{
+		idempotencyRepo: i,
+		ttl:             ttl,
+		purgeInterval:   purgeInterval,
+		contextTimeout:  timeout,
+	}
+}
+
+func (usecase *idempotencyUsecase) Begin(c context.Context, scope string, key string, route string, fingerprint string) (_ *model.IdempotencyKey, err error) {
+	c, span := tracer.Start(c, "IdempotencyUsecase.Begin")
+	defer endSpan(span, &err)
+	ctx, cancel := context.WithTimeout(c, usecase.contextTimeout)
+	defer cancel()
+
+	k, reserved, err := usecase.idempotencyRepo.Reserve(ctx, &model.IdempotencyKey{
+		Key:         key,
+		Scope:       scope,
+		Route:       route,
+		Fingerprint: fingerprint,
+		ExpiresAt:   time.Now().Add(usecase.ttl),
+	})
+	if err != nil {
+		return nil, err
+	}
+	if reserved {
+		return k, nil
+	}
+	if k.Fingerprint != fingerprint {
+		return nil, ErrIdempotencyKeyMismatch
+	}
+	if k.Status == model.IdempotencyKeyPending {
+		return nil, ErrIdempotencyKeyInProgress
+	}
+	return k, nil
+}
+
+func (usecase *idempotencyUsecase) Complete(c context.Context, k *model.IdempotencyKey, code int, contentType string, body []byte) error {
+	ctx, cancel := context.WithTimeout(c, usecase.contextTimeout)
+	defer cancel()
+
+	return usecase.idempotencyRepo.Complete(ctx, k.ID, code, contentType, body)
+}
+
+func (usecase *idempotencyUsecase) Release(c context.Context, k *model.IdempotencyKey) error {
+	ctx, cancel := context.WithTimeout(c, usecase.contextTimeout)
+	defer cancel()
+
+	return usecase.idempotencyRepo.Delete(ctx, k.ID)
+}
+
+func (usecase *idempotencyUsecase) Start() {
+	var ctx context.Context
+	ctx, usecase.stop = context.WithCancel(context.Background())
+
+	usecase.wg.Add(1)
+	go func() {
+		defer usecase.wg.Done()
+
+		ticker := time.NewTicker(usecase.purgeInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+
+			if err := usecase.purge(ctx); err != nil && ctx.Err() == nil {
+				slog.ErrorCtx(ctx, "failed purging idempotency keys", "err", err)
+			}
+		}
+	}()
+}
+
+func (usecase *idempotencyUsecase) Stop() {
+	if usecase.stop == nil {
+		return
+	}
+	usecase.stop()
+	usecase.wg.Wait()
+}
+
+func (usecase *idempotencyUsecase) purge(c context.Context) error {
+	ctx, cancel := context.WithTimeout(c, usecase.contextTimeout)
+	defer cancel()
+
+	n, err := usecase.idempotencyRepo.DeleteExpired(ctx, time.Now())
+	if err != nil {
+		return err
+	}
+	if n > 0 {
+		slog.InfoCtx(ctx, "expired idempotency keys were purged", "count", n)
+	}
+	return nil
+}
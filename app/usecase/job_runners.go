@@ -0,0 +1,126 @@
+package usecase
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"github.com/jpdel518/go-ent/domain/model"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// runUserImport imports users from the uploaded source file.
+// Failed rows are written to an error report in the storage.
+func (usecase *jobUsecase) runUserImport(ctx context.Context, job *model.Job, progress func(percent int)) error {
+	body, err := usecase.jobFileRepo.Download(ctx, job.Params["source"])
+	if err != nil {
+		return err
+	}
+	defer body.Close()
+
+	// the progress is estimated by the bytes read so far
+	size, _ := strconv.ParseInt(job.Params["size"], 10, 64)
+	source := &countingReader{r: body}
+	dec, err := usecase.decoders(job.Params["format"], source)
+	if err != nil {
+		return err
+	}
+
+	importer := &userImporter{
+		userRepo: usecase.userRepo,
+		carRepo:  usecase.carRepo,
+		onBatch: func(report *model.ImportReport) {
+			if size > 0 {
+				progress(int(source.n * 100 / size))
+			}
+		},
+	}
+	report, err := importer.run(ctx, dec)
+	if report != nil {
+		job.Result = map[string]interface{}{
+			"created": report.Created,
+			"updated": report.Updated,
+			"failed":  report.Failed,
+		}
+		if report.Failed > 0 {
+			location, err := usecase.uploadErrorReport(ctx, job, report)
+			if err != nil {
+				return err
+			}
+			job.ErrorReport = location
+		}
+	}
+	return err
+}
+
+// uploadErrorReport writes the failed rows as NDJSON to the storage and returns its location
+func (usecase *jobUsecase) uploadErrorReport(ctx context.Context, job *model.Job, report *model.ImportReport) (string, error) {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	for _, row := range report.Rows {
+		if row.Status != model.ImportStatusFailed {
+			continue
+		}
+		if err := enc.Encode(row); err != nil {
+			return "", err
+		}
+	}
+	return usecase.jobFileRepo.Upload(ctx, "report/"+strconv.Itoa(job.ID)+"-errors.ndjson", &buf)
+}
+
+// runAvatarReprocess synchronizes the avatar of every user with the files in the storage
+func (usecase *jobUsecase) runAvatarReprocess(ctx context.Context, job *model.Job, progress func(percent int)) error {
+	total, err := usecase.userRepo.Count(ctx)
+	if err != nil {
+		return err
+	}
+
+	afterID, processed, updated := 0, 0, 0
+	for {
+		users, err := usecase.userRepo.FetchAfter(ctx, afterID, exportPageSize)
+		if err != nil {
+			return err
+		}
+		for _, u := range users {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			avatar := usecase.userFileRepo.GetURLByUser(ctx, u.ID)
+			// the url ends with the folder when the user has no avatar
+			if strings.HasSuffix(avatar, "/") {
+				avatar = ""
+			}
+			if avatar != u.Avatar {
+				if err := usecase.userRepo.UpdateAvatar(ctx, u.ID, avatar); err != nil {
+					return err
+				}
+				updated++
+			}
+			processed++
+		}
+		if total > 0 {
+			progress(processed * 100 / total)
+		}
+		job.Result = map[string]interface{}{
+			"processed": processed,
+			"updated":   updated,
+		}
+		if len(users) < exportPageSize {
+			return nil
+		}
+		afterID = users[len(users)-1].ID
+	}
+}
+
+// countingReader counts the bytes read through it
+type countingReader struct {
+	r io.Reader
+	n int64
+}
+
+func (cr *countingReader) Read(p []byte) (int, error) {
+	n, err := cr.r.Read(p)
+	cr.n += int64(n)
+	return n, err
+}
@@ -0,0 +1,281 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/jpdel518/go-ent/domain/model"
+	"github.com/jpdel518/go-ent/domain/repository"
+	"github.com/jpdel518/go-ent/metrics"
+	"github.com/jpdel518/go-ent/tenant"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+	"golang.org/x/exp/slog"
+	"io"
+	"mime/multipart"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// jobPollInterval is how often idle workers look for queued jobs
+const jobPollInterval = 5 * time.Second
+
+var (
+	ErrUnknownJobType = errors.New("unknown job type")
+	ErrJobFinished    = errors.New("job has already finished")
+)
+
+type JobUsecase interface {
+	Enqueue(ctx context.Context, jobType string, params map[string]string, f multipart.File, fh *multipart.FileHeader) (*model.Job, error)
+	GetByID(ctx context.Context, id int) (*model.Job, error)
+	Cancel(ctx context.Context, id int) (*model.Job, error)
+	// Start runs the worker pool in the background
+	Start()
+	// Stop waits for the workers to quit. Running jobs are put back to the queue
+	Stop()
+}
+
+// UserDecoderFactory returns a decoder of the given format such as "csv"
+type UserDecoderFactory func(format string, r io.Reader) (model.UserDecoder, error)
+
+// jobRunner does the work of a job and reports its progress as a percentage
+type jobRunner func(ctx context.Context, job *model.Job, progress func(percent int)) error
+
+type jobUsecase struct {
+	jobRepo        repository.JobRepository
+	jobFileRepo    repository.JobFileRepository
+	userRepo       repository.UserRepository
+	carRepo        repository.CarRepository
+	userFileRepo   repository.UserFileRepository
+	decoders       UserDecoderFactory
+	runners        map[string]jobRunner
+	workers        int
+	contextTimeout time.Duration
+
+	poller  *poller
+	mu      sync.Mutex
+	running map[int]context.CancelFunc
+}
+
+// NewJobUsecase will create new a jobUsecase object
+func NewJobUsecase(j repository.JobRepository, jf repository.JobFileRepository, u repository.UserRepository, c repository.CarRepository, f repository.UserFileRepository, decoders UserDecoderFactory, workers int, timeout time.Duration) JobUsecase {
+	usecase := &jobUsecase{
+		jobRepo:        j,
+		jobFileRepo:    jf,
+		userRepo:       u,
+		carRepo:        c,
+		userFileRepo:   f,
+		decoders:       decoders,
+		workers:        workers,
+		contextTimeout: timeout,
+		running:        make(map[int]context.CancelFunc),
+	}
+	// the workers claim the jobs of every tenant, and each job runs scoped to its own
+	usecase.poller = newPoller("claiming jobs", jobPollInterval, 1, workers, usecase.claim)
+	usecase.runners = map[string]jobRunner{
+		model.JobTypeUserImport:      usecase.runUserImport,
+		model.JobTypeAvatarReprocess: usecase.runAvatarReprocess,
+	}
+	return usecase
+}
+
+// Enqueue will register a job to be run by the workers. The user import job needs a source file
+func (usecase *jobUsecase) Enqueue(c context.Context, jobType string, params map[string]string, f multipart.File, fh *multipart.FileHeader) (_ *model.Job, err error) {
+	c, span := tracer.Start(c, "JobUsecase.Enqueue")
+	defer endSpan(span, &err)
+	ctx, cancel := context.WithTimeout(c, usecase.contextTimeout)
+	defer cancel()
+
+	if _, ok := usecase.runners[jobType]; !ok {
+		return nil, fmt.Errorf("%w: %q", ErrUnknownJobType, jobType)
+	}
+	if params == nil {
+		params = make(map[string]string)
+	}
+
+	// upload the source file
+	if jobType == model.JobTypeUserImport {
+		if f == nil || fh == nil {
+			return nil, errors.New("user import job needs a source file")
+		}
+		if params["format"] == "" {
+			params["format"] = strings.TrimPrefix(filepath.Ext(fh.Filename), ".")
+		}
+		key := "source/" + strconv.FormatInt(time.Now().UnixNano(), 10) + "-" + filepath.Base(fh.Filename)
+		if _, err := usecase.jobFileRepo.Upload(ctx, key, f); err != nil {
+			return nil, err
+		}
+		params["source"] = key
+		params["size"] = strconv.FormatInt(fh.Size, 10)
+	}
+
+	job, err := usecase.jobRepo.Create(ctx, &model.Job{Type: jobType, Params: params})
+	if err != nil {
+		return nil, err
+	}
+
+	// wake up an idle worker
+	usecase.poller.wake()
+	return job, nil
+}
+
+// GetByID will find a job by id
+func (usecase *jobUsecase) GetByID(c context.Context, id int) (_ *model.Job, err error) {
+	c, span := tracer.Start(c, "JobUsecase.GetByID")
+	defer endSpan(span, &err)
+	ctx, cancel := context.WithTimeout(c, usecase.contextTimeout)
+	defer cancel()
+
+	return usecase.jobRepo.GetByID(ctx, id)
+}
+
+// Cancel will cancel a queued or running job
+func (usecase *jobUsecase) Cancel(c context.Context, id int) (_ *model.Job, err error) {
+	c, span := tracer.Start(c, "JobUsecase.Cancel")
+	defer endSpan(span, &err)
+	ctx, cancel := context.WithTimeout(c, usecase.contextTimeout)
+	defer cancel()
+
+	canceled, err := usecase.jobRepo.Cancel(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if !canceled {
+		return nil, ErrJobFinished
+	}
+
+	// stop the job if a worker is running it
+	usecase.mu.Lock()
+	if stop, ok := usecase.running[id]; ok {
+		stop()
+	}
+	usecase.mu.Unlock()
+
+	return usecase.jobRepo.GetByID(ctx, id)
+}
+
+func (usecase *jobUsecase) Start() {
+	// jobs left running by the previous process are run again
+	ctx, cancel := context.WithTimeout(tenant.AllTenants(context.Background()), usecase.contextTimeout)
+	n, err := usecase.jobRepo.Requeue(ctx)
+	cancel()
+	if err != nil {
+		slog.ErrorCtx(ctx, "failed requeueing jobs", "err", err)
+	} else if n > 0 {
+		slog.InfoCtx(ctx, "interrupted jobs were requeued", "count", n)
+	}
+
+	usecase.poller.Start()
+	metrics.AddJobWorkers(usecase.workers)
+}
+
+func (usecase *jobUsecase) Stop() {
+	if usecase.poller.stop == nil {
+		return
+	}
+	usecase.poller.Stop()
+	metrics.AddJobWorkers(-usecase.workers)
+}
+
+// claim runs the next queued job, and reports 1 so that the worker claims another one at once, or 0 when there is none
+func (usecase *jobUsecase) claim(ctx context.Context) (int, error) {
+	job, err := usecase.jobRepo.Claim(ctx)
+	if err != nil || job == nil {
+		return 0, err
+	}
+	usecase.run(ctx, job)
+	return 1, nil
+}
+
+// run runs the job in the pool, which is done when the workers are stopped
+func (usecase *jobUsecase) run(pool context.Context, job *model.Job) {
+	ctx, cancel := context.WithCancel(tenant.NewContext(pool, job.TenantID))
+	defer cancel()
+	// a job starts its own trace as it runs apart from the request which enqueued it
+	ctx, span := tracer.Start(ctx, "JobUsecase.run", trace.WithAttributes(
+		attribute.Int("job.id", job.ID),
+		attribute.String("job.type", job.Type),
+	))
+	defer span.End()
+
+	usecase.mu.Lock()
+	usecase.running[job.ID] = cancel
+	usecase.mu.Unlock()
+	defer func() {
+		usecase.mu.Lock()
+		delete(usecase.running, job.ID)
+		usecase.mu.Unlock()
+	}()
+
+	slog.InfoCtx(ctx, "job started", "job_id", job.ID, "type", job.Type)
+	// a job interrupted by the shutdown is counted as requeued
+	status := "requeued"
+	observe := metrics.JobStarted(job.Type)
+	defer func() { observe(status) }()
+	err := usecase.runSafely(ctx, job)
+
+	// use a new context because the job context may be canceled here
+	c, done := context.WithTimeout(tenant.NewContext(context.Background(), job.TenantID), usecase.contextTimeout)
+	defer done()
+
+	// the job will be run again by the next process
+	if pool.Err() != nil {
+		if _, err := usecase.jobRepo.Requeue(c, job.ID); err != nil {
+			slog.ErrorCtx(ctx, "failed requeueing job", "job_id", job.ID, "err", err)
+		}
+		slog.InfoCtx(ctx, "job was interrupted and requeued", "job_id", job.ID)
+		return
+	}
+
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		job.Status = model.JobStatusFailed
+		job.Error = err.Error()
+	} else {
+		job.Status = model.JobStatusSucceeded
+		job.Progress = 100
+	}
+	status = string(job.Status)
+	finished, err := usecase.jobRepo.Finish(c, job)
+	if err != nil {
+		slog.ErrorCtx(ctx, "failed finishing job", "job_id", job.ID, "err", err)
+		return
+	}
+	if !finished {
+		status = string(model.JobStatusCanceled)
+		slog.InfoCtx(ctx, "job was canceled", "job_id", job.ID)
+		return
+	}
+	slog.InfoCtx(ctx, "job finished", "job_id", job.ID, "status", job.Status)
+}
+
+// runSafely runs the job and turns a panic into an error so that a worker never dies
+func (usecase *jobUsecase) runSafely(ctx context.Context, job *model.Job) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("job panicked: %v", r)
+		}
+	}()
+
+	current := 0
+	progress := func(percent int) {
+		if percent <= current || percent > 100 {
+			return
+		}
+		current = percent
+		job.Progress = percent
+		if err := usecase.jobRepo.UpdateProgress(ctx, job.ID, percent); err != nil {
+			slog.WarnCtx(ctx, "failed updating job progress", "job_id", job.ID, "err", err)
+		}
+	}
+	runner, ok := usecase.runners[job.Type]
+	if !ok {
+		return fmt.Errorf("%w: %q", ErrUnknownJobType, job.Type)
+	}
+	return runner(ctx, job, progress)
+}
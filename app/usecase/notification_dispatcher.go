@@ -0,0 +1,227 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/jpdel518/go-ent/domain/event"
+	"github.com/jpdel518/go-ent/domain/model"
+	"github.com/jpdel518/go-ent/domain/repository"
+	"github.com/jpdel518/go-ent/tenant"
+	"golang.org/x/exp/slog"
+	"net/url"
+	"time"
+)
+
+// notificationBatchSize is the number of due notifications read at once
+const notificationBatchSize = 100
+
+// NotificationDispatcher is the sink which mails the users about the events concerning them:
+// a new user is welcomed, the new owner of a car and the new member of a group are told about it,
+// and the link which verifies a new e-mail is sent to it.
+// Start runs the worker which sends the queued mails and retries them with exponential backoff.
+type NotificationDispatcher interface {
+	event.Sink
+	Start()
+	Stop()
+}
+
+type notificationDispatcher struct {
+	notificationRepo repository.NotificationRepository
+	userRepo         repository.UserRepository
+	carRepo          repository.CarRepository
+	groupRepo        repository.GroupRepository
+	renderer         repository.NotificationRenderer
+	mailSender       repository.MailSender
+	verification     VerificationConfig
+	contextTimeout   time.Duration
+	*poller
+}
+
+// NewNotificationDispatcher will create new a notificationDispatcher object
+func NewNotificationDispatcher(n repository.NotificationRepository, u repository.UserRepository, c repository.CarRepository, g repository.GroupRepository, r repository.NotificationRenderer, m repository.MailSender, v VerificationConfig, interval time.Duration, timeout time.Duration) NotificationDispatcher {
+	d := &notificationDispatcher{
+		notificationRepo: n,
+		userRepo:         u,
+		carRepo:          c,
+		groupRepo:        g,
+		renderer:         r,
+		mailSender:       m,
+		verification:     v,
+		contextTimeout:   timeout,
+	}
+	// the notifications of every tenant are sent
+	d.poller = newPoller("dispatching notifications", interval, notificationBatchSize, 1, d.dispatch)
+	return d
+}
+
+func (d *notificationDispatcher) Name() string {
+	return "notifications"
+}
+
+// Publish renders the mail of the event in the language of the user and queues it, unless the user does not want it.
+// The events about the users, the cars or the groups which have been deleted since are dropped
+func (d *notificationDispatcher) Publish(ctx context.Context, e *event.Event) error {
+	ctx = tenant.NewContext(ctx, e.TenantID)
+	kind, userID, data, err := d.subject(ctx, e)
+	if errors.Is(err, repository.ErrNotFound) {
+		slog.DebugCtx(ctx, "dropped notification of deleted data", "event_id", e.ID, "err", err)
+		return nil
+	}
+	if err != nil || kind == "" {
+		return err
+	}
+
+	user, err := d.userRepo.GetByID(ctx, userID)
+	if errors.Is(err, repository.ErrNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	preferences, err := d.notificationRepo.GetPreferences(ctx, userID)
+	if errors.Is(err, repository.ErrNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if !preferences.Wants(kind) {
+		return nil
+	}
+	data.User = user
+	mail, err := d.renderer.Render(kind, preferences.Language, data)
+	if err != nil {
+		// the same event would fail again, and hold the later events of its aggregate
+		slog.ErrorCtx(ctx, "failed rendering notification", "event_id", e.ID, "kind", kind, "err", err)
+		return nil
+	}
+	err = d.notificationRepo.Create(ctx, &model.Notification{
+		UserID:  userID,
+		EventID: e.ID,
+		Kind:    kind,
+		Mail:    *mail,
+	})
+	if err != nil {
+		return err
+	}
+
+	d.wake()
+	return nil
+}
+
+// subject tells the kind of the notification of the event, the user to notify and the data about the event.
+// The kind is empty for the events which notify nobody
+func (d *notificationDispatcher) subject(ctx context.Context, e *event.Event) (model.NotificationKind, int, *model.NotificationData, error) {
+	switch e.Type {
+	case event.UserCreated:
+		return model.NotificationWelcome, e.AggregateID, &model.NotificationData{}, nil
+	case event.CarTransferred:
+		var p event.CarTransferredPayload
+		if err := json.Unmarshal(e.Payload, &p); err != nil {
+			return "", 0, nil, err
+		}
+		// the car was taken from its owner
+		if p.ToUserID == 0 {
+			return "", 0, nil, nil
+		}
+		car, err := d.carRepo.GetByID(ctx, p.CarID)
+		if err != nil {
+			return "", 0, nil, err
+		}
+		return model.NotificationCarAssigned, p.ToUserID, &model.NotificationData{Car: car}, nil
+	case event.GroupUserAdded:
+		var p event.GroupUserAddedPayload
+		if err := json.Unmarshal(e.Payload, &p); err != nil {
+			return "", 0, nil, err
+		}
+		group, err := d.groupRepo.GetByID(ctx, p.GroupID)
+		if err != nil {
+			return "", 0, nil, err
+		}
+		return model.NotificationGroupAdded, p.UserID, &model.NotificationData{Group: group}, nil
+	case event.UserVerificationRequested:
+		var p event.UserVerificationRequestedPayload
+		if err := json.Unmarshal(e.Payload, &p); err != nil {
+			return "", 0, nil, err
+		}
+		data, err := d.verificationData(ctx, e.TenantID, e.AggregateID, p.Email)
+		if err != nil || data == nil {
+			return "", 0, nil, err
+		}
+		return model.NotificationVerification, e.AggregateID, data, nil
+	}
+	return "", 0, nil, nil
+}
+
+// verificationData signs the link which verifies the e-mail of the user, which expires the TTL after it is queued.
+// It is nil when the user has another e-mail by now or has verified it already
+func (d *notificationDispatcher) verificationData(ctx context.Context, tenantID int, userID int, email string) (*model.NotificationData, error) {
+	u, err := d.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if u.Email != email || u.VerifiedAt != nil {
+		return nil, nil
+	}
+
+	expiresAt := time.Now().Add(d.verification.TTL)
+	token, err := signVerification(&verificationClaims{
+		Tenant:    tenantID,
+		User:      userID,
+		Email:     email,
+		ExpiresAt: expiresAt.Unix(),
+	}, []byte(d.verification.Secret))
+	if err != nil {
+		return nil, err
+	}
+	link, err := url.Parse(d.verification.URL)
+	if err != nil {
+		return nil, err
+	}
+	query := link.Query()
+	query.Set("token", token)
+	link.RawQuery = query.Encode()
+	return &model.NotificationData{Link: link.String(), ExpiresAt: expiresAt}, nil
+}
+
+// dispatch sends one batch of due notifications and returns the number of them.
+// Each send has its own timeout, so that the slow ones do not leave the rest of the batch without time
+func (d *notificationDispatcher) dispatch(c context.Context) (int, error) {
+	ctx, cancel := context.WithTimeout(c, d.contextTimeout)
+	notifications, err := d.notificationRepo.FetchDue(ctx, notificationBatchSize)
+	cancel()
+	if err != nil {
+		return 0, err
+	}
+	for _, n := range notifications {
+		if c.Err() != nil {
+			break
+		}
+		d.send(c, n)
+	}
+	return len(notifications), nil
+}
+
+// send makes one attempt and records its result.
+// The result is recorded in a context of its own, as the attempt may have used up its time or been stopped after sending the mail
+func (d *notificationDispatcher) send(c context.Context, n *model.Notification) {
+	sendCtx, cancel := context.WithTimeout(c, d.contextTimeout)
+	err := d.mailSender.Send(sendCtx, &n.Mail)
+	cancel()
+
+	ctx, cancel := context.WithTimeout(tenant.AllTenants(context.Background()), d.contextTimeout)
+	defer cancel()
+	if err == nil {
+		if err := d.notificationRepo.MarkSent(ctx, n.ID); err != nil {
+			slog.ErrorCtx(ctx, "failed marking notification", "notification_id", n.ID, "err", err)
+		}
+		return
+	}
+
+	slog.WarnCtx(ctx, "failed sending notification", "notification_id", n.ID, "kind", n.Kind, "attempt", n.Attempts+1, "err", err)
+	retryAt := outboundRetries.next(time.Now(), n.Attempts)
+	if err := d.notificationRepo.MarkAttemptFailed(ctx, n.ID, err, retryAt); err != nil {
+		slog.ErrorCtx(ctx, "failed marking notification", "notification_id", n.ID, "err", err)
+	}
+}
@@ -0,0 +1,137 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"github.com/jpdel518/go-ent/domain/event"
+	"github.com/jpdel518/go-ent/domain/model"
+	"github.com/jpdel518/go-ent/ent/enttest"
+	"github.com/jpdel518/go-ent/ent/notification"
+	notificationrenderer "github.com/jpdel518/go-ent/infrastructure/notification"
+	"github.com/jpdel518/go-ent/infrastructure/rdb"
+	"github.com/jpdel518/go-ent/tenant"
+	_ "github.com/mattn/go-sqlite3"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+)
+
+// slowMailSender never finishes sending to hang, and sends the other mails at once
+type slowMailSender struct {
+	hang string
+	sent []string
+}
+
+func (s *slowMailSender) Send(ctx context.Context, m *model.Mail) error {
+	if m.To == s.hang {
+		<-ctx.Done()
+		return ctx.Err()
+	}
+	s.sent = append(s.sent, m.To)
+	return nil
+}
+
+func TestNotificationDispatcherTimeouts(t *testing.T) {
+	client := enttest.Open(t, "sqlite3", fmt.Sprintf("file:%s?mode=memory&cache=shared&_fk=1", t.Name()))
+	t.Cleanup(func() {
+		_ = client.Close()
+	})
+	bg := context.Background()
+	ctx := tenant.NewContext(bg, client.Tenant.Create().SetName("Acme").SetSlug("acme").SaveX(bg).ID)
+	userID := client.User.Create().SetFirstName("Taro").SetLastName("Yamada").SetEmail("taro@example.com").SaveX(ctx).ID
+
+	repo := rdb.NewNotificationRepository(client)
+	for i, to := range []string{"slow@example.com", "taro@example.com"} {
+		n := &model.Notification{UserID: userID, EventID: i + 1, Kind: model.NotificationWelcome, Mail: model.Mail{To: to, Subject: "Welcome", Text: "Hello"}}
+		if err := repo.Create(ctx, n); err != nil {
+			t.Fatal(err)
+		}
+	}
+	sender := &slowMailSender{hang: "slow@example.com"}
+	d := NewNotificationDispatcher(repo, nil, nil, nil, nil, sender, VerificationConfig{}, time.Second, 50*time.Millisecond).(*notificationDispatcher)
+
+	// the send which times out does not use up the time of the next one, and both results are recorded
+	if n, err := d.dispatch(tenant.AllTenants(bg)); err != nil || n != 2 {
+		t.Fatalf("dispatch = %d, %v, want 2", n, err)
+	}
+	if len(sender.sent) != 1 || sender.sent[0] != "taro@example.com" {
+		t.Errorf("sent %v, want the mail after the slow one", sender.sent)
+	}
+	slow := client.Notification.Query().Where(notification.EventID(1)).OnlyX(ctx)
+	if slow.Status != notification.StatusPending || slow.Attempts != 1 || slow.LastError == "" {
+		t.Errorf("slow notification = %+v, want the failed attempt recorded", slow)
+	}
+	sent := client.Notification.Query().Where(notification.EventID(2)).OnlyX(ctx)
+	if sent.Status != notification.StatusSent || sent.SentAt == nil {
+		t.Errorf("notification = %+v, want it sent", sent)
+	}
+}
+
+func TestNotificationDispatcherVerification(t *testing.T) {
+	client := enttest.Open(t, "sqlite3", fmt.Sprintf("file:%s?mode=memory&cache=shared&_fk=1", t.Name()))
+	t.Cleanup(func() {
+		_ = client.Close()
+	})
+	bg := context.Background()
+	tenantID := client.Tenant.Create().SetName("Acme").SetSlug("acme").SaveX(bg).ID
+	ctx := tenant.NewContext(bg, tenantID)
+	taro := client.User.Create().SetFirstName("Taro").SetLastName("Yamada").SetEmail("taro@example.com").SaveX(ctx)
+	hanako := client.User.Create().SetFirstName("Hanako").SetLastName("Sato").SetEmail("hanako@example.com").SetVerifiedAt(time.Now()).SaveX(ctx)
+
+	renderer, err := notificationrenderer.NewRenderer(model.LanguageEnglish)
+	if err != nil {
+		t.Fatal(err)
+	}
+	secret := "dispatcher-secret"
+	verification := VerificationConfig{Secret: secret, TTL: time.Hour, URL: "http://localhost:8080/user/verify?lang=en"}
+	repo := rdb.NewNotificationRepository(client)
+	d := NewNotificationDispatcher(repo, rdb.NewUserRepository(client), nil, nil, renderer, &slowMailSender{}, verification, time.Second, time.Second)
+
+	requested := func(id int, userID int, email string) *event.Event {
+		e, err := event.New(event.UserVerificationRequested, event.AggregateUser, userID, event.UserVerificationRequestedPayload{Email: email})
+		if err != nil {
+			t.Fatal(err)
+		}
+		e.ID, e.TenantID = id, tenantID
+		return e
+	}
+	for _, e := range []*event.Event{
+		requested(1, taro.ID, "taro@example.com"),
+		// the e-mail has changed since, or is verified already
+		requested(2, taro.ID, "taro@example.org"),
+		requested(3, hanako.ID, "hanako@example.com"),
+	} {
+		if err := d.Publish(bg, e); err != nil {
+			t.Fatalf("Publish %d: %v", e.ID, err)
+		}
+	}
+
+	queued := client.Notification.Query().AllX(ctx)
+	if len(queued) != 1 || queued[0].EventID != 1 || queued[0].Kind != string(model.NotificationVerification) {
+		t.Fatalf("notifications = %+v, want the verification of taro only", queued)
+	}
+	// the link is signed when the mail is queued, and verifies the e-mail of the event
+	var link *url.URL
+	for _, field := range strings.Fields(queued[0].Text) {
+		if strings.HasPrefix(field, "http://") {
+			if link, err = url.Parse(field); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	if link == nil {
+		t.Fatalf("no link in %q", queued[0].Text)
+	}
+	query := link.Query()
+	claims, err := parseVerification(query.Get("token"), []byte(secret), time.Now())
+	if err != nil {
+		t.Fatalf("token of %q: %v", queued[0].Text, err)
+	}
+	if claims.Tenant != tenantID || claims.User != taro.ID || claims.Email != "taro@example.com" {
+		t.Errorf("claims = %+v, want the e-mail of taro", claims)
+	}
+	if query.Get("lang") != "en" {
+		t.Errorf("query = %v, want the parameters of the URL kept", query)
+	}
+}
@@ -0,0 +1,51 @@
+package usecase
+
+import (
+	"context"
+	"github.com/jpdel518/go-ent/domain/model"
+	"github.com/jpdel518/go-ent/domain/repository"
+	"time"
+)
+
+type NotificationUsecase interface {
+	GetPreferences(ctx context.Context, userID int) (*model.NotificationPreferences, error)
+	UpdatePreferences(ctx context.Context, p *model.NotificationPreferences) (*model.NotificationPreferences, error)
+}
+
+type notificationUsecase struct {
+	notificationRepo repository.NotificationRepository
+	contextTimeout   time.Duration
+}
+
+// NewNotificationUsecase will create new a notificationUsecase object
+func NewNotificationUsecase(n repository.NotificationRepository, timeout time.Duration) NotificationUsecase {
+	return &notificationUsecase{
+		notificationRepo: n,
+		contextTimeout:   timeout,
+	}
+}
+
+func (usecase *notificationUsecase) GetPreferences(c context.Context, userID int) (_ *model.NotificationPreferences, err error) {
+	c, span := tracer.Start(c, "NotificationUsecase.GetPreferences")
+	defer endSpan(span, &err)
+	ctx, cancel := context.WithTimeout(c, usecase.contextTimeout)
+	defer cancel()
+
+	return usecase.notificationRepo.GetPreferences(ctx, userID)
+}
+
+// UpdatePreferences replaces the preferences of the user and returns them
+func (usecase *notificationUsecase) UpdatePreferences(c context.Context, p *model.NotificationPreferences) (_ *model.NotificationPreferences, err error) {
+	c, span := tracer.Start(c, "NotificationUsecase.UpdatePreferences")
+	defer endSpan(span, &err)
+	ctx, cancel := context.WithTimeout(c, usecase.contextTimeout)
+	defer cancel()
+
+	if err := p.Validate(); err != nil {
+		return nil, err
+	}
+	if err := usecase.notificationRepo.UpdatePreferences(ctx, p); err != nil {
+		return nil, err
+	}
+	return usecase.notificationRepo.GetPreferences(ctx, p.UserID)
+}
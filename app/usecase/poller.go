@@ -0,0 +1,121 @@
+package usecase
+
+import (
+	"context"
+	"github.com/jpdel518/go-ent/tenant"
+	"golang.org/x/exp/slog"
+	"sync"
+	"time"
+)
+
+// poller runs poll in the background of the workers which wait for the next tick or wake between the rounds.
+// A round which handled a full batch runs again at once, as more may be waiting behind it.
+// The rounds belong to every tenant, and the work of each one is scoped to its own tenant by poll
+type poller struct {
+	// name is what the workers do, such as "dispatching webhooks"
+	name      string
+	interval  time.Duration
+	batchSize int
+	workers   int
+	// poll handles one batch and returns how many it handled
+	poll func(ctx context.Context) (int, error)
+
+	notify chan struct{}
+	stop   context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+func newPoller(name string, interval time.Duration, batchSize int, workers int, poll func(ctx context.Context) (int, error)) *poller {
+	return &poller{
+		name:      name,
+		interval:  interval,
+		batchSize: batchSize,
+		workers:   workers,
+		poll:      poll,
+		notify:    make(chan struct{}, workers),
+	}
+}
+
+func (p *poller) Start() {
+	var ctx context.Context
+	ctx, p.stop = context.WithCancel(tenant.AllTenants(context.Background()))
+
+	for i := 0; i < p.workers; i++ {
+		p.wg.Add(1)
+		go p.work(ctx)
+	}
+}
+
+// Stop waits for the rounds running to end
+func (p *poller) Stop() {
+	if p.stop == nil {
+		return
+	}
+	p.stop()
+	p.wg.Wait()
+}
+
+// wake starts a round of an idle worker without waiting for the next tick
+func (p *poller) wake() {
+	select {
+	case p.notify <- struct{}{}:
+	default:
+	}
+}
+
+func (p *poller) work(ctx context.Context) {
+	defer p.wg.Done()
+
+	ticker := time.NewTicker(p.interval)
+	defer ticker.Stop()
+	for {
+		n, err := p.poll(ctx)
+		if err != nil && ctx.Err() == nil {
+			slog.ErrorCtx(ctx, "failed "+p.name, "err", err)
+		}
+		if n == p.batchSize && ctx.Err() == nil {
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-p.notify:
+		case <-ticker.C:
+		}
+	}
+}
+
+// retryPolicy tells when a failed attempt is retried. The wait doubles after each failure
+type retryPolicy struct {
+	// maxAttempts is the number of attempts before giving up
+	maxAttempts int
+	// first is the wait after the first failure
+	first time.Duration
+	// max is the longest wait
+	max time.Duration
+}
+
+// outboundRetries retries the webhooks and the mails from 16 seconds up to an hour, which gives the other side about half an hour to recover
+var outboundRetries = retryPolicy{maxAttempts: 8, first: 16 * time.Second, max: time.Hour}
+
+// next returns when the failed attempt after the earlier ones is retried, nil when it is the last one
+func (p retryPolicy) next(now time.Time, attempts int) *time.Time {
+	if attempts+1 >= p.maxAttempts {
+		return nil
+	}
+	t := now.Add(p.backoff(attempts))
+	return &t
+}
+
+// backoff returns the wait after the failed attempt following the earlier ones
+func (p retryPolicy) backoff(attempts int) time.Duration {
+	d := p.first
+	for i := 0; i < attempts && d < p.max; i++ {
+		d *= 2
+	}
+	if d > p.max {
+		return p.max
+	}
+	return d
+}
@@ -0,0 +1,94 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestPoller(t *testing.T) {
+	// the batches waiting are 3 full ones and then a partial one
+	var rounds atomic.Int64
+	polled := make(chan int64, 16)
+	p := newPoller("polling", time.Hour, 10, 1, func(ctx context.Context) (int, error) {
+		n := rounds.Add(1)
+		polled <- n
+		switch {
+		case n <= 3:
+			return 10, nil
+		case n == 4:
+			return 0, errors.New("database is down")
+		}
+		return 1, nil
+	})
+	p.Start()
+	defer p.Stop()
+
+	wait := func(want int64) {
+		t.Helper()
+		select {
+		case n := <-polled:
+			if n != want {
+				t.Fatalf("round %d, want %d", n, want)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("round %d did not run", want)
+		}
+	}
+	// the full batches run one after another without waiting for the tick
+	for i := int64(1); i <= 4; i++ {
+		wait(i)
+	}
+	// the failed round waits for the tick
+	select {
+	case n := <-polled:
+		t.Fatalf("round %d ran without a tick or wake", n)
+	case <-time.After(50 * time.Millisecond):
+	}
+	p.wake()
+	wait(5)
+
+	// no round runs once stopped
+	p.Stop()
+	p.wake()
+	select {
+	case n := <-polled:
+		t.Fatalf("round %d ran after stopping", n)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+func TestRetryPolicy(t *testing.T) {
+	now := time.Date(2020, 4, 1, 9, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name     string
+		policy   retryPolicy
+		attempts int
+		want     time.Duration
+	}{
+		{name: "first outbound retry", policy: outboundRetries, attempts: 0, want: 16 * time.Second},
+		{name: "doubled", policy: outboundRetries, attempts: 3, want: 128 * time.Second},
+		{name: "last outbound retry", policy: outboundRetries, attempts: 6, want: 1024 * time.Second},
+		{name: "outbound given up", policy: outboundRetries, attempts: 7},
+		{name: "first relay retry", policy: relayRetries, attempts: 0, want: time.Second},
+		{name: "capped", policy: relayRetries, attempts: 13, want: 5 * time.Minute},
+		{name: "relay given up", policy: relayRetries, attempts: 14},
+		{name: "capped without overflowing", policy: retryPolicy{maxAttempts: 100, first: time.Second, max: time.Hour}, attempts: 80, want: time.Hour},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.policy.next(now, tt.attempts)
+			if tt.want == 0 {
+				if got != nil {
+					t.Errorf("next = %v, want no retry", got)
+				}
+				return
+			}
+			if got == nil || got.Sub(now) != tt.want {
+				t.Errorf("next = %v, want %v later", got, tt.want)
+			}
+		})
+	}
+}
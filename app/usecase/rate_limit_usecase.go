@@ -0,0 +1,47 @@
+package usecase
+
+import (
+	"context"
+	"github.com/jpdel518/go-ent/domain/model"
+	"github.com/jpdel518/go-ent/domain/repository"
+	"time"
+)
+
+type RateLimitUsecase interface {
+	// Allow takes a token of the client for the route. It returns nil when the route is not limited
+	Allow(ctx context.Context, route string, client string) (*model.RateLimitDecision, error)
+}
+
+type rateLimitUsecase struct {
+	store          repository.RateLimitStore
+	defaultLimit   model.RateLimit
+	routes         map[string]model.RateLimit
+	contextTimeout time.Duration
+}
+
+// NewRateLimitUsecase will create new a rateLimitUsecase object.
+// Each client has a bucket for each route of the routes, and shares one bucket of the default limit among the other routes.
+func NewRateLimitUsecase(s repository.RateLimitStore, defaultLimit model.RateLimit, routes map[string]model.RateLimit, timeout time.Duration) RateLimitUsecase {
+	return &rateLimitUsecase{
+		store:          s,
+		defaultLimit:   defaultLimit,
+		routes:         routes,
+		contextTimeout: timeout,
+	}
+}
+
+func (usecase *rateLimitUsecase) Allow(c context.Context, route string, client string) (*model.RateLimitDecision, error) {
+	limit, ok := usecase.routes[route]
+	if !ok {
+		limit = usecase.defaultLimit
+		route = "*"
+	}
+	if limit.Unlimited() {
+		return nil, nil
+	}
+
+	ctx, cancel := context.WithTimeout(c, usecase.contextTimeout)
+	defer cancel()
+
+	return usecase.store.Take(ctx, "ratelimit:"+route+":"+client, limit)
+}
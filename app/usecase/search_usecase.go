@@ -0,0 +1,53 @@
+package usecase
+
+import (
+	"context"
+	"github.com/jpdel518/go-ent/domain/model"
+	"github.com/jpdel518/go-ent/domain/repository"
+	"time"
+)
+
+// defaultSearchLimit is the number of the hits returned when the query has no limit
+const defaultSearchLimit = 20
+
+type SearchUsecase interface {
+	// Search ranks the users and the cars matching the query
+	Search(ctx context.Context, q *model.SearchQuery) (*model.SearchResult, error)
+}
+
+type searchUsecase struct {
+	index          repository.SearchIndex
+	contextTimeout time.Duration
+}
+
+// NewSearchUsecase will create new a searchUsecase object
+func NewSearchUsecase(i repository.SearchIndex, timeout time.Duration) SearchUsecase {
+	return &searchUsecase{
+		index:          i,
+		contextTimeout: timeout,
+	}
+}
+
+func (usecase *searchUsecase) Search(c context.Context, q *model.SearchQuery) (_ *model.SearchResult, err error) {
+	c, span := tracer.Start(c, "SearchUsecase.Search")
+	defer endSpan(span, &err)
+	if q.Limit == 0 {
+		q.Limit = defaultSearchLimit
+	}
+	if err := q.Validate(); err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(c, usecase.contextTimeout)
+	defer cancel()
+
+	hits, err := usecase.index.Search(ctx, q)
+	if err != nil {
+		return nil, err
+	}
+	result := &model.SearchResult{Query: q.Text, Total: len(hits), Hits: hits}
+	if len(hits) > q.Limit {
+		result.Hits = hits[:q.Limit]
+	}
+	return result, nil
+}
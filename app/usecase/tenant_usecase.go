@@ -0,0 +1,224 @@
+package usecase
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/jpdel518/go-ent/domain/model"
+	"github.com/jpdel518/go-ent/domain/repository"
+	"net"
+	"strings"
+	"time"
+)
+
+var (
+	ErrUnknownTenant  = errors.New("tenant is unknown")
+	ErrInvalidAPIKey  = errors.New("API key is invalid")
+	ErrInvalidToken   = errors.New("token is invalid")
+	ErrTenantMismatch = errors.New("credentials are of different tenants")
+)
+
+type TenantUsecase interface {
+	// Resolve finds the tenant of the API key, the token and the subdomain of the host. They must agree when more than one is given,
+	// and the default tenant is used when none is. ErrUnknownTenant is returned when there is no tenant to use.
+	// The principal is the API key, or the subject of the token without one
+	Resolve(ctx context.Context, cred model.TenantCredentials) (*model.TenantAccess, error)
+	// Create registers the tenant with the API key, or without one when it is empty
+	Create(ctx context.Context, t *model.Tenant, apiKey string) error
+}
+
+// TenancyConfig tells how the tenant of a request is resolved
+type TenancyConfig struct {
+	// Default is the slug of the tenant of the requests without credentials. They are rejected when it is empty
+	Default string `yaml:"default" env:"TENANT_DEFAULT"`
+	// BaseDomain is the domain under which the subdomains name the tenants, such as "example.com" for acme.example.com.
+	// The host is not used when it is empty
+	BaseDomain string `yaml:"base_domain" env:"TENANT_BASE_DOMAIN"`
+	// JWTSecret verifies the HS256 tokens of the Authorization header. The tokens are not used when it is empty
+	JWTSecret string `yaml:"jwt_secret" env:"TENANT_JWT_SECRET" secret:"true"`
+}
+
+type tenantUsecase struct {
+	tenantRepo     repository.TenantRepository
+	config         TenancyConfig
+	contextTimeout time.Duration
+}
+
+// NewTenantUsecase will create new a tenantUsecase object
+func NewTenantUsecase(t repository.TenantRepository, c TenancyConfig, timeout time.Duration) TenantUsecase {
+	return &tenantUsecase{
+		tenantRepo:     t,
+		config:         c,
+		contextTimeout: timeout,
+	}
+}
+
+func (usecase *tenantUsecase) Resolve(c context.Context, cred model.TenantCredentials) (_ *model.TenantAccess, err error) {
+	c, span := tracer.Start(c, "TenantUsecase.Resolve")
+	defer endSpan(span, &err)
+	ctx, cancel := context.WithTimeout(c, usecase.contextTimeout)
+	defer cancel()
+
+	var resolved *model.Tenant
+	principal := ""
+	// use finds the tenant, which is unknown as invalid
+	use := func(find func() (*model.Tenant, error), invalid error) error {
+		t, err := find()
+		if errors.Is(err, repository.ErrNotFound) {
+			return invalid
+		}
+		if err != nil {
+			return err
+		}
+		if resolved != nil && resolved.ID != t.ID {
+			return ErrTenantMismatch
+		}
+		resolved = t
+		return nil
+	}
+
+	if cred.APIKey != "" {
+		err := use(func() (*model.Tenant, error) {
+			return usecase.tenantRepo.GetByAPIKey(ctx, cred.APIKey)
+		}, ErrInvalidAPIKey)
+		if err != nil {
+			return nil, err
+		}
+		principal = apiKeyPrincipal(cred.APIKey)
+	}
+	if cred.Token != "" && usecase.config.JWTSecret != "" {
+		claims, err := parseToken(cred.Token, []byte(usecase.config.JWTSecret), time.Now())
+		if err != nil {
+			return nil, err
+		}
+		err = use(func() (*model.Tenant, error) {
+			return usecase.tenantRepo.GetBySlug(ctx, claims.Tenant)
+		}, ErrInvalidToken)
+		if err != nil {
+			return nil, err
+		}
+		if principal == "" && claims.Subject != "" {
+			principal = "user:" + claims.Subject
+		}
+	}
+	if slug := subdomain(cred.Host, usecase.config.BaseDomain); slug != "" {
+		err := use(func() (*model.Tenant, error) {
+			return usecase.tenantRepo.GetBySlug(ctx, slug)
+		}, ErrUnknownTenant)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if resolved != nil {
+		return &model.TenantAccess{Tenant: resolved, Principal: principal}, nil
+	}
+
+	if usecase.config.Default == "" {
+		return nil, ErrUnknownTenant
+	}
+	err = use(func() (*model.Tenant, error) {
+		return usecase.tenantRepo.GetBySlug(ctx, usecase.config.Default)
+	}, ErrUnknownTenant)
+	if err != nil {
+		return nil, err
+	}
+	return &model.TenantAccess{Tenant: resolved}, nil
+}
+
+func (usecase *tenantUsecase) Create(c context.Context, t *model.Tenant, apiKey string) (err error) {
+	c, span := tracer.Start(c, "TenantUsecase.Create")
+	defer endSpan(span, &err)
+	ctx, cancel := context.WithTimeout(c, usecase.contextTimeout)
+	defer cancel()
+
+	if err := t.Validate(); err != nil {
+		return err
+	}
+	return usecase.tenantRepo.Create(ctx, t, apiKey)
+}
+
+// apiKeyPrincipal identifies the client of the API key without keeping the key in the logs and the stores
+func apiKeyPrincipal(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return "key:" + hex.EncodeToString(sum[:16])
+}
+
+// subdomain returns the label of the host under the base domain, or an empty string for the other hosts
+func subdomain(host string, baseDomain string) string {
+	if baseDomain == "" {
+		return ""
+	}
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+	label := strings.TrimSuffix(strings.ToLower(host), "."+strings.ToLower(baseDomain))
+	if label == strings.ToLower(host) || label == "" || strings.Contains(label, ".") {
+		return ""
+	}
+	return label
+}
+
+// tokenClaims are the claims of the tokens which are checked
+type tokenClaims struct {
+	Tenant    string `json:"tenant"`
+	Subject   string `json:"sub"`
+	ExpiresAt int64  `json:"exp"`
+	NotBefore int64  `json:"nbf"`
+}
+
+// parseToken verifies the HS256 signature and the times of the JWT and returns its claims
+func parseToken(token string, secret []byte, now time.Time) (*tokenClaims, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, fmt.Errorf("%w: malformed", ErrInvalidToken)
+	}
+	var header struct {
+		Alg string `json:"alg"`
+	}
+	if err := decodeSegment(parts[0], &header); err != nil {
+		return nil, err
+	}
+	// the algorithm is fixed, so that a token cannot choose none or another key
+	if header.Alg != "HS256" {
+		return nil, fmt.Errorf("%w: algorithm %q is not HS256", ErrInvalidToken, header.Alg)
+	}
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, fmt.Errorf("%w: malformed signature", ErrInvalidToken)
+	}
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(parts[0] + "." + parts[1]))
+	if !hmac.Equal(signature, mac.Sum(nil)) {
+		return nil, fmt.Errorf("%w: signature does not match", ErrInvalidToken)
+	}
+
+	claims := &tokenClaims{}
+	if err := decodeSegment(parts[1], claims); err != nil {
+		return nil, err
+	}
+	switch {
+	case claims.ExpiresAt != 0 && now.Unix() >= claims.ExpiresAt:
+		return nil, fmt.Errorf("%w: expired", ErrInvalidToken)
+	case claims.NotBefore != 0 && now.Unix() < claims.NotBefore:
+		return nil, fmt.Errorf("%w: not valid yet", ErrInvalidToken)
+	case claims.Tenant == "":
+		return nil, fmt.Errorf("%w: no tenant claim", ErrInvalidToken)
+	}
+	return claims, nil
+}
+
+func decodeSegment(segment string, v interface{}) error {
+	b, err := base64.RawURLEncoding.DecodeString(segment)
+	if err != nil {
+		return fmt.Errorf("%w: malformed", ErrInvalidToken)
+	}
+	if err := json.Unmarshal(b, v); err != nil {
+		return fmt.Errorf("%w: malformed", ErrInvalidToken)
+	}
+	return nil
+}
@@ -0,0 +1,23 @@
+package usecase
+
+import (
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// tracer starts a span for each usecase method as a child of the span of the request
+var tracer = otel.Tracer("github.com/jpdel518/go-ent/usecase")
+
+// endSpan ends the span of a usecase method, recording the error which the method returns.
+// It is deferred with the address of the named result:
+//
+//	c, span := tracer.Start(c, "UserUsecase.GetByID")
+//	defer endSpan(span, &err)
+func endSpan(span trace.Span, err *error) {
+	if *err != nil {
+		span.RecordError(*err)
+		span.SetStatus(codes.Error, (*err).Error())
+	}
+	span.End()
+}
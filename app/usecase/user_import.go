@@ -0,0 +1,141 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/jpdel518/go-ent/domain/model"
+	"github.com/jpdel518/go-ent/domain/repository"
+	"io"
+	"sort"
+)
+
+// importBatchSize is the number of rows written in a single transaction on import
+const importBatchSize = 100
+
+// userImporter imports users without any time limit, so callers have to bound the context
+type userImporter struct {
+	userRepo repository.UserRepository
+	carRepo  repository.CarRepository
+	// onBatch is called every time a batch has been written
+	onBatch func(report *model.ImportReport)
+}
+
+// run will create or update users read from the decoder and report the result of each row
+func (importer *userImporter) run(ctx context.Context, dec model.UserDecoder) (*model.ImportReport, error) {
+	report := &model.ImportReport{Rows: make([]*model.ImportRow, 0)}
+	emails := make(map[string]int)
+	batch := make([]*importItem, 0, importBatchSize)
+	for {
+		if err := ctx.Err(); err != nil {
+			return report, err
+		}
+
+		u, line, err := dec.Decode()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			var rowErr *model.RowError
+			if errors.As(err, &rowErr) {
+				report.Add(&model.ImportRow{Line: rowErr.Line, Status: model.ImportStatusFailed, Reason: rowErr.Err.Error()})
+				continue
+			}
+			return report, err
+		}
+
+		// validation, the e-mails are compared as they are stored
+		u.Email = model.NormalizeEmail(u.Email)
+		if err := u.Validate(); err != nil {
+			report.Add(&model.ImportRow{Line: line, Status: model.ImportStatusFailed, Email: u.Email, Reason: err.Error()})
+			continue
+		}
+		if first, ok := emails[u.Email]; ok {
+			reason := fmt.Sprintf("email is duplicated with line %d", first)
+			report.Add(&model.ImportRow{Line: line, Status: model.ImportStatusFailed, Email: u.Email, Reason: reason})
+			continue
+		}
+		emails[u.Email] = line
+
+		batch = append(batch, &importItem{line: line, user: u})
+		if len(batch) == importBatchSize {
+			importer.importBatch(ctx, batch, report)
+			batch = batch[:0]
+			if importer.onBatch != nil {
+				importer.onBatch(report)
+			}
+		}
+	}
+	if len(batch) > 0 {
+		importer.importBatch(ctx, batch, report)
+	}
+
+	sort.SliceStable(report.Rows, func(i, j int) bool {
+		return report.Rows[i].Line < report.Rows[j].Line
+	})
+	return report, nil
+}
+
+type importItem struct {
+	line int
+	user *model.User
+}
+
+// importBatch will resolve car ids of the batch and upsert users whose cars exist
+func (importer *userImporter) importBatch(ctx context.Context, batch []*importItem, report *model.ImportReport) {
+	fail := func(item *importItem, reason string) {
+		report.Add(&model.ImportRow{Line: item.line, Status: model.ImportStatusFailed, Email: item.user.Email, Reason: reason})
+	}
+
+	// resolve car ids
+	var carIDs []int
+	for _, item := range batch {
+		carIDs = append(carIDs, item.user.CarIDs...)
+	}
+	cars := make(map[int]bool)
+	if len(carIDs) > 0 {
+		res, err := importer.carRepo.FetchByIDs(ctx, carIDs)
+		if err != nil {
+			for _, item := range batch {
+				fail(item, err.Error())
+			}
+			return
+		}
+		for _, car := range res {
+			cars[car.ID] = true
+		}
+	}
+
+	items := make([]*importItem, 0, len(batch))
+	users := make([]*model.User, 0, len(batch))
+Rows:
+	for _, item := range batch {
+		for _, carID := range item.user.CarIDs {
+			if !cars[carID] {
+				fail(item, fmt.Sprintf("car %d does not exist", carID))
+				continue Rows
+			}
+		}
+		items = append(items, item)
+		users = append(users, item.user)
+	}
+	if len(users) == 0 {
+		return
+	}
+
+	// create or update users
+	created, err := importer.userRepo.Upsert(ctx, users)
+	if err != nil {
+		for _, item := range items {
+			fail(item, err.Error())
+		}
+		return
+	}
+	for i, item := range items {
+		status := model.ImportStatusUpdated
+		if created[i] {
+			status = model.ImportStatusCreated
+		}
+		report.Add(&model.ImportRow{Line: item.line, Status: status, ID: item.user.ID, Email: item.user.Email})
+	}
+}
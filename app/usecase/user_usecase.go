@@ -2,81 +2,116 @@ package usecase
 
 import (
 	"context"
+	"fmt"
 	"github.com/jpdel518/go-ent/domain/model"
 	"github.com/jpdel518/go-ent/domain/repository"
-	"log"
+	"golang.org/x/exp/slog"
+	"golang.org/x/sync/errgroup"
 	"mime/multipart"
-	"sync"
 	"time"
 )
 
 type UserUsecase interface {
-	Fetch(ctx context.Context, num int) ([]*model.User, error)
-	GetByID(ctx context.Context, id int) (*model.User, error)
+	Fetch(ctx context.Context, afterID int, num int, details model.CarDetails) ([]*model.User, error)
+	GetByID(ctx context.Context, id int, details model.CarDetails) (*model.User, error)
 	Create(ctx context.Context, u *model.User, f multipart.File, fh *multipart.FileHeader) error
 	Update(ctx context.Context, u *model.User, f multipart.File, fh *multipart.FileHeader) error
 	Delete(ctx context.Context, id int) error
+	TransferCar(ctx context.Context, carID int, userID int) (*model.User, error)
+	Import(ctx context.Context, dec model.UserDecoder) (*model.ImportReport, error)
+	Export(ctx context.Context, num int, enc model.UserEncoder) error
+	// Verify marks the e-mail of the token as verified. ErrInvalidVerification is returned when the token is expired or the e-mail has changed
+	Verify(ctx context.Context, token string) (*model.User, error)
+	// ResendVerification queues the mail of the link to verify the e-mail again. ErrAlreadyVerified is returned when it is verified
+	ResendVerification(ctx context.Context, id int) error
 }
 
+// exportPageSize is the number of users read from the database at once on export
+const exportPageSize = 100
+
+// carDetailsLimit is the number of cars fetched at once for a request by default
+const carDetailsLimit = 8
+
 type userUsecase struct {
 	userRepo       repository.UserRepository
 	carRepo        repository.CarRepository
 	userFileRepo   repository.UserFileRepository
+	verification   VerificationConfig
 	contextTimeout time.Duration
+	// carDetailsLimit bounds the cars fetched at once by getCarDetails
+	carDetailsLimit int
 }
 
 // NewUserUsecase will create new an userUsecase object
-func NewUserUsecase(u repository.UserRepository, c repository.CarRepository, f repository.UserFileRepository, timeout time.Duration) UserUsecase {
+func NewUserUsecase(u repository.UserRepository, c repository.CarRepository, f repository.UserFileRepository, v VerificationConfig, timeout time.Duration) UserUsecase {
 	return &userUsecase{
-		userRepo:       u,
-		carRepo:        c,
-		userFileRepo:   f,
-		contextTimeout: timeout,
+		userRepo:        u,
+		carRepo:         c,
+		userFileRepo:    f,
+		verification:    v,
+		contextTimeout:  timeout,
+		carDetailsLimit: carDetailsLimit,
 	}
 }
 
-// fillCarDetails will fill up car details that is concerned with a parameter of user object
-func (usecase *userUsecase) getCarDetails(c context.Context, user *model.User) ([]*model.Car, error) {
-	// TODO errgroupを使ってエラーをハンドリング + contextで後続の処理をキャンセルするようにした方がいいかも
-	var wg sync.WaitGroup
-	// Get the car's id
-	var cars []*model.Car
-
-	// Using goroutine to fetch the car's detail
-	chanCar := make(chan *model.Car)
-
-	for carID := range user.Cars {
-		wg.Add(1)
-		go func(carID int) {
-			defer wg.Done()
-			res, err := usecase.carRepo.GetByID(c, carID)
-			chanCar <- res
-			if err != nil {
-				// TODO エラーをハンドリング
-				log.Printf("fillCarDetails get car ID: %d error: %v", carID, err)
+// getCarDetails will fill up the cars of the users by their ids, fetching at most carDetailsLimit of them at once.
+// The first error cancels the other fetches unless the details are partial, which leaves out the cars failed instead
+func (usecase *userUsecase) getCarDetails(c context.Context, users []*model.User, details model.CarDetails) (err error) {
+	c, span := tracer.Start(c, "UserUsecase.getCarDetails")
+	defer endSpan(span, &err)
+
+	// the users may share a car only while it changes hands, fetch it once anyway
+	index := make(map[int]int)
+	ids := make([]int, 0)
+	for _, u := range users {
+		for _, id := range u.CarIDs {
+			if _, ok := index[id]; !ok {
+				index[id] = len(ids)
+				ids = append(ids, id)
 			}
-		}(carID)
+		}
 	}
 
-	// release channel
-	go func() {
-		wg.Wait()
-		close(chanCar)
-	}()
+	// each fetch writes its own element, so the results need no lock
+	cars := make([]*model.Car, len(ids))
+	g, ctx := errgroup.WithContext(c)
+	g.SetLimit(usecase.carDetailsLimit)
+	for i, id := range ids {
+		i, id := i, id
+		g.Go(func() error {
+			car, err := usecase.carRepo.GetByID(ctx, id)
+			if err != nil {
+				// a cancelled request has no partial result
+				if details == model.CarDetailsPartial && ctx.Err() == nil {
+					slog.WarnCtx(ctx, "left out car details", "car_id", id, "err", err)
+					return nil
+				}
+				return fmt.Errorf("failed getting car %d: %w", id, err)
+			}
+			cars[i] = car
+			return nil
+		})
+	}
+	if err := g.Wait(); err != nil {
+		return err
+	}
 
-	// retrieve
-	for car := range chanCar {
-		if car != nil {
-			cars = append(cars, car)
+	for _, u := range users {
+		u.Cars = make([]model.Car, 0, len(u.CarIDs))
+		for _, id := range u.CarIDs {
+			if car := cars[index[id]]; car != nil {
+				u.Cars = append(u.Cars, *car)
+			}
 		}
 	}
-	wg.Wait()
-
-	return cars, nil
+	return nil
 }
 
-// Fetch will retrieve user
-func (usecase *userUsecase) Fetch(c context.Context, num int) ([]*model.User, error) {
+// Fetch will retrieve the users after afterID in the order of the ids, with the details of their cars unless they are CarDetailsNone.
+// The id of the last user is the afterID of the next page
+func (usecase *userUsecase) Fetch(c context.Context, afterID int, num int, details model.CarDetails) (_ []*model.User, err error) {
+	c, span := tracer.Start(c, "UserUsecase.Fetch")
+	defer endSpan(span, &err)
 	if num == 0 {
 		num = 10
 	}
@@ -84,16 +119,24 @@ func (usecase *userUsecase) Fetch(c context.Context, num int) ([]*model.User, er
 	ctx, cancel := context.WithTimeout(c, usecase.contextTimeout)
 	defer cancel()
 
-	res, err := usecase.userRepo.Fetch(ctx, num)
+	res, err := usecase.userRepo.FetchAfter(ctx, afterID, num)
 	if err != nil {
 		return nil, err
 	}
 
+	if details != model.CarDetailsNone {
+		if err := usecase.getCarDetails(ctx, res, details); err != nil {
+			return nil, err
+		}
+	}
+
 	return res, nil
 }
 
-// GetByID will find a user by id
-func (usecase *userUsecase) GetByID(c context.Context, id int) (*model.User, error) {
+// GetByID will find a user by id, with the details of their cars unless they are CarDetailsNone
+func (usecase *userUsecase) GetByID(c context.Context, id int, details model.CarDetails) (_ *model.User, err error) {
+	c, span := tracer.Start(c, "UserUsecase.GetByID")
+	defer endSpan(span, &err)
 	ctx, cancel := context.WithTimeout(c, usecase.contextTimeout)
 	defer cancel()
 
@@ -102,32 +145,29 @@ func (usecase *userUsecase) GetByID(c context.Context, id int) (*model.User, err
 		return &model.User{}, err
 	}
 
-	// cars, err := res.QueryCars().All(ctx)
-	// if err != nil {
-	// 	log.Printf("failed querying user cars: %w", err)
-	// }
-	// // Query the inverse edge.
-	// for _, c := range cars {
-	// 	owner, err := c.QueryOwner().Only(ctx)
-	// 	if err != nil {
-	// 		log.Printf("failed querying car %q owner: %w", c.Model, err)
-	// 	}
-	// 	log.Printf("car %q owner: %q\n", c.Model, owner.Email)
-	// }
+	if details != model.CarDetailsNone {
+		if err := usecase.getCarDetails(ctx, []*model.User{res}, details); err != nil {
+			return &model.User{}, err
+		}
+	}
 
 	return res, nil
 }
 
-// Create will register a user
-func (usecase *userUsecase) Create(c context.Context, u *model.User, f multipart.File, fh *multipart.FileHeader) error {
+// Create will register a user, whose repository queues the mail of the link to verify the e-mail
+func (usecase *userUsecase) Create(c context.Context, u *model.User, f multipart.File, fh *multipart.FileHeader) (err error) {
+	c, span := tracer.Start(c, "UserUsecase.Create")
+	defer endSpan(span, &err)
 	ctx, cancel := context.WithTimeout(c, usecase.contextTimeout)
 	defer cancel()
 
 	// create user
+	u.Email = model.NormalizeEmail(u.Email)
 	user, err := usecase.userRepo.Create(ctx, u)
 	if err != nil {
 		return err
 	}
+	u.ID = user.ID
 
 	// upload user avatar file
 	if f != nil && fh != nil {
@@ -138,16 +178,25 @@ func (usecase *userUsecase) Create(c context.Context, u *model.User, f multipart
 		user.Avatar = filename
 
 		// update user
-		_, err = usecase.userRepo.Update(ctx, user)
+		if _, err := usecase.userRepo.Update(ctx, user); err != nil {
+			return err
+		}
 	}
-	return err
+	return nil
 }
 
-// Update will update a user
-func (usecase *userUsecase) Update(c context.Context, u *model.User, f multipart.File, fh *multipart.FileHeader) error {
+// Update will update a user, whose repository queues the mail of the link to verify the e-mail when it changes
+func (usecase *userUsecase) Update(c context.Context, u *model.User, f multipart.File, fh *multipart.FileHeader) (err error) {
+	c, span := tracer.Start(c, "UserUsecase.Update")
+	defer endSpan(span, &err)
 	ctx, cancel := context.WithTimeout(c, usecase.contextTimeout)
 	defer cancel()
 
+	if _, err := usecase.userRepo.GetByID(ctx, u.ID); err != nil {
+		return err
+	}
+	u.Email = model.NormalizeEmail(u.Email)
+
 	if f != nil && fh != nil {
 		filename, err := usecase.userFileRepo.Update(ctx, u.ID, f, fh)
 		if err != nil {
@@ -156,14 +205,99 @@ func (usecase *userUsecase) Update(c context.Context, u *model.User, f multipart
 		u.Avatar = filename
 	}
 
-	_, err := usecase.userRepo.Update(ctx, u)
-	return err
+	if _, err := usecase.userRepo.Update(ctx, u); err != nil {
+		return err
+	}
+	return nil
 }
 
 // Delete will delete a user by id
-func (usecase *userUsecase) Delete(c context.Context, id int) error {
+func (usecase *userUsecase) Delete(c context.Context, id int) (err error) {
+	c, span := tracer.Start(c, "UserUsecase.Delete")
+	defer endSpan(span, &err)
 	ctx, cancel := context.WithTimeout(c, usecase.contextTimeout)
 	defer cancel()
 
 	return usecase.userRepo.Delete(ctx, id)
 }
+
+// TransferCar will give the car to the user, taking it from its owner
+func (usecase *userUsecase) TransferCar(c context.Context, carID int, userID int) (_ *model.User, err error) {
+	c, span := tracer.Start(c, "UserUsecase.TransferCar")
+	defer endSpan(span, &err)
+	ctx, cancel := context.WithTimeout(c, usecase.contextTimeout)
+	defer cancel()
+
+	if _, err := usecase.carRepo.GetByID(ctx, carID); err != nil {
+		return nil, err
+	}
+	u, err := usecase.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	owned := false
+	for _, id := range u.CarIDs {
+		owned = owned || id == carID
+	}
+	if !owned {
+		// replacing the cars of the user takes the car from its owner
+		u.CarIDs = append(u.CarIDs, carID)
+		if u, err = usecase.userRepo.Update(ctx, u); err != nil {
+			return nil, err
+		}
+	}
+
+	// the new owner is returned with the details of the cars as GetByID does
+	if err := usecase.getCarDetails(ctx, []*model.User{u}, model.CarDetailsAll); err != nil {
+		return nil, err
+	}
+	return u, nil
+}
+
+// Import will create or update users read from the decoder and report the result of each row
+func (usecase *userUsecase) Import(c context.Context, dec model.UserDecoder) (_ *model.ImportReport, err error) {
+	c, span := tracer.Start(c, "UserUsecase.Import")
+	defer endSpan(span, &err)
+	ctx, cancel := context.WithTimeout(c, usecase.contextTimeout)
+	defer cancel()
+
+	importer := &userImporter{userRepo: usecase.userRepo, carRepo: usecase.carRepo}
+	return importer.run(ctx, dec)
+}
+
+// Export will write users with the details of their cars to the encoder page by page. All users are written when num is 0
+func (usecase *userUsecase) Export(c context.Context, num int, enc model.UserEncoder) (err error) {
+	c, span := tracer.Start(c, "UserUsecase.Export")
+	defer endSpan(span, &err)
+	ctx, cancel := context.WithTimeout(c, usecase.contextTimeout)
+	defer cancel()
+
+	afterID, written := 0, 0
+	for num == 0 || written < num {
+		size := exportPageSize
+		if num > 0 && num-written < size {
+			size = num - written
+		}
+		users, err := usecase.userRepo.FetchAfter(ctx, afterID, size)
+		if err != nil {
+			return err
+		}
+		if err := usecase.getCarDetails(ctx, users, model.CarDetailsAll); err != nil {
+			return err
+		}
+		for _, u := range users {
+			if err := enc.Encode(u); err != nil {
+				return err
+			}
+		}
+		if err := enc.Flush(); err != nil {
+			return err
+		}
+		if len(users) < size {
+			break
+		}
+		afterID = users[len(users)-1].ID
+		written += len(users)
+	}
+	return nil
+}
@@ -0,0 +1,279 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/jpdel518/go-ent/domain/model"
+	"github.com/jpdel518/go-ent/domain/repository"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// mockUserRepository returns the users given, copied as a repository would
+type mockUserRepository struct {
+	repository.UserRepository
+	users []*model.User
+}
+
+func (r *mockUserRepository) FetchAfter(ctx context.Context, afterID int, num int) ([]*model.User, error) {
+	res := make([]*model.User, 0, len(r.users))
+	for _, u := range r.users {
+		if len(res) == num {
+			break
+		}
+		if u.ID <= afterID {
+			continue
+		}
+		c := *u
+		res = append(res, &c)
+	}
+	return res, nil
+}
+
+func (r *mockUserRepository) GetByID(ctx context.Context, id int) (*model.User, error) {
+	for _, u := range r.users {
+		if u.ID == id {
+			c := *u
+			return &c, nil
+		}
+	}
+	return nil, repository.ErrNotFound
+}
+
+// mockCarRepository answers GetByID with getByID and counts the calls
+type mockCarRepository struct {
+	repository.CarRepository
+	getByID func(ctx context.Context, id int) (*model.Car, error)
+	mu      sync.Mutex
+	calls   map[int]int
+}
+
+func (r *mockCarRepository) GetByID(ctx context.Context, id int) (*model.Car, error) {
+	r.mu.Lock()
+	if r.calls == nil {
+		r.calls = make(map[int]int)
+	}
+	r.calls[id]++
+	r.mu.Unlock()
+	return r.getByID(ctx, id)
+}
+
+// carsByID finds the cars of the ids and fails for the others
+func carsByID(ids ...int) func(ctx context.Context, id int) (*model.Car, error) {
+	return func(ctx context.Context, id int) (*model.Car, error) {
+		for _, i := range ids {
+			if i == id {
+				return &model.Car{ID: id, Name: fmt.Sprintf("car %d", id)}, nil
+			}
+		}
+		return nil, fmt.Errorf("car %d: %w", id, repository.ErrNotFound)
+	}
+}
+
+func newTestUserUsecase(users []*model.User, cars *mockCarRepository, limit int) *userUsecase {
+	return &userUsecase{
+		userRepo:        &mockUserRepository{users: users},
+		carRepo:         cars,
+		contextTimeout:  time.Second,
+		carDetailsLimit: limit,
+	}
+}
+
+func carIDsOf(u *model.User) []int {
+	if u.Cars == nil {
+		return nil
+	}
+	ids := make([]int, 0, len(u.Cars))
+	for _, c := range u.Cars {
+		ids = append(ids, c.ID)
+	}
+	return ids
+}
+
+func TestUserUsecaseGetByIDCarDetails(t *testing.T) {
+	taro := &model.User{ID: 1, FirstName: "Taro", CarIDs: []int{3, 1, 2}}
+	tests := []struct {
+		name    string
+		details model.CarDetails
+		cars    []int
+		want    []int
+		wantErr error
+	}{
+		{name: "none leaves the ids only", details: model.CarDetailsNone, cars: []int{1, 2, 3}, want: nil},
+		{name: "all in the order of the ids", details: model.CarDetailsAll, cars: []int{1, 2, 3}, want: []int{3, 1, 2}},
+		{name: "all by default", cars: []int{1, 2, 3}, want: []int{3, 1, 2}},
+		{name: "all fails for a missing car", details: model.CarDetailsAll, cars: []int{1, 3}, wantErr: repository.ErrNotFound},
+		{name: "partial leaves out a missing car", details: model.CarDetailsPartial, cars: []int{1, 3}, want: []int{3, 1}},
+		{name: "partial without any car", details: model.CarDetailsPartial, cars: nil, want: []int{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cars := &mockCarRepository{getByID: carsByID(tt.cars...)}
+			got, err := newTestUserUsecase([]*model.User{taro}, cars, 2).GetByID(context.Background(), taro.ID, tt.details)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("GetByID error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr != nil {
+				return
+			}
+			if fmt.Sprint(carIDsOf(got)) != fmt.Sprint(tt.want) || (tt.want == nil) != (got.Cars == nil) {
+				t.Errorf("cars = %v, want %v", carIDsOf(got), tt.want)
+			}
+			if fmt.Sprint(got.CarIDs) != "[3 1 2]" {
+				t.Errorf("car ids = %v, want them as they were", got.CarIDs)
+			}
+			if tt.details == model.CarDetailsNone && len(cars.calls) != 0 {
+				t.Errorf("fetched %d cars without the details", len(cars.calls))
+			}
+		})
+	}
+}
+
+func TestUserUsecaseFetchCarDetails(t *testing.T) {
+	users := []*model.User{
+		{ID: 1, CarIDs: []int{1, 2}},
+		{ID: 2, CarIDs: []int{}},
+		// a car changing hands may be listed twice
+		{ID: 3, CarIDs: []int{2, 3, 4}},
+	}
+	tests := []struct {
+		name    string
+		details model.CarDetails
+		cars    []int
+		want    string
+		wantErr error
+	}{
+		{name: "all", details: model.CarDetailsAll, cars: []int{1, 2, 3, 4}, want: "[[1 2] [] [2 3 4]]"},
+		{name: "all fails for a missing car", details: model.CarDetailsAll, cars: []int{1, 2, 4}, wantErr: repository.ErrNotFound},
+		{name: "partial", details: model.CarDetailsPartial, cars: []int{1, 2, 4}, want: "[[1 2] [] [2 4]]"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cars := &mockCarRepository{getByID: carsByID(tt.cars...)}
+			got, err := newTestUserUsecase(users, cars, 2).Fetch(context.Background(), 0, 10, tt.details)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Fetch error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr != nil {
+				return
+			}
+			ids := make([][]int, 0, len(got))
+			for _, u := range got {
+				ids = append(ids, carIDsOf(u))
+			}
+			if fmt.Sprint(ids) != tt.want {
+				t.Errorf("cars = %v, want %s", ids, tt.want)
+			}
+			for id, n := range cars.calls {
+				if n != 1 {
+					t.Errorf("car %d fetched %d times, want once", id, n)
+				}
+			}
+		})
+	}
+}
+
+func TestUserUsecaseCarDetailsLimit(t *testing.T) {
+	tests := []struct {
+		name  string
+		limit int
+		cars  int
+	}{
+		{name: "fewer cars than the limit", limit: 4, cars: 3},
+		{name: "more cars than the limit", limit: 2, cars: 10},
+		{name: "one at a time", limit: 1, cars: 5},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			u := &model.User{ID: 1}
+			for id := 1; id <= tt.cars; id++ {
+				u.CarIDs = append(u.CarIDs, id)
+			}
+			var running, peak atomic.Int32
+			cars := &mockCarRepository{getByID: func(ctx context.Context, id int) (*model.Car, error) {
+				n := running.Add(1)
+				defer running.Add(-1)
+				for {
+					p := peak.Load()
+					if n <= p || peak.CompareAndSwap(p, n) {
+						break
+					}
+				}
+				time.Sleep(5 * time.Millisecond)
+				return &model.Car{ID: id}, nil
+			}}
+			got, err := newTestUserUsecase([]*model.User{u}, cars, tt.limit).GetByID(context.Background(), 1, model.CarDetailsAll)
+			if err != nil {
+				t.Fatalf("GetByID: %v", err)
+			}
+			if len(got.Cars) != tt.cars {
+				t.Errorf("%d cars, want %d", len(got.Cars), tt.cars)
+			}
+			if p := int(peak.Load()); p > tt.limit {
+				t.Errorf("%d cars fetched at once, want at most %d", p, tt.limit)
+			}
+		})
+	}
+}
+
+func TestUserUsecaseCarDetailsCancellation(t *testing.T) {
+	failure := errors.New("connection refused")
+	tests := []struct {
+		name    string
+		details model.CarDetails
+		// cancel cancels the request while the cars are fetched instead of a failing car
+		cancel  bool
+		wantErr error
+	}{
+		{name: "the first error cancels the others", details: model.CarDetailsAll, wantErr: failure},
+		{name: "partial waits for the others", details: model.CarDetailsPartial},
+		{name: "a cancelled request fails", details: model.CarDetailsAll, cancel: true, wantErr: context.Canceled},
+		{name: "a cancelled request fails even partial", details: model.CarDetailsPartial, cancel: true, wantErr: context.Canceled},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			u := &model.User{ID: 1, CarIDs: []int{1, 2, 3}}
+			var started sync.WaitGroup
+			started.Add(2)
+			var cancelled atomic.Int32
+			cars := &mockCarRepository{getByID: func(c context.Context, id int) (*model.Car, error) {
+				if id == 1 {
+					// fail once the others are waiting
+					started.Wait()
+					if tt.cancel {
+						cancel()
+						<-c.Done()
+						return nil, c.Err()
+					}
+					return nil, failure
+				}
+				started.Done()
+				select {
+				case <-c.Done():
+					cancelled.Add(1)
+					return nil, c.Err()
+				case <-time.After(50 * time.Millisecond):
+					return &model.Car{ID: id}, nil
+				}
+			}}
+			got, err := newTestUserUsecase([]*model.User{u}, cars, 3).GetByID(ctx, 1, tt.details)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("GetByID error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr != nil {
+				if cancelled.Load() != 2 {
+					t.Errorf("%d fetches cancelled, want 2", cancelled.Load())
+				}
+				return
+			}
+			if fmt.Sprint(carIDsOf(got)) != "[2 3]" {
+				t.Errorf("cars = %v, want [2 3]", carIDsOf(got))
+			}
+		})
+	}
+}
@@ -0,0 +1,132 @@
+package usecase
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/jpdel518/go-ent/domain/model"
+	"github.com/jpdel518/go-ent/domain/repository"
+	"github.com/jpdel518/go-ent/tenant"
+	"strings"
+	"time"
+)
+
+var (
+	ErrInvalidVerification = errors.New("verification token is invalid")
+	ErrAlreadyVerified     = errors.New("e-mail is already verified")
+)
+
+// VerificationConfig of the links which verify the e-mails of the users
+type VerificationConfig struct {
+	// Secret signs the tokens of the links
+	Secret string `yaml:"secret" env:"VERIFICATION_SECRET" secret:"true" required:"true"`
+	// TTL is how long a link can be opened
+	TTL time.Duration `yaml:"ttl" env:"VERIFICATION_TTL" required:"true"`
+	// URL is the page which the link opens with the token in the "token" parameter, such as the /user/verify route
+	URL string `yaml:"url" env:"VERIFICATION_URL" required:"true"`
+}
+
+// verificationClaims name the user and the e-mail which the token verifies
+type verificationClaims struct {
+	Tenant    int    `json:"tenant"`
+	User      int    `json:"user"`
+	Email     string `json:"email"`
+	ExpiresAt int64  `json:"exp"`
+}
+
+// Verify will mark the e-mail of the token as verified. The token names the tenant, so the context needs none
+func (usecase *userUsecase) Verify(c context.Context, token string) (_ *model.User, err error) {
+	c, span := tracer.Start(c, "UserUsecase.Verify")
+	defer endSpan(span, &err)
+	ctx, cancel := context.WithTimeout(c, usecase.contextTimeout)
+	defer cancel()
+
+	claims, err := parseVerification(token, []byte(usecase.verification.Secret), time.Now())
+	if err != nil {
+		return nil, err
+	}
+	ctx = tenant.NewContext(ctx, claims.Tenant)
+
+	u, err := usecase.userRepo.GetByID(ctx, claims.User)
+	if errors.Is(err, repository.ErrNotFound) {
+		return nil, fmt.Errorf("%w: user does not exist", ErrInvalidVerification)
+	}
+	if err != nil {
+		return nil, err
+	}
+	// opening the link again keeps the time of the first one
+	if u.VerifiedAt != nil && u.Email == claims.Email {
+		return u, nil
+	}
+	err = usecase.userRepo.Verify(ctx, claims.User, claims.Email, time.Now())
+	if errors.Is(err, repository.ErrNotFound) {
+		return nil, fmt.Errorf("%w: e-mail has changed", ErrInvalidVerification)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return usecase.userRepo.GetByID(ctx, claims.User)
+}
+
+// ResendVerification will queue the mail of the link to verify the e-mail of the user again, unless it is verified
+func (usecase *userUsecase) ResendVerification(c context.Context, id int) (err error) {
+	c, span := tracer.Start(c, "UserUsecase.ResendVerification")
+	defer endSpan(span, &err)
+	ctx, cancel := context.WithTimeout(c, usecase.contextTimeout)
+	defer cancel()
+
+	u, err := usecase.userRepo.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if u.VerifiedAt != nil {
+		return ErrAlreadyVerified
+	}
+	return usecase.userRepo.RequestVerification(ctx, id)
+}
+
+// signVerification encodes the claims and their HMAC-SHA256 as "{claims}.{signature}" in base64url
+func signVerification(claims *verificationClaims, secret []byte) (string, error) {
+	b, err := json.Marshal(claims)
+	if err != nil {
+		return "", err
+	}
+	payload := base64.RawURLEncoding.EncodeToString(b)
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(payload))
+	return payload + "." + base64.RawURLEncoding.EncodeToString(mac.Sum(nil)), nil
+}
+
+// parseVerification verifies the signature and the expiry of the token and returns its claims
+func parseVerification(token string, secret []byte, now time.Time) (*verificationClaims, error) {
+	payload, sig, ok := strings.Cut(token, ".")
+	if !ok {
+		return nil, fmt.Errorf("%w: malformed", ErrInvalidVerification)
+	}
+	signature, err := base64.RawURLEncoding.DecodeString(sig)
+	if err != nil {
+		return nil, fmt.Errorf("%w: malformed signature", ErrInvalidVerification)
+	}
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(payload))
+	if !hmac.Equal(signature, mac.Sum(nil)) {
+		return nil, fmt.Errorf("%w: signature does not match", ErrInvalidVerification)
+	}
+
+	b, err := base64.RawURLEncoding.DecodeString(payload)
+	if err != nil {
+		return nil, fmt.Errorf("%w: malformed", ErrInvalidVerification)
+	}
+	claims := &verificationClaims{}
+	if err := json.Unmarshal(b, claims); err != nil {
+		return nil, fmt.Errorf("%w: malformed", ErrInvalidVerification)
+	}
+	if now.Unix() >= claims.ExpiresAt {
+		return nil, fmt.Errorf("%w: expired", ErrInvalidVerification)
+	}
+	return claims, nil
+}
@@ -0,0 +1,155 @@
+package usecase
+
+import (
+	"context"
+	"github.com/jpdel518/go-ent/domain/event"
+	"github.com/jpdel518/go-ent/domain/model"
+	"github.com/jpdel518/go-ent/domain/repository"
+	"github.com/jpdel518/go-ent/tenant"
+	"golang.org/x/exp/slog"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	// webhookBatchSize is the number of due deliveries read at once
+	webhookBatchSize = 100
+	// webhookDisableThreshold is the number of failures in a row which disables a subscription
+	webhookDisableThreshold = 20
+)
+
+// WebhookDispatcher is the sink which fans the events out to the webhook subscriptions.
+// Start runs the worker which sends the deliveries and retries them with exponential backoff.
+type WebhookDispatcher interface {
+	event.Sink
+	Start()
+	Stop()
+}
+
+type webhookDispatcher struct {
+	webhookRepo    repository.WebhookRepository
+	sender         repository.WebhookSender
+	contextTimeout time.Duration
+	*poller
+}
+
+// NewWebhookDispatcher will create new a webhookDispatcher object
+func NewWebhookDispatcher(w repository.WebhookRepository, s repository.WebhookSender, interval time.Duration, timeout time.Duration) WebhookDispatcher {
+	d := &webhookDispatcher{
+		webhookRepo:    w,
+		sender:         s,
+		contextTimeout: timeout,
+	}
+	// the deliveries of every tenant are sent
+	d.poller = newPoller("dispatching webhooks", interval, webhookBatchSize, 1, d.dispatch)
+	return d
+}
+
+func (d *webhookDispatcher) Name() string {
+	return "webhook_subscriptions"
+}
+
+// Publish queues the event for every active subscription of its tenant which wants it
+func (d *webhookDispatcher) Publish(ctx context.Context, e *event.Event) error {
+	ctx = tenant.NewContext(ctx, e.TenantID)
+	subscriptions, err := d.webhookRepo.FetchActive(ctx)
+	if err != nil {
+		return err
+	}
+	ids := make([]int, 0, len(subscriptions))
+	for _, s := range subscriptions {
+		if s.Subscribes(e.Type) {
+			ids = append(ids, s.ID)
+		}
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+	if err := d.webhookRepo.CreateDeliveries(ctx, e, ids); err != nil {
+		return err
+	}
+
+	d.wake()
+	return nil
+}
+
+// dispatch sends one batch of due deliveries and returns the number of them which were attempted.
+// The deliveries after a failure of their endpoint are left for the next round and not counted
+func (d *webhookDispatcher) dispatch(c context.Context) (int, error) {
+	ctx, cancel := context.WithTimeout(c, d.contextTimeout)
+	defer cancel()
+
+	deliveries, err := d.webhookRepo.FetchDueDeliveries(ctx, webhookBatchSize)
+	if err != nil {
+		return 0, err
+	}
+	if len(deliveries) == 0 {
+		return 0, nil
+	}
+	subscriptions, err := d.webhookRepo.FetchActive(ctx)
+	if err != nil {
+		return 0, err
+	}
+	active := make(map[int]*model.WebhookSubscription, len(subscriptions))
+	for _, s := range subscriptions {
+		active[s.ID] = s
+	}
+
+	// endpoints are called in parallel, and one by one in event order within an endpoint
+	queues := make(map[int][]*model.WebhookDelivery)
+	for _, delivery := range deliveries {
+		if _, ok := active[delivery.SubscriptionID]; !ok {
+			continue
+		}
+		queues[delivery.SubscriptionID] = append(queues[delivery.SubscriptionID], delivery)
+	}
+	var attempted atomic.Int64
+	var wg sync.WaitGroup
+	for id, queue := range queues {
+		wg.Add(1)
+		go func(s *model.WebhookSubscription, queue []*model.WebhookDelivery) {
+			defer wg.Done()
+			for _, delivery := range queue {
+				if ctx.Err() != nil {
+					return
+				}
+				attempted.Add(1)
+				// an endpoint which fails is left alone until the next round
+				if ok := d.deliver(ctx, s, delivery); !ok {
+					return
+				}
+			}
+		}(active[id], queue)
+	}
+	wg.Wait()
+	return int(attempted.Load()), nil
+}
+
+// deliver makes one attempt, records its result and reports whether it succeeded
+func (d *webhookDispatcher) deliver(ctx context.Context, s *model.WebhookSubscription, delivery *model.WebhookDelivery) bool {
+	code, err := d.sender.Send(ctx, s.URL, s.Secret, delivery)
+	if err == nil {
+		if err := d.webhookRepo.MarkDelivered(ctx, delivery.ID, code); err != nil {
+			slog.ErrorCtx(ctx, "failed marking webhook delivery", "delivery_id", delivery.ID, "err", err)
+		}
+		if _, err := d.webhookRepo.RecordResult(ctx, s.ID, true, webhookDisableThreshold); err != nil {
+			slog.ErrorCtx(ctx, "failed recording webhook result", "subscription_id", s.ID, "err", err)
+		}
+		return true
+	}
+
+	slog.WarnCtx(ctx, "failed delivering webhook", "delivery_id", delivery.ID, "url", s.URL, "attempt", delivery.Attempts+1, "err", err)
+	retryAt := outboundRetries.next(time.Now(), delivery.Attempts)
+	if err := d.webhookRepo.MarkAttemptFailed(ctx, delivery.ID, code, err, retryAt); err != nil {
+		slog.ErrorCtx(ctx, "failed marking webhook delivery", "delivery_id", delivery.ID, "err", err)
+	}
+	disabled, err := d.webhookRepo.RecordResult(ctx, s.ID, false, webhookDisableThreshold)
+	if err != nil {
+		slog.ErrorCtx(ctx, "failed recording webhook result", "subscription_id", s.ID, "err", err)
+	}
+	if disabled {
+		slog.WarnCtx(ctx, "webhook subscription was disabled", "subscription_id", s.ID, "failures", webhookDisableThreshold)
+	}
+	return false
+}
@@ -0,0 +1,164 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"github.com/jpdel518/go-ent/domain/event"
+	"github.com/jpdel518/go-ent/domain/model"
+	"github.com/jpdel518/go-ent/ent"
+	"github.com/jpdel518/go-ent/ent/enttest"
+	"github.com/jpdel518/go-ent/ent/webhookdelivery"
+	"github.com/jpdel518/go-ent/infrastructure/rdb"
+	"github.com/jpdel518/go-ent/infrastructure/webhook"
+	"github.com/jpdel518/go-ent/tenant"
+	_ "github.com/mattn/go-sqlite3"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// receiver is a webhook endpoint which checks the signatures and answers with status
+type receiver struct {
+	*httptest.Server
+	status   atomic.Int64
+	mu       sync.Mutex
+	received []string
+	forged   int
+}
+
+func newReceiver(t *testing.T, secret string) *receiver {
+	r := &receiver{}
+	r.status.Store(http.StatusOK)
+	r.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		body, _ := io.ReadAll(req.Body)
+		r.mu.Lock()
+		if !webhook.Verify(secret, req.Header.Get(webhook.TimestampHeader), body, req.Header.Get(webhook.SignatureHeader)) {
+			r.forged++
+		}
+		r.received = append(r.received, req.Header.Get(webhook.DeliveryHeader))
+		r.mu.Unlock()
+		w.WriteHeader(int(r.status.Load()))
+	}))
+	t.Cleanup(r.Close)
+	return r
+}
+
+func TestWebhookDispatcher(t *testing.T) {
+	client := enttest.Open(t, "sqlite3", fmt.Sprintf("file:%s?mode=memory&cache=shared&_fk=1", t.Name()))
+	t.Cleanup(func() {
+		_ = client.Close()
+	})
+	bg := context.Background()
+	tenantID := client.Tenant.Create().SetName("Acme").SetSlug("acme").SaveX(bg).ID
+	ctx := tenant.NewContext(bg, tenantID)
+	// the worker runs for every tenant
+	all := tenant.AllTenants(bg)
+
+	const secret = "0123456789abcdef"
+	r := newReceiver(t, secret)
+	repo := rdb.NewWebhookRepository(client)
+	s, err := repo.Create(ctx, &model.WebhookSubscription{URL: r.URL, Secret: secret, Active: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	d := NewWebhookDispatcher(repo, webhook.NewSender(r.Client()), time.Second, 5*time.Second).(*webhookDispatcher)
+
+	publish := func(id int) {
+		t.Helper()
+		e, err := event.New(event.UserCreated, event.AggregateUser, id, &model.User{ID: id})
+		if err != nil {
+			t.Fatal(err)
+		}
+		e.ID, e.TenantID = id, tenantID
+		if err := d.Publish(bg, e); err != nil {
+			t.Fatal(err)
+		}
+	}
+	dispatch := func(want int) {
+		t.Helper()
+		// the retries are due at once
+		client.WebhookDelivery.Update().
+			Where(webhookdelivery.StatusEQ(webhookdelivery.StatusPending)).
+			SetNextAttemptAt(time.Now().Add(-time.Second)).
+			ExecX(all)
+		if n, err := d.dispatch(all); err != nil || n != want {
+			t.Fatalf("dispatch = %d, %v, want %d attempted", n, err, want)
+		}
+	}
+	delivery := func(eventID int) *ent.WebhookDelivery {
+		t.Helper()
+		return client.WebhookDelivery.Query().Where(webhookdelivery.EventID(eventID)).OnlyX(all)
+	}
+
+	// the relay may publish an event again, which is delivered once
+	publish(1)
+	publish(1)
+
+	// a failure is retried after 16 seconds, then after 32
+	r.status.Store(http.StatusInternalServerError)
+	for attempt, wait := range []time.Duration{16 * time.Second, 32 * time.Second} {
+		start := time.Now()
+		dispatch(1)
+		got := delivery(1)
+		if got.Attempts != attempt+1 || got.ResponseCode != http.StatusInternalServerError || got.Status != webhookdelivery.StatusPending {
+			t.Errorf("delivery after attempt %d = %+v", attempt+1, got)
+		}
+		if retry := got.NextAttemptAt.Sub(start); retry < wait || retry > wait+2*time.Second {
+			t.Errorf("retry after attempt %d in %v, want %v", attempt+1, retry, wait)
+		}
+		// nothing is due before the backoff
+		if n, err := d.dispatch(all); err != nil || n != 0 {
+			t.Errorf("dispatch during the backoff = %d, %v, want none", n, err)
+		}
+	}
+
+	r.status.Store(http.StatusOK)
+	dispatch(1)
+	if got := delivery(1); got.Status != webhookdelivery.StatusSucceeded || got.ResponseCode != http.StatusOK || got.Attempts != 3 {
+		t.Errorf("delivery after success = %+v", got)
+	}
+	if got := client.WebhookSubscription.GetX(ctx, s.ID); got.FailureCount != 0 {
+		t.Errorf("failures after success = %d, want 0", got.FailureCount)
+	}
+
+	// the endpoint which keeps failing is disabled, and its queue is given up
+	r.status.Store(http.StatusServiceUnavailable)
+	for i := 0; i < webhookDisableThreshold; i++ {
+		publish(10 + i)
+		dispatch(1)
+	}
+	if got := client.WebhookSubscription.GetX(ctx, s.ID); got.Active || got.DisabledAt == nil || got.FailureCount != webhookDisableThreshold {
+		t.Errorf("subscription after %d failures = %+v, want disabled", webhookDisableThreshold, got)
+	}
+	if n := client.WebhookDelivery.Query().Where(webhookdelivery.StatusEQ(webhookdelivery.StatusPending)).CountX(all); n != 0 {
+		t.Errorf("pending deliveries of the disabled subscription = %d, want 0", n)
+	}
+
+	// enabled again, it is sent the new events only, and deactivating it gives them up
+	s.Active = true
+	if _, err := repo.Update(ctx, s); err != nil {
+		t.Fatal(err)
+	}
+	publish(100)
+	s.Active = false
+	if _, err := repo.Update(ctx, s); err != nil {
+		t.Fatal(err)
+	}
+	if got := delivery(100); got.Status != webhookdelivery.StatusFailed {
+		t.Errorf("delivery of the deactivated subscription = %+v, want failed", got)
+	}
+	dispatch(0)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if want := 3 + webhookDisableThreshold; len(r.received) != want || r.forged != 0 {
+		t.Errorf("received %d with %d bad signatures, want %d signed", len(r.received), r.forged, want)
+	}
+	if r.received[0] != "1" {
+		t.Errorf("first delivery header = %q, want the event id", r.received[0])
+	}
+}
@@ -0,0 +1,138 @@
+package usecase
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"github.com/jpdel518/go-ent/domain/model"
+	"github.com/jpdel518/go-ent/domain/repository"
+	"time"
+)
+
+type WebhookUsecase interface {
+	Fetch(ctx context.Context, num int) ([]*model.WebhookSubscription, error)
+	GetByID(ctx context.Context, id int) (*model.WebhookSubscription, error)
+	// Create returns the subscription with its secret. The secret is not shown again
+	Create(ctx context.Context, w *model.WebhookSubscription) (*model.WebhookSubscription, error)
+	Update(ctx context.Context, w *model.WebhookSubscription) (*model.WebhookSubscription, error)
+	Delete(ctx context.Context, id int) error
+	FetchDeliveries(ctx context.Context, id int, num int) ([]*model.WebhookDelivery, error)
+}
+
+type webhookUsecase struct {
+	webhookRepo    repository.WebhookRepository
+	contextTimeout time.Duration
+}
+
+// NewWebhookUsecase will create new a webhookUsecase object
+func NewWebhookUsecase(w repository.WebhookRepository, timeout time.Duration) WebhookUsecase {
+	return &webhookUsecase{
+		webhookRepo:    w,
+		contextTimeout: timeout,
+	}
+}
+
+func (usecase *webhookUsecase) Fetch(c context.Context, num int) (_ []*model.WebhookSubscription, err error) {
+	c, span := tracer.Start(c, "WebhookUsecase.Fetch")
+	defer endSpan(span, &err)
+	ctx, cancel := context.WithTimeout(c, usecase.contextTimeout)
+	defer cancel()
+
+	subscriptions, err := usecase.webhookRepo.Fetch(ctx, num)
+	if err != nil {
+		return nil, err
+	}
+	for _, s := range subscriptions {
+		s.Secret = ""
+	}
+	return subscriptions, nil
+}
+
+func (usecase *webhookUsecase) GetByID(c context.Context, id int) (_ *model.WebhookSubscription, err error) {
+	c, span := tracer.Start(c, "WebhookUsecase.GetByID")
+	defer endSpan(span, &err)
+	ctx, cancel := context.WithTimeout(c, usecase.contextTimeout)
+	defer cancel()
+
+	subscription, err := usecase.webhookRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	subscription.Secret = ""
+	return subscription, nil
+}
+
+func (usecase *webhookUsecase) Create(c context.Context, w *model.WebhookSubscription) (_ *model.WebhookSubscription, err error) {
+	c, span := tracer.Start(c, "WebhookUsecase.Create")
+	defer endSpan(span, &err)
+	ctx, cancel := context.WithTimeout(c, usecase.contextTimeout)
+	defer cancel()
+
+	if w.Secret == "" {
+		secret, err := newWebhookSecret()
+		if err != nil {
+			return nil, err
+		}
+		w.Secret = secret
+	}
+	if w.EventTypes == nil {
+		w.EventTypes = make([]string, 0)
+	}
+	w.Active = true
+	if err := w.Validate(); err != nil {
+		return nil, err
+	}
+
+	return usecase.webhookRepo.Create(ctx, w)
+}
+
+func (usecase *webhookUsecase) Update(c context.Context, w *model.WebhookSubscription) (_ *model.WebhookSubscription, err error) {
+	c, span := tracer.Start(c, "WebhookUsecase.Update")
+	defer endSpan(span, &err)
+	ctx, cancel := context.WithTimeout(c, usecase.contextTimeout)
+	defer cancel()
+
+	if w.EventTypes == nil {
+		w.EventTypes = make([]string, 0)
+	}
+	if err := w.Validate(); err != nil {
+		return nil, err
+	}
+
+	subscription, err := usecase.webhookRepo.Update(ctx, w)
+	if err != nil {
+		return nil, err
+	}
+	subscription.Secret = ""
+	return subscription, nil
+}
+
+func (usecase *webhookUsecase) Delete(c context.Context, id int) (err error) {
+	c, span := tracer.Start(c, "WebhookUsecase.Delete")
+	defer endSpan(span, &err)
+	ctx, cancel := context.WithTimeout(c, usecase.contextTimeout)
+	defer cancel()
+
+	return usecase.webhookRepo.Delete(ctx, id)
+}
+
+func (usecase *webhookUsecase) FetchDeliveries(c context.Context, id int, num int) (_ []*model.WebhookDelivery, err error) {
+	c, span := tracer.Start(c, "WebhookUsecase.FetchDeliveries")
+	defer endSpan(span, &err)
+	ctx, cancel := context.WithTimeout(c, usecase.contextTimeout)
+	defer cancel()
+
+	if _, err := usecase.webhookRepo.GetByID(ctx, id); err != nil {
+		return nil, err
+	}
+	return usecase.webhookRepo.FetchDeliveries(ctx, id, num)
+}
+
+// newWebhookSecret returns a random secret for signing deliveries
+func newWebhookSecret() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
@@ -1,13 +1,16 @@
 package utils
 
 import (
+	"errors"
 	"github.com/joho/godotenv"
+	"io/fs"
 	"log"
 )
 
+// LoadEnv sets the variables of .env which are not set yet. The file is optional
 func LoadEnv() {
 	err := godotenv.Load(".env")
-	if err != nil {
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
 		log.Fatalf("failed to load env: %v", err)
 	}
 }